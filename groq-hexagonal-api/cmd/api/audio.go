@@ -0,0 +1,40 @@
+//go:build !noaudio
+
+package main
+
+import (
+	"fmt"
+
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/config"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/egress"
+	"groq-hexagonal-api/internal/infrastructure/groq"
+	"groq-hexagonal-api/internal/infrastructure/mock"
+)
+
+// newTranscriptionService crea la transcripción de audio: usa los modelos
+// Whisper de Groq (primera API key) o el proveedor falso en MOCK_MODE
+// Retorna nil sin Groq: POST /api/v1/audio/transcriptions no se registra
+// Los binarios compilados con -tags noaudio no la incluyen (ver audio_disabled.go)
+func newTranscriptionService(cfg *config.Config, mockClient *mock.MockClient, settings domain.SettingsSource, allowlist *egress.Allowlist) domain.TranscriptionService {
+	if mockClient != nil {
+		fmt.Println("   ✓ Servicio de transcripción inicializado (mock)")
+		return application.NewTranscriptionService(mockClient, cfg.TranscriptionModel)
+	}
+	if cfg.GroqAPIKey == "" {
+		return nil
+	}
+
+	fmt.Printf("   ✓ Servicio de transcripción inicializado (%s)\n", cfg.TranscriptionModel)
+	return application.NewTranscriptionService(
+		groq.NewTranscriptionClient(
+			cfg.GroqAPIKey,
+			cfg.GroqBaseURL,
+			cfg.HTTPTimeout,
+			groq.WithRuntimeSettings(settings),
+			groq.WithTransportWrapper(allowlist.Wrap),
+		),
+		cfg.TranscriptionModel,
+	)
+}
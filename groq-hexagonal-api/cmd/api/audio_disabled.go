@@ -0,0 +1,19 @@
+//go:build noaudio
+
+package main
+
+import (
+	"fmt"
+
+	"groq-hexagonal-api/internal/config"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/egress"
+	"groq-hexagonal-api/internal/infrastructure/mock"
+)
+
+// newTranscriptionService no crea nada: el binario se compiló con -tags
+// noaudio (ver audio.go), así que no hay POST /api/v1/audio/transcriptions
+func newTranscriptionService(cfg *config.Config, mockClient *mock.MockClient, settings domain.SettingsSource, allowlist *egress.Allowlist) domain.TranscriptionService {
+	fmt.Println("   • Transcripción de audio: no incluida en el binario (-tags noaudio)")
+	return nil
+}
@@ -6,15 +6,41 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"groq-hexagonal-api/internal/application"
 	"groq-hexagonal-api/internal/config"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/alerts"
+	"groq-hexagonal-api/internal/infrastructure/cassette"
+	"groq-hexagonal-api/internal/infrastructure/disk"
+	"groq-hexagonal-api/internal/infrastructure/egress"
 	"groq-hexagonal-api/internal/infrastructure/groq"
+	grpcInfra "groq-hexagonal-api/internal/infrastructure/grpc"
+	"groq-hexagonal-api/internal/infrastructure/jsoncodec"
+	"groq-hexagonal-api/internal/infrastructure/jwt"
+	"groq-hexagonal-api/internal/infrastructure/language"
+	"groq-hexagonal-api/internal/infrastructure/liveness"
+	"groq-hexagonal-api/internal/infrastructure/logging"
+	"groq-hexagonal-api/internal/infrastructure/memlimit"
+	"groq-hexagonal-api/internal/infrastructure/memory"
+	"groq-hexagonal-api/internal/infrastructure/mock"
+	natsInfra "groq-hexagonal-api/internal/infrastructure/nats"
+	"groq-hexagonal-api/internal/infrastructure/ollama"
+	"groq-hexagonal-api/internal/infrastructure/openai"
+	"groq-hexagonal-api/internal/infrastructure/pii"
+	"groq-hexagonal-api/internal/infrastructure/redis"
+	"groq-hexagonal-api/internal/infrastructure/sqlcheck"
+	"groq-hexagonal-api/internal/infrastructure/templates"
+	"groq-hexagonal-api/internal/infrastructure/usage"
+	"groq-hexagonal-api/internal/infrastructure/wasm"
 	httpInfra "groq-hexagonal-api/internal/infrastructure/http"
 )
 
@@ -44,6 +70,9 @@ func main() {
 	// Imprimir configuración (sin info sensible)
 	cfg.Print()
 	
+	// Límite de memoria y GC: cuanto antes, para que rijan desde el arranque
+	memoryLimit := memlimit.Apply(int64(cfg.MemoryLimitMB)<<20, cfg.GCPercent)
+	
 	// ========================================================================
 	// 3. INICIALIZAR DEPENDENCIAS (Dependency Injection)
 	// ========================================================================
@@ -60,29 +89,584 @@ func main() {
 	
 	fmt.Println("🔌 Inicializando dependencias...")
 	
-	// CAPA DE INFRAESTRUCTURA - Adaptador Groq (puerto secundario)
-	// Este es el adaptador que se comunica con la API externa de Groq
-	groqClient := groq.NewGroqClient(
-		cfg.GroqAPIKey,
-		cfg.GroqBaseURL,
-		cfg.HTTPTimeout,
-	)
-	fmt.Println("   ✓ Cliente Groq inicializado")
+	// Lista de salida (opcional): los clientes HTTP solo se conectan a los
+	// proveedores y webhooks configurados y a EGRESS_ALLOWED_HOSTS
+	// Una lista nil no restringe nada (Wrap retorna el transporte tal cual)
+	var egressAllowlist *egress.Allowlist
+	if cfg.EgressRestrict {
+		egressAllowlist = egress.NewAllowlist(cfg.EgressAllowlist())
+		fmt.Printf("   ✓ Lista de salida activada: %s\n", strings.Join(egressAllowlist.Entries(), ", "))
+	}
+	
+	// Ajustes recargables en caliente (SIGHUP o POST /admin/config/reload):
+	// los clientes, los servicios y el rate limit los leen en cada petición
+	settings := application.NewSettingsHolder(cfg.RuntimeSettings(), func(ctx context.Context) (domain.RuntimeSettings, error) {
+		reloaded, err := config.Reload()
+		if err != nil {
+			return domain.RuntimeSettings{}, err
+		}
+		return reloaded.RuntimeSettings(), nil
+	})
+	go watchReloadSignal(settings)
+	
+	// Salud de los modelos: mide las llamadas de cada proveedor, abre el
+	// circuito de los modelos caídos y recoge las cabeceras de rate limit
+	healthMonitor := application.NewModelHealthMonitor(application.ModelHealthOptions{
+		Window:           cfg.ModelHealthWindow,
+		FailureThreshold: cfg.CircuitBreakerFailures,
+		Cooldown:         cfg.CircuitBreakerCooldown,
+	})
+	
+	// CAPA DE INFRAESTRUCTURA - Adaptadores de los proveedores (puerto secundario)
+	// Un adaptador por proveedor configurado, detrás de un ProviderRouter que
+	// elige el de cada petición (campo "provider" o LLM_PROVIDER)
+	providers := make(map[string]domain.LLMRepository)
+	
+	// Modo mock: un proveedor falso en lugar de los reales (sin red ni API key)
+	var mockClient *mock.MockClient
+	if cfg.MockMode {
+		mockClient = mock.NewMockClient(cfg.DefaultModel)
+		providers[cfg.LLMProvider] = mockClient
+		fmt.Println("   ✓ Proveedor falso inicializado (MOCK_MODE)")
+	}
+	
+	// Reproducción: las llamadas grabadas sustituyen a los proveedores, cada
+	// uno con su subdirectorio (el mismo en el que se grabó)
+	if cfg.Replaying() {
+		for name := range cfg.EnabledProviders() {
+			providers[name] = cassette.NewReplayer(filepath.Join(cfg.CassetteDir, name))
+		}
+		fmt.Printf("   ✓ Reproduciendo las llamadas grabadas en %s\n", cfg.CassetteDir)
+	}
+	realProviders := !cfg.MockMode && !cfg.Replaying()
+	
+	// Estado de cada API key de Groq (solo con varias, para /admin/groq/keys)
+	var groqKeysHealth domain.BackendHealthService
+	if cfg.GroqAPIKey != "" && realProviders {
+		// Con varias API keys, un cliente por clave detrás de un StickyRouter:
+		// una key con 429 se enfría y la petición pasa a la siguiente
+		var groqBackends []application.StickyBackend
+		for i, apiKey := range cfg.GroqAPIKeys() {
+			groqBackends = append(groqBackends, application.StickyBackend{
+				// El nombre es la posición: el hash no depende de la clave
+				Name: fmt.Sprintf("groq-%d", i+1),
+				Repository: groq.NewGroqClient(
+					apiKey,
+					cfg.GroqBaseURL,
+					cfg.HTTPTimeout,
+					groq.WithRateLimitObserver(healthMonitor.RateLimitObserver(domain.ProviderGroq)),
+					groq.WithRuntimeSettings(settings),
+					groq.WithTransportWrapper(egressAllowlist.Wrap),
+				),
+			})
+		}
+		groqRouter := application.NewStickyRouter(groqBackends, application.WithBackendCooldown(cfg.GroqKeyCooldown))
+		groqKeysHealth, _ = groqRouter.(domain.BackendHealthService)
+		providers[domain.ProviderGroq] = groqRouter
+		fmt.Printf("   ✓ Cliente Groq inicializado (%d API keys)\n", len(groqBackends))
+	}
+	if cfg.OpenAIAPIKey != "" && realProviders {
+		providers[domain.ProviderOpenAI] = openai.NewOpenAIClient(
+			cfg.OpenAIAPIKey,
+			cfg.OpenAIBaseURL,
+			cfg.HTTPTimeout,
+			groq.WithRateLimitObserver(healthMonitor.RateLimitObserver(domain.ProviderOpenAI)),
+			groq.WithRuntimeSettings(settings),
+			groq.WithTransportWrapper(egressAllowlist.Wrap),
+		)
+		fmt.Println("   ✓ Cliente OpenAI inicializado")
+	}
+	if cfg.OllamaBaseURL != "" && realProviders {
+		providers[domain.ProviderOllama] = ollama.NewOllamaClient(
+			cfg.OllamaBaseURL,
+			cfg.HTTPTimeout,
+			ollama.WithRuntimeSettings(settings),
+			ollama.WithTransportWrapper(egressAllowlist.Wrap),
+		)
+		fmt.Println("   ✓ Cliente Ollama inicializado")
+	}
+	for name, repo := range providers {
+		// Grabación: cada proveedor real guarda sus llamadas en su subdirectorio
+		if cfg.CassetteMode == "record" {
+			recorder, err := cassette.NewRecorder(repo, filepath.Join(cfg.CassetteDir, name))
+			if err != nil {
+				log.Fatalf("❌ Error al preparar la grabación: %v", err)
+			}
+			repo = recorder
+		}
+		providers[name] = healthMonitor.Wrap(name, repo)
+	}
+	if cfg.CassetteMode == "record" {
+		fmt.Printf("   ✓ Grabando las llamadas a los proveedores en %s\n", cfg.CassetteDir)
+	}
+	llmClient := application.NewProviderRouter(providers, cfg.LLMProvider)
+	
+	// Anonimización (opcional): los datos personales no salen hacia el proveedor
+	// (ni hacia el de embeddings: ver newSemanticCache)
+	var redacting *application.RedactingRepository
+	if cfg.PIIRedaction {
+		redacting = application.NewRedactingRepository(
+			llmClient,
+			pii.NewRegexDetector(),
+			alerts.NewLogRedactionAuditor(),
+			cfg.PIIRedactionTypes,
+		)
+		llmClient = redacting
+		fmt.Println("   ✓ Anonimización de datos personales activada")
+	}
+	
+	// Moderación con Llama Guard (opcional): las llamadas de moderación usan el
+	// cliente sin moderar (ya anonimizado, si la anonimización está activada)
+	if cfg.ModerationEnabled() {
+		llmClient = application.NewModeratingRepository(llmClient, llmClient, application.ModerationPolicy{
+			Model:      cfg.ModerationModel,
+			Provider:   cfg.ModerationProvider,
+			Input:      cfg.ModerationInput,
+			Output:     cfg.ModerationOutput,
+			Categories: cfg.ModerationCategories,
+			FailOpen:   cfg.ModerationFailOpen,
+		})
+		fmt.Println("   ✓ Moderación de contenido activada")
+	}
+	
+	// Política de idioma: el detector es otro adaptador (puerto secundario)
+	localePolicy := application.LocalePolicy{}
+	if cfg.LanguageDetection {
+		localePolicy.Detector = language.NewStopwordDetector()
+		localePolicy.Profiles = make(map[string]application.LocaleProfile)
+		for lang, profile := range cfg.LocaleProfiles {
+			localePolicy.Profiles[lang] = application.LocaleProfile{
+				Model:        profile.Model,
+				SystemPrompt: profile.SystemPrompt,
+			}
+		}
+	}
+	
+	// Personas desde un repositorio Git (opcional): se recargan periódicamente
+	// Un catálogo nil desactiva el campo "persona"
+	var personas *application.PersonaCatalog
+	if cfg.PromptTemplatesGitURL != "" {
+		personas = application.NewPersonaCatalog(templates.NewGitSource(
+			cfg.PromptTemplatesGitURL,
+			cfg.PromptTemplatesGitBranch,
+			cfg.PromptTemplatesDir,
+			cfg.PromptTemplatesCheckout,
+		))
+		// Si la primera carga falla, el servidor arranca igual y reintenta en
+		// la siguiente recarga (las peticiones con persona darán 404 mientras)
+		refreshPersonas(personas)
+		go runPersonaRefresh(personas, cfg.PromptTemplatesRefresh)
+	}
+	
+	// Hooks del despliegue: lógica propia antes y después de cada llamada al modelo
+	hooks := application.NewHookRegistry()
+	registerHooks(hooks)
+	if cfg.PluginsDir != "" {
+		plugins := loadPlugins(cfg.PluginsDir, hooks)
+		defer plugins.Close(context.Background())
+	}
+	if hooks.Len() > 0 {
+		fmt.Printf("   ✓ Hooks registrados: %d\n", hooks.Len())
+	}
+	
+	// Redis (opcional): estado compartido entre réplicas
+	var redisClient *redis.Client
+	if cfg.RedisURL != "" {
+		redisClient = newRedisClient(cfg.RedisURL)
+		defer redisClient.Close()
+		fmt.Println("   ✓ Redis conectado")
+	}
+	
+	// Caché de respuestas: la política se comparte con el panel de administración
+	responseCache := &application.ResponseCachePolicy{
+		Cache:    newResponseCache(cfg, redisClient),
+		TTL:      cfg.ResponseCacheTTL,
+		Semantic: newSemanticCache(cfg, realProviders, settings, egressAllowlist, redacting),
+	}
 	
 	// CAPA DE APLICACIÓN - Servicio de Chat (lógica de negocio)
-	// Inyectamos el groqClient al servicio
+	// Inyectamos el llmClient al servicio
 	// El servicio solo conoce la interfaz, no la implementación
-	chatService := application.NewChatService(groqClient, cfg.DefaultModel)
+	chatService := application.NewChatService(
+		llmClient,
+		cfg.DefaultModel,
+		application.WithStopPolicy(application.StopPolicy{
+			Global:   cfg.StopSequences,
+			PerModel: cfg.ModelStopSequences,
+		}),
+		application.WithOutputGuard(application.OutputGuard{
+			DefaultMaxChars: cfg.OutputMaxChars,
+			TenantMaxChars:  cfg.TenantOutputMaxChars,
+		}),
+		application.WithLocalePolicy(localePolicy),
+		application.WithDefaultSystemPrompt(cfg.DefaultSystemPrompt),
+		application.WithTenantPrompts(application.TenantPromptPolicy{
+			Prefixes: cfg.TenantSystemPrompts,
+		}),
+		application.WithTenantModels(application.TenantModelPolicy{
+			Limits: cfg.TenantModelLimits,
+		}),
+		application.WithModelAliases(application.NewModelAliasCatalog(
+			cfg.ModelAliases,
+			alerts.NewLogDeprecationReporter(),
+		)),
+		application.WithPersonas(personas),
+		application.WithModelPricing(cfg.ModelPricing),
+		application.WithContextGuard(application.ContextGuard{
+			Windows:     cfg.ModelContextWindows,
+			Truncate:    cfg.ContextOverflow == "truncate",
+			ShrinkOrder: cfg.ContextShrinkOrder,
+		}),
+		application.WithHistoryCompaction(application.HistoryCompaction{
+			Threshold:    cfg.HistorySummaryThreshold,
+			Model:        cfg.HistorySummaryModel,
+			KeepMessages: cfg.HistorySummaryKeep,
+		}),
+		application.WithProviders(cfg.EnabledProviders()),
+		application.WithHooks(hooks),
+		application.WithResponseCache(responseCache),
+		application.WithGroundingCheck(application.GroundingPolicy{
+			Model: cfg.GroundingModel,
+		}),
+		application.WithCostRouting(application.CostRouting{
+			Profiles:        cfg.ModelProfiles,
+			DefaultProvider: cfg.LLMProvider,
+			DefaultQuality:  cfg.DefaultQuality,
+		}),
+		application.WithRuntimeSettings(settings),
+	)
 	fmt.Println("   ✓ Servicio de chat inicializado")
 	
+	// PostgreSQL (solo con STORAGE_BACKEND=postgres): conversaciones y consumo de tokens
+	// Solo existe en los binarios compilados con -tags postgres (ver storage_postgres.go)
+	var pg *postgresStorage
+	if cfg.StorageBackend == "postgres" {
+		pg = newPostgresStorage(cfg)
+		defer pg.Close()
+	}
+	
+	// Conversaciones: repositorio (según STORAGE_BACKEND) + servicio que reutiliza chatService
+	conversationRepo := newConversationRepository(cfg, redisClient, pg)
+	conversationOptions := []application.ConversationOption{
+		application.WithDeletedRetention(cfg.ConversationRetention),
+		application.WithDefaultModelSource(settings),
+	}
+	if budget, ok := cfg.ConversationBudget(); ok {
+		conversationOptions = append(conversationOptions, application.WithDefaultBudget(budget))
+	}
+	conversationService := application.NewConversationService(
+		chatService,
+		conversationRepo,
+		cfg.DefaultModel,
+		conversationOptions...,
+	)
+	fmt.Printf("   ✓ Servicio de conversaciones inicializado (%s)\n", cfg.StorageBackend)
+	
+	// Proceso de retención: elimina las conversaciones borradas fuera de plazo
+	go runRetention(conversationService, retentionSweepInterval)
+	
+	// Mejora de prompts: habla con el proveedor directamente con su propio modelo
+	promptService := application.NewPromptService(llmClient, cfg.PromptOptimizerModel)
+	fmt.Println("   ✓ Servicio de mejora de prompts inicializado")
+	
+	// Clasificación de textos: habla con el proveedor directamente, como la mejora de prompts
+	classificationService := application.NewClassificationService(
+		llmClient,
+		cfg.DefaultModel,
+		application.WithClassificationModelSource(settings),
+	)
+	fmt.Println("   ✓ Servicio de clasificación inicializado")
+	
+	// Anonimización de datos personales: el detector es otro adaptador (puerto secundario)
+	redactionService := application.NewRedactionService(pii.NewRegexDetector())
+	fmt.Println("   ✓ Servicio de anonimización inicializado")
+	
+	// Traducción a SQL: el analizador que valida la consulta es otro adaptador
+	nl2sqlService := application.NewNL2SQLService(
+		llmClient,
+		sqlcheck.NewAnalyzer(),
+		cfg.DefaultModel,
+		application.WithNL2SQLModelSource(settings),
+	)
+	fmt.Println("   ✓ Servicio NL2SQL inicializado")
+	
+	// Explicación y revisión de código: el detector de lenguaje es otro adaptador
+	codeService := application.NewCodeService(llmClient, language.NewCodeDetector(), cfg.CodeModel)
+	fmt.Println("   ✓ Servicio de código inicializado")
+	
+	// Consumo de tokens por cliente y día, con cuota diaria opcional
+	// Con USAGE_EVENTS_URL, cada consumo se publica también en NATS
+	var usageOptions []application.UsageOption
+	var stopUsageEvents func(ctx context.Context)
+	if cfg.UsageEventsURL != "" {
+		publisher := natsInfra.NewUsagePublisher(cfg.UsageEventsURL, cfg.UsageEventsSubject)
+		stop, err := publisher.Start()
+		if err != nil {
+			log.Fatalf("❌ Error al conectar los eventos de consumo con NATS: %v", err)
+		}
+		stopUsageEvents = stop
+		usageOptions = append(usageOptions, application.WithUsageEvents(publisher))
+	}
+	usageService := application.NewUsageService(
+		newUsageRepository(cfg, redisClient, pg),
+		application.UsageQuotas{
+			Default:   cfg.DailyTokenQuota,
+			PerClient: cfg.ClientTokenQuotas,
+		},
+		cfg.ModelPricing,
+		usageOptions...,
+	)
+	fmt.Println("   ✓ Servicio de consumo de tokens inicializado")
+	
+	// Valoraciones de las respuestas: se guardan donde el consumo
+	feedbackService := application.NewFeedbackService(newFeedbackRepository(cfg, redisClient, pg))
+	fmt.Println("   ✓ Servicio de valoraciones inicializado")
+	
+	// API keys de los clientes: se gestionan en /admin/keys y, con
+	// API_KEY_AUTH, se exigen en /api/v1
+	apiKeyOptions := []application.APIKeyOption{
+		application.WithAPIKeyExpiryEvents(newAPIKeyNotifier(cfg, egressAllowlist), cfg.APIKeyExpiryWarning),
+	}
+	
+	// Tokens de vida corta: solo con un secreto para firmarlos
+	if cfg.APITokenSecret != "" {
+		apiKeyOptions = append(apiKeyOptions, application.WithScopedTokens(jwt.NewSigner(cfg.APITokenSecret), cfg.APITokenMaxTTL))
+	}
+	
+	apiKeyService := application.NewAPIKeyService(
+		newAPIKeyRepository(cfg, redisClient, pg),
+		cfg.APIKeyRotationGrace,
+		apiKeyOptions...,
+	)
+	fmt.Println("   ✓ Servicio de API keys inicializado")
+	
+	// Avisos de caducidad de las API keys (al log o a API_KEY_WEBHOOK_URL)
+	go runAPIKeyExpiry(apiKeyService, apiKeyExpirySweepInterval)
+	
+	// Watchdog de tareas colgadas (opcional): las peticiones y los jobs que
+	// pasan de WATCHDOG_FACTOR veces su plazo se registran con las pilas de
+	// las goroutines
+	var livenessMonitor domain.LivenessMonitor
+	if cfg.WatchdogFactor > 0 {
+		watchdog := liveness.NewWatchdog(cfg.WatchdogFactor, cfg.HTTPTimeout, cfg.WatchdogInterval)
+		go watchdog.Run(context.Background())
+		livenessMonitor = watchdog
+		fmt.Printf("   ✓ Watchdog de tareas colgadas activado (%dx el plazo)\n", cfg.WatchdogFactor)
+	}
+	
+	// Chats asíncronos: los workers reutilizan chatService y apuntan el consumo
+	// de cada job (el middleware de consumo no lo ve)
+	jobOptions := []application.JobOption{application.WithJobUsage(usageService)}
+	if livenessMonitor != nil {
+		jobOptions = append(jobOptions, application.WithJobLiveness(livenessMonitor))
+	}
+	
+	// Callbacks de los jobs: solo con un secreto para firmarlos
+	if cfg.WebhookSecret != "" {
+		jobOptions = append(jobOptions, application.WithJobCallbacks(
+			httpInfra.NewJobWebhookNotifier(cfg.WebhookSecret, cfg.WebhookTimeout, cfg.WebhookAllowPrivate,
+				httpInfra.WithWebhookTransportWrapper(egressAllowlist.Wrap)),
+			alerts.NewLogDeadLetterReporter(),
+			application.JobCallbackPolicy{
+				MaxAttempts: cfg.WebhookMaxAttempts,
+				Backoff:     cfg.WebhookBackoff,
+			},
+		))
+	}
+	
+	// Respuestas grandes de los jobs: en disco, fuera del repositorio de jobs
+	if cfg.JobResultDir != "" {
+		resultStore, err := disk.NewJobResultStore(cfg.JobResultDir, cfg.JobRetention)
+		if err != nil {
+			log.Fatalf("❌ Error al preparar JOB_RESULT_DIR: %v", err)
+		}
+		jobOptions = append(jobOptions, application.WithJobResultStore(resultStore, int64(cfg.JobResultSpillBytes)))
+		fmt.Printf("   ✓ Resultados grandes de jobs en %s\n", cfg.JobResultDir)
+	}
+	
+	jobService := application.NewJobService(
+		chatService,
+		newJobRepository(cfg, redisClient, pg),
+		application.JobOptions{
+			Workers:   cfg.JobWorkers,
+			QueueSize: cfg.JobQueueSize,
+			Retention: cfg.JobRetention,
+		},
+		jobOptions...,
+	)
+	fmt.Printf("   ✓ Servicio de chats asíncronos inicializado (%d workers)\n", cfg.JobWorkers)
+	
+	// Comparación de respuestas: reutiliza chatService para cada variante
+	diffService := application.NewDiffService(chatService)
+	fmt.Println("   ✓ Servicio de comparación inicializado")
+	
+	// Modo proxy: reenvía el body al proveedor tal cual y registra el uso de tokens
+	proxyService := application.NewProxyService(llmClient, usage.NewLogUsageRecorder())
+	fmt.Println("   ✓ Servicio de proxy inicializado")
+	
+	// Transcripción de audio: usa los modelos Whisper de Groq (ver audio.go)
+	transcriptionService := newTranscriptionService(cfg, mockClient, settings, egressAllowlist)
+	
 	// CAPA DE INFRAESTRUCTURA - Handler HTTP (puerto primario)
 	// Inyectamos el chatService al handler
-	chatHandler := httpInfra.NewChatHandler(chatService)
+	chatHandlerOptions := []httpInfra.ChatHandlerOption{httpInfra.WithSSERetry(cfg.SSERetry)}
+	if cfg.StreamResumeWindow > 0 {
+		chatHandlerOptions = append(chatHandlerOptions, httpInfra.WithStreamResume(cfg.StreamResumeWindow))
+		fmt.Printf("   ✓ Flujos SSE reanudables con Last-Event-ID (%v)\n", cfg.StreamResumeWindow)
+	}
+	chatHandler := httpInfra.NewChatHandler(chatService, chatHandlerOptions...)
+	conversationHandler := httpInfra.NewConversationHandler(conversationService)
+	jobHandler := httpInfra.NewJobHandler(jobService)
+	promptHandler := httpInfra.NewPromptHandler(promptService)
+	classificationHandler := httpInfra.NewClassificationHandler(classificationService)
+	redactionHandler := httpInfra.NewRedactionHandler(redactionService)
+	nl2sqlHandler := httpInfra.NewNL2SQLHandler(nl2sqlService)
+	codeHandler := httpInfra.NewCodeHandler(codeService)
+	usageHandler := httpInfra.NewUsageHandler(usageService)
+	feedbackHandler := httpInfra.NewFeedbackHandler(feedbackService)
+	diffHandler := httpInfra.NewDiffHandler(diffService)
+	proxyHandler := httpInfra.NewProxyHandler(proxyService)
+	
+	var transcriptionHandler *httpInfra.TranscriptionHandler
+	if transcriptionService != nil {
+		transcriptionHandler = httpInfra.NewTranscriptionHandler(transcriptionService)
+	}
+	
+	// El panel de salud y el resto de /admin son de administración: solo con ADMIN_API_KEY
+	var modelHealthHandler *httpInfra.ModelHealthHandler
+	var adminHandler *httpInfra.AdminHandler
+	var apiKeyHandler *httpInfra.APIKeyHandler
+	if cfg.AdminAPIKey != "" {
+		modelHealthHandler = httpInfra.NewModelHealthHandler(healthMonitor)
+		apiKeyHandler = httpInfra.NewAPIKeyHandler(apiKeyService)
+		adminHandler = httpInfra.NewAdminHandler(httpInfra.AdminOptions{
+			Config:   cfg.Masked(),
+			Cache:    responseCache,
+			Health:   healthMonitor,
+			Settings: settings,
+			Liveness: livenessMonitor,
+			GroqKeys: groqKeysHealth,
+		})
+	}
 	fmt.Println("   ✓ Handlers HTTP inicializados")
 	
+	// Librería JSON de la capa HTTP (las alternativas se enlazan con su etiqueta)
+	jsonCodec, err := jsoncodec.Get(cfg.JSONCodec)
+	if err != nil {
+		log.Fatalf("❌ JSON_CODEC: %v", err)
+	}
+	httpInfra.SetJSONCodec(jsonCodec)
+	if cfg.JSONCodec != jsoncodec.Std {
+		fmt.Printf("   ✓ Codec JSON: %s\n", cfg.JSONCodec)
+	}
+	
+	// Watchdog de memoria (opcional): con el heap cerca del límite, las
+	// peticiones nuevas reciben 503 en lugar de arriesgar un OOM
+	var loadShedder domain.LoadShedder
+	if cfg.MemoryShedPercent > 0 {
+		if memoryLimit == math.MaxInt64 {
+			log.Fatalf("❌ MEMORY_SHED_PERCENT requiere MEMORY_LIMIT_MB o GOMEMLIMIT")
+		}
+		watchdog := memlimit.NewWatchdog(memoryLimit/100*int64(cfg.MemoryShedPercent), cfg.MemoryCheckInterval)
+		go watchdog.Run(context.Background())
+		loadShedder = watchdog
+		fmt.Printf("   ✓ Watchdog de memoria activado (límite %d MiB)\n", memoryLimit>>20)
+	}
+	
+	// Autenticación de /api/v1 con las API keys de los clientes (opcional)
+	var clientAPIKeys domain.APIKeyService
+	var tokenHandler *httpInfra.TokenHandler
+	if cfg.APIKeyAuth {
+		clientAPIKeys = apiKeyService
+		fmt.Println("   ✓ API keys de cliente obligatorias en /api/v1")
+		
+		if cfg.APITokenSecret != "" {
+			tokenHandler = httpInfra.NewTokenHandler(apiKeyService)
+			fmt.Println("   ✓ Tokens de vida corta en /api/v1/token")
+		}
+	}
+	
+	// Muestreo de chats para reproducirlos con `cli replay` (opcional):
+	// se anonimizan los mismos tipos de datos que con PII_REDACTION
+	var replaySampler domain.ReplaySampler
+	if cfg.ReplaySamplePercent > 0 {
+		corpus, err := disk.NewReplayCorpus(cfg.ReplayCorpusDir, cfg.ReplayMaxSamples)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		replaySampler = application.NewReplaySampler(corpus, pii.NewRegexDetector(), cfg.ReplaySamplePercent/100, cfg.PIIRedactionTypes)
+		fmt.Printf("   ✓ Muestreo del %g%% de los chats en %s\n", cfg.ReplaySamplePercent, cfg.ReplayCorpusDir)
+	}
+	
 	// CAPA DE INFRAESTRUCTURA - Router HTTP
 	// Configuramos todas las rutas
-	router := httpInfra.SetupRouter(chatHandler)
+	router := httpInfra.SetupRouter(httpInfra.Handlers{
+		Chat:           chatHandler,
+		Conversation:   conversationHandler,
+		Job:            jobHandler,
+		Prompt:         promptHandler,
+		Classification: classificationHandler,
+		Redaction:      redactionHandler,
+		NL2SQL:         nl2sqlHandler,
+		Code:           codeHandler,
+		Usage:          usageHandler,
+		Feedback:       feedbackHandler,
+		Diff:           diffHandler,
+		Proxy:          proxyHandler,
+		Transcription:  transcriptionHandler,
+		ModelHealth:    modelHealthHandler,
+		Admin:          adminHandler,
+		APIKeys:        apiKeyHandler,
+		Tokens:         tokenHandler,
+	}, httpInfra.RouterOptions{
+		AdminKey: cfg.AdminAPIKey,
+		AccessLog: httpInfra.AccessLogOptions{
+			Format:   cfg.AccessLogFormat,
+			Fields:   cfg.AccessLogFields,
+			Redactor: logging.NewRedactor(cfg.LogContent, cfg.LogRedactHeaders),
+		},
+		RateLimit: httpInfra.RateLimitOptions{
+			Counter:       newRateCounter(cfg, redisClient),
+			Limit:         cfg.RateLimitRequests,
+			Window:        cfg.RateLimitWindow,
+			MaxConcurrent: cfg.MaxConcurrentRequests,
+			Settings:      settings,
+			MaxWait:       cfg.RateLimitMaxWait,
+			WaitQueue:     cfg.RateLimitWaitQueue,
+			WriteTimeout:  serverWriteTimeout,
+		},
+		MaxBodyBytes: cfg.MaxBodyBytes,
+		LoadShedder:  loadShedder,
+		Liveness:     livenessMonitor,
+		APIKeys:      clientAPIKeys,
+		
+		APIKeyExpiryWarning: cfg.APIKeyExpiryWarning,
+		
+		Idempotency: httpInfra.IdempotencyOptions{
+			Store: newIdempotencyStore(cfg, redisClient),
+			TTL:   cfg.IdempotencyTTL,
+		},
+		
+		RouteCache: httpInfra.RouteCacheOptions{
+			Cache:    newRouteCache(cfg, redisClient),
+			Policies: cfg.RouteCachePolicies,
+		},
+		
+		Bulkhead: httpInfra.BulkheadOptions{
+			MaxInFlight: cfg.BulkheadMaxInFlight,
+			QueueSize:   cfg.BulkheadQueueSize,
+			MaxWait:     cfg.BulkheadMaxWait,
+		},
+		
+		ReplaySampler: replaySampler,
+		
+		MaxRequestTimeout: cfg.MaxRequestTimeout,
+		
+		Providers:       cfg.EnabledProviders(),
+		DefaultProvider: cfg.LLMProvider,
+		
+		WebUI: cfg.WebUI,
+	})
 	fmt.Println("   ✓ Router configurado")
 	
 	// ========================================================================
@@ -96,9 +680,27 @@ func main() {
 		Handler: router,                 // El router configurado
 		
 		// Timeouts importantes para seguridad y performance
-		ReadTimeout:  15 * time.Second, // Tiempo máx para leer el request
-		WriteTimeout: 15 * time.Second, // Tiempo máx para escribir la response
-		IdleTimeout:  60 * time.Second, // Tiempo máx que una conexión keep-alive puede estar idle
+		ReadTimeout:  15 * time.Second,   // Tiempo máx para leer el request
+		WriteTimeout: serverWriteTimeout, // Tiempo máx para escribir la response
+		IdleTimeout:  60 * time.Second,   // Tiempo máx que una conexión keep-alive puede estar idle
+	}
+	
+	// HTTPS (y mTLS) sin proxy delante: el certificado se carga aquí para
+	// fallar al arrancar y no en el primer handshake
+	scheme := "http"
+	if cfg.TLSEnabled() {
+		tlsConfig, err := httpInfra.NewTLSConfig(httpInfra.TLSOptions{
+			CertFile:     cfg.TLSCertFile,
+			KeyFile:      cfg.TLSKeyFile,
+			MinVersion:   cfg.TLSMinVersion,
+			ClientCAFile: cfg.TLSClientCAFile,
+			ClientAuth:   cfg.TLSClientAuth,
+		})
+		if err != nil {
+			log.Fatalf("❌ Error al configurar TLS: %v", err)
+		}
+		server.TLSConfig = tlsConfig
+		scheme = "https"
 	}
 	
 	// ========================================================================
@@ -110,22 +712,67 @@ func main() {
 	//
 	go func() {
 		fmt.Println()
-		fmt.Printf("🚀 Servidor escuchando en http://localhost%s\n", cfg.GetServerAddress())
+		fmt.Printf("🚀 Servidor escuchando en %s://localhost%s\n", scheme, cfg.GetServerAddress())
 		fmt.Println("📡 Endpoints disponibles:")
-		fmt.Printf("   • POST http://localhost%s/api/v1/chat\n", cfg.GetServerAddress())
-		fmt.Printf("   • GET  http://localhost%s/api/v1/models\n", cfg.GetServerAddress())
-		fmt.Printf("   • GET  http://localhost%s/health\n", cfg.GetServerAddress())
+		fmt.Printf("   • POST %s://localhost%s/api/v1/chat\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • GET  %s://localhost%s/api/v1/models\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • POST %s://localhost%s/api/v1/conversations\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • GET  %s://localhost%s/api/v1/conversations/{id}\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • PATCH %s://localhost%s/api/v1/conversations/{id}\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • DEL  %s://localhost%s/api/v1/conversations/{id}\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • POST %s://localhost%s/api/v1/conversations/{id}/messages\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • POST %s://localhost%s/api/v1/conversations/{id}/restore\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • POST %s://localhost%s/api/v1/prompts/improve\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • POST %s://localhost%s/api/v1/diff\n", scheme, cfg.GetServerAddress())
+		fmt.Printf("   • GET  %s://localhost%s/health\n", scheme, cfg.GetServerAddress())
 		fmt.Println()
 		fmt.Println("👉 Presiona Ctrl+C para detener el servidor")
 		fmt.Println()
 		
 		// ListenAndServe() bloquea hasta que el servidor se detenga
 		// Retorna error si falla al iniciar (ej: puerto ocupado)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		// Con TLS, el certificado ya está en server.TLSConfig
+		var err error
+		if server.TLSConfig != nil {
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
 			log.Fatalf("❌ Error al iniciar servidor: %v", err)
 		}
 	}()
 	
+	// Servidor gRPC (opcional): mismo chatService, otro adaptador primario
+	var stopGRPC func(ctx context.Context)
+	if cfg.GRPCPort != "" {
+		stop, err := grpcInfra.Start(cfg.GRPCPort, chatService)
+		if err != nil {
+			log.Fatalf("❌ Error al iniciar el servidor gRPC: %v", err)
+		}
+		stopGRPC = stop
+		fmt.Printf("🚀 Servidor gRPC escuchando en :%s\n", cfg.GRPCPort)
+	}
+	
+	// Consumidor de chats por NATS (opcional): otro adaptador primario
+	var stopQueue func(ctx context.Context)
+	if cfg.QueueURL != "" {
+		consumer := natsInfra.NewChatConsumer(chatService, natsInfra.ConsumerOptions{
+			URL:          cfg.QueueURL,
+			Subject:      cfg.QueueSubject,
+			Group:        cfg.QueueGroup,
+			ReplySubject: cfg.QueueReplySubject,
+			Concurrency:  cfg.QueueConcurrency,
+			Timeout:      cfg.HTTPTimeout,
+		})
+		stop, err := consumer.Start()
+		if err != nil {
+			log.Fatalf("❌ Error al iniciar el consumidor de NATS: %v", err)
+		}
+		stopQueue = stop
+		fmt.Printf("🚀 Consumiendo chats de %s\n", cfg.QueueSubject)
+	}
+	
 	// ========================================================================
 	// 6. GRACEFUL SHUTDOWN
 	// ========================================================================
@@ -133,15 +780,320 @@ func main() {
 	// Manejar señales del sistema para shutdown gracioso
 	// Esto permite que las peticiones en curso terminen antes de cerrar
 	//
-	waitForShutdown(server)
+	waitForShutdown(server, stopGRPC, stopQueue, stopUsageEvents)
 }
 
 // ============================================================================
 // FUNCIONES AUXILIARES
 // ============================================================================
 
+// serverWriteTimeout es el tiempo máximo para escribir la response (el rate
+// limit lo renueva a las peticiones que esperan con ?wait=true)
+const serverWriteTimeout = 15 * time.Second
+
+// retentionSweepInterval es cada cuánto se purgan las conversaciones borradas
+const retentionSweepInterval = 10 * time.Minute
+
+// apiKeyExpirySweepInterval es cada cuánto se buscan API keys por caducar
+const apiKeyExpirySweepInterval = 5 * time.Minute
+
+// personaRefreshTimeout es el tiempo máximo de cada recarga de personas
+const personaRefreshTimeout = time.Minute
+
+// newConversationRepository crea el repositorio de conversaciones configurado
+// pg solo se usa con STORAGE_BACKEND=postgres
+func newConversationRepository(cfg *config.Config, redisClient *redis.Client, pg *postgresStorage) domain.ConversationRepository {
+	switch cfg.StorageBackend {
+	case "redis":
+		return redis.NewConversationRepository(redisClient, cfg.RedisKeyPrefix)
+	case "postgres":
+		return pg.conversations()
+	default:
+		return memory.NewConversationRepository()
+	}
+}
+
+// newUsageRepository elige dónde se cuenta el consumo de tokens: PostgreSQL
+// si es el almacenamiento, Redis si está configurado (compartido entre
+// réplicas) o en memoria
+func newUsageRepository(cfg *config.Config, redisClient *redis.Client, pg *postgresStorage) domain.UsageRepository {
+	if pg != nil {
+		return pg.usage()
+	}
+	if redisClient != nil {
+		return redis.NewUsageRepository(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewUsageRepository()
+}
+
+// newJobRepository elige dónde se guardan los jobs, igual que el consumo:
+// con PostgreSQL o Redis cualquier réplica responde al sondeo
+func newJobRepository(cfg *config.Config, redisClient *redis.Client, pg *postgresStorage) domain.JobRepository {
+	if pg != nil {
+		return pg.jobs()
+	}
+	if redisClient != nil {
+		return redis.NewJobRepository(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewJobRepository()
+}
+
+// newFeedbackRepository elige dónde se guardan las valoraciones, igual que
+// el consumo: en memoria se pierden al reiniciar (solo para desarrollo)
+func newFeedbackRepository(cfg *config.Config, redisClient *redis.Client, pg *postgresStorage) domain.FeedbackRepository {
+	if pg != nil {
+		return pg.feedback()
+	}
+	if redisClient != nil {
+		return redis.NewFeedbackRepository(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewFeedbackRepository()
+}
+
+// newAPIKeyNotifier elige adónde van los eventos de caducidad de las API
+// keys: el webhook si está configurado, si no el log
+func newAPIKeyNotifier(cfg *config.Config, allowlist *egress.Allowlist) domain.APIKeyNotifier {
+	if cfg.APIKeyWebhookURL != "" {
+		return httpInfra.NewAPIKeyWebhookNotifier(cfg.APIKeyWebhookURL, cfg.WebhookSecret, cfg.WebhookTimeout,
+			httpInfra.WithWebhookTransportWrapper(allowlist.Wrap))
+	}
+	return alerts.NewLogAPIKeyNotifier()
+}
+
+// newAPIKeyRepository elige dónde se guardan las API keys, igual que el
+// consumo: en memoria se pierden al reiniciar (solo para desarrollo)
+func newAPIKeyRepository(cfg *config.Config, redisClient *redis.Client, pg *postgresStorage) domain.APIKeyRepository {
+	if pg != nil {
+		return pg.apiKeys()
+	}
+	if redisClient != nil {
+		return redis.NewAPIKeyRepository(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewAPIKeyRepository()
+}
+
+// newRedisClient crea el cliente Redis y comprueba que el servidor responde
+func newRedisClient(url string) *redis.Client {
+	client, err := redis.NewClient(url)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		log.Fatalf("❌ Error al conectar con Redis: %v", err)
+	}
+	return client
+}
+
+// newRateCounter elige el contador de rate limit: Redis si está configurado
+// (compartido entre réplicas) o en memoria
+func newRateCounter(cfg *config.Config, redisClient *redis.Client) domain.RateCounter {
+	if redisClient != nil {
+		return redis.NewRateCounter(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewRateCounter()
+}
+
+// newResponseCache elige la caché de respuestas: Redis si está configurado
+// (compartida entre réplicas) o en memoria; nil si está desactivada
+func newResponseCache(cfg *config.Config, redisClient *redis.Client) domain.ResponseCache {
+	if cfg.ResponseCacheTTL <= 0 {
+		return nil
+	}
+	if redisClient != nil {
+		return redis.NewResponseCache(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewResponseCache(cfg.ResponseCacheMaxEntries)
+}
+
+// newSemanticCache crea la caché semántica con el cliente de embeddings del
+// proveedor configurado; nil si está desactivada o no hay proveedores reales
+// (en modo mock o reproduciendo no hay a quién pedir los embeddings)
+// Con la anonimización activada (redacting no nil), las preguntas también se
+// anonimizan antes de enviarlas al proveedor de embeddings
+func newSemanticCache(
+	cfg *config.Config,
+	realProviders bool,
+	settings domain.SettingsSource,
+	egressAllowlist *egress.Allowlist,
+	redacting *application.RedactingRepository,
+) *application.SemanticCachePolicy {
+	if cfg.SemanticCacheThreshold <= 0 {
+		return nil
+	}
+	if !realProviders {
+		fmt.Println("   ⚠️  Caché semántica desactivada: no hay proveedor real de embeddings")
+		return nil
+	}
+	
+	var embedder domain.EmbeddingRepository
+	switch cfg.SemanticCacheProvider {
+	case domain.ProviderOllama:
+		embedder = ollama.NewEmbeddingClient(
+			cfg.OllamaBaseURL,
+			cfg.HTTPTimeout,
+			ollama.WithRuntimeSettings(settings),
+			ollama.WithTransportWrapper(egressAllowlist.Wrap),
+		)
+	default:
+		embedder = openai.NewEmbeddingClient(
+			cfg.OpenAIAPIKey,
+			cfg.OpenAIBaseURL,
+			cfg.HTTPTimeout,
+			groq.WithRuntimeSettings(settings),
+			groq.WithTransportWrapper(egressAllowlist.Wrap),
+		)
+	}
+	if redacting != nil {
+		embedder = application.NewRedactingEmbedder(embedder, redacting)
+	}
+	fmt.Printf("   ✓ Caché semántica inicializada (%s, similitud %g)\n", cfg.EmbeddingModel(), cfg.SemanticCacheThreshold)
+	return &application.SemanticCachePolicy{
+		Embedder:  embedder,
+		Model:     cfg.EmbeddingModel(),
+		Cache:     memory.NewSemanticCache(cfg.SemanticCacheMaxEntries),
+		Threshold: cfg.SemanticCacheThreshold,
+	}
+}
+
+// newRouteCache elige dónde se guardan las respuestas de las rutas con
+// política de caché: Redis si está configurado o en memoria; nil si ninguna
+// ruta tiene política
+func newRouteCache(cfg *config.Config, redisClient *redis.Client) domain.RouteCache {
+	if !cfg.RouteCacheEnabled() {
+		return nil
+	}
+	if redisClient != nil {
+		return redis.NewRouteCache(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewRouteCache(cfg.RouteCacheMaxEntries)
+}
+
+// newIdempotencyStore elige dónde se guardan las respuestas con
+// Idempotency-Key: Redis si está configurado o en memoria; nil si está
+// desactivado
+func newIdempotencyStore(cfg *config.Config, redisClient *redis.Client) domain.IdempotencyStore {
+	if cfg.IdempotencyTTL <= 0 {
+		return nil
+	}
+	if redisClient != nil {
+		return redis.NewIdempotencyStore(redisClient, cfg.RedisKeyPrefix)
+	}
+	return memory.NewIdempotencyStore(cfg.IdempotencyMaxEntries)
+}
+
+// registerHooks registra los hooks propios del despliegue
+// Es el punto de extensión para quien embebe la API: enrutado por cabeceras,
+// facturación propia, validaciones extra... sin tocar handlers ni servicios.
+// Ejemplo (rechazar las peticiones sin cabecera de proyecto):
+//
+//	hooks.OnRequest(domain.RequestHookFunc(func(ctx context.Context, request *domain.ChatRequest) error {
+//		if domain.RequestHeadersFromContext(ctx).Get("X-Project") == "" {
+//			return fmt.Errorf("%w: falta la cabecera X-Project", domain.ErrRequestRejected)
+//		}
+//		return nil
+//	}))
+func registerHooks(hooks *application.HookRegistry) {
+	// Sin hooks por defecto
+}
+
+// loadPlugins carga los plugins WASM de dir y registra sus hooks
+// Se registran después de los de registerHooks, en orden de nombre de fichero
+func loadPlugins(dir string, hooks *application.HookRegistry) *wasm.Plugins {
+	plugins, err := wasm.Load(context.Background(), dir)
+	if err != nil {
+		log.Fatalf("❌ Error al cargar los plugins WASM: %v", err)
+	}
+	
+	for _, hook := range plugins.RequestHooks {
+		hooks.OnRequest(hook)
+	}
+	for _, hook := range plugins.ResponseHooks {
+		hooks.OnResponse(hook)
+	}
+	fmt.Printf("   ✓ Plugins WASM cargados: %d %v\n", len(plugins.Names), plugins.Names)
+	return plugins
+}
+
+// runRetention purga periódicamente las conversaciones fuera de plazo
+// Se ejecuta en su propia goroutine durante toda la vida del proceso
+func runRetention(service domain.ConversationService, interval time.Duration) {
+	// time.NewTicker envía un valor al canal C cada interval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for range ticker.C {
+		purged, err := service.PurgeDeleted(context.Background())
+		if err != nil {
+			log.Printf("❌ Error en la retención de conversaciones: %v", err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("🗑️  Retención: %d conversaciones eliminadas definitivamente", purged)
+		}
+	}
+}
+
+// runAPIKeyExpiry emite periódicamente los avisos de caducidad de las API keys
+func runAPIKeyExpiry(service domain.APIKeyService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for range ticker.C {
+		notified, err := service.NotifyExpirations(context.Background())
+		if err != nil {
+			log.Printf("❌ Error al avisar de la caducidad de las API keys: %v", err)
+		}
+		if notified > 0 {
+			log.Printf("🔑 Caducidad de API keys: %d avisos emitidos", notified)
+		}
+	}
+}
+
+// runPersonaRefresh recarga periódicamente las personas del repositorio
+func runPersonaRefresh(catalog *application.PersonaCatalog, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for range ticker.C {
+		refreshPersonas(catalog)
+	}
+}
+
+// refreshPersonas recarga las personas; si falla, se mantienen las anteriores
+func refreshPersonas(catalog *application.PersonaCatalog) {
+	// Un git colgado no debe bloquear las recargas siguientes
+	ctx, cancel := context.WithTimeout(context.Background(), personaRefreshTimeout)
+	defer cancel()
+	
+	count, err := catalog.Refresh(ctx)
+	if err != nil {
+		log.Printf("❌ Error al recargar las personas: %v", err)
+		return
+	}
+	log.Printf("🎭 Personas recargadas: %d", count)
+}
+
+// watchReloadSignal recarga los ajustes cada vez que llega SIGHUP
+// Si la nueva configuración no es válida se mantienen los ajustes anteriores
+func watchReloadSignal(settings domain.SettingsService) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	
+	for range hup {
+		log.Println("🔄 SIGHUP recibido: recargando configuración...")
+		if _, err := settings.Reload(context.Background()); err != nil {
+			log.Printf("❌ %v (se mantiene la configuración anterior)", err)
+		}
+	}
+}
+
 // waitForShutdown espera una señal de interrupción y hace shutdown gracioso
-func waitForShutdown(server *http.Server) {
+// stopGRPC detiene el servidor gRPC, stopQueue el consumidor de NATS y
+// stopUsageEvents el publicador de eventos de consumo (nil si no están activos)
+func waitForShutdown(server *http.Server, stopGRPC, stopQueue, stopUsageEvents func(ctx context.Context)) {
 	// Crear un canal para recibir señales del sistema
 	// make(chan os.Signal, 1) crea un canal con buffer de 1
 	quit := make(chan os.Signal, 1)
@@ -168,6 +1120,17 @@ func waitForShutdown(server *http.Server) {
 	if err := server.Shutdown(ctx); err != nil {
 		log.Printf("❌ Error durante shutdown: %v", err)
 	}
+	if stopGRPC != nil {
+		stopGRPC(ctx)
+	}
+	if stopQueue != nil {
+		stopQueue(ctx)
+	}
+	// Los eventos de consumo se paran los últimos: recogen los de las
+	// peticiones que han terminado durante el apagado
+	if stopUsageEvents != nil {
+		stopUsageEvents(ctx)
+	}
 	
 	fmt.Println("✅ Servidor detenido correctamente")
 	fmt.Println("👋 ¡Hasta luego!")
@@ -286,7 +1249,7 @@ func printBanner() {
 // 1. HTTP Request → Router
 // 2. Router → Handler
 // 3. Handler → ChatService (aplicación)
-// 4. ChatService → GroqRepository (interfaz del dominio)
+// 4. ChatService → LLMRepository (interfaz del dominio)
 // 5. GroqClient → API de Groq (implementación de infraestructura)
 // 6. Respuesta en sentido inverso
 //
@@ -299,7 +1262,7 @@ func printBanner() {
 // 1. Usuario: POST /api/v1/chat {"message": "Hola"}
 // 2. Router: detecta ruta, llama a handler.HandleChat()
 // 3. Handler: valida JSON, llama a chatService.SendMessage()
-// 4. Service: crea ChatRequest, llama a groqRepo.CreateChatCompletion()
+// 4. Service: crea ChatRequest, llama a llmRepo.CreateChatCompletion()
 // 5. GroqClient: hace HTTP POST a api.groq.com
 // 6. Groq API: procesa y retorna respuesta
 // 7. GroqClient: parsea JSON, retorna ChatResponse
@@ -0,0 +1,30 @@
+//go:build !postgres
+
+package main
+
+import (
+	"log"
+
+	"groq-hexagonal-api/internal/config"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// postgresStorage no existe en un binario compilado sin la etiqueta "postgres"
+// (la opción por defecto): ver storage_postgres.go
+type postgresStorage struct{}
+
+// newPostgresStorage termina el proceso: STORAGE_BACKEND=postgres necesita
+// un binario compilado con PostgreSQL
+func newPostgresStorage(cfg *config.Config) *postgresStorage {
+	log.Fatalf("❌ STORAGE_BACKEND=postgres requiere un binario compilado con -tags postgres (make build-postgres)")
+	return nil
+}
+
+// El resto de métodos nunca se llaman: newPostgresStorage no retorna
+
+func (s *postgresStorage) Close() error                                 { return nil }
+func (s *postgresStorage) conversations() domain.ConversationRepository { return nil }
+func (s *postgresStorage) usage() domain.UsageRepository                { return nil }
+func (s *postgresStorage) jobs() domain.JobRepository                   { return nil }
+func (s *postgresStorage) apiKeys() domain.APIKeyRepository             { return nil }
+func (s *postgresStorage) feedback() domain.FeedbackRepository          { return nil }
@@ -0,0 +1,86 @@
+//go:build postgres
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"groq-hexagonal-api/internal/config"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/postgres"
+)
+
+// ============================================================================
+// POSTGRESQL (-tags postgres)
+// ============================================================================
+//
+// Sin la etiqueta, el binario no incluye el paquete postgres (repositorios,
+// migraciones ni driver): ver storage_nopostgres.go y `make build-postgres`.
+// ============================================================================
+
+// postgresStorage crea los repositorios de PostgreSQL sobre pools compartidos
+type postgresStorage struct {
+	db *postgres.DB
+}
+
+// newPostgresStorage abre los pools de PostgreSQL y aplica las migraciones pendientes
+func newPostgresStorage(cfg *config.Config) *postgresStorage {
+	ctx := context.Background()
+	db, err := postgres.Open(ctx, cfg.DatabaseURL, postgres.Options{
+		ReplicaDSN:       cfg.DatabaseReplicaURL,
+		MaxOpenConns:     cfg.DatabaseMaxOpenConns,
+		MaxIdleConns:     cfg.DatabaseMaxIdleConns,
+		ConnMaxLifetime:  cfg.DatabaseConnMaxLifetime,
+		ConnMaxIdleTime:  cfg.DatabaseConnMaxIdleTime,
+		StatementTimeout: cfg.DatabaseStatementTimeout,
+		ReadRetries:      cfg.DatabaseReadRetries,
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	// El esquema se crea o actualiza al arrancar (en el primario): no hace
+	// falta un paso aparte
+	applied, err := postgres.Migrate(ctx, db.DB)
+	if err != nil {
+		log.Fatalf("❌ Error al migrar PostgreSQL: %v", err)
+	}
+	fmt.Printf("   ✓ PostgreSQL conectado (%d migraciones aplicadas)\n", applied)
+	if db.HasReplica() {
+		fmt.Println("   ✓ Réplica de lectura de PostgreSQL conectada")
+	}
+
+	return &postgresStorage{db: db}
+}
+
+// Close cierra los pools
+func (s *postgresStorage) Close() error {
+	return s.db.Close()
+}
+
+// conversations crea el repositorio de conversaciones
+func (s *postgresStorage) conversations() domain.ConversationRepository {
+	return postgres.NewConversationRepository(s.db)
+}
+
+// usage crea el repositorio del consumo de tokens
+func (s *postgresStorage) usage() domain.UsageRepository {
+	return postgres.NewUsageRepository(s.db)
+}
+
+// jobs crea el repositorio de los chats asíncronos
+func (s *postgresStorage) jobs() domain.JobRepository {
+	return postgres.NewJobRepository(s.db)
+}
+
+// apiKeys crea el repositorio de las API keys
+func (s *postgresStorage) apiKeys() domain.APIKeyRepository {
+	return postgres.NewAPIKeyRepository(s.db)
+}
+
+// feedback crea el repositorio de las valoraciones
+func (s *postgresStorage) feedback() domain.FeedbackRepository {
+	return postgres.NewFeedbackRepository(s.db)
+}
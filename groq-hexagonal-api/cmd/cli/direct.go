@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/config"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/groq"
+	"io"
+	"strings"
+)
+
+// ============================================================================
+// BACKEND DIRECTO (Groq, sin servidor)
+// ============================================================================
+//
+// Con -direct el cliente monta el mismo adaptador de Groq y el mismo
+// ChatService que cmd/api, con GROQ_API_KEY, GROQ_BASE_URL, DEFAULT_MODEL y
+// DEFAULT_SYSTEM_PROMPT de .env o del entorno. No pasa por los middlewares
+// del servidor: sin rate limit, caché ni registro de consumo.
+// ============================================================================
+
+// directBackend usa el ChatService en el propio proceso
+type directBackend struct {
+	chatService domain.ChatService
+	baseURL     string
+}
+
+// newDirectBackend crea el backend a partir de la configuración
+func newDirectBackend() (*directBackend, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("error al cargar la configuración: %w", err)
+	}
+	if cfg.GroqAPIKey == "" {
+		return nil, errors.New("GROQ_API_KEY es requerida con -direct")
+	}
+
+	client := groq.NewGroqClient(cfg.GroqAPIKey, cfg.GroqBaseURL, cfg.HTTPTimeout)
+	return &directBackend{
+		chatService: application.NewChatService(
+			client,
+			cfg.DefaultModel,
+			application.WithDefaultSystemPrompt(cfg.DefaultSystemPrompt),
+		),
+		baseURL: cfg.GroqBaseURL,
+	}, nil
+}
+
+// Name implementa backend
+func (b *directBackend) Name() string {
+	return b.baseURL + " (directo)"
+}
+
+// Stream implementa backend
+func (b *directBackend) Stream(ctx context.Context, req turnRequest, out io.Writer) (*turnReply, error) {
+	stream, err := b.chatService.StreamMessage(ctx, req.Message, req.Model, domain.MessageOptions{
+		SystemPrompt: req.SystemPrompt,
+		History:      req.History,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	reply := &turnReply{Model: req.Model}
+	var content strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			reply.Content = content.String()
+			return reply, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		delta := chunk.GetDeltaContent()
+		fmt.Fprint(out, delta)
+		content.WriteString(delta)
+		if chunk.Model != "" {
+			reply.Model = chunk.Model
+		}
+		if usage := chunk.GetUsage(); usage != nil {
+			reply.TotalTokens = usage.TotalTokens
+		}
+	}
+}
+
+// Models implementa backend
+func (b *directBackend) Models(ctx context.Context) ([]string, error) {
+	response, err := b.chatService.GetAvailableModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(response.Data))
+	for i, model := range response.Data {
+		ids[i] = model.ID
+	}
+	return ids, nil
+}
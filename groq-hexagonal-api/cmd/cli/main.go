@@ -0,0 +1,281 @@
+// Package main - Cliente de chat interactivo para la terminal
+//
+// Un segundo binario que habla con la API en marcha (por defecto) o
+// directamente con Groq (-direct), usando el mismo adaptador y el mismo
+// servicio de chat que el servidor. Sirve para probar sin curl:
+//
+//	go run ./cmd/cli                          # contra http://localhost:8080
+//	go run ./cmd/cli -url https://api.example.com -key gk_...
+//	go run ./cmd/cli -direct -model llama-3.1-8b-instant
+//
+// El historial de la conversación vive en el cliente y se envía en cada
+// mensaje (campo "history" de /api/v1/chat), y la respuesta se muestra a
+// medida que llega (streaming).
+//
+// El subcomando replay reproduce el corpus de peticiones muestreadas contra
+// otra versión de la API (ver replay.go):
+//
+//	go run ./cmd/cli replay -url http://candidata:8080
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// BACKENDS
+// ============================================================================
+
+// backend es lo que el REPL necesita de la API (remote.go) o de Groq
+// (direct.go)
+type backend interface {
+	// Stream envía el mensaje con el historial y escribe la respuesta en out
+	// a medida que llega. model vacío = el modelo por defecto
+	Stream(ctx context.Context, req turnRequest, out io.Writer) (*turnReply, error)
+
+	// Models lista los IDs de los modelos disponibles
+	Models(ctx context.Context) ([]string, error)
+
+	// Name describe el backend en el banner (ej: la URL de la API)
+	Name() string
+}
+
+// turnRequest es un mensaje del usuario con su contexto
+type turnRequest struct {
+	Message      string
+	Model        string
+	SystemPrompt string
+	History      []domain.ChatMessage
+}
+
+// turnReply es la respuesta completa de un turno
+type turnReply struct {
+	Content string
+	Model   string
+
+	// TotalTokens es 0 si el backend no informó del uso
+	TotalTokens int
+}
+
+// ============================================================================
+// MAIN
+// ============================================================================
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		os.Exit(runReplay(os.Args[2:]))
+	}
+
+	url := flag.String("url", "http://localhost:8080", "URL de la API")
+	key := flag.String("key", os.Getenv("API_KEY"), "API key de cliente (Authorization: Bearer; por defecto $API_KEY)")
+	tenant := flag.String("tenant", "", "Tenant de las peticiones (cabecera X-Tenant-ID)")
+	direct := flag.Bool("direct", false, "Hablar directamente con Groq (GROQ_API_KEY de .env o del entorno)")
+	model := flag.String("model", "", "Modelo inicial (vacío = el modelo por defecto)")
+	system := flag.String("system", "", "Instrucciones de sistema iniciales")
+	flag.Parse()
+
+	var chat backend
+	if *direct {
+		var err error
+		if chat, err = newDirectBackend(); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		chat = newRemoteBackend(*url, *key, *tenant)
+	}
+
+	repl := &repl{
+		backend:      chat,
+		model:        *model,
+		systemPrompt: *system,
+		out:          os.Stdout,
+	}
+	repl.run(os.Stdin)
+}
+
+// ============================================================================
+// REPL
+// ============================================================================
+
+// repl es el bucle de lectura de mensajes y comandos
+type repl struct {
+	backend      backend
+	model        string
+	systemPrompt string
+	history      []domain.ChatMessage
+	out          io.Writer
+
+	// cancel corta la respuesta en curso con Ctrl+C (nil = no hay ninguna)
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+// run lee líneas de in hasta /exit o fin de la entrada (Ctrl+D)
+func (r *repl) run(in io.Reader) {
+	fmt.Fprintf(r.out, "💬 Chat con %s. /help para ver los comandos\n", r.backend.Name())
+	go r.handleInterrupts()
+
+	scanner := bufio.NewScanner(in)
+	// Se admiten mensajes pegados de hasta 1 MiB (por defecto, 64 KiB)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	for {
+		fmt.Fprint(r.out, r.prompt())
+		if !scanner.Scan() {
+			fmt.Fprintln(r.out)
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "/"):
+			if !r.command(line) {
+				return
+			}
+		default:
+			r.send(line)
+		}
+	}
+}
+
+// prompt muestra el modelo en uso
+func (r *repl) prompt() string {
+	if r.model == "" {
+		return "> "
+	}
+	return "[" + r.model + "] > "
+}
+
+// command ejecuta un comando; retorna false para salir
+func (r *repl) command(line string) bool {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "/exit", "/quit":
+		return false
+	case "/help":
+		fmt.Fprintln(r.out, `Comandos:
+  /model [id]      muestra o cambia el modelo (vacío = el por defecto)
+  /models          lista los modelos disponibles
+  /system [texto]  muestra o cambia las instrucciones de sistema
+  /history         muestra la conversación
+  /reset           empieza una conversación nueva
+  /exit            sale (también Ctrl+D)
+Ctrl+C corta la respuesta en curso.`)
+	case "/model":
+		if arg == "" {
+			fmt.Fprintf(r.out, "Modelo: %s\n", valueOr(r.model, "(por defecto)"))
+			break
+		}
+		if arg == "default" {
+			arg = ""
+		}
+		r.model = arg
+	case "/models":
+		models, err := r.backend.Models(context.Background())
+		if err != nil {
+			fmt.Fprintf(r.out, "❌ %v\n", err)
+			break
+		}
+		for _, model := range models {
+			fmt.Fprintf(r.out, "  %s\n", model)
+		}
+	case "/system":
+		if arg == "" {
+			fmt.Fprintf(r.out, "Sistema: %s\n", valueOr(r.systemPrompt, "(ninguno)"))
+			break
+		}
+		r.systemPrompt = arg
+	case "/history":
+		for _, message := range r.history {
+			fmt.Fprintf(r.out, "%s: %s\n", message.Role, message.Content)
+		}
+	case "/reset":
+		r.history = nil
+		fmt.Fprintln(r.out, "Conversación nueva")
+	default:
+		fmt.Fprintf(r.out, "Comando desconocido %s (/help)\n", name)
+	}
+	return true
+}
+
+// send envía un mensaje y muestra la respuesta por fragmentos
+// El turno solo pasa al historial si la respuesta llegó entera
+func (r *repl) send(message string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.setCancel(cancel)
+	defer func() {
+		r.setCancel(nil)
+		cancel()
+	}()
+
+	reply, err := r.backend.Stream(ctx, turnRequest{
+		Message:      message,
+		Model:        r.model,
+		SystemPrompt: r.systemPrompt,
+		History:      r.history,
+	}, r.out)
+	fmt.Fprintln(r.out)
+	if ctx.Err() != nil {
+		fmt.Fprintln(r.out, "⏹️  Respuesta cortada")
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(r.out, "❌ %v\n", err)
+		return
+	}
+
+	r.history = append(r.history,
+		domain.ChatMessage{Role: "user", Content: message},
+		domain.ChatMessage{Role: "assistant", Content: reply.Content},
+	)
+	if reply.TotalTokens > 0 {
+		fmt.Fprintf(r.out, "   (%s, %d tokens)\n", reply.Model, reply.TotalTokens)
+	}
+}
+
+// handleInterrupts corta la respuesta en curso con Ctrl+C; sin ninguna, sale
+func (r *repl) handleInterrupts() {
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+
+	for range interrupts {
+		r.mu.Lock()
+		cancel := r.cancel
+		r.mu.Unlock()
+
+		if cancel == nil {
+			fmt.Fprintln(r.out)
+			os.Exit(0)
+		}
+		cancel()
+	}
+}
+
+// setCancel guarda cómo cortar la respuesta en curso
+func (r *repl) setCancel(cancel context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cancel = cancel
+}
+
+// valueOr retorna value, o fallback si está vacío
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	httpInfra "groq-hexagonal-api/internal/infrastructure/http"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ============================================================================
+// BACKEND REMOTO (la API en marcha)
+// ============================================================================
+//
+// Cada mensaje es un POST /api/v1/chat con "stream": true y el historial en
+// "history"; la respuesta llega por Server-Sent Events (ver stream.go del
+// servidor):
+//
+//   data: {"content": "Hola"}   → un fragmento de texto
+//   event: error / data: {...}  → fallo a mitad del flujo
+//   data: [DONE]                → fin del flujo
+// ============================================================================
+
+// remoteBackend habla con la API por HTTP
+type remoteBackend struct {
+	baseURL string
+	apiKey  string
+	tenant  string
+
+	// Sin timeout: las respuestas largas por streaming lo superarían
+	// Ctrl+C corta la petición cancelando su contexto
+	client *http.Client
+}
+
+// newRemoteBackend crea el backend; apiKey y tenant vacíos = no se envían
+func newRemoteBackend(baseURL, apiKey, tenant string) *remoteBackend {
+	return &remoteBackend{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		tenant:  tenant,
+		client:  &http.Client{},
+	}
+}
+
+// Name implementa backend
+func (b *remoteBackend) Name() string {
+	return b.baseURL
+}
+
+// Stream implementa backend
+func (b *remoteBackend) Stream(ctx context.Context, req turnRequest, out io.Writer) (*turnReply, error) {
+	history := make([]httpInfra.MessageInfo, len(req.History))
+	for i, message := range req.History {
+		history[i] = httpInfra.MessageInfo{Role: message.Role, Content: message.Content}
+	}
+	body, err := json.Marshal(httpInfra.ChatRequest{
+		Message:      req.Message,
+		Model:        req.Model,
+		SystemPrompt: req.SystemPrompt,
+		History:      history,
+		Stream:       true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar el mensaje: %w", err)
+	}
+
+	resp, err := b.do(ctx, http.MethodPost, "/api/v1/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	reply := &turnReply{Model: req.Model}
+	var content strings.Builder
+	var event string
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if name, ok := strings.CutPrefix(line, "event: "); ok {
+			event = name
+			continue
+		}
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			reply.Content = content.String()
+			return reply, nil
+		}
+
+		if event == "error" {
+			return nil, decodeError(data)
+		}
+		var chunk httpInfra.StreamChunkResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("fragmento inválido: %w", err)
+		}
+		fmt.Fprint(out, chunk.Content)
+		content.WriteString(chunk.Content)
+		if chunk.Model != "" {
+			reply.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			reply.TotalTokens = chunk.Usage.TotalTokens
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error al leer la respuesta: %w", err)
+	}
+	return nil, errors.New("la respuesta terminó sin [DONE]")
+}
+
+// Models implementa backend
+func (b *remoteBackend) Models(ctx context.Context) ([]string, error) {
+	resp, err := b.do(ctx, http.MethodGet, "/api/v1/models", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var models httpInfra.ModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return nil, fmt.Errorf("respuesta inválida: %w", err)
+	}
+	ids := make([]string, len(models.Models))
+	for i, model := range models.Models {
+		ids[i] = model.ID
+	}
+	return ids, nil
+}
+
+// do envía la petición; un status que no sea 2xx se convierte en error
+func (b *remoteBackend) do(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, b.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if b.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+b.apiKey)
+	}
+	if b.tenant != "" {
+		req.Header.Set(httpInfra.TenantHeader, b.tenant)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo conectar con %s: %w", b.baseURL, err)
+	}
+	if resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		return nil, fmt.Errorf("%s (HTTP %d)", decodeError(string(data)), resp.StatusCode)
+	}
+	if expiresIn := resp.Header.Get(httpInfra.KeyExpiresInHeader); expiresIn != "" {
+		fmt.Printf("⚠️  La API key caduca en %s segundos\n", expiresIn)
+	}
+	return resp, nil
+}
+
+// decodeError extrae el mensaje de un ErrorResponse (o retorna el texto tal cual)
+func decodeError(data string) error {
+	var response httpInfra.ErrorResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil || response.Error == "" {
+		return errors.New(strings.TrimSpace(data))
+	}
+	if response.Type != "" {
+		return fmt.Errorf("%s: %s", response.Type, response.Error)
+	}
+	return errors.New(response.Error)
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/disk"
+	httpInfra "groq-hexagonal-api/internal/infrastructure/http"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// REPLAY (REPRODUCIR EL CORPUS)
+// ============================================================================
+//
+// `cli replay` vuelve a enviar las muestras de REPLAY_CORPUS_DIR (ver
+// domain.ReplaySample) contra una API candidata, con su modelo o con el de
+// -model, y compara cada respuesta con la original:
+//
+//	go run ./cmd/cli replay -corpus replay-corpus -url http://candidata:8080
+//	go run ./cmd/cli replay -model llama-3.1-8b-instant -limit 50
+//
+// Las peticiones se envían sin streaming, una tras otra. Es una regresión
+// que una petición que respondió bien falle ahora: con alguna, el comando
+// termina con código 1 (para usarlo en un pipeline). El texto solo se
+// compara si la respuesta original lo guardó (no en streaming).
+// ============================================================================
+
+// replayResult es el resultado de reproducir una muestra
+type replayResult struct {
+	StatusCode int
+	Model      string
+	Message    string
+	Usage      *httpInfra.UsageInfo
+	Latency    time.Duration
+
+	// Error es el mensaje de la respuesta de error (o del fallo de conexión)
+	Error string
+}
+
+// replayer envía las muestras a la API candidata
+type replayer struct {
+	baseURL string
+	apiKey  string
+	model   string
+	client  *http.Client
+}
+
+// runReplay ejecuta el subcomando replay y retorna el código de salida
+func runReplay(args []string) int {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	corpus := flags.String("corpus", "replay-corpus", "Directorio del corpus (REPLAY_CORPUS_DIR)")
+	url := flags.String("url", "http://localhost:8080", "URL de la API candidata")
+	key := flags.String("key", os.Getenv("API_KEY"), "API key de cliente (Authorization: Bearer; por defecto $API_KEY)")
+	model := flags.String("model", "", "Modelo candidato (vacío = el de cada petición)")
+	limit := flags.Int("limit", 0, "Máximo de muestras a reproducir (0 = todas)")
+	timeout := flags.Duration("timeout", 2*time.Minute, "Plazo de cada petición")
+	flags.Parse(args)
+
+	samples, err := disk.ReadReplayCorpus(*corpus)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	if *limit > 0 && len(samples) > *limit {
+		samples = samples[:*limit]
+	}
+	if len(samples) == 0 {
+		fmt.Fprintf(os.Stderr, "❌ No hay muestras en %s\n", *corpus)
+		return 1
+	}
+
+	r := &replayer{
+		baseURL: strings.TrimRight(*url, "/"),
+		apiKey:  *key,
+		model:   *model,
+		client:  &http.Client{Timeout: *timeout},
+	}
+	fmt.Printf("🔁 Reproduciendo %d muestras contra %s\n", len(samples), r.baseURL)
+
+	var summary replaySummary
+	for _, sample := range samples {
+		result := r.replay(context.Background(), sample)
+		summary.add(sample.Baseline, result)
+		printReplayResult(sample, result)
+	}
+	summary.print()
+
+	if summary.regressions > 0 {
+		return 1
+	}
+	return 0
+}
+
+// replay envía una muestra y retorna el resultado
+func (r *replayer) replay(ctx context.Context, sample domain.ReplaySample) replayResult {
+	var request map[string]any
+	if err := json.Unmarshal(sample.Request, &request); err != nil {
+		return replayResult{Error: "muestra inválida: " + err.Error()}
+	}
+	delete(request, "stream")
+	if r.model != "" {
+		request["model"] = r.model
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return replayResult{Error: err.Error()}
+	}
+
+	path := sample.Path
+	if path == "" {
+		path = "/api/v1/chat"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return replayResult{Error: err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+	if sample.TenantID != "" {
+		req.Header.Set(httpInfra.TenantHeader, sample.TenantID)
+	}
+
+	started := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return replayResult{Error: err.Error()}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	result := replayResult{StatusCode: resp.StatusCode, Latency: time.Since(started)}
+	if err != nil {
+		result.Error = "error al leer la respuesta: " + err.Error()
+		return result
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = decodeError(string(data)).Error()
+		return result
+	}
+	var response httpInfra.ChatResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		result.Error = "respuesta inválida: " + err.Error()
+		return result
+	}
+	result.Model = response.Model
+	result.Message = response.Message
+	result.Usage = response.Usage
+	return result
+}
+
+// printReplayResult imprime una línea por muestra:
+// ✓/✗ id  status  modelo  tokens  latencia  texto
+func printReplayResult(sample domain.ReplaySample, result replayResult) {
+	baseline := sample.Baseline
+	mark := "✓"
+	if isRegression(baseline, result) {
+		mark = "✗"
+	}
+
+	line := fmt.Sprintf("%s %s  %d→%s  %s→%s  tokens %s→%s  %dms→%dms",
+		mark, sample.ID,
+		baseline.StatusCode, statusText(result.StatusCode),
+		orDash(baseline.Model), orDash(result.Model),
+		baselineTokens(baseline), resultTokens(result),
+		baseline.LatencyMS, result.Latency.Milliseconds(),
+	)
+	switch {
+	case result.Error != "":
+		line += "  (" + result.Error + ")"
+	case baseline.Message != "" && baseline.Message == result.Message:
+		line += "  texto igual"
+	case baseline.Message != "":
+		line += "  texto distinto"
+	}
+	fmt.Println(line)
+}
+
+// isRegression indica si una petición que respondió bien falla ahora
+func isRegression(baseline domain.ReplayBaseline, result replayResult) bool {
+	return baseline.StatusCode == http.StatusOK && result.StatusCode != http.StatusOK
+}
+
+// ============================================================================
+// RESUMEN
+// ============================================================================
+
+// replaySummary acumula los totales de las muestras reproducidas
+type replaySummary struct {
+	samples     int
+	regressions int
+
+	// improvements son las peticiones que fallaron y ahora responden bien
+	improvements int
+
+	// Latencia de las muestras que respondieron bien las dos veces
+	compared                          int
+	baselineLatency, candidateLatency time.Duration
+
+	// Tokens de las que además informaron del uso las dos veces
+	tokenSamples                    int
+	baselineTokens, candidateTokens int
+
+	// Textos comparables (la original los guardó) e iguales
+	texts, sameTexts int
+}
+
+// add suma una muestra al resumen
+func (s *replaySummary) add(baseline domain.ReplayBaseline, result replayResult) {
+	s.samples++
+	switch {
+	case isRegression(baseline, result):
+		s.regressions++
+	case baseline.StatusCode != http.StatusOK && result.StatusCode == http.StatusOK:
+		s.improvements++
+	}
+	if baseline.StatusCode != http.StatusOK || result.StatusCode != http.StatusOK {
+		return
+	}
+
+	s.compared++
+	if baseline.Usage != nil && result.Usage != nil {
+		s.tokenSamples++
+		s.baselineTokens += baseline.Usage.TotalTokens
+		s.candidateTokens += result.Usage.TotalTokens
+	}
+	s.baselineLatency += time.Duration(baseline.LatencyMS) * time.Millisecond
+	s.candidateLatency += result.Latency
+	if baseline.Message != "" {
+		s.texts++
+		if baseline.Message == result.Message {
+			s.sameTexts++
+		}
+	}
+}
+
+// print imprime el resumen
+func (s *replaySummary) print() {
+	fmt.Printf("\n📊 %d muestras: %d regresiones, %d que antes fallaban y ahora no\n",
+		s.samples, s.regressions, s.improvements)
+	if s.compared == 0 {
+		return
+	}
+	if s.tokenSamples > 0 {
+		fmt.Printf("   • Tokens (%d muestras con uso): %d → %d\n", s.tokenSamples, s.baselineTokens, s.candidateTokens)
+	}
+	fmt.Printf("   • Latencia media (%d correctas en ambas): %dms → %dms\n", s.compared,
+		(s.baselineLatency / time.Duration(s.compared)).Milliseconds(),
+		(s.candidateLatency / time.Duration(s.compared)).Milliseconds())
+	if s.texts > 0 {
+		fmt.Printf("   • Textos iguales: %d de %d\n", s.sameTexts, s.texts)
+	}
+}
+
+// statusText es el status de la respuesta ("-" si no hubo respuesta)
+func statusText(status int) string {
+	if status == 0 {
+		return "-"
+	}
+	return fmt.Sprint(status)
+}
+
+// baselineTokens y resultTokens son los tokens totales ("-" si no se conocen)
+func baselineTokens(baseline domain.ReplayBaseline) string {
+	if baseline.Usage == nil {
+		return "-"
+	}
+	return fmt.Sprint(baseline.Usage.TotalTokens)
+}
+
+func resultTokens(result replayResult) string {
+	if result.Usage == nil {
+		return "-"
+	}
+	return fmt.Sprint(result.Usage.TotalTokens)
+}
+
+// orDash retorna "-" para los textos vacíos
+func orDash(text string) string {
+	if text == "" {
+		return "-"
+	}
+	return text
+}
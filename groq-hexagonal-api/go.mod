@@ -10,4 +10,5 @@ require (
 	github.com/gorilla/mux v1.8.1 // Router HTTP potente y flexible
 	github.com/joho/godotenv v1.5.1 // Cargar variables de entorno desde .env
 	github.com/rs/cors v1.10.1 // Manejo de CORS para APIs
+	gopkg.in/yaml.v3 v3.0.1 // Solo en los tests: lee api/openapi.yaml
 )
@@ -0,0 +1,457 @@
+// Package application - Caso de uso de gestión de API keys de cliente
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// API KEYS
+// ============================================================================
+//
+// El secreto es "gk_" más 32 bytes aleatorios en base64url. Con esa
+// entropía basta un SHA-256 para guardarlo (no hace falta un hash lento como
+// bcrypt, pensado para contraseñas que se pueden adivinar), y autenticar
+// cuesta una búsqueda por hash.
+//
+// NotifyExpirations se llama periódicamente: cada evento de caducidad se
+// marca en la clave al entregarse, así que si falla se reintenta en la
+// siguiente pasada. Con varias réplicas un evento puede llegar repetido.
+//
+// Los tokens de vida corta no se guardan: Authenticate verifica la firma y
+// lee su clave, que tiene que seguir activa.
+// ============================================================================
+
+const (
+	// apiKeySecretPrefix identifica las claves de esta API (ej: en un
+	// escáner de secretos)
+	apiKeySecretPrefix = "gk_"
+
+	// apiKeyDisplayLength es cuántos caracteres del secreto se guardan en
+	// Prefix
+	apiKeyDisplayLength = 10
+
+	// DefaultAPIKeyRotationGrace es cuánto sigue valiendo el secreto
+	// anterior tras rotar
+	DefaultAPIKeyRotationGrace = 24 * time.Hour
+
+	// DefaultAPIKeyExpiryWarning es con cuánta antelación se avisa de que
+	// una clave va a caducar
+	DefaultAPIKeyExpiryWarning = 7 * 24 * time.Hour
+
+	// DefaultTokenTTL es cuánto vale un token si no se pide otra cosa
+	DefaultTokenTTL = 15 * time.Minute
+
+	// DefaultTokenMaxTTL es lo máximo que se puede pedir para un token
+	DefaultTokenMaxTTL = time.Hour
+)
+
+// APIKeyServiceImpl implementa domain.APIKeyService
+type APIKeyServiceImpl struct {
+	repo domain.APIKeyRepository
+
+	// grace es cuánto vale el secreto anterior tras rotar (0 = nada)
+	grace time.Duration
+
+	// notifier recibe los eventos de caducidad (nil = no se emiten);
+	// warning es la antelación del aviso (0 = solo al caducar)
+	notifier domain.APIKeyNotifier
+	warning  time.Duration
+
+	// tokens firma los tokens de vida corta (nil = no se emiten);
+	// maxTokenTTL es lo máximo que puede durar uno
+	tokens      domain.TokenSigner
+	maxTokenTTL time.Duration
+
+	// now da la hora actual
+	now func() time.Time
+}
+
+// APIKeyOption configura opciones del servicio de API keys
+type APIKeyOption func(*APIKeyServiceImpl)
+
+// WithAPIKeyExpiryEvents emite los eventos de caducidad a notifier
+// warning es con cuánta antelación se avisa (0 = solo al caducar)
+func WithAPIKeyExpiryEvents(notifier domain.APIKeyNotifier, warning time.Duration) APIKeyOption {
+	return func(s *APIKeyServiceImpl) {
+		s.notifier = notifier
+		s.warning = max(warning, 0)
+	}
+}
+
+// WithScopedTokens permite emitir tokens de vida corta firmados con signer
+// maxTTL es lo máximo que puede durar un token (0 = DefaultTokenMaxTTL)
+func WithScopedTokens(signer domain.TokenSigner, maxTTL time.Duration) APIKeyOption {
+	return func(s *APIKeyServiceImpl) {
+		if maxTTL <= 0 {
+			maxTTL = DefaultTokenMaxTTL
+		}
+		s.tokens = signer
+		s.maxTokenTTL = maxTTL
+	}
+}
+
+// NewAPIKeyService crea el servicio de API keys
+// grace es cuánto sigue valiendo el secreto anterior tras una rotación
+func NewAPIKeyService(repo domain.APIKeyRepository, grace time.Duration, opts ...APIKeyOption) domain.APIKeyService {
+	if repo == nil {
+		panic("apiKeyRepo no puede ser nil")
+	}
+
+	service := &APIKeyServiceImpl{
+		repo:  repo,
+		grace: max(grace, 0),
+		now:   time.Now,
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// CreateKey crea una clave con los datos de spec
+func (s *APIKeyServiceImpl) CreateKey(ctx context.Context, spec domain.APIKeySpec) (*domain.APIKey, string, error) {
+	now := s.now()
+	if err := validateAPIKeySpec(spec, now); err != nil {
+		return nil, "", err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, "", fmt.Errorf("error al generar el ID: %w", err)
+	}
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, "", fmt.Errorf("error al generar la clave: %w", err)
+	}
+
+	key := &domain.APIKey{
+		ID:        id,
+		Name:      strings.TrimSpace(spec.Name),
+		Prefix:    secret[:apiKeyDisplayLength],
+		Hash:      hashAPIKey(secret),
+		Scopes:    spec.Scopes,
+		Tier:      spec.Tier,
+		Tenant:    strings.TrimSpace(spec.Tenant),
+		CreatedAt: now,
+		ExpiresAt: spec.ExpiresAt,
+	}
+	if err := s.repo.SaveAPIKey(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("error al guardar la API key: %w", err)
+	}
+	return key, secret, nil
+}
+
+// ListKeys retorna todas las claves
+func (s *APIKeyServiceImpl) ListKeys(ctx context.Context) ([]domain.APIKey, error) {
+	keys, err := s.repo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar las API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// DisableKey desactiva la clave (y el secreto anterior, si se rotó)
+// Desactivar una clave ya desactivada no hace nada
+func (s *APIKeyServiceImpl) DisableKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	key, err := s.repo.FindAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.DisabledAt != nil {
+		return key, nil
+	}
+
+	now := s.now()
+	key.DisabledAt = &now
+	if err := s.repo.SaveAPIKey(ctx, key); err != nil {
+		return nil, fmt.Errorf("error al guardar la API key: %w", err)
+	}
+	return key, nil
+}
+
+// RotateKey cambia el secreto de la clave
+// El anterior vale durante el plazo de gracia; si ya había uno anterior de
+// otra rotación, deja de valer. Con expiresAt la clave pasa a caducar
+// entonces y se volverá a avisar de su caducidad
+func (s *APIKeyServiceImpl) RotateKey(ctx context.Context, id string, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	now := s.now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, "", fmt.Errorf("%w: expires_at debe ser futuro", domain.ErrInvalidAPIKeySpec)
+	}
+
+	key, err := s.repo.FindAPIKey(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.DisabledAt != nil {
+		return nil, "", domain.ErrAPIKeyDisabled
+	}
+
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		return nil, "", fmt.Errorf("error al generar la clave: %w", err)
+	}
+
+	key.RotatedAt = &now
+	key.PreviousHash, key.PreviousExpiresAt = "", nil
+	if s.grace > 0 {
+		previousExpiresAt := now.Add(s.grace)
+		key.PreviousHash = key.Hash
+		key.PreviousExpiresAt = &previousExpiresAt
+	}
+	if expiresAt != nil {
+		key.ExpiresAt = expiresAt
+		key.ExpiringNotifiedAt, key.ExpiredNotifiedAt = nil, nil
+	}
+	key.Prefix = secret[:apiKeyDisplayLength]
+	key.Hash = hashAPIKey(secret)
+
+	if err := s.repo.SaveAPIKey(ctx, key); err != nil {
+		return nil, "", fmt.Errorf("error al guardar la API key: %w", err)
+	}
+	return key, secret, nil
+}
+
+// Authenticate retorna la clave activa del secreto
+// Lo que no tiene el prefijo de las claves se comprueba como token
+func (s *APIKeyServiceImpl) Authenticate(ctx context.Context, secret string) (*domain.APIKey, error) {
+	if !strings.HasPrefix(secret, apiKeySecretPrefix) {
+		if s.tokens != nil && secret != "" {
+			return s.authenticateToken(ctx, secret)
+		}
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	hash := hashAPIKey(secret)
+	key, err := s.repo.FindAPIKeyByHash(ctx, hash)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar la API key: %w", err)
+	}
+
+	now := s.now()
+	if !key.Active(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	// El secreto anterior a una rotación solo vale durante el plazo de gracia
+	if key.Hash != hash {
+		if key.PreviousHash != hash || key.PreviousExpiresAt == nil || !now.Before(*key.PreviousExpiresAt) {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		key.UsedPreviousSecret = true
+	}
+	return key, nil
+}
+
+// authenticateToken retorna la clave de un token, con los scopes del token
+func (s *APIKeyServiceImpl) authenticateToken(ctx context.Context, token string) (*domain.APIKey, error) {
+	// Un token sin scopes daría acceso completo: nunca se emite así
+	claims, err := s.tokens.VerifyToken(token)
+	if err != nil || len(claims.Scopes) == 0 {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	now := s.now()
+	if !now.Before(claims.ExpiresAt) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	// La clave se lee en cada petición: desactivarla anula sus tokens
+	key, err := s.repo.FindAPIKey(ctx, claims.KeyID)
+	if errors.Is(err, domain.ErrAPIKeyNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar la API key: %w", err)
+	}
+	if !key.Active(now) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	key.Scopes = claims.Scopes
+	key.TokenID = claims.ID
+	return key, nil
+}
+
+// IssueToken emite un token de vida corta para la clave
+// El token no dura más que la clave ni puede emitir otros tokens
+func (s *APIKeyServiceImpl) IssueToken(ctx context.Context, key *domain.APIKey, spec domain.TokenSpec) (*domain.ScopedToken, error) {
+	if s.tokens == nil {
+		return nil, fmt.Errorf("los tokens no están habilitados")
+	}
+	if key.TokenID != "" {
+		return nil, fmt.Errorf("%w: un token no puede emitir otros tokens", domain.ErrInsufficientScope)
+	}
+	if err := s.validateTokenSpec(key, spec); err != nil {
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("error al generar el ID: %w", err)
+	}
+
+	ttl := spec.TTL
+	if ttl == 0 {
+		ttl = min(DefaultTokenTTL, s.maxTokenTTL)
+	}
+	now := s.now()
+	expiresAt := now.Add(ttl)
+	if key.ExpiresAt != nil && key.ExpiresAt.Before(expiresAt) {
+		expiresAt = *key.ExpiresAt
+	}
+
+	claims := domain.TokenClaims{
+		ID:        id,
+		KeyID:     key.ID,
+		Scopes:    spec.Scopes,
+		IssuedAt:  now,
+		ExpiresAt: expiresAt,
+	}
+	token, err := s.tokens.SignToken(claims)
+	if err != nil {
+		return nil, fmt.Errorf("error al firmar el token: %w", err)
+	}
+	return &domain.ScopedToken{TokenClaims: claims, Token: token}, nil
+}
+
+// validateTokenSpec comprueba los datos de un token nuevo
+func (s *APIKeyServiceImpl) validateTokenSpec(key *domain.APIKey, spec domain.TokenSpec) error {
+	if len(spec.Scopes) == 0 {
+		return fmt.Errorf("%w: scopes es requerido", domain.ErrInvalidTokenSpec)
+	}
+	for _, scope := range spec.Scopes {
+		if !domain.IsAPIKeyScope(scope) {
+			return fmt.Errorf("%w: scope desconocido %q (admitidos: %s)",
+				domain.ErrInvalidTokenSpec, scope, strings.Join(domain.APIKeyScopes, ", "))
+		}
+		if !key.Allows(scope) {
+			return fmt.Errorf("%w: la API key no tiene el scope %q", domain.ErrInsufficientScope, scope)
+		}
+	}
+	if spec.TTL < 0 || spec.TTL > s.maxTokenTTL {
+		return fmt.Errorf("%w: la duración debe estar entre 1 y %d segundos",
+			domain.ErrInvalidTokenSpec, int64(s.maxTokenTTL.Seconds()))
+	}
+	return nil
+}
+
+// NotifyExpirations emite los eventos de caducidad pendientes
+// Sigue con el resto de claves si falla una; retorna el primer error
+func (s *APIKeyServiceImpl) NotifyExpirations(ctx context.Context) (int, error) {
+	if s.notifier == nil {
+		return 0, nil
+	}
+
+	keys, err := s.repo.ListAPIKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error al listar las API keys: %w", err)
+	}
+
+	now := s.now()
+	notified := 0
+	var firstErr error
+	for i := range keys {
+		eventType := expiryEventType(&keys[i], now, s.warning)
+		if eventType == "" {
+			continue
+		}
+		if err := s.notifyExpiry(ctx, keys[i].ID, eventType, now); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		notified++
+	}
+	return notified, firstErr
+}
+
+// notifyExpiry emite el evento de la clave y lo marca como emitido
+// La clave se vuelve a leer para no pisar una rotación hecha entretanto
+func (s *APIKeyServiceImpl) notifyExpiry(ctx context.Context, id, eventType string, now time.Time) error {
+	key, err := s.repo.FindAPIKey(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error al leer la API key: %w", err)
+	}
+	if expiryEventType(key, now, s.warning) != eventType {
+		return nil
+	}
+
+	if err := s.notifier.NotifyAPIKey(ctx, domain.APIKeyEvent{Type: eventType, Key: *key, At: now}); err != nil {
+		return fmt.Errorf("error al avisar de la caducidad de la API key %s: %w", id, err)
+	}
+
+	if eventType == domain.APIKeyEventExpired {
+		key.ExpiredNotifiedAt = &now
+	} else {
+		key.ExpiringNotifiedAt = &now
+	}
+	if err := s.repo.SaveAPIKey(ctx, key); err != nil {
+		return fmt.Errorf("error al guardar la API key: %w", err)
+	}
+	return nil
+}
+
+// expiryEventType es el evento de caducidad pendiente de la clave ("" = ninguno)
+// Una clave que caduca sin haberse avisado antes solo recibe api_key.expired
+func expiryEventType(key *domain.APIKey, now time.Time, warning time.Duration) string {
+	if key.DisabledAt != nil || key.ExpiresAt == nil {
+		return ""
+	}
+
+	switch {
+	case !now.Before(*key.ExpiresAt):
+		if key.ExpiredNotifiedAt == nil {
+			return domain.APIKeyEventExpired
+		}
+	case warning > 0 && key.ExpiresAt.Sub(now) <= warning:
+		if key.ExpiringNotifiedAt == nil {
+			return domain.APIKeyEventExpiring
+		}
+	}
+	return ""
+}
+
+// validateAPIKeySpec comprueba los datos de una clave nueva
+func validateAPIKeySpec(spec domain.APIKeySpec, now time.Time) error {
+	if strings.TrimSpace(spec.Name) == "" {
+		return fmt.Errorf("%w: name es requerido", domain.ErrInvalidAPIKeySpec)
+	}
+	for _, scope := range spec.Scopes {
+		if !domain.IsAPIKeyScope(scope) {
+			return fmt.Errorf("%w: scope desconocido %q (admitidos: %s)",
+				domain.ErrInvalidAPIKeySpec, scope, strings.Join(domain.APIKeyScopes, ", "))
+		}
+	}
+	if spec.ExpiresAt != nil && !spec.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expires_at debe ser futuro", domain.ErrInvalidAPIKeySpec)
+	}
+	return nil
+}
+
+// newAPIKeySecret genera un secreto nuevo
+func newAPIKeySecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return apiKeySecretPrefix + base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// hashAPIKey es el SHA-256 del secreto en hex (lo que se guarda)
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,49 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/jwt"
+	"groq-hexagonal-api/internal/infrastructure/memory"
+	"testing"
+	"time"
+)
+
+// VerifyToken solo comprueba el formato y la firma: la caducidad (exp) la
+// comprueba el servicio al autenticar
+func TestAuthenticateRejectsExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1760600000, 0)
+	service := NewAPIKeyService(memory.NewAPIKeyRepository(), 0,
+		WithScopedTokens(jwt.NewSigner("0123456789abcdef0123456789abcdef"), time.Hour),
+	).(*APIKeyServiceImpl)
+	service.now = func() time.Time { return now }
+
+	key, _, err := service.CreateKey(ctx, domain.APIKeySpec{Name: "app", Scopes: []string{domain.ScopeChat}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := service.IssueToken(ctx, key, domain.TokenSpec{Scopes: []string{domain.ScopeChat}, TTL: 15 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Antes de caducar vale, con los scopes del token
+	authenticated, err := service.Authenticate(ctx, token.Token)
+	if err != nil {
+		t.Fatalf("Authenticate antes de caducar: %v", err)
+	}
+	if authenticated.ID != key.ID || authenticated.TokenID != token.ID {
+		t.Errorf("Authenticate = clave %s, token %s; se esperaba %s, %s",
+			authenticated.ID, authenticated.TokenID, key.ID, token.ID)
+	}
+
+	// Justo al caducar (exp es exclusivo) y después, ya no
+	for _, at := range []time.Time{token.ExpiresAt, token.ExpiresAt.Add(time.Hour)} {
+		now = at
+		if _, err := service.Authenticate(ctx, token.Token); !errors.Is(err, domain.ErrInvalidAPIKey) {
+			t.Errorf("Authenticate en %v = %v; se esperaba ErrInvalidAPIKey", at, err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"groq-hexagonal-api/internal/domain"
+	"time"
 )
 
 // ============================================================================
@@ -16,9 +17,11 @@ import (
 // En Go, es buena práctica definir errores específicos como variables
 // Esto permite comparar errores específicos en lugar de strings
 //
+// ErrEmptyMessage y ErrEmptyModel se definen en el dominio (los handlers los
+// traducen a 400); aquí se mantienen como alias por compatibilidad
 var (
-	ErrEmptyMessage = errors.New("el mensaje no puede estar vacío")
-	ErrEmptyModel   = errors.New("el modelo no puede estar vacío")
+	ErrEmptyMessage = domain.ErrEmptyMessage
+	ErrEmptyModel   = domain.ErrEmptyModel
 	ErrAPIFailure   = errors.New("fallo al comunicarse con la API de Groq")
 )
 
@@ -30,13 +33,200 @@ var (
 // Nota: No necesitamos declarar explícitamente que implementa la interfaz
 // Go lo detecta automáticamente si tiene los métodos correctos
 type ChatServiceImpl struct {
-	// groqRepo es la dependencia inyectada (puerto secundario)
+	// llmRepo es la dependencia inyectada (puerto secundario)
 	// Es una interfaz, no una implementación concreta
 	// Esto permite flexibilidad y testing
-	groqRepo domain.GroqRepository
+	llmRepo domain.LLMRepository
 	
 	// defaultModel es el modelo a usar si no se especifica uno
 	defaultModel string
+	
+	// stopPolicy define las secuencias de parada del operador
+	stopPolicy StopPolicy
+	
+	// outputGuard limita la longitud de las respuestas por tenant
+	outputGuard OutputGuard
+	
+	// localePolicy detecta el idioma y aplica los valores por defecto del idioma
+	localePolicy LocalePolicy
+	
+	// defaultSystemPrompt se usa cuando el cliente no envía prompt de sistema
+	defaultSystemPrompt string
+	
+	// tenantPrompts contiene las instrucciones obligatorias de cada tenant
+	tenantPrompts TenantPromptPolicy
+	
+	// tenantModels son el modelo por defecto y los rangos de parámetros de
+	// cada tenant
+	tenantModels TenantModelPolicy
+	
+	// aliases sustituye los modelos retirados por su reemplazo (nil = desactivado)
+	aliases *ModelAliasCatalog
+	
+	// personas son las plantillas de prompt con nombre (nil = desactivadas)
+	personas *PersonaCatalog
+	
+	// pricing son los precios por modelo para estimar costes (dry run y
+	// cost_usd del uso de tokens)
+	pricing map[string]domain.ModelPrice
+	
+	// providerModels son los proveedores que se pueden pedir por petición
+	// (campo "provider") y el modelo por defecto de cada uno
+	// nil = sin comprobación previa (el enrutador rechaza los desconocidos)
+	providerModels map[string]string
+	
+	// hooks son los puntos de extensión del despliegue (nil = ninguno)
+	hooks *HookRegistry
+	
+	// responseCache guarda las respuestas sin streaming (nil = desactivada)
+	responseCache *ResponseCachePolicy
+	
+	// settings aporta el modelo por defecto vigente (nil = el del constructor)
+	settings domain.SettingsSource
+	
+	// contextGuard comprueba que la petición cabe en la ventana del modelo
+	contextGuard ContextGuard
+	
+	// grounding configura la verificación de las respuestas que la piden
+	grounding GroundingPolicy
+	
+	// costRouting elige el modelo por calidad y coste (ver cost_routing.go)
+	costRouting CostRouting
+	
+	// compaction resume el historial antes de llegar a la ventana de
+	// contexto (ver history_compaction.go)
+	compaction HistoryCompaction
+}
+
+// Option configura aspectos opcionales del servicio (patrón "functional options")
+// Permite añadir configuración sin romper la firma de NewChatService
+type Option func(*ChatServiceImpl)
+
+// WithStopPolicy configura las secuencias de parada globales y por modelo
+func WithStopPolicy(policy StopPolicy) Option {
+	return func(s *ChatServiceImpl) {
+		s.stopPolicy = policy
+	}
+}
+
+// WithOutputGuard configura el límite de longitud de las respuestas
+func WithOutputGuard(guard OutputGuard) Option {
+	return func(s *ChatServiceImpl) {
+		s.outputGuard = guard
+	}
+}
+
+// WithLocalePolicy activa la detección de idioma y los perfiles por idioma
+func WithLocalePolicy(policy LocalePolicy) Option {
+	return func(s *ChatServiceImpl) {
+		s.localePolicy = policy
+	}
+}
+
+// WithDefaultSystemPrompt configura el prompt de sistema por defecto
+func WithDefaultSystemPrompt(prompt string) Option {
+	return func(s *ChatServiceImpl) {
+		s.defaultSystemPrompt = prompt
+	}
+}
+
+// WithTenantPrompts configura el prompt de sistema obligatorio por tenant
+func WithTenantPrompts(policy TenantPromptPolicy) Option {
+	return func(s *ChatServiceImpl) {
+		s.tenantPrompts = policy
+	}
+}
+
+// WithTenantModels configura el modelo por defecto y los rangos de
+// parámetros por tenant (ver tenant_limits.go)
+func WithTenantModels(policy TenantModelPolicy) Option {
+	return func(s *ChatServiceImpl) {
+		s.tenantModels = policy
+	}
+}
+
+// WithModelAliases activa la sustitución de modelos retirados
+func WithModelAliases(catalog *ModelAliasCatalog) Option {
+	return func(s *ChatServiceImpl) {
+		s.aliases = catalog
+	}
+}
+
+// WithPersonas activa las plantillas de prompt con nombre (campo "persona")
+func WithPersonas(catalog *PersonaCatalog) Option {
+	return func(s *ChatServiceImpl) {
+		s.personas = catalog
+	}
+}
+
+// WithModelPricing configura los precios por modelo (USD por millón de tokens)
+func WithModelPricing(pricing map[string]domain.ModelPrice) Option {
+	return func(s *ChatServiceImpl) {
+		s.pricing = pricing
+	}
+}
+
+// WithProviders permite elegir el proveedor por petición
+// defaultModels son los proveedores configurados y el modelo por defecto de cada uno
+func WithProviders(defaultModels map[string]string) Option {
+	return func(s *ChatServiceImpl) {
+		s.providerModels = defaultModels
+	}
+}
+
+// WithHooks registra los hooks de peticiones y respuestas del despliegue
+func WithHooks(hooks *HookRegistry) Option {
+	return func(s *ChatServiceImpl) {
+		s.hooks = hooks
+	}
+}
+
+// WithResponseCache activa la caché de respuestas (ver response_cache.go)
+// Es un puntero porque la política lleva contadores compartidos con el panel
+// de administración
+func WithResponseCache(policy *ResponseCachePolicy) Option {
+	return func(s *ChatServiceImpl) {
+		s.responseCache = policy
+	}
+}
+
+// WithContextGuard activa la comprobación de la ventana de contexto
+func WithContextGuard(guard ContextGuard) Option {
+	return func(s *ChatServiceImpl) {
+		s.contextGuard = guard
+	}
+}
+
+// WithHistoryCompaction activa el resumen automático del historial
+// Las ventanas de contexto son las de WithContextGuard
+func WithHistoryCompaction(compaction HistoryCompaction) Option {
+	return func(s *ChatServiceImpl) {
+		s.compaction = compaction
+	}
+}
+
+// WithGroundingCheck configura la verificación de las respuestas (ver grounding.go)
+// Sin esta opción se verifica igual, con el mismo modelo de la respuesta
+func WithGroundingCheck(policy GroundingPolicy) Option {
+	return func(s *ChatServiceImpl) {
+		s.grounding = policy
+	}
+}
+
+// WithCostRouting activa el enrutado por coste (campo "quality")
+// Los precios son los de WithModelPricing
+func WithCostRouting(routing CostRouting) Option {
+	return func(s *ChatServiceImpl) {
+		s.costRouting = routing
+	}
+}
+
+// WithRuntimeSettings lee el modelo por defecto en cada petición, de modo
+// que una recarga de la configuración lo cambia sin reiniciar
+func WithRuntimeSettings(settings domain.SettingsSource) Option {
+	return func(s *ChatServiceImpl) {
+		s.settings = settings
+	}
 }
 
 // ============================================================================
@@ -49,23 +239,31 @@ type ChatServiceImpl struct {
 // Parámetros:
 //   - repo: implementación del repositorio (inyección de dependencia)
 //   - defaultModel: modelo por defecto a usar
+//   - opts: configuración opcional (ver Option)
 //
 // Retorna:
 //   - domain.ChatService: retornamos la interfaz, no la implementación
 //     Esto es una buena práctica: "programa contra interfaces, no implementaciones"
-func NewChatService(repo domain.GroqRepository, defaultModel string) domain.ChatService {
+func NewChatService(repo domain.LLMRepository, defaultModel string, opts ...Option) domain.ChatService {
 	// Validación básica
 	if repo == nil {
 		// panic() es como throw en otros lenguajes, pero solo para errores irrecuperables
-		panic("groqRepo no puede ser nil")
+		panic("llmRepo no puede ser nil")
 	}
 	
 	// Retornamos un puntero a la struct
 	// El & crea un puntero, similar a "new" en otros lenguajes
-	return &ChatServiceImpl{
-		groqRepo:     repo,
+	service := &ChatServiceImpl{
+		llmRepo:      repo,
 		defaultModel: defaultModel,
 	}
+	
+	// Aplicar cada opción sobre el servicio
+	for _, opt := range opts {
+		opt(service)
+	}
+	
+	return service
 }
 
 // ============================================================================
@@ -78,6 +276,7 @@ func NewChatService(repo domain.GroqRepository, defaultModel string) domain.Chat
 //   - ctx: contexto para cancelaciones y timeouts
 //   - message: mensaje del usuario
 //   - model: modelo de IA a usar (vacío = usar default)
+//   - opts: parámetros opcionales (temperatura, max_tokens, stop)
 //
 // Retorna:
 //   - *domain.ChatResponse: respuesta del modelo
@@ -91,75 +290,122 @@ func (s *ChatServiceImpl) SendMessage(
 	ctx context.Context,
 	message string,
 	model string,
+	opts domain.MessageOptions,
 ) (*domain.ChatResponse, error) {
 	// ========================================================================
-	// 1. VALIDACIÓN DE ENTRADA
+	// 1. VALIDACIÓN Y CONSTRUCCIÓN DE LA PETICIÓN
 	// ========================================================================
 	
-	// Validar que el mensaje no esté vacío
-	// strings.TrimSpace() elimina espacios al inicio y final
-	if len(message) == 0 {
-		// Retornamos nil y un error
-		// En Go, siempre retornas (nil, error) o (valor, nil)
-		return nil, ErrEmptyMessage
-	}
-	
-	// Si no se especificó modelo, usar el default
-	if model == "" {
-		model = s.defaultModel
-	}
-	
-	// Validar que tengamos un modelo
-	if model == "" {
-		return nil, ErrEmptyModel
+	// buildRequest es compartido con StreamMessage
+	prepared, err := s.buildRequest(ctx, message, model, opts, false)
+	if err != nil {
+		return nil, err
 	}
 	
 	// ========================================================================
-	// 2. CONSTRUCCIÓN DE LA PETICIÓN
+	// 2. LLAMADA AL REPOSITORIO (puerto secundario)
 	// ========================================================================
 	
-	// Crear el mensaje del usuario
-	userMessage := domain.NewChatMessage("user", message)
+	ctx = withProvider(ctx, opts)
 	
-	// Crear la petición de chat con un slice de mensajes
-	// []domain.ChatMessage{...} crea un slice con un elemento
-	request := domain.NewChatRequest(model, []domain.ChatMessage{userMessage})
+	// Si la petición ya tiene respuesta en la caché, no se llama al modelo
+	var cacheKey string
+	var cacheStatus domain.CacheStatus
+	var cached *domain.CachedResponse
+	var semanticQuery *semanticQuery
+	var similarity float64
+	if s.responseCache.enabled() {
+		cacheKey = s.responseCache.cacheKey(ctx, prepared.request)
+		cached, cacheStatus = s.responseCache.lookup(ctx, cacheKey)
+		
+		// Sin la misma petición, quizá haya una pregunta parecida
+		if cacheStatus == domain.CacheMiss {
+			var match *domain.SemanticMatch
+			semanticQuery, match = s.responseCache.lookupSemantic(ctx, prepared.request)
+			if match != nil {
+				cached, cacheStatus, similarity = &match.Entry, domain.CacheHit, match.Similarity
+			}
+		}
+	}
 	
-	// Opcionalmente, podemos configurar parámetros adicionales
-	// Descomentar estas líneas si quieres personalizar:
-	// request.SetTemperature(0.7)
-	// request.SetMaxTokens(1000)
+	var response *domain.ChatResponse
+	if cached != nil {
+		response = &cached.Response
+	} else {
+		// Llamamos al repositorio pasando el contexto y la petición
+		// El repositorio se encarga de los detalles de comunicación HTTP
+		response, err = s.llmRepo.CreateChatCompletion(ctx, prepared.request)
+		
+		// Si el modelo fue retirado y tiene reemplazo, reintentar una vez con él
+		if s.remapDecommissioned(ctx, &prepared, err) {
+			response, err = s.llmRepo.CreateChatCompletion(ctx, prepared.request)
+		}
+		
+		// ====================================================================
+		// 3. MANEJO DE ERRORES
+		// ====================================================================
+		
+		// Verificar si hubo error
+		if err != nil {
+			// fmt.Errorf() crea un nuevo error wrapeando el original
+			// %w es el verbo especial para wrap errors (Go 1.13+)
+			// Esto permite usar errors.Is() y errors.As() después
+			return nil, fmt.Errorf("error al obtener respuesta del modelo: %w", err)
+		}
+		
+		// ====================================================================
+		// 4. VALIDACIÓN DE RESPUESTA
+		// ====================================================================
+		
+		// Verificar que la respuesta tenga contenido
+		// len() obtiene la longitud de un slice
+		if len(response.Choices) == 0 {
+			return nil, errors.New("la respuesta no contiene opciones")
+		}
+		
+		// Se guarda antes de las políticas de salida (se aplican al servirla)
+		if cacheKey != "" {
+			s.responseCache.store(ctx, cacheKey, *response)
+			s.responseCache.storeSemantic(ctx, semanticQuery, *response)
+		}
+	}
 	
 	// ========================================================================
-	// 3. LLAMADA AL REPOSITORIO (puerto secundario)
+	// 5. POLÍTICAS DE SALIDA
 	// ========================================================================
 	
-	// Llamamos al repositorio pasando el contexto y la petición
-	// El repositorio se encarga de los detalles de comunicación HTTP
-	response, err := s.groqRepo.CreateChatCompletion(ctx, request)
-	
-	// ========================================================================
-	// 4. MANEJO DE ERRORES
-	// ========================================================================
+	// Metadatos calculados al construir la petición (ej: idioma detectado)
+	response.Meta = prepared.meta
+	response.Meta.CacheStatus = cacheStatus
+	if cached != nil {
+		response.Meta.CacheHit = true
+		response.Meta.CacheAge = cached.Age(time.Now())
+		response.Meta.CacheSimilarity = similarity
+	}
 	
-	// Verificar si hubo error
-	if err != nil {
-		// fmt.Errorf() crea un nuevo error wrapeando el original
-		// %w es el verbo especial para wrap errors (Go 1.13+)
-		// Esto permite usar errors.Is() y errors.As() después
-		return nil, fmt.Errorf("error al obtener respuesta de Groq: %w", err)
+	// Recortar la respuesta si supera el límite del tenant
+	tenantID := domain.TenantFromContext(ctx)
+	content, truncated := s.outputGuard.Apply(tenantID, response.GetResponseContent())
+	if truncated {
+		response.SetResponseContent(content)
+		response.Meta.TruncatedByPolicy = true
+		response.Meta.AddWarning(domain.WarningOutputTruncated,
+			"la respuesta se ha recortado por el límite de longitud configurado")
 	}
 	
-	// ========================================================================
-	// 5. VALIDACIÓN DE RESPUESTA
-	// ========================================================================
+	// Coste estimado con los precios configurados (ver domain/pricing.go)
+	if cost, ok := domain.UsageCost(s.pricing, prepared.request.Model, response.Usage); ok {
+		response.Usage.CostUSD = cost
+	}
 	
-	// Verificar que la respuesta tenga contenido
-	// len() obtiene la longitud de un slice
-	if len(response.Choices) == 0 {
-		return nil, errors.New("la respuesta no contiene opciones")
+	// Verificación de la respuesta ya recortada (solo si se pidió)
+	if opts.Grounding != nil {
+		s.checkGrounding(ctx, message, response, prepared.request, opts)
 	}
 	
+	// Hooks de respuesta del despliegue (facturación, auditoría...)
+	s.hooks.afterResponse(ctx, prepared.request, response)
+	
 	// ========================================================================
 	// 6. RETORNO EXITOSO
 	// ========================================================================
@@ -173,7 +419,7 @@ func (s *ChatServiceImpl) SendMessage(
 // Este método es más simple porque solo delega al repositorio
 func (s *ChatServiceImpl) GetAvailableModels(ctx context.Context) (*domain.ModelsResponse, error) {
 	// Llamar directamente al repositorio
-	models, err := s.groqRepo.ListModels(ctx)
+	models, err := s.llmRepo.ListModels(ctx)
 	
 	// Propagar el error si existe
 	if err != nil {
@@ -185,6 +431,361 @@ func (s *ChatServiceImpl) GetAvailableModels(ctx context.Context) (*domain.Model
 	return models, nil
 }
 
+// StreamMessage implementa el caso de uso de enviar un mensaje en streaming
+//
+// Valida y construye la petición igual que SendMessage, pero retorna un
+// domain.ChatStream en lugar de la respuesta completa. El handler HTTP
+// consume el flujo y reenvía cada fragmento al cliente en cuanto llega.
+//
+// Nota: la guardia de longitud (OutputGuard) solo se aplica a respuestas
+// completas; en streaming el texto ya se ha enviado cuando se conoce su longitud.
+func (s *ChatServiceImpl) StreamMessage(
+	ctx context.Context,
+	message string,
+	model string,
+	opts domain.MessageOptions,
+) (domain.ChatStream, error) {
+	prepared, err := s.buildRequest(ctx, message, model, opts, false)
+	if err != nil {
+		return nil, err
+	}
+	prepared.request.Stream = true
+	
+	ctx = withProvider(ctx, opts)
+	stream, err := s.llmRepo.CreateChatCompletionStream(ctx, prepared.request)
+	if s.remapDecommissioned(ctx, &prepared, err) {
+		stream, err = s.llmRepo.CreateChatCompletionStream(ctx, prepared.request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al iniciar el streaming: %w", err)
+	}
+	
+	// El último fragmento lleva el uso de tokens: se le añade el coste
+	if price, ok := s.pricing[prepared.request.Model]; ok {
+		stream = &pricedStream{ChatStream: stream, price: price}
+	}
+	
+	// Los hooks de respuesta reciben el texto acumulado al cerrar el flujo
+	return s.hooks.wrapStream(ctx, prepared.request, stream), nil
+}
+
+// redactedTenantPrompt sustituye a las instrucciones del tenant en un dry run
+const redactedTenantPrompt = "[instrucciones del tenant]"
+
+// DryRun implementa el caso de uso de simular un mensaje
+//
+// Usa el mismo buildRequest que SendMessage, así que valida y transforma la
+// petición exactamente igual; solo se omite la llamada a Groq.
+func (s *ChatServiceImpl) DryRun(
+	ctx context.Context,
+	message string,
+	model string,
+	opts domain.MessageOptions,
+) (*domain.DryRunResult, error) {
+	prepared, err := s.buildRequest(ctx, message, model, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	
+	request := prepared.request
+	promptTokens := domain.EstimatePromptTokens(request)
+	
+	var sections []domain.PromptSection
+	if prepared.sections != nil {
+		sections = domain.MeasurePromptSections(request, prepared.sections)
+	}
+	
+	// Las instrucciones del tenant cuentan para los tokens, pero el cliente
+	// no debe verlas. buildRequest siempre las pone en el primer mensaje.
+	// Se copia el slice para no modificar el array de la petición preparada
+	if s.tenantPrompts.PrefixFor(domain.TenantFromContext(ctx)) != "" {
+		request.Messages = append([]domain.ChatMessage(nil), request.Messages...)
+		request.Messages[0].Content = redactedTenantPrompt
+	}
+	
+	return &domain.DryRunResult{
+		Request:               request,
+		Meta:                  prepared.meta,
+		EstimatedPromptTokens: promptTokens,
+		Cost:                  estimateCost(s.pricing, request.Model, promptTokens, opts.MaxTokens),
+		Sections:              sections,
+		ContextWindow:         s.contextGuard.Windows[request.Model],
+	}, nil
+}
+
+// ============================================================================
+// MÉTODOS PRIVADOS
+// ============================================================================
+
+// preparedRequest es el resultado de buildRequest
+// Además de la petición, lleva los metadatos que se añadirán a la respuesta
+type preparedRequest struct {
+	request domain.ChatRequest
+	meta    domain.ResponseMeta
+	
+	// sections son las secciones del prompt (nil si ya no cuadran)
+	sections []domain.PromptSection
+}
+
+// buildRequest valida la entrada y construye la petición para Groq
+// Es la parte común de SendMessage, StreamMessage y DryRun (dryRun evita
+// las llamadas al modelo, como la del resumen del historial)
+func (s *ChatServiceImpl) buildRequest(
+	ctx context.Context,
+	message string,
+	model string,
+	opts domain.MessageOptions,
+	dryRun bool,
+) (preparedRequest, error) {
+	// ========================================================================
+	// 1. VALIDACIÓN DE ENTRADA
+	// ========================================================================
+	
+	// Validar que el mensaje no esté vacío
+	// Excepción: tras devolver resultados de herramientas (role "tool") el
+	// modelo continúa sin un mensaje nuevo del usuario
+	if len(message) == 0 && !endsWithToolResult(opts.History) {
+		// Retornamos el valor cero del struct y un error
+		// En Go, siempre retornas (cero, error) o (valor, nil)
+		return preparedRequest{}, ErrEmptyMessage
+	}
+	
+	// La persona aporta los valores que el cliente no envía
+	// opts es una copia: modificarla no afecta al llamador
+	var personaPrompt string
+	if opts.Persona != "" {
+		persona, err := s.personas.Get(opts.Persona)
+		if err != nil {
+			return preparedRequest{}, err
+		}
+		if model == "" {
+			model = persona.Model
+		}
+		if opts.SystemPrompt == "" {
+			personaPrompt = persona.SystemPrompt
+		}
+		if opts.Temperature == nil {
+			opts.Temperature = persona.Temperature
+		}
+	}
+	
+	// El modelo por defecto del tenant sustituye a los globales (el de
+	// DEFAULT_QUALITY, el del idioma y DEFAULT_MODEL), no a lo que elige el
+	// cliente: model, persona, quality o provider
+	tenantID := domain.TenantFromContext(ctx)
+	if model == "" && opts.Quality == "" && opts.Provider == "" {
+		model = s.tenantModels.DefaultModel(tenantID)
+	}
+	
+	// Detectar el idioma (si está activado) para aplicar su perfil
+	language, locale := s.localePolicy.Resolve(message)
+	
+	// Un operador puede forzar el modelo para depurar (X-Debug-Overrides)
+	if forced := domain.DebugOverridesFromContext(ctx).ForceModel; forced != "" {
+		model = forced
+	}
+	
+	// El proveedor elegido por el cliente debe estar configurado, y sus
+	// modelos tienen otros nombres: sin modelo, se usa el suyo por defecto
+	var providerModel string
+	if opts.Provider != "" && s.providerModels != nil {
+		var ok bool
+		providerModel, ok = s.providerModels[opts.Provider]
+		if !ok {
+			return preparedRequest{}, fmt.Errorf("%w: %s", domain.ErrUnknownProvider, opts.Provider)
+		}
+	}
+	
+	// Sin modelo, una calidad elige el más barato del catálogo que la cumple
+	var routedQuality string
+	if quality := s.costRouting.qualityFor(opts); model == "" && quality != "" {
+		routed, err := s.costRouting.Pick(quality, opts.Provider, requiredCapabilities(opts), s.pricing)
+		if err != nil {
+			return preparedRequest{}, err
+		}
+		model = routed
+		routedQuality = quality
+	}
+	if model == "" {
+		model = providerModel
+	}
+	
+	// Si no se especificó modelo, usar el del idioma o el default
+	if model == "" {
+		model = locale.Model
+	}
+	if model == "" {
+		model = s.currentDefaultModel()
+	}
+	
+	// Validar que tengamos un modelo
+	if model == "" {
+		return preparedRequest{}, ErrEmptyModel
+	}
+	
+	// Si ya sabemos que el modelo está retirado, usar directamente su reemplazo
+	meta := domain.ResponseMeta{DetectedLanguage: language, RoutedQuality: routedQuality}
+	if replacement, ok := s.aliases.Remap(ctx, model); ok {
+		meta.RemappedFrom = model
+		meta.AddWarning(domain.WarningModelRemapped, remappedMessage(model, replacement))
+		model = replacement
+	}
+	
+	// ========================================================================
+	// 2. CONSTRUCCIÓN DE LA PETICIÓN
+	// ========================================================================
+	
+	// Crear la petición de chat (todavía sin mensajes)
+	request := domain.NewChatRequest(model, nil)
+	
+	// Prompt de sistema: el del cliente o, si no envía ninguno, el de la
+	// persona o el por defecto
+	// Si el historial ya trae uno (ej: el de una conversación), no se añade otro
+	systemPrompt := opts.SystemPrompt
+	if systemPrompt == "" && personaPrompt == "" && !startsWithSystem(opts.History) {
+		systemPrompt = s.defaultSystemPrompt
+	}
+	
+	// Los mensajes se montan por secciones, en orden (ver prompt_pipeline.go)
+	// El prefijo del tenant va SIEMPRE primero: nada de lo que envíe el
+	// cliente (system_prompt, historial) puede ir antes ni reemplazarlo
+	prompt := assembledPrompt{parts: promptParts{
+		tenantPrefix:  s.tenantPrompts.PrefixFor(tenantID),
+		systemPrompt:  systemPrompt,
+		personaPrompt: personaPrompt,
+		localePrompt:  locale.SystemPrompt,
+		examples:      opts.Examples,
+		documents:     opts.Documents,
+		history:       opts.History,
+		message:       message,
+		images:        opts.Images,
+	}}
+	prompt.sections = assemblePrompt(&request, prompt.parts)
+	history := findPromptSection(prompt.sections, domain.PromptSectionHistory)
+	
+	// Parámetros opcionales enviados por el cliente
+	if opts.Temperature != nil {
+		request.SetTemperature(*opts.Temperature)
+	}
+	if opts.MaxTokens > 0 {
+		request.SetMaxTokens(opts.MaxTokens)
+	}
+	opts.Sampling.ApplyTo(&request)
+	
+	// Los rangos del tenant se aplican a los parámetros ya resueltos
+	clamped, err := s.tenantModels.Apply(tenantID, &request)
+	if err != nil {
+		return preparedRequest{}, err
+	}
+	for _, change := range clamped {
+		meta.AddWarning(domain.WarningParameterClamped, "ajustado al límite del tenant: "+change)
+	}
+	
+	// Mezclar las secuencias de parada del operador con las del cliente
+	stop, dropped := s.stopPolicy.Merge(model, opts.Stop)
+	request.Stop = stop
+	if dropped > 0 {
+		meta.AddWarning(domain.WarningStopSequencesDropped, fmt.Sprintf(
+			"se han descartado %d secuencias de parada (máximo %d)", dropped, domain.MaxStopSequences))
+	}
+	
+	// Herramientas: se reenvían sin modificar
+	request.Tools = opts.Tools
+	request.ToolChoice = opts.ToolChoice
+	
+	// Cerca de la ventana de contexto, el historial antiguo se resume
+	s.compactHistory(ctx, &request, history.Start, history.End, opts, &meta, dryRun)
+	prompt.sections = fitPromptSections(prompt.sections, len(request.Messages))
+	
+	// Comprobar la ventana de contexto antes de llamar al modelo: mejor un
+	// 413 claro (o un prompt recortado) que un error opaco de Groq
+	trimmed, err := s.contextGuard.Apply(&request, &prompt)
+	if err != nil {
+		return preparedRequest{}, err
+	}
+	if n := trimmed[domain.PromptSectionDocuments]; n > 0 {
+		meta.AddWarning(domain.WarningDocumentsTruncated, fmt.Sprintf(
+			"se han descartado los últimos documentos de contexto (%d) para no superar la ventana de contexto", n))
+	}
+	if n := trimmed[domain.PromptSectionExamples]; n > 0 {
+		meta.AddWarning(domain.WarningExamplesTruncated, fmt.Sprintf(
+			"se han descartado los últimos ejemplos (%d) para no superar la ventana de contexto", n))
+	}
+	if n := trimmed[domain.PromptSectionHistory]; n > 0 {
+		meta.AddWarning(domain.WarningHistoryTruncated, fmt.Sprintf(
+			"se ha descartado el historial más antiguo (%d mensajes) para no superar la ventana de contexto", n))
+	}
+	
+	// ========================================================================
+	// 3. HOOKS DEL DESPLIEGUE
+	// ========================================================================
+	
+	// Van al final: ven (y pueden cambiar) la petición tal como saldría
+	if err := s.hooks.beforeRequest(ctx, &request); err != nil {
+		return preparedRequest{}, err
+	}
+	
+	return preparedRequest{
+		request:  request,
+		meta:     meta,
+		sections: fitPromptSections(prompt.sections, len(request.Messages)),
+	}, nil
+}
+
+// currentDefaultModel retorna el modelo por defecto vigente
+// Una recarga sin DEFAULT_MODEL no deja al servicio sin modelo
+func (s *ChatServiceImpl) currentDefaultModel() string {
+	if s.settings != nil {
+		if model := s.settings.Settings().DefaultModel; model != "" {
+			return model
+		}
+	}
+	return s.defaultModel
+}
+
+// remapDecommissioned comprueba si err indica un modelo retirado con reemplazo
+// Si es así, cambia el modelo de la petición y retorna true para reintentar
+func (s *ChatServiceImpl) remapDecommissioned(ctx context.Context, prepared *preparedRequest, err error) bool {
+	if !errors.Is(err, domain.ErrModelDecommissioned) {
+		return false
+	}
+	
+	replacement, ok := s.aliases.Decommissioned(ctx, prepared.request.Model)
+	if !ok || replacement == prepared.request.Model {
+		return false
+	}
+	
+	prepared.meta.RemappedFrom = prepared.request.Model
+	prepared.meta.AddWarning(domain.WarningModelRemapped, remappedMessage(prepared.request.Model, replacement))
+	prepared.request.Model = replacement
+	return true
+}
+
+// remappedMessage es el aviso de que se usó el reemplazo de un modelo retirado
+func remappedMessage(model, replacement string) string {
+	return fmt.Sprintf("el modelo %s está retirado; se ha usado %s", model, replacement)
+}
+
+// withProvider fija en el contexto el proveedor pedido por el cliente
+// Sin proveedor, el contexto no cambia (se usa el por defecto)
+func withProvider(ctx context.Context, opts domain.MessageOptions) context.Context {
+	if opts.Provider == "" {
+		return ctx
+	}
+	return domain.WithProvider(ctx, opts.Provider)
+}
+
+// startsWithSystem indica si el historial empieza con un mensaje de sistema
+func startsWithSystem(history []domain.ChatMessage) bool {
+	return len(history) > 0 && history[0].Role == "system"
+}
+
+// endsWithToolResult indica si el último mensaje del historial es el
+// resultado de una herramienta
+func endsWithToolResult(history []domain.ChatMessage) bool {
+	return len(history) > 0 && history[len(history)-1].Role == "tool"
+}
+
 // ============================================================================
 // CONCEPTOS CLAVE DE GO EXPLICADOS:
 // ============================================================================
@@ -221,7 +822,7 @@ func (s *ChatServiceImpl) GetAvailableModels(ctx context.Context) (*domain.Model
 //
 // 7. NAMING CONVENTIONS:
 //    - Exportado (público): empieza con mayúscula (ChatService)
-//    - No exportado (privado): empieza con minúscula (groqRepo)
+//    - No exportado (privado): empieza con minúscula (llmRepo)
 //
 // 8. ARQUITECTURA HEXAGONAL - CAPA DE APLICACIÓN:
 //    - Implementa los casos de uso del negocio
@@ -241,7 +842,7 @@ func (s *ChatServiceImpl) GetAvailableModels(ctx context.Context) (*domain.Model
 //
 // // Usar el servicio
 // ctx := context.Background()
-// response, err := chatService.SendMessage(ctx, "Hola, ¿cómo estás?", "")
+// response, err := chatService.SendMessage(ctx, "Hola, ¿cómo estás?", "", domain.MessageOptions{})
 // if err != nil {
 //     log.Fatal(err)
 // }
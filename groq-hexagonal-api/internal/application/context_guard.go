@@ -0,0 +1,126 @@
+// Package application - Ventana de contexto de los modelos
+package application
+
+import (
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// GUARDIA DE LA VENTANA DE CONTEXTO
+// ============================================================================
+//
+// Si una petición no cabe en la ventana de contexto del modelo, Groq la
+// rechaza con un 400 poco claro (o, en algunos modelos, recorta sin avisar).
+// ContextGuard hace la comprobación ANTES de llamar al modelo, con la
+// estimación de domain.EstimatePromptTokens más los max_tokens pedidos:
+//
+//   - Sin Truncate, la petición se rechaza con domain.ErrContextTooLong (413)
+//     indicando los tokens estimados y la ventana del modelo
+//   - Con Truncate, se recortan las secciones de ShrinkOrder, una tras otra,
+//     hasta que quepa (con un aviso en la respuesta): de los documentos y los
+//     ejemplos se descartan los últimos, del historial los turnos más
+//     antiguos. Las instrucciones (tenant, sistema, persona, idioma) y el
+//     mensaje actual no se descartan nunca: si aun así no cabe, se rechaza
+//     igual
+//
+// Los modelos sin ventana conocida no se comprueban.
+// ============================================================================
+
+// ContextGuard define las ventanas de contexto y qué hacer al superarlas
+type ContextGuard struct {
+	// Windows son las ventanas de contexto por modelo, en tokens
+	// (nil = guardia desactivada; 0 = sin comprobación para ese modelo)
+	Windows map[string]int
+
+	// Truncate recorta el prompt en lugar de rechazar la petición
+	Truncate bool
+
+	// ShrinkOrder son las secciones que se recortan, en orden (ver
+	// domain.ShrinkablePromptSections; nil = solo el historial)
+	ShrinkOrder []string
+}
+
+// promptTrim es cuánto se ha recortado de cada sección: mensajes del
+// historial, documentos o ejemplos
+type promptTrim map[string]int
+
+// Apply comprueba que la petición cabe en la ventana del modelo
+// prompt son las secciones con las que se montó: se ajustan a lo recortado
+// Retorna cuánto se ha recortado de cada sección
+func (g ContextGuard) Apply(request *domain.ChatRequest, prompt *assembledPrompt) (promptTrim, error) {
+	window := g.Windows[request.Model]
+	if window <= 0 {
+		return nil, nil
+	}
+
+	// Los tokens de la respuesta salen de la misma ventana
+	budget := window - request.MaxTokens
+	tokens := domain.EstimatePromptTokens(*request)
+	if tokens <= budget {
+		return nil, nil
+	}
+	if !g.Truncate {
+		return nil, contextTooLongError(request, tokens, window)
+	}
+
+	trimmed := promptTrim{}
+	for _, section := range g.shrinkOrder() {
+		if removed := prompt.shrink(request, section, budget); removed > 0 {
+			trimmed[section] = removed
+		}
+		if tokens = domain.EstimatePromptTokens(*request); tokens <= budget {
+			return trimmed, nil
+		}
+	}
+	return trimmed, contextTooLongError(request, tokens, window)
+}
+
+// shrinkOrder son las secciones que se recortan, en orden
+func (g ContextGuard) shrinkOrder() []string {
+	if g.ShrinkOrder == nil {
+		return []string{domain.PromptSectionHistory}
+	}
+	return g.ShrinkOrder
+}
+
+// dropOldestTurn descarta el turno más antiguo del historial: el primer
+// mensaje que no es de sistema y los que le responden, hasta el siguiente
+// mensaje del usuario. Así no quedan respuestas sin su pregunta ni, sobre
+// todo, resultados de herramientas sin la llamada que los originó (Groq los
+// rechaza)
+// Retorna cuántos mensajes se han descartado (0 = nada que descartar)
+func dropOldestTurn(request *domain.ChatRequest) int {
+	messages := request.Messages
+	last := len(messages) - 1
+
+	for i := 0; i < last; i++ {
+		if messages[i].Role == "system" {
+			continue
+		}
+
+		end := i + 1
+		for end < last && messages[end].Role != "user" && messages[end].Role != "system" {
+			end++
+		}
+		// El último mensaje es un resultado de este turno: es el turno en curso
+		if end == last && messages[last].Role == "tool" {
+			return 0
+		}
+
+		// messages[:i:i] fuerza una copia: el historial es del llamador
+		request.Messages = append(messages[:i:i], messages[end:]...)
+		return end - i
+	}
+	return 0
+}
+
+// contextTooLongError explica por qué la petición no cabe
+func contextTooLongError(request *domain.ChatRequest, tokens, window int) error {
+	if request.MaxTokens > 0 {
+		return fmt.Errorf("%w: ~%d tokens estimados + %d de max_tokens, %s admite %d",
+			domain.ErrContextTooLong, tokens, request.MaxTokens, request.Model, window)
+	}
+	return fmt.Errorf("%w: ~%d tokens estimados, %s admite %d",
+		domain.ErrContextTooLong, tokens, request.Model, window)
+}
@@ -0,0 +1,338 @@
+// Package application - Casos de uso de conversaciones multi-turno
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"time"
+)
+
+// DefaultDeletedRetention es el plazo por defecto para restaurar una
+// conversación borrada antes de que se elimine definitivamente
+const DefaultDeletedRetention = 7 * 24 * time.Hour
+
+// ============================================================================
+// ERRORES
+// ============================================================================
+
+var (
+	ErrEmptyConversationID = errors.New("el id de la conversación no puede estar vacío")
+)
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// ConversationServiceImpl implementa domain.ConversationService
+//
+// No habla con Groq directamente: reutiliza domain.ChatService pasando el
+// historial en MessageOptions.History. Así las conversaciones respetan las
+// mismas políticas (stop sequences, idioma, límites...) que /chat.
+type ConversationServiceImpl struct {
+	// chatService envía cada turno al modelo (puerto primario reutilizado)
+	chatService domain.ChatService
+
+	// repo guarda las conversaciones (puerto secundario)
+	repo domain.ConversationRepository
+
+	// defaultModel se usa al crear conversaciones sin modelo
+	defaultModel string
+
+	// deletedRetention es el plazo para restaurar una conversación borrada
+	deletedRetention time.Duration
+
+	// settings aporta el modelo por defecto vigente (nil = defaultModel)
+	settings domain.SettingsSource
+
+	// defaultBudget se fija en las conversaciones creadas sin presupuesto
+	// (nil = sin límite)
+	defaultBudget *domain.ConversationBudget
+}
+
+// ConversationOption configura aspectos opcionales del servicio
+type ConversationOption func(*ConversationServiceImpl)
+
+// WithDeletedRetention configura el plazo de restauración de las borradas
+func WithDeletedRetention(retention time.Duration) ConversationOption {
+	return func(s *ConversationServiceImpl) {
+		s.deletedRetention = retention
+	}
+}
+
+// WithDefaultModelSource lee el modelo por defecto de los ajustes vigentes
+// al crear cada conversación (ver domain.RuntimeSettings)
+func WithDefaultModelSource(settings domain.SettingsSource) ConversationOption {
+	return func(s *ConversationServiceImpl) {
+		s.settings = settings
+	}
+}
+
+// WithDefaultBudget fija un presupuesto en las conversaciones que se crean
+// sin uno (ver domain.ConversationBudget)
+func WithDefaultBudget(budget domain.ConversationBudget) ConversationOption {
+	return func(s *ConversationServiceImpl) {
+		s.defaultBudget = &budget
+	}
+}
+
+// NewConversationService crea el servicio de conversaciones
+func NewConversationService(
+	chatService domain.ChatService,
+	repo domain.ConversationRepository,
+	defaultModel string,
+	opts ...ConversationOption,
+) domain.ConversationService {
+	if chatService == nil {
+		panic("chatService no puede ser nil")
+	}
+	if repo == nil {
+		panic("conversationRepo no puede ser nil")
+	}
+
+	service := &ConversationServiceImpl{
+		chatService:      chatService,
+		repo:             repo,
+		defaultModel:     defaultModel,
+		deletedRetention: DefaultDeletedRetention,
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	return service
+}
+
+// CreateConversation crea y guarda una conversación vacía
+func (s *ConversationServiceImpl) CreateConversation(
+	ctx context.Context,
+	settings domain.ConversationSettings,
+) (*domain.Conversation, error) {
+	if settings.Model == "" && s.settings != nil {
+		settings.Model = s.settings.Settings().DefaultModel
+	}
+	if settings.Model == "" {
+		settings.Model = s.defaultModel
+	}
+	if settings.Model == "" {
+		return nil, ErrEmptyModel
+	}
+	if settings.Budget == nil && s.defaultBudget != nil {
+		budget := *s.defaultBudget
+		settings.Budget = &budget
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("error al generar el id: %w", err)
+	}
+
+	conversation := domain.NewConversation(id, settings)
+	if err := s.repo.Save(ctx, conversation); err != nil {
+		return nil, fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// SendMessage envía un nuevo turno de la conversación
+//
+// Pasos:
+//  1. Cargar la conversación
+//  2. Aplicar los ajustes fijados (salvo los que sobrescriba el mensaje)
+//  3. Comprobar el presupuesto: agotado, el turno se rechaza o va al modelo
+//     de reserva
+//  4. Enviar historial + mensaje nuevo al modelo
+//  5. Guardar el mensaje del usuario y la respuesta en el historial (y el
+//     resumen, si el servicio de chat resumió los turnos más antiguos)
+//
+// Si el modelo falla, la conversación no se modifica.
+func (s *ConversationServiceImpl) SendMessage(
+	ctx context.Context,
+	conversationID string,
+	message string,
+	model string,
+	opts domain.MessageOptions,
+) (*domain.Conversation, *domain.ChatResponse, error) {
+	conversation, err := s.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Los parámetros del mensaje tienen prioridad sobre los fijados
+	settings := conversation.ConversationSettings.Merge(domain.ConversationSettings{
+		Model:        model,
+		Temperature:  opts.Temperature,
+		SystemPrompt: opts.SystemPrompt,
+	})
+	opts.Temperature = settings.Temperature
+	opts.SystemPrompt = settings.SystemPrompt
+
+	// Con el presupuesto agotado, ni el modelo del mensaje tiene prioridad
+	fallback := false
+	if budget := settings.Budget; budget != nil {
+		if spent := conversation.Spent(); budget.Exceeded(spent) {
+			if budget.FallbackModel == "" {
+				return nil, nil, fmt.Errorf("%w (%d tokens, %.4f USD)",
+					domain.ErrConversationBudgetExceeded, spent.TotalTokens, spent.CostUSD)
+			}
+			settings.Model = budget.FallbackModel
+			fallback = true
+		}
+	}
+
+	// El historial guardado va antes del mensaje nuevo
+	opts.History = conversation.History()
+
+	// Todos los turnos de la conversación van a la misma API key (sticky routing)
+	ctx = domain.WithRoutingKey(ctx, conversation.ID)
+
+	start := time.Now()
+	response, err := s.chatService.SendMessage(ctx, message, settings.Model, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if fallback {
+		response.Meta.AddWarning(domain.WarningBudgetFallback,
+			"la conversación ha agotado su presupuesto: se usó el modelo "+settings.Model)
+	}
+
+	// El resumen se guarda: los turnos siguientes ya no lo vuelven a pedir
+	if summary := response.Meta.HistorySummary; summary != nil {
+		conversation.Compact(*summary)
+	}
+	conversation.AddMessage("user", message, nil)
+	conversation.AddMessage("assistant", response.GetResponseContent(), &domain.TurnMeta{
+		Model:        response.Model,
+		Latency:      time.Since(start),
+		Usage:        response.Usage,
+		FinishReason: response.GetFinishReason(),
+		CacheHit:     response.Meta.CacheHit,
+	})
+
+	// Nota: dos turnos simultáneos sobre la misma conversación se pisarían
+	// (gana el último en guardar). Para este caso de uso es aceptable.
+	if err := s.repo.Save(ctx, conversation); err != nil {
+		return nil, nil, fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+
+	return conversation, response, nil
+}
+
+// GetConversation obtiene una conversación por su id
+func (s *ConversationServiceImpl) GetConversation(
+	ctx context.Context,
+	conversationID string,
+) (*domain.Conversation, error) {
+	if conversationID == "" {
+		return nil, ErrEmptyConversationID
+	}
+
+	// El error de "no encontrada" se propaga tal cual (domain.ErrConversationNotFound)
+	// para que el handler pueda responder 404 con errors.Is()
+	conversation, err := s.repo.FindByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Una conversación borrada no existe para el cliente hasta que se restaure
+	if conversation.IsDeleted() {
+		return nil, domain.ErrConversationNotFound
+	}
+	return conversation, nil
+}
+
+// PinSettings cambia los ajustes fijados de la conversación
+func (s *ConversationServiceImpl) PinSettings(
+	ctx context.Context,
+	conversationID string,
+	settings domain.ConversationSettings,
+) (*domain.Conversation, error) {
+	conversation, err := s.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation.Pin(settings)
+	if err := s.repo.Save(ctx, conversation); err != nil {
+		return nil, fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// DeleteConversation borra la conversación (se puede restaurar dentro del plazo)
+func (s *ConversationServiceImpl) DeleteConversation(
+	ctx context.Context,
+	conversationID string,
+) (*domain.Conversation, error) {
+	conversation, err := s.GetConversation(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	conversation.SoftDelete(time.Now(), s.deletedRetention)
+	if err := s.repo.Save(ctx, conversation); err != nil {
+		return nil, fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// RestoreConversation recupera una conversación borrada
+func (s *ConversationServiceImpl) RestoreConversation(
+	ctx context.Context,
+	conversationID string,
+) (*domain.Conversation, error) {
+	if conversationID == "" {
+		return nil, ErrEmptyConversationID
+	}
+
+	// Aquí se usa el repositorio directamente: GetConversation ocultaría la borrada
+	conversation, err := s.repo.FindByID(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if !conversation.IsDeleted() {
+		return nil, domain.ErrConversationNotDeleted
+	}
+
+	// Puede que el plazo haya pasado pero la purga aún no se haya ejecutado
+	if !time.Now().Before(*conversation.PurgeAt) {
+		return nil, domain.ErrRestoreWindowExpired
+	}
+
+	conversation.Restore()
+	if err := s.repo.Save(ctx, conversation); err != nil {
+		return nil, fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+
+	return conversation, nil
+}
+
+// PurgeDeleted elimina definitivamente las conversaciones fuera de plazo
+// Lo invoca periódicamente el proceso de retención (ver main.go)
+func (s *ConversationServiceImpl) PurgeDeleted(ctx context.Context) (int, error) {
+	purged, err := s.repo.PurgeDeleted(ctx, time.Now())
+	if err != nil {
+		return purged, fmt.Errorf("error al purgar conversaciones: %w", err)
+	}
+	return purged, nil
+}
+
+// ============================================================================
+// FUNCIONES AUXILIARES
+// ============================================================================
+
+// newID genera un identificador aleatorio de 32 caracteres hexadecimales
+// crypto/rand es un generador criptográficamente seguro (a diferencia de math/rand)
+func newID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -0,0 +1,49 @@
+// Package application - Estimación del coste de las peticiones
+package application
+
+import (
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// ESTIMACIÓN DE COSTE
+// ============================================================================
+//
+// En un dry run los tokens de entrada se estiman con
+// domain.EstimatePromptTokens (ver domain/tokens.go). En las respuestas, el
+// coste (cost_usd) se calcula con los tokens que devuelve el proveedor. En
+// ambos casos sirve para presupuestar, no para facturar.
+// ============================================================================
+
+// estimateCost calcula el coste con los precios configurados
+// Retorna nil si el modelo no tiene precio
+func estimateCost(prices map[string]domain.ModelPrice, model string, promptTokens, maxTokens int) *domain.CostEstimate {
+	price, ok := prices[model]
+	if !ok {
+		return nil
+	}
+
+	return &domain.CostEstimate{
+		Prompt:        price.Cost(domain.Usage{PromptTokens: promptTokens}),
+		MaxCompletion: price.Cost(domain.Usage{CompletionTokens: maxTokens}),
+	}
+}
+
+// pricedStream añade el coste al uso de tokens del último fragmento
+type pricedStream struct {
+	domain.ChatStream
+
+	price domain.ModelPrice
+}
+
+// Recv implementa domain.ChatStream
+func (s *pricedStream) Recv() (*domain.ChatStreamChunk, error) {
+	chunk, err := s.ChatStream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	if usage := chunk.GetUsage(); usage != nil {
+		usage.CostUSD = s.price.Cost(*usage)
+	}
+	return chunk, nil
+}
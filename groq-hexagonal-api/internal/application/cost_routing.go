@@ -0,0 +1,119 @@
+// Package application - Enrutado de modelos por coste
+package application
+
+import (
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"sort"
+)
+
+// ============================================================================
+// ENRUTADO POR COSTE
+// ============================================================================
+//
+// Si la petición no elige modelo pero pide una calidad ("quality"), o el
+// operador configura una calidad por defecto, buildRequest elige el modelo
+// con CostRouting.Pick:
+//
+//  1. Candidatos: los modelos del catálogo del proveedor de la petición (o
+//     del por defecto), con precio conocido, nivel igual o superior al
+//     pedido y las capacidades que necesita la petición (visión si lleva
+//     imágenes, herramientas si lleva tools)
+//  2. Se elige el más barato, sumando el precio de entrada y el de salida
+//  3. A igual precio gana el de mayor nivel y, después, el nombre (para que
+//     la elección no dependa del orden del mapa)
+//
+// Un modelo explícito (del cliente, de la persona o forzado para depurar)
+// tiene siempre prioridad: la calidad solo decide cuando no hay modelo.
+// ============================================================================
+
+// CostRouting configura el enrutado por coste
+type CostRouting struct {
+	// Profiles es el catálogo de modelos (nil = enrutado desactivado)
+	Profiles map[string]domain.ModelProfile
+
+	// DefaultProvider es el proveedor de las peticiones que no eligen uno
+	DefaultProvider string
+
+	// DefaultQuality se aplica a las peticiones sin modelo ni calidad
+	// (vacío = esas peticiones usan el modelo por defecto)
+	DefaultQuality string
+}
+
+// qualityFor retorna la calidad que se aplica a la petición ("" = ninguna)
+func (r CostRouting) qualityFor(opts domain.MessageOptions) string {
+	if r.Profiles == nil {
+		return ""
+	}
+	if opts.Quality != "" {
+		return opts.Quality
+	}
+	return r.DefaultQuality
+}
+
+// Pick elige el modelo más barato que cumple la calidad y las capacidades
+// Sin candidatos retorna domain.ErrNoModelForQuality
+func (r CostRouting) Pick(
+	quality string,
+	provider string,
+	capabilities []string,
+	pricing map[string]domain.ModelPrice,
+) (string, error) {
+	if provider == "" {
+		provider = r.DefaultProvider
+	}
+	rank := domain.QualityRank(quality)
+
+	type candidate struct {
+		model string
+		rank  int
+		price float64
+	}
+	var candidates []candidate
+	for model, profile := range r.Profiles {
+		price, ok := pricing[model]
+		if !ok || profile.Provider != provider || !profile.Supports(capabilities) {
+			continue
+		}
+		if tier := domain.QualityRank(profile.Tier); tier >= rank {
+			candidates = append(candidates, candidate{model, tier, price.Input + price.Output})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("%w: quality=%s provider=%s", domain.ErrNoModelForQuality, quality, provider)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.price != b.price {
+			return a.price < b.price
+		}
+		if a.rank != b.rank {
+			return a.rank > b.rank
+		}
+		return a.model < b.model
+	})
+	return candidates[0].model, nil
+}
+
+// requiredCapabilities retorna las capacidades que necesita la petición
+func requiredCapabilities(opts domain.MessageOptions) []string {
+	var capabilities []string
+	if len(opts.Images) > 0 || historyHasImages(opts.History) {
+		capabilities = append(capabilities, domain.CapabilityVision)
+	}
+	if len(opts.Tools) > 0 {
+		capabilities = append(capabilities, domain.CapabilityTools)
+	}
+	return capabilities
+}
+
+// historyHasImages indica si algún mensaje del historial lleva imágenes
+func historyHasImages(history []domain.ChatMessage) bool {
+	for _, message := range history {
+		if len(message.Images) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+// Package application - Caso de uso de comparar respuestas (diff)
+package application
+
+import (
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strings"
+	"sync"
+)
+
+// maxDiffCells limita el tamaño de la tabla LCS (palabras de A × palabras de B)
+// Por encima, solo se calcula la similitud aproximada, sin el diff detallado
+const maxDiffCells = 4_000_000
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// DiffServiceImpl implementa domain.DiffService
+//
+// Igual que las conversaciones, reutiliza domain.ChatService: cada variante
+// pasa por las mismas políticas (stop sequences, idioma, límites...) que /chat.
+type DiffServiceImpl struct {
+	chatService domain.ChatService
+}
+
+// NewDiffService crea el servicio de comparación de respuestas
+func NewDiffService(chatService domain.ChatService) domain.DiffService {
+	if chatService == nil {
+		panic("chatService no puede ser nil")
+	}
+
+	return &DiffServiceImpl{chatService: chatService}
+}
+
+// Compare envía el mensaje a ambas variantes en paralelo y compara las respuestas
+func (s *DiffServiceImpl) Compare(ctx context.Context, request domain.DiffRequest) (*domain.DiffResult, error) {
+	if request.Message == "" {
+		return nil, ErrEmptyMessage
+	}
+
+	// Las dos llamadas son independientes: lanzarlas a la vez reduce la latencia
+	var (
+		wg        sync.WaitGroup
+		responses [2]*domain.ChatResponse
+		errs      [2]error
+	)
+	for i, variant := range []domain.DiffVariant{request.A, request.B} {
+		wg.Add(1)
+		go func(i int, variant domain.DiffVariant) {
+			defer wg.Done()
+			responses[i], errs[i] = s.chatService.SendMessage(ctx, request.Message, variant.Model, domain.MessageOptions{
+				Temperature:  variant.Temperature,
+				MaxTokens:    request.MaxTokens,
+				SystemPrompt: variant.SystemPrompt,
+			})
+		}(i, variant)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("variante %c: %w", 'A'+i, err)
+		}
+	}
+
+	similarity, ops := diffWords(
+		strings.Fields(responses[0].GetResponseContent()),
+		strings.Fields(responses[1].GetResponseContent()),
+	)
+
+	return &domain.DiffResult{
+		A:          responses[0],
+		B:          responses[1],
+		Similarity: similarity,
+		Ops:        ops,
+	}, nil
+}
+
+// ============================================================================
+// DIFF PALABRA A PALABRA
+// ============================================================================
+//
+// Se usa la subsecuencia común más larga (LCS): las palabras que aparecen en
+// ambas respuestas en el mismo orden son "equal"; el resto son "delete" (solo
+// en A) o "insert" (solo en B). La similitud es 2·LCS / (len(A) + len(B)),
+// la misma medida que difflib.SequenceMatcher.ratio() en Python.
+// ============================================================================
+
+// diffWords compara dos listas de palabras
+func diffWords(a, b []string) (float64, []domain.DiffOp) {
+	if len(a)+len(b) == 0 {
+		return 1, nil
+	}
+
+	// Textos muy largos: similitud por palabras en común, sin orden ni diff
+	if len(a)*len(b) > maxDiffCells {
+		return 2 * float64(commonWords(a, b)) / float64(len(a)+len(b)), nil
+	}
+
+	// lcs[i][j] = longitud de la LCS de a[i:] y b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	// Recorrer la tabla agrupando palabras consecutivas con la misma operación
+	var ops []domain.DiffOp
+	emit := func(op, word string) {
+		if n := len(ops); n > 0 && ops[n-1].Op == op {
+			ops[n-1].Text += " " + word
+			return
+		}
+		ops = append(ops, domain.DiffOp{Op: op, Text: word})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			emit(domain.DiffEqual, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			emit(domain.DiffDelete, a[i])
+			i++
+		default:
+			emit(domain.DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		emit(domain.DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		emit(domain.DiffInsert, b[j])
+	}
+
+	return 2 * float64(lcs[0][0]) / float64(len(a)+len(b)), ops
+}
+
+// commonWords cuenta las palabras compartidas (con repeticiones) sin importar el orden
+func commonWords(a, b []string) int {
+	counts := make(map[string]int, len(a))
+	for _, word := range a {
+		counts[word]++
+	}
+
+	common := 0
+	for _, word := range b {
+		if counts[word] > 0 {
+			counts[word]--
+			common++
+		}
+	}
+	return common
+}
@@ -0,0 +1,128 @@
+// Package application - Caso de uso de valorar las respuestas
+package application
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+// FeedbackServiceImpl implementa domain.FeedbackService
+type FeedbackServiceImpl struct {
+	repo domain.FeedbackRepository
+
+	// now da la hora actual (fecha de las valoraciones)
+	now func() time.Time
+}
+
+// NewFeedbackService crea el servicio de valoraciones
+func NewFeedbackService(repo domain.FeedbackRepository) domain.FeedbackService {
+	if repo == nil {
+		panic("feedbackRepo no puede ser nil")
+	}
+
+	return &FeedbackServiceImpl{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Submit implementa el caso de uso de guardar una valoración
+func (s *FeedbackServiceImpl) Submit(ctx context.Context, feedback domain.Feedback) (*domain.Feedback, error) {
+	if err := validateFeedback(feedback); err != nil {
+		return nil, err
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+	feedback.ID = id
+	feedback.Tenant = domain.TenantFromContext(ctx)
+	feedback.CreatedAt = s.now().UTC()
+
+	if err := s.repo.SaveFeedback(ctx, &feedback); err != nil {
+		return nil, err
+	}
+	return &feedback, nil
+}
+
+// Summary implementa el caso de uso de resumir las valoraciones
+func (s *FeedbackServiceImpl) Summary(ctx context.Context, filter domain.FeedbackFilter) (*domain.FeedbackSummary, error) {
+	feedbacks, err := s.repo.ListFeedback(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.FeedbackSummary{Filter: filter}
+	byModel := make(map[string]*domain.FeedbackCount)
+	byPersona := make(map[string]*domain.FeedbackCount)
+	for _, feedback := range feedbacks {
+		summary.Total.Add(feedback.Rating)
+		countBy(byModel, feedback.Model, feedback.Rating)
+		countBy(byPersona, feedback.Persona, feedback.Rating)
+	}
+	summary.ByModel = groupedFeedback(byModel)
+	summary.ByPersona = groupedFeedback(byPersona)
+
+	// Los comentarios más recientes primero: el repositorio los da al revés
+	for i := len(feedbacks) - 1; i >= 0 && len(summary.RecentComments) < domain.MaxFeedbackComments; i-- {
+		if feedbacks[i].Comment != "" {
+			summary.RecentComments = append(summary.RecentComments, feedbacks[i])
+		}
+	}
+	return summary, nil
+}
+
+// validateFeedback comprueba una valoración antes de guardarla
+func validateFeedback(feedback domain.Feedback) error {
+	if feedback.ResponseID == "" && feedback.ConversationID == "" {
+		return fmt.Errorf("%w: falta response_id o conversation_id", domain.ErrInvalidFeedback)
+	}
+	if feedback.Rating != domain.FeedbackUp && feedback.Rating != domain.FeedbackDown {
+		return fmt.Errorf("%w: rating debe ser %q o %q", domain.ErrInvalidFeedback, domain.FeedbackUp, domain.FeedbackDown)
+	}
+	if utf8.RuneCountInString(feedback.Comment) > domain.MaxFeedbackCommentChars {
+		return fmt.Errorf("%w: el comentario admite como máximo %d caracteres", domain.ErrInvalidFeedback, domain.MaxFeedbackCommentChars)
+	}
+	for _, value := range []string{feedback.ResponseID, feedback.ConversationID, feedback.Model, feedback.Persona} {
+		if len(value) > domain.MaxFeedbackIDChars {
+			return fmt.Errorf("%w: los identificadores admiten como máximo %d caracteres", domain.ErrInvalidFeedback, domain.MaxFeedbackIDChars)
+		}
+	}
+	return nil
+}
+
+// countBy suma la valoración a la cuenta de name (si la valoración lo indica)
+func countBy(counts map[string]*domain.FeedbackCount, name, rating string) {
+	if name == "" {
+		return
+	}
+	count, ok := counts[name]
+	if !ok {
+		count = &domain.FeedbackCount{}
+		counts[name] = count
+	}
+	count.Add(rating)
+}
+
+// groupedFeedback ordena las cuentas de la peor puntuación a la mejor
+// A igual puntuación, primero la que tiene más valoraciones
+func groupedFeedback(counts map[string]*domain.FeedbackCount) []domain.GroupedFeedback {
+	groups := make([]domain.GroupedFeedback, 0, len(counts))
+	for name, count := range counts {
+		groups = append(groups, domain.GroupedFeedback{Name: name, FeedbackCount: *count})
+	}
+	slices.SortFunc(groups, func(a, b domain.GroupedFeedback) int {
+		return cmp.Or(
+			cmp.Compare(a.Score(), b.Score()),
+			cmp.Compare(b.Total(), a.Total()),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return groups
+}
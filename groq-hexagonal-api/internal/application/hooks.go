@@ -0,0 +1,171 @@
+// Package application - Registro de hooks de peticiones y respuestas
+package application
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// HOOK REGISTRY
+// ============================================================================
+//
+// HookRegistry guarda los hooks del despliegue (ver domain/hooks.go). Se crea
+// en main, se registran los hooks y se pasa al servicio de chat con WithHooks:
+//
+//   hooks := application.NewHookRegistry()
+//   hooks.OnRequest(domain.RequestHookFunc(func(ctx context.Context, request *domain.ChatRequest) error {
+//       if domain.RequestHeadersFromContext(ctx).Get("X-Fast") == "1" {
+//           request.Model = "llama-3.1-8b-instant"
+//       }
+//       return nil
+//   }))
+//
+// Los hooks se ejecutan en el orden en que se registraron. Se aplican a todo
+// lo que pasa por el servicio de chat (chat, conversaciones, diff y dry run);
+// el modo proxy no los usa porque no interpreta el body.
+// ============================================================================
+
+// HookRegistry contiene los hooks registrados
+// Un registro nil o vacío no hace nada
+type HookRegistry struct {
+	requestHooks  []domain.RequestHook
+	responseHooks []domain.ResponseHook
+}
+
+// NewHookRegistry crea un registro vacío
+func NewHookRegistry() *HookRegistry {
+	return &HookRegistry{}
+}
+
+// OnRequest registra un hook que se ejecuta antes de llamar al modelo
+// Debe llamarse antes de arrancar el servidor (el registro no usa locks)
+func (r *HookRegistry) OnRequest(hook domain.RequestHook) {
+	if hook == nil {
+		panic("hook no puede ser nil")
+	}
+	r.requestHooks = append(r.requestHooks, hook)
+}
+
+// OnResponse registra un hook que recibe cada respuesta del modelo
+// Debe llamarse antes de arrancar el servidor (el registro no usa locks)
+func (r *HookRegistry) OnResponse(hook domain.ResponseHook) {
+	if hook == nil {
+		panic("hook no puede ser nil")
+	}
+	r.responseHooks = append(r.responseHooks, hook)
+}
+
+// Len retorna el número de hooks registrados (de petición y de respuesta)
+func (r *HookRegistry) Len() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.requestHooks) + len(r.responseHooks)
+}
+
+// beforeRequest ejecuta los RequestHook; el primer error detiene la cadena
+func (r *HookRegistry) beforeRequest(ctx context.Context, request *domain.ChatRequest) error {
+	if r == nil {
+		return nil
+	}
+	for _, hook := range r.requestHooks {
+		if err := hook.BeforeRequest(ctx, request); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// afterResponse ejecuta los ResponseHook
+func (r *HookRegistry) afterResponse(ctx context.Context, request domain.ChatRequest, response *domain.ChatResponse) {
+	if r == nil {
+		return
+	}
+	for _, hook := range r.responseHooks {
+		hook.AfterResponse(ctx, request, response)
+	}
+}
+
+// wrapStream envuelve un flujo para pasar a los ResponseHook la respuesta
+// acumulada al cerrarlo (sin hooks de respuesta, retorna el flujo tal cual)
+func (r *HookRegistry) wrapStream(ctx context.Context, request domain.ChatRequest, stream domain.ChatStream) domain.ChatStream {
+	if r == nil || len(r.responseHooks) == 0 {
+		return stream
+	}
+	return &hookedStream{
+		ChatStream: stream,
+		ctx:        ctx,
+		request:    request,
+		hooks:      r,
+		response:   domain.ChatResponse{Object: "chat.completion", Model: request.Model},
+	}
+}
+
+// ============================================================================
+// STREAMING
+// ============================================================================
+
+// hookedStream acumula los fragmentos de un flujo en una respuesta completa
+// Solo se acumula el texto: las llamadas a herramientas llegan troceadas y
+// los hooks que las necesiten deben usar respuestas sin streaming
+type hookedStream struct {
+	domain.ChatStream
+
+	ctx     context.Context
+	request domain.ChatRequest
+	hooks   *HookRegistry
+
+	response domain.ChatResponse
+	content  strings.Builder
+	once     sync.Once
+}
+
+// Recv implementa domain.ChatStream
+func (s *hookedStream) Recv() (*domain.ChatStreamChunk, error) {
+	chunk, err := s.ChatStream.Recv()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			s.finish()
+		}
+		return nil, err
+	}
+
+	if s.response.ID == "" {
+		s.response.ID = chunk.ID
+		s.response.Created = chunk.Created
+	}
+	if chunk.Model != "" {
+		s.response.Model = chunk.Model
+	}
+	s.content.WriteString(chunk.GetDeltaContent())
+	if len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != "" {
+		s.response.Choices = []domain.Choice{{FinishReason: chunk.Choices[0].FinishReason}}
+	}
+	if usage := chunk.GetUsage(); usage != nil {
+		s.response.Usage = *usage
+	}
+	return chunk, nil
+}
+
+// Close implementa domain.ChatStream
+// Si el cliente se desconecta a mitad, los hooks reciben la respuesta parcial
+func (s *hookedStream) Close() error {
+	s.finish()
+	return s.ChatStream.Close()
+}
+
+// finish ejecuta los ResponseHook una sola vez (al terminar o al cerrar)
+func (s *hookedStream) finish() {
+	s.once.Do(func() {
+		if len(s.response.Choices) == 0 {
+			s.response.Choices = []domain.Choice{{}}
+		}
+		s.response.Choices[0].Message = domain.ChatMessage{Role: "assistant", Content: s.content.String()}
+		s.hooks.afterResponse(s.ctx, s.request, &s.response)
+	})
+}
@@ -0,0 +1,137 @@
+// Package application - Progreso y errores tipados de los jobs
+package application
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// PROGRESO DE LOS JOBS
+// ============================================================================
+//
+// Un job con Stream genera la respuesta con ChatService.StreamMessage: el
+// worker acumula los fragmentos y guarda el progreso (tokens estimados del
+// texto recibido y, con max_tokens, el porcentaje) como mucho cada
+// jobProgressInterval, para no escribir en el repositorio en cada fragmento.
+// Al terminar, los fragmentos se juntan en una respuesta como la de /chat,
+// con el uso de tokens del último fragmento.
+//
+// Sin Stream el job pasa de running (sin tokens) a terminado de golpe: la
+// llamada al modelo no informa de nada hasta que responde.
+// ============================================================================
+
+// jobProgressInterval es cada cuánto se guarda el progreso como mucho
+const jobProgressInterval = time.Second
+
+// generateStream genera la respuesta del job por fragmentos, guardando el
+// progreso por el camino
+func (s *JobServiceImpl) generateStream(ctx context.Context, job *domain.Job, request domain.JobRequest) (*domain.ChatResponse, error) {
+	stream, err := s.chatService.StreamMessage(ctx, request.Message, request.Model, request.Options)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	response := &domain.ChatResponse{Object: "chat.completion", Created: s.now().Unix()}
+	message := domain.NewChatMessage("assistant", "")
+	var text strings.Builder
+	var finishReason string
+	lastSaved := s.now()
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if response.ID == "" {
+			response.ID = chunk.ID
+			response.Model = chunk.Model
+		}
+		text.WriteString(chunk.GetDeltaContent())
+		message.ToolCalls = append(message.ToolCalls, chunk.GetDeltaToolCalls()...)
+		if reason := chunk.GetFinishReason(); reason != "" {
+			finishReason = reason
+		}
+		if usage := chunk.GetUsage(); usage != nil {
+			response.Usage = *usage
+		}
+
+		if now := s.now(); now.Sub(lastSaved) >= jobProgressInterval {
+			job.Progress = newJobProgress(domain.EstimateTokens(text.String()), request.Options.MaxTokens, now)
+			s.save(ctx, job)
+			lastSaved = now
+		}
+	}
+
+	message.Content = text.String()
+	response.Choices = []domain.Choice{{Message: message, FinishReason: finishReason}}
+	return response, nil
+}
+
+// newJobProgress calcula el progreso con tokens generados de como mucho
+// maxTokens (0 = sin porcentaje)
+// Mientras se ejecuta no llega al 100%: el modelo puede terminar antes
+func newJobProgress(tokens, maxTokens int, now time.Time) *domain.JobProgress {
+	progress := &domain.JobProgress{Tokens: tokens, UpdatedAt: now}
+	if maxTokens > 0 {
+		percent := min(tokens*100/maxTokens, 99)
+		progress.Percent = &percent
+	}
+	return progress
+}
+
+// completedJobProgress es el progreso de un job terminado con éxito
+func completedJobProgress(response *domain.ChatResponse, now time.Time) *domain.JobProgress {
+	percent := 100
+	return &domain.JobProgress{
+		Tokens:    response.Usage.CompletionTokens,
+		Percent:   &percent,
+		UpdatedAt: now,
+	}
+}
+
+// jobErrorTypes asocia los errores del dominio con el tipo que ve el cliente
+// Son los mismos tipos que los de los errores HTTP de /chat; se recorre en
+// orden y el primero que coincide gana
+var jobErrorTypes = []struct {
+	target  error
+	errType string
+}{
+	{domain.ErrJobQueueFull, "queue_full"},
+	{domain.ErrEmptyMessage, "invalid_request"},
+	{domain.ErrEmptyModel, "invalid_request"},
+	{domain.ErrPersonaNotFound, "persona_not_found"},
+	{domain.ErrUnknownProvider, "unknown_provider"},
+	{domain.ErrNoModelForQuality, "no_model_for_quality"},
+	{domain.ErrRequestRejected, "request_rejected"},
+	{domain.ErrContentFlagged, "content_flagged"},
+	{domain.ErrRateLimited, "rate_limited"},
+	{domain.ErrModelNotFound, "model_not_found"},
+	{domain.ErrModelDecommissioned, "model_decommissioned"},
+	{domain.ErrContextTooLong, "context_too_long"},
+	{domain.ErrInvalidRequest, "invalid_request"},
+	{domain.ErrUpstreamUnavailable, "upstream_unavailable"},
+	{domain.ErrUpstreamTimeout, "upstream_timeout"},
+	{domain.ErrUpstreamAuth, "upstream_error"},
+	{domain.ErrInvalidModelOutput, "invalid_model_output"},
+	{context.DeadlineExceeded, "timeout"},
+}
+
+// jobErrorType es el tipo del fallo que ve el cliente al consultar el job
+func jobErrorType(err error) string {
+	for _, mapping := range jobErrorTypes {
+		if errors.Is(err, mapping.target) {
+			return mapping.errType
+		}
+	}
+	return "internal_error"
+}
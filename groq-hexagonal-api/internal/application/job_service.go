@@ -0,0 +1,478 @@
+// Package application - Caso de uso de chats asíncronos (jobs)
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// POOL DE WORKERS
+// ============================================================================
+//
+// Submit guarda el job en estado pending y lo deja en una cola (un canal con
+// buffer). Un número fijo de workers (goroutines) la vacía: cada uno llama a
+// ChatService.SendMessage, así que los jobs pasan por lo mismo que /chat
+// (personas, caché, hooks, alias...), y guarda el job al empezar y al
+// terminar. Los workers limitan cuántas llamadas al modelo hay a la vez; la
+// cola, cuántas esperan. Con la cola llena, Submit falla en lugar de esperar.
+//
+// El worker usa el contexto de la petición HTTP sin su cancelación
+// (context.WithoutCancel): conserva el tenant, el proveedor o las cabeceras,
+// pero la generación sigue aunque el cliente ya haya recibido el 202.
+//
+// Los callbacks se entregan en su propia goroutine al terminar el job, para
+// no ocupar un worker durante las esperas entre reintentos: el intento n
+// espera Backoff * 2^(n-2) tras el anterior (5s, 10s, 20s...). El estado de
+// la entrega se guarda en el job tras cada intento.
+//
+// Con un JobResultStore, las respuestas grandes (SpillBytes o más de texto)
+// se escriben en él en lugar de dentro del job: el repositorio guarda solo
+// la referencia, así que ni la memoria ni Redis cargan con ellas mientras el
+// job se conserva. El cliente las descarga con OpenResult (por rangos).
+// ============================================================================
+
+// Valores por defecto de JobOptions
+const (
+	DefaultJobWorkers   = 4
+	DefaultJobQueueSize = 100
+	DefaultJobRetention = 24 * time.Hour
+
+	DefaultCallbackAttempts = 6
+	DefaultCallbackBackoff  = 5 * time.Second
+
+	DefaultJobResultSpillBytes = 1 << 20
+)
+
+// JobOptions configura el pool de workers
+type JobOptions struct {
+	// Workers es cuántos jobs se ejecutan a la vez (0 = DefaultJobWorkers)
+	Workers int
+
+	// QueueSize es cuántos jobs pueden esperar a un worker (0 = DefaultJobQueueSize)
+	QueueSize int
+
+	// Retention es cuánto se guarda un job desde que se crea (0 = DefaultJobRetention)
+	Retention time.Duration
+}
+
+// JobCallbackPolicy configura los reintentos de los callbacks
+type JobCallbackPolicy struct {
+	// MaxAttempts es cuántas veces se intenta entregar (0 = DefaultCallbackAttempts)
+	MaxAttempts int
+
+	// Backoff es la espera antes del primer reintento; se dobla en cada uno
+	// (0 = DefaultCallbackBackoff)
+	Backoff time.Duration
+}
+
+// queuedJob es un job en la cola con lo necesario para ejecutarlo
+type queuedJob struct {
+	// ctx lleva los valores de la petición original (sin su cancelación)
+	ctx     context.Context
+	job     *domain.Job
+	request domain.JobRequest
+}
+
+// JobServiceImpl implementa domain.JobService
+type JobServiceImpl struct {
+	chatService domain.ChatService
+	repo        domain.JobRepository
+	queue       chan queuedJob
+	retention   time.Duration
+
+	// usage apunta el consumo de los jobs (opcional): el middleware de
+	// consumo no lo ve, porque la respuesta HTTP sale antes de la generación
+	usage domain.UsageService
+
+	// notifier entrega los callbacks (nil = callbacks desactivados) y
+	// deadLetters registra los que agotan sus intentos
+	notifier    domain.JobNotifier
+	deadLetters domain.DeadLetterReporter
+	callbacks   JobCallbackPolicy
+
+	// resultStore guarda las respuestas de spillBytes o más (nil = todas
+	// dentro del job)
+	resultStore domain.JobResultStore
+	spillBytes  int64
+
+	// liveness vigila que los workers no se queden colgados en un job
+	// (opcional)
+	liveness domain.LivenessMonitor
+
+	// now da la hora actual
+	now func() time.Time
+}
+
+// JobOption configura aspectos opcionales del servicio
+type JobOption func(*JobServiceImpl)
+
+// WithJobUsage apunta los tokens de cada job al consumo de su cliente
+func WithJobUsage(usage domain.UsageService) JobOption {
+	return func(s *JobServiceImpl) {
+		s.usage = usage
+	}
+}
+
+// WithJobCallbacks activa los callbacks de los jobs terminados
+func WithJobCallbacks(notifier domain.JobNotifier, deadLetters domain.DeadLetterReporter, policy JobCallbackPolicy) JobOption {
+	return func(s *JobServiceImpl) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = DefaultCallbackAttempts
+		}
+		if policy.Backoff <= 0 {
+			policy.Backoff = DefaultCallbackBackoff
+		}
+		s.notifier = notifier
+		s.deadLetters = deadLetters
+		s.callbacks = policy
+	}
+}
+
+// WithJobLiveness registra cada job en el monitor de tareas colgadas, con
+// su plazo por defecto
+func WithJobLiveness(liveness domain.LivenessMonitor) JobOption {
+	return func(s *JobServiceImpl) {
+		s.liveness = liveness
+	}
+}
+
+// WithJobResultStore guarda fuera del job las respuestas con spillBytes o
+// más de texto (0 = DefaultJobResultSpillBytes)
+func WithJobResultStore(store domain.JobResultStore, spillBytes int64) JobOption {
+	return func(s *JobServiceImpl) {
+		if spillBytes <= 0 {
+			spillBytes = DefaultJobResultSpillBytes
+		}
+		s.resultStore = store
+		s.spillBytes = spillBytes
+	}
+}
+
+// NewJobService crea el servicio y arranca los workers
+// Los workers viven durante toda la vida del proceso
+func NewJobService(
+	chatService domain.ChatService,
+	repo domain.JobRepository,
+	options JobOptions,
+	opts ...JobOption,
+) domain.JobService {
+	if chatService == nil {
+		panic("chatService no puede ser nil")
+	}
+	if repo == nil {
+		panic("jobRepo no puede ser nil")
+	}
+
+	if options.Workers <= 0 {
+		options.Workers = DefaultJobWorkers
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultJobQueueSize
+	}
+	if options.Retention <= 0 {
+		options.Retention = DefaultJobRetention
+	}
+
+	service := &JobServiceImpl{
+		chatService: chatService,
+		repo:        repo,
+		queue:       make(chan queuedJob, options.QueueSize),
+		retention:   options.Retention,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	for i := 0; i < options.Workers; i++ {
+		go service.work()
+	}
+	return service
+}
+
+// Submit guarda el job y lo encola sin esperar al modelo
+func (s *JobServiceImpl) Submit(ctx context.Context, request domain.JobRequest) (*domain.Job, error) {
+	if strings.TrimSpace(request.Message) == "" && len(request.Options.Images) == 0 {
+		return nil, ErrEmptyMessage
+	}
+	if request.CallbackURL != "" {
+		if s.notifier == nil {
+			return nil, domain.ErrCallbacksDisabled
+		}
+		if !validCallbackURL(request.CallbackURL) {
+			return nil, domain.ErrInvalidCallbackURL
+		}
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, fmt.Errorf("error al generar el ID: %w", err)
+	}
+
+	now := s.now()
+	job := &domain.Job{
+		ID:        id,
+		Client:    request.Client,
+		Status:    domain.JobPending,
+		Model:     request.Model,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.retention),
+	}
+	if request.CallbackURL != "" {
+		job.Callback = &domain.JobCallback{URL: request.CallbackURL, Status: domain.CallbackPending}
+	}
+	// Se guarda ANTES de encolar: si no, un worker rápido podría guardar
+	// "running" y este Save lo devolvería a "pending"
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("error al guardar el job: %w", err)
+	}
+
+	queued := queuedJob{ctx: context.WithoutCancel(ctx), job: cloneJob(job), request: request}
+	select {
+	case s.queue <- queued:
+		return job, nil
+	default:
+		// El job ya está guardado: queda como fallido para quien lo consulte
+		s.finish(ctx, job, nil, domain.ErrJobQueueFull)
+		return nil, domain.ErrJobQueueFull
+	}
+}
+
+// GetJob retorna el job si es del cliente
+func (s *JobServiceImpl) GetJob(ctx context.Context, client string, id string) (*domain.Job, error) {
+	job, err := s.repo.FindJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Un job de otro cliente no existe para este (no se revela que existe)
+	if job.Client != client {
+		return nil, domain.ErrJobNotFound
+	}
+	return job, nil
+}
+
+// OpenResult abre la respuesta del job del cliente como JSON
+// Las que están dentro del job se serializan al vuelo: el cliente puede
+// descargar cualquier resultado de la misma forma
+func (s *JobServiceImpl) OpenResult(ctx context.Context, client string, id string) (*domain.Job, io.ReadSeekCloser, error) {
+	job, err := s.GetJob(ctx, client, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.Status != domain.JobSucceeded {
+		return nil, nil, domain.ErrJobResultUnavailable
+	}
+
+	if job.ResultRef == nil {
+		data, err := json.Marshal(job.Result)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error al serializar el resultado: %w", err)
+		}
+		return job, nopSeekCloser{bytes.NewReader(data)}, nil
+	}
+
+	// El job se guardó aparte en otra réplica (o con otra configuración)
+	if s.resultStore == nil {
+		return nil, nil, fmt.Errorf("%w: el resultado está guardado aparte y esta réplica no tiene JOB_RESULT_DIR", domain.ErrJobResultUnavailable)
+	}
+	reader, err := s.resultStore.OpenResult(ctx, job.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return job, reader, nil
+}
+
+// nopSeekCloser añade un Close vacío a un reader en memoria
+type nopSeekCloser struct {
+	*bytes.Reader
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+// work ejecuta los jobs de la cola uno tras otro
+func (s *JobServiceImpl) work() {
+	for queued := range s.queue {
+		if s.liveness == nil {
+			s.run(queued)
+			continue
+		}
+		done := s.liveness.Begin("job "+queued.job.ID, 0)
+		s.run(queued)
+		done()
+	}
+}
+
+// run llama al modelo y guarda el job al empezar y al terminar
+func (s *JobServiceImpl) run(queued queuedJob) {
+	ctx, job, request := queued.ctx, queued.job, queued.request
+
+	started := s.now()
+	job.Status = domain.JobRunning
+	job.StartedAt = &started
+	job.Progress = newJobProgress(0, request.Options.MaxTokens, started)
+	s.save(ctx, job)
+
+	var response *domain.ChatResponse
+	var err error
+	if request.Stream {
+		response, err = s.generateStream(ctx, job, request)
+	} else {
+		response, err = s.chatService.SendMessage(ctx, request.Message, request.Model, request.Options)
+	}
+	s.finish(ctx, job, response, err)
+
+	if job.Callback != nil {
+		go s.deliver(ctx, cloneJob(job))
+	}
+
+	// Las respuestas de la caché no consumen tokens del proveedor
+	if err == nil && s.usage != nil && !response.Meta.CacheHit {
+		ctx := domain.WithRequestInfo(ctx, domain.RequestInfo{ID: job.ID, StartedAt: started})
+		if err := s.usage.Record(ctx, job.Client, response.Model, response.TotalUsage()); err != nil {
+			log.Printf("⚠️  Error al registrar el consumo del job %s: %v", job.ID, err)
+		}
+	}
+}
+
+// finish marca el job como terminado con la respuesta o el error
+func (s *JobServiceImpl) finish(ctx context.Context, job *domain.Job, response *domain.ChatResponse, err error) {
+	finished := s.now()
+	job.FinishedAt = &finished
+	if err != nil {
+		job.Status = domain.JobFailed
+		job.Error = jobErrorMessage(err)
+		job.ErrorType = jobErrorType(err)
+	} else {
+		job.Status = domain.JobSucceeded
+		job.Result = response
+		job.Progress = completedJobProgress(response, finished)
+		s.spill(ctx, job)
+	}
+	s.save(ctx, job)
+}
+
+// spill guarda aparte la respuesta del job si es grande
+// Si el almacén falla, la respuesta se queda dentro del job
+func (s *JobServiceImpl) spill(ctx context.Context, job *domain.Job) {
+	if s.resultStore == nil || responseTextSize(job.Result) < s.spillBytes {
+		return
+	}
+
+	size, err := s.resultStore.SaveResult(ctx, job.ID, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(job.Result)
+	})
+	if err != nil {
+		log.Printf("⚠️  Error al guardar aparte el resultado del job %s (se guarda en el job): %v", job.ID, err)
+		return
+	}
+	job.Result = nil
+	job.ResultRef = &domain.JobResultRef{Size: size}
+}
+
+// responseTextSize estima el tamaño de la respuesta por su texto (lo que
+// crece con las respuestas largas), sin serializarla
+func responseTextSize(response *domain.ChatResponse) int64 {
+	var size int64
+	for _, choice := range response.Choices {
+		size += int64(len(choice.Message.Content))
+		for _, call := range choice.Message.ToolCalls {
+			size += int64(len(call.Function.Arguments))
+		}
+	}
+	return size
+}
+
+// deliver entrega el callback del job terminado, con reintentos
+// Tras el último intento fallido, la entrega va al registro de dead letters
+func (s *JobServiceImpl) deliver(ctx context.Context, job *domain.Job) {
+	wait := s.callbacks.Backoff
+	for attempt := 1; ; attempt++ {
+		err := s.notifier.NotifyJob(ctx, job, attempt)
+
+		// Se reemplaza el estado en lugar de modificarlo: el repositorio en
+		// memoria guarda copias del job que comparten el puntero
+		callback := *job.Callback
+		callback.Attempts = attempt
+		switch {
+		case err == nil:
+			callback.Status = domain.CallbackDelivered
+			callback.LastError = ""
+		case attempt >= s.callbacks.MaxAttempts:
+			callback.Status = domain.CallbackFailed
+			callback.LastError = err.Error()
+		default:
+			callback.LastError = err.Error()
+		}
+		job.Callback = &callback
+		s.save(ctx, job)
+
+		switch callback.Status {
+		case domain.CallbackDelivered:
+			return
+		case domain.CallbackFailed:
+			if s.deadLetters != nil {
+				s.deadLetters.ReportDeadLetter(ctx, domain.DeadLetter{
+					JobID:     job.ID,
+					Client:    job.Client,
+					URL:       callback.URL,
+					Attempts:  attempt,
+					LastError: callback.LastError,
+					FailedAt:  s.now(),
+				})
+			}
+			return
+		}
+
+		log.Printf("⚠️  Callback del job %s fallido (intento %d de %d, reintento en %s): %v",
+			job.ID, attempt, s.callbacks.MaxAttempts, wait, err)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// save guarda el job; si falla solo se registra (el worker no puede
+// responder a nadie)
+func (s *JobServiceImpl) save(ctx context.Context, job *domain.Job) {
+	if err := s.repo.SaveJob(ctx, job); err != nil {
+		log.Printf("⚠️  Error al guardar el job %s (%s): %v", job.ID, job.Status, err)
+	}
+}
+
+// jobErrorMessage es el error que ve el cliente al consultar el job
+// Los fallos de credenciales y de disponibilidad del proveedor no se
+// detallan, igual que en las respuestas HTTP de /chat
+func jobErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrUpstreamAuth):
+		return "error de configuración del proveedor de modelos"
+	case errors.Is(err, domain.ErrUpstreamUnavailable), errors.Is(err, domain.ErrUpstreamTimeout):
+		return "el proveedor de modelos no está disponible"
+	default:
+		return err.Error()
+	}
+}
+
+// validCallbackURL indica si rawURL es una URL http(s) absoluta
+func validCallbackURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// cloneJob copia el job para que el worker y Submit no compartan el puntero
+func cloneJob(job *domain.Job) *domain.Job {
+	clone := *job
+	return &clone
+}
@@ -0,0 +1,46 @@
+// Package application - Valores por defecto según el idioma del mensaje
+package application
+
+import "groq-hexagonal-api/internal/domain"
+
+// ============================================================================
+// POLÍTICA DE IDIOMA (LOCALE)
+// ============================================================================
+//
+// Si hay un detector configurado, se detecta el idioma de cada mensaje y se
+// informa en los metadatos de la respuesta. Opcionalmente, cada idioma puede
+// tener un perfil con:
+//   - Model: modelo a usar cuando el cliente no indica ninguno
+//   - SystemPrompt: instrucciones de sistema para ese idioma
+//
+// Ejemplo: para "es" usar un prompt "Responde siempre en español".
+// ============================================================================
+
+// LocaleProfile son los valores por defecto de un idioma
+type LocaleProfile struct {
+	Model        string
+	SystemPrompt string
+}
+
+// LocalePolicy combina el detector de idioma con los perfiles por idioma
+type LocalePolicy struct {
+	// Detector es el puerto de detección (nil = detección desactivada)
+	Detector domain.LanguageDetector
+
+	// Profiles mapea código de idioma → perfil
+	Profiles map[string]LocaleProfile
+}
+
+// Resolve detecta el idioma del mensaje y retorna su perfil (si existe)
+func (p LocalePolicy) Resolve(message string) (string, LocaleProfile) {
+	if p.Detector == nil {
+		return "", LocaleProfile{}
+	}
+
+	language := p.Detector.Detect(message)
+	if language == "" {
+		return "", LocaleProfile{}
+	}
+
+	return language, p.Profiles[language]
+}
@@ -0,0 +1,97 @@
+// Package application - Catálogo de alias y modelos retirados
+package application
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+)
+
+// ============================================================================
+// CATÁLOGO DE ALIAS DE MODELOS
+// ============================================================================
+//
+// Cuando Groq responde que un modelo ha sido retirado (model_decommissioned),
+// el servicio busca su reemplazo en el catálogo y repite la petición con él.
+// A partir de ese momento, el modelo queda marcado como retirado y las
+// siguientes peticiones se redirigen directamente, sin el viaje fallido.
+//
+// Cada petición a un modelo retirado se notifica al DeprecationReporter con
+// la lista de modelos retirados que siguen pidiéndose.
+// ============================================================================
+
+// ModelAliasCatalog gestiona los reemplazos de modelos retirados
+// Se usa por puntero: guarda estado compartido entre peticiones
+type ModelAliasCatalog struct {
+	// aliases mapea modelo retirado → modelo de reemplazo
+	aliases map[string]string
+
+	// reporter recibe los avisos (nil = sin avisos)
+	reporter domain.DeprecationReporter
+
+	// mu protege decommissioned: lo usan varias goroutines a la vez
+	mu sync.Mutex
+
+	// decommissioned cuenta las peticiones de cada modelo retirado conocido
+	decommissioned map[string]int
+}
+
+// NewModelAliasCatalog crea el catálogo a partir de los alias configurados
+func NewModelAliasCatalog(aliases map[string]string, reporter domain.DeprecationReporter) *ModelAliasCatalog {
+	return &ModelAliasCatalog{
+		aliases:        aliases,
+		reporter:       reporter,
+		decommissioned: make(map[string]int),
+	}
+}
+
+// Remap retorna el reemplazo de un modelo que ya se sabe retirado
+// ok es false si el modelo no está retirado o no tiene alias
+func (c *ModelAliasCatalog) Remap(ctx context.Context, model string) (replacement string, ok bool) {
+	if c == nil {
+		return "", false
+	}
+
+	c.mu.Lock()
+	_, known := c.decommissioned[model]
+	c.mu.Unlock()
+	if !known {
+		return "", false
+	}
+
+	replacement, ok = c.aliases[model]
+	c.record(ctx, model, replacement)
+	return replacement, ok
+}
+
+// Decommissioned marca el modelo como retirado (tras el error de Groq) y
+// retorna su reemplazo si el catálogo tiene uno
+func (c *ModelAliasCatalog) Decommissioned(ctx context.Context, model string) (replacement string, ok bool) {
+	if c == nil {
+		return "", false
+	}
+
+	replacement, ok = c.aliases[model]
+	c.record(ctx, model, replacement)
+	return replacement, ok
+}
+
+// record cuenta la petición y publica el aviso
+func (c *ModelAliasCatalog) record(ctx context.Context, model, replacement string) {
+	c.mu.Lock()
+	c.decommissioned[model]++
+	// Copia del map: el reporter puede leerlo después de soltar el lock
+	snapshot := make(map[string]int, len(c.decommissioned))
+	for name, count := range c.decommissioned {
+		snapshot[name] = count
+	}
+	c.mu.Unlock()
+
+	if c.reporter != nil {
+		c.reporter.ReportDecommissioned(ctx, domain.DecommissionEvent{
+			Model:          model,
+			Replacement:    replacement,
+			StillRequested: snapshot,
+		})
+	}
+}
@@ -0,0 +1,361 @@
+// Package application - Salud de los modelos y circuit breaker
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// MODEL HEALTH MONITOR
+// ============================================================================
+//
+// ModelHealthMonitor observa las llamadas a los proveedores (decorando cada
+// domain.LLMRepository con Wrap) y mantiene, por proveedor y modelo:
+//
+//   - Las peticiones de la ventana reciente (resultado y latencia)
+//   - Un circuit breaker: tras FailureThreshold fallos seguidos (5xx o
+//     timeouts), el modelo se da por caído durante Cooldown y las peticiones
+//     fallan al momento con 503 en lugar de esperar al timeout. Después pasa
+//     UNA petición de prueba: si va bien el circuito se cierra, si falla se
+//     vuelve a abrir
+//   - El último margen de rate limit anunciado por el proveedor
+//
+// Los errores del cliente (400, modelo inexistente, cancelaciones) no dicen
+// nada de la salud del modelo y no se cuentan.
+// ============================================================================
+
+// Valores por defecto de ModelHealthOptions
+const (
+	defaultHealthWindow      = 5 * time.Minute
+	defaultCircuitCooldown   = 30 * time.Second
+	maxHealthSamplesPerModel = 1000
+)
+
+// ModelHealthOptions configura el monitor
+type ModelHealthOptions struct {
+	// Window es el periodo sobre el que se calculan las estadísticas
+	Window time.Duration
+
+	// FailureThreshold son los fallos seguidos que abren el circuito
+	// (0 = circuit breaker desactivado, solo estadísticas)
+	FailureThreshold int
+
+	// Cooldown es el tiempo que el circuito está abierto antes de la prueba
+	Cooldown time.Duration
+}
+
+// ModelHealthMonitor implementa domain.ModelHealthService
+type ModelHealthMonitor struct {
+	options ModelHealthOptions
+
+	// mu protege models (lo usan todas las peticiones en curso)
+	mu     sync.Mutex
+	models map[modelKey]*modelStats
+}
+
+// modelKey identifica un modelo de un proveedor
+type modelKey struct {
+	provider string
+	model    string
+}
+
+// modelStats es el estado de un modelo
+type modelStats struct {
+	samples []healthSample
+
+	// Circuit breaker
+	circuit             domain.CircuitState
+	consecutiveFailures int
+	openUntil           time.Time
+	probing             bool // hay una petición de prueba en curso (half_open)
+
+	rateLimit *domain.RateLimitStatus
+}
+
+// healthSample es el resultado de una petición
+type healthSample struct {
+	at          time.Time
+	latency     time.Duration
+	failed      bool
+	rateLimited bool
+}
+
+// NewModelHealthMonitor crea el monitor
+func NewModelHealthMonitor(options ModelHealthOptions) *ModelHealthMonitor {
+	if options.Window <= 0 {
+		options.Window = defaultHealthWindow
+	}
+	if options.Cooldown <= 0 {
+		options.Cooldown = defaultCircuitCooldown
+	}
+
+	return &ModelHealthMonitor{
+		options: options,
+		models:  make(map[modelKey]*modelStats),
+	}
+}
+
+// Wrap decora el repositorio de un proveedor para medir sus llamadas
+func (m *ModelHealthMonitor) Wrap(provider string, repo domain.LLMRepository) domain.LLMRepository {
+	return &monitoredRepository{monitor: m, provider: provider, next: repo}
+}
+
+// RateLimitObserver retorna el observador de rate limit de un proveedor
+// Se pasa al adaptador (ej: groq.WithRateLimitObserver)
+func (m *ModelHealthMonitor) RateLimitObserver(provider string) domain.RateLimitObserver {
+	return rateLimitObserver{monitor: m, provider: provider}
+}
+
+// GetModelsHealth implementa domain.ModelHealthService
+// Los modelos se ordenan por proveedor y nombre
+func (m *ModelHealthMonitor) GetModelsHealth(ctx context.Context) []domain.ModelHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	health := make([]domain.ModelHealth, 0, len(m.models))
+	for key, stats := range m.models {
+		stats.trim(now.Add(-m.options.Window))
+		health = append(health, stats.health(key))
+	}
+
+	sort.Slice(health, func(i, j int) bool {
+		if health[i].Provider != health[j].Provider {
+			return health[i].Provider < health[j].Provider
+		}
+		return health[i].Model < health[j].Model
+	})
+	return health
+}
+
+// ============================================================================
+// CIRCUIT BREAKER
+// ============================================================================
+
+// allow decide si una petición puede ir al proveedor
+func (m *ModelHealthMonitor) allow(key modelKey) error {
+	if m.options.FailureThreshold <= 0 {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.statsFor(key)
+	now := time.Now()
+	switch stats.circuit {
+	case domain.CircuitOpen:
+		if now.Before(stats.openUntil) {
+			return circuitOpenError(key, stats.openUntil.Sub(now))
+		}
+		// Fin del enfriamiento: esta petición es la de prueba
+		stats.circuit = domain.CircuitHalfOpen
+		stats.probing = true
+	case domain.CircuitHalfOpen:
+		// Solo una petición de prueba a la vez
+		if stats.probing {
+			return circuitOpenError(key, m.options.Cooldown)
+		}
+		stats.probing = true
+	}
+	return nil
+}
+
+// record guarda el resultado de una petición y actualiza el circuito
+func (m *ModelHealthMonitor) record(key modelKey, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.statsFor(key)
+	stats.probing = false
+
+	outage := errors.Is(err, domain.ErrUpstreamUnavailable) || errors.Is(err, domain.ErrUpstreamTimeout)
+	rateLimited := errors.Is(err, domain.ErrRateLimited)
+	if err != nil && !outage && !rateLimited {
+		// Error del cliente o cancelación: no dice nada del modelo
+		return
+	}
+
+	now := time.Now()
+	stats.samples = append(stats.samples, healthSample{
+		at:          now,
+		latency:     latency,
+		failed:      err != nil,
+		rateLimited: rateLimited,
+	})
+	stats.trim(now.Add(-m.options.Window))
+
+	switch {
+	case err == nil:
+		stats.consecutiveFailures = 0
+		stats.circuit = domain.CircuitClosed
+	case outage:
+		stats.consecutiveFailures++
+		// En half_open basta un fallo; en closed, FailureThreshold seguidos
+		if m.options.FailureThreshold > 0 &&
+			(stats.circuit == domain.CircuitHalfOpen || stats.consecutiveFailures >= m.options.FailureThreshold) {
+			stats.circuit = domain.CircuitOpen
+			stats.openUntil = now.Add(m.options.Cooldown)
+		}
+	}
+}
+
+// observeRateLimit guarda el último margen de rate limit del modelo
+func (m *ModelHealthMonitor) observeRateLimit(key modelKey, status domain.RateLimitStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.statsFor(key).rateLimit = &status
+}
+
+// statsFor retorna el estado del modelo, creándolo si no existe
+// Debe llamarse con mu bloqueado
+func (m *ModelHealthMonitor) statsFor(key modelKey) *modelStats {
+	stats, ok := m.models[key]
+	if !ok {
+		stats = &modelStats{circuit: domain.CircuitClosed}
+		m.models[key] = stats
+	}
+	return stats
+}
+
+// circuitOpenError es el error de una petición rechazada por el circuito
+// Es un ErrUpstreamUnavailable: el cliente recibe un 503 con Retry-After
+func circuitOpenError(key modelKey, retryAfter time.Duration) error {
+	return &domain.UpstreamError{
+		Kind:       domain.ErrUpstreamUnavailable,
+		Message:    fmt.Sprintf("el modelo %s de %s no está disponible (circuito abierto tras varios fallos seguidos)", key.model, key.provider),
+		RetryAfter: retryAfter,
+	}
+}
+
+// ============================================================================
+// ESTADÍSTICAS
+// ============================================================================
+
+// trim descarta las muestras anteriores a since (y las que sobran del máximo)
+func (s *modelStats) trim(since time.Time) {
+	first := 0
+	for first < len(s.samples) && s.samples[first].at.Before(since) {
+		first++
+	}
+	if excess := len(s.samples) - first - maxHealthSamplesPerModel; excess > 0 {
+		first += excess
+	}
+	if first > 0 {
+		// Copiar a un slice nuevo libera el array antiguo
+		s.samples = append([]healthSample(nil), s.samples[first:]...)
+	}
+}
+
+// health calcula la salud del modelo con las muestras de la ventana
+func (s *modelStats) health(key modelKey) domain.ModelHealth {
+	health := domain.ModelHealth{
+		Provider:  key.provider,
+		Model:     key.model,
+		Requests:  len(s.samples),
+		Circuit:   s.circuit,
+		RateLimit: s.rateLimit,
+	}
+	if s.circuit == domain.CircuitOpen {
+		health.CircuitOpenUntil = s.openUntil
+	}
+
+	var latencies []time.Duration
+	for _, sample := range s.samples {
+		if sample.failed {
+			health.Failures++
+		} else {
+			latencies = append(latencies, sample.latency)
+		}
+		if sample.rateLimited {
+			health.RateLimited++
+		}
+	}
+	if health.Requests > 0 {
+		health.ErrorRate = float64(health.Failures) / float64(health.Requests)
+	}
+	health.P95Latency = percentile(latencies, 0.95)
+	return health
+}
+
+// percentile calcula el percentil p (0-1) por el método nearest-rank
+func percentile(values []time.Duration, p float64) time.Duration {
+	if len(values) == 0 {
+		return 0
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	// nearest-rank: el menor valor que deja por debajo al menos el p% de los datos
+	rank := int(float64(len(values))*p+0.999999) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return values[rank]
+}
+
+// ============================================================================
+// DECORADOR DEL REPOSITORIO
+// ============================================================================
+
+// monitoredRepository mide las llamadas de un proveedor
+type monitoredRepository struct {
+	monitor  *ModelHealthMonitor
+	provider string
+	next     domain.LLMRepository
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (r *monitoredRepository) CreateChatCompletion(ctx context.Context, request domain.ChatRequest) (*domain.ChatResponse, error) {
+	key := modelKey{provider: r.provider, model: request.Model}
+	if err := r.monitor.allow(key); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	response, err := r.next.CreateChatCompletion(ctx, request)
+	r.monitor.record(key, time.Since(start), err)
+	return response, err
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+// La latencia es la de apertura del flujo (hasta las cabeceras de respuesta)
+func (r *monitoredRepository) CreateChatCompletionStream(ctx context.Context, request domain.ChatRequest) (domain.ChatStream, error) {
+	key := modelKey{provider: r.provider, model: request.Model}
+	if err := r.monitor.allow(key); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	stream, err := r.next.CreateChatCompletionStream(ctx, request)
+	r.monitor.record(key, time.Since(start), err)
+	return stream, err
+}
+
+// ListModels implementa domain.LLMRepository (no se mide)
+func (r *monitoredRepository) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	return r.next.ListModels(ctx)
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+// No se mide: el body no se interpreta, así que no se conoce el modelo
+func (r *monitoredRepository) ProxyChatCompletion(ctx context.Context, body []byte, stream bool) (*domain.ProxyResponse, error) {
+	return r.next.ProxyChatCompletion(ctx, body, stream)
+}
+
+// rateLimitObserver implementa domain.RateLimitObserver para un proveedor
+type rateLimitObserver struct {
+	monitor  *ModelHealthMonitor
+	provider string
+}
+
+// ObserveRateLimit implementa domain.RateLimitObserver
+func (o rateLimitObserver) ObserveRateLimit(model string, status domain.RateLimitStatus) {
+	o.monitor.observeRateLimit(modelKey{provider: o.provider, model: model}, status)
+}
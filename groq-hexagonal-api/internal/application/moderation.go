@@ -0,0 +1,277 @@
+// Package application - Moderación de contenido con Llama Guard
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"slices"
+	"strings"
+)
+
+// ============================================================================
+// MODERACIÓN ANTES Y DESPUÉS DEL MODELO
+// ============================================================================
+//
+// ModeratingRepository envuelve el domain.LLMRepository y pasa la
+// conversación por Llama Guard (ver domain/moderation.go):
+//
+//   - Entrada: antes de llamar al modelo, con los mensajes del usuario y del
+//     asistente. Si se marca, el modelo principal no llega a llamarse
+//   - Salida: después, con la conversación más la respuesta (la primera, si
+//     hay varias). Solo en las respuestas completas: en streaming y en el
+//     modo proxy el texto ya se ha enviado (o se reenvía sin tocar)
+//
+// Como RedactingRepository, al envolver el repositorio cubre todo lo que
+// llega al modelo (chat, conversaciones, jobs, código...). Las llamadas a
+// Llama Guard van al repositorio guard, sin moderar (y, si está activada, ya
+// con la anonimización: los datos personales tampoco salen en la moderación).
+//
+// Un contenido marcado con alguna de las categorías de la política es un
+// *domain.ModerationError (422 con las categorías); las demás se ignoran.
+// ============================================================================
+
+// ModerationPolicy configura la moderación del despliegue
+type ModerationPolicy struct {
+	// Model es el modelo de Llama Guard (ej: meta-llama/llama-guard-4-12b)
+	Model string
+
+	// Provider es el proveedor del modelo (vacío = el por defecto)
+	Provider string
+
+	// Input y Output eligen qué se modera
+	Input  bool
+	Output bool
+
+	// Categories son las etiquetas que bloquean (vacío = todas)
+	Categories []string
+
+	// FailOpen deja pasar la petición si Llama Guard no responde; por
+	// defecto la petición falla con el error del proveedor
+	FailOpen bool
+}
+
+// blocks indica si la categoría está prohibida por la política
+func (p ModerationPolicy) blocks(label string) bool {
+	return len(p.Categories) == 0 || slices.Contains(p.Categories, label)
+}
+
+// ModeratingRepository modera la entrada y la salida del repositorio envuelto
+// Implementa domain.LLMRepository
+type ModeratingRepository struct {
+	next   domain.LLMRepository
+	guard  domain.LLMRepository
+	policy ModerationPolicy
+}
+
+// NewModeratingRepository crea el repositorio que modera
+// guard es el repositorio con el que se llama a Llama Guard
+func NewModeratingRepository(next, guard domain.LLMRepository, policy ModerationPolicy) *ModeratingRepository {
+	if next == nil {
+		panic("next no puede ser nil")
+	}
+	if guard == nil {
+		panic("guard no puede ser nil")
+	}
+	if policy.Model == "" {
+		panic("el modelo de moderación no puede estar vacío")
+	}
+
+	return &ModeratingRepository{
+		next:   next,
+		guard:  guard,
+		policy: policy,
+	}
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (r *ModeratingRepository) CreateChatCompletion(
+	ctx context.Context,
+	request domain.ChatRequest,
+) (*domain.ChatResponse, error) {
+	if r.policy.Input {
+		if err := r.moderate(ctx, domain.ModerationInput, request.Messages); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := r.next.CreateChatCompletion(ctx, request)
+	if err != nil || !r.policy.Output {
+		return response, err
+	}
+
+	// Una petición de herramientas no tiene texto que moderar
+	if content := response.GetResponseContent(); content != "" {
+		conversation := append(slices.Clip(request.Messages), domain.ChatMessage{Role: "assistant", Content: content})
+		if err := r.moderate(ctx, domain.ModerationOutput, conversation); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+// Solo se modera la entrada (ver arriba)
+func (r *ModeratingRepository) CreateChatCompletionStream(
+	ctx context.Context,
+	request domain.ChatRequest,
+) (domain.ChatStream, error) {
+	if r.policy.Input {
+		if err := r.moderate(ctx, domain.ModerationInput, request.Messages); err != nil {
+			return nil, err
+		}
+	}
+	return r.next.CreateChatCompletionStream(ctx, request)
+}
+
+// ListModels implementa domain.LLMRepository
+func (r *ModeratingRepository) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	return r.next.ListModels(ctx)
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+// Solo se modera la entrada: la respuesta se reenvía sin tocar
+func (r *ModeratingRepository) ProxyChatCompletion(
+	ctx context.Context,
+	body []byte,
+	stream bool,
+) (*domain.ProxyResponse, error) {
+	if r.policy.Input {
+		messages, err := proxyConversation(body)
+		if err != nil {
+			return nil, err
+		}
+		if err := r.moderate(ctx, domain.ModerationInput, messages); err != nil {
+			return nil, err
+		}
+	}
+	return r.next.ProxyChatCompletion(ctx, body, stream)
+}
+
+// moderate pasa la conversación por Llama Guard
+// Retorna un *domain.ModerationError si alguna categoría está prohibida
+func (r *ModeratingRepository) moderate(ctx context.Context, stage string, messages []domain.ChatMessage) error {
+	conversation := guardConversation(messages)
+	if len(conversation) == 0 {
+		return nil
+	}
+
+	temperature := 0.0
+	response, err := r.guard.CreateChatCompletion(domain.WithProvider(ctx, r.policy.Provider), domain.ChatRequest{
+		Model:       r.policy.Model,
+		Messages:    conversation,
+		Temperature: &temperature,
+		// El veredicto es "safe" o "unsafe" y los códigos: bastan unos pocos tokens
+		MaxTokens: 32,
+	})
+	var categories []string
+	if err == nil {
+		categories, err = parseGuardVerdict(response.GetResponseContent())
+	}
+	if err != nil {
+		if r.policy.FailOpen {
+			log.Printf("⚠️  Moderación no disponible (%s), la petición continúa: %v", stage, err)
+			return nil
+		}
+		return fmt.Errorf("error en la moderación: %w", err)
+	}
+
+	var blocked []string
+	for _, label := range categories {
+		if r.policy.blocks(label) {
+			blocked = append(blocked, label)
+		}
+	}
+	if len(blocked) == 0 {
+		return nil
+	}
+
+	log.Printf("🚫 MODERATION event=content_flagged tenant=%s stage=%s categories=%s",
+		domain.TenantFromContext(ctx), stage, strings.Join(blocked, ","))
+	return &domain.ModerationError{Stage: stage, Categories: blocked}
+}
+
+// guardConversation son los turnos que entiende Llama Guard: usuario y
+// asistente con texto (sin instrucciones de sistema ni resultados de
+// herramientas)
+func guardConversation(messages []domain.ChatMessage) []domain.ChatMessage {
+	var conversation []domain.ChatMessage
+	for _, message := range messages {
+		if (message.Role == "user" || message.Role == "assistant") && strings.TrimSpace(message.Content) != "" {
+			conversation = append(conversation, domain.ChatMessage{Role: message.Role, Content: message.Content})
+		}
+	}
+	return conversation
+}
+
+// parseGuardVerdict interpreta la respuesta de Llama Guard:
+//
+//	safe
+//	unsafe\nS1,S10
+//
+// Retorna las etiquetas de las categorías (ninguna si es "safe")
+func parseGuardVerdict(text string) ([]string, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(text)))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: veredicto de moderación vacío", domain.ErrInvalidModelOutput)
+	}
+
+	switch fields[0] {
+	case "safe":
+		return nil, nil
+	case "unsafe":
+	default:
+		return nil, fmt.Errorf("%w: veredicto de moderación desconocido: %q", domain.ErrInvalidModelOutput, fields[0])
+	}
+
+	var categories []string
+	for _, field := range fields[1:] {
+		for _, code := range strings.Split(field, ",") {
+			if code = strings.TrimSpace(code); code != "" {
+				categories = append(categories, domain.ModerationCategoryLabel(code))
+			}
+		}
+	}
+	// "unsafe" sin categorías: se bloquea igual, sin etiqueta concreta
+	if len(categories) == 0 {
+		categories = []string{"unspecified"}
+	}
+	return categories, nil
+}
+
+// proxyConversation extrae los mensajes de un body de /chat/completions
+// (content en texto o en partes "text")
+func proxyConversation(body []byte) ([]domain.ChatMessage, error) {
+	var payload struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: el body debe ser un objeto JSON: %v", domain.ErrInvalidRequest, err)
+	}
+
+	messages := make([]domain.ChatMessage, 0, len(payload.Messages))
+	for _, message := range payload.Messages {
+		var text string
+		if json.Unmarshal(message.Content, &text) != nil {
+			var parts []struct {
+				Type string `json:"type"`
+				Text string `json:"text"`
+			}
+			json.Unmarshal(message.Content, &parts)
+			var texts []string
+			for _, part := range parts {
+				if part.Type == "text" {
+					texts = append(texts, part.Text)
+				}
+			}
+			text = strings.Join(texts, "\n")
+		}
+		messages = append(messages, domain.ChatMessage{Role: message.Role, Content: text})
+	}
+	return messages, nil
+}
@@ -0,0 +1,84 @@
+// Package application - Límite de longitud de las respuestas
+package application
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// GUARDIA DE LONGITUD DE SALIDA
+// ============================================================================
+//
+// Algunos productos necesitan acotar el tamaño de las respuestas (por
+// ejemplo, para mostrarlas en un widget o en un SMS). OutputGuard se aplica
+// DESPUÉS de la generación y recorta el texto que supere el máximo del tenant.
+//
+// El recorte es "inteligente": se corta en el último final de frase que cabe
+// en el límite. Si no hay ninguno, se corta en el último espacio, y solo como
+// último recurso se corta a mitad de palabra.
+// ============================================================================
+
+// OutputGuard define la longitud máxima (en caracteres) de las respuestas
+type OutputGuard struct {
+	// DefaultMaxChars se aplica a los tenants sin límite propio (0 = sin límite)
+	DefaultMaxChars int
+
+	// TenantMaxChars contiene los límites por tenant
+	TenantMaxChars map[string]int
+}
+
+// limitFor retorna el límite aplicable al tenant (0 = sin límite)
+func (g OutputGuard) limitFor(tenantID string) int {
+	if limit, ok := g.TenantMaxChars[tenantID]; ok {
+		return limit
+	}
+	return g.DefaultMaxChars
+}
+
+// Apply recorta el texto según el límite del tenant
+// Retorna el texto (posiblemente recortado) y si hubo recorte
+func (g OutputGuard) Apply(tenantID, text string) (string, bool) {
+	limit := g.limitFor(tenantID)
+	if limit <= 0 {
+		return text, false
+	}
+	return truncateAtSentence(text, limit)
+}
+
+// truncateAtSentence recorta text a maxChars caracteres respetando frases
+func truncateAtSentence(text string, maxChars int) (string, bool) {
+	// Trabajamos con runes (caracteres Unicode), no con bytes,
+	// para no partir caracteres como "á" o emojis
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text, false
+	}
+	window := runes[:maxChars]
+
+	// 1. Buscar el último final de frase dentro de la ventana
+	for i := len(window) - 1; i >= 0; i-- {
+		if isSentenceEnd(window[i]) {
+			return strings.TrimSpace(string(window[:i+1])), true
+		}
+	}
+
+	// 2. Si no hay, cortar en el último espacio
+	for i := len(window) - 1; i > 0; i-- {
+		if unicode.IsSpace(window[i]) {
+			return strings.TrimSpace(string(window[:i])), true
+		}
+	}
+
+	// 3. Último recurso: corte duro
+	return string(window), true
+}
+
+// isSentenceEnd indica si r termina una frase
+func isSentenceEnd(r rune) bool {
+	switch r {
+	case '.', '!', '?', '。', '\n':
+		return true
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+// Package application - Catálogo de personas con recarga en caliente
+package application
+
+import (
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+)
+
+// ============================================================================
+// CATÁLOGO DE PERSONAS
+// ============================================================================
+//
+// El catálogo guarda las personas en memoria y Refresh() las sustituye de
+// golpe por las que devuelve el PersonaSource. Si la carga falla, se
+// mantienen las anteriores: un commit roto en el repositorio de plantillas
+// no debe dejar a los clientes sin personas.
+// ============================================================================
+
+// PersonaCatalog es el conjunto de personas disponibles
+// Se usa por puntero: lo comparten el servicio de chat y el proceso de recarga
+type PersonaCatalog struct {
+	source domain.PersonaSource
+
+	// mu protege personas: Refresh la reemplaza mientras se atienden peticiones
+	mu       sync.RWMutex
+	personas map[string]domain.Persona
+}
+
+// NewPersonaCatalog crea un catálogo vacío; se llena con Refresh
+func NewPersonaCatalog(source domain.PersonaSource) *PersonaCatalog {
+	if source == nil {
+		panic("source no puede ser nil")
+	}
+
+	return &PersonaCatalog{
+		source:   source,
+		personas: make(map[string]domain.Persona),
+	}
+}
+
+// Refresh recarga las personas desde el origen y retorna cuántas hay
+func (c *PersonaCatalog) Refresh(ctx context.Context) (int, error) {
+	personas, err := c.source.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error al cargar las personas: %w", err)
+	}
+
+	c.mu.Lock()
+	c.personas = personas
+	c.mu.Unlock()
+
+	return len(personas), nil
+}
+
+// Get retorna la persona con ese nombre
+// Es nil-safe: sin catálogo, ninguna persona existe
+func (c *PersonaCatalog) Get(name string) (domain.Persona, error) {
+	if c == nil {
+		return domain.Persona{}, fmt.Errorf("%w: %s", domain.ErrPersonaNotFound, name)
+	}
+
+	c.mu.RLock()
+	persona, ok := c.personas[name]
+	c.mu.RUnlock()
+	if !ok {
+		return domain.Persona{}, fmt.Errorf("%w: %s", domain.ErrPersonaNotFound, name)
+	}
+	return persona, nil
+}
@@ -0,0 +1,82 @@
+// Package application - Recorte del prompt por secciones
+package application
+
+import "groq-hexagonal-api/internal/domain"
+
+// ============================================================================
+// RECORTE DEL PROMPT
+// ============================================================================
+//
+// Cuando el prompt no cabe, ContextGuard lo recorta sección a sección, en el
+// orden que elija el operador (CONTEXT_SHRINK_ORDER). Cada sección se recorta
+// lo justo para caber antes de pasar a la siguiente:
+//
+//   - documents y examples: se descartan los últimos (los documentos suelen
+//     venir ordenados por relevancia) y se vuelve a escribir su mensaje de
+//     sistema con los que quedan
+//   - history: se descartan los turnos más antiguos (ver dropOldestTurn)
+//
+// Las secciones vacías o desconocidas no recortan nada.
+// ============================================================================
+
+// shrink recorta la sección name hasta que la petición quepa en budget
+// Retorna cuántos elementos se han descartado
+func (p *assembledPrompt) shrink(request *domain.ChatRequest, name string, budget int) int {
+	switch name {
+	case domain.PromptSectionDocuments:
+		removed := p.shrinkItems(request, name, len(p.parts.documents), budget, func(kept int) string {
+			return domain.DocumentsPrompt(p.parts.documents[:kept])
+		})
+		p.parts.documents = p.parts.documents[:len(p.parts.documents)-removed]
+		return removed
+	case domain.PromptSectionExamples:
+		removed := p.shrinkItems(request, name, len(p.parts.examples), budget, func(kept int) string {
+			return domain.FewShotPrompt(p.parts.examples[:kept])
+		})
+		p.parts.examples = p.parts.examples[:len(p.parts.examples)-removed]
+		return removed
+	case domain.PromptSectionHistory:
+		return p.shrinkHistory(request, budget)
+	}
+	return 0
+}
+
+// shrinkItems descarta los últimos elementos de una sección de un mensaje de
+// sistema (render lo escribe con los kept primeros) hasta que quepa
+// Retorna cuántos elementos se han descartado
+func (p *assembledPrompt) shrinkItems(request *domain.ChatRequest, name string, count, budget int, render func(kept int) string) int {
+	section := findPromptSection(p.sections, name)
+	if section.Name == "" {
+		return 0
+	}
+
+	kept := count
+	for kept > 0 && domain.EstimatePromptTokens(*request) > budget {
+		kept--
+		messages := systemMessage(render(kept))
+
+		// request.Messages[:start:start] fuerza una copia: los mensajes
+		// pueden compartir memoria con el historial del llamador
+		replaced := append(request.Messages[:section.Start:section.Start], messages...)
+		request.Messages = append(replaced, request.Messages[section.End:]...)
+
+		p.resize(name, len(messages)-(section.End-section.Start))
+		section = findPromptSection(p.sections, name)
+	}
+	return count - kept
+}
+
+// shrinkHistory descarta los turnos más antiguos hasta que quepa
+// Retorna cuántos mensajes se han descartado
+func (p *assembledPrompt) shrinkHistory(request *domain.ChatRequest, budget int) int {
+	dropped := 0
+	for domain.EstimatePromptTokens(*request) > budget {
+		removed := dropOldestTurn(request)
+		if removed == 0 {
+			break
+		}
+		dropped += removed
+		p.resize(domain.PromptSectionHistory, -removed)
+	}
+	return dropped
+}
@@ -0,0 +1,141 @@
+// Package application - Montaje del prompt por secciones
+package application
+
+import "groq-hexagonal-api/internal/domain"
+
+// ============================================================================
+// MONTAJE DEL PROMPT
+// ============================================================================
+//
+// buildRequest reúne las piezas del prompt (promptParts) y promptPipeline las
+// convierte en mensajes, sección a sección y siempre en el mismo orden (ver
+// domain/prompt_sections.go). Cada sección recuerda qué mensajes aporta, así
+// un dry run puede mostrar cuántos tokens se lleva cada una.
+//
+// Para añadir una sección nueva basta con una pieza más en promptParts y un
+// paso en promptPipeline, en la posición que le corresponda.
+// ============================================================================
+
+// promptParts son las piezas del prompt antes de montarlo
+type promptParts struct {
+	tenantPrefix  string
+	systemPrompt  string
+	personaPrompt string
+	localePrompt  string
+	examples      []domain.FewShotExample
+	documents     []string
+	history       []domain.ChatMessage
+	message       string
+	images        []domain.ImageURL
+}
+
+// assembledPrompt es un prompt montado: sus piezas y las secciones de la
+// petición (nil si ya no cuadran con sus mensajes)
+type assembledPrompt struct {
+	parts    promptParts
+	sections []domain.PromptSection
+}
+
+// promptStep es un paso del montaje: los mensajes de una sección
+type promptStep struct {
+	section  string
+	messages func(parts promptParts) []domain.ChatMessage
+}
+
+// promptPipeline son los pasos del montaje, en orden
+var promptPipeline = []promptStep{
+	{domain.PromptSectionTenant, func(p promptParts) []domain.ChatMessage { return systemMessage(p.tenantPrefix) }},
+	{domain.PromptSectionSystem, func(p promptParts) []domain.ChatMessage { return systemMessage(p.systemPrompt) }},
+	{domain.PromptSectionPersona, func(p promptParts) []domain.ChatMessage { return systemMessage(p.personaPrompt) }},
+	{domain.PromptSectionLocale, func(p promptParts) []domain.ChatMessage { return systemMessage(p.localePrompt) }},
+	{domain.PromptSectionExamples, func(p promptParts) []domain.ChatMessage { return systemMessage(domain.FewShotPrompt(p.examples)) }},
+	{domain.PromptSectionDocuments, func(p promptParts) []domain.ChatMessage { return systemMessage(domain.DocumentsPrompt(p.documents)) }},
+	{domain.PromptSectionHistory, func(p promptParts) []domain.ChatMessage { return p.history }},
+	{domain.PromptSectionUser, userMessage},
+}
+
+// assemblePrompt añade a la petición los mensajes de todas las secciones
+// Retorna las secciones, con los mensajes que aporta cada una
+func assemblePrompt(request *domain.ChatRequest, parts promptParts) []domain.PromptSection {
+	sections := make([]domain.PromptSection, len(promptPipeline))
+	for i, step := range promptPipeline {
+		start := len(request.Messages)
+		request.Messages = append(request.Messages, step.messages(parts)...)
+		sections[i] = domain.PromptSection{Name: step.section, Start: start, End: len(request.Messages)}
+	}
+	return sections
+}
+
+// findPromptSection retorna la sección con ese nombre
+func findPromptSection(sections []domain.PromptSection, name string) domain.PromptSection {
+	for _, section := range sections {
+		if section.Name == name {
+			return section
+		}
+	}
+	return domain.PromptSection{}
+}
+
+// fitPromptSections ajusta las secciones tras cambiar el historial
+// El resumen del historial y los hooks pueden quitar o añadir mensajes: los que falten o sobren se cuentan en el historial (los hooks
+// que añaden mensajes en otro sitio descuadran la atribución, no el total)
+// Retorna nil si ya no cuadran (ej: un hook ha quitado mensajes de sistema)
+func fitPromptSections(sections []domain.PromptSection, messages int) []domain.PromptSection {
+	last := len(sections) - 1
+	if last < 0 {
+		return nil
+	}
+	shift := messages - sections[last].End
+
+	fitted := append([]domain.PromptSection(nil), sections...)
+	afterHistory := false
+	for i := range fitted {
+		switch {
+		case afterHistory:
+			fitted[i].Start += shift
+			fitted[i].End += shift
+		case fitted[i].Name == domain.PromptSectionHistory:
+			fitted[i].End += shift
+			if fitted[i].End < fitted[i].Start {
+				return nil
+			}
+			afterHistory = true
+		}
+	}
+	return fitted
+}
+
+// resize cambia en delta los mensajes de la sección name y desplaza las
+// siguientes (nada si la sección no existe)
+func (p *assembledPrompt) resize(name string, delta int) {
+	shift := false
+	for i := range p.sections {
+		switch {
+		case shift:
+			p.sections[i].Start += delta
+			p.sections[i].End += delta
+		case p.sections[i].Name == name:
+			p.sections[i].End += delta
+			shift = true
+		}
+	}
+}
+
+// systemMessage es una sección de un mensaje de sistema (ninguno si está vacío)
+func systemMessage(content string) []domain.ChatMessage {
+	if content == "" {
+		return nil
+	}
+	return []domain.ChatMessage{domain.NewChatMessage("system", content)}
+}
+
+// userMessage es la sección del mensaje actual, con sus imágenes
+// Sin mensaje (tras resultados de herramientas) no aporta nada
+func userMessage(parts promptParts) []domain.ChatMessage {
+	if parts.message == "" {
+		return nil
+	}
+	message := domain.NewChatMessage("user", parts.message)
+	message.Images = parts.images
+	return []domain.ChatMessage{message}
+}
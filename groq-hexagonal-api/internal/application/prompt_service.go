@@ -0,0 +1,123 @@
+// Package application - Caso de uso de mejora de prompts
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strings"
+)
+
+// ============================================================================
+// ERRORES
+// ============================================================================
+
+var (
+	ErrEmptyPrompt      = errors.New("el prompt no puede estar vacío")
+	ErrInvalidVariants  = errors.New("variants debe estar entre 0 y 5")
+	ErrInvalidMetaReply = fmt.Errorf("%w: no es una propuesta válida", domain.ErrInvalidModelOutput)
+)
+
+// MaxPromptVariants es el máximo de variantes que se pueden pedir
+const MaxPromptVariants = 5
+
+// defaultPromptVariants se usa cuando el cliente no indica cuántas variantes quiere
+const defaultPromptVariants = 2
+
+// ============================================================================
+// META-PROMPT
+// ============================================================================
+//
+// Un "meta-prompt" es un prompt que trabaja SOBRE otro prompt. Pedimos al
+// modelo que actúe como experto en prompt engineering y que responda con un
+// JSON fijo, así podemos parsear la respuesta sin adivinar su formato.
+// ============================================================================
+
+const promptImproverSystem = `Eres un experto en prompt engineering para modelos de lenguaje.
+Analiza el prompt del usuario y el resultado que quiere conseguir.
+Responde SOLO con un objeto JSON con esta forma:
+{
+  "improved_prompt": "versión mejorada del prompt",
+  "suggestions": ["cambio sugerido y por qué", "..."],
+  "variants": ["variante alternativa", "..."]
+}
+Mantén el idioma del prompt original.`
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// PromptServiceImpl implementa domain.PromptService
+type PromptServiceImpl struct {
+	llmRepo domain.LLMRepository
+
+	// model es el modelo "fuerte" que ejecuta el meta-prompt
+	model string
+}
+
+// NewPromptService crea el servicio de mejora de prompts
+func NewPromptService(repo domain.LLMRepository, model string) domain.PromptService {
+	if repo == nil {
+		panic("llmRepo no puede ser nil")
+	}
+
+	return &PromptServiceImpl{
+		llmRepo: repo,
+		model:   model,
+	}
+}
+
+// ImprovePrompt ejecuta el meta-prompt y parsea la propuesta del modelo
+func (s *PromptServiceImpl) ImprovePrompt(
+	ctx context.Context,
+	input domain.PromptImprovementRequest,
+) (*domain.PromptImprovement, error) {
+	if strings.TrimSpace(input.Prompt) == "" {
+		return nil, ErrEmptyPrompt
+	}
+	if input.Variants < 0 || input.Variants > MaxPromptVariants {
+		return nil, ErrInvalidVariants
+	}
+	if input.Variants == 0 {
+		input.Variants = defaultPromptVariants
+	}
+
+	// El mensaje del usuario describe el trabajo a hacer
+	var userMessage strings.Builder
+	fmt.Fprintf(&userMessage, "Prompt original:\n%s\n\n", input.Prompt)
+	if input.DesiredOutcome != "" {
+		fmt.Fprintf(&userMessage, "Resultado deseado:\n%s\n\n", input.DesiredOutcome)
+	}
+	fmt.Fprintf(&userMessage, "Genera exactamente %d variantes.", input.Variants)
+
+	request := domain.NewChatRequest(s.model, []domain.ChatMessage{
+		domain.NewChatMessage("system", promptImproverSystem),
+		domain.NewChatMessage("user", userMessage.String()),
+	})
+	request.SetTemperature(0.4) // Algo de creatividad, pero respuestas estables
+	request.ResponseFormat = domain.JSONResponseFormat
+
+	response, err := s.llmRepo.CreateChatCompletion(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener respuesta de Groq: %w", err)
+	}
+
+	var improvement domain.PromptImprovement
+	if err := json.Unmarshal([]byte(response.GetResponseContent()), &improvement); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMetaReply, err)
+	}
+	if improvement.ImprovedPrompt == "" {
+		return nil, ErrInvalidMetaReply
+	}
+
+	// El modelo a veces genera más variantes de las pedidas
+	if len(improvement.Variants) > input.Variants {
+		improvement.Variants = improvement.Variants[:input.Variants]
+	}
+
+	improvement.Model = response.Model
+	improvement.Usage = response.Usage
+	return &improvement, nil
+}
@@ -0,0 +1,91 @@
+// Package application - Enrutado entre proveedores de modelos
+package application
+
+import (
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// PROVIDER ROUTER
+// ============================================================================
+//
+// ProviderRouter implementa domain.LLMRepository eligiendo el proveedor de
+// cada petición (Groq, OpenAI, Ollama...) con domain.ProviderFromContext():
+//
+//   - Sin proveedor en el contexto, se usa el por defecto del despliegue
+//   - Con un proveedor no configurado, se retorna domain.ErrUnknownProvider
+//
+// Se compone con StickyRouter: el proveedor "groq" puede ser a su vez un
+// StickyRouter con varias API keys.
+// ============================================================================
+
+// ProviderRouter envía cada petición a su proveedor
+type ProviderRouter struct {
+	providers       map[string]domain.LLMRepository
+	defaultProvider string
+}
+
+// NewProviderRouter crea el router; defaultProvider debe estar en providers
+func NewProviderRouter(providers map[string]domain.LLMRepository, defaultProvider string) domain.LLMRepository {
+	if _, ok := providers[defaultProvider]; !ok {
+		panic(fmt.Sprintf("el proveedor por defecto %q no está configurado", defaultProvider))
+	}
+
+	return &ProviderRouter{
+		providers:       providers,
+		defaultProvider: defaultProvider,
+	}
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (r *ProviderRouter) CreateChatCompletion(ctx context.Context, request domain.ChatRequest) (*domain.ChatResponse, error) {
+	provider, err := r.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateChatCompletion(ctx, request)
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+func (r *ProviderRouter) CreateChatCompletionStream(ctx context.Context, request domain.ChatRequest) (domain.ChatStream, error) {
+	provider, err := r.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateChatCompletionStream(ctx, request)
+}
+
+// ListModels implementa domain.LLMRepository
+// Lista los modelos de un solo proveedor (el elegido o el por defecto)
+func (r *ProviderRouter) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	provider, err := r.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ListModels(ctx)
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+func (r *ProviderRouter) ProxyChatCompletion(ctx context.Context, body []byte, stream bool) (*domain.ProxyResponse, error) {
+	provider, err := r.pick(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ProxyChatCompletion(ctx, body, stream)
+}
+
+// pick elige el proveedor de la petición
+func (r *ProviderRouter) pick(ctx context.Context) (domain.LLMRepository, error) {
+	name := domain.ProviderFromContext(ctx)
+	if name == "" {
+		name = r.defaultProvider
+	}
+
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrUnknownProvider, name)
+	}
+	return provider, nil
+}
@@ -0,0 +1,186 @@
+// Package application - Caso de uso del modo proxy (passthrough)
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"sync"
+)
+
+// maxUsageCaptureBytes limita cuánto de una respuesta sin streaming se guarda
+// para leer el uso de tokens (el resto se reenvía igualmente al cliente)
+const maxUsageCaptureBytes = 1 << 20 // 1 MiB
+
+// usageSourceProxy identifica las peticiones del proxy en los UsageRecord
+const usageSourceProxy = "proxy"
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// ProxyServiceImpl implementa domain.ProxyService
+//
+// No aplica las políticas de /chat (stop sequences, system prompts, alias de
+// modelos...): quien usa el proxy quiere controlar el body completo.
+type ProxyServiceImpl struct {
+	repository domain.LLMRepository
+	recorder   domain.UsageRecorder
+}
+
+// NewProxyService crea el servicio del modo proxy
+func NewProxyService(repository domain.LLMRepository, recorder domain.UsageRecorder) domain.ProxyService {
+	if repository == nil {
+		panic("repository no puede ser nil")
+	}
+	if recorder == nil {
+		panic("recorder no puede ser nil")
+	}
+
+	return &ProxyServiceImpl{
+		repository: repository,
+		recorder:   recorder,
+	}
+}
+
+// proxyEnvelope son los únicos campos del body que el proxy necesita leer
+type proxyEnvelope struct {
+	Model  string `json:"model"`
+	Stream bool   `json:"stream"`
+}
+
+// ChatCompletions reenvía el body a Groq y registra el uso de tokens
+func (s *ProxyServiceImpl) ChatCompletions(ctx context.Context, body []byte) (*domain.ProxyResponse, error) {
+	// Se valida lo mínimo para no gastar una petición a Groq en algo que
+	// seguro fallará; el resto de campos los valida Groq
+	var envelope proxyEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: el body debe ser un objeto JSON: %v", domain.ErrInvalidRequest, err)
+	}
+	if envelope.Model == "" {
+		return nil, ErrEmptyModel
+	}
+
+	response, err := s.repository.ProxyChatCompletion(ctx, body, envelope.Stream)
+	if err != nil {
+		return nil, err
+	}
+
+	// Solo las respuestas correctas consumen tokens
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		response.Body = &usageCapture{
+			body:   response.Body,
+			stream: envelope.Stream,
+			model:  envelope.Model,
+			record: func(record domain.UsageRecord) {
+				s.recorder.RecordUsage(ctx, record)
+			},
+		}
+	}
+
+	return response, nil
+}
+
+// ============================================================================
+// CAPTURA DEL USO DE TOKENS
+// ============================================================================
+//
+// El body se reenvía al cliente según llega; usageCapture lo "espía" al
+// leerlo para encontrar el campo usage:
+//   - Sin streaming: un único JSON con "usage" al final
+//   - Con streaming: el último evento "data: {...}" trae x_groq.usage
+//     (o "usage" si el cliente pidió stream_options.include_usage)
+// El uso se registra al cerrar el body, cuando la respuesta ya terminó.
+// ============================================================================
+
+// usageCapture envuelve el body de la respuesta
+type usageCapture struct {
+	body   io.ReadCloser
+	stream bool
+	model  string
+	record func(domain.UsageRecord)
+
+	// buffer guarda la línea SSE incompleta (stream) o la respuesta (sin stream)
+	buffer bytes.Buffer
+	usage  *domain.Usage
+	once   sync.Once
+}
+
+// usagePayload son los campos de uso de una respuesta o un fragmento
+type usagePayload struct {
+	Model string        `json:"model"`
+	Usage *domain.Usage `json:"usage"`
+	XGroq *domain.XGroq `json:"x_groq"`
+}
+
+// Read implementa io.Reader
+func (c *usageCapture) Read(p []byte) (int, error) {
+	n, err := c.body.Read(p)
+	if n > 0 {
+		c.observe(p[:n])
+	}
+	return n, err
+}
+
+// Close implementa io.Closer y registra el uso encontrado
+func (c *usageCapture) Close() error {
+	c.once.Do(func() {
+		if !c.stream {
+			c.parse(c.buffer.Bytes())
+		}
+		if c.usage != nil {
+			c.record(domain.UsageRecord{Source: usageSourceProxy, Model: c.model, Usage: *c.usage})
+		}
+	})
+	return c.body.Close()
+}
+
+// observe procesa un trozo del body
+func (c *usageCapture) observe(data []byte) {
+	if !c.stream {
+		if c.buffer.Len()+len(data) <= maxUsageCaptureBytes {
+			c.buffer.Write(data)
+		}
+		return
+	}
+
+	// En SSE se procesan solo las líneas completas; el resto espera al siguiente trozo
+	// Next no copia: la línea apunta al buffer y parse no la guarda
+	c.buffer.Write(data)
+	for {
+		end := bytes.IndexByte(c.buffer.Bytes(), '\n')
+		if end < 0 {
+			return
+		}
+
+		line := bytes.TrimSpace(c.buffer.Next(end + 1))
+		if payload, ok := bytes.CutPrefix(line, []byte("data:")); ok {
+			c.parse(bytes.TrimSpace(payload))
+		}
+	}
+}
+
+// parse busca el uso de tokens en un JSON (se ignoran los que no lo traen)
+func (c *usageCapture) parse(data []byte) {
+	if !bytes.Contains(data, []byte(`"usage"`)) {
+		return
+	}
+
+	var payload usagePayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return
+	}
+
+	if payload.Model != "" {
+		c.model = payload.Model
+	}
+	switch {
+	case payload.Usage != nil:
+		c.usage = payload.Usage
+	case payload.XGroq != nil && payload.XGroq.Usage != nil:
+		c.usage = payload.XGroq.Usage
+	}
+}
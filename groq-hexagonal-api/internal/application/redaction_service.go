@@ -0,0 +1,151 @@
+// Package application - Caso de uso de anonimización de datos personales
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// ERRORES
+// ============================================================================
+
+var (
+	ErrEmptyRedactionText = errors.New("el texto a anonimizar no puede estar vacío")
+	ErrUnknownPIIType     = fmt.Errorf("tipo de dato personal desconocido (tipos válidos: %v)", domain.PIITypes)
+)
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// RedactionServiceImpl implementa domain.RedactionService
+type RedactionServiceImpl struct {
+	detector domain.PIIDetector
+}
+
+// NewRedactionService crea el servicio de anonimización
+func NewRedactionService(detector domain.PIIDetector) domain.RedactionService {
+	if detector == nil {
+		panic("piiDetector no puede ser nil")
+	}
+
+	return &RedactionServiceImpl{
+		detector: detector,
+	}
+}
+
+// Redact detecta los datos personales y los sustituye por marcadores
+// No llama a ningún modelo: el texto no sale del servidor
+func (s *RedactionServiceImpl) Redact(
+	ctx context.Context,
+	input domain.RedactionRequest,
+) (*domain.RedactionResult, error) {
+	if strings.TrimSpace(input.Text) == "" {
+		return nil, ErrEmptyRedactionText
+	}
+	for _, entityType := range input.Types {
+		if !domain.IsPIIType(entityType) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPIIType, entityType)
+		}
+	}
+
+	text, entities := newRedactor(s.detector, input.Types).redact(input.Text)
+	if entities == nil {
+		entities = []domain.RedactedEntity{}
+	}
+	return &domain.RedactionResult{Text: text, Entities: entities}, nil
+}
+
+// redactor sustituye datos personales por marcadores
+// Los marcadores se comparten entre todos los textos que anonimiza el mismo
+// redactor: en una conversación, [EMAIL_1] es el mismo email en todos los
+// mensajes
+type redactor struct {
+	detector domain.PIIDetector
+	types    []string
+
+	placeholders map[string]string
+	counters     map[string]int
+}
+
+// newRedactor crea un redactor para los tipos indicados (vacío = todos)
+func newRedactor(detector domain.PIIDetector, types []string) *redactor {
+	return &redactor{
+		detector:     detector,
+		types:        types,
+		placeholders: make(map[string]string),
+		counters:     make(map[string]int),
+	}
+}
+
+// redact retorna el texto anonimizado y las entidades sustituidas
+func (r *redactor) redact(text string) (string, []domain.RedactedEntity) {
+	entities := selectEntities(r.detector.Detect(text), r.types)
+	if len(entities) == 0 {
+		return text, nil
+	}
+
+	// Se recorre el texto de izquierda a derecha copiando lo que hay entre
+	// entidades; el mismo valor del mismo tipo reutiliza su marcador
+	var redacted strings.Builder
+	replaced := make([]domain.RedactedEntity, 0, len(entities))
+	position := 0
+	for _, entity := range entities {
+		key := entity.Type + "\x00" + entity.Text
+		placeholder, seen := r.placeholders[key]
+		if !seen {
+			r.counters[entity.Type]++
+			placeholder = fmt.Sprintf("[%s_%d]", strings.ToUpper(entity.Type), r.counters[entity.Type])
+			r.placeholders[key] = placeholder
+		}
+
+		redacted.WriteString(text[position:entity.Start])
+		redacted.WriteString(placeholder)
+		position = entity.End
+
+		replaced = append(replaced, domain.RedactedEntity{
+			Type:        entity.Type,
+			Text:        entity.Text,
+			Placeholder: placeholder,
+		})
+	}
+	redacted.WriteString(text[position:])
+
+	return redacted.String(), replaced
+}
+
+// selectEntities resuelve los solapamientos y filtra por tipo
+// Si dos entidades se solapan se queda con la que empieza antes (a igual
+// inicio, la más larga). El filtro va después: una tarjeta que también
+// parece un teléfono sigue siendo una tarjeta aunque solo se pidan teléfonos
+func selectEntities(entities []domain.PIIEntity, types []string) []domain.PIIEntity {
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Start != entities[j].Start {
+			return entities[i].Start < entities[j].Start
+		}
+		return entities[i].End > entities[j].End
+	})
+
+	wanted := make(map[string]bool, len(types))
+	for _, entityType := range types {
+		wanted[entityType] = true
+	}
+
+	selected := make([]domain.PIIEntity, 0, len(entities))
+	end := 0
+	for _, entity := range entities {
+		if entity.Start < end {
+			continue
+		}
+		end = entity.End
+		if len(wanted) == 0 || wanted[entity.Type] {
+			selected = append(selected, entity)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,135 @@
+// Package application - Caso de uso de muestreo de peticiones para reproducirlas
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// MUESTREO
+// ============================================================================
+//
+// ShouldSample decide al empezar la petición (así solo las elegidas pagan el
+// coste de copiar el body y la respuesta). Record anonimiza todos los textos
+// de la muestra con el mismo redactor que /api/v1/redact: los marcadores se
+// comparten, así que [EMAIL_1] es el mismo email en el mensaje, el historial
+// y la respuesta.
+//
+// No se guardan las peticiones con imágenes (el detector no ve datos
+// personales dentro de una imagen) ni los dry run (no llegan al modelo).
+// Con el corpus lleno, el muestreo se desactiva hasta reiniciar.
+// ============================================================================
+
+// ReplaySamplerImpl implementa domain.ReplaySampler
+type ReplaySamplerImpl struct {
+	corpus   domain.ReplayCorpus
+	detector domain.PIIDetector
+
+	// rate es la fracción de peticiones que se guardan (0 a 1)
+	rate float64
+
+	// types son los tipos de datos personales a anonimizar (vacío = todos)
+	types []string
+
+	// full se activa cuando el corpus rechaza una muestra por estar lleno
+	full atomic.Bool
+
+	// now da la hora de la muestra
+	now func() time.Time
+}
+
+// NewReplaySampler crea el muestreo: guarda en corpus una fracción rate de
+// las peticiones, anonimizando los tipos indicados (vacío = todos)
+func NewReplaySampler(corpus domain.ReplayCorpus, detector domain.PIIDetector, rate float64, types []string) domain.ReplaySampler {
+	if corpus == nil {
+		panic("replayCorpus no puede ser nil")
+	}
+	if detector == nil {
+		panic("piiDetector no puede ser nil")
+	}
+
+	return &ReplaySamplerImpl{
+		corpus:   corpus,
+		detector: detector,
+		rate:     min(max(rate, 0), 1),
+		types:    types,
+		now:      time.Now,
+	}
+}
+
+// ShouldSample decide al azar si se guarda la petición
+func (s *ReplaySamplerImpl) ShouldSample() bool {
+	return s.rate > 0 && !s.full.Load() && rand.Float64() < s.rate
+}
+
+// Record anonimiza la muestra y la guarda en el corpus
+func (s *ReplaySamplerImpl) Record(ctx context.Context, sample domain.ReplaySample) error {
+	var request map[string]any
+	if err := json.Unmarshal(sample.Request, &request); err != nil {
+		// El handler ya ha rechazado la petición: no hay nada que reproducir
+		return nil
+	}
+	if _, ok := request["images"]; ok {
+		return nil
+	}
+	if dryRun, _ := request["dry_run"].(bool); dryRun {
+		return nil
+	}
+
+	redactor := newRedactor(s.detector, s.types)
+	redacted, err := json.Marshal(redactJSON(redactor, request))
+	if err != nil {
+		return fmt.Errorf("error al serializar la muestra: %w", err)
+	}
+	sample.Request = redacted
+	sample.Baseline.Message, _ = redactor.redact(sample.Baseline.Message)
+
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	sample.ID = id
+	sample.RecordedAt = s.now().UTC()
+
+	err = s.corpus.SaveSample(ctx, &sample)
+	if errors.Is(err, domain.ErrReplayCorpusFull) {
+		if !s.full.Swap(true) {
+			log.Printf("⚠️  Corpus de reproducción lleno: se deja de muestrear")
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error al guardar la muestra: %w", err)
+	}
+	return nil
+}
+
+// redactJSON anonimiza todos los textos de un valor JSON decodificado
+// Las claves no se tocan: son los nombres de los campos
+func redactJSON(redactor *redactor, value any) any {
+	switch value := value.(type) {
+	case string:
+		text, _ := redactor.redact(value)
+		return text
+	case []any:
+		for i, item := range value {
+			value[i] = redactJSON(redactor, item)
+		}
+		return value
+	case map[string]any:
+		for key, item := range value {
+			value[key] = redactJSON(redactor, item)
+		}
+		return value
+	default:
+		return value
+	}
+}
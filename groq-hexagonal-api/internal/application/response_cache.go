@@ -0,0 +1,166 @@
+// Package application - Caché de respuestas del servicio de chat
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// RESPONSE CACHE
+// ============================================================================
+//
+// ResponseCachePolicy decide cuándo se usa la caché (ver
+// domain/response_cache.go) en SendMessage:
+//
+//   - La clave es un hash del tenant, el proveedor y la petición final (tras
+//     políticas y hooks): dos peticiones solo comparten respuesta si el
+//     modelo habría recibido exactamente lo mismo
+//   - Se guarda la respuesta del proveedor, antes de las políticas de salida:
+//     al servirla se vuelven a aplicar
+//   - El streaming no usa la caché
+//   - Tras un MISS se busca una pregunta parecida en la caché semántica, si
+//     está configurada (ver semantic_cache.go)
+//
+// Un fallo de la caché nunca hace fallar la petición: se registra en el log y
+// se llama al modelo como si no hubiera caché.
+// ============================================================================
+
+// ResponseCachePolicy configura la caché de respuestas
+// Una política nil o con TTL 0 desactiva la caché
+// También implementa domain.ResponseCacheService (panel de administración)
+type ResponseCachePolicy struct {
+	// Cache es dónde se guardan las respuestas
+	Cache domain.ResponseCache
+
+	// TTL es cuánto tiempo se guarda cada respuesta
+	TTL time.Duration
+
+	// Semantic es la caché semántica (nil = desactivada)
+	Semantic *SemanticCachePolicy
+
+	// Resultados de las búsquedas (ver Stats)
+	hits, misses, bypasses atomic.Int64
+}
+
+// enabled indica si la caché está activa
+func (p *ResponseCachePolicy) enabled() bool {
+	return p != nil && p.Cache != nil && p.TTL > 0
+}
+
+// cacheKey calcula la clave de la petición
+func (p *ResponseCachePolicy) cacheKey(ctx context.Context, request domain.ChatRequest) string {
+	// La petición de domain.ChatRequest siempre se puede serializar
+	data, _ := json.Marshal(request)
+
+	hash := sha256.New()
+	hash.Write([]byte(domain.TenantFromContext(ctx)))
+	hash.Write([]byte{0})
+	hash.Write([]byte(domain.ProviderFromContext(ctx)))
+	hash.Write([]byte{0})
+	hash.Write(data)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// lookup busca una respuesta válida para la petición
+// Retorna la respuesta (nil si no se puede usar) y el estado para X-Cache
+func (p *ResponseCachePolicy) lookup(ctx context.Context, key string) (_ *domain.CachedResponse, status domain.CacheStatus) {
+	defer func() { p.record(status) }()
+
+	control := domain.CacheControlFromContext(ctx)
+	if control.NoCache || control.NoStore || domain.DebugOverridesFromContext(ctx).DisableCache {
+		return nil, domain.CacheBypass
+	}
+
+	entry, err := p.Cache.Get(ctx, key)
+	if err != nil {
+		log.Printf("⚠️  Error al leer la caché de respuestas: %v", err)
+		return nil, domain.CacheMiss
+	}
+	if entry == nil {
+		return nil, domain.CacheMiss
+	}
+
+	// max-age: el cliente no acepta respuestas más antiguas
+	if control.MaxAge != nil && entry.Age(time.Now()) > *control.MaxAge {
+		return nil, domain.CacheMiss
+	}
+	return entry, domain.CacheHit
+}
+
+// store guarda la respuesta del proveedor (salvo con no-store)
+func (p *ResponseCachePolicy) store(ctx context.Context, key string, response domain.ChatResponse) {
+	if domain.CacheControlFromContext(ctx).NoStore {
+		return
+	}
+
+	entry := domain.CachedResponse{Response: response, StoredAt: time.Now()}
+	if err := p.Cache.Set(ctx, key, entry, p.TTL); err != nil {
+		log.Printf("⚠️  Error al guardar en la caché de respuestas: %v", err)
+	}
+}
+
+// record cuenta el resultado de una búsqueda
+func (p *ResponseCachePolicy) record(status domain.CacheStatus) {
+	switch status {
+	case domain.CacheHit:
+		p.hits.Add(1)
+	case domain.CacheMiss:
+		p.misses.Add(1)
+	case domain.CacheBypass:
+		p.bypasses.Add(1)
+	}
+}
+
+// Stats implementa domain.ResponseCacheService
+func (p *ResponseCachePolicy) Stats(ctx context.Context) (domain.ResponseCacheStats, error) {
+	if !p.enabled() {
+		return domain.ResponseCacheStats{}, nil
+	}
+
+	entries, err := p.Cache.Len(ctx)
+	if err != nil {
+		return domain.ResponseCacheStats{}, fmt.Errorf("error al contar las respuestas cacheadas: %w", err)
+	}
+	semantic, err := p.semanticStats(ctx)
+	if err != nil {
+		return domain.ResponseCacheStats{}, fmt.Errorf("error al contar las respuestas de la caché semántica: %w", err)
+	}
+	return domain.ResponseCacheStats{
+		Enabled:  true,
+		Entries:  entries,
+		Hits:     p.hits.Load(),
+		Misses:   p.misses.Load(),
+		Bypasses: p.bypasses.Load(),
+		Semantic: semantic,
+	}, nil
+}
+
+// Flush implementa domain.ResponseCacheService
+// Con la caché desactivada no hay nada que vaciar
+func (p *ResponseCachePolicy) Flush(ctx context.Context) (int, error) {
+	if !p.enabled() {
+		return 0, nil
+	}
+
+	removed, err := p.Cache.Flush(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error al vaciar la caché de respuestas: %w", err)
+	}
+	if p.semanticEnabled() {
+		semantic, err := p.Semantic.Cache.Flush(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("error al vaciar la caché semántica: %w", err)
+		}
+		removed += semantic
+	}
+	log.Printf("🗑️  Caché de respuestas vaciada: %d respuestas", removed)
+	return removed, nil
+}
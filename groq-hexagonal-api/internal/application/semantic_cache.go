@@ -0,0 +1,152 @@
+// Package application - Caché semántica del servicio de chat
+package application
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ SEMÁNTICA
+// ============================================================================
+//
+// Si la caché exacta no tiene la respuesta (MISS), SendMessage busca en la
+// semántica:
+//
+//   - El ámbito es la clave de la caché exacta calculada sin el texto de la
+//     última pregunta: solo se comparan peticiones con el mismo tenant,
+//     proveedor, modelo, system prompt, historial y parámetros
+//   - Se calcula el embedding de la última pregunta y se usa la respuesta
+//     guardada más parecida si la similitud llega al umbral
+//   - La respuesta nueva se guarda en las dos cachés, con el mismo TTL
+//
+// Solo se busca con una última pregunta de texto del usuario (sin imágenes).
+// Con no-cache o no-store no se busca (ni se guarda: no hay embedding). Un
+// fallo del embedding nunca hace fallar la petición: cuenta como error y se
+// llama al modelo.
+// ============================================================================
+
+// SemanticCachePolicy configura la caché semántica
+// Una política nil o con Threshold 0 la desactiva
+type SemanticCachePolicy struct {
+	// Embedder calcula los embeddings de las preguntas
+	Embedder domain.EmbeddingRepository
+
+	// Model es el modelo de embeddings
+	Model string
+
+	// Cache es dónde se guardan las respuestas con su embedding
+	Cache domain.SemanticCache
+
+	// Threshold es la similitud mínima (0 a 1) para usar una respuesta
+	Threshold float64
+
+	// Resultados de las búsquedas (ver ResponseCachePolicy.Stats)
+	hits, misses, errors atomic.Int64
+}
+
+// semanticQuery es una pregunta ya buscada: se guarda con su respuesta
+type semanticQuery struct {
+	scope  string
+	vector []float64
+}
+
+// semanticEnabled indica si la caché semántica está activa
+func (p *ResponseCachePolicy) semanticEnabled() bool {
+	return p.enabled() && p.Semantic != nil && p.Semantic.Embedder != nil &&
+		p.Semantic.Cache != nil && p.Semantic.Threshold > 0
+}
+
+// lookupSemantic busca una respuesta para una pregunta parecida
+// Retorna la pregunta (para guardarla con la respuesta; nil si la petición
+// no admite la caché semántica) y la respuesta (nil si no hay)
+func (p *ResponseCachePolicy) lookupSemantic(ctx context.Context, request domain.ChatRequest) (*semanticQuery, *domain.SemanticMatch) {
+	if !p.semanticEnabled() {
+		return nil, nil
+	}
+	scope, question, ok := p.semanticScope(ctx, request)
+	if !ok {
+		return nil, nil
+	}
+
+	semantic := p.Semantic
+	vectors, err := semantic.Embedder.CreateEmbeddings(ctx, semantic.Model, []string{question})
+	if err != nil {
+		semantic.errors.Add(1)
+		log.Printf("⚠️  Error al calcular el embedding de la caché semántica: %v", err)
+		return nil, nil
+	}
+	query := &semanticQuery{scope: scope, vector: vectors[0]}
+
+	match, err := semantic.Cache.Search(ctx, scope, query.vector, semantic.Threshold)
+	if err != nil {
+		log.Printf("⚠️  Error al buscar en la caché semántica: %v", err)
+		match = nil
+	}
+
+	// max-age: el cliente no acepta respuestas más antiguas
+	control := domain.CacheControlFromContext(ctx)
+	if match != nil && control.MaxAge != nil && match.Entry.Age(time.Now()) > *control.MaxAge {
+		match = nil
+	}
+
+	if match != nil {
+		semantic.hits.Add(1)
+	} else {
+		semantic.misses.Add(1)
+	}
+	return query, match
+}
+
+// storeSemantic guarda la respuesta con el embedding de la pregunta
+func (p *ResponseCachePolicy) storeSemantic(ctx context.Context, query *semanticQuery, response domain.ChatResponse) {
+	if query == nil || domain.CacheControlFromContext(ctx).NoStore {
+		return
+	}
+
+	entry := domain.CachedResponse{Response: response, StoredAt: time.Now()}
+	if err := p.Semantic.Cache.Add(ctx, query.scope, query.vector, entry, p.TTL); err != nil {
+		log.Printf("⚠️  Error al guardar en la caché semántica: %v", err)
+	}
+}
+
+// semanticScope separa la petición en el ámbito (todo menos el texto de la
+// última pregunta) y la pregunta
+// ok es false si la última pregunta no es un texto del usuario
+func (p *ResponseCachePolicy) semanticScope(ctx context.Context, request domain.ChatRequest) (scope, question string, ok bool) {
+	if len(request.Messages) == 0 {
+		return "", "", false
+	}
+	last := request.Messages[len(request.Messages)-1]
+	if last.Role != "user" || last.Content == "" || len(last.Images) > 0 {
+		return "", "", false
+	}
+
+	// La copia evita tocar los mensajes de la petición que se envía
+	request.Messages = slices.Clone(request.Messages)
+	request.Messages[len(request.Messages)-1].Content = ""
+	return p.cacheKey(ctx, request), last.Content, true
+}
+
+// semanticStats retorna el estado de la caché semántica (nil si está desactivada)
+func (p *ResponseCachePolicy) semanticStats(ctx context.Context) (*domain.SemanticCacheStats, error) {
+	if !p.semanticEnabled() {
+		return nil, nil
+	}
+
+	entries, err := p.Semantic.Cache.Len(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.SemanticCacheStats{
+		Threshold: p.Semantic.Threshold,
+		Entries:   entries,
+		Hits:      p.Semantic.hits.Load(),
+		Misses:    p.Semantic.misses.Load(),
+		Errors:    p.Semantic.errors.Load(),
+	}, nil
+}
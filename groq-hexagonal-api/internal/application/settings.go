@@ -0,0 +1,88 @@
+// Package application - Ajustes recargables en caliente
+package application
+
+import (
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"sync"
+)
+
+// ============================================================================
+// SETTINGS HOLDER
+// ============================================================================
+//
+// SettingsHolder guarda los ajustes vigentes (ver domain/settings.go) y los
+// sustituye de golpe en Reload. Igual que el catálogo de personas, si la
+// nueva configuración no es válida se mantienen los ajustes anteriores: un
+// .env roto no debe dejar el servidor sin modelo por defecto.
+// ============================================================================
+
+// SettingsLoader lee los ajustes de la configuración (ej: el fichero .env)
+type SettingsLoader func(ctx context.Context) (domain.RuntimeSettings, error)
+
+// SettingsHolder implementa domain.SettingsService
+// Se usa por puntero: lo comparten el servicio, los adaptadores y la recarga
+type SettingsHolder struct {
+	load SettingsLoader
+
+	// mu protege current: Reload lo reemplaza mientras se atienden peticiones
+	mu      sync.RWMutex
+	current domain.RuntimeSettings
+}
+
+// NewSettingsHolder crea el holder con los ajustes del arranque
+func NewSettingsHolder(initial domain.RuntimeSettings, load SettingsLoader) *SettingsHolder {
+	if load == nil {
+		panic("load no puede ser nil")
+	}
+
+	return &SettingsHolder{
+		load:    load,
+		current: initial,
+	}
+}
+
+// Settings implementa domain.SettingsSource
+func (h *SettingsHolder) Settings() domain.RuntimeSettings {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.current
+}
+
+// Reload implementa domain.SettingsService
+func (h *SettingsHolder) Reload(ctx context.Context) (domain.RuntimeSettings, error) {
+	settings, err := h.load(ctx)
+	if err != nil {
+		return h.Settings(), fmt.Errorf("error al recargar la configuración: %w", err)
+	}
+
+	h.mu.Lock()
+	previous := h.current
+	h.current = settings
+	h.mu.Unlock()
+
+	logSettingsChanges(previous, settings)
+	return settings, nil
+}
+
+// logSettingsChanges deja constancia de qué cambió en la recarga
+func logSettingsChanges(previous, current domain.RuntimeSettings) {
+	changes := 0
+	logChange := func(name string, from, to interface{}) {
+		if from != to {
+			log.Printf("🔄 %s: %v → %v", name, from, to)
+			changes++
+		}
+	}
+
+	logChange("Modelo por defecto", previous.DefaultModel, current.DefaultModel)
+	logChange("HTTP Timeout", previous.HTTPTimeout, current.HTTPTimeout)
+	logChange("Rate limit (peticiones)", previous.RateLimitRequests, current.RateLimitRequests)
+	logChange("Rate limit (ventana)", previous.RateLimitWindow, current.RateLimitWindow)
+	logChange("Peticiones simultáneas", previous.MaxConcurrentRequests, current.MaxConcurrentRequests)
+	logChange("Franjas horarias", len(previous.ThrottleSchedule.Windows), len(current.ThrottleSchedule.Windows))
+
+	log.Printf("🔄 Configuración recargada: %d cambios", changes)
+}
@@ -0,0 +1,353 @@
+// Package application - Enrutado fijo (sticky) entre varios repositorios
+package application
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"hash/fnv"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// STICKY ROUTER
+// ============================================================================
+//
+// StickyRouter implementa domain.LLMRepository repartiendo las peticiones
+// entre varios backends (una API key cada uno, o proveedores distintos):
+//
+//   - Con clave de enrutado (domain.WithRoutingKey, ej: el id de la
+//     conversación), la petición va siempre al mismo backend
+//   - Sin clave, se reparten por turnos (round-robin)
+//
+// El backend se elige con rendezvous hashing: para cada backend se calcula
+// hash(clave + nombre) y gana el mayor. Al añadir o quitar un backend solo
+// cambian de destino las claves que iban a él, no todas (como pasaría con
+// hash % n).
+//
+// Un backend que responde con rate limit (429) o con un error de
+// autenticación (una key revocada) queda en enfriamiento: no recibe
+// peticiones hasta que pasa el Retry-After del proveedor (o
+// DefaultBackendCooldown) o DefaultBackendAuthCooldown. La petición
+// rechazada se repite en el siguiente backend disponible, así que con N keys
+// el cliente solo ve el 429 cuando todas están agotadas. Las peticiones con
+// clave de enrutado pasan al siguiente backend de su orden de rendezvous: al
+// volver la key, la conversación vuelve a ella.
+// ============================================================================
+
+// Enfriamiento de los backends rechazados
+const (
+	// DefaultBackendCooldown es el enfriamiento tras un 429 sin Retry-After
+	DefaultBackendCooldown = 30 * time.Second
+
+	// DefaultBackendAuthCooldown es el enfriamiento tras un error de
+	// autenticación: una key revocada no se recupera sola, pero se vuelve a
+	// probar por si fue un fallo puntual del proveedor
+	DefaultBackendAuthCooldown = 5 * time.Minute
+)
+
+// StickyBackend es uno de los destinos del router
+type StickyBackend struct {
+	// Name identifica al backend en el hash: debe ser estable entre reinicios
+	// (no la API key: el hash no debe depender de un secreto que se rota)
+	Name string
+
+	Repository domain.LLMRepository
+}
+
+// StickyRouter reparte las peticiones entre varios backends
+// Implementa domain.LLMRepository y domain.BackendHealthService
+type StickyRouter struct {
+	backends []StickyBackend
+	states   []*backendState
+
+	// cooldown es el enfriamiento tras un 429 sin Retry-After
+	cooldown time.Duration
+
+	// next es el contador del round-robin (atomic: lo usan varias goroutines)
+	next atomic.Uint64
+
+	// now da la hora actual
+	now func() time.Time
+}
+
+// backendState son los contadores y el enfriamiento de un backend
+type backendState struct {
+	requests    atomic.Int64
+	failures    atomic.Int64
+	rateLimited atomic.Int64
+
+	mu           sync.Mutex
+	coolingUntil time.Time
+	lastError    string
+}
+
+// StickyOption configura aspectos opcionales del router
+type StickyOption func(*StickyRouter)
+
+// WithBackendCooldown cambia el enfriamiento tras un 429 sin Retry-After
+// (0 = DefaultBackendCooldown)
+func WithBackendCooldown(cooldown time.Duration) StickyOption {
+	return func(r *StickyRouter) {
+		if cooldown > 0 {
+			r.cooldown = cooldown
+		}
+	}
+}
+
+// NewStickyRouter crea el router; con un solo backend lo retorna tal cual
+func NewStickyRouter(backends []StickyBackend, opts ...StickyOption) domain.LLMRepository {
+	if len(backends) == 0 {
+		panic("se necesita al menos un backend")
+	}
+	if len(backends) == 1 {
+		return backends[0].Repository
+	}
+
+	router := &StickyRouter{
+		backends: backends,
+		states:   make([]*backendState, len(backends)),
+		cooldown: DefaultBackendCooldown,
+		now:      time.Now,
+	}
+	for i := range router.states {
+		router.states[i] = &backendState{}
+	}
+	for _, opt := range opts {
+		opt(router)
+	}
+	return router
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (r *StickyRouter) CreateChatCompletion(ctx context.Context, request domain.ChatRequest) (*domain.ChatResponse, error) {
+	var response *domain.ChatResponse
+	err := r.try(ctx, func(repo domain.LLMRepository) (err error) {
+		response, err = repo.CreateChatCompletion(ctx, request)
+		return err
+	})
+	return response, err
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+// El 429 llega antes del primer fragmento, así que el flujo también se
+// repite en otro backend
+func (r *StickyRouter) CreateChatCompletionStream(ctx context.Context, request domain.ChatRequest) (domain.ChatStream, error) {
+	var stream domain.ChatStream
+	err := r.try(ctx, func(repo domain.LLMRepository) (err error) {
+		stream, err = repo.CreateChatCompletionStream(ctx, request)
+		return err
+	})
+	return stream, err
+}
+
+// ListModels implementa domain.LLMRepository
+func (r *StickyRouter) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	var models *domain.ModelsResponse
+	err := r.try(ctx, func(repo domain.LLMRepository) (err error) {
+		models, err = repo.ListModels(ctx)
+		return err
+	})
+	return models, err
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+// En modo proxy el 429 es una respuesta, no un error: se repite en otro
+// backend y, si no queda ninguno, el último rechazo llega al cliente tal cual
+func (r *StickyRouter) ProxyChatCompletion(ctx context.Context, body []byte, stream bool) (*domain.ProxyResponse, error) {
+	var response *domain.ProxyResponse
+	err := r.try(ctx, func(repo domain.LLMRepository) error {
+		// El rechazo del intento anterior no llega al cliente
+		if response != nil {
+			response.Body.Close()
+			response = nil
+		}
+
+		var err error
+		response, err = repo.ProxyChatCompletion(ctx, body, stream)
+		if err != nil {
+			return err
+		}
+		return proxyRejection(response)
+	})
+	if response != nil {
+		return response, nil
+	}
+	return nil, err
+}
+
+// GetBackendsHealth implementa domain.BackendHealthService
+func (r *StickyRouter) GetBackendsHealth() []domain.BackendHealth {
+	now := r.now()
+	health := make([]domain.BackendHealth, len(r.backends))
+	for i, backend := range r.backends {
+		state := r.states[i]
+		state.mu.Lock()
+		health[i] = domain.BackendHealth{
+			Name:        backend.Name,
+			Requests:    state.requests.Load(),
+			Failures:    state.failures.Load(),
+			RateLimited: state.rateLimited.Load(),
+			LastError:   state.lastError,
+		}
+		if state.coolingUntil.After(now) {
+			health[i].CoolingUntil = state.coolingUntil
+		}
+		state.mu.Unlock()
+	}
+	return health
+}
+
+// try llama a los backends en orden hasta que uno no rechaza la petición
+// Retorna el error del último intento
+func (r *StickyRouter) try(ctx context.Context, call func(domain.LLMRepository) error) error {
+	var err error
+	for _, i := range r.candidates(ctx) {
+		err = call(r.backends[i].Repository)
+		r.record(i, err)
+		if !rejectedByBackend(err) || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// candidates retorna los backends disponibles en orden de preferencia
+// Si todos están en enfriamiento, solo el que sale antes
+func (r *StickyRouter) candidates(ctx context.Context) []int {
+	order := r.order(ctx)
+
+	now := r.now()
+	available := order[:0:0]
+	soonest, soonestUntil := order[0], time.Time{}
+	for _, i := range order {
+		until := r.states[i].cooling()
+		if !until.After(now) {
+			available = append(available, i)
+			continue
+		}
+		if soonestUntil.IsZero() || until.Before(soonestUntil) {
+			soonest, soonestUntil = i, until
+		}
+	}
+	if len(available) == 0 {
+		return []int{soonest}
+	}
+	return available
+}
+
+// order retorna todos los backends en orden de preferencia para la petición
+func (r *StickyRouter) order(ctx context.Context) []int {
+	n := len(r.backends)
+	order := make([]int, n)
+
+	key := domain.RoutingKeyFromContext(ctx)
+	if key == "" {
+		// Add retorna el valor nuevo: restamos 1 para empezar por el primero
+		first := int((r.next.Add(1) - 1) % uint64(n))
+		for i := range order {
+			order[i] = (first + i) % n
+		}
+		return order
+	}
+
+	scores := make([]uint64, n)
+	for i, backend := range r.backends {
+		order[i] = i
+		scores[i] = rendezvousScore(key, backend.Name)
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+	return order
+}
+
+// record actualiza los contadores del backend y, si rechazó la petición,
+// lo pone en enfriamiento
+func (r *StickyRouter) record(i int, err error) {
+	state := r.states[i]
+	state.requests.Add(1)
+	// La cancelación del cliente no es un fallo del backend
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	state.failures.Add(1)
+
+	var cooldown time.Duration
+	switch {
+	case errors.Is(err, domain.ErrRateLimited):
+		state.rateLimited.Add(1)
+		cooldown = r.cooldown
+		var upstream *domain.UpstreamError
+		if errors.As(err, &upstream) && upstream.RetryAfter > 0 {
+			cooldown = upstream.RetryAfter
+		}
+	case errors.Is(err, domain.ErrUpstreamAuth):
+		cooldown = DefaultBackendAuthCooldown
+	}
+
+	state.mu.Lock()
+	state.lastError = err.Error()
+	if cooldown > 0 {
+		state.coolingUntil = r.now().Add(cooldown)
+	}
+	state.mu.Unlock()
+
+	if cooldown > 0 {
+		log.Printf("⚠️  %s en enfriamiento durante %v: %v", r.backends[i].Name, cooldown, err)
+	}
+}
+
+// cooling retorna hasta cuándo está en enfriamiento el backend
+func (s *backendState) cooling() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.coolingUntil
+}
+
+// rejectedByBackend indica si el error es propio del backend (su key) y la
+// petición puede ir a otro: rate limit o autenticación
+func rejectedByBackend(err error) bool {
+	return errors.Is(err, domain.ErrRateLimited) || errors.Is(err, domain.ErrUpstreamAuth)
+}
+
+// proxyRejection traduce a error un rechazo del backend en modo proxy
+// (429, 401 o 403; nil con cualquier otro status)
+func proxyRejection(response *domain.ProxyResponse) error {
+	var kind error
+	switch response.StatusCode {
+	case 429:
+		kind = domain.ErrRateLimited
+	case 401, 403:
+		kind = domain.ErrUpstreamAuth
+	default:
+		return nil
+	}
+
+	rejection := &domain.UpstreamError{
+		Kind:       kind,
+		StatusCode: response.StatusCode,
+		Message:    "respuesta " + strconv.Itoa(response.StatusCode) + " en modo proxy",
+	}
+	if values := response.Header["Retry-After"]; len(values) > 0 {
+		if seconds, err := strconv.Atoi(values[0]); err == nil && seconds > 0 {
+			rejection.RetryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	return rejection
+}
+
+// rendezvousScore es el peso de un backend para una clave
+func rendezvousScore(key, backend string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	// El separador evita que ("ab", "c") y ("a", "bc") den el mismo hash
+	h.Write([]byte{0})
+	h.Write([]byte(backend))
+	return h.Sum64()
+}
@@ -0,0 +1,59 @@
+// Package application - Política de secuencias de parada (stop sequences)
+package application
+
+import "groq-hexagonal-api/internal/domain"
+
+// ============================================================================
+// POLÍTICA DE STOP SEQUENCES
+// ============================================================================
+//
+// Los operadores pueden definir secuencias de parada que se añaden a TODAS
+// las peticiones (globales) o solo a las de un modelo concreto (por modelo).
+// Por ejemplo, para cortar la generación en un delimitador propio: "###".
+//
+// Orden de mezcla:
+//   1. Secuencias globales
+//   2. Secuencias del modelo
+//   3. Secuencias enviadas por el cliente
+//
+// Se eliminan duplicados y el resultado se recorta a domain.MaxStopSequences,
+// así que las secuencias del operador tienen prioridad sobre las del cliente.
+// ============================================================================
+
+// StopPolicy contiene las secuencias de parada configuradas por el operador
+type StopPolicy struct {
+	// Global se aplica a todas las peticiones
+	Global []string
+
+	// PerModel se aplica solo a las peticiones del modelo indicado (clave)
+	PerModel map[string][]string
+}
+
+// Merge combina las secuencias del operador con las del cliente
+// Retorna nil si no hay ninguna secuencia (así el campo se omite en el JSON),
+// y cuántas secuencias se descartaron por superar el máximo
+func (p StopPolicy) Merge(model string, client []string) (merged []string, dropped int) {
+	// Un map[string]bool es la forma idiomática de un "set" en Go
+	seen := make(map[string]bool)
+
+	// add añade las secuencias no vacías y no repetidas hasta llegar al máximo
+	add := func(sequences []string) {
+		for _, seq := range sequences {
+			if seq == "" || seen[seq] {
+				continue
+			}
+			if len(merged) >= domain.MaxStopSequences {
+				dropped++
+				continue
+			}
+			seen[seq] = true
+			merged = append(merged, seq)
+		}
+	}
+
+	add(p.Global)
+	add(p.PerModel[model]) // Leer una clave inexistente de un map retorna nil
+	add(client)
+
+	return merged, dropped
+}
@@ -0,0 +1,80 @@
+// Package application - Modelo por defecto y rangos de parámetros por tenant
+package application
+
+import (
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// LÍMITES POR TENANT
+// ============================================================================
+//
+// TenantModelPolicy aplica los límites de domain.TenantModelLimits en
+// buildRequest, después de resolver los parámetros (los del cliente, la
+// persona...) y antes de comprobar la ventana de contexto: así el presupuesto
+// de la ventana cuenta con el max_tokens que de verdad se va a pedir.
+// ============================================================================
+
+// TenantModelPolicy contiene los límites de cada tenant
+type TenantModelPolicy struct {
+	// Limits mapea tenant → sus límites (los tenants sin entrada no tienen)
+	Limits map[string]domain.TenantModelLimits
+}
+
+// DefaultModel retorna el modelo por defecto del tenant ("" si no tiene)
+func (p TenantModelPolicy) DefaultModel(tenantID string) string {
+	return p.Limits[tenantID].DefaultModel
+}
+
+// Apply ajusta la temperatura y max_tokens de la petición a los límites
+// del tenant, o la rechaza si el tenant lo prefiere así
+// Retorna los ajustes hechos, legibles (nil = ninguno)
+func (p TenantModelPolicy) Apply(tenantID string, request *domain.ChatRequest) ([]string, error) {
+	limits, ok := p.Limits[tenantID]
+	if !ok {
+		return nil, nil
+	}
+	reject := limits.OnViolation == domain.ParameterReject
+
+	var clamped []string
+	if request.Temperature != nil {
+		temperature := *request.Temperature
+		if bounded := limits.ClampTemperature(temperature); bounded != temperature {
+			if reject {
+				return nil, fmt.Errorf("%w: temperature %g (el tenant admite %s)",
+					domain.ErrParameterOutOfRange, temperature, temperatureRange(limits))
+			}
+			request.SetTemperature(bounded)
+			clamped = append(clamped, fmt.Sprintf("temperature %g → %g", temperature, bounded))
+		}
+	}
+
+	if limits.MaxTokens > 0 {
+		switch {
+		case request.MaxTokens == 0:
+			// Sin límite explícito: no es una infracción, se aplica el del tenant
+			request.SetMaxTokens(limits.MaxTokens)
+		case request.MaxTokens > limits.MaxTokens:
+			if reject {
+				return nil, fmt.Errorf("%w: max_tokens %d (el tenant admite como máximo %d)",
+					domain.ErrParameterOutOfRange, request.MaxTokens, limits.MaxTokens)
+			}
+			clamped = append(clamped, fmt.Sprintf("max_tokens %d → %d", request.MaxTokens, limits.MaxTokens))
+			request.SetMaxTokens(limits.MaxTokens)
+		}
+	}
+	return clamped, nil
+}
+
+// temperatureRange describe el rango de temperatura del tenant
+func temperatureRange(limits domain.TenantModelLimits) string {
+	low, high := 0.0, 2.0
+	if limits.MinTemperature != nil {
+		low = *limits.MinTemperature
+	}
+	if limits.MaxTemperature != nil {
+		high = *limits.MaxTemperature
+	}
+	return fmt.Sprintf("entre %g y %g", low, high)
+}
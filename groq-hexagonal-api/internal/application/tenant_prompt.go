@@ -0,0 +1,24 @@
+// Package application - Prompt de sistema obligatorio por tenant
+package application
+
+// ============================================================================
+// PROMPT DE SISTEMA POR TENANT (BRANDING)
+// ============================================================================
+//
+// Cada tenant puede tener instrucciones que se envían SIEMPRE al modelo, antes
+// que cualquier otro mensaje: tono de la marca, avisos legales, temas
+// prohibidos... El cliente final no las ve (no se guardan en el historial de
+// las conversaciones ni se devuelven) y no puede sustituirlas: su propio
+// system_prompt se añade después, nunca en lugar de este.
+// ============================================================================
+
+// TenantPromptPolicy contiene el prefijo de sistema de cada tenant
+type TenantPromptPolicy struct {
+	// Prefixes mapea tenant → instrucciones de sistema obligatorias
+	Prefixes map[string]string
+}
+
+// PrefixFor retorna el prefijo del tenant ("" si no tiene)
+func (p TenantPromptPolicy) PrefixFor(tenantID string) string {
+	return p.Prefixes[tenantID]
+}
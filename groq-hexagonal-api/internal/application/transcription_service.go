@@ -0,0 +1,68 @@
+// Package application - Caso de uso de transcripción de audio
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// ERRORES
+// ============================================================================
+
+var (
+	ErrEmptyAudio             = errors.New("el fichero de audio no puede estar vacío")
+	ErrUnsupportedAudioFormat = fmt.Errorf("formato de audio no soportado (formatos válidos: %v)", domain.SupportedAudioFormats)
+)
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// TranscriptionServiceImpl implementa domain.TranscriptionService
+type TranscriptionServiceImpl struct {
+	repo domain.TranscriptionRepository
+
+	// defaultModel se usa cuando la petición no indica modelo
+	defaultModel string
+}
+
+// NewTranscriptionService crea el servicio de transcripción
+func NewTranscriptionService(repo domain.TranscriptionRepository, defaultModel string) domain.TranscriptionService {
+	if repo == nil {
+		panic("transcriptionRepo no puede ser nil")
+	}
+	if defaultModel == "" {
+		defaultModel = domain.DefaultTranscriptionModel
+	}
+
+	return &TranscriptionServiceImpl{
+		repo:         repo,
+		defaultModel: defaultModel,
+	}
+}
+
+// Transcribe valida la petición y la envía al proveedor
+func (s *TranscriptionServiceImpl) Transcribe(
+	ctx context.Context,
+	request domain.TranscriptionRequest,
+) (*domain.Transcription, error) {
+	if request.Audio == nil {
+		return nil, ErrEmptyAudio
+	}
+	if !domain.IsSupportedAudioFile(request.Filename) {
+		return nil, ErrUnsupportedAudioFormat
+	}
+	if request.Model == "" {
+		request.Model = s.defaultModel
+	}
+
+	transcription, err := s.repo.CreateTranscription(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("error al transcribir el audio: %w", err)
+	}
+	transcription.Model = request.Model
+	return transcription, nil
+}
@@ -0,0 +1,295 @@
+// Package application - Anonimización antes de llamar al proveedor
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"time"
+)
+
+// ============================================================================
+// ANONIMIZACIÓN DE LOS MENSAJES ENVIADOS AL PROVEEDOR
+// ============================================================================
+//
+// En entornos regulados los datos personales no pueden salir hacia el
+// proveedor del modelo. RedactingRepository envuelve el domain.LLMRepository
+// y, antes de cada llamada, sustituye los datos personales de los mensajes
+// del usuario por marcadores ([EMAIL_1], [PHONE_1]...), con el mismo
+// detector que POST /api/v1/redact.
+//
+// Al envolver el repositorio, y no el servicio de chat, cubre todo lo que
+// llega al proveedor: chat, conversaciones, jobs, verificación, código,
+// clasificación... y el body del modo proxy (messages[].content, en texto o
+// en partes "text").
+//
+// Los marcadores se comparten en toda la petición: en una conversación,
+// [EMAIL_1] es el mismo email en todos los mensajes. La respuesta del modelo
+// usa los marcadores: no se restauran los valores originales.
+//
+// Cada petición anonimizada se registra en el domain.RedactionAuditor con
+// los tipos y marcadores, nunca con los valores.
+//
+// Los embeddings (la caché semántica) no pasan por el domain.LLMRepository:
+// RedactingEmbedder hace lo mismo con los textos de domain.EmbeddingRepository.
+// ============================================================================
+
+// Orígenes de las peticiones en el registro de auditoría
+const (
+	redactionSourceChat       = "chat"
+	redactionSourceProxy      = "proxy"
+	redactionSourceEmbeddings = "embeddings"
+)
+
+// RedactingRepository anonimiza los mensajes del usuario antes de llamar al
+// repositorio envuelto
+// Implementa domain.LLMRepository
+type RedactingRepository struct {
+	next     domain.LLMRepository
+	detector domain.PIIDetector
+	auditor  domain.RedactionAuditor
+
+	// types son los tipos que se anonimizan (vacío = todos)
+	types []string
+}
+
+// NewRedactingRepository crea el repositorio que anonimiza
+func NewRedactingRepository(
+	next domain.LLMRepository,
+	detector domain.PIIDetector,
+	auditor domain.RedactionAuditor,
+	types []string,
+) *RedactingRepository {
+	if next == nil {
+		panic("next no puede ser nil")
+	}
+	if detector == nil {
+		panic("detector no puede ser nil")
+	}
+	if auditor == nil {
+		panic("auditor no puede ser nil")
+	}
+
+	return &RedactingRepository{
+		next:     next,
+		detector: detector,
+		auditor:  auditor,
+		types:    types,
+	}
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (r *RedactingRepository) CreateChatCompletion(
+	ctx context.Context,
+	request domain.ChatRequest,
+) (*domain.ChatResponse, error) {
+	return r.next.CreateChatCompletion(ctx, r.redactRequest(ctx, request))
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+func (r *RedactingRepository) CreateChatCompletionStream(
+	ctx context.Context,
+	request domain.ChatRequest,
+) (domain.ChatStream, error) {
+	return r.next.CreateChatCompletionStream(ctx, r.redactRequest(ctx, request))
+}
+
+// ListModels implementa domain.LLMRepository
+func (r *RedactingRepository) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	return r.next.ListModels(ctx)
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+// Un body que no es JSON se rechaza: no se puede comprobar qué lleva
+func (r *RedactingRepository) ProxyChatCompletion(
+	ctx context.Context,
+	body []byte,
+	stream bool,
+) (*domain.ProxyResponse, error) {
+	redacted, err := r.redactProxyBody(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	return r.next.ProxyChatCompletion(ctx, redacted, stream)
+}
+
+// RedactingEmbedder anonimiza los textos antes de calcular sus embeddings
+// Implementa domain.EmbeddingRepository
+type RedactingEmbedder struct {
+	next domain.EmbeddingRepository
+
+	// redacting aporta el detector, el registro de auditoría y los tipos
+	redacting *RedactingRepository
+}
+
+// NewRedactingEmbedder crea el cliente de embeddings que anonimiza, con la
+// misma configuración que el repositorio redacting
+func NewRedactingEmbedder(next domain.EmbeddingRepository, redacting *RedactingRepository) *RedactingEmbedder {
+	if next == nil {
+		panic("next no puede ser nil")
+	}
+	if redacting == nil {
+		panic("redacting no puede ser nil")
+	}
+
+	return &RedactingEmbedder{next: next, redacting: redacting}
+}
+
+// CreateEmbeddings implementa domain.EmbeddingRepository
+// Los textos se copian: el llamador conserva los suyos
+func (e *RedactingEmbedder) CreateEmbeddings(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	redactor := newRedactor(e.redacting.detector, e.redacting.types)
+	var entries []domain.RedactionAuditEntry
+	redacted := make([]string, len(texts))
+	for i, text := range texts {
+		var entities []domain.RedactedEntity
+		redacted[i], entities = redactor.redact(text)
+		entries = appendAuditEntries(entries, i, entities)
+	}
+
+	if len(entries) > 0 {
+		e.redacting.record(ctx, redactionSourceEmbeddings, model, entries)
+	}
+	return e.next.CreateEmbeddings(ctx, model, redacted)
+}
+
+// redactRequest retorna la petición con los mensajes del usuario anonimizados
+// Los mensajes se copian: el llamador puede reutilizar los suyos (ej: en los
+// reintentos con otro modelo)
+func (r *RedactingRepository) redactRequest(ctx context.Context, request domain.ChatRequest) domain.ChatRequest {
+	redactor := newRedactor(r.detector, r.types)
+	var entries []domain.RedactionAuditEntry
+	var messages []domain.ChatMessage
+
+	for i, message := range request.Messages {
+		if message.Role != "user" || message.Content == "" {
+			continue
+		}
+		text, entities := redactor.redact(message.Content)
+		if len(entities) == 0 {
+			continue
+		}
+		if messages == nil {
+			messages = append([]domain.ChatMessage(nil), request.Messages...)
+		}
+		messages[i].Content = text
+		entries = appendAuditEntries(entries, i, entities)
+	}
+
+	if messages != nil {
+		request.Messages = messages
+		r.record(ctx, redactionSourceChat, request.Model, entries)
+	}
+	return request
+}
+
+// redactProxyBody anonimiza los mensajes del usuario de un body de
+// /chat/completions; el resto del body se reenvía sin cambios
+func (r *RedactingRepository) redactProxyBody(ctx context.Context, body []byte) ([]byte, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: el body no es un JSON válido", domain.ErrInvalidRequest)
+	}
+	rawMessages, ok := payload["messages"]
+	if !ok {
+		return body, nil
+	}
+	var messages []map[string]json.RawMessage
+	if err := json.Unmarshal(rawMessages, &messages); err != nil {
+		return nil, fmt.Errorf("%w: messages debe ser un array de objetos", domain.ErrInvalidRequest)
+	}
+
+	redactor := newRedactor(r.detector, r.types)
+	var entries []domain.RedactionAuditEntry
+	for i, message := range messages {
+		var role string
+		if json.Unmarshal(message["role"], &role) != nil || role != "user" {
+			continue
+		}
+		content, entities := redactProxyContent(redactor, message["content"])
+		if len(entities) > 0 {
+			message["content"] = content
+			entries = appendAuditEntries(entries, i, entities)
+		}
+	}
+	if len(entries) == 0 {
+		return body, nil
+	}
+
+	var err error
+	if payload["messages"], err = json.Marshal(messages); err != nil {
+		return nil, fmt.Errorf("error al serializar los mensajes: %w", err)
+	}
+	redacted, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar el body: %w", err)
+	}
+
+	var model string
+	json.Unmarshal(payload["model"], &model)
+	r.record(ctx, redactionSourceProxy, model, entries)
+	return redacted, nil
+}
+
+// redactProxyContent anonimiza el content de un mensaje del proxy: un texto
+// o un array de partes (solo se tocan las de tipo "text")
+func redactProxyContent(redactor *redactor, raw json.RawMessage) (json.RawMessage, []domain.RedactedEntity) {
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		redacted, entities := redactor.redact(text)
+		if len(entities) == 0 {
+			return raw, nil
+		}
+		encoded, _ := json.Marshal(redacted)
+		return encoded, entities
+	}
+
+	var parts []map[string]json.RawMessage
+	if json.Unmarshal(raw, &parts) != nil {
+		return raw, nil
+	}
+	var all []domain.RedactedEntity
+	for _, part := range parts {
+		var partType, partText string
+		if json.Unmarshal(part["type"], &partType) != nil || partType != "text" ||
+			json.Unmarshal(part["text"], &partText) != nil {
+			continue
+		}
+		redacted, entities := redactor.redact(partText)
+		if len(entities) == 0 {
+			continue
+		}
+		part["text"], _ = json.Marshal(redacted)
+		all = append(all, entities...)
+	}
+	if len(all) == 0 {
+		return raw, nil
+	}
+	encoded, _ := json.Marshal(parts)
+	return encoded, all
+}
+
+// appendAuditEntries añade al registro las entidades de un mensaje
+// Solo el tipo y el marcador: nunca el valor original
+func appendAuditEntries(entries []domain.RedactionAuditEntry, message int, entities []domain.RedactedEntity) []domain.RedactionAuditEntry {
+	for _, entity := range entities {
+		entries = append(entries, domain.RedactionAuditEntry{
+			Type:        entity.Type,
+			Placeholder: entity.Placeholder,
+			Message:     message,
+		})
+	}
+	return entries
+}
+
+// record envía la petición anonimizada al registro de auditoría
+func (r *RedactingRepository) record(ctx context.Context, source, model string, entries []domain.RedactionAuditEntry) {
+	r.auditor.RecordRedaction(ctx, domain.RedactionAudit{
+		Tenant:   domain.TenantFromContext(ctx),
+		Source:   source,
+		Model:    model,
+		Entities: entries,
+		At:       time.Now(),
+	})
+}
@@ -0,0 +1,237 @@
+// Package application - Caso de uso de consumo de tokens y cuotas diarias
+package application
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"slices"
+	"time"
+)
+
+// ============================================================================
+// CUOTAS
+// ============================================================================
+
+// UsageQuotas son las cuotas diarias de tokens
+type UsageQuotas struct {
+	// Default es la cuota de los clientes sin cuota propia (0 = sin cuota)
+	Default int64
+
+	// PerClient sobrescribe la cuota de clientes concretos (ej:
+	// {"key:3f9a1c0b7e2d": 2000000}); 0 quita la cuota a ese cliente
+	PerClient map[string]int64
+}
+
+// quotaFor retorna la cuota diaria del cliente
+func (q UsageQuotas) quotaFor(client string) int64 {
+	if quota, ok := q.PerClient[client]; ok {
+		return quota
+	}
+	return q.Default
+}
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// UsageServiceImpl implementa domain.UsageService
+type UsageServiceImpl struct {
+	repo   domain.UsageRepository
+	quotas UsageQuotas
+
+	// pricing son los precios por modelo para estimar el coste (nil = sin coste)
+	pricing map[string]domain.ModelPrice
+
+	// events recibe un evento por cada consumo registrado (nil = sin eventos)
+	events domain.UsageEventPublisher
+
+	// now da la hora actual (el día del consumo es el de UTC)
+	now func() time.Time
+}
+
+// UsageOption configura opciones del servicio de consumo
+type UsageOption func(*UsageServiceImpl)
+
+// WithUsageEvents emite cada consumo registrado a publisher (ver UsageEvent)
+func WithUsageEvents(publisher domain.UsageEventPublisher) UsageOption {
+	return func(s *UsageServiceImpl) {
+		s.events = publisher
+	}
+}
+
+// NewUsageService crea el servicio de consumo
+func NewUsageService(repo domain.UsageRepository, quotas UsageQuotas, pricing map[string]domain.ModelPrice, opts ...UsageOption) domain.UsageService {
+	if repo == nil {
+		panic("usageRepo no puede ser nil")
+	}
+
+	service := &UsageServiceImpl{
+		repo:    repo,
+		quotas:  quotas,
+		pricing: pricing,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service
+}
+
+// Record suma el consumo de una petición al día de hoy del cliente y al mes
+// de su tenant (el del contexto, con la conversación de ConversationFromContext)
+// Las respuestas sin tokens no se apuntan. El coste se recalcula con los
+// precios: así cuentan también los endpoints que no lo muestran.
+// Con WithUsageEvents, el consumo se emite también como evento
+func (s *UsageServiceImpl) Record(ctx context.Context, client string, model string, usage domain.Usage) error {
+	if usage.TotalTokens <= 0 {
+		return nil
+	}
+	usage.CostUSD, _ = domain.UsageCost(s.pricing, model, usage)
+
+	now := s.now().UTC()
+	if err := s.repo.AddUsage(ctx, client, now.Format(domain.UsageDayLayout), usage); err != nil {
+		return fmt.Errorf("error al registrar el consumo: %w", err)
+	}
+
+	record := domain.TenantUsageRecord{
+		Model:          model,
+		ConversationID: domain.ConversationFromContext(ctx),
+		Usage:          usage,
+	}
+	tenant := domain.TenantFromContext(ctx)
+	if err := s.repo.AddTenantUsage(ctx, tenant, now.Format(domain.StatementMonthLayout), record); err != nil {
+		return fmt.Errorf("error al registrar el consumo del tenant: %w", err)
+	}
+
+	if s.events != nil {
+		s.events.PublishUsage(ctx, s.usageEvent(ctx, client, model, usage, now))
+	}
+	return nil
+}
+
+// usageEvent construye el evento de un consumo con los datos del contexto
+func (s *UsageServiceImpl) usageEvent(ctx context.Context, client, model string, usage domain.Usage, now time.Time) domain.UsageEvent {
+	request := domain.RequestInfoFromContext(ctx)
+	event := domain.UsageEvent{
+		RequestID:        request.ID,
+		Client:           client,
+		TenantID:         domain.TenantFromContext(ctx),
+		ConversationID:   domain.ConversationFromContext(ctx),
+		Model:            model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CostUSD:          usage.CostUSD,
+		Time:             now,
+	}
+	if key := domain.APIKeyFromContext(ctx); key != nil {
+		event.APIKeyID = key.ID
+	}
+	if !request.StartedAt.IsZero() {
+		event.LatencyMS = now.Sub(request.StartedAt).Milliseconds()
+	}
+	return event
+}
+
+// Report retorna el consumo de hoy frente a la cuota y el de los días anteriores
+func (s *UsageServiceImpl) Report(ctx context.Context, client string, historyDays int) (*domain.UsageReport, error) {
+	if historyDays < 0 {
+		historyDays = 0
+	}
+	if historyDays > domain.MaxUsageHistoryDays {
+		historyDays = domain.MaxUsageHistoryDays
+	}
+
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	usage, err := s.repo.GetUsage(ctx, client, today.Format(domain.UsageDayLayout))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el consumo: %w", err)
+	}
+
+	report := &domain.UsageReport{
+		Client:  client,
+		Today:   usage,
+		Quota:   s.quotas.quotaFor(client),
+		ResetAt: today.AddDate(0, 0, 1),
+		History: []domain.DailyUsage{},
+		CostUSD: usage.CostUSD,
+	}
+	if report.Quota > 0 {
+		report.Remaining = max(report.Quota-int64(usage.TotalTokens), 0)
+	}
+
+	for i := 1; i <= historyDays; i++ {
+		day := today.AddDate(0, 0, -i).Format(domain.UsageDayLayout)
+		usage, err := s.repo.GetUsage(ctx, client, day)
+		if err != nil {
+			return nil, fmt.Errorf("error al leer el consumo: %w", err)
+		}
+		if usage.TotalTokens > 0 {
+			report.History = append(report.History, domain.DailyUsage{Day: day, Usage: usage})
+			report.CostUSD += usage.CostUSD
+		}
+	}
+
+	return report, nil
+}
+
+// Statement retorna el extracto del tenant en month
+// Se pueden pedir el mes actual (provisional) y los MaxStatementMonths anteriores
+func (s *UsageServiceImpl) Statement(ctx context.Context, tenant string, month string) (*domain.TenantStatement, error) {
+	start, err := time.Parse(domain.StatementMonthLayout, month)
+	if err != nil {
+		return nil, fmt.Errorf("%w: formato AAAA-MM", domain.ErrInvalidStatementMonth)
+	}
+
+	now := s.now().UTC()
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if start.After(current) {
+		return nil, fmt.Errorf("%w: %s todavía no ha empezado", domain.ErrInvalidStatementMonth, month)
+	}
+	if start.Before(current.AddDate(0, -domain.MaxStatementMonths, 0)) {
+		return nil, fmt.Errorf("%w: como mucho %d meses atrás", domain.ErrInvalidStatementMonth, domain.MaxStatementMonths)
+	}
+
+	usage, err := s.repo.GetTenantUsage(ctx, tenant, month)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el consumo del tenant: %w", err)
+	}
+
+	statement := &domain.TenantStatement{
+		Tenant:           tenant,
+		Month:            month,
+		PeriodStart:      start,
+		PeriodEnd:        start.AddDate(0, 1, 0),
+		Provisional:      start.Equal(current),
+		Models:           usage.Models,
+		TopConversations: usage.Conversations,
+		GeneratedAt:      now,
+	}
+	// Cada petición tiene un modelo: los totales son la suma de los modelos
+	for _, model := range usage.Models {
+		statement.Requests += model.Requests
+		statement.Usage = statement.Usage.Add(model.Usage)
+	}
+
+	slices.SortFunc(statement.Models, func(a, b domain.ModelUsage) int {
+		return cmp.Or(
+			cmp.Compare(b.Usage.CostUSD, a.Usage.CostUSD),
+			cmp.Compare(b.Usage.TotalTokens, a.Usage.TotalTokens),
+			cmp.Compare(a.Model, b.Model),
+		)
+	})
+	slices.SortFunc(statement.TopConversations, func(a, b domain.ConversationUsage) int {
+		return cmp.Or(
+			cmp.Compare(b.Usage.TotalTokens, a.Usage.TotalTokens),
+			cmp.Compare(a.ConversationID, b.ConversationID),
+		)
+	})
+	if len(statement.TopConversations) > domain.MaxStatementConversations {
+		statement.TopConversations = statement.TopConversations[:domain.MaxStatementConversations]
+	}
+	return statement, nil
+}
@@ -2,11 +2,19 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"groq-hexagonal-api/internal/domain"
+
 	"github.com/joho/godotenv"
 )
 
@@ -20,11 +28,371 @@ type Config struct {
 	// Server configuración
 	Port string
 	
-	// Groq API configuración
+	// Puerto del servidor gRPC (vacío = desactivado; requiere make build-grpc)
+	GRPCPort string
+	
+	// Consumidor de chats por cola (vacío = desactivado): URL de NATS,
+	// subject de las peticiones, grupo de las réplicas, subject de las
+	// respuestas sin reply y chats simultáneos
+	QueueURL          string
+	QueueSubject      string
+	QueueGroup        string
+	QueueReplySubject string
+	QueueConcurrency  int
+	
+	// HTTPS: certificado y clave en PEM (vacíos = HTTP sin cifrar) y versión
+	// mínima de TLS ("1.2" o "1.3")
+	TLSCertFile   string
+	TLSKeyFile    string
+	TLSMinVersion string
+	
+	// mTLS: CAs que firman los certificados de cliente (PEM) y si se exigen:
+	// "none", "optional" (se verifican si el cliente envía uno) o "require"
+	TLSClientCAFile string
+	TLSClientAuth   string
+	
+	// Clave de administración (habilita X-Debug-Overrides; vacío = desactivado)
+	AdminAPIKey string
+	
+	// API keys de los clientes (POST /admin/keys): si se exigen en /api/v1 y
+	// cuánto sigue valiendo el secreto anterior tras una rotación
+	APIKeyAuth          bool
+	APIKeyRotationGrace time.Duration
+	
+	// Caducidad de las API keys: con cuánta antelación se avisa (cabecera
+	// X-Key-Expires-In y evento api_key.expiring) y adónde se envían los
+	// eventos (vacío = al log; requiere WEBHOOK_SECRET)
+	APIKeyExpiryWarning time.Duration
+	APIKeyWebhookURL    string
+	
+	// Tokens de vida corta (POST /api/v1/token): secreto con el que se
+	// firman (vacío = desactivados; requiere API_KEY_AUTH) y duración máxima
+	APITokenSecret string
+	APITokenMaxTTL time.Duration
+	
+	// Proveedor de modelos por defecto: "groq", "openai" u "ollama"
+	// Cada petición puede pedir otro de los configurados (campo "provider")
+	LLMProvider string
+	
+	// MockMode sustituye a los proveedores por uno falso que responde sin red
+	// ni API key (eco del mensaje y tokens sintéticos), para desarrollo
+	MockMode bool
+	
+	// CassetteMode graba las llamadas a los proveedores en CassetteDir
+	// ("record") o responde con las grabadas sin llamarlos ("replay"), para
+	// los tests de integración. Vacío = desactivado
+	CassetteMode string
+	CassetteDir  string
+	
+	// Groq API configuración (GroqAPIKey vacío = Groq desactivado)
 	GroqAPIKey   string
 	GroqBaseURL  string
+	
+	// Claves adicionales: las peticiones se reparten entre todas, y cada
+	// conversación va siempre a la misma (ver application.StickyRouter)
+	GroqExtraAPIKeys []string
+	
+	// Enfriamiento de una key que recibe 429 sin Retry-After
+	GroqKeyCooldown time.Duration
+	
+	// OpenAI (OpenAIAPIKey vacío = desactivado)
+	OpenAIAPIKey  string
+	OpenAIBaseURL string
+	
+	// Ollama (OllamaBaseURL vacío = desactivado)
+	OllamaBaseURL string
+	
+	// Modelo por defecto de cada proveedor cuando la petición lo elige
+	// (el del proveedor por defecto es DefaultModel)
+	ProviderDefaultModels map[string]string
+
+	// DefaultModel es el modelo por defecto del proveedor por defecto
 	DefaultModel string
 	HTTPTimeout  time.Duration
+	
+	// Máximo que un cliente puede pedir con X-Request-Timeout (0 = se ignora)
+	MaxRequestTimeout time.Duration
+	
+	// Prompt de sistema para las peticiones que no envían uno (vacío = ninguno)
+	DefaultSystemPrompt string
+	
+	// Secuencias de parada definidas por el operador
+	// StopSequences se aplica a todos los modelos
+	// ModelStopSequences se aplica solo al modelo indicado en la clave
+	StopSequences      []string
+	ModelStopSequences map[string][]string
+	
+	// Límite de longitud de las respuestas (en caracteres, 0 = sin límite)
+	// TenantOutputMaxChars sobrescribe el límite para tenants concretos
+	OutputMaxChars       int
+	TenantOutputMaxChars map[string]int
+	
+	// Instrucciones de sistema obligatorias por tenant (marca, avisos legales)
+	TenantSystemPrompts map[string]string
+	
+	// Modelo por defecto y rangos de temperatura y max_tokens por tenant
+	TenantModelLimits map[string]domain.TenantModelLimits
+	
+	// Reemplazos de modelos retirados por Groq (modelo retirado → reemplazo)
+	ModelAliases map[string]string
+	
+	// Precios por modelo (USD por millón de tokens) para estimar costes
+	// (domain.DefaultModelPricing más los de MODEL_PRICING)
+	ModelPricing map[string]domain.ModelPrice
+	
+	// Ventanas de contexto por modelo, en tokens (domain.DefaultContextWindows
+	// más las de MODEL_CONTEXT_WINDOWS) y qué hacer con las peticiones que no
+	// caben: "reject" (413) o "truncate" (recortar las secciones de
+	// ContextShrinkOrder, en ese orden)
+	ModelContextWindows map[string]int
+	ContextOverflow     string
+	ContextShrinkOrder  []string
+	
+	// Resumen del historial: parte de la ventana a partir de la que se
+	// resumen los turnos antiguos (0 = desactivado), modelo que resume (vacío
+	// = el de la petición) y mensajes recientes que no se resumen nunca
+	HistorySummaryThreshold float64
+	HistorySummaryModel     string
+	HistorySummaryKeep      int
+	
+	// Catálogo de modelos para el enrutado por coste (domain.DefaultModelProfiles
+	// más los de MODEL_PROFILES) y la calidad de las peticiones que no eligen
+	// modelo ni calidad (vacío = se usa DEFAULT_MODEL)
+	ModelProfiles  map[string]domain.ModelProfile
+	DefaultQuality string
+	
+	// Plazo para restaurar una conversación borrada antes de eliminarla
+	ConversationRetention time.Duration
+	
+	// Presupuesto de las conversaciones creadas sin uno: tokens y coste
+	// máximos (0 = sin límite) y el modelo de los turnos que lo superan
+	// (vacío = se rechazan)
+	ConversationMaxTokens     int
+	ConversationMaxCostUSD    float64
+	ConversationFallbackModel string
+	
+	// Chats asíncronos: workers que llaman al modelo a la vez, jobs que pueden
+	// esperar en la cola y cuánto se guarda cada job
+	JobWorkers   int
+	JobQueueSize int
+	JobRetention time.Duration
+	
+	// Respuestas grandes de los jobs: directorio donde se guardan aparte
+	// (vacío = siempre dentro del job) y bytes de texto a partir de los que
+	// se guardan
+	JobResultDir        string
+	JobResultSpillBytes int
+	
+	// Callbacks de los jobs: secreto HMAC (vacío = callbacks desactivados),
+	// intentos, espera antes del primer reintento (se dobla en cada uno),
+	// timeout de cada intento y si se admiten URLs internas
+	WebhookSecret       string
+	WebhookMaxAttempts  int
+	WebhookBackoff      time.Duration
+	WebhookTimeout      time.Duration
+	WebhookAllowPrivate bool
+	
+	// Lista de salida: con EgressRestrict, los clientes HTTP solo se conectan
+	// a los proveedores y webhooks configurados y a EgressAllowedHosts
+	// (nombres o "*.dominio"; ver EgressAllowlist)
+	EgressRestrict     bool
+	EgressAllowedHosts []string
+	
+	// WebUI sirve la página de chat embebida en /ui
+	WebUI bool
+	
+	// Dónde se guardan las conversaciones: "memory", "postgres" o "redis"
+	StorageBackend string
+	
+	// DSN de PostgreSQL (solo con StorageBackend = "postgres") y, opcional,
+	// el de una réplica de lectura
+	DatabaseURL        string
+	DatabaseReplicaURL string
+	
+	// Pools de PostgreSQL (cada uno: el del primario y el de la réplica):
+	// conexiones abiertas e inactivas como máximo (0 = sin límite / el de
+	// database/sql) y vida máxima de cada conexión (0 = sin límite)
+	DatabaseMaxOpenConns    int
+	DatabaseMaxIdleConns    int
+	DatabaseConnMaxLifetime time.Duration
+	DatabaseConnMaxIdleTime time.Duration
+	
+	// Límite de cada sentencia (0 = sin límite) y reintentos de una lectura
+	// si se pierde la conexión (failover)
+	DatabaseStatementTimeout time.Duration
+	DatabaseReadRetries      int
+	
+	// Redis: conversaciones (StorageBackend = "redis") y contador de rate limit
+	// compartido entre réplicas. Vacío = no se usa Redis
+	RedisURL       string
+	RedisKeyPrefix string
+	
+	// Tamaño máximo del body de las peticiones HTTP, en bytes (0 = sin límite)
+	MaxBodyBytes int64
+	
+	// Tiempo de reconexión que se indica a los clientes SSE con "retry:"
+	// (0 = no se envía)
+	SSERetry time.Duration
+	
+	// Cuánto se guardan los eventos de un flujo SSE terminado para que el
+	// cliente lo reanude con Last-Event-ID (0 = los flujos no se reanudan)
+	StreamResumeWindow time.Duration
+	
+	// Librería JSON de la capa HTTP: "std", "jsoniter" o "segmentio" (las dos
+	// últimas requieren compilar con su etiqueta)
+	JSONCodec string
+	
+	// Memoria: límite blando del runtime en MiB (0 = el de GOMEMLIMIT), GOGC
+	// (0 = el de GOGC; -1 = GC solo por el límite) y porcentaje del límite a
+	// partir del que se rechazan las peticiones nuevas (0 = nunca)
+	MemoryLimitMB       int
+	GCPercent           int
+	MemoryShedPercent   int
+	MemoryCheckInterval time.Duration
+	
+	// Watchdog de tareas colgadas: una petición o un job está colgado al
+	// pasar de WatchdogFactor veces su plazo (0 = sin watchdog); se revisan
+	// cada WatchdogInterval
+	WatchdogFactor   int
+	WatchdogInterval time.Duration
+	
+	// Rate limit por cliente: peticiones por ventana (0 = desactivado)
+	RateLimitRequests int
+	RateLimitWindow   time.Duration
+	
+	// Peticiones simultáneas por cliente (0 = sin límite) y franjas horarias
+	// con límites propios, en la zona horaria THROTTLE_TIMEZONE
+	MaxConcurrentRequests int
+	ThrottleSchedule      []domain.ThrottleWindow
+	ThrottleTimezone      string
+	
+	// Espera máxima de las peticiones con ?wait=true y cuántas pueden esperar
+	// a la vez en cada réplica (0 en cualquiera de los dos = sin espera)
+	RateLimitMaxWait   time.Duration
+	RateLimitWaitQueue int
+	
+	// Bulkhead: chats simultáneos de la réplica (0 = sin límite) y la cola
+	// corta en la que esperan hueco antes del 503
+	BulkheadMaxInFlight int
+	BulkheadQueueSize   int
+	BulkheadMaxWait     time.Duration
+	
+	// Cuota diaria de tokens por cliente (0 = sin cuota) y cuotas propias de
+	// clientes concretos ("key:<hash>", "tenant:<id>" o "ip:<ip>")
+	DailyTokenQuota   int64
+	ClientTokenQuotas map[string]int64
+	
+	// Eventos de consumo para facturación y analítica (vacío = desactivados):
+	// URL de NATS y subject en el que se publican
+	UsageEventsURL     string
+	UsageEventsSubject string
+	
+	// Muestreo de POST /api/v1/chat para el corpus de reproducción:
+	// porcentaje de peticiones que se guardan (0 = desactivado), directorio
+	// y máximo de muestras (0 = sin máximo)
+	ReplaySamplePercent float64
+	ReplayCorpusDir     string
+	ReplayMaxSamples    int
+	
+	// Caché de respuestas sin streaming: tiempo que se guarda cada respuesta
+	// (0 = desactivada) y máximo de respuestas en memoria (sin Redis)
+	ResponseCacheTTL        time.Duration
+	ResponseCacheMaxEntries int
+	
+	// Caché semántica (sobre la de respuestas): similitud mínima de la
+	// pregunta (0 = desactivada), proveedor y modelo de embeddings y máximo
+	// de respuestas en memoria
+	SemanticCacheThreshold  float64
+	SemanticCacheProvider   string
+	SemanticCacheModel      string
+	SemanticCacheMaxEntries int
+	
+	// Caché por ruta: política de cada ruta de /api/v1 (ver
+	// domain/route_cache.go) y máximo de respuestas en memoria (sin Redis)
+	RouteCachePolicies   map[string]domain.RouteCachePolicy
+	RouteCacheMaxEntries int
+	
+	// Idempotency-Key en POST /api/v1/chat: tiempo que se guarda cada
+	// respuesta (0 = la cabecera se ignora) y máximo de claves en memoria
+	IdempotencyTTL        time.Duration
+	IdempotencyMaxEntries int
+	
+	// Salud de los modelos: ventana de las estadísticas y circuit breaker
+	// (fallos seguidos que abren el circuito, 0 = desactivado; y enfriamiento)
+	ModelHealthWindow      time.Duration
+	CircuitBreakerFailures int
+	CircuitBreakerCooldown time.Duration
+	
+	// Directorio con plugins WASM de hooks (binario compilado con -tags wasmplugins)
+	// Vacío = sin plugins
+	PluginsDir string
+	
+	// Modelo usado por POST /api/v1/prompts/improve (conviene uno potente)
+	PromptOptimizerModel string
+	
+	// Modelo de POST /api/v1/code/explain y /code/review (conviene uno de código)
+	CodeModel string
+	
+	// Modelo que verifica las respuestas con "verify" (vacío = el de la respuesta)
+	GroundingModel string
+	
+	// Modelo Whisper de POST /api/v1/audio/transcriptions (solo con Groq)
+	TranscriptionModel string
+	
+	// Repositorio Git con las personas (plantillas de prompt); vacío = desactivado
+	PromptTemplatesGitURL    string
+	PromptTemplatesGitBranch string
+	PromptTemplatesDir       string        // Subdirectorio dentro del repositorio
+	PromptTemplatesCheckout  string        // Ruta local del clon
+	PromptTemplatesRefresh   time.Duration // Cada cuánto se hace git pull
+	
+	// Access log: formato ("json" o "combined") y campos del formato json
+	AccessLogFormat string
+	AccessLogFields []string
+	
+	// Qué se registra del contenido de las peticiones ("none" o "hash") y
+	// cabeceras que se registran redactadas (además de Authorization, X-Admin-Key...)
+	LogContent       string
+	LogRedactHeaders []string
+	
+	// Detección de idioma y valores por defecto por idioma
+	LanguageDetection bool
+	LocaleProfiles    map[string]LocaleProfile
+	
+	// Anonimización de los mensajes del usuario antes de llamar al proveedor
+	// y tipos de datos personales que se anonimizan
+	PIIRedaction      bool
+	PIIRedactionTypes []string
+	
+	// Moderación con Llama Guard: qué se modera (entrada y/o salida), modelo
+	// y proveedor, categorías que bloquean (vacío = todas) y si la petición
+	// continúa cuando Llama Guard no responde
+	ModerationInput      bool
+	ModerationOutput     bool
+	ModerationModel      string
+	ModerationProvider   string
+	ModerationCategories []string
+	ModerationFailOpen   bool
+}
+
+// LocaleProfile son los valores por defecto de un idioma (ver LOCALE_PROFILES)
+type LocaleProfile struct {
+	Model        string `json:"model"`
+	SystemPrompt string `json:"system_prompt"`
+}
+
+// defaultProviderModels son los modelos por defecto de cada proveedor
+var defaultProviderModels = map[string]string{
+	domain.ProviderGroq:   "llama-3.3-70b-versatile",
+	domain.ProviderOpenAI: "gpt-4o-mini",
+	domain.ProviderOllama: "llama3.2",
+}
+
+// defaultEmbeddingModels son los modelos de embeddings por defecto de la
+// caché semántica (Groq no tiene embeddings)
+var defaultEmbeddingModels = map[string]string{
+	domain.ProviderOpenAI: "text-embedding-3-small",
+	domain.ProviderOllama: "nomic-embed-text",
 }
 
 // ============================================================================
@@ -50,16 +418,323 @@ func Load() (*Config, error) {
 		fmt.Println("⚠️  Advertencia: archivo .env no encontrado, usando variables de entorno del sistema")
 	}
 	
+	return fromEnv()
+}
+
+// Reload vuelve a cargar la configuración (usado al recibir SIGHUP)
+// A diferencia de Load, los valores de .env reemplazan a los del entorno:
+// de lo contrario los cambios del archivo no tendrían efecto
+func Reload() (*Config, error) {
+	if err := godotenv.Overload(); err != nil {
+		fmt.Println("⚠️  Advertencia: archivo .env no encontrado, usando variables de entorno del sistema")
+	}
+	
+	return fromEnv()
+}
+
+// fromEnv construye y valida la configuración a partir del entorno
+func fromEnv() (*Config, error) {
 	// ========================================================================
 	// 2. LEER VARIABLES DE ENTORNO
 	// ========================================================================
 	
 	config := &Config{
 		Port:         getEnv("PORT", "8080"),              // Default: 8080
+		GRPCPort:     getEnv("GRPC_PORT", ""),             // Opcional
+		
+		QueueURL:          getEnv("QUEUE_URL", ""),
+		QueueSubject:      getEnv("QUEUE_SUBJECT", "chat.requests"),
+		QueueGroup:        getEnv("QUEUE_GROUP", "groq-api"),
+		QueueReplySubject: getEnv("QUEUE_REPLY_SUBJECT", "chat.responses"),
+		QueueConcurrency:  getEnvAsInt("QUEUE_CONCURRENCY", 4),
+		
+		TLSCertFile:     getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:      getEnv("TLS_KEY_FILE", ""),
+		TLSMinVersion:   getEnv("TLS_MIN_VERSION", "1.2"),
+		TLSClientCAFile: getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuth:   getEnv("TLS_CLIENT_AUTH", "none"),
+		
 		GroqAPIKey:   getEnv("GROQ_API_KEY", ""),          // Sin default (requerido)
+		AdminAPIKey:  getEnv("ADMIN_API_KEY", ""),         // Opcional
 		GroqBaseURL:  getEnv("GROQ_BASE_URL", "https://api.groq.com/openai/v1"),
-		DefaultModel: getEnv("DEFAULT_MODEL", "llama-3.3-70b-versatile"),
+		GroqExtraAPIKeys: getEnvAsList("GROQ_EXTRA_API_KEYS"),
+		GroqKeyCooldown:  getEnvAsDuration("GROQ_KEY_COOLDOWN", 30*time.Second),
+		
+		APIKeyAuth:          getEnvAsBool("API_KEY_AUTH", false),
+		APIKeyRotationGrace: time.Duration(getEnvAsInt("API_KEY_ROTATION_GRACE_HOURS", 24)) * time.Hour,
+		APIKeyExpiryWarning: time.Duration(getEnvAsInt("API_KEY_EXPIRY_WARNING_HOURS", 168)) * time.Hour,
+		APIKeyWebhookURL:    getEnv("API_KEY_WEBHOOK_URL", ""),
+		APITokenSecret:      getEnv("API_TOKEN_SECRET", ""),
+		APITokenMaxTTL:      time.Duration(getEnvAsInt("API_TOKEN_MAX_TTL_MINUTES", 60)) * time.Minute,
+		
+		LLMProvider:   getEnv("LLM_PROVIDER", domain.ProviderGroq),
+		MockMode:      getEnvAsBool("MOCK_MODE", false),
+		CassetteMode:  getEnv("CASSETTE_MODE", ""),
+		CassetteDir:   getEnv("CASSETTE_DIR", "testdata/cassettes"),
+		OpenAIAPIKey:  getEnv("OPENAI_API_KEY", ""),
+		OpenAIBaseURL: getEnv("OPENAI_BASE_URL", "https://api.openai.com/v1"),
+		OllamaBaseURL: getEnv("OLLAMA_BASE_URL", ""),
+		
 		HTTPTimeout:  getEnvAsDuration("HTTP_TIMEOUT", 30*time.Second),
+		
+		MaxRequestTimeout: getEnvAsDuration("MAX_REQUEST_TIMEOUT", 120*time.Second),
+		
+		DefaultSystemPrompt: getEnv("DEFAULT_SYSTEM_PROMPT", ""),
+		
+		StopSequences: getEnvAsList("STOP_SEQUENCES"),
+		
+		OutputMaxChars: getEnvAsInt("OUTPUT_MAX_CHARS", 0),
+		
+		LanguageDetection: getEnvAsBool("LANGUAGE_DETECTION", false),
+		
+		PIIRedaction:      getEnvAsBool("PII_REDACTION", false),
+		PIIRedactionTypes: getEnvAsList("PII_REDACTION_TYPES"),
+		
+		ModerationInput:      getEnvAsBool("MODERATION_INPUT", false),
+		ModerationOutput:     getEnvAsBool("MODERATION_OUTPUT", false),
+		ModerationModel:      getEnv("MODERATION_MODEL", "meta-llama/llama-guard-4-12b"),
+		ModerationProvider:   getEnv("MODERATION_PROVIDER", domain.ProviderGroq),
+		ModerationCategories: getEnvAsList("MODERATION_CATEGORIES"),
+		ModerationFailOpen:   getEnvAsBool("MODERATION_FAIL_OPEN", false),
+		
+		PromptTemplatesGitURL:    getEnv("PROMPT_TEMPLATES_GIT_URL", ""),
+		PromptTemplatesGitBranch: getEnv("PROMPT_TEMPLATES_GIT_BRANCH", "main"),
+		PromptTemplatesDir:       getEnv("PROMPT_TEMPLATES_DIR", ""),
+		PromptTemplatesCheckout:  getEnv("PROMPT_TEMPLATES_CHECKOUT", filepath.Join(os.TempDir(), "groq-prompt-templates")),
+		PromptTemplatesRefresh:   getEnvAsDuration("PROMPT_TEMPLATES_REFRESH", 5*time.Minute),
+		
+		ContextOverflow:    getEnv("CONTEXT_OVERFLOW", "reject"),
+		ContextShrinkOrder: getEnvAsList("CONTEXT_SHRINK_ORDER"),
+		
+		HistorySummaryThreshold: getEnvAsFloat("HISTORY_SUMMARY_THRESHOLD", 0),
+		HistorySummaryModel:     getEnv("HISTORY_SUMMARY_MODEL", ""),
+		HistorySummaryKeep:      getEnvAsInt("HISTORY_SUMMARY_KEEP", 6),
+		
+		AccessLogFormat: getEnv("ACCESS_LOG_FORMAT", "json"),
+		AccessLogFields: getEnvAsList("ACCESS_LOG_FIELDS"),
+		LogContent:       getEnv("LOG_CONTENT", "none"),
+		LogRedactHeaders: getEnvAsList("LOG_REDACT_HEADERS"),
+		
+		// En horas: el plazo típico es de días (por defecto, 7)
+		ConversationRetention: time.Duration(getEnvAsInt("CONVERSATION_RETENTION_HOURS", 168)) * time.Hour,
+		
+		ConversationMaxTokens:     getEnvAsInt("CONVERSATION_MAX_TOKENS", 0),
+		ConversationMaxCostUSD:    getEnvAsFloat("CONVERSATION_MAX_COST_USD", 0),
+		ConversationFallbackModel: getEnv("CONVERSATION_FALLBACK_MODEL", ""),
+		
+		JobWorkers:   getEnvAsInt("JOB_WORKERS", 4),
+		JobQueueSize: getEnvAsInt("JOB_QUEUE_SIZE", 100),
+		JobRetention: time.Duration(getEnvAsInt("JOB_RETENTION_HOURS", 24)) * time.Hour,
+		
+		JobResultDir:        getEnv("JOB_RESULT_DIR", ""),
+		JobResultSpillBytes: getEnvAsInt("JOB_RESULT_SPILL_BYTES", 1<<20),
+		
+		WebhookSecret:       getEnv("WEBHOOK_SECRET", ""),
+		WebhookMaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 6),
+		WebhookBackoff:      time.Duration(getEnvAsInt("WEBHOOK_BACKOFF_SECONDS", 5)) * time.Second,
+		WebhookTimeout:      time.Duration(getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10)) * time.Second,
+		WebhookAllowPrivate: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE", false),
+		
+		EgressRestrict:     getEnvAsBool("EGRESS_RESTRICT", false),
+		EgressAllowedHosts: getEnvAsList("EGRESS_ALLOWED_HOSTS"),
+		
+		WebUI: getEnvAsBool("WEB_UI", true),
+		
+		StorageBackend: getEnv("STORAGE_BACKEND", "memory"),
+		DatabaseURL:    getEnv("DATABASE_URL", ""),
+		
+		DatabaseReplicaURL:       getEnv("DATABASE_REPLICA_URL", ""),
+		DatabaseMaxOpenConns:     getEnvAsInt("DATABASE_MAX_OPEN_CONNS", 20),
+		DatabaseMaxIdleConns:     getEnvAsInt("DATABASE_MAX_IDLE_CONNS", 5),
+		DatabaseConnMaxLifetime:  getEnvAsDuration("DATABASE_CONN_MAX_LIFETIME", 30*time.Minute),
+		DatabaseConnMaxIdleTime:  getEnvAsDuration("DATABASE_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		DatabaseStatementTimeout: getEnvAsDuration("DATABASE_STATEMENT_TIMEOUT", 0),
+		DatabaseReadRetries:      getEnvAsInt("DATABASE_READ_RETRIES", 2),
+		
+		RedisURL:       getEnv("REDIS_URL", ""),
+		RedisKeyPrefix: getEnv("REDIS_KEY_PREFIX", "groq:"),
+		
+		// Por defecto, 32 MiB: caben 5 imágenes de 4 MiB en base64 o un audio de 25 MiB
+		MaxBodyBytes: int64(getEnvAsInt("MAX_BODY_BYTES", 32<<20)),
+		
+		SSERetry:           time.Duration(getEnvAsInt("SSE_RETRY_MS", 3000)) * time.Millisecond,
+		StreamResumeWindow: time.Duration(getEnvAsInt("STREAM_RESUME_SECONDS", 0)) * time.Second,
+		
+		JSONCodec: getEnv("JSON_CODEC", "std"),
+		
+		MemoryLimitMB:       getEnvAsInt("MEMORY_LIMIT_MB", 0),
+		GCPercent:           getEnvAsInt("GC_PERCENT", 0),
+		MemoryShedPercent:   getEnvAsInt("MEMORY_SHED_PERCENT", 0),
+		MemoryCheckInterval: time.Duration(getEnvAsInt("MEMORY_CHECK_INTERVAL_MS", 500)) * time.Millisecond,
+		
+		WatchdogFactor:   getEnvAsInt("WATCHDOG_FACTOR", 3),
+		WatchdogInterval: getEnvAsDuration("WATCHDOG_INTERVAL", 5*time.Second),
+		
+		RateLimitRequests: getEnvAsInt("RATE_LIMIT_REQUESTS", 0),
+		RateLimitWindow:   getEnvAsDuration("RATE_LIMIT_WINDOW", time.Minute),
+		
+		MaxConcurrentRequests: getEnvAsInt("MAX_CONCURRENT_REQUESTS", 0),
+		ThrottleTimezone:      getEnv("THROTTLE_TIMEZONE", "UTC"),
+		
+		RateLimitMaxWait:   getEnvAsDuration("RATE_LIMIT_MAX_WAIT", 30*time.Second),
+		RateLimitWaitQueue: getEnvAsInt("RATE_LIMIT_WAIT_QUEUE", 100),
+		
+		BulkheadMaxInFlight: getEnvAsInt("BULKHEAD_MAX_IN_FLIGHT", 0),
+		BulkheadQueueSize:   getEnvAsInt("BULKHEAD_QUEUE_SIZE", 50),
+		BulkheadMaxWait:     time.Duration(getEnvAsInt("BULKHEAD_MAX_WAIT_MS", 500)) * time.Millisecond,
+		
+		DailyTokenQuota: int64(getEnvAsInt("DAILY_TOKEN_QUOTA", 0)),
+		
+		UsageEventsURL:     getEnv("USAGE_EVENTS_URL", ""),
+		UsageEventsSubject: getEnv("USAGE_EVENTS_SUBJECT", "usage.events"),
+		
+		ReplaySamplePercent: getEnvAsFloat("REPLAY_SAMPLE_PERCENT", 0),
+		ReplayCorpusDir:     getEnv("REPLAY_CORPUS_DIR", "replay-corpus"),
+		ReplayMaxSamples:    getEnvAsInt("REPLAY_MAX_SAMPLES", 10000),
+		
+		ResponseCacheTTL:        getEnvAsDuration("RESPONSE_CACHE_TTL", 0),
+		ResponseCacheMaxEntries: getEnvAsInt("RESPONSE_CACHE_MAX_ENTRIES", 1000),
+		
+		SemanticCacheThreshold:  getEnvAsFloat("SEMANTIC_CACHE_THRESHOLD", 0),
+		SemanticCacheProvider:   getEnv("SEMANTIC_CACHE_PROVIDER", domain.ProviderOpenAI),
+		SemanticCacheModel:      getEnv("SEMANTIC_CACHE_MODEL", ""),
+		SemanticCacheMaxEntries: getEnvAsInt("SEMANTIC_CACHE_MAX_ENTRIES", 1000),
+		
+		RouteCacheMaxEntries: getEnvAsInt("ROUTE_CACHE_MAX_ENTRIES", 1000),
+		
+		IdempotencyTTL:        getEnvAsDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		IdempotencyMaxEntries: getEnvAsInt("IDEMPOTENCY_MAX_ENTRIES", 10000),
+		
+		ModelHealthWindow:      getEnvAsDuration("MODEL_HEALTH_WINDOW", 5*time.Minute),
+		CircuitBreakerFailures: getEnvAsInt("CIRCUIT_BREAKER_FAILURES", 5),
+		CircuitBreakerCooldown: getEnvAsDuration("CIRCUIT_BREAKER_COOLDOWN", 30*time.Second),
+		
+		PluginsDir: getEnv("PLUGINS_DIR", ""),
+		
+		TranscriptionModel: getEnv("TRANSCRIPTION_MODEL", domain.DefaultTranscriptionModel),
+	}
+	
+	// PROVIDER_DEFAULT_MODELS es un objeto JSON: {"openai": "gpt-4o", "ollama": "qwen2.5"}
+	// Los proveedores que no aparecen usan los modelos de defaultProviderModels
+	config.ProviderDefaultModels = make(map[string]string)
+	for provider, model := range defaultProviderModels {
+		config.ProviderDefaultModels[provider] = model
+	}
+	if err := getEnvAsJSON("PROVIDER_DEFAULT_MODELS", &config.ProviderDefaultModels); err != nil {
+		return nil, err
+	}
+	
+	// El modelo por defecto depende del proveedor: los nombres no se comparten
+	config.DefaultModel = getEnv("DEFAULT_MODEL", config.ProviderDefaultModels[config.LLMProvider])
+	
+	// Por defecto, el optimizador de prompts usa el modelo por defecto
+	config.PromptOptimizerModel = getEnv("PROMPT_OPTIMIZER_MODEL", config.DefaultModel)
+	config.CodeModel = getEnv("CODE_MODEL", config.DefaultModel)
+	config.GroundingModel = getEnv("GROUNDING_MODEL", "")
+	
+	// MODEL_STOP_SEQUENCES es un objeto JSON: {"modelo": ["seq1", "seq2"]}
+	// A diferencia de los valores simples, un JSON mal formado es un error
+	if err := getEnvAsJSON("MODEL_STOP_SEQUENCES", &config.ModelStopSequences); err != nil {
+		return nil, err
+	}
+	
+	// TENANT_OUTPUT_MAX_CHARS es un objeto JSON: {"tenant": caracteres}
+	if err := getEnvAsJSON("TENANT_OUTPUT_MAX_CHARS", &config.TenantOutputMaxChars); err != nil {
+		return nil, err
+	}
+	
+	// MODEL_ALIASES es un objeto JSON: {"modelo-retirado": "reemplazo"}
+	if err := getEnvAsJSON("MODEL_ALIASES", &config.ModelAliases); err != nil {
+		return nil, err
+	}
+	
+	// MODEL_PRICING es un objeto JSON: {"modelo": {"input": 0.59, "output": 0.79}}
+	// Se suma a los precios conocidos (domain.DefaultModelPricing) o los corrige
+	config.ModelPricing = make(map[string]domain.ModelPrice)
+	for model, price := range domain.DefaultModelPricing {
+		config.ModelPricing[model] = price
+	}
+	if err := getEnvAsJSON("MODEL_PRICING", &config.ModelPricing); err != nil {
+		return nil, err
+	}
+	
+	// MODEL_CONTEXT_WINDOWS es un objeto JSON: {"modelo": tokens}
+	// Se suma a las ventanas conocidas; 0 desactiva la comprobación del modelo
+	config.ModelContextWindows = make(map[string]int)
+	for model, window := range domain.DefaultContextWindows {
+		config.ModelContextWindows[model] = window
+	}
+	if err := getEnvAsJSON("MODEL_CONTEXT_WINDOWS", &config.ModelContextWindows); err != nil {
+		return nil, err
+	}
+	
+	// THROTTLE_SCHEDULE es un array JSON de franjas:
+	// [{"days": ["mon", "fri"], "from": "09:00", "to": "18:00", "rate_limit_requests": 30}]
+	if err := getEnvAsJSON("THROTTLE_SCHEDULE", &config.ThrottleSchedule); err != nil {
+		return nil, err
+	}
+	
+	// MODEL_PROFILES es un objeto JSON: {"modelo": {"tier": "best", "capabilities": ["tools"]}}
+	// Se suma al catálogo conocido o lo corrige; sin provider, el de LLM_PROVIDER
+	config.ModelProfiles = make(map[string]domain.ModelProfile)
+	for model, profile := range domain.DefaultModelProfiles {
+		config.ModelProfiles[model] = profile
+	}
+	if err := getEnvAsJSON("MODEL_PROFILES", &config.ModelProfiles); err != nil {
+		return nil, err
+	}
+	for model, profile := range config.ModelProfiles {
+		if profile.Provider == "" {
+			profile.Provider = config.LLMProvider
+			config.ModelProfiles[model] = profile
+		}
+	}
+	config.DefaultQuality = getEnv("DEFAULT_QUALITY", "")
+	
+	// CLIENT_TOKEN_QUOTAS es un objeto JSON: {"key:3f9a1c0b7e2d": tokens}
+	if err := getEnvAsJSON("CLIENT_TOKEN_QUOTAS", &config.ClientTokenQuotas); err != nil {
+		return nil, err
+	}
+	
+	// TENANT_SYSTEM_PROMPTS es un objeto JSON: {"tenant": "instrucciones"}
+	if err := getEnvAsJSON("TENANT_SYSTEM_PROMPTS", &config.TenantSystemPrompts); err != nil {
+		return nil, err
+	}
+	
+	// TENANT_MODEL_LIMITS es un objeto JSON:
+	// {"acme": {"default_model": "...", "max_temperature": 1.0, "max_tokens": 2048}}
+	if err := getEnvAsJSON("TENANT_MODEL_LIMITS", &config.TenantModelLimits); err != nil {
+		return nil, err
+	}
+	
+	// LOCALE_PROFILES es un objeto JSON: {"es": {"model": "...", "system_prompt": "..."}}
+	if err := getEnvAsJSON("LOCALE_PROFILES", &config.LocaleProfiles); err != nil {
+		return nil, err
+	}
+	
+	// ROUTE_CACHE_POLICIES es un objeto JSON:
+	// {"GET /api/v1/models": {"ttl": "5m"}, "POST /api/v1/classify": {"ttl": "1h", "key": "body"}}
+	if err := getEnvAsJSON("ROUTE_CACHE_POLICIES", &config.RouteCachePolicies); err != nil {
+		return nil, err
+	}
+	
+	// Por defecto se recortan los documentos y después el historial
+	if len(config.ContextShrinkOrder) == 0 {
+		config.ContextShrinkOrder = domain.DefaultContextShrinkOrder
+	}
+	
+	// Por defecto se anonimizan los datos que identifican a una persona
+	if len(config.PIIRedactionTypes) == 0 {
+		config.PIIRedactionTypes = []string{domain.PIIEmail, domain.PIIPhone, domain.PIICreditCard, domain.PIINationalID}
+	}
+	
+	// GROQ_API_KEYS da todas las keys a la vez: la primera hace de
+	// GROQ_API_KEY (si no está) y el resto se suman a las adicionales
+	if keys := getEnvAsList("GROQ_API_KEYS"); len(keys) > 0 {
+		if config.GroqAPIKey == "" {
+			config.GroqAPIKey, keys = keys[0], keys[1:]
+		}
+		config.GroqExtraAPIKeys = append(config.GroqExtraAPIKeys, keys...)
 	}
 	
 	// ========================================================================
@@ -77,27 +752,596 @@ func Load() (*Config, error) {
 // MÉTODOS DE VALIDACIÓN
 // ============================================================================
 
+// RuntimeSettings retorna los ajustes que se pueden recargar en caliente
+func (c *Config) RuntimeSettings() domain.RuntimeSettings {
+	return domain.RuntimeSettings{
+		DefaultModel:          c.DefaultModel,
+		HTTPTimeout:           c.HTTPTimeout,
+		RateLimitRequests:     c.RateLimitRequests,
+		RateLimitWindow:       c.RateLimitWindow,
+		MaxConcurrentRequests: c.MaxConcurrentRequests,
+		ThrottleSchedule: domain.ThrottleSchedule{
+			Windows:  c.ThrottleSchedule,
+			Location: c.throttleLocation(),
+		},
+	}
+}
+
+// throttleLocation retorna la zona horaria de las franjas (UTC si no es válida;
+// Validate ya lo rechaza)
+func (c *Config) throttleLocation() *time.Location {
+	location, err := time.LoadLocation(c.ThrottleTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// GroqAPIKeys retorna todas las API keys de Groq (la principal primero)
+func (c *Config) GroqAPIKeys() []string {
+	return append([]string{c.GroqAPIKey}, c.GroqExtraAPIKeys...)
+}
+
+// EmbeddingModel retorna el modelo de embeddings de la caché semántica
+// (SEMANTIC_CACHE_MODEL o el del proveedor)
+func (c *Config) EmbeddingModel() string {
+	if c.SemanticCacheModel != "" {
+		return c.SemanticCacheModel
+	}
+	return defaultEmbeddingModels[c.SemanticCacheProvider]
+}
+
+// RouteCacheEnabled indica si alguna ruta tiene política de caché (TTL > 0)
+func (c *Config) RouteCacheEnabled() bool {
+	for _, policy := range c.RouteCachePolicies {
+		if policy.Duration() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ConversationBudget retorna el presupuesto de las conversaciones creadas
+// sin uno; false si no hay ningún límite configurado
+func (c *Config) ConversationBudget() (domain.ConversationBudget, bool) {
+	budget := domain.ConversationBudget{
+		MaxTotalTokens: c.ConversationMaxTokens,
+		MaxCostUSD:     c.ConversationMaxCostUSD,
+		FallbackModel:  c.ConversationFallbackModel,
+	}
+	return budget, budget.MaxTotalTokens > 0 || budget.MaxCostUSD > 0
+}
+
+// EgressAllowlist retorna los destinos permitidos con EGRESS_RESTRICT: las
+// URLs de los proveedores y del webhook de API keys configurados y
+// EGRESS_ALLOWED_HOSTS
+func (c *Config) EgressAllowlist() []string {
+	entries := append([]string{}, c.EgressAllowedHosts...)
+	if c.GroqAPIKey != "" {
+		entries = append(entries, c.GroqBaseURL)
+	}
+	if c.OpenAIAPIKey != "" {
+		entries = append(entries, c.OpenAIBaseURL)
+	}
+	if c.OllamaBaseURL != "" {
+		entries = append(entries, c.OllamaBaseURL)
+	}
+	if c.APIKeyWebhookURL != "" {
+		entries = append(entries, c.APIKeyWebhookURL)
+	}
+	return entries
+}
+
+// EnabledProviders retorna los proveedores configurados y el modelo por
+// defecto de cada uno (el del proveedor por defecto es DefaultModel)
+func (c *Config) EnabledProviders() map[string]string {
+	// En modo mock solo existe el proveedor por defecto (el falso)
+	if c.MockMode {
+		return map[string]string{c.LLMProvider: c.DefaultModel}
+	}
+	
+	enabled := make(map[string]string)
+	// Al reproducir, el proveedor por defecto existe aunque no tenga API key
+	if c.Replaying() {
+		enabled[c.LLMProvider] = c.DefaultModel
+	}
+	if c.GroqAPIKey != "" {
+		enabled[domain.ProviderGroq] = c.ProviderDefaultModels[domain.ProviderGroq]
+	}
+	if c.OpenAIAPIKey != "" {
+		enabled[domain.ProviderOpenAI] = c.ProviderDefaultModels[domain.ProviderOpenAI]
+	}
+	if c.OllamaBaseURL != "" {
+		enabled[domain.ProviderOllama] = c.ProviderDefaultModels[domain.ProviderOllama]
+	}
+	if _, ok := enabled[c.LLMProvider]; ok {
+		enabled[c.LLMProvider] = c.DefaultModel
+	}
+	return enabled
+}
+
+// Replaying indica si los proveedores se sustituyen por las llamadas grabadas
+func (c *Config) Replaying() bool {
+	return c.CassetteMode == "replay"
+}
+
+// TLSEnabled indica si el servidor HTTP sirve HTTPS
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != ""
+}
+
+// ModerationEnabled indica si se modera la entrada o la salida
+func (c *Config) ModerationEnabled() bool {
+	return c.ModerationInput || c.ModerationOutput
+}
+
 // Validate verifica que la configuración sea válida
 func (c *Config) Validate() error {
-	// Verificar que el API key no esté vacío
-	if c.GroqAPIKey == "" {
-		return fmt.Errorf("GROQ_API_KEY es requerido")
+	// El proveedor por defecto debe existir y estar configurado
+	// (en modo mock o reproduciendo no se llama a ninguno: basta con que exista)
+	switch c.LLMProvider {
+	case domain.ProviderGroq:
+		if c.GroqAPIKey == "" && !c.MockMode && !c.Replaying() {
+			return fmt.Errorf("GROQ_API_KEY (o GROQ_API_KEYS) es requerido")
+		}
+	case domain.ProviderOpenAI:
+		if c.OpenAIAPIKey == "" && !c.MockMode && !c.Replaying() {
+			return fmt.Errorf("OPENAI_API_KEY es requerido con LLM_PROVIDER=openai")
+		}
+	case domain.ProviderOllama:
+		if c.OllamaBaseURL == "" && !c.MockMode && !c.Replaying() {
+			return fmt.Errorf("OLLAMA_BASE_URL es requerido con LLM_PROVIDER=ollama")
+		}
+	default:
+		return fmt.Errorf("LLM_PROVIDER debe ser \"groq\", \"openai\" u \"ollama\"")
+	}
+	
+	switch c.CassetteMode {
+	case "", "record", "replay":
+	default:
+		return fmt.Errorf("CASSETTE_MODE debe ser \"record\" o \"replay\"")
+	}
+	if c.CassetteMode != "" && c.CassetteDir == "" {
+		return fmt.Errorf("CASSETTE_DIR es requerido con CASSETTE_MODE")
+	}
+	if c.CassetteMode != "" && c.MockMode {
+		return fmt.Errorf("MOCK_MODE y CASSETTE_MODE no se pueden usar a la vez")
 	}
 	
 	// Verificar que el base URL no esté vacío
-	if c.GroqBaseURL == "" {
+	if c.GroqAPIKey != "" && c.GroqBaseURL == "" {
 		return fmt.Errorf("GROQ_BASE_URL es requerido")
 	}
 	
+	// Una key repetida contaría dos veces en el reparto
+	seenKeys := make(map[string]bool)
+	for _, key := range c.GroqAPIKeys() {
+		if seenKeys[key] {
+			return fmt.Errorf("las API keys de Groq no se pueden repetir (GROQ_API_KEY, GROQ_API_KEYS, GROQ_EXTRA_API_KEYS)")
+		}
+		seenKeys[key] = true
+	}
+	if c.GroqKeyCooldown <= 0 {
+		return fmt.Errorf("GROQ_KEY_COOLDOWN debe ser mayor que 0")
+	}
+	
+	// Sin modelo por defecto, las peticiones sin "model" fallarían
+	if c.DefaultModel == "" {
+		return fmt.Errorf("DEFAULT_MODEL es requerido")
+	}
+	
 	// Verificar que el puerto sea válido
 	if c.Port == "" {
 		return fmt.Errorf("PORT es requerido")
 	}
 	
+	// Sin clave de administración no se pueden crear las API keys
+	if c.APIKeyAuth && c.AdminAPIKey == "" {
+		return fmt.Errorf("API_KEY_AUTH requiere ADMIN_API_KEY")
+	}
+	if c.APIKeyRotationGrace < 0 {
+		return fmt.Errorf("API_KEY_ROTATION_GRACE_HOURS no puede ser negativo")
+	}
+	if c.APIKeyExpiryWarning < 0 {
+		return fmt.Errorf("API_KEY_EXPIRY_WARNING_HOURS no puede ser negativo")
+	}
+	if c.APIKeyWebhookURL != "" {
+		if c.WebhookSecret == "" {
+			return fmt.Errorf("API_KEY_WEBHOOK_URL requiere WEBHOOK_SECRET")
+		}
+		if parsed, err := url.Parse(c.APIKeyWebhookURL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("API_KEY_WEBHOOK_URL debe ser una URL http(s)")
+		}
+	}
+	if c.APITokenSecret != "" {
+		if !c.APIKeyAuth {
+			return fmt.Errorf("API_TOKEN_SECRET requiere API_KEY_AUTH")
+		}
+		// HS256: al menos 256 bits
+		if len(c.APITokenSecret) < 32 {
+			return fmt.Errorf("API_TOKEN_SECRET debe tener al menos 32 caracteres")
+		}
+		if c.APITokenMaxTTL <= 0 {
+			return fmt.Errorf("API_TOKEN_MAX_TTL_MINUTES debe ser mayor a 0")
+		}
+	}
+	
+	// HTTPS: certificado y clave van juntos; mTLS requiere HTTPS y las CAs
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE y TLS_KEY_FILE deben configurarse juntos")
+	}
+	if c.TLSMinVersion != "1.2" && c.TLSMinVersion != "1.3" {
+		return fmt.Errorf("TLS_MIN_VERSION debe ser \"1.2\" o \"1.3\"")
+	}
+	if c.TLSClientAuth != "none" && c.TLSClientAuth != "optional" && c.TLSClientAuth != "require" {
+		return fmt.Errorf("TLS_CLIENT_AUTH debe ser \"none\", \"optional\" o \"require\"")
+	}
+	if c.TLSClientAuth != "none" && c.TLSClientCAFile == "" {
+		return fmt.Errorf("TLS_CLIENT_AUTH=%s requiere TLS_CLIENT_CA_FILE", c.TLSClientAuth)
+	}
+	if c.TLSClientCAFile != "" && !c.TLSEnabled() {
+		return fmt.Errorf("TLS_CLIENT_CA_FILE requiere TLS_CERT_FILE y TLS_KEY_FILE")
+	}
+	
+	// Consumidor por cola: por ahora solo NATS
+	if c.QueueURL != "" {
+		if !strings.HasPrefix(c.QueueURL, "nats://") {
+			return fmt.Errorf("QUEUE_URL debe ser una URL nats:// (solo se admite NATS)")
+		}
+		if c.QueueSubject == "" || c.QueueReplySubject == "" {
+			return fmt.Errorf("QUEUE_SUBJECT y QUEUE_REPLY_SUBJECT no pueden estar vacíos")
+		}
+		if c.QueueConcurrency <= 0 {
+			return fmt.Errorf("QUEUE_CONCURRENCY debe ser mayor a 0")
+		}
+	}
+	
 	// Verificar que el timeout sea positivo
 	if c.HTTPTimeout <= 0 {
 		return fmt.Errorf("HTTP_TIMEOUT debe ser mayor a 0")
 	}
+	if c.MaxRequestTimeout < 0 {
+		return fmt.Errorf("MAX_REQUEST_TIMEOUT debe ser mayor o igual a 0")
+	}
+	
+	// Groq acepta como máximo domain.MaxStopSequences secuencias por petición
+	if len(c.StopSequences) > domain.MaxStopSequences {
+		return fmt.Errorf("STOP_SEQUENCES admite como máximo 4 secuencias")
+	}
+	for model, sequences := range c.ModelStopSequences {
+		if len(sequences) > domain.MaxStopSequences {
+			return fmt.Errorf("MODEL_STOP_SEQUENCES[%s] admite como máximo 4 secuencias", model)
+		}
+	}
+	
+	// El plazo de restauración debe ser positivo
+	if c.ConversationRetention <= 0 {
+		return fmt.Errorf("CONVERSATION_RETENTION_HOURS debe ser mayor a 0")
+	}
+	if c.ConversationMaxTokens < 0 {
+		return fmt.Errorf("CONVERSATION_MAX_TOKENS debe ser mayor o igual a 0")
+	}
+	if c.ConversationMaxCostUSD < 0 {
+		return fmt.Errorf("CONVERSATION_MAX_COST_USD debe ser mayor o igual a 0")
+	}
+	
+	// Chats asíncronos: al menos un worker y sitio en la cola
+	if c.JobWorkers <= 0 {
+		return fmt.Errorf("JOB_WORKERS debe ser mayor a 0")
+	}
+	if c.JobQueueSize <= 0 {
+		return fmt.Errorf("JOB_QUEUE_SIZE debe ser mayor a 0")
+	}
+	if c.JobRetention <= 0 {
+		return fmt.Errorf("JOB_RETENTION_HOURS debe ser mayor a 0")
+	}
+	if c.JobResultDir != "" && c.JobResultSpillBytes <= 0 {
+		return fmt.Errorf("JOB_RESULT_SPILL_BYTES debe ser mayor a 0")
+	}
+	if c.WebhookSecret != "" {
+		if c.WebhookMaxAttempts <= 0 {
+			return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS debe ser mayor a 0")
+		}
+		if c.WebhookBackoff <= 0 {
+			return fmt.Errorf("WEBHOOK_BACKOFF_SECONDS debe ser mayor a 0")
+		}
+		if c.WebhookTimeout <= 0 {
+			return fmt.Errorf("WEBHOOK_TIMEOUT_SECONDS debe ser mayor a 0")
+		}
+	}
+	
+	// Backends de almacenamiento soportados
+	switch c.StorageBackend {
+	case "memory":
+	case "postgres":
+		if c.DatabaseURL == "" {
+			return fmt.Errorf("DATABASE_URL es requerido con STORAGE_BACKEND=postgres")
+		}
+		if c.DatabaseMaxOpenConns < 0 || c.DatabaseMaxIdleConns < 0 || c.DatabaseReadRetries < 0 {
+			return fmt.Errorf("DATABASE_MAX_OPEN_CONNS, DATABASE_MAX_IDLE_CONNS y DATABASE_READ_RETRIES deben ser mayores o iguales a 0")
+		}
+		if c.DatabaseMaxOpenConns > 0 && c.DatabaseMaxIdleConns > c.DatabaseMaxOpenConns {
+			return fmt.Errorf("DATABASE_MAX_IDLE_CONNS no puede superar DATABASE_MAX_OPEN_CONNS")
+		}
+	case "redis":
+		if c.RedisURL == "" {
+			return fmt.Errorf("REDIS_URL es requerido con STORAGE_BACKEND=redis")
+		}
+	default:
+		return fmt.Errorf("STORAGE_BACKEND debe ser \"memory\", \"postgres\" o \"redis\"")
+	}
+	
+	if c.MaxBodyBytes < 0 {
+		return fmt.Errorf("MAX_BODY_BYTES debe ser mayor o igual a 0")
+	}
+	
+	if c.SSERetry < 0 {
+		return fmt.Errorf("SSE_RETRY_MS debe ser mayor o igual a 0")
+	}
+	if c.StreamResumeWindow < 0 {
+		return fmt.Errorf("STREAM_RESUME_SECONDS debe ser mayor o igual a 0")
+	}
+	
+	if c.JSONCodec != "std" && c.JSONCodec != "jsoniter" && c.JSONCodec != "segmentio" {
+		return fmt.Errorf("JSON_CODEC debe ser \"std\", \"jsoniter\" o \"segmentio\"")
+	}
+	
+	// Memoria y GC
+	if c.MemoryLimitMB < 0 {
+		return fmt.Errorf("MEMORY_LIMIT_MB debe ser mayor o igual a 0")
+	}
+	if c.GCPercent < -1 {
+		return fmt.Errorf("GC_PERCENT debe ser mayor o igual a -1")
+	}
+	if c.MemoryShedPercent < 0 || c.MemoryShedPercent > 100 {
+		return fmt.Errorf("MEMORY_SHED_PERCENT debe estar entre 0 y 100")
+	}
+	if c.MemoryShedPercent > 0 && c.MemoryCheckInterval <= 0 {
+		return fmt.Errorf("MEMORY_CHECK_INTERVAL_MS debe ser mayor que 0")
+	}
+	
+	// Watchdog de tareas colgadas
+	if c.WatchdogFactor < 0 {
+		return fmt.Errorf("WATCHDOG_FACTOR debe ser mayor o igual a 0")
+	}
+	if c.WatchdogFactor > 0 && c.WatchdogInterval <= 0 {
+		return fmt.Errorf("WATCHDOG_INTERVAL debe ser mayor que 0")
+	}
+	
+	// Rate limit: el límite no puede ser negativo y la ventana debe ser positiva
+	if c.RateLimitRequests < 0 {
+		return fmt.Errorf("RATE_LIMIT_REQUESTS debe ser mayor o igual a 0")
+	}
+	if c.RateLimitRequests > 0 && c.RateLimitWindow <= 0 {
+		return fmt.Errorf("RATE_LIMIT_WINDOW debe ser mayor a 0")
+	}
+	if c.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("MAX_CONCURRENT_REQUESTS debe ser mayor o igual a 0")
+	}
+	if c.RateLimitMaxWait < 0 {
+		return fmt.Errorf("RATE_LIMIT_MAX_WAIT debe ser mayor o igual a 0")
+	}
+	if c.RateLimitWaitQueue < 0 {
+		return fmt.Errorf("RATE_LIMIT_WAIT_QUEUE debe ser mayor o igual a 0")
+	}
+	if c.BulkheadMaxInFlight < 0 {
+		return fmt.Errorf("BULKHEAD_MAX_IN_FLIGHT debe ser mayor o igual a 0")
+	}
+	if c.BulkheadQueueSize < 0 {
+		return fmt.Errorf("BULKHEAD_QUEUE_SIZE debe ser mayor o igual a 0")
+	}
+	if c.BulkheadMaxWait < 0 {
+		return fmt.Errorf("BULKHEAD_MAX_WAIT_MS debe ser mayor o igual a 0")
+	}
+	
+	// Franjas horarias: bien formadas y en una zona horaria conocida
+	for i, window := range c.ThrottleSchedule {
+		if err := window.Validate(); err != nil {
+			return fmt.Errorf("THROTTLE_SCHEDULE: franja %d: %w", i+1, err)
+		}
+		if window.RateLimitRequests != nil && *window.RateLimitRequests > 0 && c.RateLimitWindow <= 0 {
+			return fmt.Errorf("RATE_LIMIT_WINDOW debe ser mayor a 0")
+		}
+	}
+	if _, err := time.LoadLocation(c.ThrottleTimezone); err != nil {
+		return fmt.Errorf("THROTTLE_TIMEZONE no es una zona horaria válida: %s", c.ThrottleTimezone)
+	}
+	
+	// Cuotas de tokens: ninguna negativa
+	if c.DailyTokenQuota < 0 {
+		return fmt.Errorf("DAILY_TOKEN_QUOTA debe ser mayor o igual a 0")
+	}
+	for client, quota := range c.ClientTokenQuotas {
+		if quota < 0 {
+			return fmt.Errorf("CLIENT_TOKEN_QUOTAS: la cuota de %q debe ser mayor o igual a 0", client)
+		}
+	}
+	
+	// Eventos de consumo: como la cola de chats, solo NATS
+	if c.UsageEventsURL != "" {
+		if !strings.HasPrefix(c.UsageEventsURL, "nats://") {
+			return fmt.Errorf("USAGE_EVENTS_URL debe ser una URL nats:// (solo se admite NATS)")
+		}
+		if c.UsageEventsSubject == "" {
+			return fmt.Errorf("USAGE_EVENTS_SUBJECT no puede estar vacío")
+		}
+	}
+	
+	// Muestreo para reproducción: un porcentaje y un directorio donde guardarlo
+	if c.ReplaySamplePercent < 0 || c.ReplaySamplePercent > 100 {
+		return fmt.Errorf("REPLAY_SAMPLE_PERCENT debe estar entre 0 y 100")
+	}
+	if c.ReplaySamplePercent > 0 && c.ReplayCorpusDir == "" {
+		return fmt.Errorf("REPLAY_CORPUS_DIR es requerido con REPLAY_SAMPLE_PERCENT")
+	}
+	if c.ReplayMaxSamples < 0 {
+		return fmt.Errorf("REPLAY_MAX_SAMPLES debe ser mayor o igual a 0")
+	}
+	
+	// Caché de respuestas: TTL no negativo y hueco para al menos una respuesta
+	if c.ResponseCacheTTL < 0 {
+		return fmt.Errorf("RESPONSE_CACHE_TTL debe ser mayor o igual a 0")
+	}
+	if c.ResponseCacheTTL > 0 && c.ResponseCacheMaxEntries <= 0 {
+		return fmt.Errorf("RESPONSE_CACHE_MAX_ENTRIES debe ser mayor a 0")
+	}
+	
+	// Caché semántica: necesita la de respuestas (comparten el TTL) y un
+	// proveedor con embeddings configurado
+	if c.SemanticCacheThreshold < 0 || c.SemanticCacheThreshold > 1 {
+		return fmt.Errorf("SEMANTIC_CACHE_THRESHOLD debe estar entre 0 y 1")
+	}
+	if c.SemanticCacheThreshold > 0 {
+		if c.ResponseCacheTTL <= 0 {
+			return fmt.Errorf("SEMANTIC_CACHE_THRESHOLD requiere RESPONSE_CACHE_TTL")
+		}
+		switch c.SemanticCacheProvider {
+		case domain.ProviderOpenAI:
+			if c.OpenAIAPIKey == "" && !c.MockMode && !c.Replaying() {
+				return fmt.Errorf("SEMANTIC_CACHE_PROVIDER=openai requiere OPENAI_API_KEY")
+			}
+		case domain.ProviderOllama:
+			if c.OllamaBaseURL == "" && !c.MockMode && !c.Replaying() {
+				return fmt.Errorf("SEMANTIC_CACHE_PROVIDER=ollama requiere OLLAMA_BASE_URL")
+			}
+		default:
+			return fmt.Errorf("SEMANTIC_CACHE_PROVIDER inválido: %q (usa openai u ollama)", c.SemanticCacheProvider)
+		}
+		if c.SemanticCacheMaxEntries <= 0 {
+			return fmt.Errorf("SEMANTIC_CACHE_MAX_ENTRIES debe ser mayor a 0")
+		}
+	}
+	
+	// Caché por ruta: rutas y políticas bien formadas
+	for route, policy := range c.RouteCachePolicies {
+		if _, _, err := domain.ParseRoute(route); err != nil {
+			return fmt.Errorf("ROUTE_CACHE_POLICIES: %w", err)
+		}
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("ROUTE_CACHE_POLICIES: %s: %w", route, err)
+		}
+	}
+	if c.RouteCacheEnabled() && c.RouteCacheMaxEntries <= 0 {
+		return fmt.Errorf("ROUTE_CACHE_MAX_ENTRIES debe ser mayor a 0")
+	}
+	
+	// Idempotencia: lo mismo que la caché de respuestas
+	if c.IdempotencyTTL < 0 {
+		return fmt.Errorf("IDEMPOTENCY_TTL debe ser mayor o igual a 0")
+	}
+	if c.IdempotencyTTL > 0 && c.IdempotencyMaxEntries <= 0 {
+		return fmt.Errorf("IDEMPOTENCY_MAX_ENTRIES debe ser mayor a 0")
+	}
+	
+	// Salud de los modelos: ventana y enfriamiento positivos
+	if c.ModelHealthWindow <= 0 {
+		return fmt.Errorf("MODEL_HEALTH_WINDOW debe ser mayor a 0")
+	}
+	if c.CircuitBreakerFailures < 0 {
+		return fmt.Errorf("CIRCUIT_BREAKER_FAILURES debe ser mayor o igual a 0")
+	}
+	if c.CircuitBreakerFailures > 0 && c.CircuitBreakerCooldown <= 0 {
+		return fmt.Errorf("CIRCUIT_BREAKER_COOLDOWN debe ser mayor a 0")
+	}
+	
+	// Las personas se recargan periódicamente: el intervalo debe ser positivo
+	if c.PromptTemplatesGitURL != "" && c.PromptTemplatesRefresh <= 0 {
+		return fmt.Errorf("PROMPT_TEMPLATES_REFRESH debe ser mayor a 0")
+	}
+	
+	// Precios: no negativos (0 = gratis, ej: un modelo local)
+	for model, price := range c.ModelPricing {
+		if price.Input < 0 || price.Output < 0 {
+			return fmt.Errorf("MODEL_PRICING: el precio de %s debe ser mayor o igual a 0", model)
+		}
+	}
+	
+	// Ventanas de contexto: no negativas, y un modo conocido al superarlas
+	for model, window := range c.ModelContextWindows {
+		if window < 0 {
+			return fmt.Errorf("MODEL_CONTEXT_WINDOWS: la ventana de %s debe ser mayor o igual a 0", model)
+		}
+	}
+	if c.ContextOverflow != "reject" && c.ContextOverflow != "truncate" {
+		return fmt.Errorf("CONTEXT_OVERFLOW debe ser \"reject\" o \"truncate\"")
+	}
+	for i, section := range c.ContextShrinkOrder {
+		if !domain.IsShrinkablePromptSection(section) {
+			return fmt.Errorf("CONTEXT_SHRINK_ORDER: sección desconocida o no recortable: %s", section)
+		}
+		if slices.Contains(c.ContextShrinkOrder[:i], section) {
+			return fmt.Errorf("CONTEXT_SHRINK_ORDER: sección repetida: %s", section)
+		}
+	}
+	if c.HistorySummaryThreshold < 0 || c.HistorySummaryThreshold > 1 {
+		return fmt.Errorf("HISTORY_SUMMARY_THRESHOLD debe estar entre 0 y 1")
+	}
+	if c.HistorySummaryKeep < 0 {
+		return fmt.Errorf("HISTORY_SUMMARY_KEEP debe ser mayor o igual a 0")
+	}
+	
+	// Catálogo de modelos: niveles y capacidades conocidos
+	for model, profile := range c.ModelProfiles {
+		if domain.QualityRank(profile.Tier) < 0 {
+			return fmt.Errorf("MODEL_PROFILES: el tier de %s debe ser fast, balanced o best", model)
+		}
+		for _, capability := range profile.Capabilities {
+			if !slices.Contains(domain.ModelCapabilities, capability) {
+				return fmt.Errorf("MODEL_PROFILES: capacidad desconocida en %s: %s", model, capability)
+			}
+		}
+	}
+	if c.DefaultQuality != "" && domain.QualityRank(c.DefaultQuality) < 0 {
+		return fmt.Errorf("DEFAULT_QUALITY debe ser fast, balanced o best")
+	}
+	
+	// Tipos de datos personales conocidos
+	for _, entityType := range c.PIIRedactionTypes {
+		if !domain.IsPIIType(entityType) {
+			return fmt.Errorf("PII_REDACTION_TYPES: tipo desconocido: %s", entityType)
+		}
+	}
+	
+	// Moderación: el modelo tiene que estar en un proveedor configurado
+	if c.ModerationEnabled() {
+		if _, ok := c.EnabledProviders()[c.ModerationProvider]; !ok {
+			return fmt.Errorf("MODERATION_PROVIDER no está configurado: %s", c.ModerationProvider)
+		}
+		if c.ModerationModel == "" {
+			return fmt.Errorf("MODERATION_MODEL es requerido con MODERATION_INPUT o MODERATION_OUTPUT")
+		}
+	}
+	for _, category := range c.ModerationCategories {
+		if !domain.IsModerationCategory(category) {
+			return fmt.Errorf("MODERATION_CATEGORIES: categoría desconocida: %s", category)
+		}
+	}
+	
+	// Formatos de access log soportados
+	if c.AccessLogFormat != "json" && c.AccessLogFormat != "combined" {
+		return fmt.Errorf("ACCESS_LOG_FORMAT debe ser \"json\" o \"combined\"")
+	}
+	
+	// El contenido de las peticiones nunca se registra en claro
+	if c.LogContent != "none" && c.LogContent != "hash" {
+		return fmt.Errorf("LOG_CONTENT debe ser \"none\" o \"hash\"")
+	}
+	
+	// Los límites de longitud no pueden ser negativos
+	if c.OutputMaxChars < 0 {
+		return fmt.Errorf("OUTPUT_MAX_CHARS debe ser mayor o igual a 0")
+	}
+	for tenantID, limit := range c.TenantOutputMaxChars {
+		if limit < 0 {
+			return fmt.Errorf("TENANT_OUTPUT_MAX_CHARS[%s] debe ser mayor o igual a 0", tenantID)
+		}
+	}
+	
+	for tenantID, limits := range c.TenantModelLimits {
+		if err := limits.Validate(); err != nil {
+			return fmt.Errorf("TENANT_MODEL_LIMITS[%s]: %w", tenantID, err)
+		}
+	}
 	
 	return nil
 }
@@ -118,11 +1362,378 @@ func (c *Config) GetServerAddress() string {
 func (c *Config) Print() {
 	fmt.Println("📋 Configuración cargada:")
 	fmt.Printf("   • Puerto: %s\n", c.Port)
-	fmt.Printf("   • Groq Base URL: %s\n", c.GroqBaseURL)
+	if c.TLSEnabled() {
+		fmt.Printf("   • HTTPS: TLS %s o superior, certificados de cliente: %s\n", c.TLSMinVersion, c.TLSClientAuth)
+	}
+	if c.GRPCPort != "" {
+		fmt.Printf("   • Puerto gRPC: %s\n", c.GRPCPort)
+	}
+	if c.QueueURL != "" {
+		fmt.Printf("   • Cola de chats: %s (grupo %s, %d a la vez)\n", c.QueueSubject, c.QueueGroup, c.QueueConcurrency)
+	}
+	fmt.Printf("   • Proveedor por defecto: %s\n", c.LLMProvider)
+	if c.MockMode {
+		fmt.Println("   • Modo mock: respuestas falsas, sin llamadas al proveedor")
+	}
+	switch c.CassetteMode {
+	case "record":
+		fmt.Printf("   • Grabando las llamadas a los proveedores en %s\n", c.CassetteDir)
+	case "replay":
+		fmt.Printf("   • Reproduciendo las llamadas grabadas en %s, sin llamar a los proveedores\n", c.CassetteDir)
+	}
+	if c.GroqAPIKey != "" {
+		fmt.Printf("   • Groq Base URL: %s\n", c.GroqBaseURL)
+	}
+	if c.OpenAIAPIKey != "" {
+		fmt.Printf("   • OpenAI Base URL: %s\n", c.OpenAIBaseURL)
+	}
+	if c.OllamaBaseURL != "" {
+		fmt.Printf("   • Ollama: %s\n", c.OllamaBaseURL)
+	}
 	fmt.Printf("   • Modelo por defecto: %s\n", c.DefaultModel)
 	fmt.Printf("   • HTTP Timeout: %v\n", c.HTTPTimeout)
+	if c.MaxRequestTimeout > 0 {
+		fmt.Printf("   • X-Request-Timeout: hasta %v\n", c.MaxRequestTimeout)
+	}
+	fmt.Printf("   • Retención de conversaciones borradas: %v\n", c.ConversationRetention)
+	if budget, ok := c.ConversationBudget(); ok {
+		fallback := "se rechazan"
+		if budget.FallbackModel != "" {
+			fallback = "van a " + budget.FallbackModel
+		}
+		fmt.Printf("   • Presupuesto por conversación: %d tokens, %.2f USD (0 = sin límite; al superarlo %s)\n",
+			budget.MaxTotalTokens, budget.MaxCostUSD, fallback)
+	}
+	fmt.Printf("   • Chats asíncronos: %d workers, cola de %d (retención %v)\n",
+		c.JobWorkers, c.JobQueueSize, c.JobRetention)
+	if c.JobResultDir != "" {
+		fmt.Printf("   • Resultados de jobs de %d bytes o más en: %s\n", c.JobResultSpillBytes, c.JobResultDir)
+	}
+	if c.WebhookSecret != "" {
+		fmt.Printf("   • Callbacks de jobs: %d intentos, primer reintento a los %v\n",
+			c.WebhookMaxAttempts, c.WebhookBackoff)
+	}
+	// DATABASE_URL no se imprime: suele llevar la contraseña
+	fmt.Printf("   • Almacenamiento de conversaciones: %s\n", c.StorageBackend)
+	if c.StorageBackend == "postgres" {
+		replica := "no"
+		if c.DatabaseReplicaURL != "" {
+			replica = "sí"
+		}
+		fmt.Printf("   • PostgreSQL: %d conexiones máx. por pool, réplica de lectura: %s, %d reintentos de lectura\n",
+			c.DatabaseMaxOpenConns, replica, c.DatabaseReadRetries)
+	}
+	if c.MaxBodyBytes > 0 {
+		fmt.Printf("   • Tamaño máximo del body: %d bytes\n", c.MaxBodyBytes)
+	}
+	if c.SSERetry > 0 {
+		fmt.Printf("   • Reconexión SSE (retry): %v\n", c.SSERetry)
+	}
+	if c.StreamResumeWindow > 0 {
+		fmt.Printf("   • Flujos reanudables: %v después de terminar\n", c.StreamResumeWindow)
+	}
+	if c.JSONCodec != "std" {
+		fmt.Printf("   • Codec JSON: %s\n", c.JSONCodec)
+	}
+	if c.MemoryLimitMB > 0 {
+		fmt.Printf("   • Límite de memoria: %d MiB\n", c.MemoryLimitMB)
+	}
+	if c.GCPercent != 0 {
+		fmt.Printf("   • GOGC: %d\n", c.GCPercent)
+	}
+	if c.MemoryShedPercent > 0 {
+		fmt.Printf("   • Rechazo de peticiones con el heap al %d%% del límite\n", c.MemoryShedPercent)
+	}
+	if c.WatchdogFactor > 0 {
+		fmt.Printf("   • Watchdog: tareas colgadas a %dx su plazo (revisión cada %v)\n", c.WatchdogFactor, c.WatchdogInterval)
+	}
+	if c.RateLimitRequests > 0 {
+		fmt.Printf("   • Rate limit: %d peticiones cada %v\n", c.RateLimitRequests, c.RateLimitWindow)
+	}
+	if c.MaxConcurrentRequests > 0 {
+		fmt.Printf("   • Peticiones simultáneas por cliente: %d\n", c.MaxConcurrentRequests)
+	}
+	if c.RateLimitMaxWait > 0 && c.RateLimitWaitQueue > 0 {
+		fmt.Printf("   • Espera con wait=true: hasta %v (%d peticiones a la vez)\n",
+			c.RateLimitMaxWait, c.RateLimitWaitQueue)
+	}
+	if c.BulkheadMaxInFlight > 0 {
+		fmt.Printf("   • Bulkhead: %d chats en curso por réplica (cola de %d, hasta %v)\n",
+			c.BulkheadMaxInFlight, c.BulkheadQueueSize, c.BulkheadMaxWait)
+	}
+	if len(c.ThrottleSchedule) > 0 {
+		fmt.Printf("   • Franjas horarias del rate limit: %d (%s)\n", len(c.ThrottleSchedule), c.ThrottleTimezone)
+	}
+	if c.DailyTokenQuota > 0 || len(c.ClientTokenQuotas) > 0 {
+		fmt.Printf("   • Cuota diaria de tokens: %d (%d clientes con cuota propia)\n",
+			c.DailyTokenQuota, len(c.ClientTokenQuotas))
+	}
+	if c.UsageEventsURL != "" {
+		fmt.Printf("   • Eventos de consumo: %s\n", c.UsageEventsSubject)
+	}
+	if c.ReplaySamplePercent > 0 {
+		fmt.Printf("   • Muestreo para reproducción: %g%% de los chats en %s\n", c.ReplaySamplePercent, c.ReplayCorpusDir)
+	}
+	if c.ResponseCacheTTL > 0 {
+		fmt.Printf("   • Caché de respuestas: TTL %v\n", c.ResponseCacheTTL)
+	}
+	if c.SemanticCacheThreshold > 0 {
+		fmt.Printf("   • Caché semántica: similitud %g con %s (%s)\n", c.SemanticCacheThreshold, c.EmbeddingModel(), c.SemanticCacheProvider)
+	}
+	if c.RouteCacheEnabled() {
+		fmt.Printf("   • Caché por ruta: %d políticas\n", len(c.RouteCachePolicies))
+	}
+	if c.IdempotencyTTL > 0 {
+		fmt.Printf("   • Idempotency-Key: respuestas guardadas %v\n", c.IdempotencyTTL)
+	}
+	if c.CircuitBreakerFailures > 0 {
+		fmt.Printf("   • Circuit breaker: %d fallos seguidos (enfriamiento de %v)\n",
+			c.CircuitBreakerFailures, c.CircuitBreakerCooldown)
+	}
+	if c.PluginsDir != "" {
+		fmt.Printf("   • Plugins WASM: %s\n", c.PluginsDir)
+	}
+	if c.DefaultSystemPrompt != "" {
+		fmt.Printf("   • Prompt de sistema por defecto: %d caracteres\n", len(c.DefaultSystemPrompt))
+	}
+	if len(c.StopSequences) > 0 || len(c.ModelStopSequences) > 0 {
+		fmt.Printf("   • Stop sequences: %d globales, %d modelos con stops propios\n",
+			len(c.StopSequences), len(c.ModelStopSequences))
+	}
+	if c.OutputMaxChars > 0 || len(c.TenantOutputMaxChars) > 0 {
+		fmt.Printf("   • Longitud máx. de respuesta: %d caracteres (%d tenants con límite propio)\n",
+			c.OutputMaxChars, len(c.TenantOutputMaxChars))
+	}
+	if len(c.ModelAliases) > 0 {
+		fmt.Printf("   • Alias de modelos retirados: %d\n", len(c.ModelAliases))
+	}
+	if len(c.ModelPricing) > 0 {
+		fmt.Printf("   • Modelos con precio configurado: %d\n", len(c.ModelPricing))
+	}
+	fmt.Printf("   • Ventana de contexto superada: %s (%d modelos conocidos)\n",
+		c.ContextOverflow, len(c.ModelContextWindows))
+	if c.ContextOverflow == "truncate" {
+		fmt.Printf("   • Orden de recorte del prompt: %s\n", strings.Join(c.ContextShrinkOrder, ", "))
+	}
+	if c.HistorySummaryThreshold > 0 {
+		model := c.HistorySummaryModel
+		if model == "" {
+			model = "el de la petición"
+		}
+		fmt.Printf("   • Resumen del historial: al %g%% de la ventana, con %s (últimos %d mensajes intactos)\n",
+			c.HistorySummaryThreshold*100, model, c.HistorySummaryKeep)
+	}
+	if c.DefaultQuality != "" {
+		fmt.Printf("   • Enrutado por coste: calidad %s por defecto (%d modelos en el catálogo)\n",
+			c.DefaultQuality, len(c.ModelProfiles))
+	}
+	if len(c.TenantSystemPrompts) > 0 {
+		fmt.Printf("   • Prompts de sistema por tenant: %d tenants\n", len(c.TenantSystemPrompts))
+	}
+	if len(c.TenantModelLimits) > 0 {
+		fmt.Printf("   • Modelo y parámetros por tenant: %d tenants\n", len(c.TenantModelLimits))
+	}
+	if c.PromptTemplatesGitURL != "" {
+		// La URL puede llevar credenciales: solo se muestra la rama y el intervalo
+		fmt.Printf("   • Personas: repositorio Git (rama %s, recarga cada %v)\n",
+			c.PromptTemplatesGitBranch, c.PromptTemplatesRefresh)
+	}
+	fmt.Printf("   • Access log: %s (contenido: %s)\n", c.AccessLogFormat, c.LogContent)
+	if c.EgressRestrict {
+		fmt.Printf("   • Lista de salida: proveedores y webhooks configurados + %d hosts\n", len(c.EgressAllowedHosts))
+	}
+	if c.WebUI {
+		fmt.Println("   • Interfaz web de chat: /ui")
+	}
+	if c.LanguageDetection {
+		fmt.Printf("   • Detección de idioma: activada (%d perfiles)\n", len(c.LocaleProfiles))
+	}
+	if c.PIIRedaction {
+		fmt.Printf("   • Anonimización antes del proveedor: %s\n", strings.Join(c.PIIRedactionTypes, ", "))
+	}
+	if c.ModerationEnabled() {
+		fmt.Printf("   • Moderación (%s): entrada=%t, salida=%t\n",
+			c.ModerationModel, c.ModerationInput, c.ModerationOutput)
+	}
 	// NO imprimir el API key por seguridad
-	fmt.Printf("   • API Key: %s\n", maskAPIKey(c.GroqAPIKey))
+	if c.GroqAPIKey != "" {
+		fmt.Printf("   • API Key: %s\n", maskAPIKey(c.GroqAPIKey))
+	}
+	if c.OpenAIAPIKey != "" {
+		fmt.Printf("   • OpenAI API Key: %s\n", maskAPIKey(c.OpenAIAPIKey))
+	}
+	if len(c.GroqExtraAPIKeys) > 0 {
+		fmt.Printf("   • API Keys adicionales: %d (enfriamiento tras 429: %v)\n", len(c.GroqExtraAPIKeys), c.GroqKeyCooldown)
+	}
+	if c.AdminAPIKey != "" {
+		fmt.Printf("   • Admin Key: %s\n", maskAPIKey(c.AdminAPIKey))
+	}
+	if c.APIKeyAuth {
+		fmt.Printf("   • API keys de cliente obligatorias (gracia al rotar: %v)\n", c.APIKeyRotationGrace)
+	}
+	if c.APIKeyWebhookURL != "" {
+		fmt.Printf("   • Caducidad de API keys: webhook %s (aviso %v antes)\n", maskURL(c.APIKeyWebhookURL), c.APIKeyExpiryWarning)
+	}
+	if c.APITokenSecret != "" {
+		fmt.Printf("   • Tokens de vida corta: hasta %v\n", c.APITokenMaxTTL)
+	}
+}
+
+// Masked retorna la configuración para el panel de administración
+// Las claves son los nombres de las variables de entorno. Los secretos se
+// enmascaran igual que en Print() y de los prompts por tenant solo se
+// muestran los tenants (las instrucciones son del cliente)
+func (c *Config) Masked() map[string]interface{} {
+	tenants := make([]string, 0, len(c.TenantSystemPrompts))
+	for tenant := range c.TenantSystemPrompts {
+		tenants = append(tenants, tenant)
+	}
+	sort.Strings(tenants)
+	
+	return map[string]interface{}{
+		"PORT":                        c.Port,
+		"GRPC_PORT":                   c.GRPCPort,
+		"QUEUE_URL":                   maskURL(c.QueueURL),
+		"QUEUE_SUBJECT":               c.QueueSubject,
+		"QUEUE_GROUP":                 c.QueueGroup,
+		"QUEUE_REPLY_SUBJECT":         c.QueueReplySubject,
+		"QUEUE_CONCURRENCY":           c.QueueConcurrency,
+		"TLS_CERT_FILE":               c.TLSCertFile,
+		"TLS_KEY_FILE":                c.TLSKeyFile,
+		"TLS_MIN_VERSION":             c.TLSMinVersion,
+		"TLS_CLIENT_CA_FILE":          c.TLSClientCAFile,
+		"TLS_CLIENT_AUTH":             c.TLSClientAuth,
+		"ADMIN_API_KEY":               maskSecret(c.AdminAPIKey),
+		"API_KEY_AUTH":                c.APIKeyAuth,
+		"API_KEY_ROTATION_GRACE":      c.APIKeyRotationGrace.String(),
+		"API_KEY_EXPIRY_WARNING":      c.APIKeyExpiryWarning.String(),
+		"API_KEY_WEBHOOK_URL":         maskURL(c.APIKeyWebhookURL),
+		"API_TOKEN_SECRET":            maskSecret(c.APITokenSecret),
+		"API_TOKEN_MAX_TTL":           c.APITokenMaxTTL.String(),
+		"LLM_PROVIDER":                c.LLMProvider,
+		"MOCK_MODE":                   c.MockMode,
+		"CASSETTE_MODE":               c.CassetteMode,
+		"CASSETTE_DIR":                c.CassetteDir,
+		"GROQ_API_KEY":                maskSecret(c.GroqAPIKey),
+		"GROQ_BASE_URL":               c.GroqBaseURL,
+		"GROQ_EXTRA_API_KEYS":         len(c.GroqExtraAPIKeys),
+		"GROQ_KEY_COOLDOWN":           c.GroqKeyCooldown.String(),
+		"OPENAI_API_KEY":              maskSecret(c.OpenAIAPIKey),
+		"OPENAI_BASE_URL":             c.OpenAIBaseURL,
+		"OLLAMA_BASE_URL":             c.OllamaBaseURL,
+		"PROVIDER_DEFAULT_MODELS":     c.ProviderDefaultModels,
+		"DEFAULT_MODEL":               c.DefaultModel,
+		"HTTP_TIMEOUT":                c.HTTPTimeout.String(),
+		"MAX_REQUEST_TIMEOUT":         c.MaxRequestTimeout.String(),
+		"DEFAULT_SYSTEM_PROMPT":       c.DefaultSystemPrompt,
+		"STOP_SEQUENCES":              c.StopSequences,
+		"MODEL_STOP_SEQUENCES":        c.ModelStopSequences,
+		"OUTPUT_MAX_CHARS":            c.OutputMaxChars,
+		"TENANT_OUTPUT_MAX_CHARS":     c.TenantOutputMaxChars,
+		"TENANT_SYSTEM_PROMPTS":       tenants,
+		"TENANT_MODEL_LIMITS":         c.TenantModelLimits,
+		"MODEL_ALIASES":               c.ModelAliases,
+		"MODEL_PRICING":               c.ModelPricing,
+		"MODEL_CONTEXT_WINDOWS":       c.ModelContextWindows,
+		"CONTEXT_OVERFLOW":            c.ContextOverflow,
+		"CONTEXT_SHRINK_ORDER":        c.ContextShrinkOrder,
+		"HISTORY_SUMMARY_THRESHOLD":   c.HistorySummaryThreshold,
+		"HISTORY_SUMMARY_MODEL":       c.HistorySummaryModel,
+		"HISTORY_SUMMARY_KEEP":        c.HistorySummaryKeep,
+		"MODEL_PROFILES":              c.ModelProfiles,
+		"DEFAULT_QUALITY":             c.DefaultQuality,
+		"CONVERSATION_RETENTION":      c.ConversationRetention.String(),
+		"CONVERSATION_MAX_TOKENS":     c.ConversationMaxTokens,
+		"CONVERSATION_MAX_COST_USD":   c.ConversationMaxCostUSD,
+		"CONVERSATION_FALLBACK_MODEL": c.ConversationFallbackModel,
+		"JOB_WORKERS":                 c.JobWorkers,
+		"JOB_QUEUE_SIZE":              c.JobQueueSize,
+		"JOB_RETENTION":               c.JobRetention.String(),
+		"JOB_RESULT_DIR":              c.JobResultDir,
+		"JOB_RESULT_SPILL_BYTES":      c.JobResultSpillBytes,
+		"WEBHOOK_SECRET":              maskSecret(c.WebhookSecret),
+		"WEBHOOK_MAX_ATTEMPTS":        c.WebhookMaxAttempts,
+		"WEBHOOK_BACKOFF":             c.WebhookBackoff.String(),
+		"WEBHOOK_TIMEOUT":             c.WebhookTimeout.String(),
+		"WEBHOOK_ALLOW_PRIVATE":       c.WebhookAllowPrivate,
+		"EGRESS_RESTRICT":             c.EgressRestrict,
+		"EGRESS_ALLOWED_HOSTS":        c.EgressAllowedHosts,
+		"WEB_UI":                      c.WebUI,
+		"STORAGE_BACKEND":             c.StorageBackend,
+		"DATABASE_URL":                maskURL(c.DatabaseURL),
+		"DATABASE_REPLICA_URL":        maskURL(c.DatabaseReplicaURL),
+		"DATABASE_MAX_OPEN_CONNS":     c.DatabaseMaxOpenConns,
+		"DATABASE_MAX_IDLE_CONNS":     c.DatabaseMaxIdleConns,
+		"DATABASE_CONN_MAX_LIFETIME":  c.DatabaseConnMaxLifetime.String(),
+		"DATABASE_CONN_MAX_IDLE_TIME": c.DatabaseConnMaxIdleTime.String(),
+		"DATABASE_STATEMENT_TIMEOUT":  c.DatabaseStatementTimeout.String(),
+		"DATABASE_READ_RETRIES":       c.DatabaseReadRetries,
+		"REDIS_URL":                   maskURL(c.RedisURL),
+		"REDIS_KEY_PREFIX":            c.RedisKeyPrefix,
+		"MAX_BODY_BYTES":              c.MaxBodyBytes,
+		"SSE_RETRY":                   c.SSERetry.String(),
+		"STREAM_RESUME_WINDOW":        c.StreamResumeWindow.String(),
+		"JSON_CODEC":                  c.JSONCodec,
+		"MEMORY_LIMIT_MB":             c.MemoryLimitMB,
+		"GC_PERCENT":                  c.GCPercent,
+		"MEMORY_SHED_PERCENT":         c.MemoryShedPercent,
+		"MEMORY_CHECK_INTERVAL_MS":    c.MemoryCheckInterval.Milliseconds(),
+		"WATCHDOG_FACTOR":             c.WatchdogFactor,
+		"WATCHDOG_INTERVAL":           c.WatchdogInterval.String(),
+		"RATE_LIMIT_REQUESTS":         c.RateLimitRequests,
+		"RATE_LIMIT_WINDOW":           c.RateLimitWindow.String(),
+		"MAX_CONCURRENT_REQUESTS":     c.MaxConcurrentRequests,
+		"THROTTLE_SCHEDULE":           c.ThrottleSchedule,
+		"THROTTLE_TIMEZONE":           c.ThrottleTimezone,
+		"RATE_LIMIT_MAX_WAIT":         c.RateLimitMaxWait.String(),
+		"RATE_LIMIT_WAIT_QUEUE":       c.RateLimitWaitQueue,
+		"BULKHEAD_MAX_IN_FLIGHT":      c.BulkheadMaxInFlight,
+		"BULKHEAD_QUEUE_SIZE":         c.BulkheadQueueSize,
+		"BULKHEAD_MAX_WAIT_MS":        c.BulkheadMaxWait.Milliseconds(),
+		"DAILY_TOKEN_QUOTA":           c.DailyTokenQuota,
+		"CLIENT_TOKEN_QUOTAS":         c.ClientTokenQuotas,
+		"USAGE_EVENTS_URL":            maskURL(c.UsageEventsURL),
+		"USAGE_EVENTS_SUBJECT":        c.UsageEventsSubject,
+		"REPLAY_SAMPLE_PERCENT":       c.ReplaySamplePercent,
+		"REPLAY_CORPUS_DIR":           c.ReplayCorpusDir,
+		"REPLAY_MAX_SAMPLES":          c.ReplayMaxSamples,
+		"RESPONSE_CACHE_TTL":          c.ResponseCacheTTL.String(),
+		"RESPONSE_CACHE_MAX_ENTRIES":  c.ResponseCacheMaxEntries,
+		"SEMANTIC_CACHE_THRESHOLD":    c.SemanticCacheThreshold,
+		"SEMANTIC_CACHE_PROVIDER":     c.SemanticCacheProvider,
+		"SEMANTIC_CACHE_MODEL":        c.EmbeddingModel(),
+		"SEMANTIC_CACHE_MAX_ENTRIES":  c.SemanticCacheMaxEntries,
+		"ROUTE_CACHE_POLICIES":        c.RouteCachePolicies,
+		"ROUTE_CACHE_MAX_ENTRIES":     c.RouteCacheMaxEntries,
+		"IDEMPOTENCY_TTL":             c.IdempotencyTTL.String(),
+		"IDEMPOTENCY_MAX_ENTRIES":     c.IdempotencyMaxEntries,
+		"MODEL_HEALTH_WINDOW":         c.ModelHealthWindow.String(),
+		"CIRCUIT_BREAKER_FAILURES":    c.CircuitBreakerFailures,
+		"CIRCUIT_BREAKER_COOLDOWN":    c.CircuitBreakerCooldown.String(),
+		"PLUGINS_DIR":                 c.PluginsDir,
+		"PROMPT_OPTIMIZER_MODEL":      c.PromptOptimizerModel,
+		"CODE_MODEL":                  c.CodeModel,
+		"GROUNDING_MODEL":             c.GroundingModel,
+		"TRANSCRIPTION_MODEL":         c.TranscriptionModel,
+		"PROMPT_TEMPLATES_GIT_URL":    maskURL(c.PromptTemplatesGitURL),
+		"PROMPT_TEMPLATES_GIT_BRANCH": c.PromptTemplatesGitBranch,
+		"PROMPT_TEMPLATES_DIR":        c.PromptTemplatesDir,
+		"PROMPT_TEMPLATES_REFRESH":    c.PromptTemplatesRefresh.String(),
+		"ACCESS_LOG_FORMAT":           c.AccessLogFormat,
+		"ACCESS_LOG_FIELDS":           c.AccessLogFields,
+		"LOG_CONTENT":                 c.LogContent,
+		"LOG_REDACT_HEADERS":          c.LogRedactHeaders,
+		"LANGUAGE_DETECTION":          c.LanguageDetection,
+		"LOCALE_PROFILES":             c.LocaleProfiles,
+		"PII_REDACTION":               c.PIIRedaction,
+		"PII_REDACTION_TYPES":         c.PIIRedactionTypes,
+		"MODERATION_INPUT":            c.ModerationInput,
+		"MODERATION_OUTPUT":           c.ModerationOutput,
+		"MODERATION_MODEL":            c.ModerationModel,
+		"MODERATION_PROVIDER":         c.ModerationProvider,
+		"MODERATION_CATEGORIES":       c.ModerationCategories,
+		"MODERATION_FAIL_OPEN":        c.ModerationFailOpen,
+	}
 }
 
 // ============================================================================
@@ -163,6 +1774,37 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsFloat obtiene una variable de entorno como float64
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	
+	value, err := strconv.ParseFloat(valueStr, 64)
+	if err != nil {
+		return defaultValue
+	}
+	
+	return value
+}
+
+// getEnvAsBool obtiene una variable de entorno como bool
+// Acepta los valores de strconv.ParseBool: "true", "1", "false", "0"...
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	
+	return value
+}
+
 // getEnvAsDuration obtiene una variable de entorno como time.Duration
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	valueStr := os.Getenv(key)
@@ -180,6 +1822,38 @@ func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	return time.Duration(seconds) * time.Second
 }
 
+// getEnvAsList obtiene una variable de entorno separada por comas como slice
+// Los elementos vacíos se descartan: "a,,b" → ["a", "b"]
+func getEnvAsList(key string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return nil
+	}
+	
+	var values []string
+	// strings.Split() divide el string por el separador
+	for _, item := range strings.Split(valueStr, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// getEnvAsJSON decodifica una variable de entorno JSON en target
+// Si la variable no existe, target no se modifica
+func getEnvAsJSON(key string, target interface{}) error {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return nil
+	}
+	
+	if err := json.Unmarshal([]byte(valueStr), target); err != nil {
+		return fmt.Errorf("%s no es un JSON válido: %w", key, err)
+	}
+	return nil
+}
+
 // maskAPIKey oculta parcialmente el API key para logs
 // Muestra solo los primeros y últimos caracteres
 func maskAPIKey(key string) string {
@@ -192,6 +1866,33 @@ func maskAPIKey(key string) string {
 	return key[:4] + "..." + key[len(key)-4:]
 }
 
+// maskSecret enmascara un secreto opcional ("" si no está configurado)
+func maskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return maskAPIKey(secret)
+}
+
+// maskURL oculta las credenciales de una URL (usuario y contraseña)
+// Ej: postgres://app:secreto@db:5432/groq → postgres://***@db:5432/groq
+func maskURL(rawURL string) string {
+	if rawURL == "" {
+		return ""
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		// Si no se puede parsear, no se sabe dónde están las credenciales
+		return "***"
+	}
+	if parsed.User == nil {
+		return rawURL
+	}
+	// url.User("***") escaparía los asteriscos: se insertan a mano
+	parsed.User = nil
+	return strings.Replace(parsed.String(), "//", "//***@", 1)
+}
+
 // ============================================================================
 // CONCEPTOS CLAVE DE GO EXPLICADOS:
 // ============================================================================
@@ -250,6 +1951,8 @@ func maskAPIKey(key string) string {
 // GROQ_BASE_URL=https://api.groq.com/openai/v1
 // DEFAULT_MODEL=llama-3.3-70b-versatile
 // HTTP_TIMEOUT=30
+// STOP_SEQUENCES=###,<|fin|>
+// MODEL_STOP_SEQUENCES={"llama-3.3-70b-versatile": ["\n\nUsuario:"]}
 //
 // ============================================================================
 
@@ -0,0 +1,214 @@
+// Package domain - API keys de los clientes
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// ============================================================================
+// API KEYS DE CLIENTE
+// ============================================================================
+//
+// Cada consumidor de la API tiene su propia clave, que se crea, desactiva y
+// rota con la API de administración (sin tocar la configuración). La clave
+// solo se muestra al crearla o rotarla: se guarda su SHA-256, y Prefix (los
+// primeros caracteres) sirve para reconocerla en los listados.
+//
+// Los scopes limitan a qué partes de la API accede la clave (sin scopes, a
+// todas). Tier es el plan del cliente (ej: "free", "pro"): viaja en el
+// contexto con la clave para que otras políticas lo lean.
+//
+// Tenant ata la clave a un tenant: con autenticación, es el tenant de todas
+// sus peticiones (prompt del tenant, límites, consumo...) y X-Tenant-ID no
+// puede elegir otro. Una clave sin tenant usa DefaultTenant.
+//
+// Al rotar, la clave anterior sigue valiendo hasta PreviousExpiresAt, para
+// que el cliente cambie la suya sin cortes.
+//
+// Cuando una clave está cerca de caducar y cuando caduca se emite un
+// APIKeyEvent (una vez cada uno): el operador la rota o crea otra a tiempo.
+// ============================================================================
+
+// Scopes de las API keys
+const (
+	// ScopeChat: /chat, /models y /diff
+	ScopeChat = "chat"
+
+	// ScopeJobs: /chat/async y /jobs
+	ScopeJobs = "jobs"
+
+	// ScopeConversations: /conversations
+	ScopeConversations = "conversations"
+
+	// ScopeTools: /prompts, /classify, /redact, /nl2sql y /code
+	ScopeTools = "tools"
+
+	// ScopeProxy: /proxy
+	ScopeProxy = "proxy"
+
+	// ScopeAudio: /audio
+	ScopeAudio = "audio"
+)
+
+// APIKeyScopes son los scopes admitidos
+var APIKeyScopes = []string{ScopeChat, ScopeJobs, ScopeConversations, ScopeTools, ScopeProxy, ScopeAudio}
+
+// IsAPIKeyScope indica si el scope es uno de APIKeyScopes
+func IsAPIKeyScope(scope string) bool {
+	return slices.Contains(APIKeyScopes, scope)
+}
+
+var (
+	// ErrAPIKeyNotFound se retorna cuando la clave no existe
+	ErrAPIKeyNotFound = errors.New("API key no encontrada")
+
+	// ErrInvalidAPIKey se retorna cuando la petición no trae una clave
+	// válida (no existe, está desactivada o ha caducado)
+	ErrInvalidAPIKey = errors.New("API key ausente o inválida")
+
+	// ErrInsufficientScope se retorna cuando la clave no tiene el scope
+	// del endpoint
+	ErrInsufficientScope = errors.New("la API key no tiene permiso para este endpoint")
+
+	// ErrInvalidAPIKeySpec se retorna cuando los datos de una clave nueva
+	// no son válidos
+	ErrInvalidAPIKeySpec = errors.New("datos de la API key inválidos")
+
+	// ErrAPIKeyDisabled se retorna al rotar una clave desactivada
+	ErrAPIKeyDisabled = errors.New("la API key está desactivada")
+
+	// ErrTenantMismatch se retorna cuando la petición pide un tenant
+	// distinto del de su API key
+	ErrTenantMismatch = errors.New("el tenant no coincide con el de la API key")
+)
+
+// Eventos de caducidad de las API keys
+const (
+	// APIKeyEventExpiring: la clave caduca dentro del plazo de aviso
+	APIKeyEventExpiring = "api_key.expiring"
+
+	// APIKeyEventExpired: la clave ha caducado
+	APIKeyEventExpired = "api_key.expired"
+)
+
+// APIKey es la clave de un cliente (sin el secreto)
+type APIKey struct {
+	// ID identifica la clave en la API de administración
+	ID string `json:"id"`
+
+	// Name describe al cliente (ej: "app-movil")
+	Name string `json:"name"`
+
+	// Prefix son los primeros caracteres del secreto, para reconocerlo
+	Prefix string `json:"prefix"`
+
+	// Hash es el SHA-256 del secreto en hex
+	Hash string `json:"hash"`
+
+	// Scopes son las partes de la API a las que accede (vacío = todas)
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Tier es el plan del cliente (opcional)
+	Tier string `json:"tier,omitempty"`
+
+	// Tenant es el tenant de sus peticiones (vacío = DefaultTenant)
+	Tenant string `json:"tenant,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// ExpiresAt es cuándo deja de valer (nil = nunca)
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// DisabledAt es cuándo se desactivó (nil = activa)
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+
+	// RotatedAt es la última rotación; PreviousHash, el secreto anterior,
+	// que vale hasta PreviousExpiresAt
+	RotatedAt         *time.Time `json:"rotated_at,omitempty"`
+	PreviousHash      string     `json:"previous_hash,omitempty"`
+	PreviousExpiresAt *time.Time `json:"previous_expires_at,omitempty"`
+
+	// ExpiringNotifiedAt y ExpiredNotifiedAt son cuándo se emitieron sus
+	// eventos de caducidad (nil = todavía no)
+	ExpiringNotifiedAt *time.Time `json:"expiring_notified_at,omitempty"`
+	ExpiredNotifiedAt  *time.Time `json:"expired_notified_at,omitempty"`
+
+	// UsedPreviousSecret indica que la petición se autenticó con el secreto
+	// anterior a la rotación (solo en la clave de la petición, no se guarda)
+	UsedPreviousSecret bool `json:"-"`
+
+	// TokenID es el token de vida corta con el que se autenticó la petición
+	// (vacío = con el secreto); Scopes son entonces los del token. Solo en
+	// la clave de la petición, no se guarda
+	TokenID string `json:"-"`
+}
+
+// Active indica si la clave vale en el momento indicado
+func (k *APIKey) Active(now time.Time) bool {
+	return k.DisabledAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// SecretExpiresAt es cuándo deja de valer el secreto con el que se
+// autenticó la petición (nil = nunca): con el secreto anterior, lo que antes
+// llegue de ExpiresAt y PreviousExpiresAt
+func (k *APIKey) SecretExpiresAt() *time.Time {
+	if !k.UsedPreviousSecret || k.PreviousExpiresAt == nil {
+		return k.ExpiresAt
+	}
+	if k.ExpiresAt != nil && k.ExpiresAt.Before(*k.PreviousExpiresAt) {
+		return k.ExpiresAt
+	}
+	return k.PreviousExpiresAt
+}
+
+// TenantID es el tenant de las peticiones de la clave
+func (k *APIKey) TenantID() string {
+	if k.Tenant == "" {
+		return DefaultTenant
+	}
+	return k.Tenant
+}
+
+// Allows indica si la clave tiene el scope
+func (k *APIKey) Allows(scope string) bool {
+	return len(k.Scopes) == 0 || slices.Contains(k.Scopes, scope)
+}
+
+// APIKeySpec son los datos de una clave nueva
+type APIKeySpec struct {
+	Name   string
+	Scopes []string
+	Tier   string
+	Tenant string
+
+	// ExpiresAt es cuándo deja de valer (nil = nunca)
+	ExpiresAt *time.Time
+}
+
+// APIKeyEvent es un aviso de caducidad de una clave
+type APIKeyEvent struct {
+	// Type es APIKeyEventExpiring o APIKeyEventExpired
+	Type string
+
+	Key APIKey
+
+	// At es cuándo se detectó
+	At time.Time
+}
+
+// apiKeyKey es la clave privada para guardar la API key en el contexto
+type apiKeyKey struct{}
+
+// WithAPIKey retorna un contexto derivado con la clave de la petición
+func WithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(ctx, apiKeyKey{}, key)
+}
+
+// APIKeyFromContext obtiene la clave de la petición (nil si no hay)
+func APIKeyFromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyKey{}).(*APIKey)
+	return key
+}
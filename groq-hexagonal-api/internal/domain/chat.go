@@ -2,7 +2,10 @@
 // Esta es la CAPA MÁS IMPORTANTE - no depende de nada externo
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // ============================================================================
 // ENTIDADES DEL DOMINIO
@@ -11,10 +14,20 @@ import "time"
 // ChatMessage representa un mensaje en una conversación
 // En Go, los structs son como clases pero sin herencia
 type ChatMessage struct {
-	// Role puede ser: "system", "user", o "assistant"
+	// Role puede ser: "system", "user", "assistant" o "tool"
 	// La etiqueta `json:"role"` indica cómo se serializa a JSON
 	Role    string `json:"role"`
 	Content string `json:"content"`
+	
+	// ToolCalls son las herramientas que el asistente pide invocar
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	
+	// ToolCallID indica a qué llamada responde un mensaje con role "tool"
+	ToolCallID string `json:"tool_call_id,omitempty"`
+	
+	// Images son las imágenes adjuntas al mensaje (ver vision.go)
+	// Con imágenes, "content" se serializa como una lista de partes
+	Images []ImageURL `json:"-"`
 }
 
 // ChatRequest representa una solicitud de chat completa
@@ -34,6 +47,133 @@ type ChatRequest struct {
 	// Máximo de tokens a generar
 	// omitempty significa que si es 0, no se incluye en el JSON
 	MaxTokens int `json:"max_tokens,omitempty"`
+	
+	// Secuencias de parada: el modelo deja de generar al encontrar alguna
+	// Groq acepta como máximo MaxStopSequences elementos
+	Stop []string `json:"stop,omitempty"`
+	
+	// Parámetros de muestreo (nil = default del modelo, ver SamplingOptions)
+	TopP             *float64 `json:"top_p,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+	
+	// N es el número de respuestas alternativas a generar (0 = una)
+	// Groq solo admite 1; OpenAI admite varias
+	N int `json:"n,omitempty"`
+	
+	// Stream pide a Groq que envíe la respuesta por partes (Server-Sent Events)
+	Stream bool `json:"stream,omitempty"`
+	
+	// ResponseFormat fuerza el formato de salida (ej: JSON válido)
+	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
+	
+	// Tools son las herramientas que el modelo puede invocar
+	Tools []Tool `json:"tools,omitempty"`
+	
+	// ToolChoice controla si el modelo usa herramientas:
+	// "auto", "none", "required" o {"type": "function", "function": {"name": ...}}
+	// Puede ser string u objeto, así que se guarda como JSON sin interpretar
+	ToolChoice json.RawMessage `json:"tool_choice,omitempty"`
+}
+
+// ResponseFormat indica a Groq el formato de la respuesta
+type ResponseFormat struct {
+	// Type puede ser "text" o "json_object"
+	Type string `json:"type"`
+}
+
+// JSONResponseFormat pide al modelo que responda con un objeto JSON válido
+// Nota: Groq exige que el prompt mencione la palabra "JSON"
+var JSONResponseFormat = &ResponseFormat{Type: "json_object"}
+
+// MaxStopSequences es el número máximo de secuencias de parada que acepta Groq
+const MaxStopSequences = 4
+
+// Rangos válidos de los parámetros de muestreo (los de la API de OpenAI)
+const (
+	MinPenalty = -2.0
+	MaxPenalty = 2.0
+	MaxChoices = 128
+)
+
+// SamplingOptions son los parámetros de muestreo avanzados de una petición
+// Los punteros nil (y N = 0) dejan el valor por defecto del modelo
+type SamplingOptions struct {
+	// TopP limita el muestreo a los tokens que suman esa probabilidad (0-1)
+	TopP *float64
+	
+	// FrequencyPenalty y PresencePenalty penalizan la repetición (-2 a 2)
+	FrequencyPenalty *float64
+	PresencePenalty  *float64
+	
+	// Seed hace el muestreo reproducible (en lo posible) entre peticiones
+	Seed *int64
+	
+	// N es el número de respuestas alternativas (1-MaxChoices)
+	N int
+}
+
+// ApplyTo copia los parámetros a la petición
+func (o SamplingOptions) ApplyTo(request *ChatRequest) {
+	request.TopP = o.TopP
+	request.FrequencyPenalty = o.FrequencyPenalty
+	request.PresencePenalty = o.PresencePenalty
+	request.Seed = o.Seed
+	request.N = o.N
+}
+
+// MessageOptions agrupa los parámetros opcionales de una petición de chat
+// Permite añadir nuevos parámetros sin cambiar la firma de SendMessage
+type MessageOptions struct {
+	// Temperature controla la creatividad (nil = default del modelo)
+	Temperature *float64
+	
+	// MaxTokens limita la longitud de la respuesta (0 = sin límite explícito)
+	MaxTokens int
+	
+	// Stop son las secuencias de parada enviadas por el cliente
+	Stop []string
+	
+	// Sampling son los parámetros de muestreo avanzados (top_p, seed...)
+	Sampling SamplingOptions
+	
+	// SystemPrompt son instrucciones de sistema para el modelo (opcional)
+	// Si está vacío, se usa el prompt de sistema por defecto del servicio
+	SystemPrompt string
+	
+	// Persona es el nombre de una plantilla de prompt (opcional)
+	// SystemPrompt, el modelo y Temperature tienen prioridad sobre los suyos
+	Persona string
+	
+	// Provider es el proveedor de modelos (vacío = el por defecto, ver provider.go)
+	Provider string
+	
+	// Quality pide el modelo más barato de un nivel en lugar de un modelo
+	// concreto (vacío = sin enrutado por coste, ver routing.go)
+	Quality string
+	
+	// Tools y ToolChoice se reenvían tal cual a Groq (ver tools.go)
+	Tools      []Tool
+	ToolChoice json.RawMessage
+	
+	// History son los mensajes anteriores de la conversación (opcional)
+	// Se envían al modelo antes del mensaje actual
+	History []ChatMessage
+	
+	// Images se adjuntan al mensaje actual (requiere un modelo con visión)
+	Images []ImageURL
+	
+	// Examples son ejemplos few-shot para el modelo (opcional)
+	Examples []FewShotExample
+	
+	// Documents son documentos de contexto para la respuesta, por ejemplo
+	// los recuperados en un RAG (opcional, ver prompt_sections.go)
+	Documents []string
+	
+	// Grounding activa la verificación de la respuesta (nil = sin verificar)
+	// Ver grounding.go
+	Grounding *GroundingOptions
 }
 
 // ChatResponse representa la respuesta de la API de Groq
@@ -55,6 +195,51 @@ type ChatResponse struct {
 	
 	// Información de uso de tokens
 	Usage Usage `json:"usage"`
+	
+	// Meta contiene información añadida por la aplicación (no viene de Groq)
+	// json:"-" hace que el campo se ignore al serializar/deserializar
+	Meta ResponseMeta `json:"-"`
+}
+
+// ResponseMeta son metadatos que la aplicación añade a una respuesta
+type ResponseMeta struct {
+	// TruncatedByPolicy indica que la respuesta se recortó por el límite
+	// de longitud del tenant
+	TruncatedByPolicy bool
+	
+	// DetectedLanguage es el idioma detectado del mensaje (ISO 639-1)
+	// Vacío si la detección está desactivada o no fue concluyente
+	DetectedLanguage string
+	
+	// RemappedFrom es el modelo pedido si estaba retirado y se usó su reemplazo
+	RemappedFrom string
+	
+	// CacheHit indica que la respuesta se sirvió desde la caché
+	CacheHit bool
+	
+	// CacheStatus es el resultado de la consulta a la caché ("" = sin caché)
+	CacheStatus CacheStatus
+	
+	// CacheAge es la edad de la respuesta servida desde la caché
+	CacheAge time.Duration
+	
+	// CacheSimilarity es la similitud con la pregunta de la respuesta servida
+	// por la caché semántica (0 = no vino de ella)
+	CacheSimilarity float64
+	
+	// Warnings son los avisos para el cliente (ver warning.go)
+	Warnings []Warning
+	
+	// Grounding es el resultado de la verificación (nil si no se pidió o falló)
+	Grounding *Grounding
+	
+	// HistorySummary es el resumen que sustituyó al historial más antiguo
+	// (nil si no hizo falta resumir, ver history_summary.go)
+	HistorySummary *HistorySummary
+	
+	// RoutedQuality es el nivel con el que se eligió el modelo por coste
+	// Vacío si el modelo no se eligió por coste
+	RoutedQuality string
 }
 
 // Choice representa una opción de respuesta del modelo
@@ -74,6 +259,57 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`      // Tokens del input
 	CompletionTokens int `json:"completion_tokens"`  // Tokens del output
 	TotalTokens      int `json:"total_tokens"`       // Total
+	
+	// CostUSD es el coste estimado con los precios configurados (no viene
+	// del proveedor; 0 si el modelo no tiene precio)
+	CostUSD float64 `json:"cost_usd,omitempty"`
+}
+
+// ============================================================================
+// STREAMING
+// ============================================================================
+
+// ChatStreamChunk es un fragmento de una respuesta en streaming
+// Groq envía muchos fragmentos; cada uno trae un trozo del texto en Delta
+type ChatStreamChunk struct {
+	ID      string         `json:"id"`
+	Object  string         `json:"object"` // Siempre "chat.completion.chunk"
+	Created int64          `json:"created"`
+	Model   string         `json:"model"`
+	Choices []StreamChoice `json:"choices"`
+	
+	// XGroq contiene extensiones de Groq; el último fragmento trae el uso de tokens
+	XGroq *XGroq `json:"x_groq,omitempty"`
+	
+	// Usage es el uso de tokens en el formato de OpenAI (último fragmento)
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// StreamChoice es la opción de respuesta dentro de un fragmento
+type StreamChoice struct {
+	Index int `json:"index"`
+	
+	// Delta contiene solo el texto NUEVO de este fragmento
+	Delta ChatMessage `json:"delta"`
+	
+	// FinishReason llega vacío hasta el último fragmento
+	FinishReason string `json:"finish_reason"`
+}
+
+// XGroq son los campos propios de Groq en los fragmentos de streaming
+type XGroq struct {
+	ID    string `json:"id"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// ChatStream es un flujo de fragmentos de respuesta
+// Se consume llamando a Recv() hasta que retorne io.EOF
+type ChatStream interface {
+	// Recv retorna el siguiente fragmento, o io.EOF cuando el flujo termina
+	Recv() (*ChatStreamChunk, error)
+	
+	// Close libera la conexión subyacente (llamar siempre, con defer)
+	Close() error
 }
 
 // Model representa un modelo de IA disponible
@@ -131,6 +367,14 @@ func (c *ChatRequest) SetMaxTokens(max int) {
 	c.MaxTokens = max
 }
 
+// TotalUsage es el consumo de la respuesta más el de su verificación
+func (c *ChatResponse) TotalUsage() Usage {
+	if c.Meta.Grounding == nil {
+		return c.Usage
+	}
+	return c.Usage.Add(c.Meta.Grounding.Usage)
+}
+
 // GetResponseContent extrae el contenido de la primera respuesta
 func (c *ChatResponse) GetResponseContent() string {
 	// Verificar que hay al menos una opción
@@ -140,6 +384,64 @@ func (c *ChatResponse) GetResponseContent() string {
 	return ""
 }
 
+// SetResponseContent reemplaza el contenido de la primera respuesta
+func (c *ChatResponse) SetResponseContent(content string) {
+	if len(c.Choices) > 0 {
+		c.Choices[0].Message.Content = content
+	}
+}
+
+// GetToolCalls retorna las herramientas pedidas en la primera respuesta
+func (c *ChatResponse) GetToolCalls() []ToolCall {
+	if len(c.Choices) > 0 {
+		return c.Choices[0].Message.ToolCalls
+	}
+	return nil
+}
+
+// GetFinishReason retorna la razón de fin de la primera respuesta
+func (c *ChatResponse) GetFinishReason() string {
+	if len(c.Choices) > 0 {
+		return c.Choices[0].FinishReason
+	}
+	return ""
+}
+
+// GetDeltaContent extrae el texto nuevo del fragmento
+func (c *ChatStreamChunk) GetDeltaContent() string {
+	if len(c.Choices) > 0 {
+		return c.Choices[0].Delta.Content
+	}
+	return ""
+}
+
+// GetDeltaToolCalls extrae los fragmentos de llamadas a herramientas
+func (c *ChatStreamChunk) GetDeltaToolCalls() []ToolCall {
+	if len(c.Choices) > 0 {
+		return c.Choices[0].Delta.ToolCalls
+	}
+	return nil
+}
+
+// GetFinishReason retorna la razón de fin (vacía si el flujo continúa)
+func (c *ChatStreamChunk) GetFinishReason() string {
+	if len(c.Choices) > 0 {
+		return c.Choices[0].FinishReason
+	}
+	return ""
+}
+
+// GetUsage retorna el uso de tokens si el fragmento lo incluye (o nil)
+func (c *ChatStreamChunk) GetUsage() *Usage {
+	if c.Usage != nil {
+		return c.Usage
+	}
+	if c.XGroq != nil {
+		return c.XGroq.Usage
+	}
+	return nil
+}
+
 // IsComplete verifica si la respuesta está completa
 func (c *ChatResponse) IsComplete() bool {
 	// Retorna true si hay opciones y la primera terminó con "stop"
@@ -0,0 +1,58 @@
+// Package domain - Clasificación de textos
+package domain
+
+// ============================================================================
+// ENTIDADES DE CLASIFICACIÓN
+// ============================================================================
+
+// Límites de una petición de clasificación
+const (
+	// MinClassificationLabels: con una sola etiqueta no hay nada que elegir
+	MinClassificationLabels = 2
+
+	// MaxClassificationLabels mantiene el prompt de sistema acotado
+	MaxClassificationLabels = 50
+
+	// MaxLabelExamples es el máximo de ejemplos (few-shot) por etiqueta
+	MaxLabelExamples = 5
+)
+
+// ClassificationLabel es una de las categorías entre las que elegir
+type ClassificationLabel struct {
+	// Name es el identificador que se retorna (ej: "facturación")
+	Name string
+
+	// Description aclara cuándo aplica la etiqueta (opcional)
+	Description string
+
+	// Examples son textos de ejemplo de esta etiqueta (few-shot, opcional)
+	Examples []string
+}
+
+// ClassificationRequest es la entrada del caso de uso de clasificar un texto
+type ClassificationRequest struct {
+	// Text es el texto a clasificar
+	Text string
+
+	// Labels son las categorías posibles (el modelo elige exactamente una)
+	Labels []ClassificationLabel
+
+	// Model es el modelo a usar (vacío = modelo por defecto)
+	Model string
+}
+
+// Classification es la etiqueta elegida por el modelo
+type Classification struct {
+	// Label es el Name de la etiqueta elegida, tal como se definió
+	Label string `json:"label"`
+
+	// Confidence es la confianza del modelo en la etiqueta, de 0 a 1
+	// La estima el propio modelo: sirve para ordenar, no es una probabilidad
+	Confidence float64 `json:"confidence"`
+
+	// Model es el modelo que clasificó el texto
+	Model string `json:"-"`
+
+	// Usage es el consumo de tokens de la petición
+	Usage Usage `json:"-"`
+}
@@ -0,0 +1,109 @@
+// Package domain - Explicación y revisión de código
+package domain
+
+// ============================================================================
+// ENTIDADES DE CÓDIGO
+// ============================================================================
+//
+// El código se envía al modelo con los números de línea delante, así las
+// secciones y los hallazgos apuntan a líneas del fichero original. Los
+// ficheros largos se parten en fragmentos (por líneas, preferiblemente en
+// líneas en blanco) que se procesan por separado; con varios fragmentos, una
+// última llamada resume el conjunto.
+// ============================================================================
+
+// MaxCodeChars limita el código de una petición
+const MaxCodeChars = 200000
+
+// Severidades de los hallazgos de una revisión, de mayor a menor
+const (
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityInfo     = "info"
+)
+
+// FindingSeverities son las severidades admitidas, de mayor a menor
+var FindingSeverities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
+// FindingCategories son las categorías de los hallazgos
+var FindingCategories = []string{"bug", "security", "performance", "maintainability", "style"}
+
+// CodeRequest es la entrada de los casos de uso de explicar y revisar código
+type CodeRequest struct {
+	// Code es el contenido del fichero
+	Code string
+
+	// Filename ayuda a detectar el lenguaje (opcional, ej: "main.go")
+	Filename string
+
+	// Language fuerza el lenguaje (vacío = se detecta)
+	Language string
+
+	// Instructions es una pregunta sobre el código (explicar) o en qué
+	// fijarse (revisar). Opcional
+	Instructions string
+
+	// Model es el modelo a usar (vacío = el modelo de código por defecto)
+	Model string
+}
+
+// CodeSection es la explicación de un rango de líneas
+type CodeSection struct {
+	StartLine   int
+	EndLine     int
+	Explanation string
+}
+
+// CodeExplanation es el resultado de explicar un fichero
+type CodeExplanation struct {
+	// Language es el lenguaje usado ("" si no se pudo detectar)
+	Language string
+
+	// Summary resume qué hace el código en pocas frases
+	Summary string
+
+	// Sections explican el código por fragmentos (una si cabía entero)
+	Sections []CodeSection
+
+	Model string
+	Usage Usage
+}
+
+// CodeFinding es un problema encontrado al revisar el código
+type CodeFinding struct {
+	// Severity es una de FindingSeverities
+	Severity string `json:"severity"`
+
+	// Category es una de FindingCategories
+	Category string `json:"category"`
+
+	// Line y EndLine son las líneas afectadas (0 = no se sabe)
+	Line    int `json:"line"`
+	EndLine int `json:"end_line,omitempty"`
+
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	// Suggestion es cómo corregirlo (opcional)
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// CodeReview es el resultado de revisar un fichero
+type CodeReview struct {
+	// Language es el lenguaje usado ("" si no se pudo detectar)
+	Language string
+
+	// Summary es la valoración general del código
+	Summary string
+
+	// Findings son los hallazgos, ordenados por línea
+	Findings []CodeFinding
+
+	// Chunks es en cuántos fragmentos se partió el fichero
+	Chunks int
+
+	Model string
+	Usage Usage
+}
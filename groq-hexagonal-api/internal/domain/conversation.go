@@ -0,0 +1,288 @@
+// Package domain - Conversaciones de varios turnos
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// ENTIDAD CONVERSATION
+// ============================================================================
+//
+// Los modelos de lenguaje no tienen memoria: cada petición es independiente.
+// Para mantener una conversación, hay que reenviar TODO el historial en cada
+// petición. La entidad Conversation guarda ese historial.
+// ============================================================================
+
+var (
+	// ErrConversationNotFound se retorna cuando no existe la conversación pedida
+	// (también si está borrada: para el cliente ya no existe)
+	ErrConversationNotFound = errors.New("conversación no encontrada")
+
+	// ErrConversationNotDeleted se retorna al restaurar una conversación activa
+	ErrConversationNotDeleted = errors.New("la conversación no está borrada")
+
+	// ErrRestoreWindowExpired se retorna al restaurar una conversación cuyo
+	// plazo de retención ya pasó (será eliminada definitivamente)
+	ErrRestoreWindowExpired = errors.New("el plazo para restaurar la conversación ha expirado")
+
+	// ErrConversationBudgetExceeded se retorna al enviar un mensaje a una
+	// conversación que ha agotado su presupuesto (sin modelo de reserva)
+	ErrConversationBudgetExceeded = errors.New("la conversación ha agotado su presupuesto")
+)
+
+// Conversation es una conversación con historial de mensajes
+type Conversation struct {
+	// ID identifica la conversación (generado por la aplicación)
+	ID string `json:"id"`
+
+	// Settings son los parámetros fijados ("pinned") para todos los turnos
+	ConversationSettings
+
+	// Messages es el historial completo (user/assistant), en orden cronológico
+	Messages []ConversationMessage `json:"messages"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// DeletedAt es el momento del borrado (nil = conversación activa)
+	// El borrado es "suave": los datos se conservan hasta PurgeAt
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// PurgeAt es cuándo se eliminará definitivamente (solo si está borrada)
+	PurgeAt *time.Time `json:"purge_at,omitempty"`
+
+	// Summary sustituye a los mensajes más antiguos al enviar el historial
+	// al modelo (nil = se envían todos). Messages se conserva completo
+	Summary *ConversationSummary `json:"summary,omitempty"`
+}
+
+// ConversationSummary es el resumen de los primeros mensajes del historial
+type ConversationSummary struct {
+	// Content es el texto del resumen
+	Content string `json:"content"`
+
+	// Messages es cuántos mensajes del principio de Messages resume
+	Messages int `json:"messages"`
+
+	// Model es el modelo que escribió el último resumen
+	Model string `json:"model"`
+
+	// Usage es el consumo sumado de todos los resúmenes (cuenta en Spent)
+	Usage Usage `json:"usage"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ConversationMessage es un mensaje del historial con sus metadatos
+type ConversationMessage struct {
+	// ChatMessage embebido: Role, Content... se serializan al mismo nivel
+	ChatMessage
+
+	// CreatedAt es el momento en que se añadió el mensaje
+	CreatedAt time.Time `json:"created_at"`
+
+	// Turn contiene los datos de la generación (solo en respuestas del asistente)
+	Turn *TurnMeta `json:"turn,omitempty"`
+}
+
+// TurnMeta son los datos de depuración y analítica de un turno del asistente
+type TurnMeta struct {
+	// Model es el modelo que generó la respuesta (puede diferir del fijado)
+	Model string `json:"model"`
+
+	// Latency es lo que tardó la generación
+	Latency time.Duration `json:"latency"`
+
+	// Usage es el consumo de tokens del turno
+	Usage Usage `json:"usage"`
+
+	// FinishReason indica por qué terminó la generación (ej: "stop", "length")
+	FinishReason string `json:"finish_reason,omitempty"`
+
+	// CacheHit indica que la respuesta salió de la caché (sin llamar a Groq)
+	CacheHit bool `json:"cache_hit,omitempty"`
+}
+
+// ConversationSettings son los parámetros fijados en una conversación
+// Se aplican a cada mensaje salvo que el mensaje los sobrescriba
+type ConversationSettings struct {
+	// Model es el modelo usado en los turnos de la conversación
+	Model string `json:"model"`
+
+	// Temperature fijada (nil = default del modelo)
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// SystemPrompt son las instrucciones de sistema de la conversación
+	// No se guarda en Messages: se envía en cada turno (y se puede cambiar)
+	SystemPrompt string `json:"system_prompt,omitempty"`
+
+	// Budget limita el consumo de la conversación (nil = sin límite)
+	Budget *ConversationBudget `json:"budget,omitempty"`
+}
+
+// ConversationBudget es el presupuesto de una conversación
+//
+// Protege de los bucles sin fin (un agente que se responde a sí mismo, un
+// cliente que reintenta): antes de cada turno se suma el consumo de los
+// anteriores y, si llega a un límite, el turno se rechaza con
+// ErrConversationBudgetExceeded o, con FallbackModel, se envía a ese modelo
+// (más barato). El turno que cruza el límite se completa: el consumo solo se
+// conoce después de generarlo.
+type ConversationBudget struct {
+	// MaxTotalTokens es el máximo de tokens (prompt + respuesta) sumando
+	// todos los turnos (0 = sin límite)
+	MaxTotalTokens int `json:"max_total_tokens,omitempty"`
+
+	// MaxCostUSD es el máximo de coste estimado (0 = sin límite)
+	// Los turnos de modelos sin precio no suman (ver pricing.go)
+	MaxCostUSD float64 `json:"max_cost_usd,omitempty"`
+
+	// FallbackModel recibe los turnos con el presupuesto agotado
+	// ("" = se rechazan)
+	FallbackModel string `json:"fallback_model,omitempty"`
+}
+
+// Exceeded indica si el consumo llega a alguno de los límites
+func (b *ConversationBudget) Exceeded(spent Usage) bool {
+	if b.MaxTotalTokens > 0 && spent.TotalTokens >= b.MaxTotalTokens {
+		return true
+	}
+	return b.MaxCostUSD > 0 && spent.CostUSD >= b.MaxCostUSD
+}
+
+// Merge retorna los ajustes con los campos no vacíos de other sobrescritos
+func (s ConversationSettings) Merge(other ConversationSettings) ConversationSettings {
+	if other.Model != "" {
+		s.Model = other.Model
+	}
+	if other.Temperature != nil {
+		s.Temperature = other.Temperature
+	}
+	if other.SystemPrompt != "" {
+		s.SystemPrompt = other.SystemPrompt
+	}
+	if other.Budget != nil {
+		// Un presupuesto vacío quita los límites
+		s.Budget = other.Budget
+		if *other.Budget == (ConversationBudget{}) {
+			s.Budget = nil
+		}
+	}
+	return s
+}
+
+// NewConversation crea una conversación vacía con los ajustes fijados
+func NewConversation(id string, settings ConversationSettings) *Conversation {
+	now := time.Now()
+	return &Conversation{
+		ID:                   id,
+		ConversationSettings: settings,
+		Messages:             []ConversationMessage{},
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+}
+
+// AddMessage añade un mensaje al historial y actualiza UpdatedAt
+// turn es nil salvo en las respuestas del asistente
+func (c *Conversation) AddMessage(role, content string, turn *TurnMeta) {
+	now := time.Now()
+	c.Messages = append(c.Messages, ConversationMessage{
+		ChatMessage: NewChatMessage(role, content),
+		CreatedAt:   now,
+		Turn:        turn,
+	})
+	c.UpdatedAt = now
+}
+
+// History retorna los mensajes tal como se envían al modelo (sin metadatos)
+// Con resumen, los mensajes resumidos se sustituyen por el mensaje del resumen
+func (c *Conversation) History() []ChatMessage {
+	messages := c.Messages
+	history := make([]ChatMessage, 0, len(messages)+1)
+	if c.Summary != nil {
+		messages = messages[min(c.Summary.Messages, len(messages)):]
+		history = append(history, NewHistorySummaryMessage(c.Summary.Content))
+	}
+	for _, message := range messages {
+		history = append(history, message.ChatMessage)
+	}
+	return history
+}
+
+// Compact guarda el resumen de los primeros mensajes de History()
+// summary.Messages cuenta sobre History(), que empieza por el resumen anterior
+// si lo había: se traduce a mensajes de Messages
+func (c *Conversation) Compact(summary HistorySummary) {
+	covered := summary.Messages
+	usage := summary.Usage
+	if previous := c.Summary; previous != nil {
+		covered += previous.Messages - 1
+		usage.PromptTokens += previous.Usage.PromptTokens
+		usage.CompletionTokens += previous.Usage.CompletionTokens
+		usage.TotalTokens += previous.Usage.TotalTokens
+		usage.CostUSD += previous.Usage.CostUSD
+	}
+	c.Summary = &ConversationSummary{
+		Content:   summary.Content,
+		Messages:  min(covered, len(c.Messages)),
+		Model:     summary.Model,
+		Usage:     usage,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Spent retorna el consumo sumado de todos los turnos del asistente y de
+// los resúmenes del historial
+func (c *Conversation) Spent() Usage {
+	var spent Usage
+	if c.Summary != nil {
+		spent = c.Summary.Usage
+	}
+	for _, message := range c.Messages {
+		if turn := message.Turn; turn != nil {
+			spent.PromptTokens += turn.Usage.PromptTokens
+			spent.CompletionTokens += turn.Usage.CompletionTokens
+			spent.TotalTokens += turn.Usage.TotalTokens
+			spent.CostUSD += turn.Usage.CostUSD
+		}
+	}
+	return spent
+}
+
+// Pin cambia los ajustes fijados (solo los campos no vacíos)
+func (c *Conversation) Pin(settings ConversationSettings) {
+	c.ConversationSettings = c.ConversationSettings.Merge(settings)
+	c.UpdatedAt = time.Now()
+}
+
+// IsDeleted indica si la conversación está borrada (pendiente de purga)
+func (c *Conversation) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// SoftDelete marca la conversación como borrada
+// Se podrá restaurar hasta que pase el plazo de retención
+func (c *Conversation) SoftDelete(now time.Time, retention time.Duration) {
+	purgeAt := now.Add(retention)
+	c.DeletedAt = &now
+	c.PurgeAt = &purgeAt
+}
+
+// Restore deshace el borrado
+func (c *Conversation) Restore() {
+	c.DeletedAt = nil
+	c.PurgeAt = nil
+	c.UpdatedAt = time.Now()
+}
+
+// Clone retorna una copia independiente de la conversación
+// Útil para que los repositorios no compartan el slice de mensajes
+func (c *Conversation) Clone() *Conversation {
+	clone := *c // Copia los campos (pero el slice sigue compartido)
+	clone.Messages = make([]ConversationMessage, len(c.Messages))
+	copy(clone.Messages, c.Messages)
+	return &clone
+}
@@ -0,0 +1,47 @@
+// Package domain - Overrides de depuración por petición
+package domain
+
+import "context"
+
+// ============================================================================
+// DEBUG OVERRIDES
+// ============================================================================
+//
+// Para reproducir un problema en producción, un operador con la clave de
+// administración puede alterar el comportamiento de UNA petición (forzar un
+// modelo, saltarse la caché o el rate limit) sin tocar la configuración.
+//
+// Igual que el tenant, los overrides viajan en el context.Context: el
+// adaptador HTTP los valida y las capas internas solo los leen.
+// ============================================================================
+
+// DebugOverrides son los cambios de comportamiento pedidos para una petición
+type DebugOverrides struct {
+	// ForceModel sustituye al modelo pedido por el cliente y a cualquier default
+	ForceModel string
+
+	// DisableCache ignora las respuestas cacheadas
+	DisableCache bool
+
+	// BypassRateLimit no aplica los límites de peticiones
+	BypassRateLimit bool
+}
+
+// IsZero indica si no hay ningún override
+func (o DebugOverrides) IsZero() bool {
+	return o == DebugOverrides{}
+}
+
+// debugOverridesKey es la clave privada para guardar los overrides en el contexto
+type debugOverridesKey struct{}
+
+// WithDebugOverrides retorna un contexto derivado que contiene los overrides
+func WithDebugOverrides(ctx context.Context, overrides DebugOverrides) context.Context {
+	return context.WithValue(ctx, debugOverridesKey{}, overrides)
+}
+
+// DebugOverridesFromContext obtiene los overrides del contexto (o ninguno)
+func DebugOverridesFromContext(ctx context.Context) DebugOverrides {
+	overrides, _ := ctx.Value(debugOverridesKey{}).(DebugOverrides)
+	return overrides
+}
@@ -0,0 +1,25 @@
+// Package domain - Avisos de modelos retirados
+package domain
+
+// ============================================================================
+// MODELOS RETIRADOS (DECOMMISSIONED)
+// ============================================================================
+//
+// Groq retira modelos periódicamente. Cuando un cliente sigue pidiendo un
+// modelo retirado, la aplicación intenta sustituirlo por su reemplazo del
+// catálogo de alias y avisa a los operadores con un DecommissionEvent, para
+// que puedan migrar a esos clientes antes de que el alias desaparezca.
+// ============================================================================
+
+// DecommissionEvent es el aviso de que se ha pedido un modelo retirado
+type DecommissionEvent struct {
+	// Model es el modelo retirado que se pidió
+	Model string
+
+	// Replacement es el modelo usado en su lugar ("" si no hay alias)
+	Replacement string
+
+	// StillRequested cuenta las peticiones de cada modelo retirado
+	// desde que arrancó el servicio (incluye la actual)
+	StillRequested map[string]int
+}
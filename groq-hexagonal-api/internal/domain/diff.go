@@ -0,0 +1,64 @@
+// Package domain - Comparación de respuestas entre dos configuraciones
+package domain
+
+// ============================================================================
+// ENTIDADES DE COMPARACIÓN (DIFF)
+// ============================================================================
+//
+// Para decidir si un modelo nuevo o un prompt nuevo puede sustituir al actual
+// (canary, A/B), se envía el MISMO mensaje con dos configuraciones y se
+// comparan las respuestas palabra a palabra.
+// ============================================================================
+
+// DiffVariant es una de las dos configuraciones a comparar
+type DiffVariant struct {
+	// Model es el modelo a usar (vacío = modelo por defecto)
+	Model string
+
+	// Temperature es la temperatura de esta variante (nil = default del modelo)
+	Temperature *float64
+
+	// SystemPrompt son las instrucciones de sistema de esta variante
+	SystemPrompt string
+}
+
+// DiffRequest es la entrada del caso de uso de comparar respuestas
+type DiffRequest struct {
+	// Message es el mensaje que se envía a ambas variantes
+	Message string
+
+	// MaxTokens se aplica a ambas variantes (0 = sin límite explícito)
+	MaxTokens int
+
+	A DiffVariant
+	B DiffVariant
+}
+
+// Operaciones de un DiffOp
+const (
+	DiffEqual  = "equal"  // El texto aparece en ambas respuestas
+	DiffDelete = "delete" // El texto solo aparece en la respuesta A
+	DiffInsert = "insert" // El texto solo aparece en la respuesta B
+)
+
+// DiffOp es un tramo del diff entre la respuesta A y la B
+type DiffOp struct {
+	// Op es DiffEqual, DiffDelete o DiffInsert
+	Op string
+
+	// Text son las palabras del tramo, separadas por espacios
+	Text string
+}
+
+// DiffResult es el resultado de comparar las dos respuestas
+type DiffResult struct {
+	A *ChatResponse
+	B *ChatResponse
+
+	// Similarity va de 0 (nada en común) a 1 (mismas palabras en el mismo orden)
+	Similarity float64
+
+	// Ops es el diff palabra a palabra de A hacia B
+	// Vacío si las respuestas son demasiado largas para calcularlo
+	Ops []DiffOp
+}
@@ -0,0 +1,46 @@
+// Package domain - Simulación de peticiones (dry run)
+package domain
+
+// ============================================================================
+// DRY RUN
+// ============================================================================
+//
+// Un dry run hace todo lo que haría SendMessage (validación, persona, modelo,
+// alias de modelos retirados, prompts de sistema, stop sequences...) excepto
+// llamar a Groq. Sirve para probar integraciones sin gastar tokens y para
+// estimar el coste de una petición antes de enviarla.
+// ============================================================================
+
+// DryRunResult es lo que se habría enviado a Groq
+type DryRunResult struct {
+	// Request es la petición tal como se enviaría a Groq
+	// Las instrucciones del tenant aparecen ocultas (el cliente no las ve)
+	Request ChatRequest
+
+	// Meta son los metadatos que llevaría la respuesta (idioma, avisos...)
+	Meta ResponseMeta
+
+	// EstimatedPromptTokens es una estimación aproximada de los tokens de
+	// entrada (el número exacto solo lo conoce el tokenizador del modelo)
+	EstimatedPromptTokens int
+
+	// Cost es la estimación del coste (nil si el modelo no tiene precio configurado)
+	Cost *CostEstimate
+
+	// Sections son las secciones del prompt, en orden (ver prompt_sections.go)
+	// Sus tokens suman EstimatedPromptTokens
+	Sections []PromptSection
+
+	// ContextWindow es la ventana de contexto del modelo (0 = desconocida)
+	ContextWindow int
+}
+
+// CostEstimate es el coste estimado de una petición en USD
+type CostEstimate struct {
+	// Prompt es el coste de los tokens de entrada estimados
+	Prompt float64
+
+	// MaxCompletion es el coste máximo de la respuesta (según max_tokens)
+	// 0 si la petición no limita max_tokens: entonces no hay máximo conocido
+	MaxCompletion float64
+}
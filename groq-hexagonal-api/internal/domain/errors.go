@@ -0,0 +1,92 @@
+// Package domain - Errores del dominio
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// ERRORES DEL DOMINIO
+// ============================================================================
+//
+// Los adaptadores (ej: el cliente de Groq) traducen sus errores a estos
+// valores, envolviéndolos con %w. Así la aplicación y los handlers pueden
+// decidir qué hacer con errors.Is() sin conocer los detalles del proveedor.
+//
+// El handler HTTP traduce cada uno a su código de estado (429, 404, 413...).
+// ============================================================================
+
+// Errores de entrada (el cliente debe corregir la petición)
+var (
+	ErrEmptyMessage = errors.New("el mensaje no puede estar vacío")
+	ErrEmptyModel   = errors.New("el modelo no puede estar vacío")
+
+	// ErrRequestRejected indica que un RequestHook rechazó la petición
+	// (validaciones o políticas propias del despliegue, ver hooks.go)
+	ErrRequestRejected = errors.New("petición rechazada")
+)
+
+// Errores del proveedor de modelos
+var (
+	// ErrModelDecommissioned indica que el modelo pedido ya no está disponible
+	// (el proveedor lo ha retirado definitivamente)
+	ErrModelDecommissioned = errors.New("el modelo ha sido retirado")
+
+	// ErrModelNotFound indica que el modelo pedido no existe
+	ErrModelNotFound = errors.New("el modelo no existe")
+
+	// ErrContextTooLong indica que los mensajes superan la ventana de contexto
+	ErrContextTooLong = errors.New("la petición supera la ventana de contexto del modelo")
+
+	// ErrRateLimited indica que se ha superado el límite de peticiones o tokens
+	ErrRateLimited = errors.New("límite de peticiones superado")
+
+	// ErrInvalidRequest indica que el proveedor rechazó la petición por inválida
+	ErrInvalidRequest = errors.New("petición rechazada por el proveedor")
+
+	// ErrUpstreamAuth indica que el proveedor rechazó nuestras credenciales
+	// Es un fallo de configuración del servidor, no del cliente
+	ErrUpstreamAuth = errors.New("credenciales del proveedor inválidas")
+
+	// ErrUpstreamUnavailable indica que el proveedor no responde o falla (5xx)
+	ErrUpstreamUnavailable = errors.New("el proveedor no está disponible")
+
+	// ErrUpstreamTimeout indica que el proveedor no respondió a tiempo
+	ErrUpstreamTimeout = errors.New("el proveedor no respondió a tiempo")
+
+	// ErrInvalidModelOutput indica que la respuesta del modelo no tiene el
+	// formato esperado (ej: se pidió JSON y no lo es)
+	ErrInvalidModelOutput = errors.New("el modelo no devolvió una respuesta válida")
+)
+
+// UpstreamError es un error del proveedor con detalles adicionales
+//
+// Kind es uno de los errores de arriba: errors.Is(err, ErrRateLimited)
+// funciona gracias a Unwrap(). Con errors.As() se accede a los detalles.
+type UpstreamError struct {
+	// Kind es la categoría del error (ErrRateLimited, ErrModelNotFound...)
+	Kind error
+
+	// StatusCode es el código HTTP que devolvió el proveedor (0 si no hubo respuesta)
+	StatusCode int
+
+	// Message es el mensaje del proveedor (útil para el cliente en errores 4xx)
+	Message string
+
+	// RetryAfter es cuánto esperar antes de reintentar (0 = desconocido)
+	RetryAfter time.Duration
+}
+
+// Error implementa la interfaz error
+func (e *UpstreamError) Error() string {
+	if e.Message == "" {
+		return e.Kind.Error()
+	}
+	return e.Kind.Error() + ": " + e.Message
+}
+
+// Unwrap permite que errors.Is() compare con Kind
+func (e *UpstreamError) Unwrap() error {
+	return e.Kind
+}
@@ -0,0 +1,130 @@
+// Package domain - Valoraciones de las respuestas
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// FEEDBACK
+// ============================================================================
+//
+// Los clientes pueden valorar una respuesta (pulgar arriba o abajo) con un
+// comentario opcional. La valoración se refiere a una respuesta (su ID, el
+// "id" de la respuesta del modelo) o a una conversación, y puede indicar el
+// modelo y la persona con los que se generó: así el operador compara prompts
+// y modelos con datos reales (ver FeedbackSummary).
+//
+// La API no comprueba que la respuesta o la conversación existan: no guarda
+// las respuestas, y una conversación puede haberse purgado. El tenant de la
+// valoración es el de la petición que la envía.
+// ============================================================================
+
+// Valores de Feedback.Rating
+const (
+	FeedbackUp   = "up"
+	FeedbackDown = "down"
+)
+
+// Límites de una valoración y de su resumen
+const (
+	MaxFeedbackCommentChars = 2000
+	MaxFeedbackIDChars      = 256
+	MaxFeedbackSummaryDays  = 90
+
+	// MaxFeedbackComments es cuántos comentarios recientes trae el resumen
+	MaxFeedbackComments = 20
+)
+
+// ErrInvalidFeedback se retorna cuando una valoración no es válida (sin
+// respuesta ni conversación, puntuación desconocida, comentario largo...)
+var ErrInvalidFeedback = errors.New("valoración inválida")
+
+// Feedback es la valoración de una respuesta
+type Feedback struct {
+	ID string `json:"id"`
+
+	// ResponseID y ConversationID identifican lo valorado (al menos uno)
+	ResponseID     string `json:"response_id,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// Rating es FeedbackUp o FeedbackDown
+	Rating string `json:"rating"`
+
+	Comment string `json:"comment,omitempty"`
+
+	// Model y Persona son con los que se generó la respuesta (opcionales)
+	Model   string `json:"model,omitempty"`
+	Persona string `json:"persona,omitempty"`
+
+	// Tenant es el de la petición que envió la valoración ("" = sin tenant)
+	Tenant string `json:"tenant,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FeedbackFilter elige las valoraciones de un resumen
+type FeedbackFilter struct {
+	// Since es desde cuándo (incluido)
+	Since time.Time
+
+	// Tenant limita las valoraciones a un tenant ("" = todos)
+	Tenant string
+}
+
+// Matches indica si la valoración entra en el filtro
+func (f FeedbackFilter) Matches(feedback Feedback) bool {
+	return !feedback.CreatedAt.Before(f.Since) && (f.Tenant == "" || feedback.Tenant == f.Tenant)
+}
+
+// FeedbackCount cuenta las valoraciones positivas y negativas
+type FeedbackCount struct {
+	Up   int
+	Down int
+}
+
+// Add suma una valoración a la cuenta
+func (c *FeedbackCount) Add(rating string) {
+	if rating == FeedbackUp {
+		c.Up++
+	} else {
+		c.Down++
+	}
+}
+
+// Total es el número de valoraciones
+func (c FeedbackCount) Total() int {
+	return c.Up + c.Down
+}
+
+// Score es la parte de valoraciones positivas (0 a 1; 0 si no hay ninguna)
+func (c FeedbackCount) Score() float64 {
+	if c.Total() == 0 {
+		return 0
+	}
+	return float64(c.Up) / float64(c.Total())
+}
+
+// GroupedFeedback es la cuenta de un modelo o una persona
+type GroupedFeedback struct {
+	Name string
+	FeedbackCount
+}
+
+// FeedbackSummary resume las valoraciones de un periodo
+type FeedbackSummary struct {
+	Filter FeedbackFilter
+
+	// Total cuenta todas las valoraciones del filtro
+	Total FeedbackCount
+
+	// ByModel y ByPersona cuentan las que indican modelo o persona, de la
+	// peor puntuación a la mejor (lo que más urge revisar va primero)
+	ByModel   []GroupedFeedback
+	ByPersona []GroupedFeedback
+
+	// RecentComments son las últimas valoraciones con comentario (como
+	// mucho MaxFeedbackComments), de la más reciente a la más antigua
+	RecentComments []Feedback
+}
@@ -0,0 +1,56 @@
+// Package domain - Verificación de las respuestas (grounding)
+package domain
+
+// ============================================================================
+// VERIFICACIÓN DE LA RESPUESTA
+// ============================================================================
+//
+// Un modelo puede afirmar con seguridad cosas que no están en las fuentes o
+// que se ha inventado. Con la verificación activada, una segunda llamada al
+// modelo revisa la respuesta ya generada, afirmación por afirmación:
+//
+//   - Con fuentes (los documentos recuperados en un RAG, o los que envíe el
+//     cliente), comprueba que cada afirmación se apoya en ellas
+//   - Sin fuentes, marca las afirmaciones que no se pueden comprobar o que
+//     probablemente son falsas (cifras, fechas, citas, referencias...)
+//
+// El resultado acompaña a la respuesta: no la cambia ni la rechaza. Decidir
+// qué hacer con una puntuación baja (avisar al usuario, reintentar, pasar a
+// una persona) es cosa del cliente.
+// ============================================================================
+
+// Límites de las fuentes de una verificación
+const (
+	MaxGroundingSources     = 20
+	MaxGroundingSourceChars = 20000
+)
+
+// GroundingOptions activa la verificación de la respuesta (ver MessageOptions)
+type GroundingOptions struct {
+	// Sources son los textos con los que se contrasta la respuesta
+	// Sin fuentes se buscan afirmaciones sin respaldo en general
+	Sources []string
+}
+
+// UnsupportedClaim es una afirmación de la respuesta sin respaldo
+type UnsupportedClaim struct {
+	Claim  string `json:"claim"`
+	Reason string `json:"reason"`
+}
+
+// Grounding es el resultado de verificar una respuesta
+type Grounding struct {
+	// Score es la parte de la respuesta respaldada: de 0 (nada) a 1 (todo)
+	Score float64
+
+	// UnsupportedClaims son las afirmaciones sin respaldo (vacío = ninguna)
+	UnsupportedClaims []UnsupportedClaim
+
+	// WithSources indica si se contrastó con fuentes o solo se buscaron
+	// afirmaciones dudosas
+	WithSources bool
+
+	// Model y Usage son los de la llamada de verificación
+	Model string
+	Usage Usage
+}
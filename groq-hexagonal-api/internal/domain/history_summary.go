@@ -0,0 +1,45 @@
+// Package domain - Resumen del historial de una conversación
+package domain
+
+import "strings"
+
+// ============================================================================
+// RESUMEN DEL HISTORIAL
+// ============================================================================
+//
+// Cuando el historial se acerca a la ventana de contexto del modelo, los
+// turnos más antiguos se sustituyen por un único mensaje de sistema con su
+// resumen (lo escribe un modelo más barato). Los turnos recientes se envían
+// tal cual.
+//
+// El mensaje del resumen empieza por HistorySummaryPrefix: así un resumen
+// anterior se reconoce y se vuelve a resumir junto con los turnos siguientes
+// en lugar de acumular varios.
+// ============================================================================
+
+// HistorySummaryPrefix encabeza el mensaje de sistema con el resumen
+const HistorySummaryPrefix = "Resumen de la conversación anterior:\n"
+
+// HistorySummary es el resumen que sustituye a los mensajes más antiguos
+type HistorySummary struct {
+	// Content es el texto del resumen (sin HistorySummaryPrefix)
+	Content string
+
+	// Messages es cuántos mensajes del principio del historial sustituye
+	// (incluido el resumen anterior, si lo había)
+	Messages int
+
+	// Model y Usage son los de la llamada que escribió el resumen
+	Model string
+	Usage Usage
+}
+
+// NewHistorySummaryMessage crea el mensaje de sistema con el resumen
+func NewHistorySummaryMessage(content string) ChatMessage {
+	return NewChatMessage("system", HistorySummaryPrefix+content)
+}
+
+// IsHistorySummary indica si el mensaje es un resumen del historial
+func IsHistorySummary(message ChatMessage) bool {
+	return message.Role == "system" && strings.HasPrefix(message.Content, HistorySummaryPrefix)
+}
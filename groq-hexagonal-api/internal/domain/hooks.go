@@ -0,0 +1,77 @@
+// Package domain - Hooks de peticiones y respuestas (puntos de extensión)
+package domain
+
+import (
+	"context"
+	"net/textproto"
+)
+
+// ============================================================================
+// HOOKS
+// ============================================================================
+//
+// Quien despliega la API puede añadir lógica propia sin tocar los handlers ni
+// los servicios: enrutado por cabeceras, facturación, validaciones extra...
+//
+//   - RequestHook: antes de enviar la petición al modelo (puede modificarla
+//     o rechazarla)
+//   - ResponseHook: con la respuesta final (solo observa)
+//
+// Los hooks se registran en main (application.HookRegistry). Para decidir
+// según la petición original, los adaptadores de entrada guardan sus
+// cabeceras en el contexto (RequestHeadersFromContext).
+//
+// Un error de un RequestHook llega al cliente como cualquier error del
+// servicio: para rechazar la petición, envuelve ErrRequestRejected (403) u
+// otro error del dominio (ej: ErrRateLimited, 429). Cualquier otro error es
+// un 500.
+// ============================================================================
+
+// RequestHookFunc permite usar una función como RequestHook
+// (igual que http.HandlerFunc con http.Handler)
+type RequestHookFunc func(ctx context.Context, request *ChatRequest) error
+
+// BeforeRequest implementa RequestHook
+func (f RequestHookFunc) BeforeRequest(ctx context.Context, request *ChatRequest) error {
+	return f(ctx, request)
+}
+
+// ResponseHookFunc permite usar una función como ResponseHook
+type ResponseHookFunc func(ctx context.Context, request ChatRequest, response *ChatResponse)
+
+// AfterResponse implementa ResponseHook
+func (f ResponseHookFunc) AfterResponse(ctx context.Context, request ChatRequest, response *ChatResponse) {
+	f(ctx, request, response)
+}
+
+// ============================================================================
+// CABECERAS DE LA PETICIÓN
+// ============================================================================
+
+// RequestHeaders son las cabeceras (HTTP) o metadatos (gRPC) de la petición
+// Las claves están en forma canónica: "X-Custom-Header"
+type RequestHeaders map[string][]string
+
+// Get retorna el primer valor de la cabecera ("" si no existe)
+// No distingue mayúsculas: Get("x-custom-header") == Get("X-Custom-Header")
+func (h RequestHeaders) Get(name string) string {
+	if values := h[textproto.CanonicalMIMEHeaderKey(name)]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// requestHeadersKey es la clave privada para guardar las cabeceras
+type requestHeadersKey struct{}
+
+// WithRequestHeaders retorna un contexto derivado con las cabeceras
+func WithRequestHeaders(ctx context.Context, headers RequestHeaders) context.Context {
+	return context.WithValue(ctx, requestHeadersKey{}, headers)
+}
+
+// RequestHeadersFromContext obtiene las cabeceras de la petición
+// (nil si el adaptador de entrada no las guardó; Get funciona igual)
+func RequestHeadersFromContext(ctx context.Context) RequestHeaders {
+	headers, _ := ctx.Value(requestHeadersKey{}).(RequestHeaders)
+	return headers
+}
@@ -0,0 +1,52 @@
+// Package domain - Peticiones idempotentes (Idempotency-Key)
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// IDEMPOTENCIA
+// ============================================================================
+//
+// Un cliente que reintenta una petición (timeout, conexión cortada) no sabe
+// si la primera llegó al modelo. Con la misma Idempotency-Key en los
+// reintentos, la primera respuesta se guarda y las siguientes la reciben tal
+// cual: no se paga dos veces ni llegan dos respuestas distintas.
+//
+// Mientras la primera petición está en curso, la clave queda reservada; un
+// duplicado que llega entonces no espera, recibe ErrIdempotencyInProgress.
+// ============================================================================
+
+// MaxIdempotencyKeyLength es la longitud máxima de una Idempotency-Key
+const MaxIdempotencyKeyLength = 255
+
+// Errores de las peticiones idempotentes
+var (
+	// ErrIdempotencyInProgress: la primera petición con la clave no ha terminado
+	ErrIdempotencyInProgress = errors.New("hay una petición en curso con la misma Idempotency-Key")
+
+	// ErrIdempotencyKeyReused: la clave ya se usó con una petición distinta
+	ErrIdempotencyKeyReused = errors.New("la Idempotency-Key ya se usó con una petición distinta")
+
+	// ErrInvalidIdempotencyKey: la clave es demasiado larga
+	ErrInvalidIdempotencyKey = errors.New("la Idempotency-Key no puede tener más de 255 caracteres")
+)
+
+// IdempotentResponse es lo que se guarda de una petición con Idempotency-Key
+type IdempotentResponse struct {
+	// Fingerprint identifica la petición (hash del método, la ruta y el body):
+	// la misma clave con otra petición es un error del cliente
+	Fingerprint string `json:"fingerprint"`
+
+	// Completed es false mientras la primera petición sigue en curso
+	Completed bool `json:"completed"`
+
+	// La respuesta HTTP (solo con Completed)
+	StatusCode int                 `json:"status_code,omitempty"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       []byte              `json:"body,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,183 @@
+// Package domain - Peticiones de chat asíncronas (jobs)
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// ENTIDAD JOB
+// ============================================================================
+//
+// Para generaciones largas, el cliente no espera a la respuesta: recibe el ID
+// de un job al instante y consulta su estado hasta que termina. Un pool de
+// workers hace la llamada al modelo en segundo plano. El job se guarda en el
+// repositorio (memoria, Redis o PostgreSQL) en cada cambio de estado, así que
+// con almacenamiento compartido cualquier réplica puede responder al sondeo.
+//
+// La cola es del proceso: los jobs que esperan o se ejecutan cuando el
+// proceso se detiene no terminan (quedan pending o running hasta caducar).
+//
+// En lugar de sondear, el cliente puede pedir un callback: al terminar el
+// job, la aplicación hace POST del job a su URL, firmado con HMAC. Las
+// entregas fallidas se reintentan con espera creciente; si se agotan los
+// intentos, la entrega va al registro de entregas fallidas (dead letter) y
+// el cliente puede seguir consultando el job.
+//
+// Mientras se ejecuta, el job informa de su progreso: los jobs con streaming
+// (Stream) cuentan los tokens generados hasta el momento y, con MaxTokens,
+// el porcentaje. Los fallos llevan un tipo (ErrorType: rate_limited,
+// upstream_timeout...), el mismo que el "type" de los errores HTTP.
+// ============================================================================
+
+var (
+	// ErrJobNotFound se retorna cuando no existe el job pedido (o caducó, o
+	// es de otro cliente: para él no existe)
+	ErrJobNotFound = errors.New("job no encontrado")
+
+	// ErrJobQueueFull se retorna cuando la cola de jobs no admite más
+	ErrJobQueueFull = errors.New("la cola de jobs está llena, inténtalo más tarde")
+
+	// ErrInvalidCallbackURL se retorna cuando callback_url no es una URL
+	// http(s) absoluta
+	ErrInvalidCallbackURL = errors.New("callback_url debe ser una URL http o https absoluta")
+
+	// ErrCallbacksDisabled se retorna cuando se pide un callback y el
+	// despliegue no tiene secreto para firmarlos (WEBHOOK_SECRET)
+	ErrCallbacksDisabled = errors.New("los callbacks de jobs no están activados en este servidor")
+
+	// ErrCallbackRejected se retorna cuando el receptor del callback no
+	// acepta la entrega (responde con un status que no es 2xx)
+	ErrCallbackRejected = errors.New("el receptor del callback rechazó la entrega")
+
+	// ErrJobResultUnavailable se retorna cuando se pide el resultado de un
+	// job que no ha terminado con éxito
+	ErrJobResultUnavailable = errors.New("el job no tiene resultado (todavía no ha terminado o ha fallado)")
+)
+
+// JobStatus es el estado de un job
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"   // En cola, esperando a un worker
+	JobRunning   JobStatus = "running"   // Un worker está llamando al modelo
+	JobSucceeded JobStatus = "succeeded" // Terminado: Result tiene la respuesta
+	JobFailed    JobStatus = "failed"    // Terminado: Error tiene la causa
+)
+
+// Done indica si el job ya terminó (con éxito o no)
+func (s JobStatus) Done() bool {
+	return s == JobSucceeded || s == JobFailed
+}
+
+// JobRequest es la entrada del caso de uso de encolar un chat
+type JobRequest struct {
+	// Client es el dueño del job: solo él puede consultarlo
+	Client string
+
+	// Message, Model y Options son los mismos que en ChatService.SendMessage
+	Message string
+	Model   string
+	Options MessageOptions
+
+	// CallbackURL recibe el job al terminar (opcional)
+	CallbackURL string
+
+	// Stream genera la respuesta por fragmentos para informar del progreso
+	// (tokens generados) mientras se ejecuta
+	Stream bool
+}
+
+// Job es una petición de chat que se procesa en segundo plano
+type Job struct {
+	// ID identifica el job (generado por la aplicación)
+	ID string `json:"id"`
+
+	// Client es el dueño del job (ver JobRequest)
+	Client string `json:"client"`
+
+	Status JobStatus `json:"status"`
+
+	// Model es el modelo pedido (vacío = modelo por defecto)
+	Model string `json:"model,omitempty"`
+
+	// Result es la respuesta del modelo (solo si Status es succeeded y no se
+	// ha guardado aparte)
+	Result *ChatResponse `json:"result,omitempty"`
+
+	// ResultRef indica que la respuesta es grande y se guardó fuera del job,
+	// en el JobResultStore (Result queda vacío)
+	ResultRef *JobResultRef `json:"result_ref,omitempty"`
+
+	// Error es la causa del fallo (solo si Status es failed)
+	Error string `json:"error,omitempty"`
+
+	// ErrorType es el tipo del fallo (ej: "rate_limited"; solo si Status es
+	// failed)
+	ErrorType string `json:"error_type,omitempty"`
+
+	// Progress es el avance de la generación (nil mientras está en cola)
+	Progress *JobProgress `json:"progress,omitempty"`
+
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+
+	// ExpiresAt es cuándo se elimina el job del repositorio
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Callback es el estado de la entrega del job (nil = sin callback)
+	Callback *JobCallback `json:"callback,omitempty"`
+}
+
+// JobProgress es el avance de un job
+type JobProgress struct {
+	// Tokens son los tokens generados hasta el momento (estimados mientras
+	// se ejecuta; los del proveedor al terminar)
+	Tokens int `json:"tokens"`
+
+	// Percent es el porcentaje completado (0 a 100; nil si no se sabe: sin
+	// MaxTokens, el final de la respuesta no se conoce hasta que llega)
+	Percent *int `json:"percent,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// JobResultRef es una respuesta guardada en el JobResultStore
+// El job no la lleva dentro: el repositorio (y su memoria) no crece con ella
+type JobResultRef struct {
+	// Size es el tamaño del JSON guardado, en bytes
+	Size int64 `json:"size"`
+}
+
+// CallbackStatus es el estado de la entrega de un callback
+type CallbackStatus string
+
+const (
+	CallbackPending   CallbackStatus = "pending"   // El job no ha terminado o quedan intentos
+	CallbackDelivered CallbackStatus = "delivered" // El receptor respondió 2xx
+	CallbackFailed    CallbackStatus = "failed"    // Se agotaron los intentos (dead letter)
+)
+
+// JobCallback es la entrega de un job terminado a la URL del cliente
+type JobCallback struct {
+	URL    string         `json:"url"`
+	Status CallbackStatus `json:"status"`
+
+	// Attempts es cuántas veces se ha intentado entregar
+	Attempts int `json:"attempts"`
+
+	// LastError es la causa del último intento fallido
+	LastError string `json:"last_error,omitempty"`
+}
+
+// DeadLetter es una entrega que agotó sus intentos
+type DeadLetter struct {
+	JobID     string
+	Client    string
+	URL       string
+	Attempts  int
+	LastError string
+	FailedAt  time.Time
+}
@@ -0,0 +1,29 @@
+// Package domain - Vigilancia de tareas colgadas
+package domain
+
+// ============================================================================
+// TAREAS COLGADAS
+// ============================================================================
+//
+// Una petición que no termina nunca (un proveedor que no cierra la conexión,
+// un lock que nadie suelta) no produce ningún error: solo ocupa un hueco del
+// rate limit o un worker de jobs hasta que alguien reinicia la réplica.
+//
+// Las peticiones y los workers registran cada tarea en un LivenessMonitor con
+// su plazo. El monitor avisa de las que pasan de varias veces ese plazo: el
+// fallo silencioso se convierte en un log con las pilas de las goroutines y
+// en una métrica.
+// ============================================================================
+
+// LivenessStats es el estado del LivenessMonitor
+type LivenessStats struct {
+	// Active son las tareas en curso
+	Active int
+
+	// Stuck son las tareas en curso que han pasado de su límite
+	Stuck int
+
+	// StuckTotal cuenta las tareas colgadas desde el arranque (aunque
+	// terminaran después)
+	StuckTotal int64
+}
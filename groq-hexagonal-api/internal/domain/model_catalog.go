@@ -0,0 +1,98 @@
+// Package domain - Catálogo de modelos para el enrutado por coste
+package domain
+
+import "errors"
+
+// ============================================================================
+// CATÁLOGO DE MODELOS Y CALIDAD
+// ============================================================================
+//
+// En lugar de un modelo, el cliente puede pedir una calidad: "fast",
+// "balanced" o "best". Cada modelo del catálogo tiene un nivel (tier) y unas
+// capacidades (visión, herramientas); la aplicación elige el modelo MÁS
+// BARATO que llega al nivel pedido (uno de nivel superior también vale) y
+// tiene las capacidades que necesita la petición.
+//
+// El catálogo por defecto cubre los modelos más usados y se amplía o corrige
+// con MODEL_PROFILES. Un modelo sin precio no se puede comparar, así que no
+// se elige nunca por coste.
+// ============================================================================
+
+// Niveles de calidad, de menor a mayor
+const (
+	QualityFast     = "fast"
+	QualityBalanced = "balanced"
+	QualityBest     = "best"
+)
+
+// QualityTiers son los niveles admitidos, de menor a mayor
+var QualityTiers = []string{QualityFast, QualityBalanced, QualityBest}
+
+// QualityRank retorna la posición del nivel (-1 si no existe)
+func QualityRank(tier string) int {
+	for i, candidate := range QualityTiers {
+		if candidate == tier {
+			return i
+		}
+	}
+	return -1
+}
+
+// Capacidades de los modelos que puede necesitar una petición
+const (
+	CapabilityVision = "vision" // Imágenes en los mensajes
+	CapabilityTools  = "tools"  // Tool calling
+)
+
+// ModelCapabilities son las capacidades admitidas
+var ModelCapabilities = []string{CapabilityVision, CapabilityTools}
+
+// ErrNoModelForQuality se retorna cuando ningún modelo del catálogo cumple
+// la calidad y las capacidades pedidas
+var ErrNoModelForQuality = errors.New("ningún modelo del catálogo cumple la calidad pedida")
+
+// ModelProfile es la entrada de un modelo en el catálogo
+type ModelProfile struct {
+	// Provider es el proveedor del modelo (ej: "groq")
+	Provider string `json:"provider"`
+
+	// Tier es el nivel de calidad (uno de QualityTiers)
+	Tier string `json:"tier"`
+
+	// Capabilities son las capacidades del modelo (de ModelCapabilities)
+	Capabilities []string `json:"capabilities,omitempty"`
+}
+
+// Supports indica si el modelo tiene todas las capacidades
+func (p ModelProfile) Supports(capabilities []string) bool {
+	for _, required := range capabilities {
+		found := false
+		for _, capability := range p.Capabilities {
+			if capability == required {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultModelProfiles es el catálogo de los modelos más usados
+// Los niveles son orientativos: se corrigen con MODEL_PROFILES
+var DefaultModelProfiles = map[string]ModelProfile{
+	// Groq
+	"llama-3.1-8b-instant":                          {Provider: "groq", Tier: QualityFast, Capabilities: []string{CapabilityTools}},
+	"openai/gpt-oss-20b":                            {Provider: "groq", Tier: QualityBalanced, Capabilities: []string{CapabilityTools}},
+	"qwen/qwen3-32b":                                {Provider: "groq", Tier: QualityBalanced, Capabilities: []string{CapabilityTools}},
+	"meta-llama/llama-4-scout-17b-16e-instruct":     {Provider: "groq", Tier: QualityBalanced, Capabilities: []string{CapabilityVision, CapabilityTools}},
+	"meta-llama/llama-4-maverick-17b-128e-instruct": {Provider: "groq", Tier: QualityBalanced, Capabilities: []string{CapabilityVision, CapabilityTools}},
+	"llama-3.3-70b-versatile":                       {Provider: "groq", Tier: QualityBest, Capabilities: []string{CapabilityTools}},
+	"openai/gpt-oss-120b":                           {Provider: "groq", Tier: QualityBest, Capabilities: []string{CapabilityTools}},
+
+	// OpenAI
+	"gpt-4o-mini": {Provider: "openai", Tier: QualityBalanced, Capabilities: []string{CapabilityVision, CapabilityTools}},
+	"gpt-4o":      {Provider: "openai", Tier: QualityBest, Capabilities: []string{CapabilityVision, CapabilityTools}},
+}
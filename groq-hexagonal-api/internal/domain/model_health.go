@@ -0,0 +1,73 @@
+// Package domain - Salud de los modelos (telemetría del cliente)
+package domain
+
+import "time"
+
+// ============================================================================
+// SALUD DE LOS MODELOS
+// ============================================================================
+//
+// La salud se calcula con lo que ve este servidor al llamar al proveedor
+// (telemetría del lado del cliente), no con la página de estado del proveedor:
+//
+//   - Tasa de error y latencia p95 de las peticiones recientes
+//   - Estado del circuit breaker del modelo
+//   - Margen de rate limit según las cabeceras x-ratelimit-* de la última respuesta
+// ============================================================================
+
+// CircuitState es el estado del circuit breaker de un modelo
+type CircuitState string
+
+const (
+	// CircuitClosed: las peticiones pasan con normalidad
+	CircuitClosed CircuitState = "closed"
+
+	// CircuitOpen: demasiados fallos seguidos; se rechazan sin llamar al proveedor
+	CircuitOpen CircuitState = "open"
+
+	// CircuitHalfOpen: pasado el enfriamiento, una petición de prueba decide
+	// si el circuito se cierra o vuelve a abrirse
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// RateLimitStatus es el margen de rate limit que anunció el proveedor
+// Los campos a 0 no venían en la respuesta
+type RateLimitStatus struct {
+	LimitRequests     int
+	RemainingRequests int
+	ResetRequests     time.Duration
+
+	LimitTokens     int
+	RemainingTokens int
+	ResetTokens     time.Duration
+
+	// ObservedAt es cuándo llegó la respuesta con estas cabeceras
+	ObservedAt time.Time
+}
+
+// ModelHealth es la salud reciente de un modelo de un proveedor
+type ModelHealth struct {
+	Provider string
+	Model    string
+
+	// Requests, Failures y RateLimited cuentan las peticiones de la ventana
+	// Failures incluye las rechazadas por rate limit (429)
+	Requests    int
+	Failures    int
+	RateLimited int
+
+	// ErrorRate es Failures / Requests (0 si no hubo peticiones)
+	ErrorRate float64
+
+	// P95Latency es la latencia p95 de las peticiones con éxito
+	P95Latency time.Duration
+
+	Circuit CircuitState
+
+	// CircuitOpenUntil es cuándo se permitirá la petición de prueba
+	// (solo con el circuito abierto)
+	CircuitOpenUntil time.Time
+
+	// RateLimit es el último margen observado (nil si el proveedor no lo envía)
+	RateLimit *RateLimitStatus
+}
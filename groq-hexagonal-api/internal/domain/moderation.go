@@ -0,0 +1,93 @@
+// Package domain - Moderación de contenido (Llama Guard)
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ============================================================================
+// MODERACIÓN DE CONTENIDO
+// ============================================================================
+//
+// Llama Guard es un modelo que clasifica una conversación como "safe" o
+// "unsafe" y, si es insegura, con qué categorías de la taxonomía de riesgos
+// de MLCommons (S1, S2...). La moderación se puede aplicar a la entrada
+// (antes de llamar al modelo) y a la salida (antes de entregar la respuesta).
+//
+// Un contenido marcado no es un fallo del servidor: es un 422 con las
+// categorías, para que el cliente pueda explicar al usuario el motivo.
+// ============================================================================
+
+// Momentos en los que se modera
+const (
+	ModerationInput  = "input"
+	ModerationOutput = "output"
+)
+
+// LlamaGuardCategories traduce los códigos de Llama Guard a etiquetas
+// estables (las que ve el cliente y las de MODERATION_CATEGORIES)
+var LlamaGuardCategories = map[string]string{
+	"S1":  "violent_crimes",
+	"S2":  "non_violent_crimes",
+	"S3":  "sex_related_crimes",
+	"S4":  "child_sexual_exploitation",
+	"S5":  "defamation",
+	"S6":  "specialized_advice",
+	"S7":  "privacy",
+	"S8":  "intellectual_property",
+	"S9":  "indiscriminate_weapons",
+	"S10": "hate",
+	"S11": "suicide_self_harm",
+	"S12": "sexual_content",
+	"S13": "elections",
+	"S14": "code_interpreter_abuse",
+}
+
+// ModerationCategoryLabel retorna la etiqueta de un código de Llama Guard
+// Un código desconocido (de una versión nueva del modelo) se devuelve tal cual
+func ModerationCategoryLabel(code string) string {
+	if label, ok := LlamaGuardCategories[strings.ToUpper(code)]; ok {
+		return label
+	}
+	return code
+}
+
+// IsModerationCategory indica si la etiqueta es una categoría conocida
+func IsModerationCategory(label string) bool {
+	for _, known := range LlamaGuardCategories {
+		if known == label {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrContentFlagged indica que la moderación marcó el contenido
+var ErrContentFlagged = errors.New("el contenido infringe la política de moderación")
+
+// ModerationError es un contenido marcado, con el momento y las categorías
+//
+// errors.Is(err, ErrContentFlagged) funciona gracias a Unwrap(); con
+// errors.As() se accede a las categorías.
+type ModerationError struct {
+	// Stage es ModerationInput o ModerationOutput
+	Stage string
+
+	// Categories son las etiquetas de las categorías infringidas
+	Categories []string
+}
+
+// Error implementa la interfaz error
+func (e *ModerationError) Error() string {
+	stage := "la petición"
+	if e.Stage == ModerationOutput {
+		stage = "la respuesta del modelo"
+	}
+	return ErrContentFlagged.Error() + " en " + stage + ": " + strings.Join(e.Categories, ", ")
+}
+
+// Unwrap permite que errors.Is() compare con ErrContentFlagged
+func (e *ModerationError) Unwrap() error {
+	return ErrContentFlagged
+}
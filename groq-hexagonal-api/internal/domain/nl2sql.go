@@ -0,0 +1,87 @@
+// Package domain - Traducción de lenguaje natural a SQL
+package domain
+
+// ============================================================================
+// ENTIDADES DE NL2SQL
+// ============================================================================
+//
+// El modelo escribe la consulta a partir de la pregunta y del esquema que
+// envía el cliente; la API no se conecta a ninguna base de datos. Antes de
+// retornarla, un analizador (puerto SQLAnalyzer) comprueba que es UNA
+// sentencia bien formada y si modifica datos o esquema: las que lo hacen se
+// rechazan salvo que la petición lo permita.
+// ============================================================================
+
+// Dialectos SQL admitidos
+const (
+	SQLDialectPostgreSQL = "postgresql"
+	SQLDialectMySQL      = "mysql"
+	SQLDialectSQLite     = "sqlite"
+	SQLDialectSQLServer  = "sqlserver"
+)
+
+// SQLDialects son los dialectos admitidos (el primero es el por defecto)
+var SQLDialects = []string{SQLDialectPostgreSQL, SQLDialectMySQL, SQLDialectSQLite, SQLDialectSQLServer}
+
+// IsSQLDialect indica si dialect es un dialecto admitido
+func IsSQLDialect(dialect string) bool {
+	for _, known := range SQLDialects {
+		if known == dialect {
+			return true
+		}
+	}
+	return false
+}
+
+// MaxSQLSchemaChars limita el esquema que se envía al modelo
+const MaxSQLSchemaChars = 50000
+
+// NL2SQLRequest es la entrada del caso de uso de traducir una pregunta a SQL
+type NL2SQLRequest struct {
+	// Question es la pregunta en lenguaje natural
+	Question string
+
+	// Schema describe las tablas (normalmente, sus CREATE TABLE)
+	Schema string
+
+	// Dialect es el dialecto SQL (vacío = postgresql)
+	Dialect string
+
+	// AllowMutations permite sentencias que modifican datos o esquema
+	// (INSERT, UPDATE, DELETE, DDL...). Por defecto solo se aceptan lecturas
+	AllowMutations bool
+
+	// Model es el modelo a usar (vacío = modelo por defecto)
+	Model string
+}
+
+// SQLAnalysis es lo que el analizador sabe de una sentencia
+type SQLAnalysis struct {
+	// StatementType es la operación en minúsculas (ej: "select", "delete")
+	StatementType string
+
+	// Mutating indica si la sentencia modifica datos o esquema (o bloquea
+	// filas, como SELECT ... FOR UPDATE)
+	Mutating bool
+
+	// Tables son las tablas que lee o escribe, sin repetir
+	Tables []string
+}
+
+// NL2SQLResult es la consulta generada y validada
+type NL2SQLResult struct {
+	// SQL es la sentencia generada
+	SQL string `json:"sql"`
+
+	// Explanation explica brevemente qué hace la consulta
+	Explanation string `json:"explanation"`
+
+	// Analysis es el resultado del analizador
+	Analysis SQLAnalysis `json:"-"`
+
+	// Model es el modelo que generó la consulta
+	Model string `json:"-"`
+
+	// Usage es el consumo de tokens de la petición
+	Usage Usage `json:"-"`
+}
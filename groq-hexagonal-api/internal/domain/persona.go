@@ -0,0 +1,30 @@
+// Package domain - Personas (plantillas de prompt con nombre)
+package domain
+
+import "errors"
+
+// ============================================================================
+// PERSONAS
+// ============================================================================
+//
+// Una persona es una plantilla de prompt con nombre ("soporte", "tutor-go"...)
+// que el cliente elige con el campo "persona" en lugar de enviar el system
+// prompt completo. Las personas viven fuera del código (ej: un repositorio
+// Git), así que cambiar un prompt pasa por code review y se despliega solo.
+// ============================================================================
+
+// ErrPersonaNotFound indica que la persona pedida no existe
+var ErrPersonaNotFound = errors.New("la persona no existe")
+
+// Persona es una plantilla de prompt con sus ajustes
+type Persona struct {
+	// Name identifica la persona (ej: "soporte")
+	Name string `json:"name"`
+
+	// SystemPrompt son las instrucciones de sistema de la persona
+	SystemPrompt string `json:"system_prompt"`
+
+	// Model y Temperature son opcionales: se usan si el cliente no envía los suyos
+	Model       string   `json:"model,omitempty"`
+	Temperature *float64 `json:"temperature,omitempty"`
+}
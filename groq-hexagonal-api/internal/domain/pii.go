@@ -0,0 +1,107 @@
+// Package domain - Detección y anonimización de datos personales (PII)
+package domain
+
+import "time"
+
+// ============================================================================
+// DATOS PERSONALES
+// ============================================================================
+//
+// Un PIIDetector (puerto secundario) encuentra las entidades con datos
+// personales de un texto; el RedactionService (puerto primario) decide qué
+// tipos se anonimizan y las sustituye por marcadores como [EMAIL_1]. El mismo
+// valor recibe siempre el mismo marcador dentro de un texto, así que el texto
+// anonimizado conserva quién es quién ("[EMAIL_1] escribió a [EMAIL_2]") y se
+// puede reenviar a un modelo sin perder el sentido.
+// ============================================================================
+
+// Tipos de entidad con datos personales
+const (
+	PIIEmail      = "email"
+	PIIPhone      = "phone"
+	PIICreditCard = "credit_card"
+	PIIIBAN       = "iban"
+	PIINationalID = "national_id" // DNI o NIE
+	PIIIPAddress  = "ip_address"
+)
+
+// PIITypes son los tipos de entidad que se detectan
+var PIITypes = []string{PIIEmail, PIIPhone, PIICreditCard, PIIIBAN, PIINationalID, PIIIPAddress}
+
+// IsPIIType indica si el tipo de entidad es conocido
+func IsPIIType(entityType string) bool {
+	for _, known := range PIITypes {
+		if entityType == known {
+			return true
+		}
+	}
+	return false
+}
+
+// PIIEntity es un dato personal encontrado en un texto
+type PIIEntity struct {
+	// Type es uno de los tipos de PIITypes
+	Type string
+
+	// Text es el valor tal como aparece en el texto
+	Text string
+
+	// Start y End son la posición en el texto, en bytes (text[Start:End])
+	Start int
+	End   int
+}
+
+// RedactionRequest es la entrada del caso de uso de anonimizar un texto
+type RedactionRequest struct {
+	// Text es el texto a anonimizar
+	Text string
+
+	// Types son los tipos a anonimizar (vacío = todos)
+	Types []string
+}
+
+// RedactedEntity es un dato personal sustituido por su marcador
+type RedactedEntity struct {
+	Type        string
+	Text        string
+	Placeholder string
+}
+
+// RedactionResult es el texto anonimizado y lo que se ha sustituido
+type RedactionResult struct {
+	// Text es el texto con los datos personales sustituidos
+	Text string
+
+	// Entities son las entidades sustituidas, en orden de aparición
+	Entities []RedactedEntity
+}
+
+// RedactionAudit es el registro de lo anonimizado en una petición antes de
+// enviarla al proveedor. No lleva los valores originales: el registro de
+// auditoría no debe convertirse en otra copia de los datos personales
+type RedactionAudit struct {
+	// Tenant es el de la petición (ver tenant.go)
+	Tenant string
+
+	// Source es el origen de la petición: "chat" (cualquier llamada de los
+	// servicios) o "proxy" (body reenviado por /api/v1/proxy)
+	Source string
+
+	// Model es el modelo de la petición
+	Model string
+
+	// Entities son los datos sustituidos, en orden de aparición
+	Entities []RedactionAuditEntry
+
+	// At es el momento de la anonimización
+	At time.Time
+}
+
+// RedactionAuditEntry es un dato sustituido en una petición
+type RedactionAuditEntry struct {
+	Type        string
+	Placeholder string
+
+	// Message es la posición del mensaje en la petición (desde 0)
+	Message int
+}
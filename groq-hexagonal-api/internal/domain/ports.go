@@ -1,7 +1,11 @@
 // Package domain - Continuación con las interfaces (Ports)
 package domain
 
-import "context"
+import (
+	"context"
+	"io"
+	"time"
+)
 
 // ============================================================================
 // PORTS (INTERFACES)
@@ -23,21 +27,585 @@ type ChatService interface {
 	// SendMessage envía un mensaje y obtiene respuesta del modelo
 	// context.Context permite cancelaciones, timeouts y propagación de valores
 	// error es el tipo estándar de Go para manejar errores
-	SendMessage(ctx context.Context, message string, model string) (*ChatResponse, error)
+	// opts contiene los parámetros opcionales (temperatura, stop, etc.)
+	SendMessage(ctx context.Context, message string, model string, opts MessageOptions) (*ChatResponse, error)
+	
+	// StreamMessage es como SendMessage pero retorna la respuesta por fragmentos
+	// El llamador debe cerrar el ChatStream cuando termine
+	StreamMessage(ctx context.Context, message string, model string, opts MessageOptions) (ChatStream, error)
+	
+	// DryRun prepara la petición como SendMessage pero sin enviarla a Groq
+	// Retorna lo que se habría enviado y la estimación de tokens y coste
+	DryRun(ctx context.Context, message string, model string, opts MessageOptions) (*DryRunResult, error)
 	
 	// GetAvailableModels obtiene la lista de modelos disponibles
 	GetAvailableModels(ctx context.Context) (*ModelsResponse, error)
 }
 
-// GroqRepository define cómo accedemos a la API de Groq
+// ConversationService define los casos de uso de conversaciones multi-turno
+// Es un PUERTO PRIMARIO, igual que ChatService
+type ConversationService interface {
+	// CreateConversation crea una conversación vacía con los ajustes fijados
+	// Todos los ajustes son opcionales (sin modelo se usa el default)
+	CreateConversation(ctx context.Context, settings ConversationSettings) (*Conversation, error)
+	
+	// SendMessage añade un mensaje del usuario, envía el historial completo
+	// al modelo y guarda su respuesta en la conversación
+	// model y opts sobrescriben los ajustes fijados solo para este mensaje
+	SendMessage(ctx context.Context, conversationID string, message string, model string, opts MessageOptions) (*Conversation, *ChatResponse, error)
+	
+	// PinSettings cambia los ajustes fijados (los campos vacíos no cambian)
+	PinSettings(ctx context.Context, conversationID string, settings ConversationSettings) (*Conversation, error)
+	
+	// GetConversation obtiene una conversación con todo su historial
+	GetConversation(ctx context.Context, conversationID string) (*Conversation, error)
+	
+	// DeleteConversation borra la conversación de forma recuperable
+	DeleteConversation(ctx context.Context, conversationID string) (*Conversation, error)
+	
+	// RestoreConversation recupera una conversación borrada dentro del plazo
+	RestoreConversation(ctx context.Context, conversationID string) (*Conversation, error)
+	
+	// PurgeDeleted elimina definitivamente las conversaciones cuyo plazo
+	// de retención ha pasado. Retorna cuántas se eliminaron
+	PurgeDeleted(ctx context.Context) (int, error)
+}
+
+// JobService define los casos de uso de los chats asíncronos
+// Es un PUERTO PRIMARIO
+type JobService interface {
+	// Submit encola la petición y retorna el job en estado pending sin
+	// esperar al modelo. Retorna ErrJobQueueFull si la cola está llena
+	Submit(ctx context.Context, request JobRequest) (*Job, error)
+	
+	// GetJob retorna el job del cliente (ErrJobNotFound si no es suyo)
+	GetJob(ctx context.Context, client string, id string) (*Job, error)
+	
+	// OpenResult abre la respuesta (JSON) del job del cliente, guardada
+	// aparte o no. Retorna ErrJobResultUnavailable si no ha terminado con
+	// éxito; el llamador debe cerrar el reader
+	OpenResult(ctx context.Context, client string, id string) (*Job, io.ReadSeekCloser, error)
+}
+
+// PromptService define los casos de uso de ayuda a la escritura de prompts
+// Es un PUERTO PRIMARIO
+type PromptService interface {
+	// ImprovePrompt analiza un prompt y propone mejoras y variantes
+	ImprovePrompt(ctx context.Context, request PromptImprovementRequest) (*PromptImprovement, error)
+}
+
+// ClassificationService define el caso de uso de clasificar textos
+// Es un PUERTO PRIMARIO
+type ClassificationService interface {
+	// Classify elige la etiqueta que mejor describe el texto
+	Classify(ctx context.Context, request ClassificationRequest) (*Classification, error)
+}
+
+// NL2SQLService define el caso de uso de traducir preguntas a SQL
+// Es un PUERTO PRIMARIO
+type NL2SQLService interface {
+	// Translate genera la consulta y la valida antes de retornarla
+	Translate(ctx context.Context, request NL2SQLRequest) (*NL2SQLResult, error)
+}
+
+// CodeService define los casos de uso de ayuda con código
+// Es un PUERTO PRIMARIO
+type CodeService interface {
+	// Explain explica qué hace el código, por fragmentos si es largo
+	Explain(ctx context.Context, request CodeRequest) (*CodeExplanation, error)
+	
+	// Review busca problemas en el código y los retorna con su línea
+	Review(ctx context.Context, request CodeRequest) (*CodeReview, error)
+}
+
+// RedactionService define el caso de uso de anonimizar datos personales
+// Es un PUERTO PRIMARIO
+type RedactionService interface {
+	// Redact sustituye los datos personales del texto por marcadores
+	Redact(ctx context.Context, request RedactionRequest) (*RedactionResult, error)
+}
+
+// ReplaySampler define el caso de uso de guardar una muestra de las
+// peticiones de producción para reproducirlas (ver replay.go)
+// Es un PUERTO PRIMARIO
+type ReplaySampler interface {
+	// ShouldSample decide si se guarda la petición que empieza
+	ShouldSample() bool
+
+	// Record anonimiza la muestra y la guarda en el corpus
+	Record(ctx context.Context, sample ReplaySample) error
+}
+
+// UsageService define el caso de uso de contar los tokens de cada cliente
+// y aplicar su cuota diaria
+// Es un PUERTO PRIMARIO
+type UsageService interface {
+	// Record suma el consumo de una petición al día de hoy del cliente
+	// (model sirve para estimar el coste)
+	Record(ctx context.Context, client string, model string, usage Usage) error
+
+	// Report retorna el consumo de hoy frente a la cuota y el de los
+	// historyDays días anteriores (0 = solo hoy)
+	Report(ctx context.Context, client string, historyDays int) (*UsageReport, error)
+
+	// Statement retorna el extracto del tenant en month (formato
+	// StatementMonthLayout)
+	Statement(ctx context.Context, tenant string, month string) (*TenantStatement, error)
+}
+
+// FeedbackService define el caso de uso de valorar las respuestas
+// Es un PUERTO PRIMARIO
+type FeedbackService interface {
+	// Submit valida y guarda la valoración (ErrInvalidFeedback si no es válida)
+	// Completa su ID, su tenant (el del contexto) y su fecha
+	Submit(ctx context.Context, feedback Feedback) (*Feedback, error)
+
+	// Summary resume las valoraciones del filtro
+	Summary(ctx context.Context, filter FeedbackFilter) (*FeedbackSummary, error)
+}
+
+// DiffService define el caso de uso de comparar dos configuraciones
+// Es un PUERTO PRIMARIO
+type DiffService interface {
+	// Compare envía el mismo mensaje a las dos variantes y compara las respuestas
+	Compare(ctx context.Context, request DiffRequest) (*DiffResult, error)
+}
+
+// ProxyService define el caso de uso del modo proxy (passthrough)
+// Es un PUERTO PRIMARIO
+type ProxyService interface {
+	// ChatCompletions reenvía el body a /chat/completions sin modificarlo
+	// Solo retorna error si la petición no llega al proveedor: sus errores
+	// HTTP se devuelven en ProxyResponse para reenviarlos tal cual
+	ChatCompletions(ctx context.Context, body []byte) (*ProxyResponse, error)
+}
+
+// TranscriptionService define el caso de uso de transcribir audio
+// Es un PUERTO PRIMARIO
+type TranscriptionService interface {
+	// Transcribe convierte el audio en texto con segmentos
+	Transcribe(ctx context.Context, request TranscriptionRequest) (*Transcription, error)
+}
+
+// ModelHealthService informa de la salud de los modelos
+// Es un PUERTO PRIMARIO (lo consulta el endpoint de administración)
+type ModelHealthService interface {
+	// GetModelsHealth retorna la salud de cada modelo usado recientemente
+	GetModelsHealth(ctx context.Context) []ModelHealth
+}
+
+// BackendHealthService informa del estado de los destinos de un enrutador
+// (ej: cada API key de Groq)
+// Es un PUERTO PRIMARIO (lo consulta el endpoint de administración)
+type BackendHealthService interface {
+	// GetBackendsHealth retorna el estado de cada destino, en su orden
+	GetBackendsHealth() []BackendHealth
+}
+
+// SettingsService da los ajustes vigentes y los recarga
+// Es un PUERTO PRIMARIO (lo usan la señal SIGHUP y el endpoint de administración)
+type SettingsService interface {
+	SettingsSource
+
+	// Reload vuelve a leer la configuración y aplica los ajustes nuevos
+	// Si la configuración no es válida se mantienen los anteriores
+	Reload(ctx context.Context) (RuntimeSettings, error)
+}
+
+// SettingsSource da los ajustes vigentes a quien los lee en cada petición
+// (el servicio de chat, los clientes de los proveedores, el rate limit)
+type SettingsSource interface {
+	// Settings retorna los ajustes vigentes
+	Settings() RuntimeSettings
+}
+
+// ResponseCacheService expone el estado de la caché de respuestas
+// Es un PUERTO PRIMARIO (lo consulta el endpoint de administración)
+type ResponseCacheService interface {
+	// Stats retorna el uso de la caché desde el arranque
+	Stats(ctx context.Context) (ResponseCacheStats, error)
+
+	// Flush elimina todas las respuestas guardadas y retorna cuántas eran
+	Flush(ctx context.Context) (int, error)
+}
+
+// APIKeyService gestiona las API keys de los clientes y autentica las
+// peticiones
+// Es un PUERTO PRIMARIO (lo usan el endpoint de administración y el
+// middleware de autenticación)
+type APIKeyService interface {
+	// CreateKey crea una clave y retorna también su secreto (solo se
+	// muestra esta vez)
+	CreateKey(ctx context.Context, spec APIKeySpec) (*APIKey, string, error)
+
+	// ListKeys retorna todas las claves, activas o no
+	ListKeys(ctx context.Context) ([]APIKey, error)
+
+	// DisableKey desactiva la clave (deja de valer al momento)
+	DisableKey(ctx context.Context, id string) (*APIKey, error)
+
+	// RotateKey cambia el secreto de la clave y retorna el nuevo; el
+	// anterior sigue valiendo durante un plazo de gracia. expiresAt es la
+	// nueva caducidad (nil = se mantiene)
+	RotateKey(ctx context.Context, id string, expiresAt *time.Time) (*APIKey, string, error)
+
+	// Authenticate retorna la clave del secreto o de un token emitido con
+	// IssueToken (ErrInvalidAPIKey si no existe, está desactivada o ha
+	// caducado)
+	Authenticate(ctx context.Context, secret string) (*APIKey, error)
+
+	// IssueToken emite un token de vida corta con parte de los scopes de la
+	// clave (ErrInsufficientScope si pide alguno que la clave no tiene)
+	IssueToken(ctx context.Context, key *APIKey, spec TokenSpec) (*ScopedToken, error)
+
+	// NotifyExpirations emite los eventos de las claves que están cerca de
+	// caducar o han caducado y aún no se habían avisado. Retorna cuántos
+	NotifyExpirations(ctx context.Context) (int, error)
+}
+
+// LLMRepository define cómo accedemos a un proveedor de modelos (Groq,
+// OpenAI, Ollama...)
 // Esta es una interfaz de PUERTO SECUNDARIO (driven port)
 // Los puertos secundarios son implementados por adaptadores externos
-type GroqRepository interface {
+type LLMRepository interface {
 	// CreateChatCompletion realiza una petición de chat completion
 	CreateChatCompletion(ctx context.Context, request ChatRequest) (*ChatResponse, error)
 	
+	// CreateChatCompletionStream realiza una petición de chat en modo streaming
+	CreateChatCompletionStream(ctx context.Context, request ChatRequest) (ChatStream, error)
+	
 	// ListModels obtiene todos los modelos disponibles
 	ListModels(ctx context.Context) (*ModelsResponse, error)
+	
+	// ProxyChatCompletion envía un body ya serializado a /chat/completions
+	// y retorna la respuesta sin procesar (stream indica si es SSE)
+	ProxyChatCompletion(ctx context.Context, body []byte, stream bool) (*ProxyResponse, error)
+}
+
+// TranscriptionRepository define cómo accedemos a un modelo de transcripción
+// (ej: Whisper en Groq)
+// Es un PUERTO SECUNDARIO
+type TranscriptionRepository interface {
+	// CreateTranscription envía el audio al proveedor y retorna el texto
+	CreateTranscription(ctx context.Context, request TranscriptionRequest) (*Transcription, error)
+}
+
+// EmbeddingRepository convierte textos en embeddings (vectores)
+// Es un PUERTO SECUNDARIO
+type EmbeddingRepository interface {
+	// CreateEmbeddings retorna un vector por texto, en el mismo orden
+	CreateEmbeddings(ctx context.Context, model string, texts []string) ([][]float64, error)
+}
+
+// ConversationRepository define cómo se guardan las conversaciones
+// Es un PUERTO SECUNDARIO: puede implementarse en memoria, en una base de datos...
+type ConversationRepository interface {
+	// Save crea o reemplaza la conversación
+	Save(ctx context.Context, conversation *Conversation) error
+	
+	// FindByID retorna ErrConversationNotFound si no existe
+	// Las conversaciones borradas (pero no purgadas) también se retornan
+	FindByID(ctx context.Context, id string) (*Conversation, error)
+	
+	// PurgeDeleted elimina las conversaciones borradas con PurgeAt <= now
+	PurgeDeleted(ctx context.Context, now time.Time) (int, error)
+}
+
+// RateCounter cuenta peticiones por clave en ventanas de tiempo fijas
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o compartido (ej: Redis)
+type RateCounter interface {
+	// Increment suma una petición a la ventana actual de key
+	// Retorna las peticiones de la ventana (incluida esta) y cuánto falta
+	// para que se reinicie
+	Increment(ctx context.Context, key string, window time.Duration) (count int64, resetIn time.Duration, err error)
+}
+
+// LoadShedder indica si la réplica está sobrecargada y debe rechazar
+// peticiones nuevas hasta recuperarse
+// Es un PUERTO SECUNDARIO: la implementación puede medir la memoria, la CPU
+// o las peticiones en curso
+type LoadShedder interface {
+	// Overloaded se consulta en cada petición: debe ser barato
+	Overloaded() bool
+}
+
+// LivenessMonitor vigila que las tareas largas (peticiones, jobs) terminen
+// Es un PUERTO SECUNDARIO: la implementación decide qué hacer con las que se
+// cuelgan (registrarlas, contarlas...)
+type LivenessMonitor interface {
+	// Begin registra una tarea que debería durar como mucho timeout (0 = el
+	// plazo por defecto del monitor); done la da por terminada
+	Begin(name string, timeout time.Duration) (done func())
+
+	// Stats retorna las tareas en curso y las colgadas
+	Stats() LivenessStats
+}
+
+// JobRepository define cómo se guardan los jobs de chat asíncronos
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o compartido (ej: Redis,
+// PostgreSQL)
+type JobRepository interface {
+	// SaveJob crea o reemplaza el job; se elimina al llegar a ExpiresAt
+	SaveJob(ctx context.Context, job *Job) error
+	
+	// FindJob retorna ErrJobNotFound si no existe o ya caducó
+	FindJob(ctx context.Context, id string) (*Job, error)
+}
+
+// JobResultStore guarda las respuestas grandes de los jobs fuera del
+// repositorio de jobs
+// Es un PUERTO SECUNDARIO: en disco (un directorio, compartido o no entre
+// réplicas) o en un almacenamiento de objetos
+type JobResultStore interface {
+	// SaveResult guarda lo que write escribe como resultado del job (sin
+	// cargarlo entero en memoria) y retorna su tamaño en bytes
+	SaveResult(ctx context.Context, jobID string, write func(w io.Writer) error) (int64, error)
+	
+	// OpenResult abre el resultado guardado; el llamador debe cerrarlo
+	// Retorna ErrJobNotFound si no existe (o ya caducó)
+	OpenResult(ctx context.Context, jobID string) (io.ReadSeekCloser, error)
+}
+
+// JobNotifier entrega un job terminado a la URL de su callback
+// Es un PUERTO SECUNDARIO: hace un solo intento (los reintentos son de la
+// aplicación) y retorna error si la entrega no se confirmó
+type JobNotifier interface {
+	NotifyJob(ctx context.Context, job *Job, attempt int) error
+}
+
+// APIKeyNotifier avisa de la caducidad de las API keys (ej: un webhook)
+// Es un PUERTO SECUNDARIO: retorna error si el aviso no se entregó (se
+// reintenta en la siguiente pasada)
+type APIKeyNotifier interface {
+	NotifyAPIKey(ctx context.Context, event APIKeyEvent) error
+}
+
+// TokenSigner firma y verifica los tokens de vida corta (ej: JWT)
+// Es un PUERTO SECUNDARIO: VerifyToken solo comprueba el formato y la firma;
+// la caducidad y la clave las comprueba la aplicación
+type TokenSigner interface {
+	SignToken(claims TokenClaims) (string, error)
+	VerifyToken(token string) (*TokenClaims, error)
+}
+
+// APIKeyRepository guarda las API keys de los clientes (solo sus hashes)
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o compartido (ej: Redis,
+// PostgreSQL)
+type APIKeyRepository interface {
+	// SaveAPIKey crea o reemplaza la clave
+	SaveAPIKey(ctx context.Context, key *APIKey) error
+
+	// FindAPIKey retorna ErrAPIKeyNotFound si no existe
+	FindAPIKey(ctx context.Context, id string) (*APIKey, error)
+
+	// FindAPIKeyByHash busca la clave cuyo Hash o PreviousHash es hash
+	// (ErrAPIKeyNotFound si no hay ninguna)
+	FindAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+
+	// ListAPIKeys retorna todas las claves, de la más antigua a la más nueva
+	ListAPIKeys(ctx context.Context) ([]APIKey, error)
+}
+
+// UsageRepository acumula los tokens consumidos por cliente y día
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o compartido (ej: Redis,
+// PostgreSQL)
+type UsageRepository interface {
+	// AddUsage suma usage al consumo del cliente en day (formato UsageDayLayout)
+	AddUsage(ctx context.Context, client string, day string, usage Usage) error
+
+	// GetUsage retorna el consumo del cliente en day (cero si no hay)
+	GetUsage(ctx context.Context, client string, day string) (Usage, error)
+
+	// AddTenantUsage suma una petición al consumo del tenant en month
+	// (formato StatementMonthLayout), por modelo y por conversación
+	AddTenantUsage(ctx context.Context, tenant string, month string, record TenantUsageRecord) error
+
+	// GetTenantUsage retorna el consumo del tenant en month (vacío si no hay)
+	GetTenantUsage(ctx context.Context, tenant string, month string) (*TenantUsage, error)
+}
+
+// FeedbackRepository guarda las valoraciones de las respuestas
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o compartido (ej: Redis,
+// PostgreSQL)
+type FeedbackRepository interface {
+	// SaveFeedback guarda la valoración
+	SaveFeedback(ctx context.Context, feedback *Feedback) error
+
+	// ListFeedback retorna las valoraciones del filtro, de la más antigua a
+	// la más reciente
+	ListFeedback(ctx context.Context, filter FeedbackFilter) ([]Feedback, error)
+}
+
+// ResponseCache guarda respuestas del modelo por clave durante un tiempo
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o compartida (ej: Redis)
+type ResponseCache interface {
+	// Get retorna la respuesta guardada o nil si no existe o caducó
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+
+	// Set guarda la respuesta durante ttl
+	Set(ctx context.Context, key string, entry CachedResponse, ttl time.Duration) error
+
+	// Len retorna el número de respuestas guardadas
+	Len(ctx context.Context) (int, error)
+
+	// Flush elimina todas las respuestas y retorna cuántas eran
+	Flush(ctx context.Context) (int, error)
+}
+
+// RouteCache guarda respuestas HTTP enteras de las rutas con política de
+// caché (ver route_cache.go)
+// Es un PUERTO SECUNDARIO, igual que ResponseCache
+type RouteCache interface {
+	// Get retorna la respuesta guardada o nil si no existe o caducó
+	Get(ctx context.Context, key string) (*CachedRoute, error)
+
+	// Set guarda la respuesta durante ttl
+	Set(ctx context.Context, key string, entry CachedRoute, ttl time.Duration) error
+}
+
+// SemanticCache guarda respuestas junto al embedding de su pregunta
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o en una base de datos
+// vectorial
+type SemanticCache interface {
+	// Search retorna la respuesta de scope con la pregunta más parecida a
+	// vector, o nil si ninguna llega a minSimilarity
+	Search(ctx context.Context, scope string, vector []float64, minSimilarity float64) (*SemanticMatch, error)
+
+	// Add guarda la respuesta durante ttl
+	Add(ctx context.Context, scope string, vector []float64, entry CachedResponse, ttl time.Duration) error
+
+	// Len retorna el número de respuestas guardadas
+	Len(ctx context.Context) (int, error)
+
+	// Flush elimina todas las respuestas y retorna cuántas eran
+	Flush(ctx context.Context) (int, error)
+}
+
+// IdempotencyStore guarda las respuestas de las peticiones con
+// Idempotency-Key durante un tiempo
+// Es un PUERTO SECUNDARIO: en memoria (una réplica) o compartido (ej: Redis)
+type IdempotencyStore interface {
+	// Reserve guarda entry (en curso) durante ttl si la clave no existe y
+	// retorna nil; si existe, retorna lo guardado sin cambiar nada
+	Reserve(ctx context.Context, key string, entry IdempotentResponse, ttl time.Duration) (*IdempotentResponse, error)
+
+	// Complete sustituye la reserva por la respuesta final, durante ttl
+	Complete(ctx context.Context, key string, entry IdempotentResponse, ttl time.Duration) error
+
+	// Release elimina la reserva: la petición falló y se puede reintentar
+	Release(ctx context.Context, key string) error
+}
+
+// LanguageDetector detecta el idioma de un texto
+// Es un PUERTO SECUNDARIO: la implementación puede ser una heurística local
+// o un servicio externo
+type LanguageDetector interface {
+	// Detect retorna el código ISO 639-1 (ej: "es", "en") o "" si no lo sabe
+	Detect(text string) string
+}
+
+// CodeLanguageDetector detecta el lenguaje de programación de un fichero
+// Es un PUERTO SECUNDARIO, igual que LanguageDetector
+type CodeLanguageDetector interface {
+	// Detect retorna el lenguaje en minúsculas (ej: "go", "python") o "" si
+	// no lo sabe. filename puede estar vacío
+	Detect(filename string, code string) string
+}
+
+// PIIDetector encuentra datos personales en un texto
+// Es un PUERTO SECUNDARIO: la implementación puede ser un conjunto de
+// expresiones regulares o un servicio externo de reconocimiento de entidades
+type PIIDetector interface {
+	// Detect retorna las entidades encontradas (pueden solaparse)
+	Detect(text string) []PIIEntity
+}
+
+// RedactionAuditor registra los datos personales anonimizados antes de
+// llamar al proveedor
+// Es un PUERTO SECUNDARIO: la implementación puede escribir en el log, en una
+// tabla de auditoría o en un SIEM
+type RedactionAuditor interface {
+	// RecordRedaction registra una petición anonimizada
+	// Los errores son cosa de la implementación: no deben frenar la petición
+	RecordRedaction(ctx context.Context, audit RedactionAudit)
+}
+
+// SQLAnalyzer analiza sentencias SQL
+// Es un PUERTO SECUNDARIO: la implementación puede ser un analizador léxico
+// propio o el parser de un motor concreto
+type SQLAnalyzer interface {
+	// Analyze retorna un error si sql no es exactamente una sentencia bien
+	// formada (comillas y paréntesis cerrados)
+	Analyze(sql string) (*SQLAnalysis, error)
+}
+
+// DeprecationReporter publica los avisos de modelos retirados
+// Es un PUERTO SECUNDARIO: puede escribir en el log, enviar métricas,
+// abrir una alerta...
+type DeprecationReporter interface {
+	ReportDecommissioned(ctx context.Context, event DecommissionEvent)
+}
+
+// DeadLetterReporter registra las entregas de callbacks que agotaron sus intentos
+// Es un PUERTO SECUNDARIO: puede escribir en el log, guardarlas para
+// reenviarlas, abrir una alerta...
+type DeadLetterReporter interface {
+	ReportDeadLetter(ctx context.Context, letter DeadLetter)
+}
+
+// PersonaSource carga las personas desde su origen (repositorio Git, disco...)
+// Es un PUERTO SECUNDARIO
+type PersonaSource interface {
+	// Load retorna todas las personas, indexadas por nombre
+	// Se llama periódicamente: debe reflejar los cambios del origen
+	Load(ctx context.Context) (map[string]Persona, error)
+}
+
+// RateLimitObserver recibe el margen de rate limit que anuncia el proveedor
+// Lo implementa la aplicación y lo llaman los adaptadores en cada respuesta
+// (así la aplicación no depende de las cabeceras de ningún proveedor)
+type RateLimitObserver interface {
+	ObserveRateLimit(model string, status RateLimitStatus)
+}
+
+// UsageRecorder registra el consumo de tokens
+// Es un PUERTO SECUNDARIO: puede escribir en el log, en una base de datos...
+type UsageRecorder interface {
+	RecordUsage(ctx context.Context, record UsageRecord)
+}
+
+// UsageEventPublisher emite los eventos de consumo (ej: a un subject de NATS)
+// Es un PUERTO SECUNDARIO: no retorna error ni bloquea al que registra el
+// consumo; los fallos los registra el adaptador
+type UsageEventPublisher interface {
+	PublishUsage(ctx context.Context, event UsageEvent)
+}
+
+// ReplayCorpus guarda las muestras de peticiones para reproducirlas
+// Es un PUERTO SECUNDARIO: puede ser un directorio, un bucket...
+type ReplayCorpus interface {
+	// SaveSample guarda la muestra (ErrReplayCorpusFull si ya no caben más)
+	SaveSample(ctx context.Context, sample *ReplaySample) error
+}
+
+// RequestHook es un punto de extensión: se ejecuta con cada petición de chat
+// ya construida (tras todas las políticas), antes de enviarla al modelo
+// Lo implementa quien despliega la API (ver hooks.go)
+type RequestHook interface {
+	// BeforeRequest puede modificar la petición (ej: elegir el modelo según
+	// una cabecera) o rechazarla retornando un error
+	// También se ejecuta en los dry run, para que muestren la petición real:
+	// los efectos (ej: descontar cuota) van mejor en un ResponseHook
+	BeforeRequest(ctx context.Context, request *ChatRequest) error
+}
+
+// ResponseHook es un punto de extensión: se ejecuta con cada respuesta del
+// modelo (ej: facturación propia, auditoría)
+// Lo implementa quien despliega la API (ver hooks.go)
+type ResponseHook interface {
+	// AfterResponse recibe la petición enviada y la respuesta final
+	// En streaming se llama al cerrar el flujo, con el texto acumulado
+	AfterResponse(ctx context.Context, request ChatRequest, response *ChatResponse)
 }
 
 // ============================================================================
@@ -93,7 +661,7 @@ type GroqRepository interface {
 //      → Ejemplo: ChatService (casos de uso que los handlers invocan)
 //    
 //    - Puertos Secundarios (Driven): Definen qué necesita la aplicación
-//      → Ejemplo: GroqRepository (cómo acceder a recursos externos)
+//      → Ejemplo: LLMRepository (cómo acceder a recursos externos)
 //
 //    El DOMINIO define las interfaces
 //    La INFRAESTRUCTURA las implementa
@@ -105,10 +673,10 @@ type GroqRepository interface {
 //
 //    Ejemplo:
 //    type Service struct {
-//        repo GroqRepository // Dependencia (interfaz)
+//        repo LLMRepository // Dependencia (interfaz)
 //    }
 //    
-//    func NewService(repo GroqRepository) *Service {
+//    func NewService(repo LLMRepository) *Service {
 //        return &Service{repo: repo}
 //    }
 //
@@ -122,14 +690,14 @@ type GroqRepository interface {
 //
 // // Implementación del servicio (capa de aplicación)
 // type chatServiceImpl struct {
-//     groqRepo GroqRepository  // Dependencia inyectada
+//     llmRepo LLMRepository  // Dependencia inyectada
 // }
 //
 // func (s *chatServiceImpl) SendMessage(ctx context.Context, message string, model string) (*ChatResponse, error) {
 //     request := NewChatRequest(model, []ChatMessage{
 //         NewChatMessage("user", message),
 //     })
-//     return s.groqRepo.CreateChatCompletion(ctx, request)
+//     return s.llmRepo.CreateChatCompletion(ctx, request)
 // }
 //
 // // Implementación del repositorio (capa de infraestructura)
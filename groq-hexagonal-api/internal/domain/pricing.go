@@ -0,0 +1,59 @@
+// Package domain - Precios de los modelos y coste de las peticiones
+package domain
+
+// ============================================================================
+// PRECIOS
+// ============================================================================
+//
+// El coste de una petición se calcula con los tokens que devuelve el
+// proveedor y el precio del modelo. Es una estimación: los precios cambian y
+// el proveedor puede aplicar descuentos (caché de prompts, lotes...), así que
+// sirve para presupuestar, no para conciliar facturas.
+// ============================================================================
+
+// tokensPerPriceUnit son los tokens a los que se refiere el precio
+const tokensPerPriceUnit = 1_000_000
+
+// ModelPrice es el precio de un modelo en USD por millón de tokens
+type ModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Cost calcula el coste en USD del consumo de una petición
+func (p ModelPrice) Cost(usage Usage) float64 {
+	return (float64(usage.PromptTokens)*p.Input + float64(usage.CompletionTokens)*p.Output) / tokensPerPriceUnit
+}
+
+// UsageCost calcula el coste del consumo con la tabla de precios
+// Retorna false si el modelo no tiene precio
+func UsageCost(prices map[string]ModelPrice, model string, usage Usage) (float64, bool) {
+	price, ok := prices[model]
+	if !ok {
+		return 0, false
+	}
+	return price.Cost(usage), true
+}
+
+// DefaultModelPricing son los precios publicados (USD por millón de tokens)
+// de los modelos más usados. Pueden quedar desfasados: se amplían o corrigen
+// con MODEL_PRICING.
+var DefaultModelPricing = map[string]ModelPrice{
+	// Groq
+	"llama-3.3-70b-versatile":                       {Input: 0.59, Output: 0.79},
+	"llama-3.1-8b-instant":                          {Input: 0.05, Output: 0.08},
+	"llama3-70b-8192":                               {Input: 0.59, Output: 0.79},
+	"llama3-8b-8192":                                {Input: 0.05, Output: 0.08},
+	"gemma2-9b-it":                                  {Input: 0.20, Output: 0.20},
+	"mixtral-8x7b-32768":                            {Input: 0.24, Output: 0.24},
+	"meta-llama/llama-4-scout-17b-16e-instruct":     {Input: 0.11, Output: 0.34},
+	"meta-llama/llama-4-maverick-17b-128e-instruct": {Input: 0.20, Output: 0.60},
+	"deepseek-r1-distill-llama-70b":                 {Input: 0.75, Output: 0.99},
+	"qwen/qwen3-32b":                                {Input: 0.29, Output: 0.59},
+	"openai/gpt-oss-120b":                           {Input: 0.15, Output: 0.75},
+	"openai/gpt-oss-20b":                            {Input: 0.10, Output: 0.50},
+
+	// OpenAI
+	"gpt-4o":      {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini": {Input: 0.15, Output: 0.60},
+}
@@ -0,0 +1,36 @@
+// Package domain - Mejora de prompts
+package domain
+
+// ============================================================================
+// ENTIDADES DE MEJORA DE PROMPTS
+// ============================================================================
+
+// PromptImprovementRequest es la entrada del caso de uso de mejorar un prompt
+type PromptImprovementRequest struct {
+	// Prompt es el texto que el equipo quiere mejorar
+	Prompt string
+
+	// DesiredOutcome describe qué se espera obtener con el prompt
+	DesiredOutcome string
+
+	// Variants es cuántas variantes alternativas generar
+	Variants int
+}
+
+// PromptImprovement es la propuesta de mejora generada por el modelo
+type PromptImprovement struct {
+	// ImprovedPrompt es la versión recomendada del prompt
+	ImprovedPrompt string `json:"improved_prompt"`
+
+	// Suggestions son los cambios sugeridos, explicados
+	Suggestions []string `json:"suggestions"`
+
+	// Variants son versiones alternativas para probar (A/B)
+	Variants []string `json:"variants"`
+
+	// Model es el modelo que generó la propuesta
+	Model string `json:"-"`
+
+	// Usage es el consumo de tokens de la meta-petición
+	Usage Usage `json:"-"`
+}
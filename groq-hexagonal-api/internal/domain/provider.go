@@ -0,0 +1,44 @@
+// Package domain - Proveedores de modelos
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ============================================================================
+// PROVEEDORES
+// ============================================================================
+//
+// La aplicación habla con los modelos a través de LLMRepository, así que el
+// proveedor (Groq, OpenAI, Ollama...) es un detalle de infraestructura. Cada
+// despliegue elige uno por defecto, y cada petición puede pedir otro con el
+// campo "provider".
+//
+// Igual que la clave de enrutado, el proveedor viaja en el context.Context:
+// lo fija el servicio de chat y lo lee el enrutador de proveedores.
+// ============================================================================
+
+// Nombres de los proveedores soportados
+const (
+	ProviderGroq   = "groq"
+	ProviderOpenAI = "openai"
+	ProviderOllama = "ollama"
+)
+
+// ErrUnknownProvider indica que el proveedor pedido no existe o no está configurado
+var ErrUnknownProvider = errors.New("el proveedor no existe o no está configurado")
+
+// providerKey es la clave privada para guardar el proveedor en el contexto
+type providerKey struct{}
+
+// WithProvider retorna un contexto derivado con el proveedor elegido
+func WithProvider(ctx context.Context, provider string) context.Context {
+	return context.WithValue(ctx, providerKey{}, provider)
+}
+
+// ProviderFromContext obtiene el proveedor elegido ("" = el por defecto)
+func ProviderFromContext(ctx context.Context) string {
+	provider, _ := ctx.Value(providerKey{}).(string)
+	return provider
+}
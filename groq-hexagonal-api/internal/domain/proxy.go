@@ -0,0 +1,30 @@
+// Package domain - Modo proxy (passthrough)
+package domain
+
+import "io"
+
+// ============================================================================
+// PROXY PASSTHROUGH
+// ============================================================================
+//
+// Para usuarios avanzados que necesitan parámetros que la API todavía no
+// modela (logprobs, response_format con schema, parámetros nuevos de Groq...),
+// el modo proxy reenvía el body tal cual a /chat/completions y devuelve la
+// respuesta de Groq sin tocar (incluidos sus errores y el streaming SSE).
+//
+// La petición sigue pasando por los middlewares del servidor, y el uso de
+// tokens se registra igual que en el resto de endpoints (UsageRecorder).
+// ============================================================================
+
+// ProxyResponse es la respuesta cruda del proveedor
+type ProxyResponse struct {
+	// StatusCode es el status HTTP de Groq (también en errores 4xx/5xx)
+	StatusCode int
+
+	// Header son las cabeceras que se pueden reenviar al cliente
+	// (Content-Type, x-ratelimit-*, retry-after...)
+	Header map[string][]string
+
+	// Body es el cuerpo sin procesar; el llamador debe cerrarlo
+	Body io.ReadCloser
+}
@@ -0,0 +1,56 @@
+// Package domain - Muestras de peticiones reales para reproducirlas
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// CORPUS DE REPRODUCCIÓN
+// ============================================================================
+//
+// Con muestreo activado, un porcentaje de las peticiones de chat de
+// producción se guarda (anonimizado) en un corpus: el body de la petición y
+// lo que se respondió (status, modelo, tokens, latencia y el texto). El
+// comando `replay` del cliente de terminal vuelve a enviarlas contra otra
+// versión de la API o con otro modelo y compara los resultados, para
+// detectar regresiones antes de desplegar.
+// ============================================================================
+
+// ErrReplayCorpusFull se retorna al guardar en un corpus que ha llegado a
+// su máximo de muestras
+var ErrReplayCorpusFull = errors.New("el corpus de reproducción está lleno")
+
+// ReplaySample es una petición guardada con su respuesta original
+type ReplaySample struct {
+	ID         string    `json:"id"`
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Path es la ruta de la petición (ej: "/api/v1/chat")
+	Path string `json:"path"`
+
+	// TenantID es el de la petición (se envía como X-Tenant-ID al reproducir)
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// Request es el body con los datos personales sustituidos por marcadores
+	Request json.RawMessage `json:"request"`
+
+	Baseline ReplayBaseline `json:"baseline"`
+}
+
+// ReplayBaseline es la respuesta original, con la que se compara
+type ReplayBaseline struct {
+	StatusCode int `json:"status_code"`
+
+	Model string `json:"model,omitempty"`
+
+	// Message es el texto de la respuesta, anonimizado (vacío en streaming
+	// y en los errores)
+	Message string `json:"message,omitempty"`
+
+	Usage *Usage `json:"usage,omitempty"`
+
+	LatencyMS int64 `json:"latency_ms"`
+}
@@ -0,0 +1,149 @@
+// Package domain - Caché de respuestas y control de frescura por petición
+package domain
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ DE RESPUESTAS
+// ============================================================================
+//
+// Las respuestas sin streaming se pueden guardar durante un tiempo (TTL) y
+// servir de nuevo si llega exactamente la misma petición del mismo tenant.
+//
+// El cliente controla la frescura con la semántica de Cache-Control:
+//
+//   no-cache   no usar la respuesta guardada (la nueva sí se guarda)
+//   no-store   ni usar ni guardar
+//   max-age=N  usar la respuesta guardada solo si tiene N segundos o menos
+//
+// Igual que el tenant, las directivas viajan en el context.Context: el
+// adaptador las extrae de la cabecera y la aplicación solo las lee.
+//
+// La caché semántica amplía la anterior: si no hay respuesta para la misma
+// petición, busca una cuya última pregunta se parezca lo suficiente (similitud
+// del coseno entre sus embeddings). El resto de la petición (modelo, system
+// prompt, historial, parámetros) tiene que ser idéntico.
+// ============================================================================
+
+// CacheStatus indica de dónde salió una respuesta (cabecera X-Cache)
+type CacheStatus string
+
+const (
+	// CacheHit: servida desde la caché, sin llamar al modelo
+	CacheHit CacheStatus = "HIT"
+
+	// CacheMiss: no había respuesta válida; se llamó al modelo
+	CacheMiss CacheStatus = "MISS"
+
+	// CacheBypass: el cliente pidió no usar la caché (no-cache, no-store o
+	// el override de depuración no_cache)
+	CacheBypass CacheStatus = "BYPASS"
+)
+
+// CachedResponse es una respuesta guardada en la caché
+type CachedResponse struct {
+	Response ChatResponse `json:"response"`
+	StoredAt time.Time    `json:"stored_at"`
+}
+
+// Age es el tiempo que lleva la respuesta en la caché
+func (c CachedResponse) Age(now time.Time) time.Duration {
+	if age := now.Sub(c.StoredAt); age > 0 {
+		return age
+	}
+	return 0
+}
+
+// SemanticMatch es la respuesta de la caché semántica más parecida
+type SemanticMatch struct {
+	Entry CachedResponse
+
+	// Similarity es la similitud del coseno entre las preguntas (hasta 1)
+	Similarity float64
+}
+
+// ResponseCacheStats es el estado de la caché de respuestas
+type ResponseCacheStats struct {
+	// Enabled indica si la caché está activa (RESPONSE_CACHE_TTL > 0)
+	Enabled bool
+
+	// Entries son las respuestas guardadas ahora mismo
+	Entries int
+
+	// Hits, Misses y Bypasses cuentan las búsquedas desde el arranque
+	// (en esta réplica, aunque la caché sea compartida)
+	Hits     int64
+	Misses   int64
+	Bypasses int64
+
+	// Semantic es el estado de la caché semántica (nil si está desactivada)
+	Semantic *SemanticCacheStats
+}
+
+// SemanticCacheStats es el estado de la caché semántica
+type SemanticCacheStats struct {
+	// Threshold es la similitud mínima para usar una respuesta
+	Threshold float64
+
+	// Entries son las respuestas guardadas ahora mismo
+	Entries int
+
+	// Hits y Misses cuentan las búsquedas (solo se busca tras un MISS de la
+	// caché exacta) y Errors las que no se hicieron porque falló el embedding
+	Hits   int64
+	Misses int64
+	Errors int64
+}
+
+// CacheControl son las directivas de caché de una petición
+type CacheControl struct {
+	// NoCache no usa la respuesta guardada
+	NoCache bool
+
+	// NoStore no usa ni guarda la respuesta
+	NoStore bool
+
+	// MaxAge es la edad máxima aceptada (nil = la de la caché)
+	MaxAge *time.Duration
+}
+
+// ParseCacheControl interpreta el valor de una cabecera Cache-Control
+// Las directivas desconocidas o mal formadas se ignoran
+func ParseCacheControl(value string) CacheControl {
+	var control CacheControl
+	for _, directive := range strings.Split(value, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "no-cache":
+			control.NoCache = true
+		case "no-store":
+			control.NoStore = true
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(arg), `"`))
+			if err == nil && seconds >= 0 {
+				maxAge := time.Duration(seconds) * time.Second
+				control.MaxAge = &maxAge
+			}
+		}
+	}
+	return control
+}
+
+// cacheControlKey es la clave privada para guardar las directivas en el contexto
+type cacheControlKey struct{}
+
+// WithCacheControl retorna un contexto derivado que contiene las directivas
+func WithCacheControl(ctx context.Context, control CacheControl) context.Context {
+	return context.WithValue(ctx, cacheControlKey{}, control)
+}
+
+// CacheControlFromContext obtiene las directivas del contexto (o ninguna)
+func CacheControlFromContext(ctx context.Context) CacheControl {
+	control, _ := ctx.Value(cacheControlKey{}).(CacheControl)
+	return control
+}
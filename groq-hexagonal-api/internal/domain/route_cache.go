@@ -0,0 +1,113 @@
+// Package domain - Políticas de caché por ruta
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ POR RUTA
+// ============================================================================
+//
+// Además de la caché de respuestas del chat (la aplicación decide qué es la
+// misma petición), cualquier ruta de la API puede cachear su respuesta HTTP
+// entera. Qué se cachea y cuánto tiempo se declara en la configuración, una
+// política por ruta:
+//
+//   {
+//     "GET /api/v1/models":     {"ttl": "5m"},
+//     "POST /api/v1/classify":  {"ttl": "1h", "key": "body"},
+//     "POST /api/v1/chat":      {"ttl": "0"}
+//   }
+//
+// La ruta es el método y la plantilla del router ("/api/v1/jobs/{id}"). Las
+// rutas sin política no se cachean nunca: "ttl": "0" lo deja escrito.
+//
+// La clave siempre incluye al cliente (tenant y API key), el método, la ruta
+// y la query. Con "key": "body" incluye además el hash del body (las rutas
+// POST que son una función de su entrada, como clasificar un texto).
+// ============================================================================
+
+// Claves de las políticas de caché por ruta
+const (
+	// RouteCacheKeyURL identifica la respuesta por el método, la ruta y la query
+	RouteCacheKeyURL = "url"
+
+	// RouteCacheKeyBody añade el hash del body a RouteCacheKeyURL
+	RouteCacheKeyBody = "body"
+)
+
+// ErrInvalidRouteCachePolicy se retorna al validar una política mal formada
+var ErrInvalidRouteCachePolicy = errors.New("política de caché inválida")
+
+// RouteCachePolicy es la política de caché de una ruta
+type RouteCachePolicy struct {
+	// TTL es cuánto se guarda cada respuesta ("5m", "1h"; "0" = no se cachea)
+	TTL string `json:"ttl"`
+
+	// Key es qué identifica la respuesta: "url" (por defecto) o "body"
+	Key string `json:"key,omitempty"`
+}
+
+// Duration retorna el TTL de la política (0 si no se cachea o no es válido;
+// Validate ya lo rechaza)
+func (p RouteCachePolicy) Duration() time.Duration {
+	ttl, err := time.ParseDuration(p.TTL)
+	if err != nil || ttl < 0 {
+		return 0
+	}
+	return ttl
+}
+
+// ByBody indica si el body forma parte de la clave
+func (p RouteCachePolicy) ByBody() bool {
+	return p.Key == RouteCacheKeyBody
+}
+
+// Validate comprueba el TTL y la clave de la política
+func (p RouteCachePolicy) Validate() error {
+	ttl, err := time.ParseDuration(p.TTL)
+	if err != nil || ttl < 0 {
+		return fmt.Errorf("%w: ttl debe ser una duración (ej: \"5m\") o \"0\"", ErrInvalidRouteCachePolicy)
+	}
+	switch p.Key {
+	case "", RouteCacheKeyURL, RouteCacheKeyBody:
+	default:
+		return fmt.Errorf("%w: key desconocida %q (usa url o body)", ErrInvalidRouteCachePolicy, p.Key)
+	}
+	return nil
+}
+
+// ParseRoute separa "GET /api/v1/models" en el método y la plantilla de la ruta
+func ParseRoute(route string) (method, path string, err error) {
+	method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+	path = strings.TrimSpace(path)
+	if !ok || !strings.HasPrefix(path, "/") {
+		return "", "", fmt.Errorf("%w: la ruta debe ser \"MÉTODO /ruta\": %q", ErrInvalidRouteCachePolicy, route)
+	}
+	switch method = strings.ToUpper(method); method {
+	case "GET", "POST":
+	default:
+		return "", "", fmt.Errorf("%w: solo se cachean GET y POST: %q", ErrInvalidRouteCachePolicy, route)
+	}
+	return method, path, nil
+}
+
+// CachedRoute es una respuesta HTTP guardada en la caché por ruta
+type CachedRoute struct {
+	StatusCode int                 `json:"status_code"`
+	Header     map[string][]string `json:"header,omitempty"`
+	Body       []byte              `json:"body,omitempty"`
+	StoredAt   time.Time           `json:"stored_at"`
+}
+
+// Age es el tiempo que lleva la respuesta en la caché
+func (c CachedRoute) Age(now time.Time) time.Duration {
+	if age := now.Sub(c.StoredAt); age > 0 {
+		return age
+	}
+	return 0
+}
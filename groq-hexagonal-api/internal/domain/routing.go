@@ -0,0 +1,53 @@
+// Package domain - Clave de enrutado entre proveedores
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ============================================================================
+// ROUTING KEY
+// ============================================================================
+//
+// Con varias API keys (o proveedores) configurados, las peticiones que
+// comparten una clave de enrutado van siempre al mismo destino. Así la caché
+// de prefijos del proveedor y su contabilidad de rate limit ven una
+// conversación entera, en lugar de trozos repartidos entre claves.
+//
+// Igual que el tenant, la clave viaja en el context.Context: la fija quien la
+// conoce (ej: el servicio de conversaciones) y la lee el enrutador.
+// ============================================================================
+
+// BackendHealth es el estado de uno de los destinos del enrutador
+type BackendHealth struct {
+	// Name es el nombre estable del destino (ej: "groq-2", la segunda key)
+	Name string
+
+	// Requests, Failures y RateLimited cuentan desde el arranque
+	// Failures incluye las rechazadas por rate limit (429)
+	Requests    int64
+	Failures    int64
+	RateLimited int64
+
+	// CoolingUntil es hasta cuándo no recibe peticiones tras un 429 o un
+	// error de autenticación (cero = disponible)
+	CoolingUntil time.Time
+
+	// LastError es el último error del destino ("" si no ha fallado)
+	LastError string
+}
+
+// routingKeyKey es la clave privada para guardar la clave de enrutado
+type routingKeyKey struct{}
+
+// WithRoutingKey retorna un contexto derivado con la clave de enrutado
+func WithRoutingKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, routingKeyKey{}, key)
+}
+
+// RoutingKeyFromContext obtiene la clave de enrutado ("" si no hay)
+func RoutingKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(routingKeyKey{}).(string)
+	return key
+}
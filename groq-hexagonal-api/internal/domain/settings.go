@@ -0,0 +1,46 @@
+// Package domain - Ajustes recargables sin reiniciar
+package domain
+
+import "time"
+
+// ============================================================================
+// AJUSTES EN CALIENTE
+// ============================================================================
+//
+// La mayor parte de la configuración es estructural: puertos, proveedores,
+// almacenamiento, cachés... Cambiarla exige reiniciar porque decide qué
+// adaptadores se crean. RuntimeSettings son los valores que se leen en cada
+// petición y que se pueden recargar (SIGHUP o POST /admin/config/reload) sin
+// cortar las peticiones en curso.
+// ============================================================================
+
+// RuntimeSettings son los ajustes que se pueden recargar sin reiniciar
+type RuntimeSettings struct {
+	// DefaultModel es el modelo de las peticiones que no indican ninguno
+	DefaultModel string
+
+	// HTTPTimeout es el tiempo máximo de cada llamada al proveedor
+	// (sin streaming: un flujo largo solo se controla con el contexto)
+	HTTPTimeout time.Duration
+
+	// RateLimitRequests son las peticiones por cliente y RateLimitWindow la
+	// ventana (RateLimitRequests = 0 desactiva el límite)
+	RateLimitRequests int
+	RateLimitWindow   time.Duration
+
+	// MaxConcurrentRequests son las peticiones simultáneas por cliente
+	// (0 = sin límite)
+	MaxConcurrentRequests int
+
+	// ThrottleSchedule cambia los límites por franja horaria (ver throttle.go)
+	ThrottleSchedule ThrottleSchedule
+}
+
+// ClientLimitsAt retorna los límites por cliente vigentes en now
+func (s RuntimeSettings) ClientLimitsAt(now time.Time) ClientLimits {
+	return s.ThrottleSchedule.Apply(ClientLimits{
+		RateLimitRequests: s.RateLimitRequests,
+		RateLimitWindow:   s.RateLimitWindow,
+		MaxConcurrent:     s.MaxConcurrentRequests,
+	}, now)
+}
@@ -0,0 +1,107 @@
+// Package domain - Extractos mensuales del consumo de cada tenant
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// EXTRACTOS MENSUALES
+// ============================================================================
+//
+// Además del consumo diario por cliente (usage.go), cada petición con tokens
+// suma al mes (UTC) de su tenant, por modelo y por conversación. El extracto
+// de un mes resume las peticiones, los tokens y el coste por modelo, y las
+// conversaciones que más han consumido. Un mes en curso da el extracto
+// provisional hasta hoy.
+// ============================================================================
+
+// StatementMonthLayout es el formato de los meses del extracto (ej: "2026-10")
+const StatementMonthLayout = "2006-01"
+
+// MaxStatementMonths es el máximo de meses anteriores al actual que se
+// pueden consultar (los almacenes sin histórico olvidan los más antiguos)
+const MaxStatementMonths = 12
+
+// MaxStatementConversations es cuántas conversaciones lista el extracto
+const MaxStatementConversations = 10
+
+// ErrInvalidStatementMonth se retorna cuando el mes pedido no es válido
+// (formato distinto de StatementMonthLayout, futuro o demasiado antiguo)
+var ErrInvalidStatementMonth = errors.New("mes del extracto inválido")
+
+// TenantUsageRecord es el consumo de una petición para el extracto del tenant
+type TenantUsageRecord struct {
+	Model string
+
+	// ConversationID es la conversación de la petición (vacío = ninguna)
+	ConversationID string
+
+	Usage Usage
+}
+
+// ModelUsage es el consumo de un mes con un modelo
+type ModelUsage struct {
+	Model    string
+	Requests int64
+	Usage    Usage
+}
+
+// ConversationUsage es el consumo de un mes en una conversación
+type ConversationUsage struct {
+	ConversationID string
+	Requests       int64
+	Usage          Usage
+}
+
+// TenantUsage es el consumo acumulado de un tenant en un mes, sin ordenar
+type TenantUsage struct {
+	Models        []ModelUsage
+	Conversations []ConversationUsage
+}
+
+// TenantStatement es el extracto de un tenant en un mes
+type TenantStatement struct {
+	Tenant string
+
+	// Month es el mes con formato StatementMonthLayout
+	Month string
+
+	// PeriodStart y PeriodEnd delimitan el mes (PeriodEnd es el primer
+	// instante del mes siguiente)
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+
+	// Provisional indica que el mes todavía no ha terminado
+	Provisional bool
+
+	// Requests y Usage son el total del mes
+	Requests int64
+	Usage    Usage
+
+	// Models es el consumo por modelo, del más caro al más barato
+	Models []ModelUsage
+
+	// TopConversations son las conversaciones con más tokens (máximo
+	// MaxStatementConversations)
+	TopConversations []ConversationUsage
+
+	GeneratedAt time.Time
+}
+
+// conversationKey es la clave privada de la conversación en el contexto
+type conversationKey struct{}
+
+// WithConversation retorna un contexto derivado con la conversación de la
+// petición, para atribuirle su consumo en el extracto
+func WithConversation(ctx context.Context, conversationID string) context.Context {
+	return context.WithValue(ctx, conversationKey{}, conversationID)
+}
+
+// ConversationFromContext obtiene la conversación del contexto (vacío = ninguna)
+func ConversationFromContext(ctx context.Context) string {
+	conversationID, _ := ctx.Value(conversationKey{}).(string)
+	return conversationID
+}
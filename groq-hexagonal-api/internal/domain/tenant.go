@@ -0,0 +1,37 @@
+// Package domain - Identificación del tenant (cliente) de una petición
+package domain
+
+import "context"
+
+// ============================================================================
+// TENANT
+// ============================================================================
+//
+// Un "tenant" es un cliente u organización que usa la API. Varias políticas
+// (límites, prompts, modelos por defecto) pueden variar según el tenant.
+//
+// El tenant viaja en el context.Context de la petición: el adaptador HTTP lo
+// extrae (por ejemplo, de una cabecera) y la capa de aplicación lo lee sin
+// saber de dónde vino.
+// ============================================================================
+
+// DefaultTenant es el tenant usado cuando la petición no indica ninguno
+const DefaultTenant = "default"
+
+// tenantKey es la clave privada para guardar el tenant en el contexto
+// Usar un tipo propio evita colisiones con claves de otros paquetes
+type tenantKey struct{}
+
+// WithTenant retorna un contexto derivado que contiene el tenant
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenantID)
+}
+
+// TenantFromContext obtiene el tenant del contexto (o DefaultTenant)
+func TenantFromContext(ctx context.Context) string {
+	// Type assertion con "comma ok": ok es false si no existe o no es string
+	if tenantID, ok := ctx.Value(tenantKey{}).(string); ok && tenantID != "" {
+		return tenantID
+	}
+	return DefaultTenant
+}
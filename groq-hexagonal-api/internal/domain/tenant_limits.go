@@ -0,0 +1,76 @@
+// Package domain - Modelo por defecto y rangos de parámetros por tenant
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ============================================================================
+// LÍMITES POR TENANT
+// ============================================================================
+//
+// Cada tenant puede tener su propio modelo por defecto y acotar los
+// parámetros que envían sus clientes: la temperatura (mínimo y máximo) y
+// max_tokens (máximo). Una petición fuera de rango se ajusta al límite más
+// cercano (con un aviso) o se rechaza con ErrParameterOutOfRange, según
+// OnViolation.
+//
+// Sin max_tokens, la petición recibe el máximo del tenant: no pedir límite
+// sería pedir el máximo del modelo. Sin temperatura se usa la del modelo.
+// ============================================================================
+
+// Valores de TenantModelLimits.OnViolation
+const (
+	ParameterClamp  = "clamp"
+	ParameterReject = "reject"
+)
+
+// ErrParameterOutOfRange indica que un parámetro supera los límites del tenant
+var ErrParameterOutOfRange = errors.New("parámetro fuera del rango permitido")
+
+// TenantModelLimits son el modelo por defecto y los rangos de un tenant
+type TenantModelLimits struct {
+	// DefaultModel sustituye a DEFAULT_MODEL para el tenant ("" = el global)
+	DefaultModel string `json:"default_model,omitempty"`
+
+	// MinTemperature y MaxTemperature acotan la temperatura (nil = sin límite)
+	MinTemperature *float64 `json:"min_temperature,omitempty"`
+	MaxTemperature *float64 `json:"max_temperature,omitempty"`
+
+	// MaxTokens es el máximo de max_tokens (0 = sin límite)
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// OnViolation es ParameterClamp ("" = por defecto) o ParameterReject
+	OnViolation string `json:"on_violation,omitempty"`
+}
+
+// Validate comprueba que los límites son coherentes
+func (l TenantModelLimits) Validate() error {
+	for _, bound := range []*float64{l.MinTemperature, l.MaxTemperature} {
+		if bound != nil && (*bound < 0 || *bound > 2) {
+			return errors.New("los límites de temperatura deben estar entre 0 y 2")
+		}
+	}
+	if l.MinTemperature != nil && l.MaxTemperature != nil && *l.MinTemperature > *l.MaxTemperature {
+		return errors.New("min_temperature no puede ser mayor que max_temperature")
+	}
+	if l.MaxTokens < 0 {
+		return errors.New("max_tokens debe ser mayor o igual a 0")
+	}
+	if l.OnViolation != "" && l.OnViolation != ParameterClamp && l.OnViolation != ParameterReject {
+		return fmt.Errorf("on_violation debe ser %q o %q", ParameterClamp, ParameterReject)
+	}
+	return nil
+}
+
+// ClampTemperature ajusta la temperatura al rango
+func (l TenantModelLimits) ClampTemperature(temperature float64) float64 {
+	if l.MinTemperature != nil && temperature < *l.MinTemperature {
+		return *l.MinTemperature
+	}
+	if l.MaxTemperature != nil && temperature > *l.MaxTemperature {
+		return *l.MaxTemperature
+	}
+	return temperature
+}
@@ -0,0 +1,183 @@
+// Package domain - Límites por franja horaria
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// FRANJAS HORARIAS DEL RATE LIMIT
+// ============================================================================
+//
+// Los límites por cliente (peticiones por ventana y peticiones simultáneas)
+// pueden cambiar según la hora y el día de la semana: por ejemplo, límites
+// bajos en horario laboral para reservar capacidad al tráfico interactivo y
+// límites altos de noche para los procesos batch.
+//
+// Cada franja indica sus días, su hora de inicio y de fin y los límites que
+// cambia; los que no indica se quedan en los de base (RATE_LIMIT_REQUESTS y
+// MAX_CONCURRENT_REQUESTS). La primera franja que contiene el momento actual
+// gana. Una franja puede cruzar la medianoche ("22:00" a "06:00"): sus días
+// son los de la hora de inicio.
+// ============================================================================
+
+// ErrInvalidThrottleWindow se retorna al validar una franja mal formada
+var ErrInvalidThrottleWindow = errors.New("franja horaria inválida")
+
+// weekdays son los nombres admitidos en ThrottleWindow.Days
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ThrottleWindow es una franja horaria con límites propios
+type ThrottleWindow struct {
+	// Name identifica la franja en los logs (opcional)
+	Name string `json:"name,omitempty"`
+
+	// Days son los días de la franja: "mon", "tue"... (vacío = todos)
+	Days []string `json:"days,omitempty"`
+
+	// From y To son la hora de inicio (incluida) y de fin (excluida), "HH:MM"
+	// To admite "24:00" para llegar hasta el final del día
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Límites de la franja (nil = el de base; 0 = sin límite)
+	RateLimitRequests *int `json:"rate_limit_requests,omitempty"`
+	MaxConcurrent     *int `json:"max_concurrent,omitempty"`
+}
+
+// Validate comprueba los días, las horas y los límites de la franja
+func (w ThrottleWindow) Validate() error {
+	for _, day := range w.Days {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("%w: día desconocido %q", ErrInvalidThrottleWindow, day)
+		}
+	}
+	from, err := clockMinutes(w.From)
+	if err != nil || from == 24*60 {
+		return fmt.Errorf("%w: from debe ser HH:MM", ErrInvalidThrottleWindow)
+	}
+	to, err := clockMinutes(w.To)
+	if err != nil {
+		return fmt.Errorf("%w: to debe ser HH:MM", ErrInvalidThrottleWindow)
+	}
+	if from == to {
+		return fmt.Errorf("%w: from y to no pueden coincidir", ErrInvalidThrottleWindow)
+	}
+	if (w.RateLimitRequests != nil && *w.RateLimitRequests < 0) ||
+		(w.MaxConcurrent != nil && *w.MaxConcurrent < 0) {
+		return fmt.Errorf("%w: los límites deben ser mayores o iguales a 0", ErrInvalidThrottleWindow)
+	}
+	return nil
+}
+
+// Contains indica si t (ya en la zona horaria del horario) cae en la franja
+func (w ThrottleWindow) Contains(t time.Time) bool {
+	from, _ := clockMinutes(w.From)
+	to, _ := clockMinutes(w.To)
+	minute := t.Hour()*60 + t.Minute()
+
+	if from < to {
+		return w.onDay(t.Weekday()) && minute >= from && minute < to
+	}
+	// Cruza la medianoche: la parte de después cuenta para el día anterior
+	if minute >= from {
+		return w.onDay(t.Weekday())
+	}
+	return minute < to && w.onDay((t.Weekday()+6)%7)
+}
+
+// onDay indica si la franja empieza el día indicado
+func (w ThrottleWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, name := range w.Days {
+		if weekdays[strings.ToLower(name)] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ThrottleSchedule son las franjas con límites propios
+type ThrottleSchedule struct {
+	Windows []ThrottleWindow
+
+	// Location es la zona horaria de las franjas (nil = UTC)
+	Location *time.Location
+}
+
+// ClientLimits son los límites por cliente vigentes en un momento
+type ClientLimits struct {
+	// RateLimitRequests son las peticiones por RateLimitWindow (0 = sin límite)
+	RateLimitRequests int
+	RateLimitWindow   time.Duration
+
+	// MaxConcurrent son las peticiones simultáneas (0 = sin límite)
+	MaxConcurrent int
+
+	// Window es el nombre de la franja aplicada ("" = límites de base)
+	Window string
+}
+
+// Apply retorna los límites de base con los de la franja vigente en now
+func (s ThrottleSchedule) Apply(base ClientLimits, now time.Time) ClientLimits {
+	if len(s.Windows) == 0 {
+		return base
+	}
+	if s.Location != nil {
+		now = now.In(s.Location)
+	} else {
+		now = now.UTC()
+	}
+
+	for i, window := range s.Windows {
+		if !window.Contains(now) {
+			continue
+		}
+		if window.RateLimitRequests != nil {
+			base.RateLimitRequests = *window.RateLimitRequests
+		}
+		if window.MaxConcurrent != nil {
+			base.MaxConcurrent = *window.MaxConcurrent
+		}
+		base.Window = window.Name
+		if base.Window == "" {
+			base.Window = "#" + strconv.Itoa(i+1)
+		}
+		return base
+	}
+	return base
+}
+
+// clockMinutes convierte "HH:MM" en minutos desde las 00:00 (hasta 24:00)
+func clockMinutes(clock string) (int, error) {
+	hours, minutes, ok := strings.Cut(clock, ":")
+	if !ok || len(hours) != 2 || len(minutes) != 2 {
+		return 0, fmt.Errorf("hora inválida: %q", clock)
+	}
+	h, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("hora inválida: %q", clock)
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil {
+		return 0, fmt.Errorf("hora inválida: %q", clock)
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m != 0) {
+		return 0, fmt.Errorf("hora inválida: %q", clock)
+	}
+	return h*60 + m, nil
+}
@@ -0,0 +1,51 @@
+// Package domain - Tokens de vida corta emitidos a partir de API keys
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ============================================================================
+// TOKENS DE VIDA CORTA
+// ============================================================================
+//
+// Un cliente con una API key puede pedir un token que caduca pronto (ej: 15
+// minutos) y solo vale para algunos de sus scopes, para entregárselo a un
+// navegador sin exponer la clave. El token se firma (ver TokenSigner) y no
+// se guarda: sigue dependiendo de su clave, así que desactivarla lo anula.
+// ============================================================================
+
+// ErrInvalidTokenSpec se retorna cuando los datos de un token no son válidos
+var ErrInvalidTokenSpec = errors.New("datos del token inválidos")
+
+// TokenSpec son los datos de un token nuevo
+type TokenSpec struct {
+	// Scopes son las partes de la API a las que accede (obligatorio; deben
+	// estar permitidas para la clave)
+	Scopes []string
+
+	// TTL es cuánto vale (0 = el valor por defecto)
+	TTL time.Duration
+}
+
+// TokenClaims son los datos firmados en un token
+type TokenClaims struct {
+	// ID identifica el token (ej: en los logs)
+	ID string
+
+	// KeyID es la API key que lo emitió
+	KeyID string
+
+	Scopes    []string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// ScopedToken es un token emitido, con su valor firmado
+type ScopedToken struct {
+	TokenClaims
+
+	// Token es lo que el cliente envía en "Authorization: Bearer"
+	Token string
+}
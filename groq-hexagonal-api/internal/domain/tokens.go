@@ -0,0 +1,146 @@
+// Package domain - Estimación de tokens y ventanas de contexto
+package domain
+
+import (
+	"encoding/json"
+	"unicode"
+)
+
+// ============================================================================
+// ESTIMACIÓN DE TOKENS
+// ============================================================================
+//
+// El tokenizador exacto de cada modelo (BPE con su vocabulario) no está
+// disponible aquí, pero sí se puede imitar su primer paso: la
+// pre-tokenización. Los tokenizadores de Llama, GPT o Mixtral parten el texto
+// en palabras, números, signos y espacios antes de aplicar el vocabulario, y
+// ningún token cruza esas fronteras. EstimateTokens hace ese mismo corte y
+// cuenta cada trozo como uno o varios tokens según su longitud:
+//
+//   - Palabras: un token cada ~4 letras (las largas se parten en subpalabras)
+//   - Números: un token cada 3 dígitos (como agrupa Llama 3)
+//   - Signos y símbolos: un token cada uno
+//   - Chino, japonés y coreano: un token por carácter
+//   - Espacios: el espacio simple va pegado a la palabra siguiente; los
+//     saltos de línea y la indentación cuentan aparte
+//
+// Es más preciso que dividir los caracteres entre 4 en código, JSON o textos
+// con mucha puntuación, pero sigue siendo una estimación: sirve para
+// previsualizar costes y para no enviar peticiones que no caben, no para
+// facturar (para eso está el Usage de la respuesta).
+// ============================================================================
+
+const (
+	// charsPerWordToken es la longitud media de una subpalabra
+	charsPerWordToken = 4
+
+	// digitsPerToken es cuántos dígitos van en cada token
+	digitsPerToken = 3
+
+	// TokensPerMessage es el coste fijo de cada mensaje (el rol y los separadores)
+	TokensPerMessage = 4
+)
+
+// EstimateTokens estima los tokens de un texto (ver arriba)
+func EstimateTokens(text string) int {
+	runes := []rune(text)
+	tokens := 0
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		j := i + 1
+
+		switch {
+		case isIdeographic(r):
+			tokens++
+		case unicode.IsLetter(r):
+			for j < len(runes) && unicode.IsLetter(runes[j]) && !isIdeographic(runes[j]) {
+				j++
+			}
+			tokens += ceilDiv(j-i, charsPerWordToken)
+		case unicode.IsDigit(r):
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			tokens += ceilDiv(j-i, digitsPerToken)
+		case unicode.IsSpace(r):
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				j++
+			}
+			if j-i > 1 || r != ' ' {
+				tokens += ceilDiv(j-i, charsPerWordToken)
+			}
+		default:
+			tokens++
+		}
+
+		i = j
+	}
+
+	return tokens
+}
+
+// EstimatePromptTokens estima los tokens de entrada de una petición
+// Las imágenes no se cuentan: su coste depende de la resolución
+func EstimatePromptTokens(request ChatRequest) int {
+	tokens := EstimateToolTokens(request.Tools)
+	for _, message := range request.Messages {
+		tokens += EstimateMessageTokens(message)
+	}
+	return tokens
+}
+
+// EstimateMessageTokens estima los tokens de un mensaje (sin imágenes)
+func EstimateMessageTokens(message ChatMessage) int {
+	return EstimateTokens(message.Content) + TokensPerMessage
+}
+
+// EstimateToolTokens estima los tokens de las herramientas de una petición
+// Se envían al modelo como JSON
+func EstimateToolTokens(tools []Tool) int {
+	if len(tools) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(tools)
+	if err != nil {
+		return 0
+	}
+	return EstimateTokens(string(data))
+}
+
+// isIdeographic indica si r es de una escritura sin espacios entre palabras
+func isIdeographic(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// ceilDiv divide redondeando hacia arriba
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+// ============================================================================
+// VENTANAS DE CONTEXTO
+// ============================================================================
+
+// DefaultContextWindows son las ventanas de contexto (en tokens) de los
+// modelos más usados. Incluye la entrada y la salida: los max_tokens pedidos
+// también deben caber. Se amplían o corrigen con MODEL_CONTEXT_WINDOWS.
+var DefaultContextWindows = map[string]int{
+	// Groq
+	"llama-3.3-70b-versatile":                       131072,
+	"llama-3.1-8b-instant":                          131072,
+	"llama3-70b-8192":                               8192,
+	"llama3-8b-8192":                                8192,
+	"gemma2-9b-it":                                  8192,
+	"mixtral-8x7b-32768":                            32768,
+	"meta-llama/llama-4-scout-17b-16e-instruct":     131072,
+	"meta-llama/llama-4-maverick-17b-128e-instruct": 131072,
+	"deepseek-r1-distill-llama-70b":                 131072,
+	"qwen/qwen3-32b":                                131072,
+	"openai/gpt-oss-120b":                           131072,
+	"openai/gpt-oss-20b":                            131072,
+
+	// OpenAI
+	"gpt-4o":      128000,
+	"gpt-4o-mini": 128000,
+}
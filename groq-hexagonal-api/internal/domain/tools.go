@@ -0,0 +1,57 @@
+// Package domain - Llamadas a herramientas (tool / function calling)
+package domain
+
+import "encoding/json"
+
+// ============================================================================
+// TOOL CALLING
+// ============================================================================
+//
+// Con "tool calling" el cliente describe funciones (nombre + JSON Schema de
+// sus parámetros) y el modelo, en lugar de responder con texto, puede pedir
+// que se llame a una de ellas. El flujo es:
+//
+//   1. Petición con tools → respuesta con tool_calls (finish_reason "tool_calls")
+//   2. El cliente ejecuta la función por su cuenta
+//   3. Nueva petición con el historial + un mensaje role "tool" con el resultado
+//   4. El modelo responde con texto usando ese resultado
+//
+// Los tipos siguen el esquema compatible con OpenAI que usa Groq, así que se
+// envían y reciben tal cual (passthrough).
+// ============================================================================
+
+// Tool describe una herramienta que el modelo puede invocar
+type Tool struct {
+	// Type es siempre "function" (el único tipo que soporta Groq)
+	Type     string       `json:"type"`
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction describe una función invocable por el modelo
+type ToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Parameters es el JSON Schema de los argumentos
+	// json.RawMessage guarda el JSON sin interpretarlo (se reenvía tal cual)
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ToolCall es una invocación de herramienta pedida por el modelo
+type ToolCall struct {
+	// Index solo aparece en streaming: identifica a qué llamada pertenece
+	// cada fragmento (los argumentos llegan troceados)
+	Index *int `json:"index,omitempty"`
+
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction es la función invocada y sus argumentos
+type ToolCallFunction struct {
+	Name string `json:"name,omitempty"`
+
+	// Arguments es un JSON serializado como string (así lo envía el modelo)
+	Arguments string `json:"arguments"`
+}
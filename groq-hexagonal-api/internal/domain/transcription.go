@@ -0,0 +1,87 @@
+// Package domain - Transcripción de audio
+package domain
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// ============================================================================
+// ENTIDADES DE TRANSCRIPCIÓN
+// ============================================================================
+//
+// La transcripción convierte un fichero de audio en texto con un modelo
+// Whisper del proveedor. Además del texto completo se retornan los
+// segmentos con sus marcas de tiempo (útil para subtítulos).
+// ============================================================================
+
+// DefaultTranscriptionModel es el modelo de transcripción por defecto
+const DefaultTranscriptionModel = "whisper-large-v3"
+
+// MaxAudioBytes es el tamaño máximo de un fichero de audio (límite de Groq)
+const MaxAudioBytes = 25 << 20 // 25 MiB
+
+// SupportedAudioFormats son las extensiones de audio que acepta Groq
+var SupportedAudioFormats = []string{"flac", "mp3", "mp4", "mpeg", "mpga", "m4a", "ogg", "opus", "wav", "webm"}
+
+// IsSupportedAudioFile indica si el nombre del fichero tiene una extensión soportada
+func IsSupportedAudioFile(filename string) bool {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	for _, format := range SupportedAudioFormats {
+		if ext == format {
+			return true
+		}
+	}
+	return false
+}
+
+// TranscriptionRequest es la entrada del caso de uso de transcribir audio
+type TranscriptionRequest struct {
+	// Audio es el contenido del fichero; Filename, su nombre (el proveedor
+	// deduce el formato por la extensión)
+	Audio    io.Reader
+	Filename string
+
+	// Model es el modelo de transcripción (vacío = el por defecto)
+	Model string
+
+	// Language es el idioma del audio en ISO 639-1 (vacío = detectarlo)
+	// Indicarlo mejora la precisión y la latencia
+	Language string
+
+	// Prompt orienta el estilo o el vocabulario (ej: nombres propios)
+	Prompt string
+
+	// Temperature controla la aleatoriedad (nil = default del modelo)
+	Temperature *float64
+}
+
+// Transcription es el resultado de transcribir un audio
+type Transcription struct {
+	// Text es el texto completo
+	Text string `json:"text"`
+
+	// Language es el idioma detectado (o el indicado)
+	Language string `json:"language"`
+
+	// Duration es la duración del audio en segundos
+	Duration float64 `json:"duration"`
+
+	// Segments son los fragmentos con marcas de tiempo
+	Segments []TranscriptionSegment `json:"segments"`
+
+	// Model es el modelo que hizo la transcripción
+	Model string `json:"-"`
+}
+
+// TranscriptionSegment es un fragmento de la transcripción
+type TranscriptionSegment struct {
+	ID int `json:"id"`
+
+	// Start y End son el inicio y el final en segundos
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+
+	Text string `json:"text"`
+}
@@ -0,0 +1,80 @@
+// Package domain - Registro del uso de tokens
+package domain
+
+import "time"
+
+// UsageRecord es el consumo de tokens de una petición al proveedor
+type UsageRecord struct {
+	// Source identifica el endpoint que hizo la petición (ej: "proxy")
+	Source string
+
+	// Model es el modelo que generó la respuesta
+	Model string
+
+	Usage Usage
+}
+
+// ============================================================================
+// CONSUMO DIARIO Y CUOTAS
+// ============================================================================
+//
+// Cada cliente (su API key; sin ella, el tenant o la IP) acumula los tokens
+// que consume cada día, en UTC. Con cuota, al agotarla se rechazan sus
+// peticiones hasta el día siguiente. La cuota se comprueba ANTES de llamar al
+// modelo, con lo consumido hasta entonces: la petición que la cruza termina
+// con normalidad (sus tokens no se conocen hasta la respuesta).
+// ============================================================================
+
+// UsageDayLayout es el formato de los días del consumo (ej: "2026-10-16")
+const UsageDayLayout = "2006-01-02"
+
+// MaxUsageHistoryDays es el máximo de días anteriores que se pueden consultar
+const MaxUsageHistoryDays = 31
+
+// DailyUsage es el consumo de un cliente en un día
+type DailyUsage struct {
+	// Day es el día en UTC con formato UsageDayLayout
+	Day string
+
+	Usage Usage
+}
+
+// UsageReport es el consumo de un cliente frente a su cuota
+type UsageReport struct {
+	// Client identifica al cliente (ej: "key:3f9a1c0b7e2d")
+	Client string
+
+	// Today es lo consumido hoy (UTC)
+	Today Usage
+
+	// Quota es la cuota diaria en tokens (0 = sin cuota)
+	Quota int64
+
+	// Remaining son los tokens que quedan hoy (0 si no hay cuota)
+	Remaining int64
+
+	// ResetAt es cuándo empieza el día siguiente (se reinicia el consumo)
+	ResetAt time.Time
+
+	// History es el consumo de los días anteriores, del más reciente al más
+	// antiguo (solo los días con consumo)
+	History []DailyUsage
+
+	// CostUSD es el coste estimado de hoy más el de los días de History
+	CostUSD float64
+}
+
+// QuotaExceeded indica si el cliente ha agotado su cuota de hoy
+func (r *UsageReport) QuotaExceeded() bool {
+	return r.Quota > 0 && int64(r.Today.TotalTokens) >= r.Quota
+}
+
+// Add retorna la suma de los dos consumos (ej: varias llamadas de un caso de uso)
+func (u Usage) Add(other Usage) Usage {
+	return Usage{
+		PromptTokens:     u.PromptTokens + other.PromptTokens,
+		CompletionTokens: u.CompletionTokens + other.CompletionTokens,
+		TotalTokens:      u.TotalTokens + other.TotalTokens,
+		CostUSD:          u.CostUSD + other.CostUSD,
+	}
+}
@@ -0,0 +1,68 @@
+// Package domain - Eventos de consumo para facturación y analítica
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ============================================================================
+// EVENTOS DE CONSUMO
+// ============================================================================
+//
+// Cada consumo que se registra (UsageService.Record) se emite además como un
+// UsageEvent, para que los sistemas de facturación y analítica lo reciban sin
+// consultar la API. El identificador y el inicio de la petición los pone el
+// adaptador que la recibe en el contexto (WithRequestInfo).
+//
+// La entrega es como mucho una vez: si el destino no responde, el evento se
+// pierde (el consumo sigue registrado en el almacén de siempre).
+// ============================================================================
+
+// UsageEvent es el consumo de una respuesta del modelo
+type UsageEvent struct {
+	// RequestID identifica la petición (la cabecera X-Request-ID o el ID del job)
+	RequestID string `json:"request_id"`
+
+	// Client es el cliente del consumo (API key, tenant o IP, como en las cuotas)
+	Client string `json:"client"`
+
+	// APIKeyID es la API key autenticada ("" sin autenticación)
+	APIKeyID string `json:"api_key_id,omitempty"`
+
+	TenantID       string `json:"tenant_id"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	Model          string `json:"model"`
+
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	TotalTokens      int     `json:"total_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+
+	// LatencyMS es lo que tardó la petición (0 si se desconoce)
+	LatencyMS int64 `json:"latency_ms"`
+
+	Time time.Time `json:"time"`
+}
+
+// RequestInfo identifica la petición que origina un consumo
+type RequestInfo struct {
+	ID string
+
+	// StartedAt es cuándo empezó (para calcular la latencia)
+	StartedAt time.Time
+}
+
+// requestInfoKey es la clave privada de la petición en el contexto
+type requestInfoKey struct{}
+
+// WithRequestInfo retorna un contexto derivado con la petición en curso
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext obtiene la petición del contexto (vacía = ninguna)
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
@@ -0,0 +1,156 @@
+// Package domain - Entrada de imágenes (visión)
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ============================================================================
+// MENSAJES MULTIMODALES
+// ============================================================================
+//
+// Los modelos con visión reciben el contenido de un mensaje como una lista
+// de partes en lugar de un texto:
+//
+//   "content": [
+//     {"type": "text", "text": "¿Qué hay en la imagen?"},
+//     {"type": "image_url", "image_url": {"url": "https://..."}}
+//   ]
+//
+// En el dominio el texto sigue en ChatMessage.Content y las imágenes van en
+// ChatMessage.Images. El resto de la aplicación (políticas, estimación de
+// tokens, conversaciones) sigue trabajando con el texto, y el formato de
+// partes solo aparece al serializar el mensaje para el proveedor.
+// ============================================================================
+
+// Límites de Groq para las imágenes de una petición
+const (
+	// MaxImagesPerMessage es el número máximo de imágenes por mensaje
+	MaxImagesPerMessage = 5
+
+	// MaxImageBytes es el tamaño máximo de una imagen enviada en base64
+	// (se mide sobre los bytes decodificados)
+	MaxImageBytes = 4 << 20 // 4 MiB
+)
+
+// SupportedImageTypes son los tipos MIME de imagen que se aceptan
+var SupportedImageTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+// IsSupportedImageType indica si el tipo MIME de imagen está soportado
+func IsSupportedImageType(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	for _, supported := range SupportedImageTypes {
+		if mimeType == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// Tipos de parte del contenido de un mensaje
+const (
+	ContentPartText     = "text"
+	ContentPartImageURL = "image_url"
+)
+
+// ContentPart es una parte del contenido de un mensaje multimodal
+type ContentPart struct {
+	Type     string    `json:"type"`
+	Text     string    `json:"text,omitempty"`
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL referencia una imagen: una URL http(s) o una data URI con la
+// imagen en base64 (data:image/png;base64,...)
+type ImageURL struct {
+	URL string `json:"url"`
+}
+
+// IsDataURI indica si la imagen va incrustada en base64
+func (i ImageURL) IsDataURI() bool {
+	return strings.HasPrefix(i.URL, "data:")
+}
+
+// Base64Data retorna los datos en base64 de una data URI ("" si es una URL)
+func (i ImageURL) Base64Data() string {
+	if !i.IsDataURI() {
+		return ""
+	}
+	_, data, found := strings.Cut(i.URL, ",")
+	if !found {
+		return ""
+	}
+	return data
+}
+
+// ============================================================================
+// SERIALIZACIÓN
+// ============================================================================
+
+// chatMessageJSON evita la recursión infinita en MarshalJSON/UnmarshalJSON:
+// tiene los mismos campos que ChatMessage pero no sus métodos
+type chatMessageJSON ChatMessage
+
+// MarshalJSON serializa el contenido como lista de partes si hay imágenes
+// Sin imágenes el mensaje se serializa igual que siempre ("content": "...")
+func (m ChatMessage) MarshalJSON() ([]byte, error) {
+	if len(m.Images) == 0 {
+		return json.Marshal(chatMessageJSON(m))
+	}
+
+	parts := make([]ContentPart, 0, len(m.Images)+1)
+	if m.Content != "" {
+		parts = append(parts, ContentPart{Type: ContentPartText, Text: m.Content})
+	}
+	for i := range m.Images {
+		parts = append(parts, ContentPart{Type: ContentPartImageURL, ImageURL: &m.Images[i]})
+	}
+
+	// El campo Content del struct embebido queda oculto por el de fuera
+	return json.Marshal(struct {
+		chatMessageJSON
+		Content []ContentPart `json:"content"`
+	}{
+		chatMessageJSON: chatMessageJSON(m),
+		Content:         parts,
+	})
+}
+
+// UnmarshalJSON acepta el contenido como texto o como lista de partes
+// Las partes de texto se concatenan en Content y las imágenes van a Images
+func (m *ChatMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		chatMessageJSON
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*m = ChatMessage(raw.chatMessageJSON)
+	m.Content = ""
+
+	content := raw.Content
+	if len(content) == 0 || string(content) == "null" {
+		return nil
+	}
+	if content[0] == '"' {
+		return json.Unmarshal(content, &m.Content)
+	}
+
+	var parts []ContentPart
+	if err := json.Unmarshal(content, &parts); err != nil {
+		return err
+	}
+	var text []string
+	for _, part := range parts {
+		switch {
+		case part.Type == ContentPartText:
+			text = append(text, part.Text)
+		case part.Type == ContentPartImageURL && part.ImageURL != nil:
+			m.Images = append(m.Images, *part.ImageURL)
+		}
+	}
+	m.Content = strings.Join(text, "\n")
+	return nil
+}
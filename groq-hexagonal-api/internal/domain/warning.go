@@ -0,0 +1,63 @@
+// Package domain - Avisos sobre decisiones de la aplicación
+package domain
+
+// ============================================================================
+// WARNINGS
+// ============================================================================
+//
+// A veces la aplicación no hace exactamente lo que pidió el cliente: usa otro
+// modelo porque el pedido está retirado, recorta la respuesta, descarta
+// secuencias de parada... La petición sigue siendo correcta, pero el cliente
+// debería saberlo (y poder decírselo a su usuario). Cada decisión así añade un
+// Warning a la respuesta, con un código estable para los programas y un
+// mensaje legible para las personas.
+// ============================================================================
+
+// Códigos de aviso (estables: los clientes pueden comparar con ellos)
+const (
+	// WarningModelRemapped: el modelo pedido está retirado y se usó su reemplazo
+	WarningModelRemapped = "model_remapped"
+
+	// WarningOutputTruncated: la respuesta se recortó por el límite del tenant
+	WarningOutputTruncated = "output_truncated"
+
+	// WarningStopSequencesDropped: se descartaron secuencias de parada por
+	// superar el máximo que admite el modelo
+	WarningStopSequencesDropped = "stop_sequences_dropped"
+
+	// WarningHistoryTruncated: se descartaron los mensajes más antiguos del
+	// historial para no superar la ventana de contexto del modelo
+	WarningHistoryTruncated = "history_truncated"
+
+	// WarningDocumentsTruncated y WarningExamplesTruncated: se descartaron
+	// documentos de contexto o ejemplos por el mismo motivo
+	WarningDocumentsTruncated = "documents_truncated"
+	WarningExamplesTruncated  = "examples_truncated"
+
+	// WarningParameterClamped: la temperatura o max_tokens superaban los
+	// límites del tenant y se ajustaron al más cercano
+	WarningParameterClamped = "parameter_clamped"
+
+	// WarningHistoryCompacted: los mensajes más antiguos del historial se
+	// sustituyeron por un resumen al acercarse a la ventana de contexto
+	WarningHistoryCompacted = "history_compacted"
+
+	// WarningGroundingUnavailable: se pidió verificar la respuesta, pero la
+	// verificación falló (la respuesta se entrega sin ella)
+	WarningGroundingUnavailable = "grounding_unavailable"
+
+	// WarningBudgetFallback: la conversación agotó su presupuesto y el turno
+	// se envió a su modelo de reserva
+	WarningBudgetFallback = "budget_fallback"
+)
+
+// Warning es un aviso sobre algo no evidente que hizo la aplicación
+type Warning struct {
+	Code    string
+	Message string
+}
+
+// AddWarning añade un aviso a los metadatos de la respuesta
+func (m *ResponseMeta) AddWarning(code, message string) {
+	m.Warnings = append(m.Warnings, Warning{Code: code, Message: message})
+}
@@ -0,0 +1,41 @@
+package alerts
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"time"
+)
+
+// ============================================================================
+// CADUCIDAD DE API KEYS EN EL LOG
+// ============================================================================
+
+// LogAPIKeyNotifier escribe los eventos de caducidad de las API keys en el
+// log, con el mismo formato clave=valor que el resto de avisos (ej: alertar
+// con las líneas event=api_key.expiring)
+// Implementa domain.APIKeyNotifier
+type LogAPIKeyNotifier struct{}
+
+// NewLogAPIKeyNotifier crea el notificador
+func NewLogAPIKeyNotifier() *LogAPIKeyNotifier {
+	return &LogAPIKeyNotifier{}
+}
+
+// NotifyAPIKey implementa domain.APIKeyNotifier
+func (n *LogAPIKeyNotifier) NotifyAPIKey(ctx context.Context, event domain.APIKeyEvent) error {
+	expiresAt := "never"
+	if event.Key.ExpiresAt != nil {
+		expiresAt = event.Key.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+
+	log.Printf(
+		"⚠️  ALERTA event=%s key=%s name=%q prefix=%s expires_at=%s",
+		event.Type,
+		event.Key.ID,
+		event.Key.Name,
+		event.Key.Prefix,
+		expiresAt,
+	)
+	return nil
+}
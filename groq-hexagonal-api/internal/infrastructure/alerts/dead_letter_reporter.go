@@ -0,0 +1,36 @@
+package alerts
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// DEAD LETTERS EN EL LOG
+// ============================================================================
+
+// LogDeadLetterReporter escribe las entregas de callbacks fallidas en el log,
+// con el mismo formato clave=valor que los avisos de modelos retirados
+// (ej: contar o reenviar las líneas con event=callback_dead_letter)
+type LogDeadLetterReporter struct{}
+
+// NewLogDeadLetterReporter crea el reporter
+func NewLogDeadLetterReporter() *LogDeadLetterReporter {
+	return &LogDeadLetterReporter{}
+}
+
+// ReportDeadLetter implementa domain.DeadLetterReporter
+func (r *LogDeadLetterReporter) ReportDeadLetter(ctx context.Context, letter domain.DeadLetter) {
+	log.Printf(
+		"❌ DEAD LETTER event=callback_dead_letter job=%s client=%s url=%s attempts=%d failed_at=%s error=%s",
+		letter.JobID,
+		letter.Client,
+		letter.URL,
+		letter.Attempts,
+		letter.FailedAt.UTC().Format(time.RFC3339),
+		strconv.Quote(letter.LastError),
+	)
+}
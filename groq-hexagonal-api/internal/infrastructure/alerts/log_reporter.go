@@ -0,0 +1,62 @@
+// Package alerts implementa los avisos para operadores (adaptadores secundarios)
+package alerts
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// AVISOS EN EL LOG
+// ============================================================================
+
+// LogDeprecationReporter escribe los avisos de modelos retirados en el log
+// con formato clave=valor, fácil de filtrar o convertir en métrica
+// (ej: contar las líneas con event=model_decommissioned)
+type LogDeprecationReporter struct{}
+
+// NewLogDeprecationReporter crea el reporter
+func NewLogDeprecationReporter() *LogDeprecationReporter {
+	return &LogDeprecationReporter{}
+}
+
+// ReportDecommissioned implementa domain.DeprecationReporter
+func (r *LogDeprecationReporter) ReportDecommissioned(ctx context.Context, event domain.DecommissionEvent) {
+	replacement := event.Replacement
+	if replacement == "" {
+		replacement = "ninguno"
+	}
+
+	log.Printf(
+		"⚠️  ALERTA event=model_decommissioned model=%s replacement=%s tenant=%s still_requested=%s",
+		event.Model,
+		replacement,
+		domain.TenantFromContext(ctx),
+		formatCounts(event.StillRequested),
+	)
+}
+
+// formatCounts formatea el map como "modelo1:3,modelo2:1" en orden alfabético
+// (el orden de iteración de un map en Go es aleatorio)
+func formatCounts(counts map[string]int) string {
+	models := make([]string, 0, len(counts))
+	for model := range counts {
+		models = append(models, model)
+	}
+	sort.Strings(models)
+
+	var b strings.Builder
+	for i, model := range models {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(model)
+		b.WriteByte(':')
+		b.WriteString(strconv.Itoa(counts[model]))
+	}
+	return b.String()
+}
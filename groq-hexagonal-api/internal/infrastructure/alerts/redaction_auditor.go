@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// AUDITORÍA DE ANONIMIZACIÓN EN EL LOG
+// ============================================================================
+
+// LogRedactionAuditor escribe en el log las peticiones anonimizadas antes de
+// llamar al proveedor, con el formato clave=valor de los demás avisos
+// (ej: contar las líneas con event=pii_redacted por tenant)
+// Solo se escriben los tipos y marcadores: nunca los valores originales
+type LogRedactionAuditor struct{}
+
+// NewLogRedactionAuditor crea el auditor
+func NewLogRedactionAuditor() *LogRedactionAuditor {
+	return &LogRedactionAuditor{}
+}
+
+// RecordRedaction implementa domain.RedactionAuditor
+func (a *LogRedactionAuditor) RecordRedaction(ctx context.Context, audit domain.RedactionAudit) {
+	// Cada entidad como mensaje:tipo:marcador (ej: 0:email:[EMAIL_1])
+	entities := make([]string, len(audit.Entities))
+	for i, entry := range audit.Entities {
+		entities[i] = strconv.Itoa(entry.Message) + ":" + entry.Type + ":" + entry.Placeholder
+	}
+
+	log.Printf(
+		"🔒 PII event=pii_redacted tenant=%s source=%s model=%s count=%d entities=%s at=%s",
+		audit.Tenant,
+		audit.Source,
+		audit.Model,
+		len(audit.Entities),
+		strings.Join(entities, ","),
+		audit.At.UTC().Format(time.RFC3339),
+	)
+}
@@ -0,0 +1,149 @@
+// Package cassette graba las llamadas a un proveedor y las reproduce después
+//
+// Recorder decora un domain.LLMRepository real y guarda cada petición con su
+// respuesta en un archivo; Replayer implementa domain.LLMRepository leyendo
+// esos archivos, sin red ni API key. Con los dos, los tests de integración de
+// los handlers y los servicios usan respuestas reales de Groq y dan siempre
+// el mismo resultado.
+package cassette
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ============================================================================
+// GRABACIONES
+// ============================================================================
+//
+// Cada llamada es un archivo JSON en el directorio, con nombre
+// <tipo>-<hash>.json: el tipo es chat, stream, models o proxy y el hash sale
+// de la petición serializada. La misma petición da el mismo archivo, así que
+// grabar otra vez sustituye la respuesta anterior.
+//
+// Los archivos tienen la petición (para saber de dónde salió la respuesta al
+// revisarlos) y la respuesta: la completa, los fragmentos del streaming o el
+// status, las cabeceras y el body del proxy. Solo se graban las llamadas que
+// terminan bien: un error del proveedor no se guarda y la petición se puede
+// volver a grabar.
+// ============================================================================
+
+// Tipos de llamada
+const (
+	kindChat   = "chat"
+	kindStream = "stream"
+	kindModels = "models"
+	kindProxy  = "proxy"
+)
+
+// ErrNotRecorded se retorna al reproducir una petición que no se grabó
+var ErrNotRecorded = errors.New("no hay grabación para la petición")
+
+// interaction es el contenido de un archivo: una petición y su respuesta
+type interaction struct {
+	Kind       string    `json:"kind"`
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// Request es la petición tal como se envió (ChatRequest o el body del proxy)
+	Request json.RawMessage `json:"request,omitempty"`
+
+	// Solo uno de estos, según Kind
+	Response *domain.ChatResponse      `json:"response,omitempty"`
+	Chunks   []*domain.ChatStreamChunk `json:"chunks,omitempty"`
+	Models   *domain.ModelsResponse    `json:"models,omitempty"`
+	Proxy    *proxyRecording           `json:"proxy,omitempty"`
+}
+
+// proxyRecording es la respuesta sin procesar del modo proxy
+type proxyRecording struct {
+	StatusCode int                 `json:"status_code"`
+	Header     map[string][]string `json:"header,omitempty"`
+
+	// Body es texto: JSON o los eventos SSE
+	Body string `json:"body"`
+}
+
+// key es el nombre del archivo de una petición
+// request son los bytes que identifican la petición (nil en ListModels)
+func key(kind string, request []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(kind))
+	hash.Write([]byte{0})
+	hash.Write(request)
+	return kind + "-" + hex.EncodeToString(hash.Sum(nil))[:16] + ".json"
+}
+
+// chatKey es el nombre del archivo de una petición de chat
+func chatKey(kind string, request domain.ChatRequest) (string, json.RawMessage, error) {
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", nil, fmt.Errorf("error al serializar la petición: %w", err)
+	}
+	return key(kind, data), data, nil
+}
+
+// proxyKey es el nombre del archivo de una petición del proxy
+// El body se guarda tal cual si es JSON (lo normal) o como cadena si no
+func proxyKey(body []byte, stream bool) (string, json.RawMessage) {
+	kind := kindProxy
+	if stream {
+		kind += "-stream"
+	}
+	request := json.RawMessage(body)
+	if !json.Valid(body) {
+		request, _ = json.Marshal(string(body))
+	}
+	return key(kind, body), request
+}
+
+// save escribe la grabación en dir/name
+// Se escribe en un temporal y se renombra: nunca queda un archivo a medias
+func save(dir, name string, recording *interaction) error {
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al serializar la grabación: %w", err)
+	}
+
+	file, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error al crear la grabación: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("error al escribir la grabación: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error al escribir la grabación: %w", err)
+	}
+	if err := os.Rename(file.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("error al guardar la grabación: %w", err)
+	}
+	return nil
+}
+
+// load lee la grabación dir/name (ErrNotRecorded si no existe)
+func load(dir, name string) (*interaction, error) {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w (%s)", ErrNotRecorded, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la grabación: %w", err)
+	}
+
+	var recording interaction
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("grabación %s inválida: %w", name, err)
+	}
+	return &recording, nil
+}
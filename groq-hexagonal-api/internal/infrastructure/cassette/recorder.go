@@ -0,0 +1,155 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"os"
+	"time"
+)
+
+// Recorder decora un proveedor real y graba cada llamada que termina bien
+// Implementa domain.LLMRepository
+type Recorder struct {
+	next domain.LLMRepository
+	dir  string
+
+	// now da la fecha de las grabaciones
+	now func() time.Time
+}
+
+// NewRecorder crea el grabador de next en dir (se crea si no existe)
+func NewRecorder(next domain.LLMRepository, dir string) (*Recorder, error) {
+	if next == nil {
+		panic("next no puede ser nil")
+	}
+	if dir == "" {
+		panic("dir no puede estar vacío")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error al crear el directorio de grabaciones: %w", err)
+	}
+	return &Recorder{next: next, dir: dir, now: time.Now}, nil
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (r *Recorder) CreateChatCompletion(ctx context.Context, request domain.ChatRequest) (*domain.ChatResponse, error) {
+	response, err := r.next.CreateChatCompletion(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	name, data, err := chatKey(kindChat, request)
+	if err == nil {
+		r.save(name, &interaction{Kind: kindChat, Request: data, Response: response})
+	}
+	return response, nil
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+// El flujo se graba cuando el llamador lo lee hasta el final
+func (r *Recorder) CreateChatCompletionStream(ctx context.Context, request domain.ChatRequest) (domain.ChatStream, error) {
+	stream, err := r.next.CreateChatCompletionStream(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	name, data, err := chatKey(kindStream, request)
+	if err != nil {
+		return stream, nil
+	}
+	return &recordingStream{
+		ChatStream: stream,
+		onEOF: func(chunks []*domain.ChatStreamChunk) {
+			r.save(name, &interaction{Kind: kindStream, Request: data, Chunks: chunks})
+		},
+	}, nil
+}
+
+// ListModels implementa domain.LLMRepository
+func (r *Recorder) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	models, err := r.next.ListModels(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.save(key(kindModels, nil), &interaction{Kind: kindModels, Models: models})
+	return models, nil
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+// El body se graba cuando el llamador lo lee hasta el final; los errores HTTP
+// del proveedor (4xx/5xx) también, porque el proxy los reenvía tal cual
+func (r *Recorder) ProxyChatCompletion(ctx context.Context, body []byte, stream bool) (*domain.ProxyResponse, error) {
+	response, err := r.next.ProxyChatCompletion(ctx, body, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	name, request := proxyKey(body, stream)
+	response.Body = &recordingBody{
+		ReadCloser: response.Body,
+		onEOF: func(data []byte) {
+			r.save(name, &interaction{Kind: kindProxy, Request: request, Proxy: &proxyRecording{
+				StatusCode: response.StatusCode,
+				Header:     response.Header,
+				Body:       string(data),
+			}})
+		},
+	}
+	return response, nil
+}
+
+// save escribe la grabación; un fallo no afecta a la respuesta, solo se registra
+func (r *Recorder) save(name string, recording *interaction) {
+	recording.RecordedAt = r.now().UTC()
+	if err := save(r.dir, name, recording); err != nil {
+		log.Printf("⚠️  No se pudo grabar %s: %v", name, err)
+	}
+}
+
+// recordingStream guarda los fragmentos a medida que se leen
+// Implementa domain.ChatStream
+type recordingStream struct {
+	domain.ChatStream
+
+	chunks []*domain.ChatStreamChunk
+	onEOF  func(chunks []*domain.ChatStreamChunk)
+}
+
+// Recv implementa domain.ChatStream
+func (s *recordingStream) Recv() (*domain.ChatStreamChunk, error) {
+	chunk, err := s.ChatStream.Recv()
+	if err == io.EOF && s.onEOF != nil {
+		s.onEOF(s.chunks)
+		s.onEOF = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.chunks = append(s.chunks, chunk)
+	return chunk, nil
+}
+
+// recordingBody guarda el body a medida que se lee
+type recordingBody struct {
+	io.ReadCloser
+
+	data  bytes.Buffer
+	onEOF func(data []byte)
+}
+
+// Read implementa io.Reader
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.data.Write(p[:n])
+	if err == io.EOF && b.onEOF != nil {
+		b.onEOF(b.data.Bytes())
+		b.onEOF = nil
+	}
+	return n, err
+}
@@ -0,0 +1,99 @@
+package cassette
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"strings"
+)
+
+// Replayer responde con las llamadas grabadas por Recorder, sin red
+// Una petición que no se grabó retorna ErrNotRecorded
+// Implementa domain.LLMRepository
+type Replayer struct {
+	dir string
+}
+
+// NewReplayer crea el reproductor de las grabaciones de dir
+func NewReplayer(dir string) *Replayer {
+	if dir == "" {
+		panic("dir no puede estar vacío")
+	}
+	return &Replayer{dir: dir}
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (r *Replayer) CreateChatCompletion(ctx context.Context, request domain.ChatRequest) (*domain.ChatResponse, error) {
+	name, _, err := chatKey(kindChat, request)
+	if err != nil {
+		return nil, err
+	}
+	recording, err := load(r.dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return recording.Response, nil
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+// Los fragmentos se entregan seguidos, sin las pausas del original
+func (r *Replayer) CreateChatCompletionStream(ctx context.Context, request domain.ChatRequest) (domain.ChatStream, error) {
+	name, _, err := chatKey(kindStream, request)
+	if err != nil {
+		return nil, err
+	}
+	recording, err := load(r.dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return &replayStream{ctx: ctx, chunks: recording.Chunks}, nil
+}
+
+// ListModels implementa domain.LLMRepository
+func (r *Replayer) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	recording, err := load(r.dir, key(kindModels, nil))
+	if err != nil {
+		return nil, err
+	}
+	return recording.Models, nil
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+func (r *Replayer) ProxyChatCompletion(ctx context.Context, body []byte, stream bool) (*domain.ProxyResponse, error) {
+	name, _ := proxyKey(body, stream)
+	recording, err := load(r.dir, name)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ProxyResponse{
+		StatusCode: recording.Proxy.StatusCode,
+		Header:     recording.Proxy.Header,
+		Body:       io.NopCloser(strings.NewReader(recording.Proxy.Body)),
+	}, nil
+}
+
+// replayStream entrega los fragmentos grabados
+// Implementa domain.ChatStream
+type replayStream struct {
+	ctx    context.Context
+	chunks []*domain.ChatStreamChunk
+}
+
+// Recv implementa domain.ChatStream
+func (s *replayStream) Recv() (*domain.ChatStreamChunk, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+// Close implementa domain.ChatStream
+func (s *replayStream) Close() error {
+	s.chunks = nil
+	return nil
+}
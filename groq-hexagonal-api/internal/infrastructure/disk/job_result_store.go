@@ -0,0 +1,161 @@
+// Package disk guarda en el sistema de archivos local
+// Implementa domain.JobResultStore con un archivo por job y
+// domain.ReplayCorpus con un archivo por muestra
+package disk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// RESULTADOS DE JOBS EN DISCO
+// ============================================================================
+//
+// Cada resultado es un archivo <id>.json en el directorio. Se escribe en un
+// archivo temporal y se renombra al terminar: quien lo abre nunca ve un
+// resultado a medias.
+//
+// Los archivos duran lo mismo que los jobs (retention desde que se
+// escriben). Los caducados se eliminan al guardar, como mucho una vez por
+// minuto, como en memory.JobRepository.
+//
+// Cada réplica escribe en su directorio: con varias réplicas, el directorio
+// debe ser un volumen compartido para que cualquiera sirva el resultado.
+// ============================================================================
+
+// resultSweepInterval es cada cuánto se buscan resultados caducados
+const resultSweepInterval = time.Minute
+
+// JobResultStore guarda los resultados de los jobs en un directorio
+// Implementa domain.JobResultStore
+type JobResultStore struct {
+	dir       string
+	retention time.Duration
+
+	mu sync.Mutex
+	// lastSweep es la última vez que se eliminaron los resultados caducados
+	lastSweep time.Time
+}
+
+// NewJobResultStore crea el almacén en dir (se crea si no existe)
+// retention es cuánto se conserva cada resultado
+func NewJobResultStore(dir string, retention time.Duration) (*JobResultStore, error) {
+	if dir == "" {
+		panic("dir no puede estar vacío")
+	}
+	if retention <= 0 {
+		panic("retention debe ser mayor que 0")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error al crear el directorio de resultados: %w", err)
+	}
+	return &JobResultStore{dir: dir, retention: retention}, nil
+}
+
+// SaveResult implementa domain.JobResultStore
+func (s *JobResultStore) SaveResult(ctx context.Context, jobID string, write func(w io.Writer) error) (int64, error) {
+	path, err := s.path(jobID)
+	if err != nil {
+		return 0, err
+	}
+	s.sweep()
+
+	file, err := os.CreateTemp(s.dir, jobID+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("error al crear el resultado: %w", err)
+	}
+	// Si algo falla, el temporal no debe quedarse en el directorio
+	defer os.Remove(file.Name())
+
+	counter := &countingWriter{w: file}
+	if err := write(counter); err != nil {
+		file.Close()
+		return 0, fmt.Errorf("error al escribir el resultado: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("error al escribir el resultado: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return 0, fmt.Errorf("error al guardar el resultado: %w", err)
+	}
+	return counter.n, nil
+}
+
+// OpenResult implementa domain.JobResultStore
+func (s *JobResultStore) OpenResult(ctx context.Context, jobID string) (io.ReadSeekCloser, error) {
+	path, err := s.path(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir el resultado: %w", err)
+	}
+	return file, nil
+}
+
+// path es el archivo del job
+// El ID se valida: llega de la URL y no puede salir del directorio
+func (s *JobResultStore) path(jobID string) (string, error) {
+	if jobID == "" || strings.ContainsFunc(jobID, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+	}) {
+		return "", domain.ErrJobNotFound
+	}
+	return filepath.Join(s.dir, jobID+".json"), nil
+}
+
+// sweep elimina los resultados caducados (y los temporales abandonados)
+func (s *JobResultStore) sweep() {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Sub(s.lastSweep) < resultSweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		log.Printf("⚠️  Error al leer el directorio de resultados: %v", err)
+		return
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() || now.Sub(info.ModTime()) < s.retention {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("⚠️  Error al eliminar el resultado %s: %v", entry.Name(), err)
+		}
+	}
+}
+
+// countingWriter cuenta los bytes escritos
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
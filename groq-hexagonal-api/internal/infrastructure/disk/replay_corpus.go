@@ -0,0 +1,144 @@
+package disk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// CORPUS DE REPRODUCCIÓN EN DISCO
+// ============================================================================
+//
+// Cada muestra es un archivo <fecha>-<id>.json en el directorio (la fecha
+// primero: ordenados por nombre, quedan en el orden en que se grabaron). Se
+// escriben como los resultados de los jobs: en un temporal que se renombra.
+//
+// El corpus no caduca: es un conjunto de pruebas y se borra o se recorta a
+// mano. Para que no llene el disco, admite como mucho maxSamples archivos
+// (contando los que ya había al arrancar).
+// ============================================================================
+
+// replaySampleSuffix es la extensión de las muestras
+const replaySampleSuffix = ".json"
+
+// ReplayCorpus guarda las muestras en un directorio
+// Implementa domain.ReplayCorpus
+type ReplayCorpus struct {
+	dir        string
+	maxSamples int
+
+	mu sync.Mutex
+	// samples es el número de muestras del directorio
+	samples int
+}
+
+// NewReplayCorpus crea el corpus en dir (se crea si no existe)
+// maxSamples es el máximo de muestras (0 = sin máximo)
+func NewReplayCorpus(dir string, maxSamples int) (*ReplayCorpus, error) {
+	if dir == "" {
+		panic("dir no puede estar vacío")
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("error al crear el directorio del corpus: %w", err)
+	}
+	files, err := replaySampleFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &ReplayCorpus{dir: dir, maxSamples: maxSamples, samples: len(files)}, nil
+}
+
+// SaveSample implementa domain.ReplayCorpus
+func (c *ReplayCorpus) SaveSample(ctx context.Context, sample *domain.ReplaySample) error {
+	c.mu.Lock()
+	if c.maxSamples > 0 && c.samples >= c.maxSamples {
+		c.mu.Unlock()
+		return domain.ErrReplayCorpusFull
+	}
+	c.samples++
+	c.mu.Unlock()
+
+	saved := false
+	defer func() {
+		if !saved {
+			c.mu.Lock()
+			c.samples--
+			c.mu.Unlock()
+		}
+	}()
+
+	data, err := json.MarshalIndent(sample, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error al serializar la muestra: %w", err)
+	}
+
+	name := sample.RecordedAt.UTC().Format("20060102T150405.000000000Z") + "-" + sample.ID + replaySampleSuffix
+	file, err := os.CreateTemp(c.dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error al crear la muestra: %w", err)
+	}
+	// Si algo falla, el temporal no debe quedarse en el directorio
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("error al escribir la muestra: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error al escribir la muestra: %w", err)
+	}
+	if err := os.Rename(file.Name(), filepath.Join(c.dir, name)); err != nil {
+		return fmt.Errorf("error al guardar la muestra: %w", err)
+	}
+	saved = true
+	return nil
+}
+
+// ReadReplayCorpus lee las muestras de dir en el orden en que se grabaron
+// (lo usa el comando replay del cliente de terminal)
+func ReadReplayCorpus(dir string) ([]domain.ReplaySample, error) {
+	files, err := replaySampleFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]domain.ReplaySample, 0, len(files))
+	for _, name := range files {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("error al leer la muestra %s: %w", name, err)
+		}
+		var sample domain.ReplaySample
+		if err := json.Unmarshal(data, &sample); err != nil {
+			return nil, fmt.Errorf("muestra %s inválida: %w", name, err)
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+// replaySampleFiles retorna los nombres de las muestras de dir, ordenados
+// (sin los temporales de las que se están escribiendo)
+func replaySampleFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el directorio del corpus: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), replaySampleSuffix) {
+			files = append(files, entry.Name())
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
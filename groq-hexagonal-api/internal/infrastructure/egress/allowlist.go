@@ -0,0 +1,134 @@
+// Package egress limita los hosts a los que el servidor puede conectarse
+package egress
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// LISTA DE SALIDA (EGRESS ALLOWLIST)
+// ============================================================================
+//
+// Cualquier función que acepte una URL del cliente (callbacks de jobs, y en
+// el futuro ingesta de páginas o tools que llaman a APIs) abre la puerta a
+// SSRF: el servidor hace de puente hacia la red interna o los metadatos de
+// la nube. Con la lista activada, los clientes HTTP de salida solo se
+// conectan a:
+//
+//   - Los hosts de los proveedores configurados (GROQ_BASE_URL...)
+//   - El webhook de API keys (API_KEY_WEBHOOK_URL)
+//   - EGRESS_ALLOWED_HOSTS: nombres exactos o "*.dominio" (subdominios)
+//
+// La comprobación está en el http.RoundTripper, así que cubre también cada
+// salto de una redirección. Se compara el nombre de la URL, no la IP: para
+// las direcciones internas resueltas por DNS está el filtro de los callbacks
+// (ver http.rejectPrivateAddress).
+// ============================================================================
+
+// ErrHostNotAllowed se retorna al intentar conectar a un host fuera de la lista
+var ErrHostNotAllowed = errors.New("host fuera de la lista de salida")
+
+// Allowlist es la lista de hosts permitidos
+// Es seguro para uso concurrente (solo se lee después de crearla)
+type Allowlist struct {
+	hosts map[string]bool
+
+	// suffixes son los dominios de las entradas "*.dominio", con el punto
+	suffixes []string
+}
+
+// NewAllowlist crea la lista con nombres de host, "*.dominio" o URLs (se
+// usa su host); las entradas vacías se ignoran
+func NewAllowlist(entries []string) *Allowlist {
+	allowlist := &Allowlist{hosts: make(map[string]bool)}
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		switch {
+		case entry == "":
+		case strings.HasPrefix(entry, "*."):
+			allowlist.suffixes = append(allowlist.suffixes, entry[1:])
+		case strings.Contains(entry, "://"):
+			if host := HostOf(entry); host != "" {
+				allowlist.hosts[host] = true
+			}
+		default:
+			allowlist.hosts[entry] = true
+		}
+	}
+	return allowlist
+}
+
+// HostOf retorna el host de una URL en minúsculas, sin puerto ("" si no es
+// una URL válida)
+func HostOf(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// Allows indica si se puede conectar a host
+func (a *Allowlist) Allows(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if a.hosts[host] {
+		return true
+	}
+	for _, suffix := range a.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Entries retorna las entradas de la lista, ordenadas (para el log)
+func (a *Allowlist) Entries() []string {
+	entries := make([]string, 0, len(a.hosts)+len(a.suffixes))
+	for host := range a.hosts {
+		entries = append(entries, host)
+	}
+	for _, suffix := range a.suffixes {
+		entries = append(entries, "*"+suffix)
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+// Wrap retorna next con la comprobación de la lista delante
+// Con la lista nil (desactivada) retorna next tal cual
+func (a *Allowlist) Wrap(next http.RoundTripper) http.RoundTripper {
+	if a == nil {
+		return next
+	}
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &guardedTransport{allowlist: a, next: next}
+}
+
+// guardedTransport rechaza las peticiones a hosts fuera de la lista
+type guardedTransport struct {
+	allowlist *Allowlist
+	next      http.RoundTripper
+}
+
+// RoundTrip implementa http.RoundTripper
+func (t *guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !t.allowlist.Allows(host) {
+		// RoundTrip debe cerrar el body aunque no envíe la petición
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		log.Printf("🚫 Conexión de salida bloqueada: %s no está en la lista", host)
+		return nil, fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,70 @@
+// Package groq - Embeddings (API compatible con OpenAI)
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"time"
+)
+
+// EmbeddingsEndpoint es el endpoint de embeddings de la API de OpenAI
+// Groq no lo tiene: este cliente se usa con OpenAI o una API compatible
+// (ver openai.NewEmbeddingClient)
+const EmbeddingsEndpoint = "/embeddings"
+
+// NewEmbeddingClient crea un cliente de embeddings para una API compatible
+// con la de OpenAI
+func NewEmbeddingClient(apiKey, baseURL string, timeout time.Duration, opts ...ClientOption) domain.EmbeddingRepository {
+	return newClient(apiKey, baseURL, timeout, opts...)
+}
+
+// embeddingsRequest es el body de POST /embeddings
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embeddingsResponse es la respuesta de POST /embeddings
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float64 `json:"embedding"`
+	} `json:"data"`
+}
+
+// CreateEmbeddings implementa domain.EmbeddingRepository
+func (c *GroqClient) CreateEmbeddings(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar request: %w", err)
+	}
+
+	response, header, err := c.doRequest(ctx, http.MethodPost, c.baseURL+EmbeddingsEndpoint, body)
+	c.observeRateLimit(model, header)
+	if err != nil {
+		return nil, fmt.Errorf("error en la petición HTTP: %w", err)
+	}
+
+	var parsed embeddingsResponse
+	if err := json.Unmarshal(response, &parsed); err != nil {
+		return nil, fmt.Errorf("error al parsear los embeddings: %w", err)
+	}
+
+	// Los vectores traen su posición: no tienen por qué llegar en orden
+	vectors := make([][]float64, len(texts))
+	for _, item := range parsed.Data {
+		if item.Index < 0 || item.Index >= len(vectors) {
+			return nil, fmt.Errorf("embedding con índice inválido: %d", item.Index)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if len(vector) == 0 {
+			return nil, fmt.Errorf("falta el embedding del texto %d", i)
+		}
+	}
+	return vectors, nil
+}
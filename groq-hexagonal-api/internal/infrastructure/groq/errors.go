@@ -0,0 +1,129 @@
+// Package groq - Traducción de los errores de la API de Groq
+package groq
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// ERRORES DE LA API
+// ============================================================================
+//
+// Groq responde a los errores con un body compatible con OpenAI:
+//
+//   {"error": {"message": "...", "type": "invalid_request_error", "code": "model_decommissioned"}}
+//
+// Se traducen a errores del dominio (domain.UpstreamError) mirando primero el
+// código de error (más específico) y después el status HTTP.
+// ============================================================================
+
+// Códigos de error de Groq que se traducen al dominio
+const (
+	codeModelDecommissioned   = "model_decommissioned"
+	codeModelNotFound         = "model_not_found"
+	codeContextLengthExceeded = "context_length_exceeded"
+	codeRateLimitExceeded     = "rate_limit_exceeded"
+)
+
+// apiErrorBody es el formato de los errores de Groq
+type apiErrorBody struct {
+	Error struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+	} `json:"error"`
+}
+
+// newAPIError construye el error de una respuesta no 2xx
+func newAPIError(resp *http.Response, body []byte) error {
+	var parsed apiErrorBody
+	// Si el body no es JSON, parsed queda vacío y se usa el status
+	_ = json.Unmarshal(body, &parsed)
+
+	message := parsed.Error.Message
+	if message == "" {
+		message = fmt.Sprintf("API retornó status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return &domain.UpstreamError{
+		Kind:       classifyAPIError(resp.StatusCode, parsed.Error.Code, parsed.Error.Type),
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+}
+
+// classifyAPIError elige la categoría del dominio del error
+func classifyAPIError(statusCode int, code, errType string) error {
+	// Algunos errores llegan con el código en "type" en lugar de "code"
+	for _, value := range []string{code, errType} {
+		switch value {
+		case codeModelDecommissioned:
+			return domain.ErrModelDecommissioned
+		case codeModelNotFound:
+			return domain.ErrModelNotFound
+		case codeContextLengthExceeded:
+			return domain.ErrContextTooLong
+		case codeRateLimitExceeded:
+			return domain.ErrRateLimited
+		}
+	}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return domain.ErrRateLimited
+	case statusCode == http.StatusNotFound:
+		return domain.ErrModelNotFound
+	case statusCode == http.StatusRequestEntityTooLarge:
+		return domain.ErrContextTooLong
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return domain.ErrUpstreamAuth
+	case statusCode == http.StatusGatewayTimeout:
+		return domain.ErrUpstreamTimeout
+	case statusCode >= 500:
+		return domain.ErrUpstreamUnavailable
+	default:
+		return domain.ErrInvalidRequest
+	}
+}
+
+// newTransportError traduce los errores sin respuesta (red, timeouts...)
+func newTransportError(ctx context.Context, err error) error {
+	// Si el cliente canceló la petición, se propaga tal cual (no es culpa de Groq)
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return fmt.Errorf("error al ejecutar request: %w", err)
+	}
+
+	kind := domain.ErrUpstreamUnavailable
+	// net.Error con Timeout() cubre tanto http.Client.Timeout como los deadlines
+	var timeoutErr interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeoutErr) && timeoutErr.Timeout()) {
+		kind = domain.ErrUpstreamTimeout
+	}
+
+	return &domain.UpstreamError{Kind: kind, Message: err.Error()}
+}
+
+// parseRetryAfter interpreta la cabecera Retry-After (en segundos)
+// Groq también puede enviar una fecha HTTP; en ese caso se calcula la espera
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
@@ -24,6 +24,7 @@ const (
 	
 	// Headers HTTP
 	ContentTypeJSON   = "application/json"
+	ContentTypeSSE    = "text/event-stream"
 	AuthorizationHeader = "Authorization"
 )
 
@@ -31,7 +32,7 @@ const (
 // CLIENT STRUCT
 // ============================================================================
 
-// GroqClient es el adaptador HTTP que implementa domain.GroqRepository
+// GroqClient es el adaptador HTTP que implementa domain.LLMRepository
 // Implementa la interfaz implícitamente (no necesita declararlo)
 type GroqClient struct {
 	// httpClient es el cliente HTTP estándar de Go
@@ -43,6 +44,58 @@ type GroqClient struct {
 	
 	// apiKey es la clave de autenticación
 	apiKey string
+	
+	// streamClient se usa para las peticiones en streaming y para las que
+	// traen su propio plazo en el contexto (ej: X-Request-Timeout)
+	// No tiene Timeout global: un flujo largo es normal y se controla con el contexto
+	streamClient *http.Client
+	
+	// streamUsage pide el uso de tokens al final del streaming (stream_options)
+	// Groq lo envía siempre en x_groq; OpenAI solo si se pide
+	streamUsage bool
+	
+	// rateLimitObserver recibe las cabeceras x-ratelimit-* (nil = se ignoran)
+	rateLimitObserver domain.RateLimitObserver
+	
+	// settings aporta el timeout vigente (nil = el de httpClient)
+	settings domain.SettingsSource
+}
+
+// ClientOption configura aspectos opcionales del cliente
+type ClientOption func(*GroqClient)
+
+// WithStreamUsage pide el uso de tokens en el último fragmento del streaming
+// Necesario en APIs compatibles que no lo envían por defecto (ej: OpenAI)
+func WithStreamUsage() ClientOption {
+	return func(c *GroqClient) {
+		c.streamUsage = true
+	}
+}
+
+// WithRateLimitObserver informa del margen de rate limit de cada respuesta
+func WithRateLimitObserver(observer domain.RateLimitObserver) ClientOption {
+	return func(c *GroqClient) {
+		c.rateLimitObserver = observer
+	}
+}
+
+// WithRuntimeSettings toma el timeout de los ajustes vigentes en cada
+// petición, de modo que una recarga de la configuración lo cambia
+func WithRuntimeSettings(settings domain.SettingsSource) ClientOption {
+	return func(c *GroqClient) {
+		c.settings = settings
+	}
+}
+
+// WithTransportWrapper envuelve el transporte de los dos clientes HTTP
+// (ej: egress.Allowlist.Wrap, que limita los hosts de salida)
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *GroqClient) {
+		// Un solo transporte envuelto: los dos clientes comparten el pool
+		transport := wrap(c.httpClient.Transport)
+		c.httpClient.Transport = transport
+		c.streamClient.Transport = transport
+	}
 }
 
 // ============================================================================
@@ -55,10 +108,17 @@ type GroqClient struct {
 //   - apiKey: tu API key de Groq
 //   - baseURL: URL base de la API
 //   - timeout: tiempo máximo de espera para requests
+//   - opts: configuración opcional (ver ClientOption)
 //
 // Retorna:
-//   - domain.GroqRepository: retornamos la interfaz (buena práctica)
-func NewGroqClient(apiKey, baseURL string, timeout time.Duration) domain.GroqRepository {
+//   - domain.LLMRepository: retornamos la interfaz (buena práctica)
+func NewGroqClient(apiKey, baseURL string, timeout time.Duration, opts ...ClientOption) domain.LLMRepository {
+	return newClient(apiKey, baseURL, timeout, opts...)
+}
+
+// newClient crea el cliente concreto (lo comparten NewGroqClient y
+// NewTranscriptionClient)
+func newClient(apiKey, baseURL string, timeout time.Duration, opts ...ClientOption) *GroqClient {
 	// Validación básica
 	if apiKey == "" {
 		panic("apiKey no puede estar vacía")
@@ -69,30 +129,37 @@ func NewGroqClient(apiKey, baseURL string, timeout time.Duration) domain.GroqRep
 	
 	// Crear el cliente HTTP con timeout
 	// &http.Client{...} crea un puntero a http.Client
+	// Transport controla cómo se hacen las conexiones HTTP
+	// Lo comparten ambos clientes para reutilizar el pool de conexiones
+	transport := &http.Transport{
+		// Configuración de connection pooling
+		MaxIdleConns:        100,              // Máx. conexiones idle totales
+		MaxIdleConnsPerHost: 10,               // Máx. conexiones idle por host
+		IdleConnTimeout:     90 * time.Second, // Tiempo antes de cerrar conexión idle
+	}
+	
 	httpClient := &http.Client{
-		Timeout: timeout, // Timeout total para cada request
-		
-		// Transport controla cómo se hacen las conexiones HTTP
-		Transport: &http.Transport{
-			// Configuración de connection pooling
-			MaxIdleConns:        100,              // Máx. conexiones idle totales
-			MaxIdleConnsPerHost: 10,               // Máx. conexiones idle por host
-			IdleConnTimeout:     90 * time.Second, // Tiempo antes de cerrar conexión idle
-		},
+		Timeout:   timeout, // Timeout total para cada request
+		Transport: transport,
 	}
 	
-	return &GroqClient{
-		httpClient: httpClient,
-		baseURL:    baseURL,
-		apiKey:     apiKey,
+	client := &GroqClient{
+		httpClient:   httpClient,
+		baseURL:      baseURL,
+		apiKey:       apiKey,
+		streamClient: &http.Client{Transport: transport},
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
+	return client
 }
 
 // ============================================================================
-// IMPLEMENTACIÓN DE domain.GroqRepository
+// IMPLEMENTACIÓN DE domain.LLMRepository
 // ============================================================================
 
-// CreateChatCompletion implementa la interfaz GroqRepository
+// CreateChatCompletion implementa la interfaz LLMRepository
 // Envía una petición POST a /chat/completions
 func (c *GroqClient) CreateChatCompletion(
 	ctx context.Context,
@@ -112,7 +179,9 @@ func (c *GroqClient) CreateChatCompletion(
 	}
 	
 	// Hacer la petición HTTP POST
-	response, err := c.doRequest(ctx, http.MethodPost, url, jsonData)
+	// Las cabeceras de rate limit llegan también en los errores (ej: 429)
+	response, header, err := c.doRequest(ctx, http.MethodPost, url, jsonData)
+	c.observeRateLimit(request.Model, header)
 	if err != nil {
 		return nil, fmt.Errorf("error en la petición HTTP: %w", err)
 	}
@@ -128,7 +197,7 @@ func (c *GroqClient) CreateChatCompletion(
 	return &chatResponse, nil
 }
 
-// ListModels implementa la interfaz GroqRepository
+// ListModels implementa la interfaz LLMRepository
 // Envía una petición GET a /models
 func (c *GroqClient) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
 	// Construir la URL completa
@@ -136,7 +205,7 @@ func (c *GroqClient) ListModels(ctx context.Context) (*domain.ModelsResponse, er
 	
 	// Hacer la petición HTTP GET
 	// nil porque GET no lleva body
-	response, err := c.doRequest(ctx, http.MethodGet, url, nil)
+	response, _, err := c.doRequest(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("error al obtener modelos: %w", err)
 	}
@@ -165,51 +234,57 @@ func (c *GroqClient) ListModels(ctx context.Context) (*domain.ModelsResponse, er
 //
 // Retorna:
 //   - []byte: respuesta del servidor en bytes
+//   - http.Header: cabeceras de la respuesta (nil si no hubo respuesta)
 //   - error: error si algo falla
 func (c *GroqClient) doRequest(
 	ctx context.Context,
 	method string,
 	url string,
 	body []byte,
-) ([]byte, error) {
+) ([]byte, http.Header, error) {
 	// ========================================================================
-	// 1. CREAR LA PETICIÓN HTTP
+	// 1-2. CREAR LA PETICIÓN HTTP Y CONFIGURAR HEADERS
 	// ========================================================================
 	
-	// bytes.NewBuffer() crea un io.Reader desde []byte
-	// io.Reader es una interfaz que http.NewRequest espera
-	var bodyReader io.Reader
-	if body != nil {
-		bodyReader = bytes.NewBuffer(body)
-	}
-	
-	// Crear la petición HTTP
-	// http.NewRequestWithContext incluye el contexto para cancelaciones
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	req, err := c.newRequest(ctx, method, url, body)
 	if err != nil {
-		return nil, fmt.Errorf("error al crear request: %w", err)
+		return nil, nil, err
 	}
-	
-	// ========================================================================
-	// 2. CONFIGURAR HEADERS
-	// ========================================================================
-	
-	// Establecer Content-Type
-	req.Header.Set("Content-Type", ContentTypeJSON)
-	
-	// Establecer Authorization
-	// La API de Groq usa Bearer token
-	req.Header.Set(AuthorizationHeader, "Bearer "+c.apiKey)
-	
+	return c.send(ctx, req)
+}
+
+// requestClient retorna el cliente de las peticiones sin streaming
+// Si el contexto trae un plazo, manda ese plazo: una petición rápida no tiene
+// por qué esperar el timeout global, ni una lenta quedar cortada por él.
+// Con un timeout recargado, crea un http.Client sobre el mismo Transport (no
+// cuesta nada: el pool de conexiones es del Transport)
+func (c *GroqClient) requestClient(ctx context.Context) *http.Client {
+	if _, ok := ctx.Deadline(); ok {
+		return c.streamClient
+	}
+	if c.settings == nil {
+		return c.httpClient
+	}
+	timeout := c.settings.Settings().HTTPTimeout
+	if timeout <= 0 || timeout == c.httpClient.Timeout {
+		return c.httpClient
+	}
+	return &http.Client{Timeout: timeout, Transport: c.httpClient.Transport}
+}
+
+// send ejecuta una petición ya construida y lee la respuesta
+// Separado de doRequest para las peticiones que no son JSON (ej: multipart)
+func (c *GroqClient) send(ctx context.Context, req *http.Request) ([]byte, http.Header, error) {
 	// ========================================================================
 	// 3. EJECUTAR LA PETICIÓN
 	// ========================================================================
 	
-	// c.httpClient.Do() ejecuta la petición HTTP
+	// Do() ejecuta la petición HTTP
 	// Usa el contexto para timeouts y cancelaciones
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.requestClient(ctx).Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error al ejecutar request: %w", err)
+		// newTransportError distingue timeouts de fallos de red
+		return nil, nil, newTransportError(ctx, err)
 	}
 	
 	// defer asegura que el body se cierre al final de la función
@@ -224,7 +299,7 @@ func (c *GroqClient) doRequest(
 	// io.ReadAll() lee todo el body de la respuesta
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error al leer respuesta: %w", err)
+		return nil, resp.Header, fmt.Errorf("error al leer respuesta: %w", err)
 	}
 	
 	// ========================================================================
@@ -234,18 +309,46 @@ func (c *GroqClient) doRequest(
 	// Verificar si la respuesta es exitosa (2xx)
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		// Si no es 2xx, retornar error con el status y el body
-		return nil, fmt.Errorf(
-			"API retornó status %d: %s",
-			resp.StatusCode,
-			string(responseBody),
-		)
+		// newAPIError traduce los errores conocidos a errores del dominio
+		return nil, resp.Header, newAPIError(resp, responseBody)
 	}
 	
 	// ========================================================================
 	// 6. RETORNAR RESPUESTA
 	// ========================================================================
 	
-	return responseBody, nil
+	return responseBody, resp.Header, nil
+}
+
+// newRequest crea una petición HTTP autenticada para la API de Groq
+func (c *GroqClient) newRequest(
+	ctx context.Context,
+	method string,
+	url string,
+	body []byte,
+) (*http.Request, error) {
+	// bytes.NewBuffer() crea un io.Reader desde []byte
+	// io.Reader es una interfaz que http.NewRequest espera
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewBuffer(body)
+	}
+	
+	// Crear la petición HTTP
+	// http.NewRequestWithContext incluye el contexto para cancelaciones
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear request: %w", err)
+	}
+	
+	// Establecer Content-Type
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	
+	// Establecer Authorization
+	// La API de Groq usa Bearer token
+	req.Header.Set(AuthorizationHeader, "Bearer "+c.apiKey)
+	
+	return req, nil
 }
 
 // ============================================================================
@@ -291,7 +394,7 @@ func (c *GroqClient) doRequest(
 //    - ctx.Err(): retorna el error de cancelación
 //
 // 7. INTERFACES IMPLÍCITAS:
-//    - GroqClient implementa domain.GroqRepository sin declararlo
+//    - GroqClient implementa domain.LLMRepository sin declararlo
 //    - Solo necesita tener los métodos correctos
 //    - Esto permite desacoplamiento total
 //
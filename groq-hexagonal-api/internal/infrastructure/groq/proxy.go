@@ -0,0 +1,67 @@
+// Package groq - Modo proxy (passthrough)
+package groq
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"strings"
+)
+
+// proxyHeaderPrefixes son las cabeceras de Groq que se reenvían al cliente
+// El resto (Content-Length, Connection, Set-Cookie...) las pone nuestro servidor
+var proxyHeaderPrefixes = []string{
+	"Content-Type",
+	"Retry-After",
+	"X-Ratelimit-",
+	"X-Request-Id",
+	"X-Groq-",
+}
+
+// ProxyChatCompletion implementa la interfaz LLMRepository
+// A diferencia de CreateChatCompletion, no interpreta ni la petición ni la
+// respuesta: los errores HTTP de Groq también se retornan como ProxyResponse
+func (c *GroqClient) ProxyChatCompletion(
+	ctx context.Context,
+	body []byte,
+	stream bool,
+) (*domain.ProxyResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, c.baseURL+ChatCompletionsEndpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Un flujo largo es normal: sin timeout global, solo el del contexto
+	client := c.requestClient(ctx)
+	if stream {
+		req.Header.Set("Accept", ContentTypeSSE)
+		client = c.streamClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, newTransportError(ctx, err)
+	}
+
+	// El body queda abierto: lo cerrará el llamador
+	return &domain.ProxyResponse{
+		StatusCode: resp.StatusCode,
+		Header:     filterProxyHeaders(resp.Header),
+		Body:       resp.Body,
+	}, nil
+}
+
+// filterProxyHeaders se queda con las cabeceras que interesan al cliente
+func filterProxyHeaders(header http.Header) map[string][]string {
+	filtered := make(map[string][]string)
+	for name, values := range header {
+		// http.Header guarda los nombres en forma canónica (X-Ratelimit-Limit-Requests)
+		for _, prefix := range proxyHeaderPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				filtered[name] = values
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,62 @@
+// Package groq - Cabeceras de rate limit
+package groq
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// CABECERAS X-RATELIMIT-*
+// ============================================================================
+//
+// Groq (y OpenAI) anuncian en cada respuesta cuánto margen queda:
+//
+//   x-ratelimit-limit-requests: 14400      x-ratelimit-limit-tokens: 18000
+//   x-ratelimit-remaining-requests: 14370  x-ratelimit-remaining-tokens: 17997
+//   x-ratelimit-reset-requests: 2m59.56s   x-ratelimit-reset-tokens: 7.66s
+//
+// Los límites son por modelo, así que se informa junto al modelo pedido.
+// ============================================================================
+
+// Cabeceras de rate limit (http.Header las guarda en forma canónica)
+const (
+	headerLimitRequests     = "X-Ratelimit-Limit-Requests"
+	headerRemainingRequests = "X-Ratelimit-Remaining-Requests"
+	headerResetRequests     = "X-Ratelimit-Reset-Requests"
+	headerLimitTokens       = "X-Ratelimit-Limit-Tokens"
+	headerRemainingTokens   = "X-Ratelimit-Remaining-Tokens"
+	headerResetTokens       = "X-Ratelimit-Reset-Tokens"
+)
+
+// observeRateLimit informa al observador de las cabeceras de una respuesta
+// No hace nada sin observador o si la respuesta no trae las cabeceras
+func (c *GroqClient) observeRateLimit(model string, header http.Header) {
+	if c.rateLimitObserver == nil || header.Get(headerLimitRequests) == "" && header.Get(headerLimitTokens) == "" {
+		return
+	}
+
+	c.rateLimitObserver.ObserveRateLimit(model, domain.RateLimitStatus{
+		LimitRequests:     headerInt(header, headerLimitRequests),
+		RemainingRequests: headerInt(header, headerRemainingRequests),
+		ResetRequests:     headerDuration(header, headerResetRequests),
+		LimitTokens:       headerInt(header, headerLimitTokens),
+		RemainingTokens:   headerInt(header, headerRemainingTokens),
+		ResetTokens:       headerDuration(header, headerResetTokens),
+		ObservedAt:        time.Now(),
+	})
+}
+
+// headerInt lee una cabecera numérica (0 si falta o no es un número)
+func headerInt(header http.Header, name string) int {
+	value, _ := strconv.Atoi(header.Get(name))
+	return value
+}
+
+// headerDuration lee una cabecera con formato de duración de Go ("2m59.56s")
+func headerDuration(header http.Header, name string) time.Duration {
+	value, _ := time.ParseDuration(header.Get(name))
+	return value
+}
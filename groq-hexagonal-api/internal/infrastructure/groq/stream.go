@@ -0,0 +1,184 @@
+// Package groq - Soporte de streaming (Server-Sent Events)
+package groq
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"net/http"
+)
+
+// ============================================================================
+// STREAMING CON SERVER-SENT EVENTS (SSE)
+// ============================================================================
+//
+// Con "stream": true, Groq no espera a tener la respuesta completa: envía
+// fragmentos a medida que el modelo genera tokens. El formato es SSE:
+//
+//   data: {"id":"...","choices":[{"delta":{"content":"Hola"}}]}
+//
+//   data: {"id":"...","choices":[{"delta":{"content":" mundo"}}]}
+//
+//   data: [DONE]
+//
+// Cada evento es una línea "data: <json>" seguida de una línea vacía.
+// El evento especial "[DONE]" indica el final del flujo.
+// ============================================================================
+
+// sseDataPrefix es el prefijo de las líneas con datos en SSE
+var sseDataPrefix = []byte("data:")
+
+// sseDone es el marcador de fin de flujo que envía Groq
+var sseDone = []byte("[DONE]")
+
+// CreateChatCompletionStream implementa la interfaz LLMRepository
+// Envía una petición POST a /chat/completions con "stream": true
+func (c *GroqClient) CreateChatCompletionStream(
+	ctx context.Context,
+	request domain.ChatRequest,
+) (domain.ChatStream, error) {
+	url := c.baseURL + ChatCompletionsEndpoint
+
+	// Forzar el modo streaming aunque el llamador lo haya olvidado
+	request.Stream = true
+
+	jsonData, err := json.Marshal(c.streamPayload(request))
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar request: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, url, jsonData)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", ContentTypeSSE)
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, newTransportError(ctx, err)
+	}
+	c.observeRateLimit(request.Model, resp.Header)
+
+	// Si Groq rechaza la petición, el body es un JSON de error (no SSE)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// defer dentro del if: solo cerramos aquí si no vamos a retornar el flujo
+		defer resp.Body.Close()
+		responseBody, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError(resp, responseBody)
+	}
+
+	// El body queda abierto: lo cerrará el llamador con stream.Close()
+	return &groqStream{
+		body:   resp.Body,
+		reader: bufio.NewReader(resp.Body),
+	}, nil
+}
+
+// streamOptions son las opciones de streaming de la API de OpenAI
+type streamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// streamPayload añade stream_options a la petición si el cliente lo pide
+// Groq no lo necesita (envía el uso en x_groq), así que por defecto no se envía
+func (c *GroqClient) streamPayload(request domain.ChatRequest) interface{} {
+	if !c.streamUsage {
+		return request
+	}
+	// Los campos de un struct embebido se serializan al mismo nivel
+	return struct {
+		domain.ChatRequest
+		StreamOptions streamOptions `json:"stream_options"`
+	}{request, streamOptions{IncludeUsage: true}}
+}
+
+// ============================================================================
+// IMPLEMENTACIÓN DE domain.ChatStream
+// ============================================================================
+
+// groqStream lee los eventos SSE del body de la respuesta
+// Es privado: fuera del paquete solo se conoce como domain.ChatStream
+type groqStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+
+	// long acumula las líneas que no caben en el buffer del reader
+	// (se reutiliza: ver readLine)
+	long []byte
+}
+
+// streamEvent es un evento de datos tal como lo envía Groq
+// Embeber domain.ChatStreamChunk "hereda" sus campos JSON
+type streamEvent struct {
+	domain.ChatStreamChunk
+
+	// Error llega si Groq falla a mitad del flujo
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// Recv lee eventos hasta encontrar un fragmento con datos
+// Retorna io.EOF cuando llega "[DONE]" o se cierra la conexión
+func (s *groqStream) Recv() (*domain.ChatStreamChunk, error) {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("error al leer el flujo: %w", err)
+		}
+
+		// Ignorar líneas vacías, comentarios (": ...") y campos que no son "data:"
+		line = bytes.TrimSpace(line)
+		if !bytes.HasPrefix(line, sseDataPrefix) {
+			continue
+		}
+
+		data := bytes.TrimSpace(line[len(sseDataPrefix):])
+		if bytes.Equal(data, sseDone) {
+			return nil, io.EOF
+		}
+
+		var event streamEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("error al parsear fragmento: %w", err)
+		}
+		if event.Error != nil {
+			return nil, fmt.Errorf("error de Groq durante el streaming: %s", event.Error.Message)
+		}
+
+		return &event.ChatStreamChunk, nil
+	}
+}
+
+// readLine lee la siguiente línea sin copiarla si cabe en el buffer del
+// reader (lo normal en un fragmento); las más largas se acumulan en s.long
+// La línea solo es válida hasta la siguiente llamada: json.Unmarshal copia
+// lo que necesita
+// A diferencia de bufio.Scanner, no hay límite de longitud de línea
+func (s *groqStream) readLine() ([]byte, error) {
+	line, err := s.reader.ReadSlice('\n')
+	if !errors.Is(err, bufio.ErrBufferFull) {
+		return line, err
+	}
+
+	s.long = append(s.long[:0], line...)
+	for errors.Is(err, bufio.ErrBufferFull) {
+		line, err = s.reader.ReadSlice('\n')
+		s.long = append(s.long, line...)
+	}
+	return s.long, err
+}
+
+// Close cierra la conexión con Groq
+func (s *groqStream) Close() error {
+	return s.body.Close()
+}
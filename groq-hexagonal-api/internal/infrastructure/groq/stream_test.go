@@ -0,0 +1,70 @@
+package groq
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// benchmarkStreamChunks son los fragmentos de cada flujo del benchmark
+const benchmarkStreamChunks = 64
+
+// benchmarkStreamBody simula el body SSE de Groq: fragmentos con una palabra,
+// el último con el uso en x_groq, y el [DONE]
+func benchmarkStreamBody() []byte {
+	var body bytes.Buffer
+	for i := 0; i < benchmarkStreamChunks; i++ {
+		body.WriteString(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1760600000,"model":"llama-3.3-70b-versatile","choices":[{"index":0,"delta":{"content":" palabra"},"finish_reason":null}]}` + "\n\n")
+	}
+	body.WriteString(`data: {"id":"chatcmpl-1","object":"chat.completion.chunk","created":1760600000,"model":"llama-3.3-70b-versatile","choices":[{"index":0,"delta":{},"finish_reason":"stop"}],"x_groq":{"usage":{"prompt_tokens":12,"completion_tokens":64,"total_tokens":76}}}` + "\n\n")
+	body.WriteString("data: [DONE]\n\n")
+	return body.Bytes()
+}
+
+// BenchmarkStreamRecv mide la lectura de un flujo completo (por operación:
+// benchmarkStreamChunks+1 fragmentos)
+func BenchmarkStreamRecv(b *testing.B) {
+	payload := benchmarkStreamBody()
+	reader := bytes.NewReader(payload)
+
+	b.SetBytes(int64(len(payload)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reader.Reset(payload)
+		stream := &groqStream{body: io.NopCloser(reader), reader: bufio.NewReader(reader)}
+		for {
+			_, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkStreamRecvLongLine mide un fragmento que no cabe en el buffer del
+// reader (se acumula en groqStream.long)
+func BenchmarkStreamRecvLongLine(b *testing.B) {
+	content := strings.Repeat("x", 16*1024)
+	payload := []byte(`data: {"id":"chatcmpl-1","model":"llama-3.3-70b-versatile","choices":[{"index":0,"delta":{"content":"` + content + `"}}]}` + "\n\ndata: [DONE]\n\n")
+	reader := bytes.NewReader(payload)
+
+	b.SetBytes(int64(len(payload)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		reader.Reset(payload)
+		stream := &groqStream{body: io.NopCloser(reader), reader: bufio.NewReader(reader)}
+		if _, err := stream.Recv(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package groq - Transcripción de audio (Whisper)
+package groq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TranscriptionsEndpoint es el endpoint de transcripción de la API de Groq
+const TranscriptionsEndpoint = "/audio/transcriptions"
+
+// NewTranscriptionClient crea un cliente para los modelos Whisper de Groq
+// timeout debe cubrir la subida del audio además de la transcripción
+func NewTranscriptionClient(apiKey, baseURL string, timeout time.Duration, opts ...ClientOption) domain.TranscriptionRepository {
+	return newClient(apiKey, baseURL, timeout, opts...)
+}
+
+// CreateTranscription implementa domain.TranscriptionRepository
+// Envía el audio como multipart/form-data y pide verbose_json para recibir
+// los segmentos con sus marcas de tiempo
+func (c *GroqClient) CreateTranscription(
+	ctx context.Context,
+	request domain.TranscriptionRequest,
+) (*domain.Transcription, error) {
+	body, contentType, err := transcriptionBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, c.baseURL+TranscriptionsEndpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	response, header, err := c.send(ctx, req)
+	c.observeRateLimit(request.Model, header)
+	if err != nil {
+		return nil, fmt.Errorf("error en la petición HTTP: %w", err)
+	}
+
+	var transcription domain.Transcription
+	if err := json.Unmarshal(response, &transcription); err != nil {
+		return nil, fmt.Errorf("error al parsear la transcripción: %w", err)
+	}
+	return &transcription, nil
+}
+
+// transcriptionBody construye el cuerpo multipart de la petición
+// Retorna el cuerpo y su Content-Type (incluye el boundary)
+func transcriptionBody(request domain.TranscriptionRequest) ([]byte, string, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	file, err := writer.CreateFormFile("file", request.Filename)
+	if err != nil {
+		return nil, "", fmt.Errorf("error al preparar el audio: %w", err)
+	}
+	if _, err := io.Copy(file, request.Audio); err != nil {
+		return nil, "", fmt.Errorf("error al leer el audio: %w", err)
+	}
+
+	fields := map[string]string{
+		"model":                     request.Model,
+		"response_format":           "verbose_json",
+		"timestamp_granularities[]": "segment",
+		"language":                  request.Language,
+		"prompt":                    request.Prompt,
+	}
+	if request.Temperature != nil {
+		fields["temperature"] = strconv.FormatFloat(*request.Temperature, 'f', -1, 64)
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", fmt.Errorf("error al preparar la petición: %w", err)
+		}
+	}
+
+	// Close escribe el boundary final
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("error al preparar la petición: %w", err)
+	}
+	return body.Bytes(), writer.FormDataContentType(), nil
+}
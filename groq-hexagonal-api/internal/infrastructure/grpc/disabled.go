@@ -0,0 +1,21 @@
+//go:build !grpc
+
+// Package grpc implementa el adaptador primario gRPC
+//
+// Este fichero se compila cuando el binario NO incluye gRPC (la opción por
+// defecto, para no arrastrar sus dependencias). Ver server.go y `make build-grpc`.
+package grpc
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ErrNotCompiled indica que el binario se compiló sin soporte gRPC
+var ErrNotCompiled = errors.New("binario compilado sin soporte gRPC (usa make build-grpc)")
+
+// Start no hace nada: sin la etiqueta de compilación "grpc" no hay servidor
+func Start(port string, chatService domain.ChatService) (stop func(ctx context.Context), err error) {
+	return nil, ErrNotCompiled
+}
@@ -0,0 +1,258 @@
+//go:build grpc
+
+// Package grpc implementa el adaptador primario gRPC
+// Es la alternativa al adaptador HTTP: traduce las llamadas gRPC a
+// domain.ChatService, igual que los handlers traducen las peticiones HTTP
+//
+// Se compila solo con la etiqueta "grpc" (make build-grpc), que requiere el
+// código generado en chatv1 (make proto) y las dependencias de gRPC
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/grpc/chatv1"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// tenantMetadataKey es el metadato con el tenant (los metadatos van en minúsculas)
+const tenantMetadataKey = "x-tenant-id"
+
+// ============================================================================
+// ARRANQUE DEL SERVIDOR
+// ============================================================================
+
+// Start arranca el servidor gRPC en su propia goroutine
+// Retorna la función que lo detiene de forma ordenada
+func Start(port string, chatService domain.ChatService) (stop func(ctx context.Context), err error) {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return nil, fmt.Errorf("error al escuchar en el puerto %s: %w", port, err)
+	}
+
+	server := grpc.NewServer()
+	chatv1.RegisterChatServiceServer(server, NewChatServer(chatService))
+
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Printf("❌ Error en el servidor gRPC: %v", err)
+		}
+	}()
+
+	return func(ctx context.Context) {
+		// GracefulStop espera a que terminen las llamadas en curso (incluidos
+		// los streams); si el plazo vence antes, se cortan con Stop
+		done := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-ctx.Done():
+			server.Stop()
+		}
+	}, nil
+}
+
+// ============================================================================
+// IMPLEMENTACIÓN DEL SERVICIO
+// ============================================================================
+
+// ChatServer implementa chatv1.ChatServiceServer sobre domain.ChatService
+type ChatServer struct {
+	// UnimplementedChatServiceServer responde Unimplemented a los métodos
+	// nuevos del .proto que todavía no estén implementados aquí
+	chatv1.UnimplementedChatServiceServer
+
+	chatService domain.ChatService
+}
+
+// NewChatServer crea el servidor con el servicio inyectado
+func NewChatServer(chatService domain.ChatService) *ChatServer {
+	if chatService == nil {
+		panic("chatService no puede ser nil")
+	}
+
+	return &ChatServer{chatService: chatService}
+}
+
+// SendMessage implementa chatv1.ChatServiceServer
+func (s *ChatServer) SendMessage(ctx context.Context, req *chatv1.SendMessageRequest) (*chatv1.SendMessageResponse, error) {
+	response, err := s.chatService.SendMessage(withIncomingMetadata(ctx), req.GetMessage(), req.GetModel(), toMessageOptions(req))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &chatv1.SendMessageResponse{
+		Message:           response.GetResponseContent(),
+		Model:             response.Model,
+		Usage:             toUsage(response.Usage),
+		FinishReason:      response.GetFinishReason(),
+		TruncatedByPolicy: response.Meta.TruncatedByPolicy,
+		DetectedLanguage:  response.Meta.DetectedLanguage,
+		RemappedFrom:      response.Meta.RemappedFrom,
+		Warnings:          toWarnings(response.Meta.Warnings),
+	}, nil
+}
+
+// StreamMessage implementa chatv1.ChatServiceServer (server streaming)
+func (s *ChatServer) StreamMessage(req *chatv1.SendMessageRequest, stream chatv1.ChatService_StreamMessageServer) error {
+	ctx := withIncomingMetadata(stream.Context())
+
+	chatStream, err := s.chatService.StreamMessage(ctx, req.GetMessage(), req.GetModel(), toMessageOptions(req))
+	if err != nil {
+		return toStatusError(err)
+	}
+	defer chatStream.Close()
+
+	for {
+		chunk, err := chatStream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return toStatusError(err)
+		}
+
+		if err := stream.Send(&chatv1.StreamMessageResponse{
+			Content:      chunk.GetDeltaContent(),
+			Model:        chunk.Model,
+			FinishReason: chunk.GetFinishReason(),
+			Usage:        toUsage(chunk.GetUsage()),
+		}); err != nil {
+			// El cliente cerró el stream
+			return err
+		}
+	}
+}
+
+// GetAvailableModels implementa chatv1.ChatServiceServer
+func (s *ChatServer) GetAvailableModels(ctx context.Context, _ *chatv1.GetAvailableModelsRequest) (*chatv1.GetAvailableModelsResponse, error) {
+	response, err := s.chatService.GetAvailableModels(withIncomingMetadata(ctx))
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	models := make([]*chatv1.Model, len(response.Data))
+	for i, model := range response.Data {
+		models[i] = &chatv1.Model{Id: model.ID, OwnedBy: model.OwnedBy}
+	}
+	return &chatv1.GetAvailableModelsResponse{Models: models}, nil
+}
+
+// ============================================================================
+// MAPEOS gRPC ↔ DOMINIO
+// ============================================================================
+
+// withIncomingMetadata copia el tenant y los metadatos al contexto
+// (como tenantMiddleware y requestHeadersMiddleware en el adaptador HTTP)
+func withIncomingMetadata(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	// Los metadatos llegan en minúsculas; los hooks los leen como cabeceras
+	headers := make(domain.RequestHeaders, len(md))
+	for key, values := range md {
+		headers[textproto.CanonicalMIMEHeaderKey(key)] = values
+	}
+	ctx = domain.WithRequestHeaders(ctx, headers)
+
+	// Mismas directivas de caché que la cabecera Cache-Control de HTTP
+	if values := md.Get("cache-control"); len(values) > 0 {
+		ctx = domain.WithCacheControl(ctx, domain.ParseCacheControl(values[0]))
+	}
+
+	if values := md.Get(tenantMetadataKey); len(values) > 0 && values[0] != "" {
+		return domain.WithTenant(ctx, values[0])
+	}
+	return ctx
+}
+
+// toMessageOptions convierte la petición gRPC a opciones del dominio
+func toMessageOptions(req *chatv1.SendMessageRequest) domain.MessageOptions {
+	history := make([]domain.ChatMessage, len(req.GetHistory()))
+	for i, message := range req.GetHistory() {
+		history[i] = domain.ChatMessage{Role: message.GetRole(), Content: message.GetContent()}
+	}
+
+	return domain.MessageOptions{
+		// Temperature es "optional" en el .proto: nil si no se envió
+		Temperature: req.Temperature,
+		MaxTokens:   int(req.GetMaxTokens()),
+		Stop:        req.GetStop(),
+		Sampling: domain.SamplingOptions{
+			TopP:             req.TopP,
+			FrequencyPenalty: req.FrequencyPenalty,
+			PresencePenalty:  req.PresencePenalty,
+			Seed:             req.Seed,
+		},
+		SystemPrompt: req.GetSystemPrompt(),
+		Persona:      req.GetPersona(),
+		Provider:     req.GetProvider(),
+		History:      history,
+	}
+}
+
+// toUsage convierte el uso de tokens (nil si no hay)
+func toUsage(usage *domain.Usage) *chatv1.Usage {
+	if usage == nil {
+		return nil
+	}
+	return &chatv1.Usage{
+		PromptTokens:     int32(usage.PromptTokens),
+		CompletionTokens: int32(usage.CompletionTokens),
+		TotalTokens:      int32(usage.TotalTokens),
+	}
+}
+
+// toWarnings convierte los avisos del dominio
+func toWarnings(warnings []domain.Warning) []*chatv1.Warning {
+	result := make([]*chatv1.Warning, len(warnings))
+	for i, warning := range warnings {
+		result[i] = &chatv1.Warning{Code: warning.Code, Message: warning.Message}
+	}
+	return result
+}
+
+// statusMappings traduce los errores del dominio a códigos gRPC
+// Es el equivalente de serviceErrorMappings en el adaptador HTTP
+var statusMappings = []struct {
+	target error
+	code   codes.Code
+}{
+	{domain.ErrEmptyMessage, codes.InvalidArgument},
+	{domain.ErrEmptyModel, codes.InvalidArgument},
+	{domain.ErrInvalidRequest, codes.InvalidArgument},
+	{domain.ErrContextTooLong, codes.InvalidArgument},
+	{domain.ErrPersonaNotFound, codes.NotFound},
+	{domain.ErrUnknownProvider, codes.InvalidArgument},
+	{domain.ErrParameterOutOfRange, codes.InvalidArgument},
+	{domain.ErrRequestRejected, codes.PermissionDenied},
+	{domain.ErrModelNotFound, codes.NotFound},
+	{domain.ErrModelDecommissioned, codes.NotFound},
+	{domain.ErrRateLimited, codes.ResourceExhausted},
+	{domain.ErrUpstreamUnavailable, codes.Unavailable},
+	{domain.ErrUpstreamTimeout, codes.DeadlineExceeded},
+}
+
+// toStatusError convierte un error del servicio en un error gRPC
+func toStatusError(err error) error {
+	for _, mapping := range statusMappings {
+		if errors.Is(err, mapping.target) {
+			return status.Error(mapping.code, err.Error())
+		}
+	}
+
+	log.Printf("Error en servicio (gRPC): %v", err)
+	return status.Error(codes.Internal, "error al procesar el mensaje")
+}
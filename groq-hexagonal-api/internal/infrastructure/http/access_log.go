@@ -0,0 +1,309 @@
+// Package http - Access log estructurado
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/logging"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// ACCESS LOG
+// ============================================================================
+//
+// Cada petición produce UNA línea al terminar. Hay dos formatos:
+//
+//   json (por defecto):
+//     {"time":"...","method":"POST","path":"/api/v1/chat","status":200,"duration_ms":812,"model":"...","total_tokens":95,...}
+//
+//   combined (Apache, para pipelines de logs antiguos):
+//     203.0.113.7 - - [15/Oct/2026:10:00:00 +0000] "POST /api/v1/chat HTTP/1.1" 200 512 "-" "curl/8.0"
+//
+// En json, los campos opcionales se eligen con AccessLogOptions.Fields; time,
+// method, path y remote_addr aparecen siempre. El formato combined es fijo.
+//
+// El modelo y los tokens los conocen los handlers, no el middleware: los
+// apuntan con annotateAccessLog() en un accessLogEntry guardado en el contexto.
+// Los tokens también los lee el registro del consumo (ver usage_handler.go).
+//
+// El body y las cabeceras de la petición solo llegan al log a través de un
+// logging.Redactor: por defecto el contenido no se registra y las cabeceras
+// con credenciales aparecen como "[REDACTED]". content_hash y headers no están
+// en el formato combined.
+// ============================================================================
+
+// Formatos del access log
+const (
+	AccessLogFormatJSON     = "json"
+	AccessLogFormatCombined = "combined"
+)
+
+// Campos opcionales del access log (formato json)
+const (
+	AccessLogFieldStatus     = "status"
+	AccessLogFieldBytes      = "bytes"
+	AccessLogFieldDuration   = "duration"
+	AccessLogFieldUserAgent  = "user_agent"
+	AccessLogFieldAPIKeyHash = "api_key_hash"
+	AccessLogFieldModel      = "model"
+	AccessLogFieldTokens     = "tokens"
+
+	// Hash del body (solo con el contenido en modo hash, ver logging.Redactor)
+	AccessLogFieldContentHash = "content_hash"
+
+	// Cabeceras de la petición, redactadas
+	AccessLogFieldHeaders = "headers"
+)
+
+// allAccessLogFields son todos los campos opcionales
+var allAccessLogFields = []string{
+	AccessLogFieldStatus,
+	AccessLogFieldBytes,
+	AccessLogFieldDuration,
+	AccessLogFieldUserAgent,
+	AccessLogFieldAPIKeyHash,
+	AccessLogFieldModel,
+	AccessLogFieldTokens,
+	AccessLogFieldContentHash,
+	AccessLogFieldHeaders,
+}
+
+// defaultAccessLogFields son los campos que se registran si no se indica
+// ninguno: todos menos headers, que alarga mucho cada línea
+var defaultAccessLogFields = allAccessLogFields[:len(allAccessLogFields)-1]
+
+// AccessLogOptions configura el access log
+type AccessLogOptions struct {
+	// Format es "json" (por defecto) o "combined"
+	Format string
+
+	// Fields son los campos opcionales del formato json (vacío = los de por defecto)
+	Fields []string
+
+	// Redactor decide qué se registra del body y de las cabeceras
+	// (nil = sin contenido y con las cabeceras por defecto redactadas)
+	Redactor *logging.Redactor
+}
+
+// accessLogger escribe el access log
+type accessLogger struct {
+	format   string
+	fields   map[string]bool
+	redactor *logging.Redactor
+
+	// out escribe sin prefijo: la línea ya lleva su propia fecha
+	out *log.Logger
+}
+
+// newAccessLogger crea el access log; los campos desconocidos se ignoran con un aviso
+func newAccessLogger(options AccessLogOptions) *accessLogger {
+	format := options.Format
+	if format == "" {
+		format = AccessLogFormatJSON
+	}
+
+	names := options.Fields
+	if len(names) == 0 {
+		names = defaultAccessLogFields
+	}
+	fields := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !isAccessLogField(name) {
+			log.Printf("⚠️  Campo de access log desconocido, se ignora: %q", name)
+			continue
+		}
+		fields[name] = true
+	}
+
+	redactor := options.Redactor
+	if redactor == nil {
+		redactor = logging.NewRedactor(logging.ContentNone, nil)
+	}
+
+	return &accessLogger{
+		format:   format,
+		fields:   fields,
+		redactor: redactor,
+		out:      log.New(os.Stdout, "", 0),
+	}
+}
+
+// isAccessLogField indica si name es un campo opcional válido
+func isAccessLogField(name string) bool {
+	for _, field := range allAccessLogFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// middleware registra una línea por petición al terminar
+func (l *accessLogger) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		entry := &accessLogEntry{contentHash: func() string { return "" }}
+		if l.format == AccessLogFormatJSON && l.fields[AccessLogFieldContentHash] {
+			r.Body, entry.contentHash = l.redactor.HashBody(r.Body)
+		}
+
+		recorder := wrapResponseWriter(w)
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), accessLogKey{}, entry)))
+
+		if l.format == AccessLogFormatCombined {
+			l.out.Println(formatCombined(r, recorder, start))
+			return
+		}
+		l.out.Println(l.formatJSON(r, recorder, entry, start))
+	})
+}
+
+// formatJSON construye la línea en formato json
+func (l *accessLogger) formatJSON(r *http.Request, recorder *responseWriter, entry *accessLogEntry, start time.Time) string {
+	line := map[string]interface{}{
+		"time":        start.UTC().Format(time.RFC3339Nano),
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"remote_addr": r.RemoteAddr,
+	}
+
+	if l.fields[AccessLogFieldStatus] {
+		line["status"] = recorder.Status()
+	}
+	if l.fields[AccessLogFieldBytes] {
+		line["bytes"] = recorder.BytesWritten()
+	}
+	if l.fields[AccessLogFieldDuration] {
+		line["duration_ms"] = time.Since(start).Milliseconds()
+	}
+	if l.fields[AccessLogFieldUserAgent] {
+		line["user_agent"] = r.UserAgent()
+	}
+	if l.fields[AccessLogFieldAPIKeyHash] {
+		if hash := apiKeyHash(r); hash != "" {
+			line["api_key_hash"] = hash
+		}
+	}
+	if l.fields[AccessLogFieldModel] && entry.model != "" {
+		line["model"] = entry.model
+	}
+	if l.fields[AccessLogFieldTokens] && entry.usage != nil {
+		line["prompt_tokens"] = entry.usage.PromptTokens
+		line["completion_tokens"] = entry.usage.CompletionTokens
+		line["total_tokens"] = entry.usage.TotalTokens
+	}
+	if l.fields[AccessLogFieldContentHash] {
+		if hash := entry.contentHash(); hash != "" {
+			line["content_hash"] = hash
+		}
+	}
+	if l.fields[AccessLogFieldHeaders] {
+		line["headers"] = l.redactor.Headers(r.Header)
+	}
+
+	// json.Marshal ordena las claves del map: las líneas son comparables entre sí
+	data, err := json.Marshal(line)
+	if err != nil {
+		return fmt.Sprintf(`{"error":%q}`, err.Error())
+	}
+	return string(data)
+}
+
+// formatCombined construye la línea en el Combined Log Format de Apache
+func formatCombined(r *http.Request, recorder *responseWriter, start time.Time) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	// En CLF, "-" significa "sin valor"
+	size := "-"
+	if written := recorder.BytesWritten(); written > 0 {
+		size = fmt.Sprint(written)
+	}
+
+	return fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %s "%s" "%s"`,
+		host,
+		start.Format("02/Jan/2006:15:04:05 -0700"),
+		r.Method,
+		r.URL.RequestURI(),
+		r.Proto,
+		recorder.Status(),
+		size,
+		orDash(r.Referer()),
+		orDash(r.UserAgent()),
+	)
+}
+
+// orDash retorna "-" si value está vacío
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// apiKeyHash identifica la clave del cliente sin guardarla en el log
+// Son los primeros 12 caracteres hex del SHA-256 del token Bearer
+func apiKeyHash(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// ============================================================================
+// DATOS APORTADOS POR LOS HANDLERS
+// ============================================================================
+
+// accessLogKey es la clave del accessLogEntry en el contexto
+type accessLogKey struct{}
+
+// accessLogEntry guarda lo que los handlers saben de la petición
+// Solo lo escribe el handler de la petición (una goroutine), antes de que
+// el middleware lo lea
+type accessLogEntry struct {
+	model string
+	usage *domain.Usage
+
+	// contentHash lo pone el middleware, no los handlers
+	contentHash func() string
+}
+
+// annotateAccessLog apunta el modelo y los tokens usados por la petición
+// No hace nada si la petición no pasa por el access log
+func annotateAccessLog(ctx context.Context, model string, usage *domain.Usage) {
+	entry, ok := ctx.Value(accessLogKey{}).(*accessLogEntry)
+	if !ok {
+		return
+	}
+	if model != "" {
+		entry.model = model
+	}
+	if usage != nil {
+		entry.usage = usage
+	}
+}
+
+// annotatedUsage retorna el modelo y los tokens apuntados por el handler
+// (usage es nil si no apuntó ninguno)
+func annotatedUsage(ctx context.Context) (string, *domain.Usage) {
+	entry, ok := ctx.Value(accessLogKey{}).(*accessLogEntry)
+	if !ok {
+		return "", nil
+	}
+	return entry.model, entry.usage
+}
@@ -0,0 +1,201 @@
+// Package http - Endpoints de administración (/admin)
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+	"sync/atomic"
+)
+
+// ============================================================================
+// ADMINISTRACIÓN
+// ============================================================================
+//
+// Las rutas bajo /admin dan visibilidad a los operadores sin reiniciar el
+// servidor. Todas requieren la cabecera X-Admin-Key con ADMIN_API_KEY (sin
+// ella no se registran):
+//
+//   GET  /admin/config         configuración con los secretos enmascarados
+//   POST /admin/config/reload  recarga los ajustes en caliente (como SIGHUP)
+//   GET  /admin/cache          estadísticas de la caché de respuestas
+//   POST /admin/cache/flush    vacía la caché de respuestas
+//   GET  /admin/circuits       estado del circuit breaker de cada modelo
+//   GET  /admin/in-flight      peticiones a /api/v1 en curso
+//   GET  /admin/liveness       tareas colgadas (con WATCHDOG_FACTOR > 0)
+//   GET  /admin/groq/keys      estado de cada API key de Groq (con varias)
+//   GET  /admin/models/health  salud de los modelos (ver model_health_handler.go)
+//   GET  /admin/runtime        goroutines, heap y GC (ver diagnostics.go)
+//   GET  /admin/debug/pprof/   perfiles de net/http/pprof (ver diagnostics.go)
+//
+// Quedan fuera de /api/v1: no consumen rate limit ni cuentan como en curso.
+// ============================================================================
+
+// AdminOptions son las dependencias del AdminHandler
+type AdminOptions struct {
+	// Config es la configuración ya enmascarada (ver config.Masked)
+	Config map[string]interface{}
+
+	// Cache es la caché de respuestas (desactivada si RESPONSE_CACHE_TTL es 0)
+	Cache domain.ResponseCacheService
+
+	// Health aporta el estado de los circuit breakers
+	Health domain.ModelHealthService
+
+	// Settings recarga los ajustes en caliente (ver domain/settings.go)
+	Settings domain.SettingsService
+
+	// Liveness aporta las tareas colgadas (opcional)
+	Liveness domain.LivenessMonitor
+
+	// GroqKeys aporta el estado de cada API key de Groq (opcional: solo
+	// con varias keys)
+	GroqKeys domain.BackendHealthService
+}
+
+// AdminHandler maneja los endpoints de administración
+type AdminHandler struct {
+	config   map[string]interface{}
+	cache    domain.ResponseCacheService
+	health   domain.ModelHealthService
+	settings domain.SettingsService
+	liveness domain.LivenessMonitor
+	groqKeys domain.BackendHealthService
+
+	// inFlight cuenta las peticiones a /api/v1 en curso (ver trackInFlight)
+	inFlight atomic.Int64
+}
+
+// NewAdminHandler crea un nuevo handler con las dependencias inyectadas
+func NewAdminHandler(options AdminOptions) *AdminHandler {
+	if options.Cache == nil {
+		panic("responseCacheService no puede ser nil")
+	}
+	if options.Health == nil {
+		panic("modelHealthService no puede ser nil")
+	}
+	if options.Settings == nil {
+		panic("settingsService no puede ser nil")
+	}
+
+	return &AdminHandler{
+		config:   options.Config,
+		cache:    options.Cache,
+		health:   options.Health,
+		settings: options.Settings,
+		liveness: options.Liveness,
+		groqKeys: options.GroqKeys,
+	}
+}
+
+// HandleConfig maneja GET /admin/config
+// Los ajustes recargables se muestran con su valor vigente, no el del arranque
+func (h *AdminHandler) HandleConfig(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleConfig", r.Method, r.URL.Path)
+
+	config := make(map[string]interface{}, len(h.config))
+	for key, value := range h.config {
+		config[key] = value
+	}
+	settings := h.settings.Settings()
+	config["DEFAULT_MODEL"] = settings.DefaultModel
+	config["HTTP_TIMEOUT"] = settings.HTTPTimeout.String()
+	config["RATE_LIMIT_REQUESTS"] = settings.RateLimitRequests
+	config["RATE_LIMIT_WINDOW"] = settings.RateLimitWindow.String()
+
+	writeJSONResponse(w, &AdminConfigResponse{Success: true, Config: config}, http.StatusOK)
+}
+
+// HandleConfigReload maneja POST /admin/config/reload
+// Retorna los ajustes que quedan vigentes
+func (h *AdminHandler) HandleConfigReload(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleConfigReload", r.Method, r.URL.Path)
+
+	settings, err := h.settings.Reload(r.Context())
+	if err != nil {
+		// Solo lo ven los administradores: el detalle dice qué corregir en .env
+		log.Printf("❌ %v", err)
+		writeErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONResponse(w, NewSettingsResponse(settings), http.StatusOK)
+}
+
+// HandleCacheStats maneja GET /admin/cache
+func (h *AdminHandler) HandleCacheStats(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleCacheStats", r.Method, r.URL.Path)
+
+	stats, err := h.cache.Stats(r.Context())
+	if err != nil {
+		writeServiceError(w, err, "error al leer la caché")
+		return
+	}
+	writeJSONResponse(w, NewCacheStatsResponse(stats), http.StatusOK)
+}
+
+// HandleCacheFlush maneja POST /admin/cache/flush
+func (h *AdminHandler) HandleCacheFlush(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleCacheFlush", r.Method, r.URL.Path)
+
+	flushed, err := h.cache.Flush(r.Context())
+	if err != nil {
+		writeServiceError(w, err, "error al vaciar la caché")
+		return
+	}
+	writeJSONResponse(w, &CacheFlushResponse{Success: true, Flushed: flushed}, http.StatusOK)
+}
+
+// HandleCircuits maneja GET /admin/circuits
+// Es un resumen del panel de salud con solo el estado de los circuitos
+func (h *AdminHandler) HandleCircuits(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleCircuits", r.Method, r.URL.Path)
+
+	models := h.health.GetModelsHealth(r.Context())
+	writeJSONResponse(w, NewCircuitsResponse(models), http.StatusOK)
+}
+
+// HandleInFlight maneja GET /admin/in-flight
+func (h *AdminHandler) HandleInFlight(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleInFlight", r.Method, r.URL.Path)
+
+	writeJSONResponse(w, &InFlightResponse{Success: true, InFlight: h.inFlight.Load()}, http.StatusOK)
+}
+
+// HandleLiveness maneja GET /admin/liveness
+func (h *AdminHandler) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleLiveness", r.Method, r.URL.Path)
+
+	stats := h.liveness.Stats()
+	writeJSONResponse(w, &LivenessResponse{
+		Success:    true,
+		Active:     stats.Active,
+		Stuck:      stats.Stuck,
+		StuckTotal: stats.StuckTotal,
+	}, http.StatusOK)
+}
+
+// HandleGroqKeys maneja GET /admin/groq/keys
+// Las keys se identifican por su posición (groq-1 es GROQ_API_KEY), nunca
+// por su valor
+func (h *AdminHandler) HandleGroqKeys(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleGroqKeys", r.Method, r.URL.Path)
+
+	writeJSONResponse(w, NewBackendsHealthResponse(h.groqKeys.GetBackendsHealth()), http.StatusOK)
+}
+
+// trackInFlight es el middleware que cuenta las peticiones en curso
+// Un streaming cuenta hasta que se envía el último fragmento
+func (h *AdminHandler) trackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.inFlight.Add(1)
+		defer h.inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdminKeyMiddleware aplica requireAdminKey a todo un subrouter
+func requireAdminKeyMiddleware(adminKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return requireAdminKey(adminKey, next.ServeHTTP)
+	}
+}
@@ -0,0 +1,112 @@
+// Package http - Autenticación de /api/v1 con las API keys de los clientes
+package http
+
+import (
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// AUTENTICACIÓN CON API KEYS
+// ============================================================================
+//
+// Con un domain.APIKeyService en RouterOptions.APIKeys, cada petición a
+// /api/v1 debe traer "Authorization: Bearer <clave>" con una clave activa
+// que tenga el scope del endpoint (401 y 403 si no). La clave queda en el
+// contexto (domain.APIKeyFromContext) y su ID identifica al cliente en el
+// rate limit y el consumo: rotar la clave no reinicia sus contadores.
+//
+// El tenant de la petición pasa a ser el de la clave (domain.APIKey.Tenant):
+// sustituye al que tenantMiddleware leyó de X-Tenant-ID, y una cabecera con
+// otro tenant se rechaza (403 tenant_mismatch) en lugar de ignorarse, para
+// que el cliente sepa que no se está aplicando.
+//
+// Cuando al secreto usado le queda menos del plazo de aviso (por caducidad
+// de la clave o por ser el anterior a una rotación), la respuesta lleva
+// X-Key-Expires-In con los segundos que le quedan.
+// ============================================================================
+
+// KeyExpiresInHeader son los segundos que le quedan al secreto de la petición
+const KeyExpiresInHeader = "X-Key-Expires-In"
+
+// apiKeyRouteScopes asocia los prefijos de ruta con su scope
+// Se recorre en orden (el primero que coincide gana); scope vacío = basta
+// con una clave válida. Las rutas que no aparecen requieren una clave sin
+// scopes (acceso completo)
+var apiKeyRouteScopes = []struct {
+	prefix string
+	scope  string
+}{
+	{"/api/v1/chat/async", domain.ScopeJobs},
+	{"/api/v1/jobs", domain.ScopeJobs},
+	{"/api/v1/chat", domain.ScopeChat},
+	{"/api/v1/models", domain.ScopeChat},
+	{"/api/v1/diff", domain.ScopeChat},
+	{"/api/v1/conversations", domain.ScopeConversations},
+	{"/api/v1/prompts", domain.ScopeTools},
+	{"/api/v1/classify", domain.ScopeTools},
+	{"/api/v1/redact", domain.ScopeTools},
+	{"/api/v1/nl2sql", domain.ScopeTools},
+	{"/api/v1/code", domain.ScopeTools},
+	{"/api/v1/proxy", domain.ScopeProxy},
+	{"/api/v1/audio", domain.ScopeAudio},
+	// Cada cliente puede consultar su propio consumo, pedir tokens con
+	// sus scopes (ver token_handler.go) y valorar las respuestas
+	{"/api/v1/usage", ""},
+	{"/api/v1/token", ""},
+	{"/api/v1/feedback", ""},
+}
+
+// apiKeyAuthMiddleware exige una API key válida (nil = sin autenticación)
+// expiryWarning es desde cuánto antes de caducar se avisa (0 = nunca)
+func apiKeyAuthMiddleware(apiKeys domain.APIKeyService, expiryWarning time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if apiKeys == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			key, err := apiKeys.Authenticate(r.Context(), strings.TrimSpace(secret))
+			if err != nil {
+				if errors.Is(err, domain.ErrInvalidAPIKey) {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+				}
+				writeServiceError(w, err, "error al comprobar la API key")
+				return
+			}
+
+			if !keyAllowsPath(key, r.URL.Path) {
+				writeServiceError(w, domain.ErrInsufficientScope, "")
+				return
+			}
+			tenantID := key.TenantID()
+			if requested := r.Header.Get(TenantHeader); requested != "" && requested != tenantID {
+				writeServiceError(w, fmt.Errorf("%w: la clave es del tenant %q", domain.ErrTenantMismatch, tenantID), "")
+				return
+			}
+			if expiresAt := key.SecretExpiresAt(); expiresAt != nil && expiryWarning > 0 {
+				if remaining := time.Until(*expiresAt); remaining <= expiryWarning {
+					w.Header().Set(KeyExpiresInHeader, strconv.FormatInt(int64(remaining.Seconds()), 10))
+				}
+			}
+			ctx := domain.WithTenant(domain.WithAPIKey(r.Context(), key), tenantID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// keyAllowsPath indica si la clave tiene el scope de la ruta
+func keyAllowsPath(key *domain.APIKey, path string) bool {
+	for _, route := range apiKeyRouteScopes {
+		if path == route.prefix || strings.HasPrefix(path, route.prefix+"/") {
+			return route.scope == "" || key.Allows(route.scope)
+		}
+	}
+	return len(key.Scopes) == 0
+}
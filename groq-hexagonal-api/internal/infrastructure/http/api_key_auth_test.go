@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// stubAPIKeys autentica cualquier secreto como key
+// Los demás métodos de domain.APIKeyService no se usan en estos tests
+type stubAPIKeys struct {
+	domain.APIKeyService
+	key domain.APIKey
+}
+
+func (s *stubAPIKeys) Authenticate(context.Context, string) (*domain.APIKey, error) {
+	key := s.key
+	return &key, nil
+}
+
+func TestAPIKeyAuthBindsTenant(t *testing.T) {
+	tests := []struct {
+		name       string
+		keyTenant  string
+		header     string
+		wantStatus int
+		wantTenant string
+	}{
+		{"tenant de la clave sin cabecera", "acme", "", http.StatusOK, "acme"},
+		{"cabecera con el tenant de la clave", "acme", "acme", http.StatusOK, "acme"},
+		{"cabecera con otro tenant", "acme", "globex", http.StatusForbidden, ""},
+		{"clave sin tenant", "", "", http.StatusOK, domain.DefaultTenant},
+		{"clave sin tenant y cabecera default", "", domain.DefaultTenant, http.StatusOK, domain.DefaultTenant},
+		{"clave sin tenant y otro tenant", "", "acme", http.StatusForbidden, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiKeys := &stubAPIKeys{key: domain.APIKey{ID: "key-1", Tenant: tt.keyTenant}}
+			var gotTenant string
+			handler := tenantMiddleware(apiKeyAuthMiddleware(apiKeys, 0)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					gotTenant = domain.TenantFromContext(r.Context())
+				}),
+			))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/usage", nil)
+			req.Header.Set("Authorization", "Bearer gk_secreto")
+			if tt.header != "" {
+				req.Header.Set(TenantHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d; se esperaba %d (%s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				var response ErrorResponse
+				if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+					t.Fatal(err)
+				}
+				if response.Type != "tenant_mismatch" {
+					t.Errorf("type = %q; se esperaba tenant_mismatch", response.Type)
+				}
+				return
+			}
+			if gotTenant != tt.wantTenant {
+				t.Errorf("tenant = %q; se esperaba %q", gotTenant, tt.wantTenant)
+			}
+		})
+	}
+}
+
+func TestStatementRequiresKeyTenant(t *testing.T) {
+	// El extracto de otro tenant se rechaza antes de leer nada
+	handler := &UsageHandler{}
+	ctx := domain.WithAPIKey(context.Background(), &domain.APIKey{ID: "key-1", Tenant: "acme"})
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/globex/statements/2026-09", nil).WithContext(ctx)
+	req = mux.SetURLVars(req, map[string]string{"id": "globex", "month": "2026-09"})
+	rec := httptest.NewRecorder()
+	handler.HandleStatement(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("status = %d; se esperaba %d (%s)", rec.Code, http.StatusForbidden, rec.Body)
+	}
+}
@@ -0,0 +1,103 @@
+// Package http - Handlers HTTP de administración de API keys
+package http
+
+import (
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// APIKeyHandler maneja la administración de las API keys de los clientes
+// Sus rutas van en el subrouter /admin (requieren X-Admin-Key)
+type APIKeyHandler struct {
+	apiKeys domain.APIKeyService
+}
+
+// NewAPIKeyHandler crea un nuevo handler con el servicio inyectado
+func NewAPIKeyHandler(service domain.APIKeyService) *APIKeyHandler {
+	if service == nil {
+		panic("apiKeyService no puede ser nil")
+	}
+
+	return &APIKeyHandler{
+		apiKeys: service,
+	}
+}
+
+// HandleCreate maneja POST /admin/keys
+// Responde 201 con la clave y su secreto, que no se vuelve a mostrar
+func (h *APIKeyHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleCreateAPIKey", r.Method, r.URL.Path)
+
+	var req CreateAPIKeyRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	key, secret, err := h.apiKeys.CreateKey(r.Context(), req.toDomain())
+	if err != nil {
+		writeServiceError(w, err, "error al crear la API key")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONResponse(w, &APIKeyResponse{Success: true, Key: NewAPIKeyInfo(key, time.Now()), Secret: secret}, http.StatusCreated)
+}
+
+// HandleList maneja GET /admin/keys
+func (h *APIKeyHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleListAPIKeys", r.Method, r.URL.Path)
+
+	keys, err := h.apiKeys.ListKeys(r.Context())
+	if err != nil {
+		writeServiceError(w, err, "error al listar las API keys")
+		return
+	}
+
+	now := time.Now()
+	response := &APIKeysResponse{Success: true, Keys: make([]APIKeyInfo, 0, len(keys))}
+	for i := range keys {
+		response.Keys = append(response.Keys, NewAPIKeyInfo(&keys[i], now))
+	}
+	writeJSONResponse(w, response, http.StatusOK)
+}
+
+// HandleDisable maneja POST /admin/keys/{id}/disable
+func (h *APIKeyHandler) HandleDisable(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleDisableAPIKey", r.Method, r.URL.Path)
+
+	key, err := h.apiKeys.DisableKey(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, "error al desactivar la API key")
+		return
+	}
+	writeJSONResponse(w, &APIKeyResponse{Success: true, Key: NewAPIKeyInfo(key, time.Now())}, http.StatusOK)
+}
+
+// HandleRotate maneja POST /admin/keys/{id}/rotate
+// Responde con el secreto nuevo; el anterior vale hasta previous_expires_at.
+// El body es opcional: {"expires_at": ...} cambia también la caducidad
+func (h *APIKeyHandler) HandleRotate(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleRotateAPIKey", r.Method, r.URL.Path)
+
+	var req RotateAPIKeyRequest
+	if err := decodeJSON(r.Body, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeDecodeError(w, err)
+		return
+	}
+
+	key, secret, err := h.apiKeys.RotateKey(r.Context(), mux.Vars(r)["id"], req.expiresAt())
+	if err != nil {
+		writeServiceError(w, err, "error al rotar la API key")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONResponse(w, &APIKeyResponse{Success: true, Key: NewAPIKeyInfo(key, time.Now()), Secret: secret}, http.StatusOK)
+}
@@ -0,0 +1,50 @@
+// Package http - Webhook de caducidad de las API keys
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"time"
+)
+
+// ============================================================================
+// WEBHOOK DE CADUCIDAD DE API KEYS
+// ============================================================================
+//
+// Mismo formato y firma que los callbacks de los jobs (ver job_webhook.go),
+// con X-Webhook-Event api_key.expiring o api_key.expired y X-Webhook-ID
+// "<id de la clave>:<evento>" (el mismo si el aviso se repite). No lleva
+// X-Webhook-Attempt: si la entrega falla, se reintenta en la siguiente
+// pasada. La URL la configura el operador, así que puede ser interna.
+// ============================================================================
+
+// APIKeyWebhookNotifier entrega los eventos de caducidad por HTTP
+// Implementa domain.APIKeyNotifier
+type APIKeyWebhookNotifier struct {
+	webhook *JobWebhookNotifier
+	url     string
+}
+
+// NewAPIKeyWebhookNotifier crea el notificador
+// secret firma los eventos; timeout limita cada entrega
+func NewAPIKeyWebhookNotifier(url, secret string, timeout time.Duration, opts ...WebhookOption) *APIKeyWebhookNotifier {
+	if url == "" {
+		panic("url no puede estar vacía")
+	}
+
+	return &APIKeyWebhookNotifier{
+		webhook: NewJobWebhookNotifier(secret, timeout, true, opts...),
+		url:     url,
+	}
+}
+
+// NotifyAPIKey implementa domain.APIKeyNotifier
+func (n *APIKeyWebhookNotifier) NotifyAPIKey(ctx context.Context, event domain.APIKeyEvent) error {
+	body, err := json.Marshal(NewAPIKeyEventPayload(event))
+	if err != nil {
+		return fmt.Errorf("error al serializar el evento: %w", err)
+	}
+	return n.webhook.deliver(ctx, n.url, event.Type, event.Key.ID+":"+event.Type, 0, body)
+}
@@ -0,0 +1,59 @@
+// Package http - Límite de tamaño del body (MAX_BODY_BYTES)
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ============================================================================
+// LÍMITE DEL BODY
+// ============================================================================
+//
+// Sin límite, un cliente podría enviar un mensaje de cientos de MB que el
+// handler decodificaría entero en memoria y reenviaría a Groq. El middleware
+// rechaza con 413 las peticiones que declaran un Content-Length mayor y, para
+// las que no lo declaran (chunked), corta la lectura con http.MaxBytesReader:
+// el decoder JSON falla al pasar del límite y writeDecodeError responde 413.
+//
+// Los endpoints con límites propios (transcripción, proxy) los mantienen:
+// el menor de los dos es el que se aplica.
+// ============================================================================
+
+// maxBodyMiddleware limita el tamaño del body de las peticiones
+// Con limit <= 0 no se aplica ningún límite
+func maxBodyMiddleware(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Rechazar antes de leer nada si el tamaño ya se conoce
+			if r.ContentLength > limit {
+				writeErrorResponse(w, bodyTooLargeMessage(limit), http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeDecodeError responde al error al decodificar un body JSON
+// El body que supera el límite es 413; el resto, JSON inválido (400)
+func writeDecodeError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeErrorResponse(w, bodyTooLargeMessage(tooLarge.Limit), http.StatusRequestEntityTooLarge)
+		return
+	}
+	writeErrorResponse(w, "JSON inválido: "+err.Error(), http.StatusBadRequest)
+}
+
+// bodyTooLargeMessage es el mensaje de error para un body demasiado grande
+func bodyTooLargeMessage(limit int64) string {
+	return fmt.Sprintf("el body supera el tamaño máximo (%d bytes)", limit)
+}
@@ -0,0 +1,107 @@
+// Package http - Límite global de chats en curso (bulkhead)
+package http
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// BULKHEAD
+// ============================================================================
+//
+// El rate limit reparte la capacidad entre clientes, pero no protege al
+// proceso de una avalancha de muchos clientes a la vez (un despliegue, un
+// reintento masivo tras una caída): cada chat en curso ocupa memoria, una
+// conexión con el proveedor y cuota de tokens.
+//
+// El bulkhead es un semáforo de la réplica con BulkheadOptions.MaxInFlight
+// huecos para las rutas de chat. Sin hueco libre, la petición espera en una
+// cola corta (QueueSize peticiones, como mucho MaxWait) y, si sigue sin
+// hueco, recibe 503 overloaded con Retry-After: un balanceador puede
+// reintentarla en otra réplica.
+// ============================================================================
+
+// BulkheadOptions configura el límite global de chats en curso
+type BulkheadOptions struct {
+	// MaxInFlight son los chats simultáneos de la réplica (0 = sin límite)
+	MaxInFlight int
+
+	// QueueSize son las peticiones que pueden esperar hueco a la vez y
+	// MaxWait cuánto esperan (cualquiera de los dos a 0 = sin espera)
+	QueueSize int
+	MaxWait   time.Duration
+}
+
+// bulkheadMiddleware limita los chats en curso de la réplica
+// El semáforo se crea una vez: todas las rutas envueltas lo comparten
+func bulkheadMiddleware(options BulkheadOptions) func(http.Handler) http.Handler {
+	if options.MaxInFlight <= 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	slots := make(chan struct{}, options.MaxInFlight)
+	var queue chan struct{}
+	if options.QueueSize > 0 && options.MaxWait > 0 {
+		queue = make(chan struct{}, options.QueueSize)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !acquireSlot(r.Context(), slots, queue, options.MaxWait) {
+				writeBulkheadFull(w, options.MaxWait)
+				return
+			}
+			defer func() { <-slots }()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// acquireSlot ocupa un hueco; si no hay, espera en la cola como mucho maxWait
+// Retorna false con la cola llena, al agotar la espera o si el cliente se va
+func acquireSlot(ctx context.Context, slots, queue chan struct{}, maxWait time.Duration) bool {
+	select {
+	case slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	if queue == nil {
+		return false
+	}
+	select {
+	case queue <- struct{}{}:
+		defer func() { <-queue }()
+	default:
+		return false
+	}
+
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+
+	select {
+	case slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// writeBulkheadFull responde 503 overloaded con Retry-After
+// Se sugiere esperar lo mismo que la cola (como mínimo un segundo)
+func writeBulkheadFull(w http.ResponseWriter, maxWait time.Duration) {
+	retryAfter := int(math.Max(1, math.Ceil(maxWait.Seconds())))
+
+	response := NewErrorResponse("demasiados chats en curso, reintenta en unos segundos", http.StatusServiceUnavailable)
+	response.Type = "overloaded"
+	response.RetryAfter = retryAfter
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	writeJSONResponse(w, response, http.StatusServiceUnavailable)
+}
@@ -0,0 +1,82 @@
+// Package http - Funciones disponibles en el despliegue (capabilities)
+package http
+
+import (
+	"net/http"
+	"sort"
+)
+
+// ============================================================================
+// CAPABILITIES
+// ============================================================================
+//
+// Casi todo lo opcional de la API depende de la configuración: sin
+// STREAM_RESUME_SECONDS no hay reanudación, sin Groq no hay audio, etc. En
+// lugar de suponerlo (y descubrirlo con un 404), un SDK consulta
+//
+//   GET /api/v1/capabilities
+//
+// Igual que la especificación OpenAPI, la respuesta se construye al arrancar
+// a partir de los handlers registrados, así que no puede contradecir al router.
+// ============================================================================
+
+// newCapabilitiesResponse describe lo que registra SetupRouter
+func newCapabilitiesResponse(handlers Handlers, options RouterOptions) *CapabilitiesResponse {
+	names := make([]string, 0, len(options.Providers))
+	for name := range options.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	providers := make([]ProviderCapability, len(names))
+	for i, name := range names {
+		providers[i] = ProviderCapability{
+			Name:         name,
+			DefaultModel: options.Providers[name],
+			Default:      name == options.DefaultProvider,
+		}
+	}
+
+	return &CapabilitiesResponse{
+		Success:   true,
+		Providers: providers,
+		Features: CapabilityFeatures{
+			Streaming:    true,
+			StreamResume: handlers.Chat.streams != nil,
+			Tools:        true,
+			Vision:       true,
+			Grounding:    true,
+			// La API no busca en documentos propios: las fuentes de "verify"
+			// las envía el cliente
+			RAG:            false,
+			Audio:          handlers.Transcription != nil,
+			Batch:          handlers.Job != nil,
+			Conversations:  handlers.Conversation != nil,
+			Prompts:        handlers.Prompt != nil,
+			Classification: handlers.Classification != nil,
+			Redaction:      handlers.Redaction != nil,
+			NL2SQL:         handlers.NL2SQL != nil,
+			Code:           handlers.Code != nil,
+			Diff:           handlers.Diff != nil,
+			Proxy:          handlers.Proxy != nil,
+			Usage:          handlers.Usage != nil,
+			Feedback:       handlers.Feedback != nil,
+			Tokens:         handlers.Tokens != nil,
+			Idempotency:    options.Idempotency.Store != nil && options.Idempotency.TTL > 0,
+			RequestTimeout: options.MaxRequestTimeout > 0,
+			APIKeyAuth:     options.APIKeys != nil,
+		},
+		Limits: CapabilityLimits{
+			MaxBodyBytes:             options.MaxBodyBytes,
+			MaxTools:                 MaxTools,
+			MaxRequestTimeoutSeconds: int(options.MaxRequestTimeout.Seconds()),
+		},
+	}
+}
+
+// capabilitiesHandler sirve las capacidades calculadas al arrancar
+func capabilitiesHandler(capabilities *CapabilitiesResponse) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, capabilities, http.StatusOK)
+	}
+}
@@ -0,0 +1,58 @@
+// Package http - Handler HTTP de clasificación de textos
+package http
+
+import (
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// ClassificationHandler maneja las peticiones HTTP de clasificación
+type ClassificationHandler struct {
+	classificationService domain.ClassificationService
+}
+
+// NewClassificationHandler crea un nuevo handler con el servicio inyectado
+func NewClassificationHandler(service domain.ClassificationService) *ClassificationHandler {
+	if service == nil {
+		panic("classificationService no puede ser nil")
+	}
+
+	return &ClassificationHandler{
+		classificationService: service,
+	}
+}
+
+// HandleClassify maneja POST /api/v1/classify
+// Elige una de las etiquetas para el texto y retorna la confianza del modelo
+func (h *ClassificationHandler) HandleClassify(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleClassify", r.Method, r.URL.Path)
+
+	var req ClassifyRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	classification, err := h.classificationService.Classify(r.Context(), req.toDomain())
+	if err != nil {
+		// Igual que en /prompts/improve: los errores de validación del caso de
+		// uso son 400 y el resto se traduce igual que en /chat
+		if application.IsClassificationValidationError(err) {
+			writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServiceError(w, err, "error al clasificar el texto")
+		return
+	}
+	annotateAccessLog(r.Context(), classification.Model, &classification.Usage)
+
+	writeJSONResponse(w, &ClassifyResponse{
+		Success:    true,
+		Label:      classification.Label,
+		Confidence: classification.Confidence,
+		Model:      classification.Model,
+		Usage:      NewUsageInfo(classification.Usage),
+	}, http.StatusOK)
+}
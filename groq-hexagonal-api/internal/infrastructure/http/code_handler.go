@@ -0,0 +1,76 @@
+// Package http - Handlers HTTP de explicación y revisión de código
+package http
+
+import (
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// CodeHandler maneja las peticiones HTTP sobre código fuente
+type CodeHandler struct {
+	codeService domain.CodeService
+}
+
+// NewCodeHandler crea un nuevo handler con el servicio inyectado
+func NewCodeHandler(service domain.CodeService) *CodeHandler {
+	if service == nil {
+		panic("codeService no puede ser nil")
+	}
+
+	return &CodeHandler{
+		codeService: service,
+	}
+}
+
+// HandleExplain maneja POST /api/v1/code/explain
+// Explica el código por fragmentos y resume el fichero completo
+func (h *CodeHandler) HandleExplain(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleExplainCode", r.Method, r.URL.Path)
+
+	var req CodeRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	result, err := h.codeService.Explain(r.Context(), req.toDomain())
+	if err != nil {
+		writeCodeError(w, err, "error al explicar el código")
+		return
+	}
+	annotateAccessLog(r.Context(), result.Model, &result.Usage)
+
+	writeJSONResponse(w, NewCodeExplainResponse(result), http.StatusOK)
+}
+
+// HandleReview maneja POST /api/v1/code/review
+// Revisa el código y responde los hallazgos ordenados por línea
+func (h *CodeHandler) HandleReview(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleReviewCode", r.Method, r.URL.Path)
+
+	var req CodeRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	result, err := h.codeService.Review(r.Context(), req.toDomain())
+	if err != nil {
+		writeCodeError(w, err, "error al revisar el código")
+		return
+	}
+	annotateAccessLog(r.Context(), result.Model, &result.Usage)
+
+	writeJSONResponse(w, NewCodeReviewResponse(result), http.StatusOK)
+}
+
+// writeCodeError responde 400 a los errores de la petición y delega el resto
+func writeCodeError(w http.ResponseWriter, err error, fallback string) {
+	if application.IsCodeValidationError(err) {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeServiceError(w, err, fallback)
+}
@@ -0,0 +1,173 @@
+// Package http - Handlers HTTP de conversaciones
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// conversationErrorMessage es el mensaje de los errores internos del servicio
+const conversationErrorMessage = "error al procesar la conversación"
+
+// ============================================================================
+// HANDLER STRUCT
+// ============================================================================
+
+// ConversationHandler maneja las peticiones HTTP de conversaciones multi-turno
+type ConversationHandler struct {
+	conversationService domain.ConversationService
+}
+
+// NewConversationHandler crea un nuevo handler con el servicio inyectado
+func NewConversationHandler(service domain.ConversationService) *ConversationHandler {
+	if service == nil {
+		panic("conversationService no puede ser nil")
+	}
+
+	return &ConversationHandler{
+		conversationService: service,
+	}
+}
+
+// ============================================================================
+// HTTP HANDLERS
+// ============================================================================
+
+// HandleCreate maneja POST /api/v1/conversations
+func (h *ConversationHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleCreateConversation", r.Method, r.URL.Path)
+
+	// El body es opcional: una petición sin body crea una conversación por defecto
+	var req ConversationSettingsRequest
+	if r.ContentLength != 0 {
+		if err := decodeJSON(r.Body, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+	}
+
+	if err := req.Validate(); err != nil {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conversation, err := h.conversationService.CreateConversation(r.Context(), req.toDomain())
+	if err != nil {
+		writeServiceError(w, err, "error al crear la conversación")
+		return
+	}
+
+	// 201 Created: se ha creado un recurso nuevo
+	writeJSONResponse(w, NewConversationResponse(conversation), http.StatusCreated)
+}
+
+// HandleSendMessage maneja POST /api/v1/conversations/{id}/messages
+func (h *ConversationHandler) HandleSendMessage(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleSendConversationMessage", r.Method, r.URL.Path)
+
+	// mux.Vars() obtiene las variables de la ruta ({id})
+	conversationID := mux.Vars(r)["id"]
+
+	var req ConversationMessageRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conversation, response, err := h.conversationService.SendMessage(
+		r.Context(),
+		conversationID,
+		req.Message,
+		req.Model,
+		req.toMessageOptions(),
+	)
+	if err != nil {
+		writeServiceError(w, err, conversationErrorMessage)
+		return
+	}
+	annotateAccessLog(r.Context(), response.Model, &response.Usage)
+
+	writeCacheHeaders(w, response.Meta)
+	writeJSONResponse(w, &ConversationMessageResponse{
+		Success:        true,
+		ConversationID: conversation.ID,
+		Message:        response.GetResponseContent(),
+		Model:          response.Model,
+		Usage:          NewUsageInfo(response.Usage),
+		TurnCount:      len(conversation.Messages),
+		Warnings:       NewWarningInfos(response.Meta.Warnings),
+	}, http.StatusOK)
+}
+
+// HandleGet maneja GET /api/v1/conversations/{id}
+func (h *ConversationHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleGetConversation", r.Method, r.URL.Path)
+
+	conversation, err := h.conversationService.GetConversation(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, conversationErrorMessage)
+		return
+	}
+
+	writeJSONResponse(w, NewConversationResponse(conversation), http.StatusOK)
+}
+
+// HandlePin maneja PATCH /api/v1/conversations/{id}
+// Cambia los ajustes fijados (model, temperature, system_prompt)
+func (h *ConversationHandler) HandlePin(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandlePinConversation", r.Method, r.URL.Path)
+
+	var req ConversationSettingsRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	conversation, err := h.conversationService.PinSettings(r.Context(), mux.Vars(r)["id"], req.toDomain())
+	if err != nil {
+		writeServiceError(w, err, conversationErrorMessage)
+		return
+	}
+
+	writeJSONResponse(w, NewConversationResponse(conversation), http.StatusOK)
+}
+
+// HandleDelete maneja DELETE /api/v1/conversations/{id}
+// El borrado es recuperable con POST /api/v1/conversations/{id}/restore
+func (h *ConversationHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleDeleteConversation", r.Method, r.URL.Path)
+
+	conversation, err := h.conversationService.DeleteConversation(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, conversationErrorMessage)
+		return
+	}
+
+	writeJSONResponse(w, NewConversationResponse(conversation), http.StatusOK)
+}
+
+// HandleRestore maneja POST /api/v1/conversations/{id}/restore
+func (h *ConversationHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleRestoreConversation", r.Method, r.URL.Path)
+
+	conversation, err := h.conversationService.RestoreConversation(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, conversationErrorMessage)
+		return
+	}
+
+	writeJSONResponse(w, NewConversationResponse(conversation), http.StatusOK)
+}
@@ -0,0 +1,103 @@
+// Package http - Overrides de depuración (X-Debug-Overrides)
+package http
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// ============================================================================
+// X-DEBUG-OVERRIDES
+// ============================================================================
+//
+// Formato de la cabecera: lista separada por comas
+//
+//   X-Debug-Overrides: force_model=llama-3.1-8b-instant, no_cache, bypass_rate_limit
+//   X-Admin-Key: <ADMIN_API_KEY>
+//
+// Solo se aceptan junto a la clave de administración. Sin ella (o si el
+// servidor no tiene ADMIN_API_KEY), la petición se rechaza con 403: ignorar
+// los overrides en silencio haría creer al operador que se aplicaron.
+// ============================================================================
+
+const (
+	// DebugOverridesHeader contiene los overrides pedidos
+	DebugOverridesHeader = "X-Debug-Overrides"
+
+	// AdminKeyHeader contiene la clave de administración
+	AdminKeyHeader = "X-Admin-Key"
+)
+
+// debugOverridesMiddleware valida X-Debug-Overrides y lo guarda en el contexto
+func debugOverridesMiddleware(adminKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(DebugOverridesHeader)
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !isAdminKey(adminKey, r.Header.Get(AdminKeyHeader)) {
+				writeErrorResponse(w, "X-Debug-Overrides requiere una clave de administración válida", http.StatusForbidden)
+				return
+			}
+
+			overrides, err := parseDebugOverrides(header)
+			if err != nil {
+				writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			// Queda registrado quién alteró qué petición
+			log.Printf("[%s] %s - Debug overrides aplicados: %+v", r.Method, r.URL.Path, overrides)
+
+			r = r.WithContext(domain.WithDebugOverrides(r.Context(), overrides))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isAdminKey compara la clave recibida con la configurada
+// subtle.ConstantTimeCompare tarda lo mismo acierte o no: evita que se pueda
+// adivinar la clave midiendo tiempos de respuesta
+func isAdminKey(expected, received string) bool {
+	if expected == "" || received == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(received)) == 1
+}
+
+// parseDebugOverrides interpreta la cabecera X-Debug-Overrides
+func parseDebugOverrides(header string) (domain.DebugOverrides, error) {
+	var overrides domain.DebugOverrides
+
+	for _, item := range strings.Split(header, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		// strings.Cut divide en la primera aparición del separador
+		name, value, _ := strings.Cut(item, "=")
+		switch strings.TrimSpace(name) {
+		case "force_model":
+			overrides.ForceModel = strings.TrimSpace(value)
+			if overrides.ForceModel == "" {
+				return overrides, fmt.Errorf("force_model necesita un valor")
+			}
+		case "no_cache":
+			overrides.DisableCache = true
+		case "bypass_rate_limit":
+			overrides.BypassRateLimit = true
+		default:
+			return overrides, fmt.Errorf("override desconocido en %s: %q", DebugOverridesHeader, name)
+		}
+	}
+
+	return overrides, nil
+}
@@ -0,0 +1,91 @@
+// Package http - Diagnóstico del runtime de Go (/admin/runtime y pprof)
+package http
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/metrics"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// DIAGNÓSTICO DEL RUNTIME
+// ============================================================================
+//
+// Para investigar un problema de rendimiento en producción sin redesplegar:
+//
+//   GET /admin/runtime       goroutines, heap y GC de esta réplica (JSON)
+//   GET /admin/debug/pprof/  perfiles de net/http/pprof
+//
+// Ejemplo: curl -o cpu.prof -H "X-Admin-Key: ..." \
+//   http://localhost:8080/admin/debug/pprof/profile?seconds=30
+// y después go tool pprof cpu.prof
+//
+// Van en el subrouter /admin, así que requieren X-Admin-Key. Importar
+// net/http/pprof registra también sus rutas en http.DefaultServeMux, que
+// este servidor no sirve.
+// ============================================================================
+
+// startedAt es la hora de arranque del proceso (para el uptime)
+var startedAt = time.Now()
+
+// HandleRuntime maneja GET /admin/runtime
+// runtime.ReadMemStats para el mundo un instante: no es para sondear cada segundo
+func HandleRuntime(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleRuntime", r.Method, r.URL.Path)
+
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	writeJSONResponse(w, NewRuntimeResponse(&stats, time.Since(startedAt)), http.StatusOK)
+}
+
+// registerPprof monta los handlers de net/http/pprof en /debug/pprof/ del
+// subrouter de administración
+func registerPprof(adminRouter *mux.Router) {
+	profiles := http.NewServeMux()
+	profiles.HandleFunc("/debug/pprof/", pprof.Index)
+	profiles.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	profiles.HandleFunc("/debug/pprof/profile", withoutWriteDeadline(pprof.Profile))
+	profiles.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	profiles.HandleFunc("/debug/pprof/trace", withoutWriteDeadline(pprof.Trace))
+
+	// pprof.Index busca el nombre del perfil tras "/debug/pprof/"
+	adminRouter.PathPrefix("/debug/pprof/").Handler(http.StripPrefix("/admin", profiles))
+}
+
+// withoutWriteDeadline quita el WriteTimeout del servidor, que cortaría los
+// perfiles de más segundos (?seconds=30 por defecto en profile)
+func withoutWriteDeadline(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+			log.Printf("No se pudo quitar el write deadline: %v", err)
+		}
+		next(w, r)
+	}
+}
+
+// readGCSettings lee GOGC y el límite blando de memoria (0 = sin límite)
+// sin cambiarlos (debug.SetGCPercent solo los lee cambiándolos)
+func readGCSettings() (gcPercent int, memoryLimit int64) {
+	samples := []metrics.Sample{
+		{Name: "/gc/gogc:percent"},
+		{Name: "/gc/gomemlimit:bytes"},
+	}
+	metrics.Read(samples)
+
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		gcPercent = int(samples[0].Value.Uint64())
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		if limit := samples[1].Value.Uint64(); limit < math.MaxInt64 {
+			memoryLimit = int64(limit)
+		}
+	}
+	return gcPercent, memoryLimit
+}
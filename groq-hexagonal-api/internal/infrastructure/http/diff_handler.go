@@ -0,0 +1,49 @@
+// Package http - Handler HTTP de comparación de respuestas
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// DiffHandler maneja las peticiones HTTP de comparación de respuestas
+type DiffHandler struct {
+	diffService domain.DiffService
+}
+
+// NewDiffHandler crea un nuevo handler con el servicio inyectado
+func NewDiffHandler(service domain.DiffService) *DiffHandler {
+	if service == nil {
+		panic("diffService no puede ser nil")
+	}
+
+	return &DiffHandler{
+		diffService: service,
+	}
+}
+
+// HandleDiff maneja POST /api/v1/diff
+// Envía el mismo mensaje a dos configuraciones y compara las respuestas
+func (h *DiffHandler) HandleDiff(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleDiff", r.Method, r.URL.Path)
+
+	var req DiffRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.diffService.Compare(r.Context(), req.toDomain())
+	if err != nil {
+		writeServiceError(w, err, "error al comparar las respuestas")
+		return
+	}
+
+	writeJSONResponse(w, NewDiffResponse(result), http.StatusOK)
+}
@@ -2,6 +2,13 @@
 // Esta es parte de la CAPA DE INFRAESTRUCTURA
 package http
 
+import (
+	"encoding/json"
+	"groq-hexagonal-api/internal/domain"
+	"runtime"
+	"time"
+)
+
 // ============================================================================
 // DATA TRANSFER OBJECTS (DTOs)
 // ============================================================================
@@ -30,9 +37,287 @@ type ChatRequest struct {
 	// omitempty: si está vacío, no se incluye en el JSON
 	Model string `json:"model,omitempty" example:"llama-3.3-70b-versatile"`
 	
+	// SystemPrompt son instrucciones de sistema (opcional, hay default configurable)
+	SystemPrompt string `json:"system_prompt,omitempty" example:"Eres un experto en Go. Responde de forma concisa."`
+	
+	// Persona es el nombre de una plantilla de prompt (ver PROMPT_TEMPLATES_GIT_URL)
+	// Aporta system prompt, modelo y temperatura si el cliente no los envía
+	Persona string `json:"persona,omitempty" example:"soporte"`
+	
+	// Provider es el proveedor de modelos: "groq", "openai" u "ollama"
+	// (opcional, por defecto el configurado en LLM_PROVIDER)
+	Provider string `json:"provider,omitempty" example:"groq"`
+	
+	// Quality elige el modelo más barato del nivel: "fast", "balanced" o
+	// "best" (opcional, se ignora si se envía model; ver MODEL_PROFILES)
+	Quality string `json:"quality,omitempty" example:"balanced"`
+	
 	// Parámetros opcionales avanzados
 	Temperature *float64 `json:"temperature,omitempty" example:"0.7"`
 	MaxTokens   int      `json:"max_tokens,omitempty" example:"1000"`
+	
+	// Stop son secuencias que detienen la generación (máx. 4)
+	// Se mezclan con las secuencias configuradas por el operador
+	Stop []string `json:"stop,omitempty" example:"###"`
+	
+	// Parámetros de muestreo (opcionales, por defecto los del modelo)
+	TopP             *float64 `json:"top_p,omitempty" example:"0.9"`             // 0 a 1
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty" example:"0.5"` // -2 a 2
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty" example:"0"`    // -2 a 2
+	Seed             *int64   `json:"seed,omitempty" example:"42"`
+	
+	// N es el número de respuestas alternativas (1 a 128, sin streaming)
+	// Groq solo admite 1; las demás se devuelven en "choices"
+	N int `json:"n,omitempty" example:"1"`
+	
+	// Stream activa la respuesta por fragmentos (Server-Sent Events)
+	Stream bool `json:"stream,omitempty" example:"false"`
+	
+	// DryRun prepara la petición sin enviarla a Groq y retorna lo que se
+	// habría enviado, con la estimación de tokens y coste (ver DryRunResponse)
+	DryRun bool `json:"dry_run,omitempty" example:"false"`
+	
+	// Tools son las funciones que el modelo puede pedir invocar
+	Tools []ToolInfo `json:"tools,omitempty"`
+	
+	// ToolChoice: "auto", "none", "required" o {"type": "function", "function": {"name": "..."}}
+	ToolChoice json.RawMessage `json:"tool_choice,omitempty"`
+	
+	// History son los mensajes previos (ej: la respuesta con tool_calls y los
+	// resultados con role "tool"). Si el último es role "tool", message es opcional
+	History []MessageInfo `json:"history,omitempty"`
+	
+	// Images se adjuntan al mensaje (máx. 5, requiere un modelo con visión)
+	// Cada una es una URL http(s) o una data URI en base64 (ver images.go)
+	Images []string `json:"images,omitempty" example:"https://example.com/gato.jpg"`
+	
+	// Verify pide una segunda llamada que verifica la respuesta (ver "grounding"
+	// en la respuesta). No se admite con stream
+	Verify bool `json:"verify,omitempty" example:"false"`
+	
+	// Sources son los textos con los que se contrasta la respuesta (máx. 20,
+	// requiere verify). Sin fuentes se marcan las afirmaciones dudosas
+	Sources []string `json:"sources,omitempty" example:"Nuestra tienda abre de 9 a 18 h de lunes a viernes."`
+	
+	// Examples son ejemplos few-shot de entrada y respuesta (máx. 10)
+	Examples []FewShotExampleInfo `json:"examples,omitempty"`
+	
+	// Documents son textos de contexto para la respuesta, ej: los de un RAG
+	// (máx. 20). Van en el prompt, antes del historial
+	Documents []string `json:"documents,omitempty" example:"La garantía cubre dos años desde la compra."`
+}
+
+// FewShotExampleInfo es un ejemplo few-shot de ChatRequest
+type FewShotExampleInfo struct {
+	Input  string `json:"input" example:"¿Abrís los domingos?"`
+	Output string `json:"output" example:"No, abrimos de lunes a sábado."`
+}
+
+// ChatJobRequest es el DTO para POST /api/v1/chat/async
+// Es el mismo body que /chat más el callback opcional
+type ChatJobRequest struct {
+	ChatRequest
+	
+	// CallbackURL recibe el job al terminar (POST firmado con WEBHOOK_SECRET)
+	CallbackURL string `json:"callback_url,omitempty" example:"https://example.com/hooks/groq"`
+}
+
+// ToolInfo describe una herramienta (esquema compatible con OpenAI)
+type ToolInfo struct {
+	Type     string           `json:"type" example:"function"`
+	Function ToolFunctionInfo `json:"function"`
+}
+
+// ToolFunctionInfo describe una función invocable por el modelo
+type ToolFunctionInfo struct {
+	Name        string `json:"name" example:"get_weather"`
+	Description string `json:"description,omitempty" example:"Obtiene el tiempo de una ciudad"`
+	
+	// Parameters es el JSON Schema de los argumentos (se reenvía sin tocar)
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+}
+
+// ConversationSettingsRequest son los ajustes fijados de una conversación
+// Es el DTO de POST /api/v1/conversations y PATCH /api/v1/conversations/{id}
+// Todos los campos son opcionales (en PATCH, los vacíos no cambian)
+type ConversationSettingsRequest struct {
+	Model        string   `json:"model,omitempty" example:"llama-3.3-70b-versatile"`
+	Temperature  *float64 `json:"temperature,omitempty" example:"0.3"`
+	SystemPrompt string   `json:"system_prompt,omitempty" example:"Eres un tutor de Go"`
+	
+	// Budget limita el consumo total de la conversación (en PATCH
+	// reemplaza el anterior; {} lo quita)
+	Budget *ConversationBudgetInfo `json:"budget,omitempty"`
+}
+
+// ConversationBudgetInfo es el presupuesto de una conversación
+// Agotado, los mensajes se rechazan con 402 o, con fallback_model, van a
+// ese modelo
+type ConversationBudgetInfo struct {
+	MaxTotalTokens int     `json:"max_total_tokens,omitempty" example:"200000"`
+	MaxCostUSD     float64 `json:"max_cost_usd,omitempty" example:"0.5"`
+	FallbackModel  string  `json:"fallback_model,omitempty" example:"llama-3.1-8b-instant"`
+}
+
+// ConversationMessageRequest es el DTO para POST /api/v1/conversations/{id}/messages
+// model, temperature y system_prompt sobrescriben los de la conversación
+// solo para este mensaje
+type ConversationMessageRequest struct {
+	Message      string   `json:"message" example:"¿Y qué son las goroutines?"`
+	Model        string   `json:"model,omitempty" example:"llama-3.1-8b-instant"`
+	Temperature  *float64 `json:"temperature,omitempty" example:"0.7"`
+	SystemPrompt string   `json:"system_prompt,omitempty" example:"Responde con un ejemplo de código"`
+	MaxTokens    int      `json:"max_tokens,omitempty" example:"1000"`
+	Stop         []string `json:"stop,omitempty" example:"###"`
+}
+
+// ImprovePromptRequest es el DTO para POST /api/v1/prompts/improve
+type ImprovePromptRequest struct {
+	// Prompt es el prompt a mejorar (obligatorio)
+	Prompt string `json:"prompt" example:"Resume este texto"`
+	
+	// DesiredOutcome describe el resultado esperado (opcional pero recomendado)
+	DesiredOutcome string `json:"desired_outcome,omitempty" example:"Un resumen de 3 viñetas para directivos"`
+	
+	// Variants es cuántas variantes generar (0 = valor por defecto, máx. 5)
+	Variants int `json:"variants,omitempty" example:"2"`
+}
+
+// ClassifyRequest es el DTO para POST /api/v1/classify
+type ClassifyRequest struct {
+	// Text es el texto a clasificar (obligatorio)
+	Text string `json:"text" example:"No me ha llegado la factura de marzo"`
+	
+	// Labels son las etiquetas posibles (entre 2 y 50)
+	Labels []ClassifyLabel `json:"labels"`
+	
+	// Model es el modelo a usar (opcional, por defecto DEFAULT_MODEL)
+	Model string `json:"model,omitempty" example:"llama-3.1-8b-instant"`
+}
+
+// ClassifyLabel es una etiqueta de POST /api/v1/classify
+type ClassifyLabel struct {
+	Name        string `json:"name" example:"facturación"`
+	Description string `json:"description,omitempty" example:"Dudas sobre facturas, cobros y pagos"`
+	
+	// Examples son textos de ejemplo de la etiqueta (few-shot, máx. 5)
+	Examples []string `json:"examples,omitempty" example:"¿Por qué me habéis cobrado dos veces?"`
+}
+
+// FeedbackRequest es el DTO para POST /api/v1/feedback
+type FeedbackRequest struct {
+	// ResponseID es el "id" de la respuesta valorada y ConversationID el de
+	// su conversación (al menos uno)
+	ResponseID     string `json:"response_id,omitempty" example:"chatcmpl-5f1c2a9e"`
+	ConversationID string `json:"conversation_id,omitempty" example:"9b2f4c1d7a3e8f60b5c4d2e1f0a9b8c7"`
+	
+	// Rating es "up" o "down"
+	Rating string `json:"rating" example:"down"`
+	
+	// Comment es un comentario libre (opcional, máx. 2000 caracteres)
+	Comment string `json:"comment,omitempty" example:"La respuesta se inventa el horario de los sábados"`
+	
+	// Model y Persona son con los que se generó la respuesta (opcionales,
+	// para comparar modelos y prompts)
+	Model   string `json:"model,omitempty" example:"llama-3.3-70b-versatile"`
+	Persona string `json:"persona,omitempty" example:"soporte"`
+}
+
+// RedactRequest es el DTO para POST /api/v1/redact
+type RedactRequest struct {
+	// Text es el texto a anonimizar (obligatorio)
+	Text string `json:"text" example:"Escríbeme a ana@example.com o al 612 345 678"`
+	
+	// Types limita los tipos a anonimizar (opcional, por defecto todos)
+	Types []string `json:"types,omitempty" example:"email"`
+}
+
+// NL2SQLRequest es el DTO para POST /api/v1/nl2sql
+type NL2SQLRequest struct {
+	// Question es la pregunta en lenguaje natural (obligatoria)
+	Question string `json:"question" example:"¿Cuántos pedidos hizo cada cliente en 2024?"`
+	
+	// Schema describe las tablas, normalmente con sus CREATE TABLE (obligatorio)
+	Schema string `json:"schema" example:"CREATE TABLE orders (id INT, customer_id INT, created_at DATE);"`
+	
+	// Dialect es el dialecto SQL: postgresql (por defecto), mysql, sqlite o sqlserver
+	Dialect string `json:"dialect,omitempty" example:"postgresql"`
+	
+	// AllowMutations acepta INSERT, UPDATE, DELETE y DDL (por defecto solo lecturas)
+	AllowMutations bool `json:"allow_mutations,omitempty"`
+	
+	// Model es el modelo a usar (opcional, por defecto DEFAULT_MODEL)
+	Model string `json:"model,omitempty" example:"llama-3.3-70b-versatile"`
+}
+
+// CodeRequest es el DTO para POST /api/v1/code/explain y /code/review
+type CodeRequest struct {
+	// Code es el contenido del fichero (obligatorio)
+	Code string `json:"code" example:"func div(a, b int) int { return a / b }"`
+	
+	// Filename ayuda a detectar el lenguaje (opcional)
+	Filename string `json:"filename,omitempty" example:"math.go"`
+	
+	// Language fuerza el lenguaje (opcional, por defecto se detecta)
+	Language string `json:"language,omitempty" example:"go"`
+	
+	// Instructions es una pregunta sobre el código o en qué fijarse al revisarlo (opcional)
+	Instructions string `json:"instructions,omitempty" example:"Céntrate en los errores no controlados"`
+	
+	// Model es el modelo a usar (opcional, por defecto CODE_MODEL)
+	Model string `json:"model,omitempty" example:"openai/gpt-oss-120b"`
+}
+
+// DiffRequest es el DTO para POST /api/v1/diff
+type DiffRequest struct {
+	// Message se envía a ambas variantes
+	Message   string `json:"message" example:"Explica qué es un mutex"`
+	MaxTokens int    `json:"max_tokens,omitempty" example:"500"`
+	
+	// A y B son las dos configuraciones a comparar
+	A DiffVariantRequest `json:"a"`
+	B DiffVariantRequest `json:"b"`
+}
+
+// DiffVariantRequest es una de las configuraciones de POST /api/v1/diff
+type DiffVariantRequest struct {
+	Model        string   `json:"model,omitempty" example:"llama-3.1-8b-instant"`
+	Temperature  *float64 `json:"temperature,omitempty" example:"0.2"`
+	SystemPrompt string   `json:"system_prompt,omitempty" example:"Responde en una sola frase"`
+}
+
+// CreateAPIKeyRequest es el DTO para POST /admin/keys
+type CreateAPIKeyRequest struct {
+	// Name describe al cliente (obligatorio)
+	Name string `json:"name" example:"app-movil"`
+	
+	// Scopes limita la clave a partes de la API (opcional, por defecto todas)
+	Scopes []string `json:"scopes,omitempty" example:"chat"`
+	
+	// Tier es el plan del cliente (opcional)
+	Tier string `json:"tier,omitempty" example:"pro"`
+	
+	// Tenant es el tenant de todas sus peticiones (opcional, por defecto
+	// "default"); X-Tenant-ID no puede elegir otro
+	Tenant string `json:"tenant,omitempty" example:"acme"`
+	
+	// ExpiresAt es cuándo deja de valer, Unix timestamp (opcional, por defecto nunca)
+	ExpiresAt int64 `json:"expires_at,omitempty" example:"1767225600"`
+}
+
+// TokenRequest es el DTO para POST /api/v1/token
+type TokenRequest struct {
+	// Scopes limita el token a partes de la API (obligatorio, dentro de los de la clave)
+	Scopes []string `json:"scopes" example:"chat"`
+	
+	// ExpiresIn son los segundos que vale (opcional, por defecto 900)
+	ExpiresIn int64 `json:"expires_in,omitempty" example:"900"`
+}
+
+// RotateAPIKeyRequest es el DTO (opcional) para POST /admin/keys/{id}/rotate
+type RotateAPIKeyRequest struct {
+	// ExpiresAt es la nueva caducidad, Unix timestamp (opcional, por defecto se mantiene)
+	ExpiresAt int64 `json:"expires_at,omitempty" example:"1798761600"`
 }
 
 // ============================================================================
@@ -53,132 +338,2054 @@ type ChatResponse struct {
 	// Usage contiene información sobre tokens usados
 	Usage *UsageInfo `json:"usage,omitempty"`
 	
+	// TruncatedByPolicy indica que la respuesta se recortó por el límite
+	// de longitud configurado para el tenant
+	TruncatedByPolicy bool `json:"truncated_by_policy,omitempty"`
+	
+	// DetectedLanguage es el idioma detectado del mensaje (ej: "es")
+	DetectedLanguage string `json:"detected_language,omitempty"`
+	
+	// RemappedFrom es el modelo pedido cuando estaba retirado y se usó otro
+	// (el modelo realmente usado está en Model)
+	RemappedFrom string `json:"remapped_from,omitempty"`
+	
+	// Quality es el nivel con el que se eligió el modelo por coste
+	// (solo si el modelo se eligió así)
+	Quality string `json:"quality,omitempty"`
+	
+	// ToolCalls son las herramientas que el modelo pide invocar
+	// En ese caso Message suele venir vacío y FinishReason es "tool_calls"
+	ToolCalls []ToolCallInfo `json:"tool_calls,omitempty"`
+	
+	// FinishReason indica por qué terminó la generación (ej: "stop", "tool_calls")
+	FinishReason string `json:"finish_reason,omitempty"`
+	
+	// Choices son los textos de todas las respuestas cuando se pidió n > 1
+	// (Message es la primera)
+	Choices []string `json:"choices,omitempty"`
+	
+	// Grounding es la verificación de la respuesta (solo con verify)
+	Grounding *GroundingInfo `json:"grounding,omitempty"`
+	
+	// HistorySummary es el resumen que sustituyó al historial más antiguo
+	// (solo si se resumió): el cliente puede enviarlo en lugar de esos mensajes
+	HistorySummary *HistorySummaryInfo `json:"history_summary,omitempty"`
+	
+	// Warnings avisan de lo que la API hizo distinto de lo pedido
+	// (modelo reemplazado, respuesta recortada...)
+	Warnings []WarningInfo `json:"warnings,omitempty"`
+	
 	// Error contiene el mensaje de error si success=false
 	// omitempty: solo se incluye si hay error
 	Error string `json:"error,omitempty"`
 }
 
+// GroundingInfo es el resultado de verificar la respuesta
+type GroundingInfo struct {
+	Score             float64                `json:"score" example:"0.75"`        // Parte respaldada, de 0 a 1
+	UnsupportedClaims []UnsupportedClaimInfo `json:"unsupported_claims"`          // Vacío si todo está respaldado
+	WithSources       bool                   `json:"with_sources" example:"true"` // false: sin fuentes, solo afirmaciones dudosas
+	Model             string                 `json:"model"`
+	Usage             *UsageInfo             `json:"usage,omitempty"` // Tokens de la verificación (aparte de los de la respuesta)
+}
+
+// HistorySummaryInfo es el resumen de los mensajes más antiguos del historial
+type HistorySummaryInfo struct {
+	Content  string     `json:"content"`
+	Messages int        `json:"messages" example:"12"` // Mensajes del principio del historial (tras los de sistema) que sustituye
+	Model    string     `json:"model"`
+	Usage    *UsageInfo `json:"usage,omitempty"` // Tokens del resumen (aparte de los de la respuesta)
+}
+
+// UnsupportedClaimInfo es una afirmación sin respaldo
+type UnsupportedClaimInfo struct {
+	Claim  string `json:"claim" example:"La tienda abre los sábados"`
+	Reason string `json:"reason" example:"Las fuentes solo mencionan de lunes a viernes"`
+}
+
+// DryRunResponse es el DTO de POST /api/v1/chat con "dry_run": true
+type DryRunResponse struct {
+	Success bool `json:"success"`
+	DryRun  bool `json:"dry_run"` // Siempre true: no se ha llamado a Groq
+	
+	// Model es el modelo que se usaría (tras personas, idioma y alias)
+	Model        string `json:"model"`
+	RemappedFrom string `json:"remapped_from,omitempty"`
+	
+	// Request es el body exacto que se enviaría a Groq (/chat/completions)
+	Request domain.ChatRequest `json:"request"`
+	
+	// EstimatedPromptTokens es aproximado (ver domain/tokens.go)
+	EstimatedPromptTokens int `json:"estimated_prompt_tokens"`
+	
+	// EstimatedCost solo aparece si el modelo tiene precio (MODEL_PRICING)
+	EstimatedCost *CostEstimateInfo `json:"estimated_cost,omitempty"`
+	
+	// PromptSections reparte estimated_prompt_tokens entre las secciones del
+	// prompt, en orden (ver domain/prompt_sections.go)
+	PromptSections []PromptSectionInfo `json:"prompt_sections,omitempty"`
+	
+	// ContextWindow es la ventana del modelo (se omite si no se conoce)
+	ContextWindow int `json:"context_window,omitempty" example:"131072"`
+	
+	DetectedLanguage string        `json:"detected_language,omitempty"`
+	Warnings         []WarningInfo `json:"warnings,omitempty"`
+}
+
+// PromptSectionInfo es una sección del prompt de un dry run
+type PromptSectionInfo struct {
+	Name string `json:"name" example:"history"`
+	
+	// Start y End delimitan sus mensajes en request.messages: [start, end)
+	Start int `json:"start" example:"2"`
+	End   int `json:"end" example:"6"`
+	
+	EstimatedTokens int `json:"estimated_tokens" example:"412"`
+}
+
+// CostEstimateInfo es el coste estimado en USD
+type CostEstimateInfo struct {
+	Currency string  `json:"currency" example:"USD"`
+	Prompt   float64 `json:"prompt" example:"0.000059"`
+	
+	// MaxCompletion es el coste si la respuesta llega a max_tokens
+	// Se omite si la petición no envía max_tokens
+	MaxCompletion float64 `json:"max_completion,omitempty" example:"0.00079"`
+}
+
+// WarningInfo es un aviso para el cliente
+// Code es estable (ej: "model_remapped"); Message es para mostrar al usuario
+type WarningInfo struct {
+	Code    string `json:"code" example:"model_remapped"`
+	Message string `json:"message" example:"el modelo X está retirado; se ha usado Y"`
+}
+
+// ToolCallInfo es una invocación de herramienta pedida por el modelo
+type ToolCallInfo struct {
+	// Index solo aparece en streaming (identifica la llamada de cada fragmento)
+	Index    *int                 `json:"index,omitempty"`
+	ID       string               `json:"id,omitempty" example:"call_abc123"`
+	Type     string               `json:"type,omitempty" example:"function"`
+	Function ToolCallFunctionInfo `json:"function"`
+}
+
+// ToolCallFunctionInfo es la función invocada y sus argumentos (JSON en un string)
+type ToolCallFunctionInfo struct {
+	Name      string `json:"name,omitempty" example:"get_weather"`
+	Arguments string `json:"arguments" example:"{\"city\": \"Madrid\"}"`
+}
+
 // UsageInfo contiene información sobre el uso de tokens
 type UsageInfo struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+
+	// CostUSD es el coste estimado (solo si el modelo tiene precio, MODEL_PRICING)
+	CostUSD float64 `json:"cost_usd,omitempty" example:"0.000134"`
 }
 
-// ModelsResponse es el DTO para la lista de modelos
-type ModelsResponse struct {
-	Success bool          `json:"success"`
-	Models  []ModelInfo   `json:"models,omitempty"`
-	Error   string        `json:"error,omitempty"`
+// StreamChunkResponse es el DTO de cada evento SSE en modo streaming
+// Cada evento lleva solo el texto nuevo; el cliente debe concatenarlos
+type StreamChunkResponse struct {
+	// Content es el texto nuevo de este fragmento
+	Content string `json:"content"`
+	
+	// Model indica qué modelo se usó
+	Model string `json:"model,omitempty"`
+	
+	// FinishReason solo aparece en el último fragmento (ej: "stop", "length")
+	FinishReason string `json:"finish_reason,omitempty"`
+	
+	// ToolCalls son fragmentos de llamadas a herramientas; los argumentos
+	// llegan troceados y se concatenan agrupando por index
+	ToolCalls []ToolCallInfo `json:"tool_calls,omitempty"`
+	
+	// Usage solo aparece en el último fragmento
+	Usage *UsageInfo `json:"usage,omitempty"`
 }
 
-// ModelInfo contiene información sobre un modelo
-type ModelInfo struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	OwnedBy string `json:"owned_by"`
+// ConversationInfo es la representación HTTP de una conversación
+type ConversationInfo struct {
+	ID    string `json:"id"`
+	Model string `json:"model"`
+	
+	// Ajustes fijados (además de Model)
+	Temperature  *float64                `json:"temperature,omitempty"`
+	SystemPrompt string                  `json:"system_prompt,omitempty"`
+	Budget       *ConversationBudgetInfo `json:"budget,omitempty"`
+	
+	// Spent es el consumo sumado de todos los turnos (y de los resúmenes)
+	Spent *UsageInfo `json:"spent"`
+	
+	// Summary sustituye a los primeros mensajes al enviar el historial al
+	// modelo (Messages sigue completo)
+	Summary *ConversationSummaryInfo `json:"summary,omitempty"`
+	
+	Messages  []MessageInfo `json:"messages"`
+	CreatedAt int64         `json:"created_at"` // Unix timestamp
+	UpdatedAt int64         `json:"updated_at"` // Unix timestamp
+	
+	// Solo en conversaciones borradas (Unix timestamps)
+	DeletedAt int64 `json:"deleted_at,omitempty"`
+	PurgeAt   int64 `json:"purge_at,omitempty"` // Hasta entonces se puede restaurar
 }
 
-// ============================================================================
-// GENERIC RESPONSE DTOs
-// ============================================================================
+// ConversationSummaryInfo es el resumen del historial de una conversación
+type ConversationSummaryInfo struct {
+	Content   string     `json:"content"`
+	Messages  int        `json:"messages" example:"12"` // Mensajes del principio del historial que resume
+	Model     string     `json:"model"`
+	Usage     *UsageInfo `json:"usage,omitempty"` // Suma de todos los resúmenes
+	CreatedAt int64      `json:"created_at"`      // Unix timestamp
+}
 
-// ErrorResponse es una respuesta genérica de error
-type ErrorResponse struct {
-	Success bool   `json:"success"`
-	Error   string `json:"error"`
-	Code    int    `json:"code,omitempty"`
+// MessageInfo es un mensaje del historial
+type MessageInfo struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+	
+	// Solo en mensajes de herramientas (ver ToolCallInfo)
+	ToolCalls  []ToolCallInfo `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+	
+	// Solo en el historial de una conversación
+	CreatedAt int64     `json:"created_at,omitempty"` // Unix timestamp
+	Turn      *TurnInfo `json:"turn,omitempty"`       // Solo en respuestas del asistente
 }
 
-// SuccessResponse es una respuesta genérica de éxito
-type SuccessResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+// TurnInfo son los datos de generación de un turno del asistente
+type TurnInfo struct {
+	Model        string     `json:"model"`
+	LatencyMs    int64      `json:"latency_ms"`
+	Usage        *UsageInfo `json:"usage"`
+	FinishReason string     `json:"finish_reason,omitempty"`
+	CacheHit     bool       `json:"cache_hit"`
 }
 
-// HealthResponse es la respuesta del endpoint de health check
-type HealthResponse struct {
-	Status    string `json:"status"`
-	Timestamp int64  `json:"timestamp"`
-	Service   string `json:"service"`
+// ConversationResponse es el DTO de crear/obtener una conversación
+type ConversationResponse struct {
+	Success      bool              `json:"success"`
+	Conversation *ConversationInfo `json:"conversation,omitempty"`
+	Error        string            `json:"error,omitempty"`
 }
 
-// ============================================================================
-// MÉTODOS DE VALIDACIÓN
-// ============================================================================
+// ConversationMessageResponse es el DTO de enviar un mensaje a una conversación
+type ConversationMessageResponse struct {
+	Success        bool       `json:"success"`
+	ConversationID string     `json:"conversation_id"`
+	Message        string     `json:"message"` // Respuesta del asistente
+	Model          string     `json:"model"`
+	Usage          *UsageInfo `json:"usage,omitempty"`
+	TurnCount      int        `json:"turn_count"` // Mensajes en el historial
+	
+	// Warnings: ver ChatResponse
+	Warnings []WarningInfo `json:"warnings,omitempty"`
+}
 
-// Validate valida el ChatRequest
-// Retorna error si algo está mal
-func (r *ChatRequest) Validate() error {
-	// Verificar que el mensaje no esté vacío
-	if r.Message == "" {
-		return ErrEmptyMessage
-	}
+// ImprovePromptResponse es el DTO de la propuesta de mejora de un prompt
+type ImprovePromptResponse struct {
+	Success        bool       `json:"success"`
+	ImprovedPrompt string     `json:"improved_prompt"`
+	Suggestions    []string   `json:"suggestions"`
+	Variants       []string   `json:"variants"`
+	Model          string     `json:"model"`
+	Usage          *UsageInfo `json:"usage,omitempty"`
+}
+
+// ClassifyResponse es el DTO de la etiqueta elegida por el modelo
+type ClassifyResponse struct {
+	Success    bool       `json:"success"`
+	Label      string     `json:"label" example:"facturación"`
+	Confidence float64    `json:"confidence" example:"0.92"`
+	Model      string     `json:"model"`
+	Usage      *UsageInfo `json:"usage,omitempty"`
+}
+
+// RedactResponse es el DTO del texto anonimizado
+type RedactResponse struct {
+	Success  bool                 `json:"success"`
+	Text     string               `json:"text" example:"Escríbeme a [EMAIL_1] o al [PHONE_1]"`
+	Entities []RedactedEntityInfo `json:"entities"`
+}
+
+// RedactedEntityInfo es un dato personal sustituido
+type RedactedEntityInfo struct {
+	Type        string `json:"type" example:"email"`
+	Text        string `json:"text" example:"ana@example.com"`
+	Placeholder string `json:"placeholder" example:"[EMAIL_1]"`
+}
+
+// JobResponse es el DTO de un chat asíncrono (POST /chat/async y GET /jobs/{id})
+type JobResponse struct {
+	Success bool   `json:"success"`
+	ID      string `json:"id" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Status  string `json:"status" example:"pending"` // pending, running, succeeded o failed
+	Model   string `json:"model,omitempty"`          // Modelo pedido (vacío = por defecto)
 	
-	// Validar temperatura si está presente
-	if r.Temperature != nil {
-		temp := *r.Temperature
-		if temp < 0 || temp > 2 {
-			return ErrInvalidTemperature
-		}
-	}
+	// Result es la respuesta, igual que la de /chat (solo en succeeded)
+	Result *ChatResponse `json:"result,omitempty"`
 	
-	// Validar max_tokens si está presente
-	if r.MaxTokens < 0 {
-		return ErrInvalidMaxTokens
-	}
+	// ResultURL y ResultSize sustituyen a result si la respuesta es grande:
+	// se descarga aparte, en el formato del proveedor (choices...)
+	ResultURL  string `json:"result_url,omitempty" example:"/api/v1/jobs/9f86d081884c7d659a2feaa0c55ad015/result"`
+	ResultSize int64  `json:"result_size,omitempty"` // Bytes
 	
-	return nil
+	// JobError es la causa del fallo y JobErrorType su tipo, el mismo que
+	// el "type" de los errores HTTP (solo en failed)
+	JobError     string `json:"error,omitempty"`
+	JobErrorType string `json:"error_type,omitempty" example:"rate_limited"`
+	
+	// Progress es el avance de la generación (ausente mientras está en cola)
+	Progress *JobProgressInfo `json:"progress,omitempty"`
+	
+	// Unix timestamps; started_at y finished_at solo cuando ocurren
+	CreatedAt  int64 `json:"created_at"`
+	StartedAt  int64 `json:"started_at,omitempty"`
+	FinishedAt int64 `json:"finished_at,omitempty"`
+	ExpiresAt  int64 `json:"expires_at"` // Después, GET /jobs/{id} responde 404
+	
+	// Callback es el estado de la entrega a callback_url (solo si se pidió)
+	Callback *JobCallbackInfo `json:"callback,omitempty"`
 }
 
-// ============================================================================
-// ERRORES DE VALIDACIÓN
-// ============================================================================
+// JobProgressInfo es el avance de un job
+type JobProgressInfo struct {
+	// Tokens son los tokens generados (estimados hasta que termina; solo
+	// avanza en los jobs con "stream": true)
+	Tokens int `json:"tokens" example:"350"`
+	
+	// Percent es el porcentaje completado (solo con max_tokens o al terminar)
+	Percent *int `json:"percent,omitempty" example:"35"`
+	
+	UpdatedAt int64 `json:"updated_at"` // Unix timestamp
+}
 
-// Definimos errores personalizados para validación
-// Estos son específicos de la capa HTTP
-var (
-	ErrEmptyMessage        = NewValidationError("el mensaje no puede estar vacío")
-	ErrInvalidTemperature  = NewValidationError("la temperatura debe estar entre 0 y 2")
-	ErrInvalidMaxTokens    = NewValidationError("max_tokens debe ser mayor o igual a 0")
-)
+// JobCallbackInfo es el estado de la entrega de un job a su callback_url
+type JobCallbackInfo struct {
+	URL       string `json:"url" example:"https://example.com/hooks/groq"`
+	Status    string `json:"status" example:"delivered"` // pending, delivered o failed
+	Attempts  int    `json:"attempts" example:"1"`
+	LastError string `json:"last_error,omitempty"` // Causa del último intento fallido
+}
 
-// ValidationError es un tipo de error personalizado para validaciones
-type ValidationError struct {
-	Message string
+// NL2SQLResponse es el DTO de la consulta generada y validada
+type NL2SQLResponse struct {
+	Success       bool       `json:"success"`
+	SQL           string     `json:"sql" example:"SELECT customer_id, COUNT(*) FROM orders GROUP BY customer_id"`
+	Explanation   string     `json:"explanation"`
+	StatementType string     `json:"statement_type" example:"select"` // Operación de la sentencia
+	Mutating      bool       `json:"mutating"`                        // Si modifica datos o esquema
+	Tables        []string   `json:"tables" example:"orders"`
+	Model         string     `json:"model"`
+	Usage         *UsageInfo `json:"usage,omitempty"`
 }
 
-// Error implementa la interfaz error
-// Este método es necesario para que ValidationError sea un error
-func (e *ValidationError) Error() string {
-	return e.Message
+// CodeExplainResponse es el DTO de la explicación de un fichero
+type CodeExplainResponse struct {
+	Success  bool              `json:"success"`
+	Language string            `json:"language" example:"go"` // Vacío si no se pudo detectar
+	Summary  string            `json:"summary"`
+	Sections []CodeSectionInfo `json:"sections"` // Una por fragmento del fichero
+	Model    string            `json:"model"`
+	Usage    *UsageInfo        `json:"usage,omitempty"`
 }
 
-// NewValidationError crea un nuevo error de validación
-func NewValidationError(message string) *ValidationError {
-	return &ValidationError{Message: message}
+// CodeSectionInfo es la explicación de un rango de líneas
+type CodeSectionInfo struct {
+	StartLine   int    `json:"start_line" example:"1"`
+	EndLine     int    `json:"end_line" example:"120"`
+	Explanation string `json:"explanation"` // Markdown
 }
 
-// ============================================================================
-// FACTORY FUNCTIONS (funciones para crear DTOs)
-// ============================================================================
+// CodeReviewResponse es el DTO de la revisión de un fichero
+type CodeReviewResponse struct {
+	Success  bool              `json:"success"`
+	Language string            `json:"language" example:"go"` // Vacío si no se pudo detectar
+	Summary  string            `json:"summary"`
+	Findings []CodeFindingInfo `json:"findings"`           // Ordenados por línea
+	Chunks   int               `json:"chunks" example:"1"` // Fragmentos en que se partió el fichero
+	Model    string            `json:"model"`
+	Usage    *UsageInfo        `json:"usage,omitempty"`
+}
 
-// NewChatResponse crea una respuesta de chat exitosa
-func NewChatResponse(message, model string, usage *UsageInfo) *ChatResponse {
-	return &ChatResponse{
-		Success: true,
-		Message: message,
-		Model:   model,
-		Usage:   usage,
-	}
+// CodeFindingInfo es un problema encontrado en la revisión
+type CodeFindingInfo struct {
+	Severity    string `json:"severity" example:"high"` // critical, high, medium, low o info
+	Category    string `json:"category" example:"bug"`  // bug, security, performance, maintainability o style
+	Line        int    `json:"line" example:"1"`        // 0 si no se sabe
+	EndLine     int    `json:"end_line,omitempty"`
+	Title       string `json:"title" example:"División por cero"`
+	Description string `json:"description"`
+	Suggestion  string `json:"suggestion,omitempty"`
 }
 
-// NewChatErrorResponse crea una respuesta de error de chat
-func NewChatErrorResponse(errorMsg string) *ChatResponse {
-	return &ChatResponse{
-		Success: false,
-		Error:   errorMsg,
+// UsageResponse es el DTO de GET /api/v1/usage
+type UsageResponse struct {
+	Success   bool             `json:"success"`
+	Client    string           `json:"client" example:"key:3f9a1c0b7e2d"` // Identidad a la que se cuenta el consumo
+	Today     UsageInfo        `json:"today"`
+	Quota     int64            `json:"quota"`     // Tokens por día (0 = sin cuota)
+	Remaining int64            `json:"remaining"` // Tokens que quedan hoy (0 si no hay cuota)
+	ResetAt   int64            `json:"reset_at"`  // Unix: inicio del día siguiente (UTC)
+	History   []DailyUsageInfo `json:"history"`   // Días anteriores con consumo (ver ?days=)
+	CostUSD   float64          `json:"cost_usd"`  // Coste estimado de hoy y de los días de history
+}
+
+// DailyUsageInfo es el consumo de un día
+type DailyUsageInfo struct {
+	Day   string    `json:"day" example:"2026-10-15"`
+	Usage UsageInfo `json:"usage"`
+}
+
+// StatementResponse es el DTO de GET /api/v1/tenants/{id}/statements/{month}
+type StatementResponse struct {
+	Success          bool                        `json:"success"`
+	Tenant           string                      `json:"tenant" example:"acme"`
+	Month            string                      `json:"month" example:"2026-09"`
+	PeriodStart      int64                       `json:"period_start"` // Unix: inicio del mes (UTC)
+	PeriodEnd        int64                       `json:"period_end"`   // Unix: inicio del mes siguiente
+	Provisional      bool                        `json:"provisional"`  // El mes todavía no ha terminado
+	Requests         int64                       `json:"requests"`
+	Usage            UsageInfo                   `json:"usage"`
+	Models           []StatementModelInfo        `json:"models"`            // Del más caro al más barato
+	TopConversations []StatementConversationInfo `json:"top_conversations"` // Las de más tokens (máx. 10)
+	GeneratedAt      int64                       `json:"generated_at"`
+}
+
+// StatementModelInfo es el consumo del mes con un modelo
+type StatementModelInfo struct {
+	Model    string    `json:"model" example:"llama-3.3-70b-versatile"`
+	Requests int64     `json:"requests"`
+	Usage    UsageInfo `json:"usage"`
+}
+
+// StatementConversationInfo es el consumo del mes en una conversación
+type StatementConversationInfo struct {
+	ConversationID string    `json:"conversation_id"`
+	Requests       int64     `json:"requests"`
+	Usage          UsageInfo `json:"usage"`
+}
+
+// FeedbackResponse es el DTO de POST /api/v1/feedback
+type FeedbackResponse struct {
+	Success  bool         `json:"success"`
+	Feedback FeedbackInfo `json:"feedback"`
+}
+
+// FeedbackInfo es una valoración guardada
+type FeedbackInfo struct {
+	ID             string `json:"id" example:"4e7d2a1c9b0f8e6d5c3b2a1f0e9d8c7b"`
+	ResponseID     string `json:"response_id,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+	Rating         string `json:"rating" example:"down"`
+	Comment        string `json:"comment,omitempty"`
+	Model          string `json:"model,omitempty"`
+	Persona        string `json:"persona,omitempty"`
+	Tenant         string `json:"tenant,omitempty"`
+	CreatedAt      int64  `json:"created_at"` // Unix
+}
+
+// FeedbackSummaryResponse es el DTO de GET /admin/feedback
+type FeedbackSummaryResponse struct {
+	Success bool   `json:"success"`
+	Since   int64  `json:"since"`            // Unix: inicio del periodo (ver ?days=)
+	Tenant  string `json:"tenant,omitempty"` // Vacío = todos los tenants
+	
+	Total     FeedbackCountInfo   `json:"total"`
+	ByModel   []FeedbackCountInfo `json:"by_model"`   // De la peor puntuación a la mejor
+	ByPersona []FeedbackCountInfo `json:"by_persona"` // De la peor puntuación a la mejor
+	
+	// RecentComments son las últimas valoraciones con comentario (máx. 20)
+	RecentComments []FeedbackInfo `json:"recent_comments"`
+}
+
+// FeedbackCountInfo cuenta las valoraciones de un modelo, una persona o el total
+type FeedbackCountInfo struct {
+	Name  string  `json:"name,omitempty" example:"llama-3.3-70b-versatile"`
+	Up    int     `json:"up" example:"42"`
+	Down  int     `json:"down" example:"8"`
+	Score float64 `json:"score" example:"0.84"` // Parte de valoraciones positivas (0 a 1)
+}
+
+// FormFile es un fichero de un formulario multipart (solo para OpenAPI)
+type FormFile string
+
+// TranscriptionForm describe el formulario de POST /api/v1/audio/transcriptions
+// El handler lee los campos del multipart directamente; este DTO solo sirve
+// para la especificación OpenAPI
+type TranscriptionForm struct {
+	File        FormFile `json:"file"`
+	Model       string   `json:"model,omitempty" example:"whisper-large-v3"`
+	Language    string   `json:"language,omitempty" example:"es"`
+	Prompt      string   `json:"prompt,omitempty"`
+	Temperature float64  `json:"temperature,omitempty"`
+}
+
+// TranscriptionResponse es el DTO de POST /api/v1/audio/transcriptions
+type TranscriptionResponse struct {
+	Success  bool                       `json:"success"`
+	Text     string                     `json:"text"`
+	Language string                     `json:"language,omitempty"` // Detectado o indicado
+	Duration float64                    `json:"duration"`           // Segundos de audio
+	Model    string                     `json:"model"`
+	Segments []TranscriptionSegmentInfo `json:"segments"`
+}
+
+// TranscriptionSegmentInfo es un fragmento con sus marcas de tiempo (segundos)
+type TranscriptionSegmentInfo struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+// DiffResponse es el DTO del resultado de POST /api/v1/diff
+type DiffResponse struct {
+	Success bool            `json:"success"`
+	A       DiffVariantInfo `json:"a"`
+	B       DiffVariantInfo `json:"b"`
+	
+	// Similarity va de 0 a 1 (1 = mismas palabras en el mismo orden)
+	Similarity float64 `json:"similarity"`
+	
+	// Diff es la comparación palabra a palabra de A hacia B
+	// Se omite si las respuestas son demasiado largas
+	Diff []DiffOpInfo `json:"diff,omitempty"`
+}
+
+// DiffVariantInfo es la respuesta de una de las variantes
+type DiffVariantInfo struct {
+	Message string     `json:"message"`
+	Model   string     `json:"model"`
+	Usage   *UsageInfo `json:"usage,omitempty"`
+}
+
+// DiffOpInfo es un tramo del diff
+type DiffOpInfo struct {
+	// Op es "equal", "delete" (solo en A) o "insert" (solo en B)
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// ModelsHealthResponse es la respuesta de GET /admin/models/health
+type ModelsHealthResponse struct {
+	Success bool              `json:"success"`
+	Models  []ModelHealthInfo `json:"models"`
+}
+
+// ModelHealthInfo es la salud reciente de un modelo
+type ModelHealthInfo struct {
+	Provider string `json:"provider" example:"groq"`
+	Model    string `json:"model" example:"llama-3.3-70b-versatile"`
+	
+	// Peticiones de la ventana (failures incluye las rechazadas con 429)
+	Requests    int     `json:"requests"`
+	Failures    int     `json:"failures"`
+	RateLimited int     `json:"rate_limited"`
+	ErrorRate   float64 `json:"error_rate" example:"0.02"`
+	
+	// P95LatencyMs es la latencia p95 de las peticiones con éxito
+	P95LatencyMs int64 `json:"p95_latency_ms"`
+	
+	// Circuit es "closed", "open" o "half_open"
+	Circuit          string `json:"circuit" example:"closed"`
+	CircuitOpenUntil int64  `json:"circuit_open_until,omitempty"` // Unix timestamp
+	
+	// RateLimit se omite si el proveedor no envía cabeceras x-ratelimit-*
+	RateLimit *RateLimitInfo `json:"rate_limit,omitempty"`
+}
+
+// AdminConfigResponse es la respuesta de GET /admin/config
+type AdminConfigResponse struct {
+	Success bool `json:"success"`
+	
+	// Config usa como claves los nombres de las variables de entorno
+	Config map[string]interface{} `json:"config"`
+}
+
+// SettingsResponse es la respuesta de POST /admin/config/reload
+type SettingsResponse struct {
+	Success  bool         `json:"success"`
+	Settings SettingsInfo `json:"settings"`
+}
+
+// SettingsInfo son los ajustes recargables vigentes
+type SettingsInfo struct {
+	DefaultModel           string `json:"default_model" example:"llama-3.3-70b-versatile"`
+	HTTPTimeoutSeconds     int    `json:"http_timeout_seconds" example:"30"`
+	RateLimitRequests      int    `json:"rate_limit_requests" example:"60"`
+	RateLimitWindowSeconds int    `json:"rate_limit_window_seconds" example:"60"`
+}
+
+// CacheStatsResponse es la respuesta de GET /admin/cache
+type CacheStatsResponse struct {
+	Success bool           `json:"success"`
+	Cache   CacheStatsInfo `json:"cache"`
+}
+
+// CacheStatsInfo es el estado de la caché de respuestas
+type CacheStatsInfo struct {
+	Enabled bool `json:"enabled"`
+	Entries int  `json:"entries"`
+	
+	// Búsquedas desde el arranque de esta réplica
+	Hits     int64 `json:"hits"`
+	Misses   int64 `json:"misses"`
+	Bypasses int64 `json:"bypasses"`
+	
+	// HitRate es Hits / (Hits + Misses); los bypass no cuentan
+	HitRate float64 `json:"hit_rate" example:"0.35"`
+	
+	// Semantic es la caché semántica (ausente si está desactivada)
+	Semantic *SemanticCacheInfo `json:"semantic,omitempty"`
+}
+
+// SemanticCacheInfo es el estado de la caché semántica
+type SemanticCacheInfo struct {
+	Threshold float64 `json:"threshold" example:"0.95"`
+	Entries   int     `json:"entries"`
+	
+	// Búsquedas tras un MISS de la caché exacta desde el arranque de esta
+	// réplica; Errors son las que no se hicieron por fallo del embedding
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Errors int64 `json:"errors"`
+	
+	// HitRate es Hits / (Hits + Misses)
+	HitRate float64 `json:"hit_rate" example:"0.2"`
+}
+
+// CacheFlushResponse es la respuesta de POST /admin/cache/flush
+type CacheFlushResponse struct {
+	Success bool `json:"success"`
+	
+	// Flushed es el número de respuestas eliminadas
+	Flushed int `json:"flushed"`
+}
+
+// CircuitsResponse es la respuesta de GET /admin/circuits
+type CircuitsResponse struct {
+	Success  bool          `json:"success"`
+	Circuits []CircuitInfo `json:"circuits"`
+}
+
+// CircuitInfo es el estado del circuit breaker de un modelo
+type CircuitInfo struct {
+	Provider string `json:"provider" example:"groq"`
+	Model    string `json:"model" example:"llama-3.3-70b-versatile"`
+	
+	// State es "closed", "open" o "half_open"
+	State     string `json:"state" example:"closed"`
+	OpenUntil int64  `json:"open_until,omitempty"` // Unix timestamp
+}
+
+// InFlightResponse es la respuesta de GET /admin/in-flight
+type InFlightResponse struct {
+	Success bool `json:"success"`
+	
+	// InFlight son las peticiones a /api/v1 en curso en esta réplica
+	InFlight int64 `json:"in_flight"`
+}
+
+// BackendsHealthResponse es la respuesta de GET /admin/groq/keys
+type BackendsHealthResponse struct {
+	Success  bool          `json:"success"`
+	Backends []BackendInfo `json:"keys"`
+}
+
+// BackendInfo es el estado de una API key (o destino) desde el arranque
+type BackendInfo struct {
+	Name string `json:"name" example:"groq-2"`
+	
+	Requests    int64 `json:"requests"`
+	Failures    int64 `json:"failures"`
+	RateLimited int64 `json:"rate_limited"`
+	
+	// Available es false mientras está en enfriamiento (hasta CoolingUntil)
+	Available    bool   `json:"available"`
+	CoolingUntil int64  `json:"cooling_until,omitempty"` // Unix timestamp
+	LastError    string `json:"last_error,omitempty"`
+}
+
+// LivenessResponse es la respuesta de GET /admin/liveness
+type LivenessResponse struct {
+	Success bool `json:"success"`
+	
+	// Active son las peticiones y jobs vigilados en curso; Stuck, los que
+	// han pasado de su límite
+	Active int `json:"active"`
+	Stuck  int `json:"stuck"`
+	
+	// StuckTotal cuenta las tareas colgadas desde el arranque
+	StuckTotal int64 `json:"stuck_total"`
+}
+
+// RuntimeResponse es la respuesta de GET /admin/runtime
+type RuntimeResponse struct {
+	Success bool `json:"success"`
+	
+	GoVersion  string `json:"go_version" example:"go1.22.5"`
+	Goroutines int    `json:"goroutines" example:"42"`
+	GOMAXPROCS int    `json:"gomaxprocs" example:"4"`
+	NumCPU     int    `json:"num_cpu" example:"4"`
+	UptimeSec  int64  `json:"uptime_seconds"`
+	
+	Heap RuntimeHeapInfo `json:"heap"`
+	GC   RuntimeGCInfo   `json:"gc"`
+}
+
+// RuntimeHeapInfo es el uso de memoria del proceso, en bytes
+type RuntimeHeapInfo struct {
+	Alloc    uint64 `json:"alloc"`    // Objetos vivos (y aún no barridos)
+	InUse    uint64 `json:"in_use"`   // Spans con objetos
+	Idle     uint64 `json:"idle"`     // Spans sin objetos, aún no devueltos al SO
+	Released uint64 `json:"released"` // Devuelto al SO
+	Sys      uint64 `json:"sys"`      // Total pedido al SO (heap, stacks, runtime)
+	Objects  uint64 `json:"objects"`
+	
+	// TotalAlloc es lo asignado desde el arranque (crece siempre)
+	TotalAlloc uint64 `json:"total_alloc"`
+}
+
+// RuntimeGCInfo son las métricas del recolector de basura
+type RuntimeGCInfo struct {
+	NumGC       uint32  `json:"num_gc"`
+	NumForcedGC uint32  `json:"num_forced_gc"`
+	NextGC      uint64  `json:"next_gc"` // Tamaño del heap que dispara el siguiente ciclo
+	CPUFraction float64 `json:"cpu_fraction"`
+	
+	// Pausas en milisegundos: total desde el arranque y la del último ciclo
+	PauseTotalMs float64 `json:"pause_total_ms"`
+	LastPauseMs  float64 `json:"last_pause_ms"`
+	LastGC       int64   `json:"last_gc,omitempty"` // Unix timestamp (0 = ninguno)
+	
+	// GOGC y límite de memoria en bytes (0 = sin límite)
+	GCPercent   int   `json:"gc_percent" example:"100"`
+	MemoryLimit int64 `json:"memory_limit,omitempty"`
+}
+
+// APIKeyResponse es la respuesta de POST /admin/keys, /disable y /rotate
+type APIKeyResponse struct {
+	Success bool       `json:"success"`
+	Key     APIKeyInfo `json:"key"`
+	
+	// Secret es la clave que usa el cliente (Authorization: Bearer); solo
+	// aparece al crearla o rotarla
+	Secret string `json:"secret,omitempty" example:"gk_Jx3k9Qm2..."`
+}
+
+// APIKeysResponse es la respuesta de GET /admin/keys
+type APIKeysResponse struct {
+	Success bool         `json:"success"`
+	Keys    []APIKeyInfo `json:"keys"`
+}
+
+// APIKeyInfo es una API key sin su secreto ni su hash
+type APIKeyInfo struct {
+	ID     string   `json:"id" example:"9f86d081884c7d659a2feaa0c55ad015"`
+	Name   string   `json:"name" example:"app-movil"`
+	Prefix string   `json:"prefix" example:"gk_Jx3k9Qm"` // Primeros caracteres del secreto
+	Scopes []string `json:"scopes,omitempty" example:"chat"`
+	Tier   string   `json:"tier,omitempty" example:"pro"`
+	Tenant string   `json:"tenant,omitempty" example:"acme"`
+	
+	// Status es "active", "disabled" o "expired"
+	Status string `json:"status" example:"active"`
+	
+	// Unix timestamps; los opcionales solo cuando aplican
+	CreatedAt  int64 `json:"created_at"`
+	ExpiresAt  int64 `json:"expires_at,omitempty"`
+	DisabledAt int64 `json:"disabled_at,omitempty"`
+	RotatedAt  int64 `json:"rotated_at,omitempty"`
+	
+	// PreviousExpiresAt es hasta cuándo vale el secreto anterior a la rotación
+	PreviousExpiresAt int64 `json:"previous_expires_at,omitempty"`
+}
+
+// TokenResponse es la respuesta de POST /api/v1/token
+type TokenResponse struct {
+	Success bool `json:"success"`
+	
+	// Token se envía como "Authorization: Bearer <token>"
+	Token     string   `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
+	TokenType string   `json:"token_type" example:"Bearer"`
+	Scopes    []string `json:"scopes" example:"chat"`
+	
+	// ExpiresIn son los segundos que vale; ExpiresAt, Unix timestamp
+	ExpiresIn int64 `json:"expires_in" example:"900"`
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// APIKeyEventPayload es el body del webhook de caducidad de una API key
+type APIKeyEventPayload struct {
+	// Event es "api_key.expiring" o "api_key.expired"
+	Event string     `json:"event" example:"api_key.expiring"`
+	Key   APIKeyInfo `json:"key"`
+	
+	// ExpiresIn son los segundos que le quedan a la clave (0 = caducada)
+	ExpiresIn  int64 `json:"expires_in" example:"518400"`
+	OccurredAt int64 `json:"occurred_at"` // Unix timestamp
+}
+
+// RateLimitInfo es el margen de rate limit que anunció el proveedor
+type RateLimitInfo struct {
+	LimitRequests     int     `json:"limit_requests,omitempty"`
+	RemainingRequests int     `json:"remaining_requests"`
+	ResetRequestsSec  float64 `json:"reset_requests_seconds,omitempty"`
+	LimitTokens       int     `json:"limit_tokens,omitempty"`
+	RemainingTokens   int     `json:"remaining_tokens"`
+	ResetTokensSec    float64 `json:"reset_tokens_seconds,omitempty"`
+	ObservedAt        int64   `json:"observed_at"` // Unix timestamp
+}
+
+// ModelsResponse es el DTO para la lista de modelos
+type ModelsResponse struct {
+	Success bool          `json:"success"`
+	Models  []ModelInfo   `json:"models,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// ModelInfo contiene información sobre un modelo
+type ModelInfo struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	OwnedBy string `json:"owned_by"`
+}
+
+// ============================================================================
+// GENERIC RESPONSE DTOs
+// ============================================================================
+
+// ErrorResponse es una respuesta genérica de error
+type ErrorResponse struct {
+	Success bool   `json:"success"`
+	Error   string `json:"error"`
+	Code    int    `json:"code,omitempty"`
+
+	// Type identifica el tipo de error de forma estable (ej: "rate_limited")
+	// Los clientes deben usar Type, no el texto de Error, para decidir qué hacer
+	Type string `json:"type,omitempty"`
+
+	// RetryAfter son los segundos a esperar antes de reintentar (solo en 429)
+	RetryAfter int `json:"retry_after,omitempty"`
+
+	// Categories son las categorías de moderación infringidas (solo en
+	// content_flagged)
+	Categories []string `json:"categories,omitempty"`
+}
+
+// SuccessResponse es una respuesta genérica de éxito
+type SuccessResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// HealthResponse es la respuesta del endpoint de health check
+type HealthResponse struct {
+	Status    string `json:"status"`
+	Timestamp int64  `json:"timestamp"`
+	Service   string `json:"service"`
+}
+
+// CapabilitiesResponse es la respuesta de GET /api/v1/capabilities
+// Los SDKs la consultan para saber qué funciones tiene este despliegue
+type CapabilitiesResponse struct {
+	Success bool `json:"success"`
+	
+	// Providers son los proveedores configurados, con su modelo por defecto
+	Providers []ProviderCapability `json:"providers"`
+	
+	Features CapabilityFeatures `json:"features"`
+	Limits   CapabilityLimits   `json:"limits"`
+}
+
+// ProviderCapability es un proveedor configurado
+type ProviderCapability struct {
+	Name         string `json:"name" example:"groq"`
+	DefaultModel string `json:"default_model,omitempty" example:"llama-3.3-70b-versatile"`
+	
+	// Default indica si es el proveedor de las peticiones que no eligen uno
+	Default bool `json:"default"`
+}
+
+// CapabilityFeatures indica qué funciones opcionales están activas
+type CapabilityFeatures struct {
+	Streaming      bool `json:"streaming"`       // "stream": true en /chat
+	StreamResume   bool `json:"stream_resume"`   // Reanudar flujos con Last-Event-ID
+	Tools          bool `json:"tools"`           // Llamadas a funciones ("tools")
+	Vision         bool `json:"vision"`          // Imágenes en /chat ("images")
+	Grounding      bool `json:"grounding"`       // Verificación con fuentes ("verify")
+	RAG            bool `json:"rag"`             // Búsqueda en documentos propios (no disponible)
+	Audio          bool `json:"audio"`           // POST /api/v1/audio/transcriptions
+	Batch          bool `json:"batch"`           // Chats asíncronos (POST /api/v1/chat/async)
+	Conversations  bool `json:"conversations"`   // Conversaciones multi-turno
+	Prompts        bool `json:"prompts"`         // Mejora de prompts
+	Classification bool `json:"classification"`  // POST /api/v1/classify
+	Redaction      bool `json:"redaction"`       // POST /api/v1/redact
+	NL2SQL         bool `json:"nl2sql"`          // POST /api/v1/nl2sql
+	Code           bool `json:"code"`            // Explicación y revisión de código
+	Diff           bool `json:"diff"`            // POST /api/v1/diff
+	Proxy          bool `json:"proxy"`           // Modo proxy (formato OpenAI)
+	Usage          bool `json:"usage"`           // Consumo y cuotas de tokens
+	Feedback       bool `json:"feedback"`        // POST /api/v1/feedback
+	Tokens         bool `json:"tokens"`          // Tokens de vida corta (POST /api/v1/token)
+	Idempotency    bool `json:"idempotency"`     // Idempotency-Key en /chat
+	RequestTimeout bool `json:"request_timeout"` // X-Request-Timeout
+	APIKeyAuth     bool `json:"api_key_auth"`    // Las peticiones requieren API key
+}
+
+// CapabilityLimits son los límites de las peticiones (0 = sin límite)
+type CapabilityLimits struct {
+	MaxBodyBytes             int64 `json:"max_body_bytes" example:"33554432"`
+	MaxTools                 int   `json:"max_tools" example:"128"`
+	MaxRequestTimeoutSeconds int   `json:"max_request_timeout_seconds" example:"120"`
+}
+
+// ============================================================================
+// MÉTODOS DE VALIDACIÓN
+// ============================================================================
+
+// Validate valida el ChatRequest
+// Retorna error si algo está mal
+func (r *ChatRequest) Validate() error {
+	// Verificar que el mensaje no esté vacío
+	// Tras enviar resultados de herramientas (role "tool") puede omitirse
+	if r.Message == "" && !endsWithToolResult(r.History) {
+		return ErrEmptyMessage
+	}
+	
+	// Validar temperatura si está presente
+	if r.Temperature != nil {
+		temp := *r.Temperature
+		if temp < 0 || temp > 2 {
+			return ErrInvalidTemperature
+		}
+	}
+	
+	// Validar max_tokens si está presente
+	if r.MaxTokens < 0 {
+		return ErrInvalidMaxTokens
+	}
+	
+	// Validar el número de secuencias de parada
+	if len(r.Stop) > domain.MaxStopSequences {
+		return ErrTooManyStopSequences
+	}
+	
+	// Validar los parámetros de muestreo
+	if r.TopP != nil && (*r.TopP < 0 || *r.TopP > 1) {
+		return ErrInvalidTopP
+	}
+	if !validPenalty(r.FrequencyPenalty) {
+		return ErrInvalidFrequencyPenalty
+	}
+	if !validPenalty(r.PresencePenalty) {
+		return ErrInvalidPresencePenalty
+	}
+	if r.N < 0 || r.N > domain.MaxChoices {
+		return ErrInvalidN
+	}
+	// En streaming los fragmentos de varias respuestas llegarían mezclados
+	if r.N > 1 && r.Stream {
+		return ErrStreamMultipleChoices
+	}
+	
+	// Validar el nivel de calidad
+	if r.Quality != "" && domain.QualityRank(r.Quality) < 0 {
+		return ErrInvalidQuality
+	}
+	
+	// Validar la verificación: se hace con la respuesta completa
+	if r.Verify && r.Stream {
+		return ErrStreamVerify
+	}
+	if len(r.Sources) > 0 && !r.Verify {
+		return ErrSourcesWithoutVerify
+	}
+	if len(r.Sources) > domain.MaxGroundingSources {
+		return ErrTooManySources
+	}
+	for _, source := range r.Sources {
+		if len([]rune(source)) > domain.MaxGroundingSourceChars {
+			return ErrSourceTooLong
+		}
+	}
+	
+	// Validar los ejemplos y los documentos
+	if len(r.Examples) > domain.MaxPromptExamples {
+		return ErrTooManyExamples
+	}
+	for _, example := range r.Examples {
+		if example.Input == "" || example.Output == "" {
+			return ErrInvalidExample
+		}
+	}
+	if len(r.Documents) > domain.MaxPromptDocuments {
+		return ErrTooManyDocuments
+	}
+	for _, document := range r.Documents {
+		if len([]rune(document)) > domain.MaxPromptDocumentChars {
+			return ErrDocumentTooLong
+		}
+	}
+	
+	// Validar las herramientas
+	if len(r.Tools) > MaxTools {
+		return ErrTooManyTools
+	}
+	for _, tool := range r.Tools {
+		if tool.Type != "function" || tool.Function.Name == "" {
+			return ErrInvalidTool
+		}
+	}
+	
+	// Validar los roles del historial
+	for _, message := range r.History {
+		if !validHistoryRoles[message.Role] {
+			return ErrInvalidHistoryRole
+		}
+	}
+	
+	return nil
+}
+
+// MaxTools es el número máximo de herramientas por petición
+const MaxTools = 128
+
+// validPenalty indica si una penalización está en su rango (nil = no enviada)
+func validPenalty(penalty *float64) bool {
+	return penalty == nil || (*penalty >= domain.MinPenalty && *penalty <= domain.MaxPenalty)
+}
+
+// validHistoryRoles son los roles aceptados en el historial
+var validHistoryRoles = map[string]bool{
+	"system":    true,
+	"user":      true,
+	"assistant": true,
+	"tool":      true,
+}
+
+// endsWithToolResult indica si el último mensaje es el resultado de una herramienta
+func endsWithToolResult(history []MessageInfo) bool {
+	return len(history) > 0 && history[len(history)-1].Role == "tool"
+}
+
+// Validate valida el DiffRequest
+// Cada variante se valida con las reglas de ChatRequest
+func (r *DiffRequest) Validate() error {
+	for _, variant := range []DiffVariantRequest{r.A, r.B} {
+		chatRequest := ChatRequest{
+			Message:     r.Message,
+			Temperature: variant.Temperature,
+			MaxTokens:   r.MaxTokens,
+		}
+		if err := chatRequest.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// MAPEO DTO → DOMINIO
+// ============================================================================
+
+// toMessageOptions convierte los parámetros opcionales del DTO al dominio
+func (r *ChatRequest) toMessageOptions() domain.MessageOptions {
+	return domain.MessageOptions{
+		Temperature:  r.Temperature,
+		MaxTokens:    r.MaxTokens,
+		Stop:         r.Stop,
+		Sampling: domain.SamplingOptions{
+			TopP:             r.TopP,
+			FrequencyPenalty: r.FrequencyPenalty,
+			PresencePenalty:  r.PresencePenalty,
+			Seed:             r.Seed,
+			N:                r.N,
+		},
+		SystemPrompt: r.SystemPrompt,
+		Persona:      r.Persona,
+		Provider:     r.Provider,
+		Quality:      r.Quality,
+		Tools:        toDomainTools(r.Tools),
+		ToolChoice:   r.ToolChoice,
+		History:      toDomainMessages(r.History),
+		Images:       toDomainImages(r.Images),
+		Examples:     toDomainExamples(r.Examples),
+		Documents:    r.Documents,
+		Grounding:    r.toGroundingOptions(),
+	}
+}
+
+// toDomainExamples convierte los ejemplos few-shot del DTO al dominio
+func toDomainExamples(examples []FewShotExampleInfo) []domain.FewShotExample {
+	if len(examples) == 0 {
+		return nil
+	}
+	
+	result := make([]domain.FewShotExample, len(examples))
+	for i, example := range examples {
+		result[i] = domain.FewShotExample{Input: example.Input, Output: example.Output}
+	}
+	return result
+}
+
+// toGroundingOptions convierte verify y sources (nil si no se pidió verificar)
+func (r *ChatRequest) toGroundingOptions() *domain.GroundingOptions {
+	if !r.Verify {
+		return nil
+	}
+	return &domain.GroundingOptions{Sources: r.Sources}
+}
+
+// toDomainTools convierte las herramientas del DTO al dominio
+func toDomainTools(tools []ToolInfo) []domain.Tool {
+	if len(tools) == 0 {
+		return nil
+	}
+	
+	result := make([]domain.Tool, len(tools))
+	for i, tool := range tools {
+		result[i] = domain.Tool{
+			Type: tool.Type,
+			Function: domain.ToolFunction{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+			},
+		}
+	}
+	return result
+}
+
+// toDomainMessages convierte el historial del DTO al dominio
+func toDomainMessages(messages []MessageInfo) []domain.ChatMessage {
+	if len(messages) == 0 {
+		return nil
+	}
+	
+	result := make([]domain.ChatMessage, len(messages))
+	for i, message := range messages {
+		result[i] = domain.ChatMessage{
+			Role:       message.Role,
+			Content:    message.Content,
+			ToolCalls:  toDomainToolCalls(message.ToolCalls),
+			ToolCallID: message.ToolCallID,
+		}
+	}
+	return result
+}
+
+// toDomainToolCalls convierte las llamadas a herramientas del DTO al dominio
+func toDomainToolCalls(calls []ToolCallInfo) []domain.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+	
+	result := make([]domain.ToolCall, len(calls))
+	for i, call := range calls {
+		result[i] = domain.ToolCall{
+			Index: call.Index,
+			ID:    call.ID,
+			Type:  call.Type,
+			Function: domain.ToolCallFunction{
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			},
+		}
+	}
+	return result
+}
+
+// toMessageOptions convierte los parámetros opcionales del DTO al dominio
+func (r *ConversationMessageRequest) toMessageOptions() domain.MessageOptions {
+	return domain.MessageOptions{
+		Temperature:  r.Temperature,
+		MaxTokens:    r.MaxTokens,
+		Stop:         r.Stop,
+		SystemPrompt: r.SystemPrompt,
+	}
+}
+
+// toDomain convierte los ajustes del DTO al dominio
+func (r *ConversationSettingsRequest) toDomain() domain.ConversationSettings {
+	settings := domain.ConversationSettings{
+		Model:        r.Model,
+		Temperature:  r.Temperature,
+		SystemPrompt: r.SystemPrompt,
+	}
+	if r.Budget != nil {
+		settings.Budget = &domain.ConversationBudget{
+			MaxTotalTokens: r.Budget.MaxTotalTokens,
+			MaxCostUSD:     r.Budget.MaxCostUSD,
+			FallbackModel:  r.Budget.FallbackModel,
+		}
+	}
+	return settings
+}
+
+// toDomain convierte el DTO de la clave nueva al dominio
+func (r *CreateAPIKeyRequest) toDomain() domain.APIKeySpec {
+	spec := domain.APIKeySpec{
+		Name:   r.Name,
+		Scopes: r.Scopes,
+		Tier:   r.Tier,
+		Tenant: r.Tenant,
+	}
+	if r.ExpiresAt > 0 {
+		expiresAt := time.Unix(r.ExpiresAt, 0)
+		spec.ExpiresAt = &expiresAt
+	}
+	return spec
+}
+
+// toDomain convierte el DTO del token al dominio
+func (r *TokenRequest) toDomain() domain.TokenSpec {
+	return domain.TokenSpec{
+		Scopes: r.Scopes,
+		TTL:    time.Duration(r.ExpiresIn) * time.Second,
+	}
+}
+
+// expiresAt convierte la nueva caducidad al dominio (nil = se mantiene)
+func (r *RotateAPIKeyRequest) expiresAt() *time.Time {
+	if r.ExpiresAt <= 0 {
+		return nil
+	}
+	expiresAt := time.Unix(r.ExpiresAt, 0)
+	return &expiresAt
+}
+
+// Validate valida los ajustes de una conversación
+func (r *ConversationSettingsRequest) Validate() error {
+	if r.Temperature != nil && (*r.Temperature < 0 || *r.Temperature > 2) {
+		return ErrInvalidTemperature
+	}
+	if r.Budget != nil && (r.Budget.MaxTotalTokens < 0 || r.Budget.MaxCostUSD < 0) {
+		return ErrInvalidBudget
+	}
+	return nil
+}
+
+// toDomain convierte el DTO de comparación al dominio
+func (r *DiffRequest) toDomain() domain.DiffRequest {
+	return domain.DiffRequest{
+		Message:   r.Message,
+		MaxTokens: r.MaxTokens,
+		A:         r.A.toDomain(),
+		B:         r.B.toDomain(),
+	}
+}
+
+// toDomain convierte una variante del DTO al dominio
+func (v DiffVariantRequest) toDomain() domain.DiffVariant {
+	return domain.DiffVariant{
+		Model:        v.Model,
+		Temperature:  v.Temperature,
+		SystemPrompt: v.SystemPrompt,
+	}
+}
+
+// toDomain convierte el DTO de clasificación al dominio
+func (r *ClassifyRequest) toDomain() domain.ClassificationRequest {
+	labels := make([]domain.ClassificationLabel, 0, len(r.Labels))
+	for _, label := range r.Labels {
+		labels = append(labels, domain.ClassificationLabel{
+			Name:        label.Name,
+			Description: label.Description,
+			Examples:    label.Examples,
+		})
+	}
+	return domain.ClassificationRequest{
+		Text:   r.Text,
+		Labels: labels,
+		Model:  r.Model,
+	}
+}
+
+// toDomain convierte el DTO de NL2SQL al dominio
+func (r *NL2SQLRequest) toDomain() domain.NL2SQLRequest {
+	return domain.NL2SQLRequest{
+		Question:       r.Question,
+		Schema:         r.Schema,
+		Dialect:        r.Dialect,
+		AllowMutations: r.AllowMutations,
+		Model:          r.Model,
+	}
+}
+
+// toDomain convierte el DTO de código al dominio
+func (r *CodeRequest) toDomain() domain.CodeRequest {
+	return domain.CodeRequest{
+		Code:         r.Code,
+		Filename:     r.Filename,
+		Language:     r.Language,
+		Instructions: r.Instructions,
+		Model:        r.Model,
+	}
+}
+
+// Validate valida el ConversationMessageRequest
+// Reutiliza las reglas de ChatRequest para los campos comunes
+func (r *ConversationMessageRequest) Validate() error {
+	chatRequest := ChatRequest{
+		Message:     r.Message,
+		Temperature: r.Temperature,
+		MaxTokens:   r.MaxTokens,
+		Stop:        r.Stop,
+	}
+	return chatRequest.Validate()
+}
+
+// ============================================================================
+// ERRORES DE VALIDACIÓN
+// ============================================================================
+
+// Definimos errores personalizados para validación
+// Estos son específicos de la capa HTTP
+var (
+	ErrEmptyMessage            = NewValidationError("el mensaje no puede estar vacío")
+	ErrInvalidTemperature      = NewValidationError("la temperatura debe estar entre 0 y 2")
+	ErrInvalidMaxTokens        = NewValidationError("max_tokens debe ser mayor o igual a 0")
+	ErrTooManyStopSequences    = NewValidationError("stop admite como máximo 4 secuencias")
+	ErrInvalidTopP             = NewValidationError("top_p debe estar entre 0 y 1")
+	ErrInvalidFrequencyPenalty = NewValidationError("frequency_penalty debe estar entre -2 y 2")
+	ErrInvalidPresencePenalty  = NewValidationError("presence_penalty debe estar entre -2 y 2")
+	ErrInvalidN                = NewValidationError("n debe estar entre 1 y 128")
+	ErrStreamMultipleChoices   = NewValidationError("n mayor que 1 no se admite con stream")
+	ErrTooManyTools            = NewValidationError("tools admite como máximo 128 herramientas")
+	ErrInvalidTool             = NewValidationError("cada tool debe tener type \"function\" y un function.name")
+	ErrInvalidHistoryRole      = NewValidationError("los roles del historial deben ser system, user, assistant o tool")
+	ErrStreamVerify            = NewValidationError("verify no se admite con stream")
+	ErrSourcesWithoutVerify    = NewValidationError("sources requiere verify: true")
+	ErrTooManySources          = NewValidationError("sources admite como máximo 20 textos")
+	ErrSourceTooLong           = NewValidationError("cada texto de sources admite como máximo 20000 caracteres")
+	ErrTooManyExamples         = NewValidationError("examples admite como máximo 10 ejemplos")
+	ErrInvalidExample          = NewValidationError("cada ejemplo de examples debe tener input y output")
+	ErrTooManyDocuments        = NewValidationError("documents admite como máximo 20 textos")
+	ErrDocumentTooLong         = NewValidationError("cada texto de documents admite como máximo 20000 caracteres")
+	ErrInvalidQuality          = NewValidationError("quality debe ser fast, balanced o best")
+	ErrInvalidBudget           = NewValidationError("los límites de budget deben ser mayores o iguales a 0")
+)
+
+// ValidationError es un tipo de error personalizado para validaciones
+type ValidationError struct {
+	Message string
+}
+
+// Error implementa la interfaz error
+// Este método es necesario para que ValidationError sea un error
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// NewValidationError crea un nuevo error de validación
+func NewValidationError(message string) *ValidationError {
+	return &ValidationError{Message: message}
+}
+
+// ============================================================================
+// FACTORY FUNCTIONS (funciones para crear DTOs)
+// ============================================================================
+
+// NewTranscriptionResponse convierte una transcripción del dominio a DTO
+func NewTranscriptionResponse(transcription *domain.Transcription) *TranscriptionResponse {
+	segments := make([]TranscriptionSegmentInfo, len(transcription.Segments))
+	for i, segment := range transcription.Segments {
+		segments[i] = TranscriptionSegmentInfo{
+			Start: segment.Start,
+			End:   segment.End,
+			Text:  segment.Text,
+		}
+	}
+	
+	return &TranscriptionResponse{
+		Success:  true,
+		Text:     transcription.Text,
+		Language: transcription.Language,
+		Duration: transcription.Duration,
+		Model:    transcription.Model,
+		Segments: segments,
+	}
+}
+
+// NewChatResponse crea una respuesta de chat exitosa
+func NewChatResponse(message, model string, usage *UsageInfo) *ChatResponse {
+	return &ChatResponse{
+		Success: true,
+		Message: message,
+		Model:   model,
+		Usage:   usage,
+	}
+}
+
+// NewChatResponseFromDomain convierte la respuesta del modelo al DTO de /chat
+func NewChatResponseFromDomain(response *domain.ChatResponse) *ChatResponse {
+	chatResponse := NewChatResponse(
+		response.GetResponseContent(),
+		response.Model,
+		&UsageInfo{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+			CostUSD:          response.Usage.CostUSD,
+		},
+	)
+	chatResponse.TruncatedByPolicy = response.Meta.TruncatedByPolicy
+	chatResponse.DetectedLanguage = response.Meta.DetectedLanguage
+	chatResponse.RemappedFrom = response.Meta.RemappedFrom
+	chatResponse.Quality = response.Meta.RoutedQuality
+	chatResponse.ToolCalls = NewToolCallInfos(response.GetToolCalls())
+	chatResponse.FinishReason = response.GetFinishReason()
+	if len(response.Choices) > 1 {
+		for _, choice := range response.Choices {
+			chatResponse.Choices = append(chatResponse.Choices, choice.Message.Content)
+		}
+	}
+	chatResponse.Grounding = NewGroundingInfo(response.Meta.Grounding)
+	if summary := response.Meta.HistorySummary; summary != nil {
+		chatResponse.HistorySummary = &HistorySummaryInfo{
+			Content:  summary.Content,
+			Messages: summary.Messages,
+			Model:    summary.Model,
+			Usage:    NewUsageInfo(summary.Usage),
+		}
+	}
+	chatResponse.Warnings = NewWarningInfos(response.Meta.Warnings)
+	return chatResponse
+}
+
+// NewGroundingInfo convierte la verificación al DTO (nil si no la hay)
+func NewGroundingInfo(grounding *domain.Grounding) *GroundingInfo {
+	if grounding == nil {
+		return nil
+	}
+
+	claims := make([]UnsupportedClaimInfo, 0, len(grounding.UnsupportedClaims))
+	for _, claim := range grounding.UnsupportedClaims {
+		claims = append(claims, UnsupportedClaimInfo{Claim: claim.Claim, Reason: claim.Reason})
+	}
+	return &GroundingInfo{
+		Score:             grounding.Score,
+		UnsupportedClaims: claims,
+		WithSources:       grounding.WithSources,
+		Model:             grounding.Model,
+		Usage:             NewUsageInfo(grounding.Usage),
+	}
+}
+
+// NewDryRunResponse convierte el resultado de un dry run al DTO
+func NewDryRunResponse(result *domain.DryRunResult) *DryRunResponse {
+	response := &DryRunResponse{
+		Success:               true,
+		DryRun:                true,
+		Model:                 result.Request.Model,
+		RemappedFrom:          result.Meta.RemappedFrom,
+		Request:               result.Request,
+		EstimatedPromptTokens: result.EstimatedPromptTokens,
+		DetectedLanguage:      result.Meta.DetectedLanguage,
+		Warnings:              NewWarningInfos(result.Meta.Warnings),
+	}
+	if result.Cost != nil {
+		response.EstimatedCost = &CostEstimateInfo{
+			Currency:      "USD",
+			Prompt:        result.Cost.Prompt,
+			MaxCompletion: result.Cost.MaxCompletion,
+		}
+	}
+	response.ContextWindow = result.ContextWindow
+	for _, section := range result.Sections {
+		response.PromptSections = append(response.PromptSections, PromptSectionInfo{
+			Name:            section.Name,
+			Start:           section.Start,
+			End:             section.End,
+			EstimatedTokens: section.EstimatedTokens,
+		})
+	}
+	return response
+}
+
+// NewWarningInfos convierte los avisos del dominio (nil si no hay ninguno)
+func NewWarningInfos(warnings []domain.Warning) []WarningInfo {
+	if len(warnings) == 0 {
+		return nil
+	}
+	infos := make([]WarningInfo, len(warnings))
+	for i, warning := range warnings {
+		infos[i] = WarningInfo{Code: warning.Code, Message: warning.Message}
+	}
+	return infos
+}
+
+// NewChatErrorResponse crea una respuesta de error de chat
+func NewChatErrorResponse(errorMsg string) *ChatResponse {
+	return &ChatResponse{
+		Success: false,
+		Error:   errorMsg,
+	}
+}
+
+// NewUsageInfo convierte el uso de tokens del dominio a DTO
+func NewUsageInfo(usage domain.Usage) *UsageInfo {
+	return &UsageInfo{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		CostUSD:          usage.CostUSD,
+	}
+}
+
+// NewToolCallInfos convierte las llamadas a herramientas del dominio a DTO
+func NewToolCallInfos(calls []domain.ToolCall) []ToolCallInfo {
+	if len(calls) == 0 {
+		return nil
+	}
+	
+	result := make([]ToolCallInfo, len(calls))
+	for i, call := range calls {
+		result[i] = ToolCallInfo{
+			Index: call.Index,
+			ID:    call.ID,
+			Type:  call.Type,
+			Function: ToolCallFunctionInfo{
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			},
+		}
+	}
+	return result
+}
+
+// NewConversationInfo convierte una conversación del dominio a DTO
+func NewConversationInfo(conversation *domain.Conversation) *ConversationInfo {
+	messages := make([]MessageInfo, len(conversation.Messages))
+	for i, message := range conversation.Messages {
+		messages[i] = MessageInfo{
+			Role:       message.Role,
+			Content:    message.Content,
+			ToolCalls:  NewToolCallInfos(message.ToolCalls),
+			ToolCallID: message.ToolCallID,
+			CreatedAt:  message.CreatedAt.Unix(),
+		}
+		if turn := message.Turn; turn != nil {
+			messages[i].Turn = &TurnInfo{
+				Model:        turn.Model,
+				LatencyMs:    turn.Latency.Milliseconds(),
+				Usage:        NewUsageInfo(turn.Usage),
+				FinishReason: turn.FinishReason,
+				CacheHit:     turn.CacheHit,
+			}
+		}
+	}
+	
+	info := &ConversationInfo{
+		ID:           conversation.ID,
+		Model:        conversation.Model,
+		Temperature:  conversation.Temperature,
+		SystemPrompt: conversation.SystemPrompt,
+		Spent:        NewUsageInfo(conversation.Spent()),
+		Messages:     messages,
+		CreatedAt: conversation.CreatedAt.Unix(),
+		UpdatedAt: conversation.UpdatedAt.Unix(),
+	}
+	if budget := conversation.Budget; budget != nil {
+		info.Budget = &ConversationBudgetInfo{
+			MaxTotalTokens: budget.MaxTotalTokens,
+			MaxCostUSD:     budget.MaxCostUSD,
+			FallbackModel:  budget.FallbackModel,
+		}
+	}
+	if summary := conversation.Summary; summary != nil {
+		info.Summary = &ConversationSummaryInfo{
+			Content:   summary.Content,
+			Messages:  summary.Messages,
+			Model:     summary.Model,
+			Usage:     NewUsageInfo(summary.Usage),
+			CreatedAt: summary.CreatedAt.Unix(),
+		}
+	}
+	if conversation.IsDeleted() {
+		info.DeletedAt = conversation.DeletedAt.Unix()
+		info.PurgeAt = conversation.PurgeAt.Unix()
+	}
+	return info
+}
+
+// NewConversationResponse crea una respuesta exitosa con la conversación
+func NewConversationResponse(conversation *domain.Conversation) *ConversationResponse {
+	return &ConversationResponse{
+		Success:      true,
+		Conversation: NewConversationInfo(conversation),
+	}
+}
+
+// NewDiffResponse convierte el resultado de la comparación a DTO
+func NewDiffResponse(result *domain.DiffResult) *DiffResponse {
+	ops := make([]DiffOpInfo, len(result.Ops))
+	for i, op := range result.Ops {
+		ops[i] = DiffOpInfo{Op: op.Op, Text: op.Text}
+	}
+	
+	return &DiffResponse{
+		Success:    true,
+		A:          newDiffVariantInfo(result.A),
+		B:          newDiffVariantInfo(result.B),
+		Similarity: result.Similarity,
+		Diff:       ops,
+	}
+}
+
+// newDiffVariantInfo convierte la respuesta de una variante a DTO
+func newDiffVariantInfo(response *domain.ChatResponse) DiffVariantInfo {
+	return DiffVariantInfo{
+		Message: response.GetResponseContent(),
+		Model:   response.Model,
+		Usage:   NewUsageInfo(response.Usage),
+	}
+}
+
+// NewModelsHealthResponse convierte la salud de los modelos a DTO
+func NewModelsHealthResponse(models []domain.ModelHealth) *ModelsHealthResponse {
+	infos := make([]ModelHealthInfo, len(models))
+	for i, model := range models {
+		infos[i] = ModelHealthInfo{
+			Provider:     model.Provider,
+			Model:        model.Model,
+			Requests:     model.Requests,
+			Failures:     model.Failures,
+			RateLimited:  model.RateLimited,
+			ErrorRate:    model.ErrorRate,
+			P95LatencyMs: model.P95Latency.Milliseconds(),
+			Circuit:      string(model.Circuit),
+			RateLimit:    newRateLimitInfo(model.RateLimit),
+		}
+		if !model.CircuitOpenUntil.IsZero() {
+			infos[i].CircuitOpenUntil = model.CircuitOpenUntil.Unix()
+		}
+	}
+	
+	return &ModelsHealthResponse{
+		Success: true,
+		Models:  infos,
+	}
+}
+
+// NewRedactResponse convierte el resultado de la anonimización a DTO
+func NewRedactResponse(result *domain.RedactionResult) *RedactResponse {
+	entities := make([]RedactedEntityInfo, 0, len(result.Entities))
+	for _, entity := range result.Entities {
+		entities = append(entities, RedactedEntityInfo{
+			Type:        entity.Type,
+			Text:        entity.Text,
+			Placeholder: entity.Placeholder,
+		})
+	}
+	return &RedactResponse{
+		Success:  true,
+		Text:     result.Text,
+		Entities: entities,
+	}
+}
+
+// NewJobResponse convierte el job a DTO
+func NewJobResponse(job *domain.Job) *JobResponse {
+	response := &JobResponse{
+		Success:   true,
+		ID:        job.ID,
+		Status:    string(job.Status),
+		Model:     job.Model,
+		JobError:  job.Error,
+		CreatedAt: job.CreatedAt.Unix(),
+		ExpiresAt: job.ExpiresAt.Unix(),
+		
+		JobErrorType: job.ErrorType,
+	}
+	if job.Progress != nil {
+		response.Progress = &JobProgressInfo{
+			Tokens:    job.Progress.Tokens,
+			Percent:   job.Progress.Percent,
+			UpdatedAt: job.Progress.UpdatedAt.Unix(),
+		}
+	}
+	if job.Result != nil {
+		response.Result = NewChatResponseFromDomain(job.Result)
+	}
+	if job.ResultRef != nil {
+		response.ResultURL = "/api/v1/jobs/" + job.ID + "/result"
+		response.ResultSize = job.ResultRef.Size
+	}
+	if job.StartedAt != nil {
+		response.StartedAt = job.StartedAt.Unix()
+	}
+	if job.FinishedAt != nil {
+		response.FinishedAt = job.FinishedAt.Unix()
+	}
+	if job.Callback != nil {
+		response.Callback = &JobCallbackInfo{
+			URL:       job.Callback.URL,
+			Status:    string(job.Callback.Status),
+			Attempts:  job.Callback.Attempts,
+			LastError: job.Callback.LastError,
+		}
+	}
+	return response
+}
+
+// NewAPIKeyInfo convierte la clave a DTO (sin el hash)
+func NewAPIKeyInfo(key *domain.APIKey, now time.Time) APIKeyInfo {
+	info := APIKeyInfo{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		Tier:      key.Tier,
+		Tenant:    key.Tenant,
+		Status:    "active",
+		CreatedAt: key.CreatedAt.Unix(),
+	}
+	if key.ExpiresAt != nil {
+		info.ExpiresAt = key.ExpiresAt.Unix()
+		if !now.Before(*key.ExpiresAt) {
+			info.Status = "expired"
+		}
+	}
+	if key.DisabledAt != nil {
+		info.DisabledAt = key.DisabledAt.Unix()
+		info.Status = "disabled"
+	}
+	if key.RotatedAt != nil {
+		info.RotatedAt = key.RotatedAt.Unix()
+	}
+	if key.PreviousExpiresAt != nil && now.Before(*key.PreviousExpiresAt) {
+		info.PreviousExpiresAt = key.PreviousExpiresAt.Unix()
+	}
+	return info
+}
+
+// NewTokenResponse convierte un token emitido a DTO
+func NewTokenResponse(token *domain.ScopedToken) *TokenResponse {
+	return &TokenResponse{
+		Success:   true,
+		Token:     token.Token,
+		TokenType: "Bearer",
+		Scopes:    token.Scopes,
+		ExpiresIn: int64(token.ExpiresAt.Sub(token.IssuedAt).Seconds()),
+		ExpiresAt: token.ExpiresAt.Unix(),
+	}
+}
+
+// NewAPIKeyEventPayload convierte un evento de caducidad al body del webhook
+func NewAPIKeyEventPayload(event domain.APIKeyEvent) *APIKeyEventPayload {
+	payload := &APIKeyEventPayload{
+		Event:      event.Type,
+		Key:        NewAPIKeyInfo(&event.Key, event.At),
+		OccurredAt: event.At.Unix(),
+	}
+	if event.Key.ExpiresAt != nil {
+		payload.ExpiresIn = max(int64(event.Key.ExpiresAt.Sub(event.At).Seconds()), 0)
+	}
+	return payload
+}
+
+// NewNL2SQLResponse convierte la consulta generada a DTO
+func NewNL2SQLResponse(result *domain.NL2SQLResult) *NL2SQLResponse {
+	return &NL2SQLResponse{
+		Success:       true,
+		SQL:           result.SQL,
+		Explanation:   result.Explanation,
+		StatementType: result.Analysis.StatementType,
+		Mutating:      result.Analysis.Mutating,
+		Tables:        result.Analysis.Tables,
+		Model:         result.Model,
+		Usage:         NewUsageInfo(result.Usage),
+	}
+}
+
+// NewCodeExplainResponse convierte la explicación a DTO
+func NewCodeExplainResponse(result *domain.CodeExplanation) *CodeExplainResponse {
+	sections := make([]CodeSectionInfo, 0, len(result.Sections))
+	for _, section := range result.Sections {
+		sections = append(sections, CodeSectionInfo{
+			StartLine:   section.StartLine,
+			EndLine:     section.EndLine,
+			Explanation: section.Explanation,
+		})
+	}
+
+	return &CodeExplainResponse{
+		Success:  true,
+		Language: result.Language,
+		Summary:  result.Summary,
+		Sections: sections,
+		Model:    result.Model,
+		Usage:    NewUsageInfo(result.Usage),
+	}
+}
+
+// NewCodeReviewResponse convierte la revisión a DTO
+func NewCodeReviewResponse(result *domain.CodeReview) *CodeReviewResponse {
+	findings := make([]CodeFindingInfo, 0, len(result.Findings))
+	for _, finding := range result.Findings {
+		findings = append(findings, CodeFindingInfo{
+			Severity:    finding.Severity,
+			Category:    finding.Category,
+			Line:        finding.Line,
+			EndLine:     finding.EndLine,
+			Title:       finding.Title,
+			Description: finding.Description,
+			Suggestion:  finding.Suggestion,
+		})
+	}
+
+	return &CodeReviewResponse{
+		Success:  true,
+		Language: result.Language,
+		Summary:  result.Summary,
+		Findings: findings,
+		Chunks:   result.Chunks,
+		Model:    result.Model,
+		Usage:    NewUsageInfo(result.Usage),
+	}
+}
+
+// NewUsageResponse convierte el informe de consumo a DTO
+func NewUsageResponse(report *domain.UsageReport) *UsageResponse {
+	history := make([]DailyUsageInfo, 0, len(report.History))
+	for _, day := range report.History {
+		history = append(history, DailyUsageInfo{
+			Day:   day.Day,
+			Usage: *NewUsageInfo(day.Usage),
+		})
+	}
+	return &UsageResponse{
+		Success:   true,
+		Client:    report.Client,
+		Today:     *NewUsageInfo(report.Today),
+		Quota:     report.Quota,
+		Remaining: report.Remaining,
+		ResetAt:   report.ResetAt.Unix(),
+		History:   history,
+		CostUSD:   report.CostUSD,
+	}
+}
+
+// NewStatementResponse convierte el extracto de un tenant a DTO
+func NewStatementResponse(statement *domain.TenantStatement) *StatementResponse {
+	models := make([]StatementModelInfo, 0, len(statement.Models))
+	for _, model := range statement.Models {
+		models = append(models, StatementModelInfo{
+			Model:    model.Model,
+			Requests: model.Requests,
+			Usage:    *NewUsageInfo(model.Usage),
+		})
+	}
+	conversations := make([]StatementConversationInfo, 0, len(statement.TopConversations))
+	for _, conversation := range statement.TopConversations {
+		conversations = append(conversations, StatementConversationInfo{
+			ConversationID: conversation.ConversationID,
+			Requests:       conversation.Requests,
+			Usage:          *NewUsageInfo(conversation.Usage),
+		})
+	}
+	return &StatementResponse{
+		Success:          true,
+		Tenant:           statement.Tenant,
+		Month:            statement.Month,
+		PeriodStart:      statement.PeriodStart.Unix(),
+		PeriodEnd:        statement.PeriodEnd.Unix(),
+		Provisional:      statement.Provisional,
+		Requests:         statement.Requests,
+		Usage:            *NewUsageInfo(statement.Usage),
+		Models:           models,
+		TopConversations: conversations,
+		GeneratedAt:      statement.GeneratedAt.Unix(),
+	}
+}
+
+// NewFeedbackInfo convierte una valoración a DTO
+func NewFeedbackInfo(feedback *domain.Feedback) FeedbackInfo {
+	return FeedbackInfo{
+		ID:             feedback.ID,
+		ResponseID:     feedback.ResponseID,
+		ConversationID: feedback.ConversationID,
+		Rating:         feedback.Rating,
+		Comment:        feedback.Comment,
+		Model:          feedback.Model,
+		Persona:        feedback.Persona,
+		Tenant:         feedback.Tenant,
+		CreatedAt:      feedback.CreatedAt.Unix(),
+	}
+}
+
+// NewFeedbackSummaryResponse convierte el resumen de las valoraciones a DTO
+func NewFeedbackSummaryResponse(summary *domain.FeedbackSummary) *FeedbackSummaryResponse {
+	comments := make([]FeedbackInfo, 0, len(summary.RecentComments))
+	for i := range summary.RecentComments {
+		comments = append(comments, NewFeedbackInfo(&summary.RecentComments[i]))
+	}
+	return &FeedbackSummaryResponse{
+		Success:        true,
+		Since:          summary.Filter.Since.Unix(),
+		Tenant:         summary.Filter.Tenant,
+		Total:          newFeedbackCountInfo("", summary.Total),
+		ByModel:        newFeedbackCountInfos(summary.ByModel),
+		ByPersona:      newFeedbackCountInfos(summary.ByPersona),
+		RecentComments: comments,
+	}
+}
+
+// newFeedbackCountInfo convierte una cuenta de valoraciones
+func newFeedbackCountInfo(name string, count domain.FeedbackCount) FeedbackCountInfo {
+	return FeedbackCountInfo{Name: name, Up: count.Up, Down: count.Down, Score: count.Score()}
+}
+
+// newFeedbackCountInfos convierte las cuentas por modelo o persona
+func newFeedbackCountInfos(groups []domain.GroupedFeedback) []FeedbackCountInfo {
+	infos := make([]FeedbackCountInfo, 0, len(groups))
+	for _, group := range groups {
+		infos = append(infos, newFeedbackCountInfo(group.Name, group.FeedbackCount))
+	}
+	return infos
+}
+
+// NewSettingsResponse convierte los ajustes vigentes a DTO
+func NewSettingsResponse(settings domain.RuntimeSettings) *SettingsResponse {
+	return &SettingsResponse{
+		Success: true,
+		Settings: SettingsInfo{
+			DefaultModel:           settings.DefaultModel,
+			HTTPTimeoutSeconds:     int(settings.HTTPTimeout.Seconds()),
+			RateLimitRequests:      settings.RateLimitRequests,
+			RateLimitWindowSeconds: int(settings.RateLimitWindow.Seconds()),
+		},
+	}
+}
+
+// NewCacheStatsResponse convierte las estadísticas de la caché a DTO
+func NewCacheStatsResponse(stats domain.ResponseCacheStats) *CacheStatsResponse {
+	info := CacheStatsInfo{
+		Enabled:  stats.Enabled,
+		Entries:  stats.Entries,
+		Hits:     stats.Hits,
+		Misses:   stats.Misses,
+		Bypasses: stats.Bypasses,
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		info.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	if semantic := stats.Semantic; semantic != nil {
+		info.Semantic = &SemanticCacheInfo{
+			Threshold: semantic.Threshold,
+			Entries:   semantic.Entries,
+			Hits:      semantic.Hits,
+			Misses:    semantic.Misses,
+			Errors:    semantic.Errors,
+		}
+		if lookups := semantic.Hits + semantic.Misses; lookups > 0 {
+			info.Semantic.HitRate = float64(semantic.Hits) / float64(lookups)
+		}
+	}
+	
+	return &CacheStatsResponse{
+		Success: true,
+		Cache:   info,
+	}
+}
+
+// NewRuntimeResponse convierte las estadísticas del runtime a DTO
+func NewRuntimeResponse(stats *runtime.MemStats, uptime time.Duration) *RuntimeResponse {
+	gcPercent, memoryLimit := readGCSettings()
+	response := &RuntimeResponse{
+		Success:    true,
+		GoVersion:  runtime.Version(),
+		Goroutines: runtime.NumGoroutine(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		NumCPU:     runtime.NumCPU(),
+		UptimeSec:  int64(uptime.Seconds()),
+		Heap: RuntimeHeapInfo{
+			Alloc:      stats.HeapAlloc,
+			InUse:      stats.HeapInuse,
+			Idle:       stats.HeapIdle,
+			Released:   stats.HeapReleased,
+			Sys:        stats.Sys,
+			Objects:    stats.HeapObjects,
+			TotalAlloc: stats.TotalAlloc,
+		},
+		GC: RuntimeGCInfo{
+			NumGC:        stats.NumGC,
+			NumForcedGC:  stats.NumForcedGC,
+			NextGC:       stats.NextGC,
+			CPUFraction:  stats.GCCPUFraction,
+			PauseTotalMs: float64(stats.PauseTotalNs) / 1e6,
+			GCPercent:    gcPercent,
+			MemoryLimit:  memoryLimit,
+		},
+	}
+	if stats.NumGC > 0 {
+		// PauseNs es un buffer circular: la última pausa está en (NumGC+255)%256
+		response.GC.LastPauseMs = float64(stats.PauseNs[(stats.NumGC+255)%256]) / 1e6
+		response.GC.LastGC = time.Unix(0, int64(stats.LastGC)).Unix()
+	}
+	return response
+}
+
+// NewCircuitsResponse extrae el estado de los circuitos de la salud de los modelos
+func NewCircuitsResponse(models []domain.ModelHealth) *CircuitsResponse {
+	circuits := make([]CircuitInfo, len(models))
+	for i, model := range models {
+		circuits[i] = CircuitInfo{
+			Provider: model.Provider,
+			Model:    model.Model,
+			State:    string(model.Circuit),
+		}
+		if !model.CircuitOpenUntil.IsZero() {
+			circuits[i].OpenUntil = model.CircuitOpenUntil.Unix()
+		}
+	}
+	
+	return &CircuitsResponse{
+		Success:  true,
+		Circuits: circuits,
+	}
+}
+
+// NewBackendsHealthResponse convierte el estado de los destinos a DTO
+func NewBackendsHealthResponse(backends []domain.BackendHealth) *BackendsHealthResponse {
+	infos := make([]BackendInfo, len(backends))
+	for i, backend := range backends {
+		infos[i] = BackendInfo{
+			Name:        backend.Name,
+			Requests:    backend.Requests,
+			Failures:    backend.Failures,
+			RateLimited: backend.RateLimited,
+			Available:   backend.CoolingUntil.IsZero(),
+			LastError:   backend.LastError,
+		}
+		if !backend.CoolingUntil.IsZero() {
+			infos[i].CoolingUntil = backend.CoolingUntil.Unix()
+		}
+	}
+	
+	return &BackendsHealthResponse{
+		Success:  true,
+		Backends: infos,
+	}
+}
+
+// newRateLimitInfo convierte el margen de rate limit a DTO (nil si no hay)
+func newRateLimitInfo(status *domain.RateLimitStatus) *RateLimitInfo {
+	if status == nil {
+		return nil
+	}
+	return &RateLimitInfo{
+		LimitRequests:     status.LimitRequests,
+		RemainingRequests: status.RemainingRequests,
+		ResetRequestsSec:  status.ResetRequests.Seconds(),
+		LimitTokens:       status.LimitTokens,
+		RemainingTokens:   status.RemainingTokens,
+		ResetTokensSec:    status.ResetTokens.Seconds(),
+		ObservedAt:        status.ObservedAt.Unix(),
 	}
 }
 
@@ -0,0 +1,150 @@
+// Package http - Traducción de errores del dominio a respuestas HTTP
+package http
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// ============================================================================
+// ERRORES DEL SERVICIO → HTTP
+// ============================================================================
+//
+// Los servicios devuelven errores del dominio (domain.ErrRateLimited...)
+// envueltos con %w. Aquí se decide, en un solo sitio, el status HTTP y el
+// "type" que ve el cliente. Así todos los handlers responden igual al mismo
+// fallo (ej: un 429 de Groq es un 429 en /chat, /diff o /conversations).
+//
+//   {"success": false, "error": "...", "code": 429, "type": "rate_limited", "retry_after": 12}
+// ============================================================================
+
+// serviceErrorMapping asocia un error del dominio con su respuesta HTTP
+type serviceErrorMapping struct {
+	target  error
+	status  int
+	errType string
+
+	// expose indica si el mensaje del error se puede mostrar al cliente
+	// Los fallos del servidor (credenciales, 5xx) usan un mensaje genérico
+	expose bool
+}
+
+// serviceErrorMappings se recorre en orden: el primero que coincide gana
+var serviceErrorMappings = []serviceErrorMapping{
+	// Errores de entrada
+	{domain.ErrEmptyMessage, http.StatusBadRequest, "invalid_request", true},
+	{domain.ErrEmptyModel, http.StatusBadRequest, "invalid_request", true},
+	{domain.ErrPersonaNotFound, http.StatusNotFound, "persona_not_found", true},
+	{domain.ErrUnknownProvider, http.StatusBadRequest, "unknown_provider", true},
+	{domain.ErrNoModelForQuality, http.StatusBadRequest, "no_model_for_quality", true},
+	{domain.ErrParameterOutOfRange, http.StatusBadRequest, "parameter_out_of_range", true},
+	{domain.ErrRequestRejected, http.StatusForbidden, "request_rejected", true},
+	// 422: la petición es válida pero su contenido no se acepta
+	{domain.ErrContentFlagged, http.StatusUnprocessableEntity, "content_flagged", true},
+
+	// API keys de los clientes
+	{domain.ErrInvalidAPIKey, http.StatusUnauthorized, "unauthorized", true},
+	{domain.ErrInsufficientScope, http.StatusForbidden, "insufficient_scope", true},
+	{domain.ErrTenantMismatch, http.StatusForbidden, "tenant_mismatch", true},
+	{domain.ErrAPIKeyNotFound, http.StatusNotFound, "not_found", true},
+	{domain.ErrInvalidAPIKeySpec, http.StatusBadRequest, "invalid_request", true},
+	{domain.ErrAPIKeyDisabled, http.StatusConflict, "conflict", true},
+	{domain.ErrInvalidTokenSpec, http.StatusBadRequest, "invalid_request", true},
+
+	// Idempotency-Key
+	{domain.ErrInvalidIdempotencyKey, http.StatusBadRequest, "invalid_request", true},
+	{domain.ErrIdempotencyKeyReused, http.StatusUnprocessableEntity, "idempotency_key_reused", true},
+	{domain.ErrIdempotencyInProgress, http.StatusConflict, "idempotency_in_progress", true},
+
+	// Valoraciones
+	{domain.ErrInvalidFeedback, http.StatusBadRequest, "invalid_request", true},
+
+	// Extractos
+	{domain.ErrInvalidStatementMonth, http.StatusBadRequest, "invalid_request", true},
+
+	// Conversaciones
+	{domain.ErrConversationNotFound, http.StatusNotFound, "not_found", true},
+	// 409 Conflict: la petición choca con el estado actual del recurso
+	{domain.ErrConversationNotDeleted, http.StatusConflict, "conflict", true},
+	// 410 Gone: el recurso existió pero ya no se puede recuperar
+	{domain.ErrRestoreWindowExpired, http.StatusGone, "gone", true},
+	// 402: seguir exige más presupuesto (reintentar no sirve)
+	{domain.ErrConversationBudgetExceeded, http.StatusPaymentRequired, "budget_exceeded", true},
+
+	// Chats asíncronos
+	{domain.ErrJobNotFound, http.StatusNotFound, "not_found", true},
+	{domain.ErrJobQueueFull, http.StatusServiceUnavailable, "queue_full", true},
+	{domain.ErrInvalidCallbackURL, http.StatusBadRequest, "invalid_request", true},
+	{domain.ErrCallbacksDisabled, http.StatusBadRequest, "invalid_request", true},
+	{domain.ErrJobResultUnavailable, http.StatusConflict, "conflict", true},
+
+	// Proveedor de modelos
+	{domain.ErrRateLimited, http.StatusTooManyRequests, "rate_limited", true},
+	{domain.ErrModelNotFound, http.StatusNotFound, "model_not_found", true},
+	{domain.ErrModelDecommissioned, http.StatusNotFound, "model_decommissioned", true},
+	{domain.ErrContextTooLong, http.StatusRequestEntityTooLarge, "context_too_long", true},
+	{domain.ErrInvalidRequest, http.StatusBadRequest, "invalid_request", true},
+	{domain.ErrUpstreamUnavailable, http.StatusServiceUnavailable, "upstream_unavailable", false},
+	{domain.ErrUpstreamTimeout, http.StatusGatewayTimeout, "upstream_timeout", false},
+	// 502: el fallo está en el servicio externo (o en nuestra configuración de él)
+	{domain.ErrUpstreamAuth, http.StatusBadGateway, "upstream_error", false},
+	{domain.ErrInvalidModelOutput, http.StatusBadGateway, "invalid_model_output", true},
+
+	// Venció el plazo de la petición (X-Request-Timeout) antes de llamar al proveedor
+	{context.DeadlineExceeded, http.StatusGatewayTimeout, "timeout", false},
+}
+
+// classifyServiceError busca la respuesta HTTP de un error del servicio
+// Si no hay ninguna, devuelve un 500 con el mensaje genérico
+func classifyServiceError(err error, fallbackMessage string) *ErrorResponse {
+	for _, mapping := range serviceErrorMappings {
+		// errors.Is() recorre la cadena de errores wrapeados con %w
+		if !errors.Is(err, mapping.target) {
+			continue
+		}
+
+		message := fallbackMessage
+		if mapping.expose {
+			message = err.Error()
+		}
+
+		response := NewErrorResponse(message, mapping.status)
+		response.Type = mapping.errType
+
+		// errors.As() extrae los detalles del proveedor (Retry-After...)
+		var upstream *domain.UpstreamError
+		if errors.As(err, &upstream) && upstream.RetryAfter > 0 {
+			response.RetryAfter = int(math.Ceil(upstream.RetryAfter.Seconds()))
+		}
+		var flagged *domain.ModerationError
+		if errors.As(err, &flagged) {
+			response.Categories = flagged.Categories
+		}
+		return response
+	}
+
+	response := NewErrorResponse(fallbackMessage, http.StatusInternalServerError)
+	response.Type = "internal_error"
+	return response
+}
+
+// writeServiceError escribe la respuesta de error de un fallo del servicio
+// fallbackMessage se usa cuando el error no debe mostrarse al cliente
+func writeServiceError(w http.ResponseWriter, err error, fallbackMessage string) {
+	response := classifyServiceError(err, fallbackMessage)
+
+	// Los errores del servidor se registran; los del cliente no hacen ruido
+	if response.Code >= http.StatusInternalServerError {
+		log.Printf("Error en servicio: %v", err)
+	}
+
+	if response.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(response.RetryAfter))
+	}
+	writeJSONResponse(w, response, response.Code)
+}
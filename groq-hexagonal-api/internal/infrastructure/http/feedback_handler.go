@@ -0,0 +1,83 @@
+// Package http - Handlers HTTP de las valoraciones de las respuestas
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// defaultFeedbackSummaryDays es el periodo de GET /admin/feedback sin ?days=
+const defaultFeedbackSummaryDays = 30
+
+// FeedbackHandler maneja las valoraciones de las respuestas
+// HandleSummary va en el subrouter /admin (requiere X-Admin-Key)
+type FeedbackHandler struct {
+	feedbackService domain.FeedbackService
+}
+
+// NewFeedbackHandler crea un nuevo handler con el servicio inyectado
+func NewFeedbackHandler(service domain.FeedbackService) *FeedbackHandler {
+	if service == nil {
+		panic("feedbackService no puede ser nil")
+	}
+
+	return &FeedbackHandler{
+		feedbackService: service,
+	}
+}
+
+// HandleSubmit maneja POST /api/v1/feedback
+// Responde 201 con la valoración guardada
+func (h *FeedbackHandler) HandleSubmit(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleSubmitFeedback", r.Method, r.URL.Path)
+
+	var req FeedbackRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	feedback, err := h.feedbackService.Submit(r.Context(), domain.Feedback{
+		ResponseID:     req.ResponseID,
+		ConversationID: req.ConversationID,
+		Rating:         req.Rating,
+		Comment:        req.Comment,
+		Model:          req.Model,
+		Persona:        req.Persona,
+	})
+	if err != nil {
+		writeServiceError(w, err, "error al guardar la valoración")
+		return
+	}
+	writeJSONResponse(w, &FeedbackResponse{Success: true, Feedback: NewFeedbackInfo(feedback)}, http.StatusCreated)
+}
+
+// HandleSummary maneja GET /admin/feedback
+// ?days=N es el periodo (por defecto 30, máximo 90) y ?tenant= limita el
+// resumen a un tenant
+func (h *FeedbackHandler) HandleSummary(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleFeedbackSummary", r.Method, r.URL.Path)
+
+	days := defaultFeedbackSummaryDays
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > domain.MaxFeedbackSummaryDays {
+			writeErrorResponse(w, "days debe ser un entero entre 1 y "+strconv.Itoa(domain.MaxFeedbackSummaryDays), http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	summary, err := h.feedbackService.Summary(r.Context(), domain.FeedbackFilter{
+		Since:  time.Now().UTC().AddDate(0, 0, -days),
+		Tenant: r.URL.Query().Get("tenant"),
+	})
+	if err != nil {
+		writeServiceError(w, err, "error al resumir las valoraciones")
+		return
+	}
+	writeJSONResponse(w, NewFeedbackSummaryResponse(summary), http.StatusOK)
+}
@@ -2,7 +2,6 @@
 package http
 
 import (
-	"encoding/json"
 	"groq-hexagonal-api/internal/domain"
 	"log"
 	"net/http"
@@ -19,6 +18,32 @@ type ChatHandler struct {
 	// chatService es la dependencia del servicio de aplicación
 	// Usamos la interfaz, no la implementación concreta
 	chatService domain.ChatService
+	
+	// sseRetry es el campo "retry:" que se envía al abrir un flujo SSE
+	// (0 = no se envía y EventSource usa su valor por defecto)
+	sseRetry time.Duration
+	
+	// streams guarda los flujos para reanudarlos (nil = no se reanudan)
+	streams *streamSessions
+}
+
+// ChatHandlerOption configura aspectos opcionales del handler de chat
+type ChatHandlerOption func(*ChatHandler)
+
+// WithSSERetry indica a los clientes EventSource cuánto esperar antes de
+// reconectar si se corta el flujo
+func WithSSERetry(retry time.Duration) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.sseRetry = retry
+	}
+}
+
+// WithStreamResume permite reanudar los flujos SSE interrumpidos durante
+// window después de terminar (ver stream_resume.go)
+func WithStreamResume(window time.Duration) ChatHandlerOption {
+	return func(h *ChatHandler) {
+		h.streams = newStreamSessions(window)
+	}
 }
 
 // ============================================================================
@@ -26,14 +51,18 @@ type ChatHandler struct {
 // ============================================================================
 
 // NewChatHandler crea un nuevo handler con el servicio inyectado
-func NewChatHandler(service domain.ChatService) *ChatHandler {
+func NewChatHandler(service domain.ChatService, opts ...ChatHandlerOption) *ChatHandler {
 	if service == nil {
 		panic("chatService no puede ser nil")
 	}
 	
-	return &ChatHandler{
+	h := &ChatHandler{
 		chatService: service,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // ============================================================================
@@ -71,11 +100,12 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 	// Crear una variable para el DTO
 	var req ChatRequest
 	
-	// json.NewDecoder() lee del body de la petición
-	// .Decode(&req) parsea el JSON a la struct
+	// decodeJSON() lee el body de la petición con el codec configurado
+	// (encoding/json por defecto, ver json_codec.go) y parsea el JSON a la struct
 	// &req es un puntero porque Decode necesita modificar el struct
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeErrorResponse(w, "JSON inválido: "+err.Error(), http.StatusBadRequest)
+	if err := decodeJSON(r.Body, &req); err != nil {
+		// 413 si el body supera MAX_BODY_BYTES (ver body_limit.go)
+		writeDecodeError(w, err)
 		return
 	}
 	
@@ -92,6 +122,12 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	// Las imágenes tienen su propio status (413 demasiado grande, 415 tipo no soportado)
+	if status, err := validateImages(req); err != nil {
+		h.writeErrorResponse(w, err.Error(), status)
+		return
+	}
+	
 	// ========================================================================
 	// 5. LLAMAR AL SERVICIO DE APLICACIÓN
 	// ========================================================================
@@ -100,37 +136,57 @@ func (h *ChatHandler) HandleChat(w http.ResponseWriter, r *http.Request) {
 	// Este contexto se cancela automáticamente si el cliente cierra la conexión
 	ctx := r.Context()
 	
-	// Llamar al servicio con el mensaje y modelo
-	response, err := h.chatService.SendMessage(ctx, req.Message, req.Model)
+	// En un dry run no se llama a Groq (tampoco en streaming: no hay nada que emitir)
+	if req.DryRun {
+		h.handleChatDryRun(w, r, req)
+		return
+	}
+	
+	// Si el cliente pidió streaming, la respuesta se envía por fragmentos (SSE)
+	if req.Stream {
+		h.handleChatStream(w, r, req)
+		return
+	}
+	
+	// Llamar al servicio con el mensaje, el modelo y los parámetros opcionales
+	response, err := h.chatService.SendMessage(ctx, req.Message, req.Model, req.toMessageOptions())
 	if err != nil {
-		// Error del servicio -> 500 Internal Server Error
-		log.Printf("Error en servicio: %v", err)
-		h.writeErrorResponse(w, "error al procesar el mensaje", http.StatusInternalServerError)
+		// Error del servicio -> status según el tipo (429, 404, 413, 503...)
+		writeServiceError(w, err, "error al procesar el mensaje")
 		return
 	}
 	
+	// El consumo incluye la llamada de verificación (si se pidió)
+	usage := response.TotalUsage()
+	annotateAccessLog(ctx, response.Model, &usage)
+	
 	// ========================================================================
 	// 6. MAPEAR DOMINIO → DTO
 	// ========================================================================
 	
 	// Convertir la respuesta del dominio a DTO HTTP
-	chatResponse := NewChatResponse(
-		response.GetResponseContent(),
-		response.Model,
-		&UsageInfo{
-			PromptTokens:     response.Usage.PromptTokens,
-			CompletionTokens: response.Usage.CompletionTokens,
-			TotalTokens:      response.Usage.TotalTokens,
-		},
-	)
+	chatResponse := NewChatResponseFromDomain(response)
 	
 	// ========================================================================
 	// 7. ESCRIBIR LA RESPUESTA JSON
 	// ========================================================================
 	
+	writeCacheHeaders(w, response.Meta)
 	h.writeJSONResponse(w, chatResponse, http.StatusOK)
 }
 
+// handleChatDryRun responde con lo que se habría enviado a Groq
+func (h *ChatHandler) handleChatDryRun(w http.ResponseWriter, r *http.Request, req ChatRequest) {
+	result, err := h.chatService.DryRun(r.Context(), req.Message, req.Model, req.toMessageOptions())
+	if err != nil {
+		writeServiceError(w, err, "error al procesar el mensaje")
+		return
+	}
+	
+	annotateAccessLog(r.Context(), result.Request.Model, nil)
+	h.writeJSONResponse(w, NewDryRunResponse(result), http.StatusOK)
+}
+
 // HandleGetModels maneja GET /api/v1/models
 // Retorna la lista de modelos disponibles
 func (h *ChatHandler) HandleGetModels(w http.ResponseWriter, r *http.Request) {
@@ -152,11 +208,14 @@ func (h *ChatHandler) HandleGetModels(w http.ResponseWriter, r *http.Request) {
 	// 3. LLAMAR AL SERVICIO
 	// ========================================================================
 	
+	// ?provider=openai lista los modelos de otro proveedor (por defecto, el configurado)
 	ctx := r.Context()
+	if provider := r.URL.Query().Get("provider"); provider != "" {
+		ctx = domain.WithProvider(ctx, provider)
+	}
 	response, err := h.chatService.GetAvailableModels(ctx)
 	if err != nil {
-		log.Printf("Error al obtener modelos: %v", err)
-		h.writeErrorResponse(w, "error al obtener modelos", http.StatusInternalServerError)
+		writeServiceError(w, err, "error al obtener modelos")
 		return
 	}
 	
@@ -200,6 +259,17 @@ func (h *ChatHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 // writeJSONResponse escribe una respuesta JSON
 // Es un método privado (empieza con minúscula)
 func (h *ChatHandler) writeJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
+	writeJSONResponse(w, data, statusCode)
+}
+
+// writeErrorResponse escribe una respuesta de error
+func (h *ChatHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+	writeErrorResponse(w, message, statusCode)
+}
+
+// writeJSONResponse es la versión como función del paquete
+// La comparten todos los handlers (ChatHandler, ConversationHandler...)
+func writeJSONResponse(w http.ResponseWriter, data interface{}, statusCode int) {
 	// Establecer Content-Type
 	w.Header().Set("Content-Type", "application/json")
 	
@@ -207,20 +277,20 @@ func (h *ChatHandler) writeJSONResponse(w http.ResponseWriter, data interface{},
 	w.WriteHeader(statusCode)
 	
 	// Serializar y escribir JSON
-	// json.NewEncoder() crea un encoder que escribe directamente a w
-	if err := json.NewEncoder(w).Encode(data); err != nil {
+	// El encoder del codec configurado escribe directamente a w
+	if err := jsonCodec.NewEncoder(w).Encode(data); err != nil {
 		// Si falla la serialización, registrar el error
 		log.Printf("Error al escribir JSON: %v", err)
 	}
 }
 
 // writeErrorResponse escribe una respuesta de error
-func (h *ChatHandler) writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
+func writeErrorResponse(w http.ResponseWriter, message string, statusCode int) {
 	// Crear el DTO de error
 	errorResponse := NewErrorResponse(message, statusCode)
 	
 	// Escribir la respuesta
-	h.writeJSONResponse(w, errorResponse, statusCode)
+	writeJSONResponse(w, errorResponse, statusCode)
 }
 
 // ============================================================================
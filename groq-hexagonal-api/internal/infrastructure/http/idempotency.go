@@ -0,0 +1,256 @@
+// Package http - Peticiones idempotentes con Idempotency-Key
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// ============================================================================
+// IDEMPOTENCY-KEY
+// ============================================================================
+//
+// POST /api/v1/chat acepta la cabecera Idempotency-Key (ej: un UUID por
+// operación, el mismo en todos sus reintentos). La primera petición con la
+// clave se procesa y su respuesta se guarda durante IdempotencyOptions.TTL;
+// los duplicados la reciben tal cual, con la cabecera Idempotent-Replayed:
+//
+//   - la misma clave con otro body → 422 idempotency_key_reused
+//   - la primera todavía en curso → 409 idempotency_in_progress (reintentar)
+//   - las respuestas 5xx y 429 no se guardan: el reintento vuelve a ejecutar
+//
+// Las claves son de cada cliente (tenant y API key). El streaming también se
+// guarda (el flujo entero) y se guarda sin comprimir, para servirlo a
+// cualquier cliente; un Last-Event-ID (reanudar el flujo) no pasa por aquí.
+// Un flujo solo se guarda si llegó al cliente hasta el [DONE] (el handler lo
+// marca con markStreamDone): si el cliente se desconecta o el flujo termina
+// con "event: error", la clave se libera y el reintento vuelve a ejecutar.
+//
+// El almacén es un puerto (domain.IdempotencyStore): en memoria con una
+// réplica, en Redis con varias. Si falla, la petición se procesa sin
+// idempotencia (se registra en el log).
+// ============================================================================
+
+// Cabeceras de las peticiones idempotentes
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotencyReservationTTL es cuánto dura la reserva de una clave en curso:
+// si la réplica cae a mitad de la petición, la clave se libera sola
+const idempotencyReservationTTL = 10 * time.Minute
+
+// idempotentHeaders son las cabeceras de la respuesta que se guardan (el
+// resto las ponen los middlewares en cada petición: CORS, rate limit...)
+var idempotentHeaders = []string{"Content-Type", "Content-Disposition", CacheStatusHeader, CacheSimilarityHeader, StreamIDHeader}
+
+// IdempotencyOptions configura las peticiones idempotentes
+type IdempotencyOptions struct {
+	// Store guarda las respuestas (nil = Idempotency-Key se ignora)
+	Store domain.IdempotencyStore
+
+	// TTL es cuánto se guarda cada respuesta
+	TTL time.Duration
+}
+
+// idempotencyMiddleware guarda y repite las respuestas con Idempotency-Key
+func idempotencyMiddleware(options IdempotencyOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if options.Store == nil || options.TTL <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
+			if idempotencyKey == "" || r.Header.Get(lastEventIDHeader) != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(idempotencyKey) > domain.MaxIdempotencyKeyLength {
+				writeServiceError(w, domain.ErrInvalidIdempotencyKey, "Idempotency-Key inválida")
+				return
+			}
+
+			// El body se lee aquí para compararlo con el de la primera petición
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// Guardar o repetir la respuesta no depende de que el cliente siga
+			ctx := context.WithoutCancel(r.Context())
+			key := idempotencyStoreKey(requestOwner(r), idempotencyKey)
+			fingerprint := requestFingerprint(r, body)
+
+			existing, err := options.Store.Reserve(ctx, key, domain.IdempotentResponse{
+				Fingerprint: fingerprint,
+				CreatedAt:   time.Now(),
+			}, idempotencyReservationTTL)
+			if err != nil {
+				log.Printf("⚠️  Error al reservar la Idempotency-Key: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if existing != nil {
+				replayIdempotentResponse(w, existing, fingerprint)
+				return
+			}
+
+			// Sin comprimir: la respuesta guardada sirve a cualquier cliente
+			r.Header.Del("Accept-Encoding")
+
+			recorder := &idempotencyRecorder{ResponseWriter: w, headers: idempotentHeaders}
+			completed := false
+			// Si el handler no termina (panic), la clave se libera igualmente
+			defer func() {
+				if completed {
+					return
+				}
+				if err := options.Store.Release(ctx, key); err != nil {
+					log.Printf("⚠️  Error al liberar la Idempotency-Key: %v", err)
+				}
+			}()
+
+			next.ServeHTTP(recorder, r)
+
+			status := recorder.statusCode()
+			if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+				return
+			}
+			// Un flujo a medias no se puede repetir como si fuera la respuesta
+			if isEventStream(recorder.header) && !recorder.streamDone {
+				return
+			}
+			err = options.Store.Complete(ctx, key, domain.IdempotentResponse{
+				Fingerprint: fingerprint,
+				Completed:   true,
+				StatusCode:  status,
+				Header:      recorder.header,
+				Body:        recorder.body.Bytes(),
+				CreatedAt:   time.Now(),
+			}, options.TTL)
+			if err != nil {
+				log.Printf("⚠️  Error al guardar la respuesta idempotente: %v", err)
+				return
+			}
+			completed = true
+		})
+	}
+}
+
+// replayIdempotentResponse responde a un duplicado
+func replayIdempotentResponse(w http.ResponseWriter, existing *domain.IdempotentResponse, fingerprint string) {
+	switch {
+	case existing.Fingerprint != fingerprint:
+		writeServiceError(w, domain.ErrIdempotencyKeyReused, "Idempotency-Key reutilizada")
+	case !existing.Completed:
+		w.Header().Set("Retry-After", "1")
+		writeServiceError(w, domain.ErrIdempotencyInProgress, "Idempotency-Key en curso")
+	default:
+		for name, values := range existing.Header {
+			w.Header()[name] = values
+		}
+		w.Header().Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(existing.StatusCode)
+		w.Write(existing.Body)
+	}
+}
+
+// idempotencyStoreKey es la clave en el almacén: un hash del cliente y de la
+// Idempotency-Key (de longitud fija, y sin que la clave del cliente llegue al
+// almacén tal cual)
+func idempotencyStoreKey(owner, idempotencyKey string) string {
+	hash := sha256.New()
+	hash.Write([]byte(owner))
+	hash.Write([]byte{0})
+	hash.Write([]byte(idempotencyKey))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// requestFingerprint identifica la petición: método, ruta y body
+func requestFingerprint(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// idempotencyRecorder copia la respuesta a medida que se envía
+// También lo usa la caché por ruta (ver route_cache.go)
+type idempotencyRecorder struct {
+	http.ResponseWriter
+
+	// headers son las cabeceras de la respuesta que se copian
+	headers []string
+
+	status int
+	header map[string][]string
+	body   bytes.Buffer
+
+	// streamDone indica que el flujo SSE llegó entero al cliente
+	streamDone bool
+}
+
+// WriteHeader guarda el status y las cabeceras que se repiten
+func (rec *idempotencyRecorder) WriteHeader(statusCode int) {
+	if rec.status == 0 {
+		rec.status = statusCode
+		rec.header = make(map[string][]string)
+		for _, name := range rec.headers {
+			if values := rec.Header().Values(name); len(values) > 0 {
+				rec.header[name] = append([]string(nil), values...)
+			}
+		}
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write copia el body (el primer Write implica un 200)
+func (rec *idempotencyRecorder) Write(data []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	rec.body.Write(data)
+	return rec.ResponseWriter.Write(data)
+}
+
+// Unwrap permite que http.ResponseController llegue al writer original
+// (Flush en streaming)
+func (rec *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// statusCode retorna el status enviado (200 si el handler no escribió nada)
+func (rec *idempotencyRecorder) statusCode() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+// markStreamDone marca como completo el flujo SSE que se escribe en w: los
+// recorders de la cadena de writers pueden guardarlo
+// El handler la llama solo si el cliente recibió el [DONE]
+func markStreamDone(w http.ResponseWriter) {
+	for w != nil {
+		if recorder, ok := w.(*idempotencyRecorder); ok {
+			recorder.streamDone = true
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = unwrapper.Unwrap()
+	}
+}
@@ -0,0 +1,109 @@
+// Package http - Validación de las imágenes del chat (visión)
+package http
+
+import (
+	"encoding/base64"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ============================================================================
+// IMÁGENES
+// ============================================================================
+//
+// ChatRequest.Images acepta dos formatos:
+//
+//   "https://example.com/gato.jpg"        Groq descarga la imagen
+//   "data:image/png;base64,iVBORw0KG..."  la imagen va en la petición
+//
+// De las data URIs se comprueba aquí el tamaño y el tipo MIME (el declarado
+// y el real, detectado por los primeros bytes) antes de enviar nada a Groq.
+// De las URLs solo se comprueba la forma: el contenido lo valida Groq.
+// ============================================================================
+
+// Errores de validación de las imágenes
+var (
+	ErrTooManyImages        = NewValidationError(fmt.Sprintf("images admite como máximo %d imágenes", domain.MaxImagesPerMessage))
+	ErrInvalidImage         = NewValidationError("cada imagen debe ser una URL http(s) o una data URI en base64")
+	ErrImageTooLarge        = NewValidationError(fmt.Sprintf("cada imagen admite como máximo %d MiB", domain.MaxImageBytes>>20))
+	ErrUnsupportedImage     = NewValidationError(fmt.Sprintf("tipo de imagen no soportado (tipos válidos: %v)", domain.SupportedImageTypes))
+	ErrImageTypeMismatch    = NewValidationError("el contenido de la imagen no coincide con su tipo MIME")
+	ErrImagesWithoutMessage = NewValidationError("las imágenes requieren un mensaje")
+)
+
+// validateImages comprueba las imágenes de la petición
+// Retorna el status HTTP que corresponde al error: 413 si una imagen es
+// demasiado grande, 415 si su tipo no se admite y 400 en el resto de casos
+func validateImages(req ChatRequest) (int, error) {
+	if len(req.Images) == 0 {
+		return http.StatusOK, nil
+	}
+	if req.Message == "" {
+		return http.StatusBadRequest, ErrImagesWithoutMessage
+	}
+	if len(req.Images) > domain.MaxImagesPerMessage {
+		return http.StatusBadRequest, ErrTooManyImages
+	}
+
+	for _, image := range req.Images {
+		if status, err := validateImage(image); err != nil {
+			return status, err
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// validateImage comprueba una imagen (ver validateImages)
+func validateImage(image string) (int, error) {
+	if !strings.HasPrefix(image, "data:") {
+		parsed, err := url.Parse(image)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return http.StatusBadRequest, ErrInvalidImage
+		}
+		return http.StatusOK, nil
+	}
+
+	// data:<tipo MIME>;base64,<datos>
+	header, data, found := strings.Cut(strings.TrimPrefix(image, "data:"), ",")
+	mimeType, isBase64 := strings.CutSuffix(header, ";base64")
+	if !found || !isBase64 {
+		return http.StatusBadRequest, ErrInvalidImage
+	}
+	if !domain.IsSupportedImageType(mimeType) {
+		return http.StatusUnsupportedMediaType, ErrUnsupportedImage
+	}
+
+	// El tamaño se comprueba antes de decodificar para no reservar memoria
+	if base64.StdEncoding.DecodedLen(len(data)) > domain.MaxImageBytes+2 {
+		return http.StatusRequestEntityTooLarge, ErrImageTooLarge
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil || len(decoded) == 0 {
+		return http.StatusBadRequest, ErrInvalidImage
+	}
+	if len(decoded) > domain.MaxImageBytes {
+		return http.StatusRequestEntityTooLarge, ErrImageTooLarge
+	}
+
+	// DetectContentType mira los primeros bytes (la "firma" del formato)
+	if http.DetectContentType(decoded) != strings.ToLower(mimeType) {
+		return http.StatusUnsupportedMediaType, ErrImageTypeMismatch
+	}
+	return http.StatusOK, nil
+}
+
+// toDomainImages convierte las imágenes del DTO al dominio
+func toDomainImages(images []string) []domain.ImageURL {
+	if len(images) == 0 {
+		return nil
+	}
+
+	result := make([]domain.ImageURL, len(images))
+	for i, image := range images {
+		result[i] = domain.ImageURL{URL: image}
+	}
+	return result
+}
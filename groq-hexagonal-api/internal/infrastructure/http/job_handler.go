@@ -0,0 +1,196 @@
+// Package http - Handlers HTTP de chats asíncronos (jobs)
+package http
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// JobHandler maneja las peticiones HTTP de chats asíncronos
+type JobHandler struct {
+	jobService domain.JobService
+}
+
+// NewJobHandler crea un nuevo handler con el servicio inyectado
+func NewJobHandler(service domain.JobService) *JobHandler {
+	if service == nil {
+		panic("jobService no puede ser nil")
+	}
+
+	return &JobHandler{
+		jobService: service,
+	}
+}
+
+// HandleSubmit maneja POST /api/v1/chat/async
+// Acepta el mismo body que /chat (más callback_url) y responde 202 con el job
+// sin esperar al modelo. Con "stream": true la respuesta se genera por
+// fragmentos y el job informa de los tokens generados (ver job_progress.go)
+func (h *JobHandler) HandleSubmit(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleSubmitJob", r.Method, r.URL.Path)
+
+	var req ChatJobRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if status, err := validateImages(req.ChatRequest); err != nil {
+		writeErrorResponse(w, err.Error(), status)
+		return
+	}
+	// El resultado se consulta al terminar: no hay nada que simular
+	if req.DryRun {
+		writeErrorResponse(w, "dry_run no se admite en /chat/async", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.jobService.Submit(r.Context(), domain.JobRequest{
+		Client:      usageClient(r),
+		Message:     req.Message,
+		Model:       req.Model,
+		Options:     req.toMessageOptions(),
+		CallbackURL: req.CallbackURL,
+		Stream:      req.Stream,
+	})
+	if err != nil {
+		writeServiceError(w, err, "error al encolar el mensaje")
+		return
+	}
+
+	// 202 Accepted: la petición se procesará; Location dice dónde consultarla
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	writeJSONResponse(w, NewJobResponse(job), http.StatusAccepted)
+}
+
+// HandleGet maneja GET /api/v1/jobs/{id}
+// Solo el cliente que creó el job puede consultarlo (al resto le responde 404)
+func (h *JobHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	job, err := h.jobService.GetJob(r.Context(), usageClient(r), mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, "error al obtener el job")
+		return
+	}
+
+	writeJSONResponse(w, NewJobResponse(job), http.StatusOK)
+}
+
+// HandleResult maneja GET /api/v1/jobs/{id}/result
+// Descarga la respuesta del job terminado como JSON. Admite Range (para
+// reanudar descargas grandes) e If-None-Match: el resultado no cambia, así
+// que el ID del job sirve de ETag
+func (h *JobHandler) HandleResult(w http.ResponseWriter, r *http.Request) {
+	job, result, err := h.jobService.OpenResult(r.Context(), usageClient(r), mux.Vars(r)["id"])
+	if err != nil {
+		writeServiceError(w, err, "error al obtener el resultado del job")
+		return
+	}
+	defer result.Close()
+
+	var modified time.Time
+	if job.FinishedAt != nil {
+		modified = *job.FinishedAt
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", `"`+job.ID+`"`)
+	http.ServeContent(w, r, "", modified, result)
+}
+
+// jobEventsPollInterval es cada cuánto se consulta el job en
+// GET /jobs/{id}/events (el repositorio puede ser de otra réplica)
+const jobEventsPollInterval = 500 * time.Millisecond
+
+// jobEventsPingInterval es cada cuánto se envía un comentario si el job no
+// cambia, para que los proxies no cierren la conexión
+const jobEventsPingInterval = 15 * time.Second
+
+// HandleEvents maneja GET /api/v1/jobs/{id}/events
+// Emite el job (el mismo JSON que GET /jobs/{id}) como evento SSE cada vez
+// que cambia su estado o su progreso; el nombre del evento es el estado
+// (pending, running, succeeded o failed). El flujo termina con [DONE] al
+// terminar el job
+func (h *JobHandler) HandleEvents(w http.ResponseWriter, r *http.Request) {
+	client, id := usageClient(r), mux.Vars(r)["id"]
+
+	// Antes de abrir el flujo: un job que no existe es un 404 normal
+	job, err := h.jobService.GetJob(r.Context(), client, id)
+	if err != nil {
+		writeServiceError(w, err, "error al obtener el job")
+		return
+	}
+
+	out, flush, finish := startSSE(w, r, 0)
+	defer finish()
+	events := newSSEWriter(out)
+
+	poll := time.NewTicker(jobEventsPollInterval)
+	defer poll.Stop()
+	var last *JobResponse
+	lastSent := time.Now()
+
+	for {
+		current := NewJobResponse(job)
+		if last == nil || jobChanged(last, current) {
+			if err := events.WriteEvent(current.Status, current); err != nil {
+				return
+			}
+			last, lastSent = current, time.Now()
+		} else if time.Since(lastSent) >= jobEventsPingInterval {
+			if err := events.WritePing(); err != nil {
+				return
+			}
+			lastSent = time.Now()
+		}
+		if job.Status.Done() {
+			events.WriteDone()
+			flush()
+			return
+		}
+		if err := flush(); err != nil {
+			return
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-poll.C:
+		}
+
+		job, err = h.jobService.GetJob(r.Context(), client, id)
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				// Las cabeceras ya se enviaron: el status va solo en el evento
+				events.WriteEvent("error", classifyServiceError(err, "error al obtener el job"))
+				flush()
+			}
+			return
+		}
+	}
+}
+
+// jobChanged indica si el estado o el progreso del job han cambiado
+func jobChanged(previous, current *JobResponse) bool {
+	if previous.Status != current.Status || (previous.Progress == nil) != (current.Progress == nil) {
+		return true
+	}
+	if current.Progress == nil {
+		return false
+	}
+	// Percent es un puntero: se comparan los valores
+	before, after := *previous.Progress, *current.Progress
+	if (before.Percent == nil) != (after.Percent == nil) ||
+		(after.Percent != nil && *before.Percent != *after.Percent) {
+		return true
+	}
+	return before.Tokens != after.Tokens || before.UpdatedAt != after.UpdatedAt
+}
@@ -0,0 +1,184 @@
+// Package http - Entrega de los jobs terminados a su callback_url
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ============================================================================
+// CALLBACKS DE JOBS (WEBHOOKS)
+// ============================================================================
+//
+// El body del callback es el mismo JSON que GET /api/v1/jobs/{id} (sin el
+// estado del callback). Cabeceras:
+//
+//	X-Webhook-Event:     job.completed
+//	X-Webhook-ID:        ID del job (el mismo en todos los reintentos)
+//	X-Webhook-Attempt:   número de intento, desde 1
+//	X-Webhook-Timestamp: Unix, segundos
+//	X-Webhook-Signature: sha256=<hex de HMAC-SHA256(secreto, timestamp + "." + body)>
+//
+// El receptor recalcula la firma con WEBHOOK_SECRET y rechaza las que no
+// coinciden o tienen un timestamp viejo (evita que se reenvíe una captura).
+// Como ya se reintenta, debe tolerar entregas repetidas del mismo job.
+//
+// La URL la elige el cliente, así que por defecto no se conecta a
+// direcciones internas (loopback, redes privadas, link-local con los
+// metadatos de la nube, CGNAT 100.64.0.0/10, que en muchas nubes es la red
+// interna de la VPC): la comprobación se hace al conectar, con la IP ya resuelta, para que un DNS
+// que cambie de respuesta no la salte. Las redirecciones no se siguen.
+// ============================================================================
+
+// Cabeceras de los callbacks
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookIDHeader        = "X-Webhook-ID"
+	WebhookAttemptHeader   = "X-Webhook-Attempt"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// sharedAddressSpace es el rango CGNAT (RFC 6598): net.IP no lo considera
+// privado, pero no es alcanzable desde Internet
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// errPrivateAddress se retorna al intentar conectar a una dirección interna
+var errPrivateAddress = errors.New("la dirección del callback no es pública")
+
+// JobWebhookNotifier entrega los jobs por HTTP, firmados con HMAC
+// Implementa domain.JobNotifier
+type JobWebhookNotifier struct {
+	client *http.Client
+	secret []byte
+
+	// now da la hora de la firma
+	now func() time.Time
+}
+
+// WebhookOption configura aspectos opcionales del notificador
+type WebhookOption func(*JobWebhookNotifier)
+
+// WithWebhookTransportWrapper envuelve el transporte HTTP de las entregas
+// (ej: egress.Allowlist.Wrap, que limita los hosts de salida)
+func WithWebhookTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) WebhookOption {
+	return func(n *JobWebhookNotifier) {
+		n.client.Transport = wrap(n.client.Transport)
+	}
+}
+
+// NewJobWebhookNotifier crea el notificador
+// timeout limita cada intento; allowPrivate permite URLs internas (pruebas,
+// receptores en la misma red)
+func NewJobWebhookNotifier(secret string, timeout time.Duration, allowPrivate bool, opts ...WebhookOption) *JobWebhookNotifier {
+	if secret == "" {
+		panic("secret no puede estar vacío")
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = rejectPrivateAddress
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+
+	notifier := &JobWebhookNotifier{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		secret: []byte(secret),
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(notifier)
+	}
+	return notifier
+}
+
+// NotifyJob implementa domain.JobNotifier
+func (n *JobWebhookNotifier) NotifyJob(ctx context.Context, job *domain.Job, attempt int) error {
+	// El estado de la entrega no le sirve al receptor: es esta misma entrega
+	payload := NewJobResponse(job)
+	payload.Callback = nil
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("error al serializar el job: %w", err)
+	}
+
+	return n.deliver(ctx, job.Callback.URL, "job.completed", job.ID, attempt, body)
+}
+
+// deliver envía un webhook firmado a url
+// attempt 0 omite X-Webhook-Attempt (el emisor no cuenta los intentos)
+func (n *JobWebhookNotifier) deliver(ctx context.Context, url, event, id string, attempt int, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error al crear la petición: %w", err)
+	}
+	timestamp := strconv.FormatInt(n.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookIDHeader, id)
+	if attempt > 0 {
+		req.Header.Set(WebhookAttemptHeader, strconv.Itoa(attempt))
+	}
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+n.sign(timestamp, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error al entregar el callback: %w", err)
+	}
+	defer resp.Body.Close()
+	// Se lee (poco) el body para que la conexión se pueda reutilizar
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: status %d", domain.ErrCallbackRejected, resp.StatusCode)
+	}
+	return nil
+}
+
+// sign calcula la firma HMAC-SHA256 de timestamp + "." + body
+func (n *JobWebhookNotifier) sign(timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, n.secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// rejectPrivateAddress impide conectar a direcciones no públicas
+// Se llama con la IP ya resuelta, justo antes de conectar
+func rejectPrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testWebhookSecret es el secreto de los callbacks de prueba
+const testWebhookSecret = "webhook-secret"
+
+func TestRejectPrivateAddress(t *testing.T) {
+	rejected := []string{
+		"127.0.0.1", "127.1.2.3", "::1", // loopback
+		"10.0.0.1", "172.16.5.4", "192.168.1.1", "fd00::1", // privadas
+		"169.254.169.254", "fe80::1", // link-local (metadatos de la nube)
+		"::ffff:127.0.0.1", "::ffff:10.0.0.1", "::ffff:169.254.169.254", // IPv4 en IPv6
+		"0.0.0.0", "::", // sin especificar
+		"100.64.0.1", "100.127.255.254", // CGNAT
+		"224.0.0.1", "ff02::1", // multicast
+	}
+	for _, ip := range rejected {
+		err := rejectPrivateAddress("tcp", net.JoinHostPort(ip, "443"), nil)
+		if !errors.Is(err, errPrivateAddress) {
+			t.Errorf("rejectPrivateAddress(%s) = %v; se esperaba errPrivateAddress", ip, err)
+		}
+	}
+
+	allowed := []string{"93.184.216.34", "8.8.8.8", "100.63.255.255", "100.128.0.1", "2606:4700::1111"}
+	for _, ip := range allowed {
+		if err := rejectPrivateAddress("tcp", net.JoinHostPort(ip, "443"), nil); err != nil {
+			t.Errorf("rejectPrivateAddress(%s) = %v; se esperaba nil", ip, err)
+		}
+	}
+}
+
+func TestWebhookRejectsPrivateURL(t *testing.T) {
+	var received bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = true
+	}))
+	defer server.Close()
+
+	notifier := NewJobWebhookNotifier(testWebhookSecret, time.Second, false)
+	err := notifier.deliver(context.Background(), server.URL, "job.completed", "job-1", 1, []byte(`{}`))
+	if !errors.Is(err, errPrivateAddress) {
+		t.Errorf("deliver a %s = %v; se esperaba errPrivateAddress", server.URL, err)
+	}
+	if received {
+		t.Error("el callback llegó a una dirección loopback")
+	}
+}
+
+func TestWebhookDoesNotFollowRedirects(t *testing.T) {
+	var redirected bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	// allowPrivate: los servidores de prueba escuchan en loopback
+	notifier := NewJobWebhookNotifier(testWebhookSecret, time.Second, true)
+	err := notifier.deliver(context.Background(), server.URL, "job.completed", "job-1", 1, []byte(`{}`))
+	if !errors.Is(err, domain.ErrCallbackRejected) {
+		t.Errorf("deliver con redirección = %v; se esperaba ErrCallbackRejected", err)
+	}
+	if redirected {
+		t.Error("se siguió la redirección")
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	var request *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	notifier := NewJobWebhookNotifier(testWebhookSecret, time.Second, true)
+	notifier.now = func() time.Time { return time.Unix(1760600000, 0) }
+	payload := []byte(`{"id":"job-1","status":"completed"}`)
+	if err := notifier.deliver(context.Background(), server.URL, "job.completed", "job-1", 2, payload); err != nil {
+		t.Fatal(err)
+	}
+
+	// La firma que tiene que recalcular el receptor
+	mac := hmac.New(sha256.New, []byte(testWebhookSecret))
+	mac.Write([]byte("1760600000." + string(payload)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	headers := map[string]string{
+		WebhookEventHeader:     "job.completed",
+		WebhookIDHeader:        "job-1",
+		WebhookAttemptHeader:   "2",
+		WebhookTimestampHeader: "1760600000",
+		WebhookSignatureHeader: want,
+	}
+	for name, value := range headers {
+		if got := request.Header.Get(name); got != value {
+			t.Errorf("%s = %q; se esperaba %q", name, got, value)
+		}
+	}
+	if string(body) != string(payload) {
+		t.Errorf("body = %s; se esperaba %s", body, payload)
+	}
+}
@@ -0,0 +1,51 @@
+// Package http - Codec JSON de las peticiones y respuestas
+package http
+
+import (
+	"groq-hexagonal-api/internal/infrastructure/jsoncodec"
+	"io"
+)
+
+// jsonCodec lee los bodies y escribe las respuestas (incluidos los eventos
+// SSE); por defecto, encoding/json (ver jsoncodec y JSON_CODEC)
+var jsonCodec jsoncodec.Codec = jsoncodec.Standard()
+
+// SetJSONCodec cambia el codec JSON de la capa HTTP
+// Se llama una vez al arrancar, antes de atender peticiones: el codec no se
+// protege con un mutex
+func SetJSONCodec(codec jsoncodec.Codec) {
+	if codec == nil {
+		panic("codec no puede ser nil")
+	}
+	jsonCodec = codec
+}
+
+// decodeJSON parsea el body de una petición en v
+// Los errores se responden con writeDecodeError (400, o 413 si el body es
+// demasiado grande)
+func decodeJSON(body io.Reader, v interface{}) error {
+	// No todos los codecs envuelven el error del reader con %w: se guarda
+	// aparte para que el *http.MaxBytesError llegue a writeDecodeError
+	reader := &readErrorRecorder{reader: body}
+	if err := jsonCodec.NewDecoder(reader).Decode(v); err != nil {
+		if reader.err != nil && reader.err != io.EOF {
+			return reader.err
+		}
+		return err
+	}
+	return nil
+}
+
+// readErrorRecorder recuerda el último error del reader que envuelve
+type readErrorRecorder struct {
+	reader io.Reader
+	err    error
+}
+
+func (r *readErrorRecorder) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if err != nil {
+		r.err = err
+	}
+	return n, err
+}
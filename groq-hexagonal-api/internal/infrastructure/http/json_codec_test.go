@@ -0,0 +1,94 @@
+package http
+
+import (
+	"bytes"
+	"groq-hexagonal-api/internal/infrastructure/jsoncodec"
+	"io"
+	"testing"
+)
+
+// Los benchmarks se ejecutan con cada codec compilado en el binario de test:
+// para comparar las librerías, make bench TAGS="jsoniter segmentio"
+
+// benchmarkChatBody es un POST /api/v1/chat con historial y parámetros
+var benchmarkChatBody = []byte(`{
+	"message": "¿Y cuánto cuesta el envío a Canarias?",
+	"model": "llama-3.3-70b-versatile",
+	"system_prompt": "Eres el asistente de una tienda online. Responde de forma concisa.",
+	"temperature": 0.7,
+	"max_tokens": 1000,
+	"stop": ["###"],
+	"history": [
+		{"role": "user", "content": "Hola, ¿hacéis envíos fuera de la península?"},
+		{"role": "assistant", "content": "Sí, enviamos a Baleares, Canarias, Ceuta y Melilla. El plazo es de 3 a 5 días laborables."},
+		{"role": "user", "content": "¿Y a Portugal?"},
+		{"role": "assistant", "content": "También: el envío a Portugal tarda de 2 a 4 días laborables."}
+	],
+	"documents": ["Envíos a Canarias: 9,95 € (gratis a partir de 60 €). Los pedidos pueden tener gastos de aduana."]
+}`)
+
+// benchmarkChatResponse es la respuesta de POST /api/v1/chat
+var benchmarkChatResponse = &ChatResponse{
+	Success: true,
+	Message: "El envío a Canarias cuesta 9,95 € y es gratis a partir de 60 €. Ten en cuenta que el pedido puede tener gastos de aduana.",
+	Model:   "llama-3.3-70b-versatile",
+	Usage:   &UsageInfo{PromptTokens: 182, CompletionTokens: 34, TotalTokens: 216, CostUSD: 0.000134},
+}
+
+// benchmarkCodecs ejecuta benchmark con cada codec compilado
+func benchmarkCodecs(b *testing.B, benchmark func(b *testing.B, codec jsoncodec.Codec)) {
+	for _, name := range jsoncodec.Names() {
+		codec, err := jsoncodec.Get(name)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			benchmark(b, codec)
+		})
+	}
+}
+
+// BenchmarkDecodeChatRequest mide la lectura del body de POST /api/v1/chat
+// (el mismo camino que decodeJSON)
+func BenchmarkDecodeChatRequest(b *testing.B) {
+	benchmarkCodecs(b, func(b *testing.B, codec jsoncodec.Codec) {
+		reader := bytes.NewReader(benchmarkChatBody)
+		b.SetBytes(int64(len(benchmarkChatBody)))
+		for i := 0; i < b.N; i++ {
+			reader.Reset(benchmarkChatBody)
+			var request ChatRequest
+			if err := codec.NewDecoder(reader).Decode(&request); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkEncodeChatResponse mide la escritura de la respuesta (el mismo
+// camino que writeJSONResponse)
+func BenchmarkEncodeChatResponse(b *testing.B) {
+	benchmarkCodecs(b, func(b *testing.B, codec jsoncodec.Codec) {
+		for i := 0; i < b.N; i++ {
+			if err := codec.NewEncoder(io.Discard).Encode(benchmarkChatResponse); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkEncodeStreamChunk mide un fragmento del streaming con un encoder
+// reutilizado, como sseWriter
+func BenchmarkEncodeStreamChunk(b *testing.B) {
+	chunk := &StreamChunkResponse{Content: " palabra", Model: "llama-3.3-70b-versatile"}
+	benchmarkCodecs(b, func(b *testing.B, codec jsoncodec.Codec) {
+		var buf bytes.Buffer
+		encoder := codec.NewEncoder(&buf)
+		for i := 0; i < b.N; i++ {
+			buf.Reset()
+			if err := encoder.Encode(chunk); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,31 @@
+// Package http - Registro de las peticiones en el watchdog de tareas colgadas
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"time"
+)
+
+// livenessMiddleware registra cada petición en el monitor de tareas colgadas
+// Su plazo es el de X-Request-Timeout (ver request_timeout.go) o, sin él, el
+// plazo por defecto del monitor. Con monitor nil no hace nada
+func livenessMiddleware(monitor domain.LivenessMonitor) func(http.Handler) http.Handler {
+	if monitor == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var timeout time.Duration
+			if deadline, ok := r.Context().Deadline(); ok {
+				timeout = time.Until(deadline)
+			}
+
+			done := monitor.Begin(r.Method+" "+r.URL.Path, timeout)
+			defer done()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Package http - Rechazo de peticiones con la réplica sobrecargada
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"strconv"
+)
+
+// loadSheddingRetryAfter son los segundos que se sugiere esperar: la
+// presión de memoria suele bajar en cuanto terminan las peticiones en curso
+const loadSheddingRetryAfter = 5
+
+// loadSheddingMiddleware responde 503 overloaded mientras la réplica está
+// sobrecargada (ver domain.LoadShedder); un balanceador puede reintentar en
+// otra réplica
+func loadSheddingMiddleware(shedder domain.LoadShedder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if shedder == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !shedder.Overloaded() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			response := NewErrorResponse("el servidor está sobrecargado, reintenta en unos segundos", http.StatusServiceUnavailable)
+			response.Type = "overloaded"
+			response.RetryAfter = loadSheddingRetryAfter
+			w.Header().Set("Retry-After", strconv.Itoa(loadSheddingRetryAfter))
+			writeJSONResponse(w, response, http.StatusServiceUnavailable)
+		})
+	}
+}
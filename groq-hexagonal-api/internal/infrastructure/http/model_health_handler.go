@@ -0,0 +1,45 @@
+// Package http - Handler HTTP de la salud de los modelos
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// ModelHealthHandler maneja el panel de salud de los modelos
+type ModelHealthHandler struct {
+	healthService domain.ModelHealthService
+}
+
+// NewModelHealthHandler crea un nuevo handler con el servicio inyectado
+func NewModelHealthHandler(service domain.ModelHealthService) *ModelHealthHandler {
+	if service == nil {
+		panic("modelHealthService no puede ser nil")
+	}
+
+	return &ModelHealthHandler{
+		healthService: service,
+	}
+}
+
+// HandleModelsHealth maneja GET /admin/models/health
+// Retorna tasa de error, latencia p95, estado del circuito y margen de rate
+// limit de cada modelo usado recientemente
+func (h *ModelHealthHandler) HandleModelsHealth(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleModelsHealth", r.Method, r.URL.Path)
+
+	models := h.healthService.GetModelsHealth(r.Context())
+	writeJSONResponse(w, NewModelsHealthResponse(models), http.StatusOK)
+}
+
+// requireAdminKey rechaza con 403 las peticiones sin la clave de administración
+func requireAdminKey(adminKey string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAdminKey(adminKey, r.Header.Get(AdminKeyHeader)) {
+			writeErrorResponse(w, "se requiere una clave de administración válida", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
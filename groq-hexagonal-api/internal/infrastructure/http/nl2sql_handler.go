@@ -0,0 +1,57 @@
+// Package http - Handler HTTP de traducción de lenguaje natural a SQL
+package http
+
+import (
+	"errors"
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// NL2SQLHandler maneja las peticiones HTTP de traducción a SQL
+type NL2SQLHandler struct {
+	nl2sqlService domain.NL2SQLService
+}
+
+// NewNL2SQLHandler crea un nuevo handler con el servicio inyectado
+func NewNL2SQLHandler(service domain.NL2SQLService) *NL2SQLHandler {
+	if service == nil {
+		panic("nl2sqlService no puede ser nil")
+	}
+
+	return &NL2SQLHandler{
+		nl2sqlService: service,
+	}
+}
+
+// HandleNL2SQL maneja POST /api/v1/nl2sql
+// Genera una sentencia SQL para la pregunta y el esquema, sin ejecutarla
+func (h *NL2SQLHandler) HandleNL2SQL(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleNL2SQL", r.Method, r.URL.Path)
+
+	var req NL2SQLRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	result, err := h.nl2sqlService.Translate(r.Context(), req.toDomain())
+	if err != nil {
+		switch {
+		case application.IsNL2SQLValidationError(err):
+			writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, application.ErrSQLMutationRejected):
+			// 422: la petición es válida, pero la consulta que resulta no se acepta
+			response := NewErrorResponse(err.Error(), http.StatusUnprocessableEntity)
+			response.Type = "mutation_rejected"
+			writeJSONResponse(w, response, http.StatusUnprocessableEntity)
+		default:
+			writeServiceError(w, err, "error al generar la consulta")
+		}
+		return
+	}
+	annotateAccessLog(r.Context(), result.Model, &result.Usage)
+
+	writeJSONResponse(w, NewNL2SQLResponse(result), http.StatusOK)
+}
@@ -0,0 +1,514 @@
+// Package http - Especificación OpenAPI generada desde los DTOs
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// OPENAPI
+// ============================================================================
+//
+// La especificación que sirve GET /openapi.json se construye al arrancar a
+// partir de los DTOs de este paquete (con reflection), así que no puede
+// quedar desfasada respecto al código:
+//
+//   - Las propiedades salen de las etiquetas `json` (omitempty = opcional)
+//   - Los ejemplos salen de las etiquetas `example`
+//   - Las operaciones salen de apiOperations (solo las de los handlers activos)
+//
+// GET /docs sirve Swagger UI apuntando a /openapi.json.
+//
+// api/openapi.yaml (la fuente de los SDKs) se escribe a mano, pero
+// openapi_test.go comprueba que sus operaciones y esquemas coinciden con esta.
+// ============================================================================
+
+// apiOperation describe un endpoint para la especificación
+type apiOperation struct {
+	method  string
+	path    string
+	tag     string
+	id      string
+	summary string
+
+	// request y response son DTOs de ejemplo (nil = sin body)
+	request  interface{}
+	response interface{}
+	status   int
+
+	// stream es el DTO de cada evento SSE, si el endpoint admite streaming
+	stream interface{}
+
+	// alternatives son otras respuestas posibles (oneOf), ej: la de dry_run
+	alternatives []interface{}
+}
+
+// multipartForm marca un request que se envía como multipart/form-data
+// (los campos salen del DTO igual que en JSON; los ficheros son FormFile)
+type multipartForm struct {
+	fields interface{}
+}
+
+// apiOperations retorna los endpoints de los handlers configurados
+func apiOperations(handlers Handlers) []apiOperation {
+	operations := []apiOperation{
+		{http.MethodPost, "/api/v1/chat", "chat", "chat", "Envía un mensaje al modelo (dry_run: sin llamar a Groq)",
+			ChatRequest{}, ChatResponse{}, http.StatusOK, StreamChunkResponse{}, []interface{}{DryRunResponse{}}},
+		{http.MethodGet, "/api/v1/models", "chat", "listModels", "Lista los modelos disponibles",
+			nil, ModelsResponse{}, http.StatusOK, nil, nil},
+		{http.MethodGet, "/api/v1/capabilities", "chat", "getCapabilities", "Funciones opcionales activas en este despliegue",
+			nil, CapabilitiesResponse{}, http.StatusOK, nil, nil},
+		{http.MethodGet, "/health", "health", "health", "Health check",
+			nil, HealthResponse{}, http.StatusOK, nil, nil},
+	}
+
+	if handlers.Chat.streams != nil {
+		operations = append(operations,
+			apiOperation{http.MethodGet, "/api/v1/chat/streams/{id}", "chat", "resumeChatStream", "Reanuda un flujo SSE interrumpido desde Last-Event-ID",
+				nil, nil, http.StatusOK, StreamChunkResponse{}, nil})
+	}
+
+	if handlers.Job != nil {
+		operations = append(operations,
+			apiOperation{http.MethodPost, "/api/v1/chat/async", "jobs", "submitChatJob", "Encola un mensaje y retorna el job sin esperar al modelo",
+				ChatJobRequest{}, JobResponse{}, http.StatusAccepted, nil, nil},
+			apiOperation{http.MethodGet, "/api/v1/jobs/{id}", "jobs", "getJob", "Estado y resultado de un chat asíncrono",
+				nil, JobResponse{}, http.StatusOK, nil, nil},
+			// El resultado es la respuesta del proveedor tal cual (choices, usage...)
+			apiOperation{http.MethodGet, "/api/v1/jobs/{id}/result", "jobs", "getJobResult", "Descarga la respuesta de un chat asíncrono terminado (admite Range)",
+				nil, map[string]interface{}{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodGet, "/api/v1/jobs/{id}/events", "jobs", "getJobEvents", "Sigue el estado y el progreso de un chat asíncrono (SSE)",
+				nil, nil, http.StatusOK, JobResponse{}, nil},
+		)
+	}
+	if handlers.Conversation != nil {
+		operations = append(operations,
+			apiOperation{http.MethodPost, "/api/v1/conversations", "conversations", "createConversation", "Crea una conversación",
+				ConversationSettingsRequest{}, ConversationResponse{}, http.StatusCreated, nil, nil},
+			apiOperation{http.MethodGet, "/api/v1/conversations/{id}", "conversations", "getConversation", "Obtiene una conversación con su historial",
+				nil, ConversationResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPatch, "/api/v1/conversations/{id}", "conversations", "pinConversationSettings", "Cambia los ajustes fijados",
+				ConversationSettingsRequest{}, ConversationResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodDelete, "/api/v1/conversations/{id}", "conversations", "deleteConversation", "Borra la conversación (recuperable)",
+				nil, ConversationResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPost, "/api/v1/conversations/{id}/messages", "conversations", "sendConversationMessage", "Envía un turno de la conversación",
+				ConversationMessageRequest{}, ConversationMessageResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPost, "/api/v1/conversations/{id}/restore", "conversations", "restoreConversation", "Restaura una conversación borrada",
+				nil, ConversationResponse{}, http.StatusOK, nil, nil},
+		)
+	}
+	if handlers.Prompt != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/prompts/improve", "prompts", "improvePrompt", "Propone una versión mejorada de un prompt",
+			ImprovePromptRequest{}, ImprovePromptResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Classification != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/classify", "classification", "classify", "Clasifica un texto en una de las etiquetas",
+			ClassifyRequest{}, ClassifyResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Redaction != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/redact", "redaction", "redact", "Sustituye los datos personales del texto por marcadores",
+			RedactRequest{}, RedactResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.NL2SQL != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/nl2sql", "nl2sql", "nl2sql", "Traduce una pregunta a SQL y rechaza las consultas que modifican datos",
+			NL2SQLRequest{}, NL2SQLResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Code != nil {
+		operations = append(operations,
+			apiOperation{http.MethodPost, "/api/v1/code/explain", "code", "explainCode", "Explica un fichero de código por fragmentos",
+				CodeRequest{}, CodeExplainResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPost, "/api/v1/code/review", "code", "reviewCode", "Revisa un fichero de código y lista los hallazgos por línea",
+				CodeRequest{}, CodeReviewResponse{}, http.StatusOK, nil, nil},
+		)
+	}
+	if handlers.Usage != nil {
+		operations = append(operations, apiOperation{http.MethodGet, "/api/v1/usage", "usage", "getUsage", "Consumo de tokens del cliente frente a su cuota diaria (?days=N: días anteriores)",
+			nil, UsageResponse{}, http.StatusOK, nil, nil})
+		operations = append(operations, apiOperation{http.MethodGet, "/api/v1/tenants/{id}/statements/{month}", "usage", "getTenantStatement", "Extracto mensual del tenant (month: AAAA-MM; ?format=pdf o Accept: application/pdf para PDF)",
+			nil, StatementResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Feedback != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/feedback", "feedback", "submitFeedback", "Valora una respuesta o una conversación (up/down y comentario)",
+			FeedbackRequest{}, FeedbackResponse{}, http.StatusCreated, nil, nil})
+	}
+	if handlers.Diff != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/diff", "diff", "diffResponses", "Compara las respuestas de dos configuraciones",
+			DiffRequest{}, DiffResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Proxy != nil {
+		// El proxy no tiene DTOs: el body es el de la API de OpenAI/Groq
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/proxy/chat/completions", "proxy", "proxyChatCompletions", "Reenvía la petición a Groq sin modificarla",
+			map[string]interface{}{}, map[string]interface{}{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Transcription != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/audio/transcriptions", "audio", "createTranscription", "Transcribe un fichero de audio (Whisper)",
+			multipartForm{TranscriptionForm{}}, TranscriptionResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Tokens != nil {
+		operations = append(operations, apiOperation{http.MethodPost, "/api/v1/token", "tokens", "issueToken", "Cambia la API key por un token de vida corta con parte de sus scopes",
+			TokenRequest{}, TokenResponse{}, http.StatusCreated, nil, nil})
+	}
+	if handlers.ModelHealth != nil {
+		operations = append(operations, apiOperation{http.MethodGet, "/admin/models/health", "admin", "modelsHealth", "Salud de los modelos: errores, latencia p95, circuito y rate limit (X-Admin-Key)",
+			nil, ModelsHealthResponse{}, http.StatusOK, nil, nil})
+	}
+	if handlers.Admin != nil {
+		operations = append(operations,
+			apiOperation{http.MethodGet, "/admin/config", "admin", "adminConfig", "Configuración con los secretos enmascarados (X-Admin-Key)",
+				nil, AdminConfigResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPost, "/admin/config/reload", "admin", "reloadConfig", "Recarga los ajustes en caliente, como SIGHUP (X-Admin-Key)",
+				nil, SettingsResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodGet, "/admin/cache", "admin", "cacheStats", "Estadísticas de la caché de respuestas (X-Admin-Key)",
+				nil, CacheStatsResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPost, "/admin/cache/flush", "admin", "flushCache", "Vacía la caché de respuestas (X-Admin-Key)",
+				nil, CacheFlushResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodGet, "/admin/circuits", "admin", "circuits", "Estado del circuit breaker de cada modelo (X-Admin-Key)",
+				nil, CircuitsResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodGet, "/admin/in-flight", "admin", "inFlight", "Peticiones a /api/v1 en curso (X-Admin-Key)",
+				nil, InFlightResponse{}, http.StatusOK, nil, nil},
+			// Los perfiles de /admin/debug/pprof/ no son JSON: no se describen aquí
+			apiOperation{http.MethodGet, "/admin/runtime", "admin", "runtimeStats", "Goroutines, heap y GC de la réplica (X-Admin-Key)",
+				nil, RuntimeResponse{}, http.StatusOK, nil, nil},
+		)
+		if handlers.Admin.liveness != nil {
+			operations = append(operations, apiOperation{http.MethodGet, "/admin/liveness", "admin", "liveness", "Peticiones y jobs colgados (X-Admin-Key)",
+				nil, LivenessResponse{}, http.StatusOK, nil, nil})
+		}
+		if handlers.Admin.groqKeys != nil {
+			operations = append(operations, apiOperation{http.MethodGet, "/admin/groq/keys", "admin", "groqKeys", "Peticiones, fallos y enfriamiento de cada API key de Groq (X-Admin-Key)",
+				nil, BackendsHealthResponse{}, http.StatusOK, nil, nil})
+		}
+	}
+	if handlers.APIKeys != nil {
+		operations = append(operations,
+			apiOperation{http.MethodPost, "/admin/keys", "admin", "createAPIKey", "Crea una API key de cliente; el secreto solo se muestra aquí (X-Admin-Key)",
+				CreateAPIKeyRequest{}, APIKeyResponse{}, http.StatusCreated, nil, nil},
+			apiOperation{http.MethodGet, "/admin/keys", "admin", "listAPIKeys", "Lista las API keys de cliente, sin secretos (X-Admin-Key)",
+				nil, APIKeysResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPost, "/admin/keys/{id}/disable", "admin", "disableAPIKey", "Desactiva una API key de cliente (X-Admin-Key)",
+				nil, APIKeyResponse{}, http.StatusOK, nil, nil},
+			apiOperation{http.MethodPost, "/admin/keys/{id}/rotate", "admin", "rotateAPIKey", "Cambia el secreto (y opcionalmente la caducidad) de una API key; el anterior vale durante la gracia (X-Admin-Key)",
+				RotateAPIKeyRequest{}, APIKeyResponse{}, http.StatusOK, nil, nil},
+		)
+	}
+	// El resumen va en /admin: solo existe con la clave de administración, como Admin
+	if handlers.Feedback != nil && handlers.Admin != nil {
+		operations = append(operations, apiOperation{http.MethodGet, "/admin/feedback", "admin", "feedbackSummary", "Resumen de las valoraciones por modelo y persona (?days=N, ?tenant=; X-Admin-Key)",
+			nil, FeedbackSummaryResponse{}, http.StatusOK, nil, nil})
+	}
+	return operations
+}
+
+// newOpenAPISpec construye la especificación en JSON
+func newOpenAPISpec(handlers Handlers) ([]byte, error) {
+	builder := &schemaBuilder{components: make(map[string]interface{})}
+	paths := make(map[string]map[string]interface{})
+
+	for _, op := range apiOperations(handlers) {
+		operation := map[string]interface{}{
+			"operationId": op.id,
+			"summary":     op.summary,
+			"tags":        []string{op.tag},
+			"parameters":  operationParameters(op.path),
+		}
+
+		if op.request != nil {
+			contentType, request := "application/json", op.request
+			if form, ok := op.request.(multipartForm); ok {
+				contentType, request = "multipart/form-data", form.fields
+			}
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content": map[string]interface{}{
+					contentType: map[string]interface{}{"schema": builder.schemaFor(reflect.TypeOf(request))},
+				},
+			}
+		}
+
+		// Sin response, el endpoint solo responde con eventos SSE
+		content := map[string]interface{}{}
+		if op.response != nil {
+			responseSchema := builder.schemaFor(reflect.TypeOf(op.response))
+			if len(op.alternatives) > 0 {
+				oneOf := []interface{}{responseSchema}
+				for _, alternative := range op.alternatives {
+					oneOf = append(oneOf, builder.schemaFor(reflect.TypeOf(alternative)))
+				}
+				responseSchema = map[string]interface{}{"oneOf": oneOf}
+			}
+			content["application/json"] = map[string]interface{}{"schema": responseSchema}
+		}
+		if op.stream != nil {
+			content["text/event-stream"] = map[string]interface{}{"schema": builder.schemaFor(reflect.TypeOf(op.stream))}
+		}
+		operation["responses"] = map[string]interface{}{
+			strconv.Itoa(op.status): map[string]interface{}{
+				"description": http.StatusText(op.status),
+				"content":     content,
+			},
+			"default": map[string]interface{}{
+				"description": "Error",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": builder.schemaFor(reflect.TypeOf(ErrorResponse{}))},
+				},
+			},
+		}
+
+		if paths[op.path] == nil {
+			paths[op.path] = make(map[string]interface{})
+		}
+		paths[op.path][strings.ToLower(op.method)] = operation
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "Groq Hexagonal API",
+			"version":     "1.0.0",
+			"description": "API REST para interactuar con Groq usando Arquitectura Hexagonal",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": builder.components},
+	})
+}
+
+// operationParameters retorna los parámetros comunes y los de la ruta ({id})
+func operationParameters(route string) []interface{} {
+	parameters := []interface{}{
+		map[string]interface{}{
+			"name":        TenantHeader,
+			"in":          "header",
+			"required":    false,
+			"description": "Tenant (cliente) de la petición",
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+	}
+	for _, segment := range strings.Split(route, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			parameters = append(parameters, map[string]interface{}{
+				"name":     strings.Trim(segment, "{}"),
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]interface{}{"type": "string"},
+			})
+		}
+	}
+	return parameters
+}
+
+// ============================================================================
+// ESQUEMAS DESDE LOS TIPOS DE GO
+// ============================================================================
+
+// schemaBuilder convierte tipos de Go en esquemas OpenAPI
+// Cada struct con nombre se registra una vez en components y se referencia con $ref
+type schemaBuilder struct {
+	components map[string]interface{}
+}
+
+var (
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	timeType       = reflect.TypeOf(time.Time{})
+	formFileType   = reflect.TypeOf(FormFile(""))
+)
+
+// schemaFor retorna el esquema de un tipo
+func (b *schemaBuilder) schemaFor(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == rawMessageType:
+		// JSON sin interpretar: cualquier valor
+		return map[string]interface{}{}
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == formFileType:
+		return map[string]interface{}{"type": "string", "format": "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return b.structRef(t)
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schemaFor(t.Elem())}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	default:
+		// interface{} y similares: cualquier valor
+		return map[string]interface{}{}
+	}
+}
+
+// structRef registra el struct en components y retorna su $ref
+func (b *schemaBuilder) structRef(t reflect.Type) map[string]interface{} {
+	name := schemaName(t)
+	if _, ok := b.components[name]; !ok {
+		// Se registra antes de construirlo: así los tipos recursivos terminan
+		b.components[name] = map[string]interface{}{}
+		b.components[name] = b.structSchema(t)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// schemaName es el nombre del struct en components
+// Los tipos de otros paquetes llevan su paquete delante (ej: DomainChatRequest),
+// para no chocar con los DTOs del mismo nombre
+func schemaName(t reflect.Type) string {
+	if t.PkgPath() == reflect.TypeOf(apiOperation{}).PkgPath() {
+		return t.Name()
+	}
+	pkg := path.Base(t.PkgPath())
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+}
+
+// structSchema construye el esquema de un struct a partir de sus campos
+func (b *schemaBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+	b.addFields(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// addFields añade las propiedades de t (y de sus structs embebidos)
+func (b *schemaBuilder) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Un struct embebido sin nombre JSON aporta sus campos al mismo nivel
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			b.addFields(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := b.schemaFor(field.Type)
+		if example, ok := parseExample(field); ok {
+			// En OpenAPI 3.0, $ref no admite propiedades hermanas
+			if _, isRef := schema["$ref"]; !isRef {
+				schema["example"] = example
+			}
+		}
+		properties[name] = schema
+
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// parseExample convierte la etiqueta `example` al tipo del campo
+func parseExample(field reflect.StructField) (interface{}, bool) {
+	raw, ok := field.Tag.Lookup("example")
+	if !ok {
+		return nil, false
+	}
+
+	t := field.Type
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == rawMessageType {
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			return raw, true
+		}
+		return value, true
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return value, true
+		}
+	case reflect.Float32, reflect.Float64:
+		if value, err := strconv.ParseFloat(raw, 64); err == nil {
+			return value, true
+		}
+	case reflect.Bool:
+		if value, err := strconv.ParseBool(raw); err == nil {
+			return value, true
+		}
+	case reflect.Slice:
+		// Una lista de ejemplo con un solo elemento (ej: stop: ["###"])
+		return []string{raw}, true
+	default:
+		return raw, true
+	}
+	return nil, false
+}
+
+// ============================================================================
+// HANDLERS
+// ============================================================================
+
+// openAPIHandler sirve la especificación ya serializada
+func openAPIHandler(spec []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}
+}
+
+// swaggerUIVersion es la versión de Swagger UI que carga /docs
+const swaggerUIVersion = "5.17.14"
+
+// swaggerUIPage es la página de /docs
+// Los estáticos de Swagger UI se cargan de un CDN: el binario solo lleva esta página
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="utf-8">
+  <title>Groq Hexagonal API - Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>`
+
+// handleDocs sirve Swagger UI
+func handleDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(swaggerUIPage))
+}
@@ -0,0 +1,260 @@
+package http
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ============================================================================
+// api/openapi.yaml FRENTE A LOS DTOs
+// ============================================================================
+//
+// api/openapi.yaml (la fuente de los SDKs) se escribe a mano: lleva las
+// descripciones, cabeceras y códigos de estado que no salen de los DTOs. Lo
+// que sí sale de ellos (las operaciones y las propiedades de cada esquema) lo
+// construye newOpenAPISpec, y este test falla si los dos no coinciden:
+//
+//   - cada operación fuera de /admin está en el YAML con el mismo operationId,
+//     y el YAML no tiene operaciones que la API no sirve
+//   - cada esquema de esas operaciones tiene en el YAML las mismas propiedades
+//
+// make check-openapi lo ejecuta solo.
+// ============================================================================
+
+// openAPIDocumentPath es el YAML respecto a este paquete
+const openAPIDocumentPath = "../../../api/openapi.yaml"
+
+// openAPIMethods son las claves de un path que son operaciones
+var openAPIMethods = map[string]bool{
+	"get": true, "post": true, "put": true, "patch": true, "delete": true,
+}
+
+// allHandlers activa todos los handlers: apiOperations solo mira cuáles hay
+func allHandlers() Handlers {
+	return Handlers{
+		Chat:           &ChatHandler{streams: &streamSessions{}},
+		Conversation:   &ConversationHandler{},
+		Job:            &JobHandler{},
+		Prompt:         &PromptHandler{},
+		Classification: &ClassificationHandler{},
+		Redaction:      &RedactionHandler{},
+		NL2SQL:         &NL2SQLHandler{},
+		Code:           &CodeHandler{},
+		Usage:          &UsageHandler{},
+		Feedback:       &FeedbackHandler{},
+		Diff:           &DiffHandler{},
+		Proxy:          &ProxyHandler{},
+		Transcription:  &TranscriptionHandler{},
+		Tokens:         &TokenHandler{},
+	}
+}
+
+// openAPIDocument es lo que se compara de una especificación
+type openAPIDocument struct {
+	Paths      map[string]map[string]interface{} `json:"paths" yaml:"paths"`
+	Components struct {
+		Schemas map[string]map[string]interface{} `json:"schemas" yaml:"schemas"`
+	} `json:"components" yaml:"components"`
+}
+
+func TestOpenAPIDocumentMatchesDTOs(t *testing.T) {
+	spec, err := newOpenAPISpec(allHandlers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generated openAPIDocument
+	if err := json.Unmarshal(spec, &generated); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(openAPIDocumentPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var documented openAPIDocument
+	if err := yaml.Unmarshal(raw, &documented); err != nil {
+		t.Fatalf("%s: %v", openAPIDocumentPath, err)
+	}
+
+	// Operaciones: las de la API en el YAML y viceversa
+	generatedOps := operationIDs(generated)
+	documentedOps := operationIDs(documented)
+	for route, id := range generatedOps {
+		if strings.HasPrefix(route, "/admin") {
+			continue
+		}
+		switch documentedID, ok := documentedOps[route]; {
+		case !ok:
+			t.Errorf("%s: falta en %s", route, openAPIDocumentPath)
+		case documentedID != id:
+			t.Errorf("%s: operationId %q en el YAML, %q en la API", route, documentedID, id)
+		}
+	}
+	for route := range documentedOps {
+		if _, ok := generatedOps[route]; !ok {
+			t.Errorf("%s: está en %s pero la API no la sirve", route, openAPIDocumentPath)
+		}
+	}
+
+	// Esquemas: los de las operaciones documentadas, propiedad a propiedad
+	// (el YAML puede definir en línea lo que aquí es un struct con nombre)
+	for _, name := range referencedSchemas(generated, documentedOps) {
+		schema, ok := documented.Components.Schemas[name]
+		if !ok {
+			t.Errorf("esquema %s: falta en %s", name, openAPIDocumentPath)
+			continue
+		}
+		compareSchemas(t, name, generated, generated.Components.Schemas[name], documented, schema, map[string]bool{})
+	}
+}
+
+// compareSchemas compara las propiedades de dos esquemas y, recursivamente,
+// las de sus propiedades, elementos y valores
+// Los objetos libres (sin properties) en cualquiera de los dos no se comparan
+// visiting son los esquemas con nombre que se están comparando: un tipo
+// recursivo se compara una vez
+func compareSchemas(
+	t *testing.T,
+	where string,
+	genDoc openAPIDocument, gen map[string]interface{},
+	docDoc openAPIDocument, doc map[string]interface{},
+	visiting map[string]bool,
+) {
+	t.Helper()
+	if ref, ok := gen["$ref"].(string); ok {
+		if visiting[ref] {
+			return
+		}
+		visiting[ref] = true
+		defer delete(visiting, ref)
+	}
+	gen, doc = resolveSchema(genDoc, gen), resolveSchema(docDoc, doc)
+
+	for _, key := range []string{"items", "additionalProperties"} {
+		genChild, genOK := gen[key].(map[string]interface{})
+		docChild, docOK := doc[key].(map[string]interface{})
+		if genOK && docOK {
+			compareSchemas(t, where+"["+key+"]", genDoc, genChild, docDoc, docChild, visiting)
+		}
+	}
+
+	want, got := schemaProperties(genDoc, gen), schemaProperties(docDoc, doc)
+	if len(want) == 0 || len(got) == 0 {
+		return
+	}
+	if missing := difference(want, got); len(missing) > 0 {
+		t.Errorf("esquema %s: faltan en el YAML %v", where, missing)
+	}
+	if extra := difference(got, want); len(extra) > 0 {
+		t.Errorf("esquema %s: el YAML tiene de más %v", where, extra)
+	}
+	for name, genChild := range want {
+		if docChild, ok := got[name]; ok {
+			compareSchemas(t, where+"."+name, genDoc, genChild, docDoc, docChild, visiting)
+		}
+	}
+}
+
+// operationIDs mapea "MÉTODO ruta" → operationId
+func operationIDs(doc openAPIDocument) map[string]string {
+	ids := make(map[string]string)
+	for route, item := range doc.Paths {
+		for method, value := range item {
+			if !openAPIMethods[method] {
+				continue
+			}
+			operation, _ := value.(map[string]interface{})
+			id, _ := operation["operationId"].(string)
+			ids[route+" "+strings.ToUpper(method)] = id
+		}
+	}
+	return ids
+}
+
+// referencedSchemas retorna los esquemas que usan directamente las
+// operaciones de routes, en orden alfabético (los anidados los recorre
+// compareSchemas)
+func referencedSchemas(doc openAPIDocument, routes map[string]string) []string {
+	seen := make(map[string]bool)
+	var visit func(value interface{})
+	visit = func(value interface{}) {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if ref, ok := value["$ref"].(string); ok {
+				seen[strings.TrimPrefix(ref, "#/components/schemas/")] = true
+			}
+			for _, child := range value {
+				visit(child)
+			}
+		case []interface{}:
+			for _, child := range value {
+				visit(child)
+			}
+		}
+	}
+
+	for route, item := range doc.Paths {
+		for method, operation := range item {
+			if _, ok := routes[route+" "+strings.ToUpper(method)]; ok {
+				visit(operation)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// resolveSchema sigue el $ref de un esquema (si lo tiene)
+func resolveSchema(doc openAPIDocument, schema map[string]interface{}) map[string]interface{} {
+	for {
+		ref, ok := schema["$ref"].(string)
+		if !ok {
+			return schema
+		}
+		schema = doc.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	}
+}
+
+// schemaProperties retorna las propiedades de un esquema, incluidas las de
+// los que compone con allOf
+func schemaProperties(doc openAPIDocument, schema map[string]interface{}) map[string]map[string]interface{} {
+	schema = resolveSchema(doc, schema)
+	properties := make(map[string]map[string]interface{})
+	if own, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, property := range own {
+			property, _ := property.(map[string]interface{})
+			properties[name] = property
+		}
+	}
+	parts, _ := schema["allOf"].([]interface{})
+	for _, part := range parts {
+		if part, ok := part.(map[string]interface{}); ok {
+			for name, property := range schemaProperties(doc, part) {
+				properties[name] = property
+			}
+		}
+	}
+	return properties
+}
+
+// difference retorna las propiedades de a que no están en b, ordenadas
+func difference(a, b map[string]map[string]interface{}) []string {
+	var missing []string
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
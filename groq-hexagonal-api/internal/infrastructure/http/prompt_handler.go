@@ -0,0 +1,64 @@
+// Package http - Handlers HTTP de ayuda con prompts
+package http
+
+import (
+	"errors"
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// PromptHandler maneja las peticiones HTTP de ayuda con prompts
+type PromptHandler struct {
+	promptService domain.PromptService
+}
+
+// NewPromptHandler crea un nuevo handler con el servicio inyectado
+func NewPromptHandler(service domain.PromptService) *PromptHandler {
+	if service == nil {
+		panic("promptService no puede ser nil")
+	}
+
+	return &PromptHandler{
+		promptService: service,
+	}
+}
+
+// HandleImprove maneja POST /api/v1/prompts/improve
+// Propone una versión mejorada del prompt, sugerencias y variantes
+func (h *PromptHandler) HandleImprove(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleImprovePrompt", r.Method, r.URL.Path)
+
+	var req ImprovePromptRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	improvement, err := h.promptService.ImprovePrompt(r.Context(), domain.PromptImprovementRequest{
+		Prompt:         req.Prompt,
+		DesiredOutcome: req.DesiredOutcome,
+		Variants:       req.Variants,
+	})
+	if err != nil {
+		// Los errores de validación propios de este caso de uso van aparte;
+		// el resto (429 de Groq, modelo inexistente...) se traduce igual que en /chat
+		if errors.Is(err, application.ErrEmptyPrompt) || errors.Is(err, application.ErrInvalidVariants) {
+			writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServiceError(w, err, "error al mejorar el prompt")
+		return
+	}
+	annotateAccessLog(r.Context(), improvement.Model, &improvement.Usage)
+
+	writeJSONResponse(w, &ImprovePromptResponse{
+		Success:        true,
+		ImprovedPrompt: improvement.ImprovedPrompt,
+		Suggestions:    improvement.Suggestions,
+		Variants:       improvement.Variants,
+		Model:          improvement.Model,
+		Usage:          NewUsageInfo(improvement.Usage),
+	}, http.StatusOK)
+}
@@ -0,0 +1,92 @@
+// Package http - Handler HTTP del modo proxy (passthrough)
+package http
+
+import (
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// maxProxyBodyBytes limita el tamaño del body reenviado a Groq
+const maxProxyBodyBytes = 4 << 20 // 4 MiB
+
+// proxyCopyBufferSize es el tamaño de los trozos copiados hacia el cliente
+const proxyCopyBufferSize = 32 << 10 // 32 KiB
+
+// ProxyHandler maneja las peticiones del modo proxy
+type ProxyHandler struct {
+	proxyService domain.ProxyService
+}
+
+// NewProxyHandler crea un nuevo handler con el servicio inyectado
+func NewProxyHandler(service domain.ProxyService) *ProxyHandler {
+	if service == nil {
+		panic("proxyService no puede ser nil")
+	}
+
+	return &ProxyHandler{
+		proxyService: service,
+	}
+}
+
+// HandleChatCompletions maneja POST /api/v1/proxy/chat/completions
+// Reenvía el body (formato OpenAI) a Groq y devuelve su respuesta sin tocar
+func (h *ProxyHandler) HandleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleProxyChatCompletions", r.Method, r.URL.Path)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxProxyBodyBytes))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeErrorResponse(w, "el body supera el tamaño máximo", http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeErrorResponse(w, "error al leer el body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Los errores del servicio son los nuestros (body inválido, Groq inalcanzable);
+	// los errores HTTP de Groq llegan en response y se reenvían tal cual
+	response, err := h.proxyService.ChatCompletions(r.Context(), body)
+	if err != nil {
+		writeServiceError(w, err, "error al reenviar la petición")
+		return
+	}
+	defer response.Body.Close()
+
+	for name, values := range response.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	// Igual que en el streaming de /chat: el WriteTimeout cortaría los flujos largos
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("No se pudo quitar el write deadline: %v", err)
+	}
+	w.WriteHeader(response.StatusCode)
+
+	// Copiar y hacer flush de cada trozo: con SSE el cliente recibe los
+	// fragmentos en cuanto Groq los envía
+	buffer := make([]byte, proxyCopyBufferSize)
+	for {
+		n, readErr := response.Body.Read(buffer)
+		if n > 0 {
+			if _, err := w.Write(buffer[:n]); err != nil {
+				// El cliente se fue: no hay a quién seguir enviando
+				return
+			}
+			rc.Flush()
+		}
+		if readErr != nil {
+			if !errors.Is(readErr, io.EOF) && r.Context().Err() == nil {
+				log.Printf("Error durante el proxy: %v", readErr)
+			}
+			return
+		}
+	}
+}
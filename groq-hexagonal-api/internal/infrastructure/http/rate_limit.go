@@ -0,0 +1,260 @@
+// Package http - Límite de peticiones por cliente
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// RATE LIMIT
+// ============================================================================
+//
+// Cada cliente puede hacer RateLimitOptions.Limit peticiones por ventana. El
+// cliente es la API key autenticada y, sin autenticación, la IP: las
+// cabeceras que el cliente elige (X-Tenant-ID, una Authorization que nadie
+// ha comprobado) no sirven, porque cambiarlas en cada petición daría un
+// contador nuevo. Al superar el límite se responde 429 con Retry-After,
+// igual que cuando el límite lo pone Groq.
+//
+// El contador es un puerto (domain.RateCounter): en memoria con una réplica,
+// en Redis con varias.
+//
+// Además, cada cliente puede tener como máximo MaxConcurrent peticiones en
+// curso (incluido el streaming); las demás reciben 429 sin esperar. Este
+// límite es de cada réplica.
+//
+// Los dos límites pueden cambiar por franja horaria (domain.ThrottleSchedule):
+// se evalúan en cada petición con la hora actual.
+//
+// Con ?wait=true la petición espera en lugar de recibir 429 (ver
+// rate_limit_wait.go).
+// ============================================================================
+
+// Cabeceras informativas del rate limit
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+
+	// RateLimitScheduleHeader es la franja horaria aplicada (si hay alguna)
+	RateLimitScheduleHeader = "X-RateLimit-Schedule"
+)
+
+// RateLimitOptions configura el rate limit (Limit = 0 lo desactiva)
+type RateLimitOptions struct {
+	Counter domain.RateCounter
+	Limit   int
+	Window  time.Duration
+
+	// MaxConcurrent son las peticiones simultáneas por cliente (0 = sin límite)
+	MaxConcurrent int
+
+	// Schedule cambia los límites por franja horaria
+	Schedule domain.ThrottleSchedule
+
+	// Settings, si no es nil, sustituye a Limit, Window, MaxConcurrent y
+	// Schedule: se leen en cada petición para que una recarga de la
+	// configuración los cambie
+	Settings domain.SettingsSource
+
+	// MaxWait es la espera máxima con ?wait=true y WaitQueue las peticiones
+	// que pueden esperar a la vez (cualquiera de los dos a 0 la desactiva)
+	MaxWait   time.Duration
+	WaitQueue int
+
+	// WriteTimeout es el WriteTimeout del servidor: una petición que ha
+	// esperado lo recibe de nuevo al salir de la cola (0 = no se renueva)
+	WriteTimeout time.Duration
+}
+
+// current retorna los límites vigentes
+func (o RateLimitOptions) current() domain.ClientLimits {
+	now := time.Now()
+	if o.Settings != nil {
+		return o.Settings.Settings().ClientLimitsAt(now)
+	}
+	return domain.RuntimeSettings{
+		RateLimitRequests:     o.Limit,
+		RateLimitWindow:       o.Window,
+		MaxConcurrentRequests: o.MaxConcurrent,
+		ThrottleSchedule:      o.Schedule,
+	}.ClientLimitsAt(now)
+}
+
+// disabled indica si ningún límite puede llegar a aplicarse
+func (o RateLimitOptions) disabled() bool {
+	// Con ajustes recargables los límites pueden activarse más tarde
+	return o.Settings == nil && o.Limit <= 0 && o.MaxConcurrent <= 0 && len(o.Schedule.Windows) == 0
+}
+
+// rateLimitMiddleware rechaza las peticiones que superan el límite
+func rateLimitMiddleware(options RateLimitOptions) func(http.Handler) http.Handler {
+	// Fuera del closure: mux llama a la función en cada petición y las
+	// peticiones en curso se tienen que contar todas en el mismo sitio
+	concurrency := newConcurrencyLimiter()
+	var waitQueue chan struct{}
+	if options.WaitQueue > 0 {
+		waitQueue = make(chan struct{}, options.WaitQueue)
+	}
+
+	return func(next http.Handler) http.Handler {
+		if options.disabled() {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Un operador puede saltarse el límite para depurar (X-Debug-Overrides)
+			if domain.DebugOverridesFromContext(r.Context()).BypassRateLimit {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			limits := options.current()
+			key := rateLimitKey(r)
+			if limits.Window != "" {
+				w.Header().Set(RateLimitScheduleHeader, limits.Window)
+			}
+
+			// Con wait=true se espera en lugar de responder 429
+			waiter, err := newRequestWaiter(r, waitQueue, options.MaxWait, options.WriteTimeout)
+			if err != nil {
+				writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer waiter.leave(w, r)
+
+			if limits.RateLimitRequests > 0 && options.Counter != nil {
+				for {
+					allowed, resetIn := allowRequest(w, r, options.Counter, key, limits)
+					if allowed {
+						break
+					}
+					if !waiter.sleep(r.Context(), resetIn) {
+						writeRateLimited(w, "límite de peticiones superado", resetIn)
+						return
+					}
+				}
+			}
+
+			if limits.MaxConcurrent > 0 {
+				for {
+					acquired, released := concurrency.acquire(key, limits.MaxConcurrent)
+					if acquired {
+						break
+					}
+					if !waiter.waitFor(r.Context(), released) {
+						writeRateLimited(w, "límite de peticiones simultáneas superado", time.Second)
+						return
+					}
+				}
+				defer concurrency.release(key)
+			}
+
+			// La cola de espera es para las peticiones aparcadas, no para las
+			// que ya se están procesando
+			waiter.leave(w, r)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// allowRequest cuenta la petición en la ventana del cliente
+// Si supera el límite retorna false y el tiempo hasta la ventana siguiente
+func allowRequest(
+	w http.ResponseWriter,
+	r *http.Request,
+	counter domain.RateCounter,
+	key string,
+	limits domain.ClientLimits,
+) (bool, time.Duration) {
+	count, resetIn, err := counter.Increment(r.Context(), key, limits.RateLimitWindow)
+	if err != nil {
+		// Si el contador no responde (ej: Redis caído) se deja pasar:
+		// es preferible a tumbar toda la API
+		log.Printf("⚠️  Rate limit no disponible: %v", err)
+		return true, 0
+	}
+
+	remaining := int64(limits.RateLimitRequests) - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	w.Header().Set(RateLimitLimitHeader, strconv.Itoa(limits.RateLimitRequests))
+	w.Header().Set(RateLimitRemainingHeader, strconv.FormatInt(remaining, 10))
+
+	return count <= int64(limits.RateLimitRequests), resetIn
+}
+
+// writeRateLimited responde 429 rate_limited con Retry-After
+func writeRateLimited(w http.ResponseWriter, message string, retryAfter time.Duration) {
+	response := NewErrorResponse(message, http.StatusTooManyRequests)
+	response.Type = "rate_limited"
+	response.RetryAfter = int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(response.RetryAfter))
+	writeJSONResponse(w, response, http.StatusTooManyRequests)
+}
+
+// concurrencyLimiter cuenta las peticiones en curso de cada cliente
+type concurrencyLimiter struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+
+	// released se cierra (y se sustituye) cada vez que se libera un hueco,
+	// para despertar a las peticiones que esperan
+	released chan struct{}
+}
+
+// newConcurrencyLimiter crea el contador de peticiones en curso
+func newConcurrencyLimiter() *concurrencyLimiter {
+	return &concurrencyLimiter{
+		inFlight: make(map[string]int),
+		released: make(chan struct{}),
+	}
+}
+
+// acquire reserva un hueco para el cliente si tiene menos de limit en curso
+// Si no hay hueco, retorna el canal que se cierra al liberarse alguno
+func (l *concurrencyLimiter) acquire(key string, limit int) (bool, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inFlight[key] >= limit {
+		return false, l.released
+	}
+	l.inFlight[key]++
+	return true, nil
+}
+
+// release libera el hueco (y la entrada del cliente si ya no tiene ninguna)
+func (l *concurrencyLimiter) release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight[key]--
+	if l.inFlight[key] <= 0 {
+		delete(l.inFlight, key)
+	}
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+// rateLimitKey identifica al cliente: la API key autenticada o la IP
+func rateLimitKey(r *http.Request) string {
+	// Con autenticación, el ID de la clave: sobrevive a las rotaciones y el
+	// cliente no puede cambiarlo con la cabecera del tenant
+	if key := domain.APIKeyFromContext(r.Context()); key != nil {
+		return "apikey:" + key.ID
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
@@ -0,0 +1,169 @@
+// Package http - Espera en lugar de 429 (wait=true)
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// ESPERA EN EL RATE LIMIT
+// ============================================================================
+//
+// Un cliente batch suele preferir esperar a reintentar. Con ?wait=true, una
+// petición que supera el rate limit o las peticiones simultáneas no recibe
+// 429: espera (aparcada) a que se abra la ventana siguiente o se libere un
+// hueco, y entonces se procesa.
+//
+//   - ?max_wait=N limita la espera a N segundos (por defecto, y como máximo,
+//     RateLimitOptions.MaxWait). Si la ventana se abre después del plazo, se
+//     responde 429 enseguida, sin esperar en balde
+//   - Las peticiones aparcadas a la vez en la réplica están limitadas
+//     (RateLimitOptions.WaitQueue): con la cola llena se responde 429
+//   - La respuesta de una petición que ha esperado lleva X-RateLimit-Waited
+//     con los milisegundos de espera
+//   - El WriteTimeout del servidor cuenta desde que se leyó la petición, así
+//     que la espera se comería el plazo para escribir la respuesta (el chat se
+//     haría y se cobraría, pero el cliente vería la conexión cortada). Al salir
+//     de la cola el plazo vuelve a empezar (RateLimitOptions.WriteTimeout),
+//     sin acortar nunca el de X-Request-Timeout
+//
+// No hay orden estricto de llegada: cuando se libera un hueco lo ocupa la
+// primera petición que lo encuentra.
+// ============================================================================
+
+// RateLimitWaitedHeader son los milisegundos que la petición estuvo aparcada
+const RateLimitWaitedHeader = "X-RateLimit-Waited"
+
+// errInvalidMaxWait se retorna si max_wait no es un número de segundos
+var errInvalidMaxWait = errors.New("max_wait debe ser un entero mayor o igual a 0")
+
+// requestWaiter controla la espera de una petición con wait=true
+type requestWaiter struct {
+	// queue son los huecos de la cola de espera (nil = sin espera)
+	queue chan struct{}
+
+	start    time.Time
+	deadline time.Time
+
+	// writeTimeout es el plazo para escribir la respuesta una vez fuera de
+	// la cola (0 = se deja el del servidor)
+	writeTimeout time.Duration
+
+	// parked indica si la petición ocupa un hueco de la cola
+	parked bool
+}
+
+// newRequestWaiter lee wait y max_wait de la petición
+// Retorna nil si el cliente no pide esperar o la espera está desactivada;
+// un max_wait inválido es un error de la petición
+func newRequestWaiter(r *http.Request, queue chan struct{}, maxWait, writeTimeout time.Duration) (*requestWaiter, error) {
+	query := r.URL.Query()
+	if queue == nil || maxWait <= 0 || query.Get("wait") != "true" {
+		return nil, nil
+	}
+
+	wait := maxWait
+	if value := query.Get("max_wait"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return nil, errInvalidMaxWait
+		}
+		wait = min(time.Duration(seconds)*time.Second, maxWait)
+	}
+
+	now := time.Now()
+	return &requestWaiter{queue: queue, start: now, deadline: now.Add(wait), writeTimeout: writeTimeout}, nil
+}
+
+// sleep espera d si cabe en el plazo; retorna false si no se ha esperado
+func (w *requestWaiter) sleep(ctx context.Context, d time.Duration) bool {
+	if w == nil || time.Now().Add(d).After(w.deadline) || !w.park() {
+		return false
+	}
+	// Un contador sin caducidad conocida no debe dejar la petición en un bucle
+	d = max(d, 10*time.Millisecond)
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// waitFor espera a que se cierre released, como mucho hasta el plazo
+// Retorna false si se acaba el plazo (o el cliente se va) antes
+func (w *requestWaiter) waitFor(ctx context.Context, released <-chan struct{}) bool {
+	if w == nil {
+		return false
+	}
+	remaining := time.Until(w.deadline)
+	if remaining <= 0 || !w.park() {
+		return false
+	}
+
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-released:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// park ocupa un hueco de la cola (una vez por petición)
+// Retorna false si la cola está llena
+func (w *requestWaiter) park() bool {
+	if w.parked {
+		return true
+	}
+	select {
+	case w.queue <- struct{}{}:
+		w.parked = true
+		return true
+	default:
+		return false
+	}
+}
+
+// leave libera el hueco de la cola, anota la espera en la respuesta y
+// renueva el plazo para escribirla
+// Se puede llamar varias veces
+func (w *requestWaiter) leave(rw http.ResponseWriter, r *http.Request) {
+	if w == nil || !w.parked {
+		return
+	}
+	<-w.queue
+	w.parked = false
+	rw.Header().Set(RateLimitWaitedHeader, strconv.FormatInt(time.Since(w.start).Milliseconds(), 10))
+
+	if w.writeTimeout <= 0 {
+		return
+	}
+	deadline := time.Now().Add(w.writeTimeout)
+	if timeout, ok := r.Context().Deadline(); ok {
+		// requestTimeoutMiddleware ya fijó uno para X-Request-Timeout
+		deadline = maxTime(deadline, timeout.Add(requestTimeoutWriteMargin))
+	}
+	if err := http.NewResponseController(rw).SetWriteDeadline(deadline); err != nil {
+		log.Printf("No se pudo ajustar el write deadline: %v", err)
+	}
+}
+
+// maxTime retorna el más tardío de a y b
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
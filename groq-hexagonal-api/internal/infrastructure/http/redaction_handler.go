@@ -0,0 +1,53 @@
+// Package http - Handler HTTP de anonimización de datos personales
+package http
+
+import (
+	"errors"
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// RedactionHandler maneja las peticiones HTTP de anonimización
+type RedactionHandler struct {
+	redactionService domain.RedactionService
+}
+
+// NewRedactionHandler crea un nuevo handler con el servicio inyectado
+func NewRedactionHandler(service domain.RedactionService) *RedactionHandler {
+	if service == nil {
+		panic("redactionService no puede ser nil")
+	}
+
+	return &RedactionHandler{
+		redactionService: service,
+	}
+}
+
+// HandleRedact maneja POST /api/v1/redact
+// Sustituye los datos personales del texto por marcadores ([EMAIL_1]...)
+func (h *RedactionHandler) HandleRedact(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleRedact", r.Method, r.URL.Path)
+
+	var req RedactRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	result, err := h.redactionService.Redact(r.Context(), domain.RedactionRequest{
+		Text:  req.Text,
+		Types: req.Types,
+	})
+	if err != nil {
+		if errors.Is(err, application.ErrEmptyRedactionText) || errors.Is(err, application.ErrUnknownPIIType) {
+			writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeServiceError(w, err, "error al anonimizar el texto")
+		return
+	}
+
+	writeJSONResponse(w, NewRedactResponse(result), http.StatusOK)
+}
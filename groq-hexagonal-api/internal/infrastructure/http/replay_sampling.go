@@ -0,0 +1,135 @@
+// Package http - Muestreo de peticiones para el corpus de reproducción
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// ============================================================================
+// MUESTREO PARA REPRODUCCIÓN
+// ============================================================================
+//
+// En las peticiones que elige el domain.ReplaySampler, el middleware copia el
+// body y, si la respuesta es JSON, también la respuesta; al terminar pasa la
+// muestra al sampler, que la anonimiza y la guarda. El resto de peticiones no
+// se tocan. Las respuestas en streaming se guardan sin el texto (el modelo,
+// los tokens y la latencia sí, apuntados por el handler en el access log).
+//
+// Va dentro de Idempotency-Key: los duplicados que reciben la primera
+// respuesta no se muestrean otra vez.
+// ============================================================================
+
+// maxSampledResponseBytes es lo máximo que se copia de una respuesta JSON
+// (más larga, la muestra se guarda sin el texto)
+const maxSampledResponseBytes = 1 << 20
+
+// replaySamplingMiddleware guarda una muestra de las peticiones en el corpus
+// (sampler nil = desactivado)
+func replaySamplingMiddleware(sampler domain.ReplaySampler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if sampler == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !sampler.ShouldSample() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeDecodeError(w, err)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			started := time.Now()
+			recorder := &samplingRecorder{ResponseWriter: w}
+			next.ServeHTTP(recorder, r)
+
+			model, usage := annotatedUsage(r.Context())
+			sample := domain.ReplaySample{
+				Path:     r.URL.Path,
+				TenantID: domain.TenantFromContext(r.Context()),
+				Request:  body,
+				Baseline: domain.ReplayBaseline{
+					StatusCode: recorder.statusCode(),
+					Model:      model,
+					Message:    recorder.message(),
+					Usage:      usage,
+					LatencyMS:  time.Since(started).Milliseconds(),
+				},
+			}
+			// La muestra se guarda aunque el cliente se haya ido
+			if err := sampler.Record(context.WithoutCancel(r.Context()), sample); err != nil {
+				log.Printf("⚠️  %v", err)
+			}
+		})
+	}
+}
+
+// samplingRecorder copia el status y, si es JSON, la respuesta
+type samplingRecorder struct {
+	http.ResponseWriter
+
+	status int
+	json   bool
+	body   bytes.Buffer
+}
+
+// WriteHeader guarda el status y decide si se copia la respuesta
+func (rec *samplingRecorder) WriteHeader(statusCode int) {
+	if rec.status == 0 {
+		rec.status = statusCode
+		mediaType, _, _ := mime.ParseMediaType(rec.Header().Get("Content-Type"))
+		rec.json = mediaType == "application/json"
+	}
+	rec.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write copia la respuesta JSON hasta maxSampledResponseBytes
+func (rec *samplingRecorder) Write(data []byte) (int, error) {
+	if rec.status == 0 {
+		rec.WriteHeader(http.StatusOK)
+	}
+	if rec.json && rec.body.Len() <= maxSampledResponseBytes {
+		rec.body.Write(data)
+	}
+	return rec.ResponseWriter.Write(data)
+}
+
+// Unwrap permite que http.ResponseController llegue al writer original
+// (Flush en streaming)
+func (rec *samplingRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// statusCode retorna el status enviado (200 si el handler no escribió nada)
+func (rec *samplingRecorder) statusCode() int {
+	if rec.status == 0 {
+		return http.StatusOK
+	}
+	return rec.status
+}
+
+// message retorna el texto de una respuesta correcta ("" si no es JSON, es
+// un error o no cupo entera)
+func (rec *samplingRecorder) message() string {
+	if !rec.json || rec.statusCode() != http.StatusOK || rec.body.Len() > maxSampledResponseBytes {
+		return ""
+	}
+	var response ChatResponse
+	if err := json.Unmarshal(rec.body.Bytes(), &response); err != nil {
+		return ""
+	}
+	return response.Message
+}
@@ -0,0 +1,81 @@
+// Package http - Plazo por petición (X-Request-Timeout)
+package http
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// PLAZO POR PETICIÓN
+// ============================================================================
+//
+// Por defecto, cada llamada al proveedor tiene el timeout global
+// (HTTP_TIMEOUT, 30s). Un cliente que prefiere fallar rápido, o que sabe que
+// su petición es larga, lo indica en segundos:
+//
+//   X-Request-Timeout: 5
+//
+// El middleware lo convierte en un context.WithTimeout; los clientes de los
+// proveedores usan ese plazo en lugar del global (ver
+// groq.GroqClient.requestClient). Un plazo mayor que el máximo del servidor
+// se recorta a ese máximo. Al vencer, la respuesta es un 504.
+// ============================================================================
+
+// RequestTimeoutHeader contiene el plazo pedido por el cliente, en segundos
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// requestTimeoutWriteMargin es el tiempo para escribir la respuesta (el 504)
+// una vez vencido el plazo
+const requestTimeoutWriteMargin = 5 * time.Second
+
+// requestTimeoutMiddleware aplica X-Request-Timeout, como mucho max
+// Con max 0 la cabecera se ignora
+func requestTimeoutMiddleware(max time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(RequestTimeoutHeader)
+			if header == "" || max <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			timeout, err := parseRequestTimeout(header, max)
+			if err != nil {
+				writeErrorResponse(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			// El WriteTimeout del servidor cortaría un plazo más largo que él
+			deadline := time.Now().Add(timeout + requestTimeoutWriteMargin)
+			if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil {
+				log.Printf("No se pudo ajustar el write deadline: %v", err)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// parseRequestTimeout interpreta el plazo en segundos (admite decimales)
+// y lo recorta a max
+func parseRequestTimeout(value string, max time.Duration) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(seconds) || seconds <= 0 {
+		return 0, fmt.Errorf("%s debe ser un número de segundos mayor que 0", RequestTimeoutHeader)
+	}
+
+	// Se compara en segundos: un valor enorme desbordaría time.Duration
+	if seconds >= max.Seconds() {
+		return max, nil
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,56 @@
+// Package http - Control de la caché de respuestas
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"strconv"
+)
+
+// ============================================================================
+// CACHÉ DE RESPUESTAS
+// ============================================================================
+//
+// El cliente controla la caché de respuestas con la cabecera estándar
+// Cache-Control (no-cache, no-store, max-age=N) y recibe:
+//
+//   X-Cache: HIT | MISS | BYPASS
+//   Age: segundos que llevaba la respuesta en la caché (solo en HIT)
+//   X-Cache-Similarity: similitud con la pregunta guardada (solo en los HIT
+//     de la caché semántica)
+//
+// Sin caché configurada no se envía ninguna de las dos.
+// ============================================================================
+
+// CacheStatusHeader indica si la respuesta salió de la caché
+const CacheStatusHeader = "X-Cache"
+
+// CacheSimilarityHeader indica que la respuesta es la de una pregunta
+// parecida (caché semántica) y cuánto se parecía
+const CacheSimilarityHeader = "X-Cache-Similarity"
+
+// cacheControlMiddleware guarda las directivas de Cache-Control en el contexto
+// La aplicación las lee con domain.CacheControlFromContext()
+func cacheControlMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if value := r.Header.Get("Cache-Control"); value != "" {
+			r = r.WithContext(domain.WithCacheControl(r.Context(), domain.ParseCacheControl(value)))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// writeCacheHeaders añade X-Cache, Age y X-Cache-Similarity según el resultado de la caché
+// Debe llamarse antes de escribir el body
+func writeCacheHeaders(w http.ResponseWriter, meta domain.ResponseMeta) {
+	if meta.CacheStatus == "" {
+		return
+	}
+	w.Header().Set(CacheStatusHeader, string(meta.CacheStatus))
+	if meta.CacheStatus == domain.CacheHit {
+		w.Header().Set("Age", strconv.Itoa(int(meta.CacheAge.Seconds())))
+	}
+	if meta.CacheSimilarity > 0 {
+		w.Header().Set(CacheSimilarityHeader, strconv.FormatFloat(meta.CacheSimilarity, 'f', 4, 64))
+	}
+}
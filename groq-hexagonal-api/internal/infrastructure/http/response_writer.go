@@ -0,0 +1,140 @@
+// Package http - ResponseWriter que registra status y tamaño
+package http
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sync"
+)
+
+// ============================================================================
+// RESPONSE WRITER COMPARTIDO POR LOS MIDDLEWARES
+// ============================================================================
+//
+// http.ResponseWriter no permite preguntar qué status se envió ni cuántos
+// bytes se escribieron. responseWriter lo envuelve para registrarlo, de modo
+// que el access log (y cualquier middleware) conozca el resultado real,
+// también en streaming, donde el body se escribe por trozos durante minutos.
+//
+// - Se envuelve UNA vez: wrapResponseWriter reutiliza el wrapper si ya existe
+// - Es seguro entre goroutines (ej: un handler que escribe desde otra goroutine
+//   mientras un middleware lee el status)
+// - Mantiene Flush y Hijack: sin ellos, SSE y WebSockets dejarían de funcionar
+// ============================================================================
+
+// responseWriter envuelve un http.ResponseWriter
+type responseWriter struct {
+	http.ResponseWriter
+
+	// mu protege los campos de abajo y serializa las escrituras
+	mu       sync.Mutex
+	status   int
+	bytes    int64
+	hijacked bool
+}
+
+// wrapResponseWriter envuelve w, o lo retorna tal cual si ya está envuelto
+func wrapResponseWriter(w http.ResponseWriter) *responseWriter {
+	if rw, ok := w.(*responseWriter); ok {
+		return rw
+	}
+	return &responseWriter{ResponseWriter: w}
+}
+
+// WriteHeader guarda el status; las llamadas repetidas se ignoran
+// (net/http solo registra un aviso, pero el status enviado es el primero)
+func (rw *responseWriter) WriteHeader(statusCode int) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.status != 0 || rw.hijacked {
+		return
+	}
+	rw.status = statusCode
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write cuenta los bytes enviados (el primer Write implica un 200)
+func (rw *responseWriter) Write(data []byte) (int, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	n, err := rw.ResponseWriter.Write(data)
+	rw.bytes += int64(n)
+	return n, err
+}
+
+// Flush implementa http.Flusher (necesario para SSE)
+func (rw *responseWriter) Flush() {
+	// FlushError no existe en http.Flusher: se ignora el error igual que Flush()
+	_ = rw.FlushError()
+}
+
+// FlushError envía al cliente lo escrito hasta ahora
+// http.ResponseController lo prefiere a Flush porque retorna el error
+func (rw *responseWriter) FlushError() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	// ResponseController recorre los Unwrap() hasta encontrar quién sabe hacer flush
+	return http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// Hijack implementa http.Hijacker (WebSockets, protocolos sobre la conexión)
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	conn, buffer, err := http.NewResponseController(rw.ResponseWriter).Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Tras el hijack, el servidor HTTP ya no escribe nada en la conexión
+	rw.hijacked = true
+	if rw.status == 0 {
+		rw.status = http.StatusSwitchingProtocols
+	}
+	return conn, buffer, nil
+}
+
+// Unwrap permite que http.ResponseController llegue al writer original
+// (ej: SetWriteDeadline en streaming)
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Status retorna el status enviado (200 si el handler no escribió nada)
+func (rw *responseWriter) Status() int {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.status == 0 {
+		return http.StatusOK
+	}
+	return rw.status
+}
+
+// BytesWritten retorna los bytes del body enviados hasta ahora
+func (rw *responseWriter) BytesWritten() int64 {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	return rw.bytes
+}
+
+// WroteHeader indica si ya se enviaron las cabeceras
+// A partir de ahí no se puede cambiar el status (ej: a un 500 tras un panic)
+func (rw *responseWriter) WroteHeader() bool {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	return rw.status != 0
+}
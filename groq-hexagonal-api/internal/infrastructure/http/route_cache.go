@@ -0,0 +1,180 @@
+// Package http - Caché de respuestas por ruta
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// CACHÉ POR RUTA
+// ============================================================================
+//
+// Las rutas de /api/v1 con política (ver domain/route_cache.go) guardan su
+// respuesta entera (status, cabeceras de contenido y body) sin que el handler
+// sepa nada: este middleware la guarda y la sirve de nuevo.
+//
+//   - Solo se guardan las respuestas 200 que no son streaming
+//   - Cache-Control funciona igual que en la caché del chat (no-cache,
+//     no-store, max-age=N; y el override de depuración no_cache)
+//   - Las respuestas llevan X-Cache (HIT, MISS o BYPASS) y Age en los HIT
+//
+// Va después del rate limit y de la API key: un HIT cuenta como petición y la
+// clave es de cada cliente (tenant y API key). Las respuestas se guardan sin
+// comprimir, para servirlas a cualquier cliente.
+//
+// El almacén es un puerto (domain.RouteCache): en memoria con una réplica,
+// en Redis con varias. Si falla, la petición se atiende sin caché.
+// ============================================================================
+
+// routeCacheHeaders son las cabeceras de la respuesta que se guardan
+var routeCacheHeaders = []string{"Content-Type", "Content-Disposition", "ETag"}
+
+// RouteCacheOptions configura la caché por ruta
+type RouteCacheOptions struct {
+	// Cache guarda las respuestas (nil = desactivada)
+	Cache domain.RouteCache
+
+	// Policies son las políticas de cada ruta ("GET /api/v1/models")
+	Policies map[string]domain.RouteCachePolicy
+}
+
+// routeCacheMiddleware guarda y sirve las respuestas de las rutas con política
+func routeCacheMiddleware(options RouteCacheOptions) mux.MiddlewareFunc {
+	// Las rutas se normalizan ("get  /x" → "GET /x"); Validate ya rechaza
+	// las mal formadas y las de TTL 0 no se cachean
+	policies := make(map[string]domain.RouteCachePolicy)
+	for route, policy := range options.Policies {
+		method, path, err := domain.ParseRoute(route)
+		if err != nil || policy.Duration() <= 0 {
+			continue
+		}
+		policies[method+" "+path] = policy
+	}
+
+	return func(next http.Handler) http.Handler {
+		if options.Cache == nil || len(policies) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			policy, ok := routeCachePolicy(r, policies)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var body []byte
+			if policy.ByBody() {
+				var err error
+				if body, err = io.ReadAll(r.Body); err != nil {
+					writeDecodeError(w, err)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			// Guardar la respuesta no depende de que el cliente siga
+			ctx := context.WithoutCancel(r.Context())
+			key := routeCacheKey(requestOwner(r), r, body)
+			control := domain.CacheControlFromContext(r.Context())
+			bypass := control.NoCache || control.NoStore || domain.DebugOverridesFromContext(r.Context()).DisableCache
+
+			status := domain.CacheBypass
+			if !bypass {
+				status = domain.CacheMiss
+				cached, err := options.Cache.Get(ctx, key)
+				if err != nil {
+					log.Printf("⚠️  Error al leer la caché por ruta: %v", err)
+				}
+				if cached != nil && (control.MaxAge == nil || cached.Age(time.Now()) <= *control.MaxAge) {
+					replayCachedRoute(w, cached)
+					return
+				}
+			}
+
+			w.Header().Set(CacheStatusHeader, string(status))
+			if control.NoStore {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Sin comprimir: la respuesta guardada sirve a cualquier cliente
+			r.Header.Del("Accept-Encoding")
+
+			recorder := &idempotencyRecorder{ResponseWriter: w, headers: routeCacheHeaders}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.statusCode() != http.StatusOK || isEventStream(recorder.header) {
+				return
+			}
+			err := options.Cache.Set(ctx, key, domain.CachedRoute{
+				StatusCode: http.StatusOK,
+				Header:     recorder.header,
+				Body:       recorder.body.Bytes(),
+				StoredAt:   time.Now(),
+			}, policy.Duration())
+			if err != nil {
+				log.Printf("⚠️  Error al guardar en la caché por ruta: %v", err)
+			}
+		})
+	}
+}
+
+// routeCachePolicy retorna la política de la ruta de la petición
+func routeCachePolicy(r *http.Request, policies map[string]domain.RouteCachePolicy) (domain.RouteCachePolicy, bool) {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return domain.RouteCachePolicy{}, false
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return domain.RouteCachePolicy{}, false
+	}
+	policy, ok := policies[r.Method+" "+template]
+	return policy, ok
+}
+
+// replayCachedRoute responde con una respuesta guardada
+func replayCachedRoute(w http.ResponseWriter, cached *domain.CachedRoute) {
+	for name, values := range cached.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(CacheStatusHeader, string(domain.CacheHit))
+	w.Header().Set("Age", strconv.Itoa(int(cached.Age(time.Now()).Seconds())))
+	w.WriteHeader(cached.StatusCode)
+	w.Write(cached.Body)
+}
+
+// routeCacheKey es la clave en el almacén: un hash del cliente, el método, la
+// ruta, la query (ordenada) y el body (vacío si la política no lo incluye)
+func routeCacheKey(owner string, r *http.Request, body []byte) string {
+	hash := sha256.New()
+	for _, part := range []string{owner, r.Method, r.URL.Path, r.URL.Query().Encode()} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// isEventStream indica si las cabeceras guardadas son las de un flujo SSE
+func isEventStream(header map[string][]string) bool {
+	values := header["Content-Type"]
+	if len(values) == 0 {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(values[0])
+	return mediaType == "text/event-stream"
+}
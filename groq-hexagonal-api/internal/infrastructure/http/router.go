@@ -2,6 +2,7 @@
 package http
 
 import (
+	"groq-hexagonal-api/internal/domain"
 	"log"
 	"net/http"
 	"time"
@@ -14,14 +15,133 @@ import (
 // ROUTER SETUP
 // ============================================================================
 
+// Handlers agrupa todos los handlers HTTP de la aplicación
+// Los handlers opcionales pueden ser nil: sus rutas no se registran
+type Handlers struct {
+	// Chat es obligatorio (chat, modelos y health check)
+	Chat *ChatHandler
+
+	// Conversation atiende las conversaciones multi-turno
+	Conversation *ConversationHandler
+
+	// Job atiende los chats asíncronos (encolar y consultar)
+	Job *JobHandler
+
+	// Prompt atiende las herramientas de ayuda con prompts
+	Prompt *PromptHandler
+
+	// Classification atiende la clasificación de textos
+	Classification *ClassificationHandler
+
+	// Redaction atiende la anonimización de datos personales
+	Redaction *RedactionHandler
+
+	// NL2SQL atiende la traducción de preguntas a SQL
+	NL2SQL *NL2SQLHandler
+
+	// Code atiende la explicación y la revisión de código
+	Code *CodeHandler
+
+	// Usage atiende el consumo de tokens y aplica las cuotas diarias
+	Usage *UsageHandler
+
+	// Feedback atiende las valoraciones de las respuestas (el resumen,
+	// solo con AdminKey)
+	Feedback *FeedbackHandler
+
+	// Diff atiende la comparación de respuestas entre dos configuraciones
+	Diff *DiffHandler
+
+	// Proxy reenvía peticiones en formato OpenAI a Groq sin modificarlas
+	Proxy *ProxyHandler
+
+	// Transcription atiende la transcripción de audio
+	Transcription *TranscriptionHandler
+
+	// ModelHealth atiende el panel de salud de los modelos (requiere AdminKey)
+	ModelHealth *ModelHealthHandler
+
+	// Admin atiende el resto de endpoints de administración (requiere AdminKey)
+	Admin *AdminHandler
+
+	// APIKeys atiende la administración de las API keys (requiere AdminKey)
+	APIKeys *APIKeyHandler
+
+	// Tokens emite tokens de vida corta a partir de las API keys (requiere
+	// RouterOptions.APIKeys)
+	Tokens *TokenHandler
+}
+
+// RouterOptions contiene la configuración de los middlewares
+type RouterOptions struct {
+	// AdminKey autoriza las operaciones privilegiadas (vacío = desactivadas)
+	AdminKey string
+
+	// AccessLog configura el formato y los campos del access log
+	AccessLog AccessLogOptions
+
+	// RateLimit limita las peticiones por cliente en /api/v1 (ver rate_limit.go)
+	RateLimit RateLimitOptions
+
+	// MaxBodyBytes limita el tamaño del body de las peticiones (0 = sin límite)
+	MaxBodyBytes int64
+
+	// LoadShedder rechaza las peticiones de /api/v1 con la réplica
+	// sobrecargada (nil = nunca)
+	LoadShedder domain.LoadShedder
+
+	// Liveness vigila que las peticiones de /api/v1 no se queden colgadas
+	// (nil = sin vigilancia)
+	Liveness domain.LivenessMonitor
+
+	// APIKeys autentica las peticiones de /api/v1 (nil = sin autenticación;
+	// ver api_key_auth.go)
+	APIKeys domain.APIKeyService
+
+	// APIKeyExpiryWarning es desde cuánto antes de caducar la clave se
+	// responde X-Key-Expires-In (0 = nunca)
+	APIKeyExpiryWarning time.Duration
+
+	// Idempotency guarda las respuestas de POST /api/v1/chat con
+	// Idempotency-Key (ver idempotency.go)
+	Idempotency IdempotencyOptions
+
+	// Bulkhead limita los chats en curso de la réplica (ver bulkhead.go)
+	Bulkhead BulkheadOptions
+
+	// RouteCache guarda las respuestas de las rutas de /api/v1 con política
+	// de caché (ver route_cache.go)
+	RouteCache RouteCacheOptions
+
+	// ReplaySampler guarda una muestra de POST /api/v1/chat para
+	// reproducirla (nil = desactivado; ver replay_sampling.go)
+	ReplaySampler domain.ReplaySampler
+
+	// MaxRequestTimeout es el máximo que se acepta en X-Request-Timeout
+	// (0 = la cabecera se ignora; ver request_timeout.go)
+	MaxRequestTimeout time.Duration
+
+	// Providers son los proveedores configurados con su modelo por defecto, y
+	// DefaultProvider el de las peticiones que no eligen uno (solo se
+	// informan en GET /api/v1/capabilities)
+	Providers       map[string]string
+	DefaultProvider string
+
+	// WebUI sirve la página de chat embebida en /ui (ver ui.go)
+	WebUI bool
+}
+
 // SetupRouter configura y retorna el router HTTP con todas las rutas
 //
 // Parámetros:
-//   - handler: el ChatHandler con todos los handlers
+//   - handlers: todos los handlers de la aplicación
+//   - options: configuración de los middlewares
 //
 // Retorna:
 //   - http.Handler: router configurado y listo para usar
-func SetupRouter(handler *ChatHandler) http.Handler {
+func SetupRouter(handlers Handlers, options RouterOptions) http.Handler {
+	handler := handlers.Chat
+
 	// ========================================================================
 	// 1. CREAR EL ROUTER
 	// ========================================================================
@@ -34,12 +154,27 @@ func SetupRouter(handler *ChatHandler) http.Handler {
 	// 2. CONFIGURAR MIDDLEWARES GLOBALES
 	// ========================================================================
 
-	// Middleware de logging para todas las rutas
-	router.Use(loggingMiddleware)
+	// Access log: una línea estructurada por petición
+	router.Use(newAccessLogger(options.AccessLog).middleware)
 
 	// Middleware de recovery para capturar panics
 	router.Use(recoveryMiddleware)
 
+	// Middleware que limita el tamaño del body (ver body_limit.go)
+	router.Use(maxBodyMiddleware(options.MaxBodyBytes))
+
+	// Middleware que identifica el tenant de la petición
+	router.Use(tenantMiddleware)
+
+	// Las cabeceras quedan en el contexto para los hooks del despliegue
+	router.Use(requestHeadersMiddleware)
+
+	// Directivas de Cache-Control para la caché de respuestas
+	router.Use(cacheControlMiddleware)
+
+	// Middleware de overrides de depuración (solo con la clave de administración)
+	router.Use(debugOverridesMiddleware(options.AdminKey))
+
 	// ========================================================================
 	// 3. DEFINIR RUTAS
 	// ========================================================================
@@ -48,16 +183,181 @@ func SetupRouter(handler *ChatHandler) http.Handler {
 	// Esto crea un "sub-router" que maneja todas las rutas bajo /api/v1
 	apiV1 := router.PathPrefix("/api/v1").Subrouter()
 
+	// Con la réplica sobrecargada se rechaza antes de contar la petición
+	apiV1.Use(loadSheddingMiddleware(options.LoadShedder))
+
+	// La API key va antes del rate limit: su ID identifica al cliente
+	apiV1.Use(apiKeyAuthMiddleware(options.APIKeys, options.APIKeyExpiryWarning))
+
+	// El plazo del cliente incluye la espera en la cola del rate limit
+	apiV1.Use(requestTimeoutMiddleware(options.MaxRequestTimeout))
+
+	// El watchdog va después del plazo del cliente: lo usa como límite
+	apiV1.Use(livenessMiddleware(options.Liveness))
+
+	// El rate limit solo se aplica a la API (no a /health): va después de los
+	// overrides de depuración porque bypass_rate_limit lo desactiva
+	apiV1.Use(rateLimitMiddleware(options.RateLimit))
+
+	// Las rutas con política de caché se sirven desde aquí en los HIT
+	apiV1.Use(routeCacheMiddleware(options.RouteCache))
+
+	// Las rutas de chat comparten el límite de chats en curso de la réplica
+	bulkhead := bulkheadMiddleware(options.Bulkhead)
+
 	// POST /api/v1/chat - Enviar mensaje al modelo
-	apiV1.HandleFunc("/chat", handler.HandleChat).Methods(http.MethodPost)
+	// Con Idempotency-Key, los reintentos reciben la primera respuesta (sin
+	// ocupar hueco en el bulkhead ni volver a muestrearse)
+	sampling := replaySamplingMiddleware(options.ReplaySampler)
+	apiV1.Handle("/chat", idempotencyMiddleware(options.Idempotency)(sampling(bulkhead(http.HandlerFunc(handler.HandleChat))))).Methods(http.MethodPost)
+
+	// GET /api/v1/chat/streams/{id} - Reanudar un flujo SSE interrumpido
+	if handler.streams != nil {
+		apiV1.HandleFunc("/chat/streams/{id}", handler.HandleResumeStream).Methods(http.MethodGet)
+	}
 
 	// GET /api/v1/models - Obtener modelos disponibles
 	apiV1.HandleFunc("/models", handler.HandleGetModels).Methods(http.MethodGet)
 
+	// GET /api/v1/capabilities - Funciones activas en este despliegue
+	apiV1.HandleFunc("/capabilities", capabilitiesHandler(newCapabilitiesResponse(handlers, options))).Methods(http.MethodGet)
+
+	// Chats asíncronos: el job se encola y se consulta hasta que termina
+	if jobs := handlers.Job; jobs != nil {
+		apiV1.HandleFunc("/chat/async", jobs.HandleSubmit).Methods(http.MethodPost)
+		apiV1.HandleFunc("/jobs/{id}", jobs.HandleGet).Methods(http.MethodGet)
+		apiV1.HandleFunc("/jobs/{id}/result", jobs.HandleResult).Methods(http.MethodGet)
+		apiV1.HandleFunc("/jobs/{id}/events", jobs.HandleEvents).Methods(http.MethodGet)
+	}
+
+	// Conversaciones multi-turno
+	if conversations := handlers.Conversation; conversations != nil {
+		apiV1.HandleFunc("/conversations", conversations.HandleCreate).Methods(http.MethodPost)
+		apiV1.HandleFunc("/conversations/{id}", conversations.HandleGet).Methods(http.MethodGet)
+		apiV1.HandleFunc("/conversations/{id}", conversations.HandlePin).Methods(http.MethodPatch)
+		apiV1.HandleFunc("/conversations/{id}", conversations.HandleDelete).Methods(http.MethodDelete)
+		apiV1.Handle("/conversations/{id}/messages", bulkhead(http.HandlerFunc(conversations.HandleSendMessage))).Methods(http.MethodPost)
+		apiV1.HandleFunc("/conversations/{id}/restore", conversations.HandleRestore).Methods(http.MethodPost)
+	}
+
+	// Mejora de prompts
+	if prompts := handlers.Prompt; prompts != nil {
+		apiV1.HandleFunc("/prompts/improve", prompts.HandleImprove).Methods(http.MethodPost)
+	}
+
+	// Clasificación de textos con etiquetas
+	if classification := handlers.Classification; classification != nil {
+		apiV1.HandleFunc("/classify", classification.HandleClassify).Methods(http.MethodPost)
+	}
+
+	// Anonimización de datos personales (sin llamar al modelo)
+	if redaction := handlers.Redaction; redaction != nil {
+		apiV1.HandleFunc("/redact", redaction.HandleRedact).Methods(http.MethodPost)
+	}
+
+	// Traducción de lenguaje natural a SQL (valida la consulta, no la ejecuta)
+	if nl2sql := handlers.NL2SQL; nl2sql != nil {
+		apiV1.HandleFunc("/nl2sql", nl2sql.HandleNL2SQL).Methods(http.MethodPost)
+	}
+
+	// Explicación y revisión de código (los ficheros largos se parten en fragmentos)
+	if code := handlers.Code; code != nil {
+		apiV1.HandleFunc("/code/explain", code.HandleExplain).Methods(http.MethodPost)
+		apiV1.HandleFunc("/code/review", code.HandleReview).Methods(http.MethodPost)
+	}
+
+	// Consumo de tokens del cliente; el middleware cuenta el de toda la API
+	// y rechaza las peticiones con la cuota diaria agotada
+	if usage := handlers.Usage; usage != nil {
+		apiV1.HandleFunc("/usage", usage.HandleUsage).Methods(http.MethodGet)
+		apiV1.HandleFunc("/tenants/{id}/statements/{month}", usage.HandleStatement).Methods(http.MethodGet)
+		apiV1.Use(usage.trackUsage)
+	}
+
+	// Valoraciones de las respuestas (evaluación offline de prompts y modelos)
+	if feedback := handlers.Feedback; feedback != nil {
+		apiV1.HandleFunc("/feedback", feedback.HandleSubmit).Methods(http.MethodPost)
+	}
+
+	// Comparación de respuestas (canary, A/B)
+	if diff := handlers.Diff; diff != nil {
+		apiV1.HandleFunc("/diff", diff.HandleDiff).Methods(http.MethodPost)
+	}
+
+	// Modo proxy (passthrough) para parámetros que la API no modela
+	if proxy := handlers.Proxy; proxy != nil {
+		apiV1.Handle("/proxy/chat/completions", bulkhead(http.HandlerFunc(proxy.HandleChatCompletions))).Methods(http.MethodPost)
+	}
+
+	// Transcripción de audio (multipart)
+	if transcription := handlers.Transcription; transcription != nil {
+		apiV1.HandleFunc("/audio/transcriptions", transcription.HandleTranscription).Methods(http.MethodPost)
+	}
+
+	// Tokens de vida corta para clientes que no deben ver la API key
+	if tokens := handlers.Tokens; tokens != nil {
+		apiV1.HandleFunc("/token", tokens.HandleIssue).Methods(http.MethodPost)
+	}
+
+	// Administración (fuera de /api/v1: no consume rate limit)
+	// Todo el subrouter exige la clave de administración
+	if options.AdminKey != "" {
+		adminRouter := router.PathPrefix("/admin").Subrouter()
+		adminRouter.Use(requireAdminKeyMiddleware(options.AdminKey))
+
+		// Diagnóstico del runtime y perfiles (ver diagnostics.go)
+		adminRouter.HandleFunc("/runtime", HandleRuntime).Methods(http.MethodGet)
+		registerPprof(adminRouter)
+
+		if health := handlers.ModelHealth; health != nil {
+			adminRouter.HandleFunc("/models/health", health.HandleModelsHealth).Methods(http.MethodGet)
+		}
+		if admin := handlers.Admin; admin != nil {
+			adminRouter.HandleFunc("/config", admin.HandleConfig).Methods(http.MethodGet)
+			adminRouter.HandleFunc("/config/reload", admin.HandleConfigReload).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/cache", admin.HandleCacheStats).Methods(http.MethodGet)
+			adminRouter.HandleFunc("/cache/flush", admin.HandleCacheFlush).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/circuits", admin.HandleCircuits).Methods(http.MethodGet)
+			adminRouter.HandleFunc("/in-flight", admin.HandleInFlight).Methods(http.MethodGet)
+			if admin.liveness != nil {
+				adminRouter.HandleFunc("/liveness", admin.HandleLiveness).Methods(http.MethodGet)
+			}
+			if admin.groqKeys != nil {
+				adminRouter.HandleFunc("/groq/keys", admin.HandleGroqKeys).Methods(http.MethodGet)
+			}
+
+			// Las peticiones en curso se cuentan en la API
+			apiV1.Use(admin.trackInFlight)
+		}
+		if apiKeys := handlers.APIKeys; apiKeys != nil {
+			adminRouter.HandleFunc("/keys", apiKeys.HandleCreate).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/keys", apiKeys.HandleList).Methods(http.MethodGet)
+			adminRouter.HandleFunc("/keys/{id}/disable", apiKeys.HandleDisable).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/keys/{id}/rotate", apiKeys.HandleRotate).Methods(http.MethodPost)
+		}
+		if feedback := handlers.Feedback; feedback != nil {
+			adminRouter.HandleFunc("/feedback", feedback.HandleSummary).Methods(http.MethodGet)
+		}
+	}
+
 	// Health check endpoint (fuera de /api/v1)
 	// GET /health - Verificar estado del servicio
 	router.HandleFunc("/health", handler.HandleHealth).Methods(http.MethodGet)
 
+	// Documentación: especificación OpenAPI (generada de los DTOs) y Swagger UI
+	if spec, err := newOpenAPISpec(handlers); err != nil {
+		log.Printf("⚠️  No se pudo generar la especificación OpenAPI: %v", err)
+	} else {
+		router.HandleFunc("/openapi.json", openAPIHandler(spec)).Methods(http.MethodGet)
+		router.HandleFunc("/docs", handleDocs).Methods(http.MethodGet)
+	}
+
+	// Interfaz web de chat (opcional)
+	if options.WebUI {
+		router.Handle("/ui", http.RedirectHandler("/ui/", http.StatusMovedPermanently)).Methods(http.MethodGet)
+		router.PathPrefix("/ui/").Handler(uiHandler()).Methods(http.MethodGet)
+	}
+
 	// Ruta raíz (opcional)
 	router.HandleFunc("/", handleRoot).Methods(http.MethodGet)
 
@@ -76,6 +376,7 @@ func SetupRouter(handler *ChatHandler) http.Handler {
 			http.MethodGet,
 			http.MethodPost,
 			http.MethodPut,
+			http.MethodPatch,
 			http.MethodDelete,
 			http.MethodOptions,
 		},
@@ -85,11 +386,29 @@ func SetupRouter(handler *ChatHandler) http.Handler {
 			"Content-Type",
 			"Authorization",
 			"X-Requested-With",
+			TenantHeader,
+			DebugOverridesHeader,
+			AdminKeyHeader,
+			RequestTimeoutHeader,
+			"Cache-Control",
+			lastEventIDHeader,
+			IdempotencyKeyHeader,
 		},
 
 		// ExposedHeaders: headers que el cliente puede leer
 		ExposedHeaders: []string{
 			"Content-Length",
+			"Retry-After",
+			RateLimitLimitHeader,
+			RateLimitRemainingHeader,
+			RateLimitScheduleHeader,
+			RateLimitWaitedHeader,
+			CacheStatusHeader,
+			CacheSimilarityHeader,
+			"Age",
+			KeyExpiresInHeader,
+			StreamIDHeader,
+			IdempotentReplayedHeader,
 		},
 
 		// AllowCredentials: permitir cookies
@@ -121,8 +440,17 @@ func handleRoot(w http.ResponseWriter, r *http.Request) {
 		"description": "API REST para interactuar con Groq usando Arquitectura Hexagonal",
 		"endpoints": {
 			"chat": "POST /api/v1/chat",
+			"jobs": "POST /api/v1/chat/async, GET /api/v1/jobs/{id}, GET /api/v1/jobs/{id}/result",
 			"models": "GET /api/v1/models",
-			"health": "GET /health"
+			"conversations": "POST /api/v1/conversations, GET|PATCH|DELETE /api/v1/conversations/{id}, POST /api/v1/conversations/{id}/messages, POST /api/v1/conversations/{id}/restore",
+			"prompts": "POST /api/v1/prompts/improve",
+			"diff": "POST /api/v1/diff",
+			"usage": "GET /api/v1/usage",
+			"statements": "GET /api/v1/tenants/{id}/statements/{month}",
+			"health": "GET /health",
+			"openapi": "GET /openapi.json",
+			"docs": "GET /docs",
+			"ui": "GET /ui"
 		},
 		"documentation": "https://github.com/tu-usuario/groq-hexagonal-api"
 	}`
@@ -146,38 +474,12 @@ func handleRoot(w http.ResponseWriter, r *http.Request) {
 // 3. Hace algo después (ej: medir tiempo)
 // ============================================================================
 
-// loggingMiddleware registra todas las peticiones HTTP
-func loggingMiddleware(next http.Handler) http.Handler {
-	// http.HandlerFunc convierte una función en un Handler
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Registrar el inicio de la petición
-		start := time.Now()
-
-		log.Printf(
-			"[%s] %s %s - Iniciando",
-			r.Method,
-			r.URL.Path,
-			r.RemoteAddr,
-		)
-
-		// Llamar al siguiente handler en la cadena
-		next.ServeHTTP(w, r)
-
-		// Registrar el final de la petición con duración
-		duration := time.Since(start)
-		log.Printf(
-			"[%s] %s %s - Completado en %v",
-			r.Method,
-			r.URL.Path,
-			r.RemoteAddr,
-			duration,
-		)
-	})
-}
-
 // recoveryMiddleware captura panics y previene que crashee el servidor
 func recoveryMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// El wrapper dice si las cabeceras ya salieron (ver response_writer.go)
+		rw := wrapResponseWriter(w)
+
 		// defer con recover() captura panics
 		defer func() {
 			// recover() retorna nil si no hay panic, o el valor del panic
@@ -185,14 +487,46 @@ func recoveryMiddleware(next http.Handler) http.Handler {
 				// Registrar el panic
 				log.Printf("PANIC: %v", err)
 
+				// Si ya se envió el status (ej: a mitad de un streaming), no se
+				// puede cambiar a 500: solo queda cortar la respuesta
+				if rw.WroteHeader() {
+					return
+				}
+
 				// Retornar error 500 al cliente
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(`{"success": false, "error": "internal server error"}`))
+				rw.Header().Set("Content-Type", "application/json")
+				rw.WriteHeader(http.StatusInternalServerError)
+				rw.Write([]byte(`{"success": false, "error": "internal server error"}`))
 			}
 		}()
 
 		// Llamar al siguiente handler
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// TenantHeader es la cabecera que identifica al tenant (cliente) de la petición
+const TenantHeader = "X-Tenant-ID"
+
+// tenantMiddleware guarda el tenant de la cabecera en el contexto
+// Las capas internas lo leen con domain.TenantFromContext()
+// Con API keys, apiKeyAuthMiddleware lo sustituye por el de la clave
+func tenantMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tenantID := r.Header.Get(TenantHeader); tenantID != "" {
+			// r.WithContext() crea una copia de la petición con el nuevo contexto
+			r = r.WithContext(domain.WithTenant(r.Context(), tenantID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestHeadersMiddleware guarda las cabeceras de la petición en el contexto
+// Los hooks las leen con domain.RequestHeadersFromContext()
+func requestHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// http.Header ya guarda las claves en forma canónica
+		r = r.WithContext(domain.WithRequestHeaders(r.Context(), domain.RequestHeaders(r.Header)))
 		next.ServeHTTP(w, r)
 	})
 }
@@ -297,11 +631,11 @@ func recoveryMiddleware(next http.Handler) http.Handler {
 // Para una petición POST /api/v1/chat:
 //
 // 1. CORS Handler (preflight check)
-// 2. loggingMiddleware (log inicio)
+// 2. accessLogger.middleware (medir inicio, envolver el ResponseWriter)
 // 3. recoveryMiddleware (preparar recover)
-// 4. handler.HandleChat (procesar petición)
+// 4. handler.HandleChat (procesar petición, apuntar modelo y tokens)
 // 5. recoveryMiddleware (verificar panic)
-// 6. loggingMiddleware (log fin + duración)
+// 6. accessLogger.middleware (una línea con status, bytes, duración...)
 // 7. CORS Handler (añadir headers CORS)
 //
 // ============================================================================
@@ -0,0 +1,134 @@
+// Package http - Extractos mensuales del consumo de cada tenant
+package http
+
+import (
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/pdf"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// EXTRACTOS
+// ============================================================================
+//
+// GET /api/v1/tenants/{id}/statements/{month} resume el consumo de un tenant
+// en un mes (AAAA-MM, UTC): peticiones, tokens y coste por modelo y las
+// conversaciones que más han consumido. Sale de lo que trackUsage registra,
+// así que cuenta lo mismo que GET /api/v1/usage.
+//
+// Por defecto la respuesta es JSON; con ?format=pdf o "Accept:
+// application/pdf", un PDF para adjuntar a una factura. Con API keys, solo
+// una clave sin scopes puede pedir extractos, y solo el de su propio tenant
+// (domain.APIKey.TenantID); el de cualquier otro es un 403 tenant_mismatch.
+// ============================================================================
+
+// HandleStatement maneja GET /api/v1/tenants/{id}/statements/{month}
+func (h *UsageHandler) HandleStatement(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleStatement", r.Method, r.URL.Path)
+
+	vars := mux.Vars(r)
+	// Con autenticación, cada clave solo ve el extracto de su tenant
+	if key := domain.APIKeyFromContext(r.Context()); key != nil && vars["id"] != key.TenantID() {
+		writeServiceError(w, domain.ErrTenantMismatch, "")
+		return
+	}
+	statement, err := h.usageService.Statement(r.Context(), vars["id"], vars["month"])
+	if err != nil {
+		writeServiceError(w, err, "error al generar el extracto")
+		return
+	}
+
+	if !wantsPDF(r) {
+		writeJSONResponse(w, NewStatementResponse(statement), http.StatusOK)
+		return
+	}
+
+	body := renderStatementPDF(statement)
+	filename := "statement-" + statement.Tenant + "-" + statement.Month + ".pdf"
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+// wantsPDF indica si el cliente pide el extracto en PDF
+func wantsPDF(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "pdf"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "application/pdf")
+}
+
+// renderStatementPDF genera el PDF del extracto
+func renderStatementPDF(statement *domain.TenantStatement) []byte {
+	doc := pdf.NewDocument("Extracto " + statement.Tenant + " " + statement.Month)
+
+	doc.Heading("Extracto de consumo: " + statement.Tenant)
+	lastDay := statement.PeriodEnd.AddDate(0, 0, -1)
+	period := fmt.Sprintf("Periodo:   %s a %s (UTC)", statement.PeriodStart.Format("2006-01-02"), lastDay.Format("2006-01-02"))
+	if statement.Provisional {
+		period += ", provisional: el mes no ha terminado"
+	}
+	doc.Text(period)
+	doc.Text("Generado:  " + statement.GeneratedAt.Format("2006-01-02 15:04 UTC"))
+	doc.Blank()
+
+	doc.Heading("Resumen")
+	doc.Text(fmt.Sprintf("%-24s %15d", "Peticiones", statement.Requests))
+	doc.Text(fmt.Sprintf("%-24s %15d", "Tokens de entrada", statement.Usage.PromptTokens))
+	doc.Text(fmt.Sprintf("%-24s %15d", "Tokens de salida", statement.Usage.CompletionTokens))
+	doc.Text(fmt.Sprintf("%-24s %15d", "Tokens totales", statement.Usage.TotalTokens))
+	doc.Text(fmt.Sprintf("%-24s %15.4f USD", "Coste estimado", statement.Usage.CostUSD))
+	doc.Blank()
+
+	doc.Heading("Consumo por modelo")
+	if len(statement.Models) == 0 {
+		doc.Text("Sin consumo en el periodo")
+	} else {
+		doc.Text(statementRow("Modelo", "Peticiones", "Tokens", "Coste (USD)"))
+		doc.Text(strings.Repeat("-", statementRowWidth))
+		for _, model := range statement.Models {
+			doc.Text(statementRow(model.Model,
+				strconv.FormatInt(model.Requests, 10),
+				strconv.Itoa(model.Usage.TotalTokens),
+				strconv.FormatFloat(model.Usage.CostUSD, 'f', 4, 64)))
+		}
+	}
+	doc.Blank()
+
+	doc.Heading("Conversaciones con más consumo")
+	if len(statement.TopConversations) == 0 {
+		doc.Text("Sin conversaciones en el periodo")
+	} else {
+		doc.Text(statementRow("Conversación", "Peticiones", "Tokens", "Coste (USD)"))
+		doc.Text(strings.Repeat("-", statementRowWidth))
+		for _, conversation := range statement.TopConversations {
+			doc.Text(statementRow(conversation.ConversationID,
+				strconv.FormatInt(conversation.Requests, 10),
+				strconv.Itoa(conversation.Usage.TotalTokens),
+				strconv.FormatFloat(conversation.Usage.CostUSD, 'f', 4, 64)))
+		}
+	}
+
+	return doc.Bytes()
+}
+
+// statementRowWidth es el ancho de las filas de las tablas del extracto
+const statementRowWidth = 40 + 1 + 12 + 1 + 14 + 1 + 14
+
+// statementRow formatea una fila: el nombre a la izquierda (cortado a 40
+// caracteres) y las cifras a la derecha
+func statementRow(name, requests, tokens, cost string) string {
+	if runes := []rune(name); len(runes) > 40 {
+		name = string(runes[:39]) + "…"
+	}
+	return fmt.Sprintf("%-40s %12s %14s %14s", name, requests, tokens, cost)
+}
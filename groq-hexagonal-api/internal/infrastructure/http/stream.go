@@ -0,0 +1,319 @@
+// Package http - Respuestas en streaming con Server-Sent Events (SSE)
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/infrastructure/jsoncodec"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// SERVER-SENT EVENTS
+// ============================================================================
+//
+// SSE es un protocolo simple sobre HTTP para que el servidor envíe eventos
+// al cliente por una conexión abierta. Cada evento tiene este formato:
+//
+//   event: <nombre>     (opcional)
+//   data: <contenido>
+//   <línea vacía>
+//
+// En el navegador se consume con EventSource o con fetch() + ReadableStream.
+//
+// Eventos que emite POST /api/v1/chat con "stream": true:
+//   data: {"content": "Hola"}                  → un fragmento de texto
+//   data: {"content": "", "finish_reason": ...} → último fragmento
+//   event: error / data: {...}                 → fallo a mitad del flujo
+//   data: [DONE]                               → fin del flujo
+//
+// Con muchos flujos a la vez, cada fragmento cuenta: sseWriter serializa
+// todos los eventos de un flujo en el mismo buffer y los envía con una sola
+// escritura, sin fmt ni un []byte nuevo por evento.
+//
+// DETRÁS DE PROXIES
+// Un proxy que acumula la respuesta (nginx, CDNs) o la recomprime rompe el
+// streaming: el cliente recibe todo de golpe al final. Por eso:
+//   - X-Accel-Buffering: no y Cache-Control: no-transform piden a los
+//     proxies que reenvíen cada fragmento tal cual
+//   - no hay Content-Length: en HTTP/1.1 la respuesta va en chunked y en
+//     HTTP/2 en frames, y cada flush sale en cuanto se escribe
+//   - si el cliente acepta gzip (Accept-Encoding), el flujo se comprime y
+//     el compresor se vacía en cada evento, sin esperar a llenar un bloque
+//   - el primer evento es "retry: <ms>" (SSE_RETRY_MS): el tiempo que
+//     EventSource espera antes de reconectar si se corta la conexión
+// ============================================================================
+
+// handleChatStream atiende POST /api/v1/chat con "stream": true
+// Se llama desde HandleChat una vez decodificado y validado el request
+func (h *ChatHandler) handleChatStream(w http.ResponseWriter, r *http.Request, req ChatRequest) {
+	// Un cliente que reconecta con Last-Event-ID retoma su flujo (ver stream_resume.go)
+	if lastEventID := r.Header.Get(lastEventIDHeader); lastEventID != "" && h.streams != nil {
+		h.resumeChatStream(w, r, lastEventID)
+		return
+	}
+
+	// Con flujos reanudables, la generación no se cancela si el cliente se
+	// desconecta: sigue guardando eventos para cuando reconecte (con plazo:
+	// ver detachStream)
+	ctx := r.Context()
+	if h.streams != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = detachStream(ctx)
+		defer cancel()
+	}
+
+	// Iniciar el flujo ANTES de escribir cabeceras: si Groq falla aquí,
+	// todavía podemos responder con un error JSON normal
+	stream, err := h.chatService.StreamMessage(ctx, req.Message, req.Model, req.toMessageOptions())
+	if err != nil {
+		writeServiceError(w, err, "error al procesar el mensaje")
+		return
+	}
+	defer stream.Close()
+
+	var session *streamSession
+	if h.streams != nil {
+		session, err = h.streams.create(requestOwner(r))
+		if err != nil {
+			writeErrorResponse(w, "error al iniciar el flujo", http.StatusInternalServerError)
+			return
+		}
+		defer h.streams.finish(session)
+		w.Header().Set(StreamIDHeader, session.id)
+	}
+
+	out, flush, finish := startSSE(w, r, h.sseRetry)
+	defer finish()
+
+	var relay *streamRelay
+	if session != nil {
+		relay = &streamRelay{session: session, client: out, flush: flush}
+		out, flush = relay, relay.Flush
+	}
+	events := newSSEWriter(out)
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			// Si el cliente se fue, no hay a quién avisar. Un flujo reanudable
+			// guarda el error igualmente: lo recibirá al reconectar
+			if session == nil && errors.Is(ctx.Err(), context.Canceled) {
+				return
+			}
+			log.Printf("Error durante el streaming: %v", err)
+			// Las cabeceras ya se enviaron: el status va solo en el evento
+			events.WriteEvent("error", classifyServiceError(err, "error durante el streaming"))
+			flush()
+			return
+		}
+
+		event := StreamChunkResponse{
+			Content:      chunk.GetDeltaContent(),
+			Model:        chunk.Model,
+			FinishReason: chunk.GetFinishReason(),
+			ToolCalls:    NewToolCallInfos(chunk.GetDeltaToolCalls()),
+		}
+		if usage := chunk.GetUsage(); usage != nil {
+			annotateAccessLog(ctx, chunk.Model, usage)
+			event.Usage = &UsageInfo{
+				PromptTokens:     usage.PromptTokens,
+				CompletionTokens: usage.CompletionTokens,
+				TotalTokens:      usage.TotalTokens,
+				CostUSD:          usage.CostUSD,
+			}
+		}
+
+		// Los fragmentos sin texto ni metadatos (ej: el primero, solo con "role")
+		// no aportan nada al cliente
+		if event.Content == "" && event.FinishReason == "" && event.Usage == nil && len(event.ToolCalls) == 0 {
+			continue
+		}
+
+		if err := events.WriteEvent("", &event); err != nil {
+			log.Printf("Error al escribir evento SSE: %v", err)
+			return
+		}
+
+		// Flush envía al cliente lo escrito hasta ahora (sin esperar al final)
+		if err := flush(); err != nil {
+			log.Printf("Error al hacer flush: %v", err)
+			return
+		}
+	}
+
+	// Marcador de fin, igual que la API de Groq/OpenAI
+	if events.WriteDone() != nil || flush() != nil {
+		return
+	}
+	// Solo un flujo que llegó entero al cliente puede repetirse con la misma
+	// Idempotency-Key (el relay no falla aunque el cliente se haya ido)
+	if relay == nil || !relay.gone {
+		markStreamDone(w)
+	}
+}
+
+// startSSE quita el write deadline, escribe las cabeceras SSE y el retry
+// (0 = no se envía)
+// Retorna dónde escribir los eventos, cómo enviarlos al cliente y la función
+// que termina la respuesta (cierra el gzip)
+func startSSE(w http.ResponseWriter, r *http.Request, retry time.Duration) (io.Writer, func() error, func()) {
+	// http.ResponseController (Go 1.20+) da acceso a Flush() y a los deadlines
+	// aunque w esté envuelto por middlewares
+	rc := http.NewResponseController(w)
+
+	// El WriteTimeout del servidor cortaría respuestas largas
+	// Un deadline cero significa "sin límite" para esta petición
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Printf("No se pudo quitar el write deadline: %v", err)
+	}
+
+	// Cabeceras SSE
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache, no-transform")
+	header.Set("X-Accel-Buffering", "no") // Desactiva el buffering de nginx
+	header.Del("Content-Length")
+	header.Add("Vary", "Accept-Encoding")
+	// HTTP/2 prohíbe las cabeceras de conexión
+	if r.ProtoMajor == 1 {
+		header.Set("Connection", "keep-alive")
+	}
+
+	var out io.Writer = w
+	flush := rc.Flush
+	finish := func() {}
+	if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		header.Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		out = gz
+		flush = func() error {
+			if err := gz.Flush(); err != nil {
+				return err
+			}
+			return rc.Flush()
+		}
+		// Close escribe el final del gzip antes de terminar la respuesta
+		finish = func() { gz.Close() }
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	// El retry y las cabeceras salen ya, antes del primer fragmento: así el
+	// cliente (y los proxies) saben desde el principio que el flujo está abierto
+	if retry > 0 {
+		newSSEWriter(out).WriteRetry(retry)
+	}
+	flush()
+
+	return out, flush, finish
+}
+
+// acceptsGzip indica si la cabecera Accept-Encoding admite gzip
+// Respeta los pesos: "gzip;q=0" lo rechaza y "*" lo admite salvo que gzip
+// aparezca rechazado explícitamente
+func acceptsGzip(acceptEncoding string) bool {
+	gzipQ, wildcardQ := -1.0, -1.0
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip", "x-gzip":
+			gzipQ = q
+		case "*":
+			wildcardQ = q
+		}
+	}
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return wildcardQ > 0
+}
+
+// Trozos fijos de los eventos SSE (se escriben sin formatear)
+var (
+	sseEventPrefix = []byte("event: ")
+	sseDataPrefix  = []byte("data: ")
+	sseRetryPrefix = []byte("retry: ")
+	sseDoneEvent   = []byte("data: [DONE]\n\n")
+	ssePingComment = []byte(": ping\n\n")
+)
+
+// sseWriter escribe los eventos SSE de un flujo
+// No es seguro para usarlo desde varias goroutines (un flujo tiene una)
+type sseWriter struct {
+	w io.Writer
+
+	// buf se reutiliza en todos los eventos: tras los primeros fragmentos
+	// ya tiene capacidad suficiente y no vuelve a crecer
+	buf     bytes.Buffer
+	encoder jsoncodec.Encoder
+}
+
+// newSSEWriter crea el escritor de eventos sobre w
+func newSSEWriter(w io.Writer) *sseWriter {
+	writer := &sseWriter{w: w}
+	writer.encoder = jsonCodec.NewEncoder(&writer.buf)
+	return writer
+}
+
+// WriteEvent escribe un evento con data serializado como JSON
+// Si name está vacío, se omite la línea "event:" (evento "message" por defecto)
+func (s *sseWriter) WriteEvent(name string, data interface{}) error {
+	s.buf.Reset()
+	if name != "" {
+		s.buf.Write(sseEventPrefix)
+		s.buf.WriteString(name)
+		s.buf.WriteByte('\n')
+	}
+	s.buf.Write(sseDataPrefix)
+	// Encode termina el JSON con '\n': falta la línea vacía del final
+	if err := s.encoder.Encode(data); err != nil {
+		return err
+	}
+	s.buf.WriteByte('\n')
+
+	_, err := s.w.Write(s.buf.Bytes())
+	return err
+}
+
+// WriteDone escribe el marcador de fin del flujo
+func (s *sseWriter) WriteDone() error {
+	_, err := s.w.Write(sseDoneEvent)
+	return err
+}
+
+// WritePing escribe un comentario que el cliente ignora: mantiene abierta la
+// conexión (y los proxies intermedios) mientras no hay eventos
+func (s *sseWriter) WritePing() error {
+	_, err := s.w.Write(ssePingComment)
+	return err
+}
+
+// WriteRetry escribe el campo "retry:" con el tiempo de reconexión en ms
+func (s *sseWriter) WriteRetry(retry time.Duration) error {
+	s.buf.Reset()
+	s.buf.Write(sseRetryPrefix)
+	s.buf.WriteString(strconv.FormatInt(retry.Milliseconds(), 10))
+	s.buf.WriteString("\n\n")
+
+	_, err := s.w.Write(s.buf.Bytes())
+	return err
+}
@@ -0,0 +1,358 @@
+// Package http - Reanudación de flujos SSE interrumpidos
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// REANUDACIÓN DE FLUJOS
+// ============================================================================
+//
+// Con STREAM_RESUME_SECONDS > 0, cada flujo de POST /api/v1/chat recibe un ID
+// (cabecera X-Stream-ID) y cada evento un "id: <flujo>:<n>". Los eventos se
+// guardan mientras dura el flujo y STREAM_RESUME_SECONDS más.
+//
+// Si la conexión se corta, la generación NO se cancela: sigue llenando el
+// buffer, como mucho hasta el plazo de la petición (X-Request-Timeout) o
+// maxDetachedStream. El cliente reconecta con la cabecera Last-Event-ID (el último id
+// que recibió) y recibe lo que le falta, sin pagar una generación nueva:
+//
+//   - GET /api/v1/chat/streams/{id}: para EventSource, que envía
+//     Last-Event-ID él solo al reconectar (sin ella, el flujo desde el inicio)
+//   - repitiendo el POST /api/v1/chat con Last-Event-ID (clientes con fetch)
+//
+// Un flujo terminado y ya entregado entero responde 204, que indica a
+// EventSource que deje de reconectar. Los flujos solo los puede reanudar el
+// mismo tenant con la misma API key.
+//
+// El buffer está en memoria: con varias réplicas, la reconexión tiene que
+// llegar a la misma (sticky sessions en el balanceador).
+// ============================================================================
+
+// StreamIDHeader es la cabecera con el ID del flujo reanudable
+const StreamIDHeader = "X-Stream-ID"
+
+// maxDetachedStream es lo máximo que dura una generación reanudable sin
+// X-Request-Timeout: sin él, un proveedor colgado la mantendría (y su hueco
+// en el bulkhead) para siempre después de que el cliente se vaya
+const maxDetachedStream = 10 * time.Minute
+
+// minStreamPurgeInterval es el intervalo mínimo entre purgas de los flujos
+// caducados
+const minStreamPurgeInterval = time.Minute
+
+// lastEventIDHeader es la cabecera con la que el cliente indica el último
+// evento recibido (la envía EventSource al reconectar)
+const lastEventIDHeader = "Last-Event-ID"
+
+// streamSession son los eventos de un flujo, para reenviarlos al reconectar
+type streamSession struct {
+	id string
+
+	// owner es el tenant y la API key que iniciaron el flujo
+	owner string
+
+	mu     sync.Mutex
+	frames [][]byte
+	done   bool
+
+	// changed se cierra (y se sustituye) con cada evento nuevo o al terminar,
+	// para despertar a los clientes que esperan
+	changed chan struct{}
+
+	// finishedAt es cuándo terminó el flujo (cero = sigue en curso)
+	finishedAt time.Time
+}
+
+// append guarda un evento ya serializado y retorna su número (desde 1)
+func (s *streamSession) append(frame []byte) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.frames = append(s.frames, append([]byte(nil), frame...))
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return len(s.frames)
+}
+
+// finish marca el flujo como terminado
+func (s *streamSession) finish(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return
+	}
+	s.done = true
+	s.finishedAt = now
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// since retorna los eventos posteriores al número after, si el flujo ha
+// terminado y un canal que se cierra cuando haya novedades
+// after no puede superar los eventos guardados (ver sent)
+func (s *streamSession) since(after int) ([][]byte, bool, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.frames[after:], s.done, s.changed
+}
+
+// sent indica si el flujo ha llegado ya al evento número seq
+func (s *streamSession) sent(seq int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return seq <= len(s.frames)
+}
+
+// streamSessions guarda los flujos reanudables
+type streamSessions struct {
+	// window es cuánto se guarda un flujo después de terminar
+	window time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*streamSession
+	now      func() time.Time
+}
+
+// newStreamSessions crea el almacén de flujos
+// Los caducados se purgan en segundo plano durante toda la vida del proceso
+// (una réplica sin flujos nuevos también los libera)
+func newStreamSessions(window time.Duration) *streamSessions {
+	sessions := &streamSessions{
+		window:   window,
+		sessions: make(map[string]*streamSession),
+		now:      time.Now,
+	}
+	go sessions.runPurge(max(window, minStreamPurgeInterval))
+	return sessions
+}
+
+// runPurge olvida periódicamente los flujos caducados
+func (s *streamSessions) runPurge(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.mu.Lock()
+		s.purge(s.now())
+		s.mu.Unlock()
+	}
+}
+
+// purge olvida los flujos caducados (con s.mu bloqueado)
+func (s *streamSessions) purge(now time.Time) {
+	for id, existing := range s.sessions {
+		if s.expired(existing, now) {
+			delete(s.sessions, id)
+		}
+	}
+}
+
+// create registra un flujo nuevo y olvida los que han caducado
+func (s *streamSessions) create(owner string) (*streamSession, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+	session := &streamSession{
+		id:      hex.EncodeToString(bytes),
+		owner:   owner,
+		changed: make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purge(s.now())
+	s.sessions[session.id] = session
+	return session, nil
+}
+
+// get retorna el flujo de owner con ese ID (nil si no existe o caducó)
+func (s *streamSessions) get(id, owner string) *streamSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok || session.owner != owner || s.expired(session, s.now()) {
+		return nil
+	}
+	return session
+}
+
+// finish marca el flujo como terminado: desde ahora corre su ventana
+func (s *streamSessions) finish(session *streamSession) {
+	session.finish(s.now())
+}
+
+// expired indica si el flujo terminó hace más de window
+func (s *streamSessions) expired(session *streamSession, now time.Time) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.done && now.Sub(session.finishedAt) > s.window
+}
+
+// detachStream separa la generación de la conexión del cliente, pero no de
+// su plazo: conserva el de X-Request-Timeout o, si no hay, maxDetachedStream
+func detachStream(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return context.WithTimeout(detached, maxDetachedStream)
+}
+
+// requestOwner identifica al cliente de una petición: el tenant y la API key
+// (los flujos y las Idempotency-Key de un cliente no las ve otro)
+func requestOwner(r *http.Request) string {
+	owner := domain.TenantFromContext(r.Context())
+	if key := domain.APIKeyFromContext(r.Context()); key != nil {
+		owner += "/" + key.ID
+	}
+	return owner
+}
+
+// parseLastEventID separa "<flujo>:<n>" en el ID del flujo y el número
+func parseLastEventID(value string) (string, int, bool) {
+	id, seq, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok || id == "" {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(seq)
+	if err != nil || n < 0 {
+		return "", 0, false
+	}
+	return id, n, true
+}
+
+// writeStreamFrame escribe un evento guardado con su línea "id:"
+func writeStreamFrame(w io.Writer, streamID string, seq int, frame []byte) error {
+	if _, err := io.WriteString(w, "id: "+streamID+":"+strconv.Itoa(seq)+"\n"); err != nil {
+		return err
+	}
+	_, err := w.Write(frame)
+	return err
+}
+
+// streamRelay guarda cada evento en el flujo y lo envía al cliente mientras
+// siga conectado. Si el cliente se va, los eventos se siguen guardando
+// Cada Write recibe un evento completo (ver sseWriter)
+type streamRelay struct {
+	session *streamSession
+	client  io.Writer
+	flush   func() error
+
+	// gone indica que el cliente se desconectó
+	gone bool
+}
+
+// Write implementa io.Writer: nunca falla, aunque el cliente ya no esté
+func (r *streamRelay) Write(frame []byte) (int, error) {
+	seq := r.session.append(frame)
+	if !r.gone {
+		if err := writeStreamFrame(r.client, r.session.id, seq, frame); err != nil {
+			r.disconnect(err)
+		}
+	}
+	return len(frame), nil
+}
+
+// Flush envía al cliente lo escrito (nunca falla, como Write)
+func (r *streamRelay) Flush() error {
+	if !r.gone {
+		if err := r.flush(); err != nil {
+			r.disconnect(err)
+		}
+	}
+	return nil
+}
+
+// disconnect deja de escribir al cliente
+func (r *streamRelay) disconnect(err error) {
+	r.gone = true
+	log.Printf("Cliente desconectado del flujo %s (%v): la generación sigue para poder reanudarlo", r.session.id, err)
+}
+
+// HandleResumeStream maneja GET /api/v1/chat/streams/{id}
+// Reenvía los eventos posteriores a Last-Event-ID y sigue el flujo si no ha terminado
+func (h *ChatHandler) HandleResumeStream(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleResumeStream", r.Method, r.URL.Path)
+
+	streamID := mux.Vars(r)["id"]
+	after := 0
+	if id, seq, ok := parseLastEventID(r.Header.Get(lastEventIDHeader)); ok && id == streamID {
+		after = seq
+	}
+	h.replayStream(w, r, streamID, after)
+}
+
+// resumeChatStream atiende un POST /api/v1/chat con Last-Event-ID
+func (h *ChatHandler) resumeChatStream(w http.ResponseWriter, r *http.Request, lastEventID string) {
+	streamID, after, ok := parseLastEventID(lastEventID)
+	if !ok {
+		writeErrorResponse(w, "Last-Event-ID inválido (formato: <flujo>:<n>)", http.StatusBadRequest)
+		return
+	}
+	h.replayStream(w, r, streamID, after)
+}
+
+// replayStream envía los eventos del flujo posteriores a after y, si sigue
+// en curso, los nuevos a medida que llegan
+func (h *ChatHandler) replayStream(w http.ResponseWriter, r *http.Request, streamID string, after int) {
+	session := h.streams.get(streamID, requestOwner(r))
+	if session == nil {
+		writeErrorResponse(w, "flujo no encontrado o caducado", http.StatusNotFound)
+		return
+	}
+	// Un número que el flujo aún no ha emitido no lo puede haber recibido el
+	// cliente: continuar desde ahí daría ids equivocados
+	if !session.sent(after) {
+		writeErrorResponse(w, "Last-Event-ID posterior al último evento del flujo", http.StatusBadRequest)
+		return
+	}
+
+	frames, done, changed := session.since(after)
+	if done && len(frames) == 0 {
+		// Ya lo tiene todo: 204 hace que EventSource deje de reconectar
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Set(StreamIDHeader, session.id)
+	out, flush, finish := startSSE(w, r, h.sseRetry)
+	defer finish()
+
+	ctx := r.Context()
+	for {
+		for _, frame := range frames {
+			after++
+			if err := writeStreamFrame(out, session.id, after, frame); err != nil {
+				return
+			}
+		}
+		if err := flush(); err != nil || done {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changed:
+		}
+		frames, done, changed = session.since(after)
+	}
+}
@@ -0,0 +1,45 @@
+package http
+
+import (
+	"io"
+	"testing"
+)
+
+// BenchmarkSSEWriteEvent mide la escritura de un fragmento del streaming: con
+// el buffer y el encoder reutilizados no debería reservar memoria por evento
+func BenchmarkSSEWriteEvent(b *testing.B) {
+	events := newSSEWriter(io.Discard)
+	chunk := &StreamChunkResponse{Content: " palabra", Model: "llama-3.3-70b-versatile"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := events.WriteEvent("", chunk); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSSEWriteLastEvent mide el último fragmento (con el uso y el motivo
+// de fin) seguido del [DONE]
+func BenchmarkSSEWriteLastEvent(b *testing.B) {
+	events := newSSEWriter(io.Discard)
+	chunk := &StreamChunkResponse{
+		Model:        "llama-3.3-70b-versatile",
+		FinishReason: "stop",
+		Usage:        &UsageInfo{PromptTokens: 12, CompletionTokens: 64, TotalTokens: 76, CostUSD: 0.000134},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := events.WriteEvent("", chunk); err != nil {
+			b.Fatal(err)
+		}
+		if err := events.WriteDone(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
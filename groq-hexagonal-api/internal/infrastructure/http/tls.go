@@ -0,0 +1,146 @@
+// Package http - HTTPS y certificados de cliente (mTLS)
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// TLS Y mTLS
+// ============================================================================
+//
+// Sin un proxy delante, el servidor puede servir HTTPS directamente con un
+// certificado y su clave en PEM. Con mTLS, además, verifica el certificado
+// del cliente contra las CAs de TLSOptions.ClientCAFile:
+//
+//   - "optional": se verifica si el cliente envía uno (las peticiones sin
+//     certificado pasan; la autenticación queda en la API key)
+//   - "require": sin un certificado válido no hay conexión
+//
+// Los certificados de servidor de vida corta se renuevan sin reiniciar: cada
+// certReloadInterval se comprueba si el archivo ha cambiado y, si es así, se
+// vuelve a cargar. Si la carga falla, se sigue usando el anterior. Las CAs
+// de cliente se leen solo al arrancar.
+// ============================================================================
+
+// certReloadInterval es cada cuánto se comprueba si el certificado cambió
+const certReloadInterval = 30 * time.Second
+
+// TLSOptions configura el HTTPS del servidor
+type TLSOptions struct {
+	// CertFile y KeyFile son el certificado (con la cadena) y la clave en PEM
+	CertFile string
+	KeyFile  string
+
+	// MinVersion es "1.2" o "1.3" (vacío = "1.2")
+	MinVersion string
+
+	// ClientCAFile son las CAs de los certificados de cliente en PEM
+	// ClientAuth es "none", "optional" o "require" (vacío = "none")
+	ClientCAFile string
+	ClientAuth   string
+}
+
+// NewTLSConfig crea la configuración TLS del servidor
+// Falla si el certificado, la clave o las CAs no se pueden cargar
+func NewTLSConfig(options TLSOptions) (*tls.Config, error) {
+	reloader, err := newCertReloader(options.CertFile, options.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.getCertificate,
+	}
+	switch options.MinVersion {
+	case "", "1.2":
+	case "1.3":
+		config.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("versión de TLS desconocida: %q", options.MinVersion)
+	}
+
+	switch options.ClientAuth {
+	case "", "none":
+		return config, nil
+	case "optional":
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case "require":
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("modo de certificados de cliente desconocido: %q", options.ClientAuth)
+	}
+
+	pem, err := os.ReadFile(options.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer las CAs de cliente: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%s no contiene ningún certificado PEM", options.ClientCAFile)
+	}
+	config.ClientCAs = pool
+	return config, nil
+}
+
+// certReloader entrega el certificado del servidor y lo recarga si cambia
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu          sync.Mutex
+	cert        *tls.Certificate
+	modTime     time.Time
+	lastChecked time.Time
+}
+
+// newCertReloader carga el certificado; un error aquí impide arrancar
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	reloader := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := reloader.load(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// load lee el certificado y la clave
+func (c *certReloader) load() error {
+	info, err := os.Stat(c.certFile)
+	if err != nil {
+		return fmt.Errorf("error al leer el certificado: %w", err)
+	}
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("error al cargar el certificado: %w", err)
+	}
+
+	c.cert = &cert
+	c.modTime = info.ModTime()
+	return nil
+}
+
+// getCertificate implementa tls.Config.GetCertificate
+func (c *certReloader) getCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(c.lastChecked) >= certReloadInterval {
+		c.lastChecked = now
+		if info, err := os.Stat(c.certFile); err == nil && !info.ModTime().Equal(c.modTime) {
+			if err := c.load(); err != nil {
+				log.Printf("⚠️  Error al recargar el certificado TLS (se sigue usando el anterior): %v", err)
+			} else {
+				log.Printf("🔐 Certificado TLS recargado: %s", c.certFile)
+			}
+		}
+	}
+	return c.cert, nil
+}
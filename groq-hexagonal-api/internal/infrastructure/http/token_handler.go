@@ -0,0 +1,62 @@
+// Package http - Handler de los tokens de vida corta
+package http
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+)
+
+// ============================================================================
+// TOKENS DE VIDA CORTA
+// ============================================================================
+//
+// POST /api/v1/token cambia la API key de la petición por un token que
+// caduca pronto y solo vale para los scopes pedidos (ej: un frontend que
+// solo chatea). El token se usa igual que la clave, en
+// "Authorization: Bearer", y cuenta como la clave en el rate limit y el
+// consumo. Un token no puede pedir otros tokens.
+// ============================================================================
+
+// TokenHandler maneja POST /api/v1/token
+// Solo tiene sentido con API_KEY_AUTH: necesita la clave en el contexto
+type TokenHandler struct {
+	apiKeys domain.APIKeyService
+}
+
+// NewTokenHandler crea un nuevo handler con el servicio inyectado
+func NewTokenHandler(service domain.APIKeyService) *TokenHandler {
+	if service == nil {
+		panic("apiKeyService no puede ser nil")
+	}
+
+	return &TokenHandler{
+		apiKeys: service,
+	}
+}
+
+// HandleIssue maneja POST /api/v1/token
+func (h *TokenHandler) HandleIssue(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleIssueToken", r.Method, r.URL.Path)
+
+	key := domain.APIKeyFromContext(r.Context())
+	if key == nil {
+		writeServiceError(w, domain.ErrInvalidAPIKey, "")
+		return
+	}
+
+	var req TokenRequest
+	if err := decodeJSON(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	token, err := h.apiKeys.IssueToken(r.Context(), key, req.toDomain())
+	if err != nil {
+		writeServiceError(w, err, "error al emitir el token")
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSONResponse(w, NewTokenResponse(token), http.StatusCreated)
+}
@@ -0,0 +1,135 @@
+// Package http - Handler HTTP de transcripción de audio
+package http
+
+import (
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/application"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// TRANSCRIPCIÓN DE AUDIO
+// ============================================================================
+//
+// POST /api/v1/audio/transcriptions recibe un multipart/form-data:
+//
+//   file         el audio (obligatorio; ver domain.SupportedAudioFormats)
+//   model        modelo Whisper (por defecto, TRANSCRIPTION_MODEL)
+//   language     idioma del audio en ISO 639-1 (por defecto, se detecta)
+//   prompt       texto que orienta el estilo o el vocabulario
+//   temperature  0 a 1
+//
+// El tamaño y el formato se comprueban aquí, antes de subir nada a Groq.
+// ============================================================================
+
+const (
+	// transcriptionFormOverhead es el margen para los campos de texto y las
+	// cabeceras multipart, además del propio audio
+	transcriptionFormOverhead = 1 << 20 // 1 MiB
+
+	// transcriptionMemoryLimit es lo que se guarda en memoria al parsear el
+	// formulario; el resto del audio va a un fichero temporal
+	transcriptionMemoryLimit = 8 << 20 // 8 MiB
+)
+
+// Errores de validación de la transcripción
+var (
+	ErrMissingAudioFile      = NewValidationError("falta el fichero de audio (campo file)")
+	ErrAudioTooLarge         = NewValidationError(fmt.Sprintf("el audio supera el tamaño máximo de %d MiB", domain.MaxAudioBytes>>20))
+	ErrInvalidTranscriptTemp = NewValidationError("temperature debe estar entre 0 y 1")
+)
+
+// TranscriptionHandler maneja las peticiones HTTP de transcripción
+type TranscriptionHandler struct {
+	transcriptionService domain.TranscriptionService
+}
+
+// NewTranscriptionHandler crea un nuevo handler con el servicio inyectado
+func NewTranscriptionHandler(service domain.TranscriptionService) *TranscriptionHandler {
+	if service == nil {
+		panic("transcriptionService no puede ser nil")
+	}
+
+	return &TranscriptionHandler{
+		transcriptionService: service,
+	}
+}
+
+// HandleTranscription maneja POST /api/v1/audio/transcriptions
+func (h *TranscriptionHandler) HandleTranscription(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleTranscription", r.Method, r.URL.Path)
+
+	// MaxBytesReader corta la lectura al pasar del límite: un fichero enorme
+	// no llega a escribirse entero en disco
+	r.Body = http.MaxBytesReader(w, r.Body, domain.MaxAudioBytes+transcriptionFormOverhead)
+	if err := r.ParseMultipartForm(transcriptionMemoryLimit); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeErrorResponse(w, ErrAudioTooLarge.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		writeErrorResponse(w, "formulario multipart inválido: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	// RemoveAll borra los ficheros temporales del formulario
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeErrorResponse(w, ErrMissingAudioFile.Error(), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	// ========================================================================
+	// VALIDACIÓN DEL AUDIO
+	// ========================================================================
+
+	if header.Size == 0 {
+		writeErrorResponse(w, application.ErrEmptyAudio.Error(), http.StatusBadRequest)
+		return
+	}
+	if header.Size > domain.MaxAudioBytes {
+		writeErrorResponse(w, ErrAudioTooLarge.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	// 415 Unsupported Media Type: el formato del fichero no se admite
+	if !domain.IsSupportedAudioFile(header.Filename) {
+		writeErrorResponse(w, application.ErrUnsupportedAudioFormat.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	request := domain.TranscriptionRequest{
+		Audio:    file,
+		Filename: header.Filename,
+		Model:    strings.TrimSpace(r.FormValue("model")),
+		Language: strings.TrimSpace(r.FormValue("language")),
+		Prompt:   r.FormValue("prompt"),
+	}
+	if value := r.FormValue("temperature"); value != "" {
+		temperature, err := strconv.ParseFloat(value, 64)
+		if err != nil || temperature < 0 || temperature > 1 {
+			writeErrorResponse(w, ErrInvalidTranscriptTemp.Error(), http.StatusBadRequest)
+			return
+		}
+		request.Temperature = &temperature
+	}
+
+	// ========================================================================
+	// LLAMADA AL SERVICIO
+	// ========================================================================
+
+	transcription, err := h.transcriptionService.Transcribe(r.Context(), request)
+	if err != nil {
+		writeServiceError(w, err, "error al transcribir el audio")
+		return
+	}
+	annotateAccessLog(r.Context(), transcription.Model, nil)
+
+	writeJSONResponse(w, NewTranscriptionResponse(transcription), http.StatusOK)
+}
@@ -0,0 +1,39 @@
+// Package http - Interfaz web de chat embebida
+package http
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// ============================================================================
+// INTERFAZ WEB (/ui)
+// ============================================================================
+//
+// Una página de chat (HTML, CSS y JS sin dependencias ni build) que va dentro
+// del binario con go:embed: sirve para enseñar el proyecto o probarlo en un
+// navegador sin desplegar nada más. Usa los endpoints públicos como cualquier
+// otro cliente:
+//
+//   - GET /api/v1/capabilities y GET /api/v1/models al cargar
+//   - POST /api/v1/chat con el historial de la página, con streaming (SSE
+//     leído con fetch) si el despliegue lo admite
+//
+// Con API_KEY_AUTH, la API key se escribe en la página y se guarda en el
+// localStorage del navegador; el servidor no la ve hasta la primera petición.
+// WEB_UI=false la desactiva.
+// ============================================================================
+
+//go:embed ui
+var uiFiles embed.FS
+
+// uiHandler sirve los archivos de la interfaz bajo /ui/
+func uiHandler() http.Handler {
+	files, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		// El directorio se embebe al compilar: no puede faltar
+		panic(err)
+	}
+	return http.StripPrefix("/ui", http.FileServer(http.FS(files)))
+}
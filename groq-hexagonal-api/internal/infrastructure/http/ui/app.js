@@ -0,0 +1,245 @@
+// Interfaz web de chat de Groq Hexagonal API
+// Usa los mismos endpoints que cualquier cliente: /api/v1/capabilities,
+// /api/v1/models y /api/v1/chat (con SSE si "Streaming" está marcado)
+"use strict";
+
+const API = "/api/v1";
+const KEY_STORAGE = "groq-hexagonal-api.key";
+
+const $ = (id) => document.getElementById(id);
+const els = {
+  model: $("model"),
+  apiKey: $("api-key"),
+  stream: $("stream"),
+  reset: $("reset"),
+  systemPrompt: $("system-prompt"),
+  messages: $("messages"),
+  chat: $("chat"),
+  input: $("input"),
+  send: $("send"),
+  status: $("status"),
+};
+
+// history son los turnos anteriores, que se envían en cada petición
+let history = [];
+
+// ============================================================================
+// PETICIONES
+// ============================================================================
+
+function headers() {
+  const h = { "Content-Type": "application/json" };
+  const key = els.apiKey.value.trim();
+  if (key) {
+    h["Authorization"] = "Bearer " + key;
+  }
+  return h;
+}
+
+// errorMessage extrae el mensaje de una respuesta de error de la API
+async function errorMessage(response) {
+  try {
+    const body = await response.json();
+    if (body && body.error) {
+      return body.error;
+    }
+  } catch (_) {
+    // El body no es JSON (un proxy, por ejemplo)
+  }
+  return "HTTP " + response.status;
+}
+
+async function loadCapabilities() {
+  const response = await fetch(API + "/capabilities", { headers: headers() });
+  if (!response.ok) {
+    throw new Error(await errorMessage(response));
+  }
+  const caps = await response.json();
+  if (!caps.features.streaming) {
+    els.stream.checked = false;
+    els.stream.disabled = true;
+  }
+  const provider = (caps.providers || []).find((p) => p.default);
+  if (provider && provider.default_model) {
+    els.model.options[0].textContent = "(por defecto: " + provider.default_model + ")";
+  }
+  return caps;
+}
+
+async function loadModels() {
+  const response = await fetch(API + "/models", { headers: headers() });
+  if (!response.ok) {
+    throw new Error(await errorMessage(response));
+  }
+  const body = await response.json();
+  const selected = els.model.value;
+  els.model.length = 1;
+  for (const model of body.models || []) {
+    const option = document.createElement("option");
+    option.value = model.id;
+    option.textContent = model.id;
+    els.model.appendChild(option);
+  }
+  els.model.value = selected;
+}
+
+function chatRequest(message, stream) {
+  const request = { message, stream, history };
+  if (els.model.value) {
+    request.model = els.model.value;
+  }
+  const systemPrompt = els.systemPrompt.value.trim();
+  if (systemPrompt) {
+    request.system_prompt = systemPrompt;
+  }
+  return fetch(API + "/chat", {
+    method: "POST",
+    headers: headers(),
+    body: JSON.stringify(request),
+  });
+}
+
+// readStream lee los eventos SSE de la respuesta y llama a onContent con
+// cada fragmento de texto; lanza un Error con los eventos "error"
+async function readStream(response, onContent) {
+  const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+  let buffer = "";
+
+  for (;;) {
+    const { value, done } = await reader.read();
+    if (done) {
+      return;
+    }
+    buffer += value;
+
+    // Los eventos terminan en una línea vacía
+    let end;
+    while ((end = buffer.indexOf("\n\n")) >= 0) {
+      const raw = buffer.slice(0, end);
+      buffer = buffer.slice(end + 2);
+
+      let event = "message";
+      let data = "";
+      for (const line of raw.split("\n")) {
+        if (line.startsWith("event: ")) {
+          event = line.slice(7);
+        } else if (line.startsWith("data: ")) {
+          data += line.slice(6);
+        }
+      }
+      if (data === "" || data === "[DONE]") {
+        continue;
+      }
+
+      const payload = JSON.parse(data);
+      if (event === "error") {
+        throw new Error(payload.error || "error durante el streaming");
+      }
+      if (payload.content) {
+        onContent(payload.content);
+      }
+    }
+  }
+}
+
+// ============================================================================
+// PÁGINA
+// ============================================================================
+
+function addMessage(role, text) {
+  const div = document.createElement("div");
+  div.className = "message " + role;
+  div.textContent = text;
+  els.messages.appendChild(div);
+  els.messages.scrollTop = els.messages.scrollHeight;
+  return div;
+}
+
+function setBusy(busy, status) {
+  els.send.disabled = busy;
+  els.input.disabled = busy;
+  els.status.textContent = status || "";
+}
+
+async function send(message) {
+  const stream = els.stream.checked;
+  addMessage("user", message);
+  setBusy(true, "Esperando respuesta...");
+
+  const bubble = addMessage("assistant", "");
+  const started = performance.now();
+  try {
+    const response = await chatRequest(message, stream);
+    if (!response.ok) {
+      throw new Error(await errorMessage(response));
+    }
+
+    let reply;
+    let model = "";
+    if (stream) {
+      reply = "";
+      await readStream(response, (content) => {
+        reply += content;
+        bubble.textContent = reply;
+        els.messages.scrollTop = els.messages.scrollHeight;
+      });
+    } else {
+      const body = await response.json();
+      reply = body.message;
+      model = body.model;
+      bubble.textContent = reply;
+    }
+
+    history.push({ role: "user", content: message }, { role: "assistant", content: reply });
+    const seconds = ((performance.now() - started) / 1000).toFixed(1);
+    setBusy(false, (model ? model + " · " : "") + seconds + " s");
+  } catch (err) {
+    bubble.remove();
+    addMessage("error", err.message);
+    setBusy(false);
+  }
+  els.input.focus();
+}
+
+async function init() {
+  els.apiKey.value = localStorage.getItem(KEY_STORAGE) || "";
+  try {
+    await loadCapabilities();
+    await loadModels();
+    els.status.textContent = "";
+  } catch (err) {
+    els.status.textContent = "No se pudo conectar con la API: " + err.message;
+  }
+}
+
+els.chat.addEventListener("submit", (event) => {
+  event.preventDefault();
+  const message = els.input.value.trim();
+  if (!message) {
+    return;
+  }
+  els.input.value = "";
+  send(message);
+});
+
+els.input.addEventListener("keydown", (event) => {
+  if (event.key === "Enter" && !event.shiftKey) {
+    event.preventDefault();
+    els.chat.requestSubmit();
+  }
+});
+
+// Al cambiar la key se guarda y se vuelven a pedir los modelos
+els.apiKey.addEventListener("change", () => {
+  localStorage.setItem(KEY_STORAGE, els.apiKey.value.trim());
+  init();
+});
+
+els.reset.addEventListener("click", () => {
+  history = [];
+  els.messages.textContent = "";
+  els.status.textContent = "";
+  els.input.focus();
+});
+
+init();
@@ -0,0 +1,160 @@
+// Package http - Consumo de tokens y cuotas diarias
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ============================================================================
+// CONSUMO Y CUOTAS
+// ============================================================================
+//
+// Cada petición a /api/v1 suma sus tokens al día de hoy del cliente: su API
+// key (Authorization: Bearer ...); sin ella, el tenant o la IP, como el rate
+// limit. Los tokens son los que el handler apunta en el access log, así que
+// se cuentan igual en JSON y en streaming. Las respuestas servidas desde la
+// caché no se cuentan: no han consumido tokens del proveedor.
+//
+// Con cuota (DAILY_TOKEN_QUOTA), el cliente que la ha agotado recibe 429
+// quota_exceeded con Retry-After hasta el día siguiente (UTC). Las peticiones
+// GET no consumen tokens: se atienden siempre, y así GET /api/v1/usage sigue
+// funcionando con la cuota agotada.
+//
+// Cada petición registrada lleva un identificador para los eventos de consumo
+// (ver domain.UsageEvent): el de la cabecera X-Request-ID o, sin ella, uno
+// nuevo. Se devuelve en la misma cabecera para correlacionar ambos lados.
+// ============================================================================
+
+// RequestIDHeader identifica la petición en los eventos de consumo
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength es el máximo de un X-Request-ID del cliente (más largo,
+// se genera uno nuevo)
+const maxRequestIDLength = 128
+
+// UsageHandler maneja GET /api/v1/usage y el registro del consumo
+type UsageHandler struct {
+	usageService domain.UsageService
+}
+
+// NewUsageHandler crea un nuevo handler con el servicio inyectado
+func NewUsageHandler(service domain.UsageService) *UsageHandler {
+	if service == nil {
+		panic("usageService no puede ser nil")
+	}
+
+	return &UsageHandler{
+		usageService: service,
+	}
+}
+
+// HandleUsage maneja GET /api/v1/usage
+// ?days=N añade el consumo de los N días anteriores (máximo 31)
+func (h *UsageHandler) HandleUsage(w http.ResponseWriter, r *http.Request) {
+	log.Printf("[%s] %s - HandleUsage", r.Method, r.URL.Path)
+
+	days := 0
+	if value := r.URL.Query().Get("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 || parsed > domain.MaxUsageHistoryDays {
+			writeErrorResponse(w, "days debe ser un entero entre 0 y "+strconv.Itoa(domain.MaxUsageHistoryDays), http.StatusBadRequest)
+			return
+		}
+		days = parsed
+	}
+
+	report, err := h.usageService.Report(r.Context(), usageClient(r), days)
+	if err != nil {
+		writeServiceError(w, err, "error al leer el consumo")
+		return
+	}
+	writeJSONResponse(w, NewUsageResponse(report), http.StatusOK)
+}
+
+// trackUsage es el middleware que aplica la cuota y registra el consumo
+// Si el almacén del consumo no responde se deja pasar (como el rate limit)
+func (h *UsageHandler) trackUsage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		started := time.Now()
+		client := usageClient(r)
+		report, err := h.usageService.Report(r.Context(), client, 0)
+		if err != nil {
+			log.Printf("⚠️  Cuota de tokens no disponible: %v", err)
+		} else if report.QuotaExceeded() {
+			response := NewErrorResponse("cuota diaria de tokens agotada", http.StatusTooManyRequests)
+			response.Type = "quota_exceeded"
+			response.RetryAfter = int(math.Ceil(time.Until(report.ResetAt).Seconds()))
+			w.Header().Set("Retry-After", strconv.Itoa(response.RetryAfter))
+			writeJSONResponse(w, response, http.StatusTooManyRequests)
+			return
+		}
+
+		requestID := requestIDFor(r)
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r)
+
+		model, usage := annotatedUsage(r.Context())
+		if usage == nil || w.Header().Get(CacheStatusHeader) == string(domain.CacheHit) {
+			return
+		}
+		// El cliente puede haberse ido: el consumo se registra igualmente
+		ctx := domain.WithRequestInfo(context.WithoutCancel(r.Context()), domain.RequestInfo{
+			ID:        requestID,
+			StartedAt: started,
+		})
+		if conversationID := usageConversation(r); conversationID != "" {
+			ctx = domain.WithConversation(ctx, conversationID)
+		}
+		if err := h.usageService.Record(ctx, client, model, *usage); err != nil {
+			log.Printf("⚠️  %v", err)
+		}
+	})
+}
+
+// requestIDFor retorna el X-Request-ID de la petición o uno nuevo
+func requestIDFor(r *http.Request) string {
+	if id := r.Header.Get(RequestIDHeader); id != "" && len(id) <= maxRequestIDLength {
+		return id
+	}
+	bytes := make([]byte, 16)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// usageConversation retorna la conversación de la petición para el extracto
+// del tenant (vacío si no es de una conversación)
+func usageConversation(r *http.Request) string {
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/conversations/") {
+		return ""
+	}
+	return mux.Vars(r)["id"]
+}
+
+// usageClient identifica al cliente del consumo: API key, tenant o IP
+// La API key va primero: el tenant es una cabecera que el cliente elige
+// (con autenticación, la clave es la del contexto; ver rateLimitKey)
+func usageClient(r *http.Request) string {
+	if domain.APIKeyFromContext(r.Context()) != nil {
+		return rateLimitKey(r)
+	}
+	if hash := apiKeyHash(r); hash != "" {
+		return "key:" + hash
+	}
+	return rateLimitKey(r)
+}
@@ -0,0 +1,108 @@
+// Package jsoncodec abstrae la serialización JSON de la capa HTTP
+// Por defecto usa encoding/json; las librerías más rápidas se enlazan con
+// etiquetas de compilación (ver jsoniter.go y segmentio.go)
+package jsoncodec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// CODECS JSON
+// ============================================================================
+//
+// Con muchas peticiones por segundo, serializar los DTOs (sobre todo los
+// fragmentos del streaming) es una parte apreciable de la CPU. Codec es la
+// interfaz mínima que usa la capa HTTP para leer y escribir JSON, y JSON_CODEC
+// elige la implementación al arrancar:
+//
+//   - "std": encoding/json (por defecto, sin dependencias)
+//   - "jsoniter": github.com/json-iterator/go (make build-jsoniter)
+//   - "segmentio": github.com/segmentio/encoding/json (make build-segmentio)
+//
+// Las dos alternativas son compatibles con encoding/json (mismas etiquetas
+// `json:"..."`, omitempty, json.RawMessage...), así que los DTOs no cambian.
+// Igual que gRPC o PostgreSQL, solo se enlazan con su etiqueta: el binario
+// por defecto no depende de ellas.
+// ============================================================================
+
+// Nombres de los codecs
+const (
+	Std       = "std"
+	Jsoniter  = "jsoniter"
+	Segmentio = "segmentio"
+)
+
+// Codec serializa y deserializa JSON
+// Las implementaciones deben ser seguras para usarlas desde varias goroutines
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+
+	// NewEncoder escribe cada valor en w seguido de '\n' (como json.Encoder)
+	NewEncoder(w io.Writer) Encoder
+
+	// NewDecoder lee valores de r (como json.Decoder)
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder escribe valores JSON en un writer
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder lee valores JSON de un reader
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// codecs son los codecs compilados en el binario
+// Los ficheros con etiqueta de compilación añaden el suyo en init()
+var codecs = map[string]Codec{
+	Std: stdCodec{},
+}
+
+// register añade un codec (solo desde init: el map no tiene mutex)
+func register(name string, codec Codec) {
+	codecs[name] = codec
+}
+
+// Get retorna el codec con ese nombre
+// Retorna error si no existe o si el binario se compiló sin él
+func Get(name string) (Codec, error) {
+	if codec, ok := codecs[name]; ok {
+		return codec, nil
+	}
+	switch name {
+	case Jsoniter, Segmentio:
+		return nil, fmt.Errorf("binario compilado sin el codec JSON %q (usa make build-%s)", name, name)
+	}
+	return nil, fmt.Errorf("codec JSON desconocido: %q (disponibles: %s)", name, strings.Join(Names(), ", "))
+}
+
+// Names retorna los codecs compilados en el binario, en orden alfabético
+func Names() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Standard retorna el codec de encoding/json
+func Standard() Codec {
+	return stdCodec{}
+}
+
+// stdCodec implementa Codec con encoding/json
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (stdCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (stdCodec) NewEncoder(w io.Writer) Encoder             { return json.NewEncoder(w) }
+func (stdCodec) NewDecoder(r io.Reader) Decoder             { return json.NewDecoder(r) }
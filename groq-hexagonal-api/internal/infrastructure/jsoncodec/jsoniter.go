@@ -0,0 +1,27 @@
+//go:build jsoniter
+
+package jsoncodec
+
+// Registra el codec "jsoniter"
+// Solo se compila con -tags jsoniter (make build-jsoniter)
+
+import (
+	"io"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func init() {
+	register(Jsoniter, jsoniterCodec{api: jsoniter.ConfigCompatibleWithStandardLibrary})
+}
+
+// jsoniterCodec implementa Codec con la configuración de jsoniter compatible
+// con encoding/json (claves ordenadas en los maps, HTML escapado...)
+type jsoniterCodec struct {
+	api jsoniter.API
+}
+
+func (c jsoniterCodec) Marshal(v interface{}) ([]byte, error)      { return c.api.Marshal(v) }
+func (c jsoniterCodec) Unmarshal(data []byte, v interface{}) error { return c.api.Unmarshal(data, v) }
+func (c jsoniterCodec) NewEncoder(w io.Writer) Encoder             { return c.api.NewEncoder(w) }
+func (c jsoniterCodec) NewDecoder(r io.Reader) Decoder             { return c.api.NewDecoder(r) }
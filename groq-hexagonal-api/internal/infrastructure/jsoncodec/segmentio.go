@@ -0,0 +1,25 @@
+//go:build segmentio
+
+package jsoncodec
+
+// Registra el codec "segmentio"
+// Solo se compila con -tags segmentio (make build-segmentio)
+
+import (
+	"io"
+
+	"github.com/segmentio/encoding/json"
+)
+
+func init() {
+	register(Segmentio, segmentioCodec{})
+}
+
+// segmentioCodec implementa Codec con github.com/segmentio/encoding/json,
+// que replica la API (y la salida) de encoding/json
+type segmentioCodec struct{}
+
+func (segmentioCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (segmentioCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (segmentioCodec) NewEncoder(w io.Writer) Encoder             { return json.NewEncoder(w) }
+func (segmentioCodec) NewDecoder(r io.Reader) Decoder             { return json.NewDecoder(r) }
@@ -0,0 +1,121 @@
+// Package jwt firma los tokens de vida corta como JWT (HS256)
+// Implementa el puerto domain.TokenSigner
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// JWT HS256
+// ============================================================================
+//
+// Solo se necesita firmar y verificar tokens propios con un secreto
+// compartido, así que basta con HS256 y la biblioteca estándar. La cabecera
+// tiene que ser exactamente HS256: un token con "alg": "none" u otro
+// algoritmo se rechaza antes de mirar la firma.
+//
+// Claims: jti (ID), sub (ID de la API key), scope (scopes separados por
+// espacios, como en OAuth 2.0), iat, exp e iss.
+// ============================================================================
+
+// Issuer es el claim iss de los tokens
+const Issuer = "groq-hexagonal-api"
+
+// MinSecretLength es la longitud mínima del secreto (256 bits para HS256)
+const MinSecretLength = 32
+
+// header es la cabecera de todos los tokens, ya codificada
+var header = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// errInvalidToken se retorna con cualquier token mal formado o mal firmado
+var errInvalidToken = errors.New("token inválido")
+
+// claims es el payload del JWT
+type claims struct {
+	ID       string `json:"jti"`
+	Subject  string `json:"sub"`
+	Scope    string `json:"scope"`
+	IssuedAt int64  `json:"iat"`
+	Expires  int64  `json:"exp"`
+	Issuer   string `json:"iss"`
+}
+
+// Signer firma y verifica los tokens con HMAC-SHA256
+// Implementa domain.TokenSigner
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner crea el firmante; secret debe tener al menos MinSecretLength bytes
+func NewSigner(secret string) *Signer {
+	if len(secret) < MinSecretLength {
+		panic(fmt.Sprintf("secret debe tener al menos %d bytes", MinSecretLength))
+	}
+
+	return &Signer{secret: []byte(secret)}
+}
+
+// SignToken implementa domain.TokenSigner
+func (s *Signer) SignToken(tokenClaims domain.TokenClaims) (string, error) {
+	payload, err := json.Marshal(claims{
+		ID:       tokenClaims.ID,
+		Subject:  tokenClaims.KeyID,
+		Scope:    strings.Join(tokenClaims.Scopes, " "),
+		IssuedAt: tokenClaims.IssuedAt.Unix(),
+		Expires:  tokenClaims.ExpiresAt.Unix(),
+		Issuer:   Issuer,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error al serializar los claims: %w", err)
+	}
+
+	signingInput := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + s.sign(signingInput), nil
+}
+
+// VerifyToken implementa domain.TokenSigner
+func (s *Signer) VerifyToken(token string) (*domain.TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != header {
+		return nil, errInvalidToken
+	}
+
+	// hmac.Equal compara en tiempo constante
+	expected := s.sign(parts[0] + "." + parts[1])
+	if !hmac.Equal([]byte(parts[2]), []byte(expected)) {
+		return nil, errInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	var decoded claims
+	if err := json.Unmarshal(payload, &decoded); err != nil || decoded.Issuer != Issuer || decoded.Subject == "" {
+		return nil, errInvalidToken
+	}
+
+	return &domain.TokenClaims{
+		ID:        decoded.ID,
+		KeyID:     decoded.Subject,
+		Scopes:    strings.Fields(decoded.Scope),
+		IssuedAt:  time.Unix(decoded.IssuedAt, 0),
+		ExpiresAt: time.Unix(decoded.Expires, 0),
+	}, nil
+}
+
+// sign es la firma HMAC-SHA256 de signingInput en base64url
+func (s *Signer) sign(signingInput string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"groq-hexagonal-api/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSecret tiene exactamente MinSecretLength bytes
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// testClaims son los claims de los tokens de prueba
+func testClaims() domain.TokenClaims {
+	issuedAt := time.Unix(1760600000, 0)
+	return domain.TokenClaims{
+		ID:        "tok-1",
+		KeyID:     "key-1",
+		Scopes:    []string{domain.ScopeChat, domain.ScopeJobs},
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(15 * time.Minute),
+	}
+}
+
+// forge firma un token con una cabecera y un payload cualesquiera
+func forge(secret, rawHeader, rawPayload string) string {
+	signingInput := base64.RawURLEncoding.EncodeToString([]byte(rawHeader)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(rawPayload))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestSignAndVerifyRoundTrip(t *testing.T) {
+	signer := NewSigner(testSecret)
+	claims := testClaims()
+
+	token, err := signer.SignToken(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verified, err := signer.VerifyToken(token)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if !reflect.DeepEqual(*verified, claims) {
+		t.Errorf("VerifyToken = %+v; se esperaba %+v", *verified, claims)
+	}
+}
+
+func TestVerifyRejectsOtherHeaders(t *testing.T) {
+	signer := NewSigner(testSecret)
+	payload := `{"jti":"tok-1","sub":"key-1","scope":"chat","iat":1760600000,"exp":1760600900,"iss":"groq-hexagonal-api"}`
+
+	headers := map[string]string{
+		"alg none":          `{"alg":"none","typ":"JWT"}`,
+		"otro algoritmo":    `{"alg":"HS512","typ":"JWT"}`,
+		"alg en minúsculas": `{"alg":"hs256","typ":"JWT"}`,
+		"campos de más":     `{"alg":"HS256","typ":"JWT","kid":"1"}`,
+		"otro orden":        `{"typ":"JWT","alg":"HS256"}`,
+	}
+	for name, rawHeader := range headers {
+		// Bien firmado con el secreto: solo falla la cabecera
+		if _, err := signer.VerifyToken(forge(testSecret, rawHeader, payload)); err == nil {
+			t.Errorf("%s: VerifyToken aceptó la cabecera %s", name, rawHeader)
+		}
+	}
+
+	// alg none sin firma
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(payload)) + "."
+	if _, err := signer.VerifyToken(unsigned); err == nil {
+		t.Error("VerifyToken aceptó un token sin firma")
+	}
+}
+
+func TestVerifyRejectsTamperedPayload(t *testing.T) {
+	signer := NewSigner(testSecret)
+	token, err := signer.SignToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := strings.Split(token, ".")
+	tampered := `{"jti":"tok-1","sub":"key-1","scope":"chat jobs proxy","iat":1760600000,"exp":1760600900,"iss":"groq-hexagonal-api"}`
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(tampered))
+	if _, err := signer.VerifyToken(strings.Join(parts, ".")); err == nil {
+		t.Error("VerifyToken aceptó un payload modificado")
+	}
+}
+
+func TestVerifyRejectsWrongSecret(t *testing.T) {
+	token, err := NewSigner(testSecret).SignToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := NewSigner(strings.Repeat("x", MinSecretLength))
+	if _, err := other.VerifyToken(token); err == nil {
+		t.Error("VerifyToken aceptó un token firmado con otro secreto")
+	}
+}
+
+func TestVerifyRejectsMissingClaims(t *testing.T) {
+	signer := NewSigner(testSecret)
+	rawHeader := `{"alg":"HS256","typ":"JWT"}`
+
+	payloads := map[string]string{
+		"sin sub":   `{"jti":"tok-1","scope":"chat","iat":1760600000,"exp":1760600900,"iss":"groq-hexagonal-api"}`,
+		"sub vacío": `{"jti":"tok-1","sub":"","scope":"chat","iat":1760600000,"exp":1760600900,"iss":"groq-hexagonal-api"}`,
+		"sin iss":   `{"jti":"tok-1","sub":"key-1","scope":"chat","iat":1760600000,"exp":1760600900}`,
+		"otro iss":  `{"jti":"tok-1","sub":"key-1","scope":"chat","iat":1760600000,"exp":1760600900,"iss":"otro"}`,
+		"no JSON":   `no es json`,
+	}
+	for name, payload := range payloads {
+		if _, err := signer.VerifyToken(forge(testSecret, rawHeader, payload)); err == nil {
+			t.Errorf("%s: VerifyToken aceptó %s", name, payload)
+		}
+	}
+}
+
+func TestVerifyRejectsMalformed(t *testing.T) {
+	signer := NewSigner(testSecret)
+	token, err := signer.SignToken(testClaims())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, malformed := range []string{"", "a.b", token + ".x", strings.TrimSuffix(token, token[len(token)-1:])} {
+		if _, err := signer.VerifyToken(malformed); err == nil {
+			t.Errorf("VerifyToken(%q) no retornó error", malformed)
+		}
+	}
+}
+
+func TestNewSignerRejectsShortSecret(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewSigner aceptó un secreto corto")
+		}
+	}()
+	NewSigner(testSecret[:MinSecretLength-1])
+}
@@ -0,0 +1,217 @@
+package language
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// DETECCIÓN DEL LENGUAJE DE PROGRAMACIÓN
+// ============================================================================
+//
+// Implementa domain.CodeLanguageDetector en tres pasos, del más fiable al
+// menos:
+//
+//  1. El nombre del fichero: extensión (".go") o nombre conocido ("Dockerfile")
+//  2. El shebang de la primera línea ("#!/usr/bin/env python3")
+//  3. Construcciones típicas de cada lenguaje ("func main(", "def __init__"),
+//     con la misma idea que las stopwords: gana el lenguaje con más
+//     coincidencias, y sin evidencia suficiente o con empate no se adivina
+// ============================================================================
+
+// codeExtensions mapea extensión → lenguaje
+var codeExtensions = map[string]string{
+	".go": "go", ".py": "python", ".pyi": "python", ".js": "javascript", ".mjs": "javascript",
+	".cjs": "javascript", ".jsx": "javascript", ".ts": "typescript", ".tsx": "typescript",
+	".java": "java", ".kt": "kotlin", ".kts": "kotlin", ".scala": "scala", ".rs": "rust",
+	".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".cxx": "cpp", ".hpp": "cpp",
+	".cs": "csharp", ".rb": "ruby", ".php": "php", ".swift": "swift", ".m": "objective-c",
+	".sh": "shell", ".bash": "shell", ".zsh": "shell", ".ps1": "powershell", ".sql": "sql",
+	".r": "r", ".lua": "lua", ".pl": "perl", ".dart": "dart", ".ex": "elixir", ".exs": "elixir",
+	".erl": "erlang", ".hs": "haskell", ".clj": "clojure", ".vue": "vue", ".svelte": "svelte",
+	".html": "html", ".css": "css", ".scss": "scss", ".yaml": "yaml", ".yml": "yaml",
+	".json": "json", ".toml": "toml", ".tf": "terraform", ".proto": "protobuf",
+}
+
+// codeFilenames mapea nombres de fichero sin extensión útil → lenguaje
+var codeFilenames = map[string]string{
+	"dockerfile": "dockerfile", "makefile": "makefile", "gemfile": "ruby",
+	"rakefile": "ruby", "jenkinsfile": "groovy", "vagrantfile": "ruby",
+}
+
+// shebangInterpreters mapea intérprete del shebang → lenguaje
+var shebangInterpreters = map[string]string{
+	"python": "python", "python3": "python", "node": "javascript", "deno": "typescript",
+	"bash": "shell", "sh": "shell", "zsh": "shell", "ruby": "ruby", "perl": "perl", "php": "php",
+}
+
+// codeSignatures son construcciones típicas de cada lenguaje
+// Se buscan por línea: cada línea que coincide suma un punto
+var codeSignatures = map[string][]*regexp.Regexp{
+	"go": {
+		regexp.MustCompile(`^package \w+$`),
+		regexp.MustCompile(`^func (\(\w+ \*?\w+\) )?\w+\(`),
+		regexp.MustCompile(`:= `),
+		regexp.MustCompile(`^import \($`),
+		regexp.MustCompile(`\berr != nil\b`),
+	},
+	"python": {
+		regexp.MustCompile(`^\s*def \w+\(.*\):`),
+		regexp.MustCompile(`^\s*class \w+(\(.*\))?:`),
+		regexp.MustCompile(`^(from [\w.]+ )?import \w+`),
+		regexp.MustCompile(`^\s*(elif|except)\b.*:`),
+		regexp.MustCompile(`\bself\.\w+`),
+		regexp.MustCompile(`^if __name__ == `),
+	},
+	"javascript": {
+		regexp.MustCompile(`\b(const|let) \w+ = `),
+		regexp.MustCompile(`\bfunction\s*\w*\(`),
+		regexp.MustCompile(`=> \{?`),
+		regexp.MustCompile(`\brequire\(['"]`),
+		regexp.MustCompile(`\bconsole\.log\(`),
+		regexp.MustCompile(`^(export default|module\.exports)\b`),
+	},
+	"typescript": {
+		regexp.MustCompile(`^(export )?(interface|type) \w+(<.*>)? (=|\{)`),
+		regexp.MustCompile(`\w+\??: (string|number|boolean|any|unknown)\b`),
+		regexp.MustCompile(`^import .* from ['"]`),
+		regexp.MustCompile(`\b(private|public|readonly) \w+:`),
+	},
+	"java": {
+		regexp.MustCompile(`\bpublic (static )?(final )?(class|interface|void|enum) `),
+		regexp.MustCompile(`^import java\.`),
+		regexp.MustCompile(`\bSystem\.out\.print`),
+		regexp.MustCompile(`@Override\b`),
+		regexp.MustCompile(`^package [\w.]+;$`),
+	},
+	"csharp": {
+		regexp.MustCompile(`^using System`),
+		regexp.MustCompile(`^namespace [\w.]+`),
+		regexp.MustCompile(`\bpublic (async )?(Task|void|string|int)\b.*\(`),
+		regexp.MustCompile(`\{ get; (private )?set; \}`),
+	},
+	"rust": {
+		regexp.MustCompile(`^\s*(pub )?fn \w+`),
+		regexp.MustCompile(`\blet mut \w+`),
+		regexp.MustCompile(`^use \w+(::\w+)+`),
+		regexp.MustCompile(`\bimpl(<.*>)? \w+`),
+		regexp.MustCompile(`\w+!\(`),
+	},
+	"c": {
+		regexp.MustCompile(`^#include <\w+\.h>`),
+		regexp.MustCompile(`\bprintf\(`),
+		regexp.MustCompile(`\bmalloc\(`),
+		regexp.MustCompile(`^int main\(`),
+	},
+	"cpp": {
+		regexp.MustCompile(`^#include <\w+>$`),
+		regexp.MustCompile(`\bstd::\w+`),
+		regexp.MustCompile(`^using namespace std;`),
+		regexp.MustCompile(`\btemplate ?<`),
+	},
+	"ruby": {
+		regexp.MustCompile(`^\s*def \w+[?!]?(\(.*\))?$`),
+		regexp.MustCompile(`^\s*end$`),
+		regexp.MustCompile(`^require ['"]`),
+		regexp.MustCompile(`\bputs\b`),
+		regexp.MustCompile(`\bdo \|\w+\|`),
+	},
+	"php": {
+		regexp.MustCompile(`^<\?php`),
+		regexp.MustCompile(`\$\w+ = `),
+		regexp.MustCompile(`\bfunction \w+\(.*\$`),
+		regexp.MustCompile(`->\w+\(`),
+	},
+	"shell": {
+		regexp.MustCompile(`^\s*(if|while) \[\[? `),
+		regexp.MustCompile(`^\s*(fi|done|esac)$`),
+		regexp.MustCompile(`^\s*echo `),
+		regexp.MustCompile(`^\s*export \w+=`),
+		regexp.MustCompile(`\$\{?\w+\}?`),
+	},
+	"sql": {
+		regexp.MustCompile(`(?i)^\s*(select|insert into|update|delete from|create table|alter table)\b`),
+		regexp.MustCompile(`(?i)\b(from|where|group by|order by|join)\b`),
+	},
+}
+
+// codeSupersets son lenguajes que incluyen a otro: el código TypeScript
+// también parece JavaScript, así que con evidencia suficiente gana el superset
+var codeSupersets = map[string]string{
+	"javascript": "typescript",
+	"c":          "cpp",
+}
+
+// CodeDetector detecta el lenguaje de programación de un fichero
+type CodeDetector struct{}
+
+// NewCodeDetector crea el detector
+func NewCodeDetector() *CodeDetector {
+	return &CodeDetector{}
+}
+
+// Detect implementa domain.CodeLanguageDetector
+func (d *CodeDetector) Detect(filename string, code string) string {
+	if filename != "" {
+		base := strings.ToLower(path.Base(strings.ReplaceAll(filename, "\\", "/")))
+		if language, ok := codeFilenames[base]; ok {
+			return language
+		}
+		if language, ok := codeExtensions[path.Ext(base)]; ok {
+			return language
+		}
+	}
+
+	if language := shebangLanguage(code); language != "" {
+		return language
+	}
+
+	scores := make(map[string]int)
+	for _, line := range strings.Split(code, "\n") {
+		for language, signatures := range codeSignatures {
+			for _, signature := range signatures {
+				if signature.MatchString(line) {
+					scores[language]++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestScore, tie := "", 0, false
+	for language, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = language, score, false
+		case score == bestScore:
+			tie = true
+		}
+	}
+	if bestScore < minMatches || tie {
+		return ""
+	}
+	if superset, ok := codeSupersets[best]; ok && scores[superset] >= minMatches {
+		return superset
+	}
+	return best
+}
+
+// shebangLanguage lee el intérprete del shebang ("" si no hay)
+func shebangLanguage(code string) string {
+	firstLine, _, _ := strings.Cut(code, "\n")
+	if !strings.HasPrefix(firstLine, "#!") {
+		return ""
+	}
+
+	// "#!/usr/bin/env python3" o "#!/bin/bash -e"
+	fields := strings.Fields(strings.TrimPrefix(firstLine, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" && len(fields) > 1 {
+		interpreter = fields[1]
+	}
+	return shebangInterpreters[interpreter]
+}
@@ -0,0 +1,86 @@
+// Package language implementa la detección de idioma (adaptador secundario)
+// Implementa domain.LanguageDetector sin dependencias externas
+package language
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// DETECCIÓN POR PALABRAS FRECUENTES (stopwords)
+// ============================================================================
+//
+// Es una heurística sencilla: cada idioma tiene palabras muy frecuentes
+// ("el", "de", "que" en español; "the", "and", "is" en inglés...). Contamos
+// cuántas palabras del texto aparecen en la lista de cada idioma y elegimos
+// el que más coincidencias tenga.
+//
+// Funciona bien con frases normales y es muy rápida. Para textos muy cortos
+// ("ok", "gracias") puede no decidir, y entonces retorna "".
+//
+// Si necesitas más precisión, puedes crear otro adaptador (por ejemplo, con
+// una librería de n-gramas) que implemente domain.LanguageDetector.
+// ============================================================================
+
+// minMatches es el mínimo de coincidencias para decidir un idioma
+const minMatches = 2
+
+// stopwords contiene palabras frecuentes por idioma (código ISO 639-1)
+var stopwords = map[string][]string{
+	"es": {"el", "la", "los", "las", "de", "que", "y", "en", "un", "una", "es", "por", "con", "para", "como", "qué", "cómo", "pero", "del", "se", "no", "mi", "está"},
+	"en": {"the", "and", "is", "are", "of", "to", "in", "that", "it", "for", "with", "what", "how", "you", "this", "be", "on", "not", "my", "can"},
+	"pt": {"o", "os", "as", "de", "que", "e", "em", "um", "uma", "é", "por", "com", "para", "como", "não", "do", "da", "você", "isso", "está"},
+	"fr": {"le", "la", "les", "de", "des", "et", "est", "un", "une", "que", "en", "pour", "avec", "comment", "pas", "je", "vous", "ce", "du", "qui"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ein", "eine", "zu", "mit", "ich", "du", "wie", "was", "für", "auf", "den", "sie", "es", "von"},
+	"it": {"il", "lo", "la", "gli", "di", "che", "e", "è", "un", "una", "per", "con", "come", "non", "sono", "del", "della", "questo", "cosa", "io"},
+}
+
+// StopwordDetector detecta el idioma contando palabras frecuentes
+type StopwordDetector struct {
+	// index mapea palabra → idiomas en los que aparece
+	index map[string][]string
+}
+
+// NewStopwordDetector crea el detector y construye su índice
+func NewStopwordDetector() *StopwordDetector {
+	index := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return &StopwordDetector{index: index}
+}
+
+// Detect implementa domain.LanguageDetector
+// Retorna el código ISO 639-1 del idioma o "" si no hay suficiente evidencia
+func (d *StopwordDetector) Detect(text string) string {
+	// strings.FieldsFunc divide el texto en palabras usando la función dada
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	scores := make(map[string]int)
+	for _, word := range words {
+		for _, lang := range d.index[word] {
+			scores[lang]++
+		}
+	}
+
+	best, bestScore, tie := "", 0, false
+	for lang, score := range scores {
+		switch {
+		case score > bestScore:
+			best, bestScore, tie = lang, score, false
+		case score == bestScore:
+			tie = true
+		}
+	}
+
+	// Sin evidencia suficiente o empate: mejor no adivinar
+	if bestScore < minMatches || tie {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,177 @@
+// Package liveness detecta las peticiones y los jobs que se quedan colgados
+// Implementa domain.LivenessMonitor
+package liveness
+
+import (
+	"bytes"
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// WATCHDOG DE TAREAS COLGADAS
+// ============================================================================
+//
+// Cada tarea se registra con Begin y su plazo (el timeout de la petición o,
+// si no tiene, el plazo por defecto). El Watchdog revisa las tareas cada
+// poco tiempo; una que lleva más de factor × su plazo está colgada:
+//
+//  1. Se registra en el log con su nombre y duración
+//  2. Se vuelcan las pilas de todas las goroutines (como un panic), que
+//     muestran dónde está bloqueada sin tener que reproducirlo
+//  3. Se suma a StuckTotal (ver GET /admin/liveness)
+//
+// Cada tarea se avisa una sola vez, y las pilas se vuelcan como mucho una
+// vez por minDumpInterval: con muchas tareas colgadas a la vez (el proveedor
+// no responde), el log no se llena de volcados iguales.
+//
+// El watchdog solo avisa: no cancela la tarea, que sigue su curso.
+// ============================================================================
+
+// minDumpInterval es el tiempo mínimo entre dos volcados de pilas
+const minDumpInterval = time.Minute
+
+// Watchdog vigila las tareas registradas con Begin
+// Implementa domain.LivenessMonitor
+type Watchdog struct {
+	factor         int
+	defaultTimeout time.Duration
+	interval       time.Duration
+
+	mu       sync.Mutex
+	tasks    map[uint64]*task
+	next     uint64
+	lastDump time.Time
+
+	stuckTotal atomic.Int64
+}
+
+// task es una tarea en curso
+type task struct {
+	name    string
+	started time.Time
+	limit   time.Duration // factor × plazo
+	stuck   bool
+}
+
+// NewWatchdog crea el watchdog
+// Una tarea está colgada al pasar de factor × su plazo (defaultTimeout si
+// no lo indica); interval es cada cuánto se revisan las tareas
+func NewWatchdog(factor int, defaultTimeout, interval time.Duration) *Watchdog {
+	if factor <= 0 {
+		panic("el factor debe ser mayor que 0")
+	}
+	if defaultTimeout <= 0 {
+		panic("el plazo por defecto debe ser mayor que 0")
+	}
+	if interval <= 0 {
+		panic("el intervalo debe ser mayor que 0")
+	}
+
+	return &Watchdog{
+		factor:         factor,
+		defaultTimeout: defaultTimeout,
+		interval:       interval,
+		tasks:          make(map[uint64]*task),
+	}
+}
+
+// Begin implementa domain.LivenessMonitor
+func (w *Watchdog) Begin(name string, timeout time.Duration) func() {
+	if timeout <= 0 {
+		timeout = w.defaultTimeout
+	}
+
+	w.mu.Lock()
+	w.next++
+	id := w.next
+	w.tasks[id] = &task{name: name, started: time.Now(), limit: timeout * time.Duration(w.factor)}
+	w.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { w.end(id) })
+	}
+}
+
+// Stats implementa domain.LivenessMonitor
+func (w *Watchdog) Stats() domain.LivenessStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stats := domain.LivenessStats{Active: len(w.tasks), StuckTotal: w.stuckTotal.Load()}
+	for _, t := range w.tasks {
+		if t.stuck {
+			stats.Stuck++
+		}
+	}
+	return stats
+}
+
+// Run revisa las tareas hasta que se cancela ctx
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			w.check(now)
+		}
+	}
+}
+
+// end quita la tarea (y registra el final de las que estaban colgadas)
+func (w *Watchdog) end(id uint64) {
+	w.mu.Lock()
+	t := w.tasks[id]
+	delete(w.tasks, id)
+	w.mu.Unlock()
+
+	if t != nil && t.stuck {
+		log.Printf("✅ Tarea colgada terminada: %s tras %s", t.name, time.Since(t.started).Round(time.Second))
+	}
+}
+
+// check marca las tareas que han pasado de su límite y las registra
+func (w *Watchdog) check(now time.Time) {
+	w.mu.Lock()
+	var stuck []task
+	for _, t := range w.tasks {
+		if !t.stuck && now.Sub(t.started) > t.limit {
+			t.stuck = true
+			stuck = append(stuck, *t)
+		}
+	}
+	dump := len(stuck) > 0 && now.Sub(w.lastDump) >= minDumpInterval
+	if dump {
+		w.lastDump = now
+	}
+	w.mu.Unlock()
+
+	for _, t := range stuck {
+		w.stuckTotal.Add(1)
+		log.Printf("⚠️  Tarea colgada: %s lleva %s (límite %s)",
+			t.name, now.Sub(t.started).Round(time.Second), t.limit)
+	}
+	if dump {
+		log.Printf("⚠️  Pilas de las goroutines:\n%s", goroutineStacks())
+	}
+}
+
+// goroutineStacks retorna las pilas de todas las goroutines en el formato
+// de un panic
+func goroutineStacks() string {
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 2); err != nil {
+		return "no se pudieron leer: " + err.Error()
+	}
+	return buf.String()
+}
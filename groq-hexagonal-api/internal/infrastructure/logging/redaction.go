@@ -0,0 +1,142 @@
+// Package logging decide qué datos de una petición pueden llegar a los logs
+package logging
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ============================================================================
+// REGLAS DE REDACCIÓN
+// ============================================================================
+//
+// Los prompts y las respuestas son datos del cliente: no deben acabar en un
+// sistema de logs con otra retención y otros permisos. Todo lo que viene de la
+// petición pasa por un Redactor antes de escribirse:
+//
+//   - El contenido (el body) no se registra nunca. Con ContentHash se registra
+//     su SHA-256, útil para correlacionar peticiones repetidas sin leerlas.
+//   - Las cabeceras se registran con el valor sustituido por "[REDACTED]" si
+//     su nombre está en la lista (Authorization, X-Admin-Key... siempre lo
+//     están; se pueden añadir más).
+//
+// Los handlers no formatean contenido en sus logs: si un dato de la petición
+// debe registrarse, se añade aquí o en el access log a través del Redactor.
+// ============================================================================
+
+// Modos de registro del contenido
+const (
+	ContentNone = "none" // No se registra (por defecto)
+	ContentHash = "hash" // Se registra el SHA-256
+)
+
+// Redacted sustituye a los valores que no se pueden registrar
+const Redacted = "[REDACTED]"
+
+// DefaultRedactedHeaders son las cabeceras que nunca se registran en claro
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"X-Admin-Key",
+}
+
+// Redactor aplica las reglas de redacción
+// Es seguro para uso concurrente (solo se lee después de crearlo)
+type Redactor struct {
+	content string
+	headers map[string]bool // Nombres canónicos
+}
+
+// NewRedactor crea las reglas; headers se añade a DefaultRedactedHeaders
+// content vacío equivale a ContentNone
+func NewRedactor(content string, headers []string) *Redactor {
+	if content == "" {
+		content = ContentNone
+	}
+	if !IsContentMode(content) {
+		panic("modo de contenido desconocido: " + content)
+	}
+
+	redacted := make(map[string]bool, len(DefaultRedactedHeaders)+len(headers))
+	for _, name := range append(append([]string{}, DefaultRedactedHeaders...), headers...) {
+		redacted[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+	}
+	return &Redactor{content: content, headers: redacted}
+}
+
+// IsContentMode indica si mode es un modo de contenido válido
+func IsContentMode(mode string) bool {
+	return mode == ContentNone || mode == ContentHash
+}
+
+// HashesContent indica si se registra el hash del contenido
+func (r *Redactor) HashesContent() bool {
+	return r.content == ContentHash
+}
+
+// Content retorna lo que se puede registrar de text: su hash o Redacted
+func (r *Redactor) Content(text string) string {
+	if !r.HashesContent() {
+		return Redacted
+	}
+	sum := sha256.Sum256([]byte(text))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// HashBody envuelve el body para calcular su hash a medida que el handler lo
+// lee; sum retorna el hash de lo leído ("" si no se registra el contenido,
+// y entonces body se retorna tal cual)
+func (r *Redactor) HashBody(body io.ReadCloser) (wrapped io.ReadCloser, sum func() string) {
+	if !r.HashesContent() || body == nil || body == http.NoBody {
+		return body, func() string { return "" }
+	}
+
+	hashing := &hashingBody{ReadCloser: body, hash: sha256.New()}
+	return hashing, func() string {
+		if hashing.read == 0 {
+			return ""
+		}
+		return "sha256:" + hex.EncodeToString(hashing.hash.Sum(nil))
+	}
+}
+
+// Header retorna el valor registrable de la cabecera name
+func (r *Redactor) Header(name, value string) string {
+	if r.headers[http.CanonicalHeaderKey(name)] {
+		return Redacted
+	}
+	return value
+}
+
+// Headers retorna las cabeceras de la petición listas para registrar
+// Los valores repetidos se unen con ", " (como los une HTTP)
+func (r *Redactor) Headers(header http.Header) map[string]string {
+	logged := make(map[string]string, len(header))
+	for name, values := range header {
+		logged[name] = r.Header(name, strings.Join(values, ", "))
+	}
+	return logged
+}
+
+// hashingBody calcula el hash del body mientras se lee
+type hashingBody struct {
+	io.ReadCloser
+
+	hash hash.Hash
+	read int64
+}
+
+// Read implementa io.Reader
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	b.read += int64(n)
+	return n, err
+}
@@ -0,0 +1,122 @@
+// Package memlimit ajusta el recolector de basura y vigila el uso de memoria
+// Implementa domain.LoadShedder con el tamaño del heap
+package memlimit
+
+import (
+	"context"
+	"log"
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"sync/atomic"
+	"time"
+)
+
+// ============================================================================
+// LÍMITE DE MEMORIA Y DESCARGA DE PETICIONES
+// ============================================================================
+//
+// Con muchas respuestas grandes en memoria a la vez (jobs, conversaciones
+// largas, streaming con buffers...), el proceso puede superar el límite del
+// contenedor y morir por OOM, perdiendo todas las peticiones en curso. Dos
+// defensas:
+//
+//  1. Apply fija el límite blando del runtime (como GOMEMLIMIT) y GOGC: al
+//     acercarse al límite, el GC se ejecuta más a menudo en lugar de dejar
+//     crecer el heap
+//  2. El Watchdog mide el heap cada poco tiempo y, al pasar del umbral
+//     (un porcentaje del límite), marca la réplica como sobrecargada: las
+//     peticiones nuevas reciben 503 hasta que el heap baja, mientras las que
+//     están en curso terminan
+//
+// Para no alternar en cada medición, la réplica vuelve a aceptar peticiones
+// cuando el heap baja de resumeRatio del umbral.
+// ============================================================================
+
+// heapMetric son los bytes de objetos vivos (o aún no recogidos) del heap
+// Leerla no detiene el mundo, al contrario que runtime.ReadMemStats
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// resumeRatio es la parte del umbral por debajo de la que se vuelven a
+// aceptar peticiones
+const resumeRatio = 0.9
+
+// Apply fija el límite de memoria (bytes, 0 = el de GOMEMLIMIT o ninguno) y
+// GOGC (0 = el de GOGC o 100; negativo = GC solo por el límite)
+// Retorna el límite vigente (math.MaxInt64 si no hay)
+func Apply(limitBytes int64, gcPercent int) int64 {
+	if limitBytes > 0 {
+		debug.SetMemoryLimit(limitBytes)
+	}
+	if gcPercent != 0 {
+		debug.SetGCPercent(gcPercent)
+	}
+	// Un valor negativo consulta el límite sin cambiarlo
+	return debug.SetMemoryLimit(-1)
+}
+
+// Watchdog marca la réplica como sobrecargada si el heap supera el umbral
+// Implementa domain.LoadShedder
+type Watchdog struct {
+	threshold uint64
+	interval  time.Duration
+
+	overloaded atomic.Bool
+	sample     []metrics.Sample
+}
+
+// NewWatchdog crea el watchdog con el umbral en bytes
+// interval es cada cuánto se mide el heap
+func NewWatchdog(threshold int64, interval time.Duration) *Watchdog {
+	if threshold <= 0 || threshold == math.MaxInt64 {
+		panic("el umbral de memoria debe ser mayor que 0")
+	}
+	if interval <= 0 {
+		panic("el intervalo debe ser mayor que 0")
+	}
+
+	return &Watchdog{
+		threshold: uint64(threshold),
+		interval:  interval,
+		sample:    []metrics.Sample{{Name: heapMetric}},
+	}
+}
+
+// Overloaded implementa domain.LoadShedder
+func (w *Watchdog) Overloaded() bool {
+	return w.overloaded.Load()
+}
+
+// Run mide el heap hasta que se cancela ctx
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check mide el heap y actualiza el estado (y lo registra si cambia)
+func (w *Watchdog) check() {
+	metrics.Read(w.sample)
+	if w.sample[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	heap := w.sample[0].Value.Uint64()
+
+	switch {
+	case !w.overloaded.Load() && heap >= w.threshold:
+		w.overloaded.Store(true)
+		log.Printf("⚠️  Memoria: heap de %d MiB (umbral %d MiB), se rechazan las peticiones nuevas",
+			heap>>20, w.threshold>>20)
+	case w.overloaded.Load() && float64(heap) < float64(w.threshold)*resumeRatio:
+		w.overloaded.Store(false)
+		log.Printf("✅ Memoria: heap de %d MiB, se vuelven a aceptar peticiones", heap>>20)
+	}
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"slices"
+	"sync"
+)
+
+// ============================================================================
+// API KEYS EN MEMORIA
+// ============================================================================
+//
+// Solo para desarrollo: las claves se pierden al reiniciar y cada réplica
+// tiene las suyas. Buscar por hash recorre todas las claves: son pocas.
+// ============================================================================
+
+// APIKeyRepository guarda las API keys en un map
+// Implementa domain.APIKeyRepository
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]domain.APIKey
+}
+
+// NewAPIKeyRepository crea un repositorio vacío
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make(map[string]domain.APIKey),
+	}
+}
+
+// SaveAPIKey implementa domain.APIKeyRepository
+func (r *APIKeyRepository) SaveAPIKey(ctx context.Context, key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.ID] = *key
+	return nil
+}
+
+// FindAPIKey implementa domain.APIKeyRepository
+func (r *APIKeyRepository) FindAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return &key, nil
+}
+
+// FindAPIKeyByHash implementa domain.APIKeyRepository
+func (r *APIKeyRepository) FindAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.Hash == hash || (key.PreviousHash != "" && key.PreviousHash == hash) {
+			return &key, nil
+		}
+	}
+	return nil, domain.ErrAPIKeyNotFound
+}
+
+// ListAPIKeys implementa domain.APIKeyRepository
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]domain.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b domain.APIKey) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return keys, nil
+}
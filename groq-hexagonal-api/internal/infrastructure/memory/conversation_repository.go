@@ -0,0 +1,69 @@
+// Package memory implementa repositorios en memoria (adaptadores secundarios)
+// Útiles para desarrollo y demos: los datos se pierden al reiniciar
+package memory
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// REPOSITORIO DE CONVERSACIONES EN MEMORIA
+// ============================================================================
+
+// ConversationRepository guarda las conversaciones en un map
+// Implementa domain.ConversationRepository
+type ConversationRepository struct {
+	// mu protege el map: los handlers HTTP se ejecutan en goroutines distintas
+	// RWMutex permite muchas lecturas simultáneas o una sola escritura
+	mu            sync.RWMutex
+	conversations map[string]*domain.Conversation
+}
+
+// NewConversationRepository crea un repositorio vacío
+func NewConversationRepository() *ConversationRepository {
+	return &ConversationRepository{
+		conversations: make(map[string]*domain.Conversation),
+	}
+}
+
+// Save crea o reemplaza la conversación
+func (r *ConversationRepository) Save(ctx context.Context, conversation *domain.Conversation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Guardamos una copia: si el llamador modifica su conversación después,
+	// no debe cambiar lo almacenado sin pasar por Save()
+	r.conversations[conversation.ID] = conversation.Clone()
+	return nil
+}
+
+// FindByID retorna una copia de la conversación o domain.ErrConversationNotFound
+func (r *ConversationRepository) FindByID(ctx context.Context, id string) (*domain.Conversation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	conversation, ok := r.conversations[id]
+	if !ok {
+		return nil, domain.ErrConversationNotFound
+	}
+	return conversation.Clone(), nil
+}
+
+// PurgeDeleted elimina las conversaciones borradas cuyo plazo ha pasado
+func (r *ConversationRepository) PurgeDeleted(ctx context.Context, now time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, conversation := range r.conversations {
+		// Borrar de un map mientras se recorre es seguro en Go
+		if conversation.PurgeAt != nil && !conversation.PurgeAt.After(now) {
+			delete(r.conversations, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+)
+
+// ============================================================================
+// VALORACIONES EN MEMORIA
+// ============================================================================
+//
+// Solo para desarrollo: las valoraciones se pierden al reiniciar y cada
+// réplica tiene las suyas. Van en orden de llegada; al guardar se descartan
+// las que ya no entran en ningún resumen (MaxFeedbackSummaryDays).
+// ============================================================================
+
+// FeedbackRepository guarda las valoraciones en un slice
+// Implementa domain.FeedbackRepository
+type FeedbackRepository struct {
+	mu        sync.RWMutex
+	feedbacks []domain.Feedback
+}
+
+// NewFeedbackRepository crea un repositorio vacío
+func NewFeedbackRepository() *FeedbackRepository {
+	return &FeedbackRepository{}
+}
+
+// SaveFeedback implementa domain.FeedbackRepository
+func (r *FeedbackRepository) SaveFeedback(ctx context.Context, feedback *domain.Feedback) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := feedback.CreatedAt.AddDate(0, 0, -domain.MaxFeedbackSummaryDays)
+	expired := 0
+	for expired < len(r.feedbacks) && r.feedbacks[expired].CreatedAt.Before(cutoff) {
+		expired++
+	}
+	r.feedbacks = append(r.feedbacks[expired:], *feedback)
+	return nil
+}
+
+// ListFeedback implementa domain.FeedbackRepository
+func (r *FeedbackRepository) ListFeedback(ctx context.Context, filter domain.FeedbackFilter) ([]domain.Feedback, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var feedbacks []domain.Feedback
+	for _, feedback := range r.feedbacks {
+		if filter.Matches(feedback) {
+			feedbacks = append(feedbacks, feedback)
+		}
+	}
+	return feedbacks, nil
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// IDEMPOTENCIA EN MEMORIA
+// ============================================================================
+//
+// Igual que la caché de respuestas: un map con caducidad y, con el máximo de
+// entradas alcanzado, se descarta la más antigua. Solo sirve con una réplica:
+// con varias, un reintento que llega a otra réplica no ve la primera
+// respuesta (usar la de Redis).
+// ============================================================================
+
+// idempotencyEntry es una respuesta (o reserva) guardada
+type idempotencyEntry struct {
+	key       string
+	response  domain.IdempotentResponse
+	expiresAt time.Time
+}
+
+// IdempotencyStore guarda las respuestas de las peticiones idempotentes
+// Implementa domain.IdempotencyStore
+type IdempotencyStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // de la más antigua a la más reciente
+}
+
+// NewIdempotencyStore crea un almacén vacío con como mucho maxEntries claves
+func NewIdempotencyStore(maxEntries int) *IdempotencyStore {
+	if maxEntries <= 0 {
+		panic("maxEntries debe ser mayor que 0")
+	}
+
+	return &IdempotencyStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Reserve implementa domain.IdempotencyStore
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, entry domain.IdempotentResponse, ttl time.Duration) (*domain.IdempotentResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		existing := element.Value.(*idempotencyEntry)
+		if time.Now().Before(existing.expiresAt) {
+			response := existing.response
+			return &response, nil
+		}
+		s.remove(element)
+	}
+
+	s.set(key, entry, ttl)
+	return nil, nil
+}
+
+// Complete implementa domain.IdempotencyStore
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, entry domain.IdempotentResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+	s.set(key, entry, ttl)
+	return nil
+}
+
+// Release implementa domain.IdempotencyStore
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+	return nil
+}
+
+// set guarda la entrada, descartando las más antiguas si no cabe
+// (se llama con mu bloqueado)
+func (s *IdempotencyStore) set(key string, response domain.IdempotentResponse, ttl time.Duration) {
+	for s.order.Len() >= s.maxEntries {
+		s.remove(s.order.Front())
+	}
+	s.entries[key] = s.order.PushBack(&idempotencyEntry{
+		key:       key,
+		response:  response,
+		expiresAt: time.Now().Add(ttl),
+	})
+}
+
+// remove elimina una entrada (se llama con mu bloqueado)
+func (s *IdempotencyStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.entries, element.Value.(*idempotencyEntry).key)
+}
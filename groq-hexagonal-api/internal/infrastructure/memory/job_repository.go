@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// JOBS EN MEMORIA
+// ============================================================================
+//
+// Solo sirve con una réplica: el sondeo tiene que llegar a la réplica que
+// encoló el job. Los jobs caducados se eliminan al guardar, como mucho una
+// vez por minuto.
+// ============================================================================
+
+// jobSweepInterval es cada cuánto se buscan jobs caducados
+const jobSweepInterval = time.Minute
+
+// JobRepository guarda los jobs en un map
+// Implementa domain.JobRepository
+type JobRepository struct {
+	mu   sync.RWMutex
+	jobs map[string]domain.Job
+
+	// lastSweep es la última vez que se eliminaron los jobs caducados
+	lastSweep time.Time
+}
+
+// NewJobRepository crea un repositorio vacío
+func NewJobRepository() *JobRepository {
+	return &JobRepository{
+		jobs: make(map[string]domain.Job),
+	}
+}
+
+// SaveJob implementa domain.JobRepository
+func (r *JobRepository) SaveJob(ctx context.Context, job *domain.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastSweep) >= jobSweepInterval {
+		for id, stored := range r.jobs {
+			if !stored.ExpiresAt.After(now) {
+				delete(r.jobs, id)
+			}
+		}
+		r.lastSweep = now
+	}
+
+	// Se guarda una copia: el worker sigue modificando su job
+	r.jobs[job.ID] = *job
+	return nil
+}
+
+// FindJob implementa domain.JobRepository
+func (r *JobRepository) FindJob(ctx context.Context, id string) (*domain.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.jobs[id]
+	if !ok || !job.ExpiresAt.After(time.Now()) {
+		return nil, domain.ErrJobNotFound
+	}
+	return &job, nil
+}
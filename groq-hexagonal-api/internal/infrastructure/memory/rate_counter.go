@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// CONTADOR DE RATE LIMIT EN MEMORIA
+// ============================================================================
+//
+// Ventana fija por clave. Solo sirve con una réplica: con varias, cada una
+// cuenta por su lado (usar el contador de Redis).
+// ============================================================================
+
+// rateWindow es el contador de una clave en la ventana actual
+type rateWindow struct {
+	count   int64
+	resetAt time.Time
+}
+
+// RateCounter cuenta peticiones por ventana en un map
+// Implementa domain.RateCounter
+type RateCounter struct {
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+
+	// lastSweep es la última limpieza de ventanas caducadas
+	lastSweep time.Time
+}
+
+// NewRateCounter crea un contador vacío
+func NewRateCounter() *RateCounter {
+	return &RateCounter{
+		windows: make(map[string]*rateWindow),
+	}
+}
+
+// Increment implementa domain.RateCounter
+func (c *RateCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	current, ok := c.windows[key]
+	if !ok || !now.Before(current.resetAt) {
+		// De vez en cuando se olvidan las ventanas caducadas: sin esto, el
+		// map crecería con cada cliente distinto
+		if now.Sub(c.lastSweep) >= window {
+			c.sweep(now)
+			c.lastSweep = now
+		}
+		current = &rateWindow{resetAt: now.Add(window)}
+		c.windows[key] = current
+	}
+
+	current.count++
+	return current.count, current.resetAt.Sub(now), nil
+}
+
+// sweep elimina las ventanas caducadas (se llama con mu bloqueado)
+func (c *RateCounter) sweep(now time.Time) {
+	for key, window := range c.windows {
+		if !now.Before(window.resetAt) {
+			delete(c.windows, key)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ DE RESPUESTAS EN MEMORIA
+// ============================================================================
+//
+// Las respuestas se guardan serializadas: así el servicio puede modificar la
+// respuesta que recibe (recortes, hooks) sin alterar la guardada.
+//
+// Con el máximo de entradas alcanzado se descarta la más antigua. Solo sirve
+// con una réplica: con varias, cada una tiene su caché (usar la de Redis).
+// ============================================================================
+
+// cacheEntry es una respuesta guardada
+type cacheEntry struct {
+	key       string
+	data      []byte
+	expiresAt time.Time
+}
+
+// ResponseCache guarda respuestas en un map con caducidad
+// Implementa domain.ResponseCache
+type ResponseCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // de la más antigua a la más reciente
+}
+
+// NewResponseCache crea una caché vacía con como mucho maxEntries respuestas
+func NewResponseCache(maxEntries int) *ResponseCache {
+	if maxEntries <= 0 {
+		panic("maxEntries debe ser mayor que 0")
+	}
+
+	return &ResponseCache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// Get implementa domain.ResponseCache
+func (c *ResponseCache) Get(ctx context.Context, key string) (*domain.CachedResponse, error) {
+	data, ok := c.get(key)
+	if !ok {
+		return nil, nil
+	}
+
+	var entry domain.CachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("respuesta cacheada corrupta: %w", err)
+	}
+	return &entry, nil
+}
+
+// Set implementa domain.ResponseCache
+func (c *ResponseCache) Set(ctx context.Context, key string, entry domain.CachedResponse, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error al serializar la respuesta: %w", err)
+	}
+	c.set(key, data, ttl)
+	return nil
+}
+
+// get retorna la respuesta serializada (false si no existe o caducó)
+func (c *ResponseCache) get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(element.Value.(*cacheEntry).expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	return element.Value.(*cacheEntry).data, true
+}
+
+// set guarda la respuesta serializada durante ttl
+func (c *ResponseCache) set(key string, data []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.remove(element)
+	}
+	for c.order.Len() >= c.maxEntries {
+		c.remove(c.order.Front())
+	}
+
+	c.entries[key] = c.order.PushBack(&cacheEntry{
+		key:       key,
+		data:      data,
+		expiresAt: time.Now().Add(ttl),
+	})
+}
+
+// Len implementa domain.ResponseCache
+// Puede incluir respuestas caducadas que aún no se han leído ni desplazado
+func (c *ResponseCache) Len(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len(), nil
+}
+
+// Flush implementa domain.ResponseCache
+func (c *ResponseCache) Flush(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := c.order.Len()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	return removed, nil
+}
+
+// remove elimina una entrada (se llama con mu bloqueado)
+func (c *ResponseCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*cacheEntry).key)
+}
@@ -0,0 +1,53 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ POR RUTA EN MEMORIA
+// ============================================================================
+//
+// Guarda las respuestas igual que ResponseCache (serializadas, con caducidad
+// y descartando la más antigua con el máximo alcanzado), pero con sus propias
+// entradas: las respuestas de las rutas no desplazan a las del chat.
+// ============================================================================
+
+// RouteCache guarda respuestas HTTP en memoria
+// Implementa domain.RouteCache
+type RouteCache struct {
+	cache *ResponseCache
+}
+
+// NewRouteCache crea una caché vacía con como mucho maxEntries respuestas
+func NewRouteCache(maxEntries int) *RouteCache {
+	return &RouteCache{cache: NewResponseCache(maxEntries)}
+}
+
+// Get implementa domain.RouteCache
+func (c *RouteCache) Get(ctx context.Context, key string) (*domain.CachedRoute, error) {
+	data, ok := c.cache.get(key)
+	if !ok {
+		return nil, nil
+	}
+
+	var entry domain.CachedRoute
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("respuesta cacheada corrupta: %w", err)
+	}
+	return &entry, nil
+}
+
+// Set implementa domain.RouteCache
+func (c *RouteCache) Set(ctx context.Context, key string, entry domain.CachedRoute, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error al serializar la respuesta: %w", err)
+	}
+	c.cache.set(key, data, ttl)
+	return nil
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"math"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ SEMÁNTICA EN MEMORIA
+// ============================================================================
+//
+// La búsqueda recorre todas las entradas del ámbito y calcula la similitud
+// del coseno con cada una: con unos miles de vectores de ~1500 dimensiones
+// son unos pocos milisegundos, mucho menos que la llamada al modelo que se
+// ahorra. Para cachés mayores haría falta un índice (una base de datos
+// vectorial).
+//
+// Los vectores se guardan normalizados, así la similitud es el producto
+// escalar. Igual que en ResponseCache, con el máximo de entradas alcanzado se
+// descarta la más antigua y las respuestas se guardan serializadas.
+// ============================================================================
+
+// semanticEntry es una respuesta guardada con el vector de su pregunta
+type semanticEntry struct {
+	scope     string
+	vector    []float64 // normalizado
+	data      []byte
+	expiresAt time.Time
+}
+
+// SemanticCache guarda respuestas en una lista con caducidad
+// Implementa domain.SemanticCache
+type SemanticCache struct {
+	maxEntries int
+
+	mu    sync.Mutex
+	order *list.List // de la más antigua a la más reciente
+}
+
+// NewSemanticCache crea una caché vacía con como mucho maxEntries respuestas
+func NewSemanticCache(maxEntries int) *SemanticCache {
+	if maxEntries <= 0 {
+		panic("maxEntries debe ser mayor que 0")
+	}
+
+	return &SemanticCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+	}
+}
+
+// Search implementa domain.SemanticCache
+// Las entradas caducadas que encuentra por el camino se eliminan
+func (c *SemanticCache) Search(ctx context.Context, scope string, vector []float64, minSimilarity float64) (*domain.SemanticMatch, error) {
+	query := normalize(vector)
+	if query == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var best *semanticEntry
+	bestSimilarity := minSimilarity
+
+	c.mu.Lock()
+	for element := c.order.Front(); element != nil; {
+		next := element.Next()
+		entry := element.Value.(*semanticEntry)
+		switch {
+		case !now.Before(entry.expiresAt):
+			c.order.Remove(element)
+		case entry.scope == scope && len(entry.vector) == len(query):
+			if similarity := dot(entry.vector, query); similarity >= bestSimilarity {
+				best, bestSimilarity = entry, similarity
+			}
+		}
+		element = next
+	}
+	c.mu.Unlock()
+
+	if best == nil {
+		return nil, nil
+	}
+
+	var cached domain.CachedResponse
+	if err := json.Unmarshal(best.data, &cached); err != nil {
+		return nil, fmt.Errorf("respuesta cacheada corrupta: %w", err)
+	}
+	return &domain.SemanticMatch{Entry: cached, Similarity: bestSimilarity}, nil
+}
+
+// Add implementa domain.SemanticCache
+func (c *SemanticCache) Add(ctx context.Context, scope string, vector []float64, entry domain.CachedResponse, ttl time.Duration) error {
+	normalized := normalize(vector)
+	if normalized == nil {
+		return fmt.Errorf("embedding vacío")
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error al serializar la respuesta: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.order.Len() >= c.maxEntries {
+		c.order.Remove(c.order.Front())
+	}
+	c.order.PushBack(&semanticEntry{
+		scope:     scope,
+		vector:    normalized,
+		data:      data,
+		expiresAt: time.Now().Add(ttl),
+	})
+	return nil
+}
+
+// Len implementa domain.SemanticCache
+// Puede incluir respuestas caducadas que aún no se han recorrido
+func (c *SemanticCache) Len(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len(), nil
+}
+
+// Flush implementa domain.SemanticCache
+func (c *SemanticCache) Flush(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := c.order.Len()
+	c.order.Init()
+	return removed, nil
+}
+
+// normalize retorna una copia del vector con norma 1 (nil si es el vector cero)
+func normalize(vector []float64) []float64 {
+	norm := math.Sqrt(dot(vector, vector))
+	if norm == 0 {
+		return nil
+	}
+	normalized := make([]float64, len(vector))
+	for i, value := range vector {
+		normalized[i] = value / norm
+	}
+	return normalized
+}
+
+// dot es el producto escalar de dos vectores de la misma longitud
+func dot(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
@@ -0,0 +1,176 @@
+package memory
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// CONSUMO DE TOKENS EN MEMORIA
+// ============================================================================
+//
+// Un contador por cliente y día. Solo sirve con una réplica (cada una
+// contaría por su lado) y se pierde al reiniciar: para cuotas reales, Redis o
+// PostgreSQL. Los días más antiguos que el historial consultable se olvidan,
+// y los meses del extracto de cada tenant, a partir de MaxStatementMonths.
+// ============================================================================
+
+// usageKey identifica el consumo de un cliente en un día
+type usageKey struct {
+	client string
+	day    string
+}
+
+// tenantUsageKey identifica el consumo de un tenant en un mes
+type tenantUsageKey struct {
+	tenant string
+	month  string
+}
+
+// tenantUsage es el consumo de un tenant en un mes por modelo y conversación
+type tenantUsage struct {
+	models        map[string]domain.ModelUsage
+	conversations map[string]domain.ConversationUsage
+}
+
+// UsageRepository acumula el consumo de tokens en un map
+// Implementa domain.UsageRepository
+type UsageRepository struct {
+	mu      sync.Mutex
+	usage   map[usageKey]domain.Usage
+	tenants map[tenantUsageKey]*tenantUsage
+
+	// lastSweep es el último día en que se olvidaron los días antiguos
+	lastSweep string
+
+	// lastTenantSweep es el último mes en que se olvidaron los meses antiguos
+	lastTenantSweep string
+}
+
+// NewUsageRepository crea un repositorio vacío
+func NewUsageRepository() *UsageRepository {
+	return &UsageRepository{
+		usage:   make(map[usageKey]domain.Usage),
+		tenants: make(map[tenantUsageKey]*tenantUsage),
+	}
+}
+
+// AddUsage implementa domain.UsageRepository
+func (r *UsageRepository) AddUsage(ctx context.Context, client string, day string, usage domain.Usage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Una vez al día se olvidan los días que ya no se pueden consultar: sin
+	// esto, el map crecería con cada cliente y cada día
+	if day != r.lastSweep {
+		r.sweep(day)
+		r.lastSweep = day
+	}
+
+	key := usageKey{client: client, day: day}
+	current := r.usage[key]
+	current.PromptTokens += usage.PromptTokens
+	current.CompletionTokens += usage.CompletionTokens
+	current.TotalTokens += usage.TotalTokens
+	current.CostUSD += usage.CostUSD
+	r.usage[key] = current
+	return nil
+}
+
+// GetUsage implementa domain.UsageRepository
+func (r *UsageRepository) GetUsage(ctx context.Context, client string, day string) (domain.Usage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.usage[usageKey{client: client, day: day}], nil
+}
+
+// AddTenantUsage implementa domain.UsageRepository
+func (r *UsageRepository) AddTenantUsage(ctx context.Context, tenant string, month string, record domain.TenantUsageRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if month != r.lastTenantSweep {
+		r.sweepTenants(month)
+		r.lastTenantSweep = month
+	}
+
+	key := tenantUsageKey{tenant: tenant, month: month}
+	usage, ok := r.tenants[key]
+	if !ok {
+		usage = &tenantUsage{
+			models:        make(map[string]domain.ModelUsage),
+			conversations: make(map[string]domain.ConversationUsage),
+		}
+		r.tenants[key] = usage
+	}
+
+	model := usage.models[record.Model]
+	model.Model = record.Model
+	model.Requests++
+	model.Usage = model.Usage.Add(record.Usage)
+	usage.models[record.Model] = model
+
+	if record.ConversationID != "" {
+		conversation := usage.conversations[record.ConversationID]
+		conversation.ConversationID = record.ConversationID
+		conversation.Requests++
+		conversation.Usage = conversation.Usage.Add(record.Usage)
+		usage.conversations[record.ConversationID] = conversation
+	}
+	return nil
+}
+
+// GetTenantUsage implementa domain.UsageRepository
+func (r *UsageRepository) GetTenantUsage(ctx context.Context, tenant string, month string) (*domain.TenantUsage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &domain.TenantUsage{}
+	usage, ok := r.tenants[tenantUsageKey{tenant: tenant, month: month}]
+	if !ok {
+		return result, nil
+	}
+	for _, model := range usage.models {
+		result.Models = append(result.Models, model)
+	}
+	for _, conversation := range usage.conversations {
+		result.Conversations = append(result.Conversations, conversation)
+	}
+	return result, nil
+}
+
+// sweep elimina los días anteriores al historial consultable desde today
+// (se llama con mu bloqueado)
+func (r *UsageRepository) sweep(today string) {
+	now, err := time.Parse(domain.UsageDayLayout, today)
+	if err != nil {
+		return
+	}
+	oldest := now.AddDate(0, 0, -domain.MaxUsageHistoryDays).Format(domain.UsageDayLayout)
+
+	// El formato AAAA-MM-DD ordena igual como texto que como fecha
+	for key := range r.usage {
+		if key.day < oldest {
+			delete(r.usage, key)
+		}
+	}
+}
+
+// sweepTenants elimina los meses anteriores a los consultables desde month
+// (se llama con mu bloqueado)
+func (r *UsageRepository) sweepTenants(month string) {
+	current, err := time.Parse(domain.StatementMonthLayout, month)
+	if err != nil {
+		return
+	}
+	oldest := current.AddDate(0, -domain.MaxStatementMonths, 0).Format(domain.StatementMonthLayout)
+
+	for key := range r.tenants {
+		if key.month < oldest {
+			delete(r.tenants, key)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Package mock implementa un proveedor de modelos falso para desarrollo
+package mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// PROVEEDOR FALSO (MOCK_MODE)
+// ============================================================================
+//
+// Con MOCK_MODE=true este adaptador sustituye a Groq (y al resto de
+// proveedores): no necesita API key ni red, y responde siempre lo mismo a la
+// misma petición, para desarrollar un frontend contra la API sin gastar
+// tokens. Las respuestas son:
+//
+//   - el eco del último mensaje del usuario: "[mock] Has dicho: <mensaje>"
+//   - "safe" a los modelos de moderación (Llama Guard)
+//   - "{}" cuando la petición pide JSON (response_format)
+//
+// El uso de tokens es sintético (la estimación de domain.EstimateTokens), así
+// que el consumo, las cuotas y los costes funcionan igual que con Groq. El
+// streaming envía la respuesta palabra a palabra.
+// ============================================================================
+
+// Constantes del proveedor falso
+const (
+	ownedBy   = "mock"
+	echoReply = "[mock] Has dicho: "
+
+	// streamDelay es la pausa entre fragmentos del streaming
+	streamDelay = 30 * time.Millisecond
+)
+
+// MockClient implementa domain.LLMRepository y domain.TranscriptionRepository
+// sin llamar a ningún proveedor
+type MockClient struct {
+	// models son los modelos que lista ListModels
+	models []string
+
+	// now da la hora de las respuestas (el campo "created")
+	now func() time.Time
+}
+
+// NewMockClient crea el proveedor falso
+// ListModels retorna defaultModel y los modelos del catálogo por defecto
+func NewMockClient(defaultModel string) *MockClient {
+	models := []string{defaultModel}
+	for model := range domain.DefaultModelProfiles {
+		if model != defaultModel {
+			models = append(models, model)
+		}
+	}
+	slices.Sort(models[1:])
+
+	return &MockClient{
+		models: models,
+		now:    time.Now,
+	}
+}
+
+// CreateChatCompletion implementa domain.LLMRepository
+func (c *MockClient) CreateChatCompletion(ctx context.Context, request domain.ChatRequest) (*domain.ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	content := reply(request)
+	return &domain.ChatResponse{
+		ID:      responseID(request),
+		Object:  "chat.completion",
+		Created: c.now().Unix(),
+		Model:   request.Model,
+		Choices: []domain.Choice{{
+			Message:      domain.NewChatMessage("assistant", content),
+			FinishReason: "stop",
+		}},
+		Usage: usage(request, content),
+	}, nil
+}
+
+// CreateChatCompletionStream implementa domain.LLMRepository
+func (c *MockClient) CreateChatCompletionStream(ctx context.Context, request domain.ChatRequest) (domain.ChatStream, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	content := reply(request)
+	return &mockStream{
+		ctx:     ctx,
+		id:      responseID(request),
+		created: c.now().Unix(),
+		model:   request.Model,
+		words:   splitWords(content),
+		usage:   usage(request, content),
+		delay:   streamDelay,
+	}, nil
+}
+
+// ListModels implementa domain.LLMRepository
+func (c *MockClient) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	response := &domain.ModelsResponse{Object: "list"}
+	for _, model := range c.models {
+		response.Data = append(response.Data, domain.Model{ID: model, Object: "model", OwnedBy: ownedBy})
+	}
+	return response, nil
+}
+
+// ProxyChatCompletion implementa domain.LLMRepository
+// El body se interpreta como una petición de chat y la respuesta se genera
+// en el formato de la API de OpenAI/Groq (JSON o SSE)
+func (c *MockClient) ProxyChatCompletion(ctx context.Context, body []byte, stream bool) (*domain.ProxyResponse, error) {
+	var request domain.ChatRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return proxyResponse(http.StatusBadRequest, "application/json",
+			[]byte(`{"error":{"message":"body inválido","type":"invalid_request_error"}}`)), nil
+	}
+
+	if !stream {
+		response, err := c.CreateChatCompletion(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(response)
+		if err != nil {
+			return nil, fmt.Errorf("error al serializar la respuesta: %w", err)
+		}
+		return proxyResponse(http.StatusOK, "application/json", data), nil
+	}
+
+	// En el proxy el streaming se escribe entero de una vez: sin pausas
+	content := reply(request)
+	chunks := &mockStream{
+		ctx:     ctx,
+		id:      responseID(request),
+		created: c.now().Unix(),
+		model:   request.Model,
+		words:   splitWords(content),
+		usage:   usage(request, content),
+	}
+	var events bytes.Buffer
+	for {
+		chunk, err := chunks.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("error al serializar la respuesta: %w", err)
+		}
+		events.WriteString("data: ")
+		events.Write(data)
+		events.WriteString("\n\n")
+	}
+	events.WriteString("data: [DONE]\n\n")
+	return proxyResponse(http.StatusOK, "text/event-stream", events.Bytes()), nil
+}
+
+// CreateTranscription implementa domain.TranscriptionRepository
+// El audio se descarta: la transcripción dice cuántos bytes tenía
+func (c *MockClient) CreateTranscription(ctx context.Context, request domain.TranscriptionRequest) (*domain.Transcription, error) {
+	size, err := io.Copy(io.Discard, request.Audio)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el audio: %w", err)
+	}
+
+	text := fmt.Sprintf("[mock] Transcripción de %s (%d bytes)", request.Filename, size)
+	language := request.Language
+	if language == "" {
+		language = "es"
+	}
+	return &domain.Transcription{
+		Text:     text,
+		Language: language,
+		Duration: 1,
+		Segments: []domain.TranscriptionSegment{{Start: 0, End: 1, Text: text}},
+		Model:    request.Model,
+	}, nil
+}
+
+// ============================================================================
+// RESPUESTAS
+// ============================================================================
+
+// reply genera la respuesta a una petición (siempre la misma)
+func reply(request domain.ChatRequest) string {
+	if strings.Contains(strings.ToLower(request.Model), "guard") {
+		return "safe"
+	}
+	if request.ResponseFormat != nil {
+		return "{}"
+	}
+
+	for i := len(request.Messages) - 1; i >= 0; i-- {
+		if message := request.Messages[i]; message.Role == "user" {
+			return echoReply + message.Content
+		}
+	}
+	return echoReply
+}
+
+// usage estima los tokens de la petición y de la respuesta
+func usage(request domain.ChatRequest, content string) domain.Usage {
+	prompt := domain.EstimatePromptTokens(request)
+	completion := domain.EstimateTokens(content)
+	return domain.Usage{
+		PromptTokens:     prompt,
+		CompletionTokens: completion,
+		TotalTokens:      prompt + completion,
+	}
+}
+
+// responseID deriva el ID de los mensajes: la misma petición, el mismo ID
+func responseID(request domain.ChatRequest) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Model))
+	for _, message := range request.Messages {
+		hash.Write([]byte{0})
+		hash.Write([]byte(message.Role + ":" + message.Content))
+	}
+	return "mock-" + hex.EncodeToString(hash.Sum(nil))[:24]
+}
+
+// splitWords parte el texto en palabras que conservan los espacios previos,
+// para que concatenar los fragmentos dé el texto original
+func splitWords(text string) []string {
+	var words []string
+	start := 0
+	for i := 1; i < len(text); i++ {
+		if text[i] == ' ' && text[i-1] != ' ' {
+			words = append(words, text[start:i])
+			start = i
+		}
+	}
+	if start < len(text) {
+		words = append(words, text[start:])
+	}
+	return words
+}
+
+// proxyResponse construye la respuesta cruda del modo proxy
+func proxyResponse(status int, contentType string, body []byte) *domain.ProxyResponse {
+	return &domain.ProxyResponse{
+		StatusCode: status,
+		Header:     map[string][]string{"Content-Type": {contentType}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
@@ -0,0 +1,70 @@
+package mock
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"time"
+)
+
+// mockStream envía la respuesta palabra a palabra, como un modelo real
+// El último fragmento trae finish_reason y el uso de tokens (en x_groq, como Groq)
+// Implementa domain.ChatStream
+type mockStream struct {
+	ctx     context.Context
+	id      string
+	created int64
+	model   string
+
+	// words son los fragmentos que quedan por enviar
+	words []string
+	usage domain.Usage
+
+	// delay es la pausa antes de cada fragmento
+	delay time.Duration
+
+	// done indica que ya se envió el último fragmento
+	done bool
+}
+
+// Recv implementa domain.ChatStream
+func (s *mockStream) Recv() (*domain.ChatStreamChunk, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+	if s.delay > 0 {
+		timer := time.NewTimer(s.delay)
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return nil, s.ctx.Err()
+		case <-timer.C:
+		}
+	} else if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	chunk := &domain.ChatStreamChunk{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: s.created,
+		Model:   s.model,
+	}
+	if len(s.words) > 0 {
+		chunk.Choices = []domain.StreamChoice{{Delta: domain.NewChatMessage("assistant", s.words[0])}}
+		s.words = s.words[1:]
+		return chunk, nil
+	}
+
+	usage := s.usage
+	chunk.Choices = []domain.StreamChoice{{FinishReason: "stop"}}
+	chunk.XGroq = &domain.XGroq{ID: s.id, Usage: &usage}
+	s.done = true
+	return chunk, nil
+}
+
+// Close implementa domain.ChatStream
+func (s *mockStream) Close() error {
+	s.done = true
+	return nil
+}
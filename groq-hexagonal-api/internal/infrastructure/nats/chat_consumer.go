@@ -0,0 +1,360 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// CONSUMIDOR DE CHATS (ADAPTADOR PRIMARIO)
+// ============================================================================
+//
+// Es la alternativa a HTTP y gRPC para los productores que no son clientes
+// HTTP (otros servicios, pipelines de eventos): publican un ChatRequest en
+// QUEUE_SUBJECT y reciben un ChatResponse con el mismo "id".
+//
+//   - La respuesta va al reply del mensaje (petición-respuesta de NATS,
+//     nats request) o, si no lo trae, a QUEUE_REPLY_SUBJECT
+//   - Las réplicas se suscriben con el mismo grupo (QUEUE_GROUP): cada
+//     mensaje lo procesa una sola
+//   - Hasta Concurrency mensajes a la vez; con todos los workers ocupados,
+//     se deja de leer y NATS acumula los mensajes (hasta su límite de
+//     pendientes, que descarta los que sobran)
+//
+// NATS básico entrega como mucho una vez: si la réplica cae a mitad de un
+// chat, la petición se pierde. El productor debe aplicar su propio timeout.
+// Las peticiones no pasan por la autenticación ni el rate limit de HTTP: el
+// acceso lo controlan los permisos de NATS.
+// ============================================================================
+
+const (
+	// subscriptionID identifica la única suscripción de la conexión
+	subscriptionID = 1
+
+	// reconnectDelay es la espera entre intentos de reconexión
+	reconnectDelay = 2 * time.Second
+
+	// errorMessage es el mensaje de los errores que no se muestran
+	errorMessage = "error al procesar el mensaje"
+)
+
+// ConsumerOptions configura el consumidor
+type ConsumerOptions struct {
+	// URL del servidor (nats://host:puerto)
+	URL string
+
+	// Subject del que se leen las peticiones
+	Subject string
+
+	// Group es el grupo de cola compartido por las réplicas
+	Group string
+
+	// ReplySubject recibe las respuestas de los mensajes sin reply
+	ReplySubject string
+
+	// Concurrency es el máximo de chats en curso
+	Concurrency int
+
+	// Timeout es el plazo de cada chat
+	Timeout time.Duration
+}
+
+// ChatRequest es el mensaje de una petición de chat
+// Los campos son los de POST /api/v1/chat que tienen sentido sin streaming
+type ChatRequest struct {
+	// ID se copia en la respuesta para correlacionarla
+	ID string `json:"id,omitempty"`
+
+	Message      string               `json:"message"`
+	Model        string               `json:"model,omitempty"`
+	SystemPrompt string               `json:"system_prompt,omitempty"`
+	Temperature  *float64             `json:"temperature,omitempty"`
+	MaxTokens    int                  `json:"max_tokens,omitempty"`
+	Stop         []string             `json:"stop,omitempty"`
+	Persona      string               `json:"persona,omitempty"`
+	Provider     string               `json:"provider,omitempty"`
+	History      []domain.ChatMessage `json:"history,omitempty"`
+
+	// TenantID hace el papel de la cabecera X-Tenant-ID
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// ChatResponse es el mensaje de respuesta
+type ChatResponse struct {
+	ID      string `json:"id,omitempty"`
+	Success bool   `json:"success"`
+
+	Message      string           `json:"message,omitempty"`
+	Model        string           `json:"model,omitempty"`
+	FinishReason string           `json:"finish_reason,omitempty"`
+	Usage        *domain.Usage    `json:"usage,omitempty"`
+	Warnings     []domain.Warning `json:"warnings,omitempty"`
+
+	// Error y Type solo aparecen si Success es false (Type como en HTTP)
+	Error string `json:"error,omitempty"`
+	Type  string `json:"type,omitempty"`
+}
+
+// ChatConsumer lee peticiones de chat de NATS y publica las respuestas
+type ChatConsumer struct {
+	chatService domain.ChatService
+	options     ConsumerOptions
+
+	// client es la conexión actual (se reemplaza al reconectar)
+	mu     sync.Mutex
+	client *Client
+
+	// inFlight cuenta los chats en curso, para esperarlos al parar
+	inFlight sync.WaitGroup
+}
+
+// NewChatConsumer crea el consumidor
+func NewChatConsumer(chatService domain.ChatService, options ConsumerOptions) *ChatConsumer {
+	if chatService == nil {
+		panic("chatService no puede ser nil")
+	}
+	if options.Concurrency <= 0 {
+		panic("la concurrencia debe ser mayor que 0")
+	}
+
+	return &ChatConsumer{chatService: chatService, options: options}
+}
+
+// Start conecta, se suscribe y empieza a consumir en su propia goroutine
+// El primer intento de conexión es síncrono (una URL o credenciales mal
+// configuradas fallan al arrancar); después se reconecta solo.
+// Retorna la función que lo detiene esperando a los chats en curso
+func (c *ChatConsumer) Start() (stop func(ctx context.Context), err error) {
+	client, err := c.connect(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.run(ctx, client)
+	}()
+
+	return func(stopCtx context.Context) {
+		// Primero se deja de recibir (UNSUB y cierre de la lectura, que
+		// desbloquea Next) y se espera a los chats en curso, que aún
+		// publican su respuesta; después se cierra la conexión
+		cancel()
+		if client := c.currentClient(); client != nil {
+			client.Unsubscribe(subscriptionID)
+			client.CloseRead()
+		}
+		<-done
+
+		finished := make(chan struct{})
+		go func() {
+			c.inFlight.Wait()
+			close(finished)
+		}()
+		select {
+		case <-finished:
+		case <-stopCtx.Done():
+		}
+
+		if client := c.currentClient(); client != nil {
+			client.Close()
+		}
+	}, nil
+}
+
+// connect abre una conexión y crea la suscripción
+func (c *ChatConsumer) connect(ctx context.Context) (*Client, error) {
+	client, err := Dial(ctx, c.options.URL, "groq-hexagonal-api")
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Subscribe(c.options.Subject, c.options.Group, subscriptionID); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Si se paró mientras conectaba, la conexión nueva sobra
+	if ctx.Err() != nil {
+		client.Close()
+		return nil, ctx.Err()
+	}
+	c.client = client
+	return client, nil
+}
+
+// currentClient retorna la conexión actual
+func (c *ChatConsumer) currentClient() *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.client
+}
+
+// run lee mensajes hasta que se cancela ctx, reconectando si se pierde la conexión
+func (c *ChatConsumer) run(ctx context.Context, client *Client) {
+	// slots limita los chats en curso: con todos ocupados no se lee más
+	slots := make(chan struct{}, c.options.Concurrency)
+
+	for {
+		err := c.consume(ctx, client, slots)
+		if ctx.Err() != nil {
+			return
+		}
+		client.Close()
+		log.Printf("⚠️  Conexión con NATS perdida: %v (reconectando)", err)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+			if client, err = c.connect(ctx); err == nil {
+				log.Printf("✅ Reconectado a NATS")
+				break
+			}
+			log.Printf("⚠️  No se pudo reconectar a NATS: %v", err)
+		}
+	}
+}
+
+// consume procesa los mensajes de la conexión hasta que falla
+func (c *ChatConsumer) consume(ctx context.Context, client *Client, slots chan struct{}) error {
+	for {
+		msg, err := client.Next()
+		if err != nil {
+			return err
+		}
+		// Tras parar pueden llegar mensajes ya enviados antes del UNSUB: se
+		// descartan (como si la réplica hubiera caído)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		c.inFlight.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				c.inFlight.Done()
+			}()
+			c.handle(msg)
+		}()
+	}
+}
+
+// handle procesa una petición y publica su respuesta
+// La respuesta sale por la conexión actual (puede haberse reconectado)
+func (c *ChatConsumer) handle(msg Msg) {
+	replyTo := msg.Reply
+	if replyTo == "" {
+		replyTo = c.options.ReplySubject
+	}
+
+	response := c.chat(msg.Data)
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("❌ Error al serializar la respuesta de NATS: %v", err)
+		return
+	}
+	if err := c.currentClient().Publish(replyTo, data); err != nil {
+		log.Printf("❌ Error al publicar la respuesta en %s: %v", replyTo, err)
+	}
+}
+
+// chat decodifica la petición y la envía al servicio
+func (c *ChatConsumer) chat(data []byte) *ChatResponse {
+	var request ChatRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return &ChatResponse{Error: "JSON inválido: " + err.Error(), Type: "invalid_request"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.options.Timeout)
+	defer cancel()
+	if request.TenantID != "" {
+		ctx = domain.WithTenant(ctx, request.TenantID)
+	}
+
+	response, err := c.chatService.SendMessage(ctx, request.Message, request.Model, domain.MessageOptions{
+		Temperature:  request.Temperature,
+		MaxTokens:    request.MaxTokens,
+		Stop:         request.Stop,
+		SystemPrompt: request.SystemPrompt,
+		Persona:      request.Persona,
+		Provider:     request.Provider,
+		History:      request.History,
+	})
+	if err != nil {
+		message, errType := classifyError(err)
+		return &ChatResponse{ID: request.ID, Error: message, Type: errType}
+	}
+
+	return &ChatResponse{
+		ID:           request.ID,
+		Success:      true,
+		Message:      response.GetResponseContent(),
+		Model:        response.Model,
+		FinishReason: response.GetFinishReason(),
+		Usage:        &response.Usage,
+		Warnings:     response.Meta.Warnings,
+	}
+}
+
+// errorTypes traduce los errores del dominio al "type" de la respuesta
+// Es el equivalente de serviceErrorMappings en el adaptador HTTP
+var errorTypes = []struct {
+	target  error
+	errType string
+
+	// expose indica si el mensaje del error se puede mostrar al productor
+	expose bool
+}{
+	{domain.ErrEmptyMessage, "invalid_request", true},
+	{domain.ErrEmptyModel, "invalid_request", true},
+	{domain.ErrInvalidRequest, "invalid_request", true},
+	{domain.ErrContextTooLong, "context_too_long", true},
+	{domain.ErrPersonaNotFound, "persona_not_found", true},
+	{domain.ErrUnknownProvider, "unknown_provider", true},
+	{domain.ErrParameterOutOfRange, "parameter_out_of_range", true},
+	{domain.ErrRequestRejected, "request_rejected", true},
+	{domain.ErrContentFlagged, "content_flagged", true},
+	{domain.ErrModelNotFound, "model_not_found", true},
+	{domain.ErrModelDecommissioned, "model_decommissioned", true},
+	{domain.ErrRateLimited, "rate_limited", true},
+	{domain.ErrUpstreamUnavailable, "upstream_unavailable", false},
+	{domain.ErrUpstreamTimeout, "upstream_timeout", false},
+	{domain.ErrUpstreamAuth, "upstream_error", false},
+	{context.DeadlineExceeded, "timeout", false},
+}
+
+// classifyError retorna el mensaje y el tipo de un error del servicio
+// Los errores del servidor y los no previstos se registran y se responden
+// con un mensaje genérico
+func classifyError(err error) (message, errType string) {
+	for _, mapping := range errorTypes {
+		if !errors.Is(err, mapping.target) {
+			continue
+		}
+		if mapping.expose {
+			return err.Error(), mapping.errType
+		}
+		log.Printf("Error en servicio (NATS): %v", err)
+		return errorMessage, mapping.errType
+	}
+
+	log.Printf("Error en servicio (NATS): %v", err)
+	return errorMessage, "internal_error"
+}
@@ -0,0 +1,322 @@
+// Package nats implementa los adaptadores de colas sobre NATS
+// Consume peticiones de chat de un subject y publica las respuestas
+// (adaptador primario) y publica los eventos de consumo (secundario)
+package nats
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// CLIENTE NATS (PROTOCOLO DE TEXTO)
+// ============================================================================
+//
+// El consumidor solo necesita suscribirse y publicar, así que, como el
+// cliente de Redis, en lugar de una dependencia externa se usa un cliente
+// mínimo del protocolo de NATS sobre net.Conn. Cada comando es una línea
+// terminada en \r\n; los mensajes llevan el tamaño y después el contenido:
+//
+//   servidor: INFO {...}                          (al conectar)
+//   cliente:  CONNECT {...}  PING                 → servidor: PONG (o -ERR)
+//   cliente:  SUB <subject> [grupo] <sid>    UNSUB <sid>
+//   cliente:  PUB <subject> <bytes>\r\n<contenido>
+//   servidor: MSG <subject> <sid> [reply] <bytes>\r\n<contenido>
+//   servidor: PING                                → cliente: PONG
+//
+// No admite TLS, JetStream ni cabeceras (HMSG): con el servidor exigiendo
+// TLS, Dial retorna error.
+// ============================================================================
+
+const (
+	// defaultPort es el puerto de NATS si la URL no lo indica
+	defaultPort = "4222"
+
+	// dialTimeout es el máximo para conectar y completar el saludo
+	dialTimeout = 5 * time.Second
+
+	// maxControlLine es el tamaño máximo de una línea de control
+	maxControlLine = 4096
+)
+
+var (
+	// ErrServer es un error enviado por el servidor (-ERR)
+	ErrServer = errors.New("error del servidor NATS")
+
+	// ErrPayloadTooLarge se retorna al publicar más de lo que admite el
+	// servidor (max_payload); el servidor cerraría la conexión
+	ErrPayloadTooLarge = errors.New("el mensaje supera el máximo del servidor NATS")
+)
+
+// Msg es un mensaje recibido de una suscripción
+type Msg struct {
+	Subject string
+
+	// Reply es el subject al que responder ("" si el emisor no lo indicó)
+	Reply string
+
+	Data []byte
+}
+
+// Client es una conexión a un servidor NATS
+// Publish es seguro para uso concurrente; Next solo se llama desde una
+// goroutine (la que lee los mensajes)
+type Client struct {
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// writeMu serializa las escrituras (Publish desde varios workers y el
+	// PONG de Next)
+	writeMu sync.Mutex
+	writer  *bufio.Writer
+
+	// maxPayload es el tamaño máximo de un mensaje (lo anuncia el servidor)
+	maxPayload int
+}
+
+// serverInfo son los campos de INFO que interesan al cliente
+type serverInfo struct {
+	TLSRequired bool `json:"tls_required"`
+	MaxPayload  int  `json:"max_payload"`
+}
+
+// connectOptions es el cuerpo de CONNECT
+type connectOptions struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	Protocol  int    `json:"protocol"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+// Dial conecta con el servidor de rawURL (nats://[usuario:clave@|token@]host[:puerto])
+// name identifica la conexión en la monitorización del servidor
+func Dial(ctx context.Context, rawURL, name string) (*Client, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "nats" || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("URL de NATS inválida (se espera nats://host:puerto)")
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(parsed.Hostname(), port))
+	if err != nil {
+		return nil, fmt.Errorf("error al conectar con NATS: %w", err)
+	}
+
+	client := &Client{
+		conn:   conn,
+		reader: bufio.NewReaderSize(conn, maxControlLine),
+		writer: bufio.NewWriter(conn),
+	}
+
+	// El saludo (INFO → CONNECT + PING → PONG) tiene el mismo plazo que la conexión
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+	if err := client.handshake(parsed.User, name); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return client, nil
+}
+
+// handshake lee INFO, envía CONNECT y espera el PONG del servidor
+func (c *Client) handshake(user *url.Userinfo, name string) error {
+	line, err := c.readLine()
+	if err != nil {
+		return fmt.Errorf("error al leer INFO: %w", err)
+	}
+	op, args, _ := strings.Cut(line, " ")
+	if op != "INFO" {
+		return fmt.Errorf("respuesta inesperada de NATS: %q", line)
+	}
+	var info serverInfo
+	if err := json.Unmarshal([]byte(args), &info); err != nil {
+		return fmt.Errorf("INFO inválido: %w", err)
+	}
+	if info.TLSRequired {
+		return fmt.Errorf("el servidor NATS exige TLS (no soportado)")
+	}
+	c.maxPayload = info.MaxPayload
+
+	options := connectOptions{Name: name, Lang: "go", Version: "1.0.0", Protocol: 1}
+	if user != nil {
+		if pass, ok := user.Password(); ok {
+			options.User, options.Pass = user.Username(), pass
+		} else {
+			options.AuthToken = user.Username()
+		}
+	}
+	body, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+
+	c.writer.WriteString("CONNECT ")
+	c.writer.Write(body)
+	c.writer.WriteString("\r\nPING\r\n")
+	if err := c.writer.Flush(); err != nil {
+		return fmt.Errorf("error al enviar CONNECT: %w", err)
+	}
+
+	// Con verbose=false la respuesta es PONG o -ERR (credenciales...)
+	line, err = c.readLine()
+	if err != nil {
+		return fmt.Errorf("error al leer la respuesta a CONNECT: %w", err)
+	}
+	if line != "PONG" {
+		return serverError(line)
+	}
+	return nil
+}
+
+// Subscribe se suscribe a subject; con queue, los mensajes se reparten
+// entre los suscriptores del grupo (cada uno lo recibe una sola réplica)
+func (c *Client) Subscribe(subject, queue string, sid int) error {
+	command := "SUB " + subject + " " + strconv.Itoa(sid) + "\r\n"
+	if queue != "" {
+		command = "SUB " + subject + " " + queue + " " + strconv.Itoa(sid) + "\r\n"
+	}
+	return c.write(func(w *bufio.Writer) {
+		w.WriteString(command)
+	})
+}
+
+// Unsubscribe cancela la suscripción sid
+func (c *Client) Unsubscribe(sid int) error {
+	return c.write(func(w *bufio.Writer) {
+		w.WriteString("UNSUB " + strconv.Itoa(sid) + "\r\n")
+	})
+}
+
+// Publish publica data en subject
+func (c *Client) Publish(subject string, data []byte) error {
+	if c.maxPayload > 0 && len(data) > c.maxPayload {
+		return fmt.Errorf("%w (%d bytes, máximo %d)", ErrPayloadTooLarge, len(data), c.maxPayload)
+	}
+	return c.write(func(w *bufio.Writer) {
+		w.WriteString("PUB ")
+		w.WriteString(subject)
+		w.WriteByte(' ')
+		w.WriteString(strconv.Itoa(len(data)))
+		w.WriteString("\r\n")
+		w.Write(data)
+		w.WriteString("\r\n")
+	})
+}
+
+// Next espera el siguiente mensaje de las suscripciones
+// Responde a los PING del servidor mientras espera
+func (c *Client) Next() (Msg, error) {
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return Msg{}, err
+		}
+
+		op, args, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(op) {
+		case "MSG":
+			return c.readMsg(args)
+		case "PING":
+			if err := c.write(func(w *bufio.Writer) { w.WriteString("PONG\r\n") }); err != nil {
+				return Msg{}, err
+			}
+		case "PONG", "+OK", "INFO":
+			// Respuestas a nuestros PING, confirmaciones y cambios del clúster
+		case "-ERR":
+			return Msg{}, serverError(line)
+		default:
+			return Msg{}, fmt.Errorf("comando NATS desconocido: %q", op)
+		}
+	}
+}
+
+// CloseRead deja de leer de la conexión (Next retorna error) sin impedir
+// publicar: sirve para dejar de consumir y terminar de responder
+func (c *Client) CloseRead() error {
+	if tcp, ok := c.conn.(*net.TCPConn); ok {
+		return tcp.CloseRead()
+	}
+	return c.conn.Close()
+}
+
+// Close cierra la conexión (Next retorna error)
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// readMsg lee el contenido de un MSG
+// args es "<subject> <sid> [reply] <bytes>"
+func (c *Client) readMsg(args string) (Msg, error) {
+	fields := strings.Fields(args)
+	if len(fields) != 3 && len(fields) != 4 {
+		return Msg{}, fmt.Errorf("MSG inválido: %q", args)
+	}
+	size, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || size < 0 {
+		return Msg{}, fmt.Errorf("MSG inválido: %q", args)
+	}
+
+	msg := Msg{Subject: fields[0]}
+	if len(fields) == 4 {
+		msg.Reply = fields[2]
+	}
+
+	// El contenido va seguido de \r\n
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		return Msg{}, err
+	}
+	msg.Data = data[:size]
+	return msg, nil
+}
+
+// readLine lee una línea de control sin el \r\n
+func (c *Client) readLine() (string, error) {
+	line, err := c.reader.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		return "", fmt.Errorf("línea de control de NATS demasiado larga")
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+// write escribe un comando y lo envía
+func (c *Client) write(fill func(w *bufio.Writer)) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	fill(c.writer)
+	return c.writer.Flush()
+}
+
+// serverError convierte una línea -ERR 'mensaje' en error
+func serverError(line string) error {
+	message := strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "-ERR")), "'")
+	return fmt.Errorf("%w: %s", ErrServer, message)
+}
@@ -0,0 +1,211 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// PUBLICADOR DE EVENTOS DE CONSUMO (ADAPTADOR SECUNDARIO)
+// ============================================================================
+//
+// Implementa domain.UsageEventPublisher: cada consumo registrado se publica
+// como JSON (domain.UsageEvent) en USAGE_EVENTS_SUBJECT, para que los
+// sistemas de facturación y analítica se suscriban en lugar de consultar la
+// API. Para llevarlos a Kafka, un puente NATS → Kafka puede leer el subject.
+//
+//   - PublishUsage no bloquea: el evento entra en un buffer y lo publica una
+//     goroutine; con el buffer lleno (NATS caído o lento), se descarta
+//   - Si se pierde la conexión, se reintenta cada reconnectDelay; los eventos
+//     que llegan mientras tanto se descartan
+//   - Al parar se publican los eventos que quedan en el buffer
+//
+// La entrega es como mucho una vez (NATS básico): el consumo oficial sigue
+// siendo el del almacén de siempre (GET /api/v1/usage, los extractos).
+// ============================================================================
+
+// usageEventBuffer es el máximo de eventos pendientes de publicar
+const usageEventBuffer = 1000
+
+// UsagePublisher publica los eventos de consumo en un subject de NATS
+type UsagePublisher struct {
+	url     string
+	subject string
+
+	events chan domain.UsageEvent
+
+	// quit pide a la goroutine que vacíe el buffer y termine
+	quit chan struct{}
+
+	// dropped cuenta los eventos descartados desde el arranque
+	mu      sync.Mutex
+	dropped int
+}
+
+// NewUsagePublisher crea el publicador de url (nats://host:puerto) en subject
+func NewUsagePublisher(url, subject string) *UsagePublisher {
+	if subject == "" {
+		panic("el subject de los eventos de consumo no puede estar vacío")
+	}
+
+	return &UsagePublisher{
+		url:     url,
+		subject: subject,
+		events:  make(chan domain.UsageEvent, usageEventBuffer),
+		quit:    make(chan struct{}),
+	}
+}
+
+// Start conecta y empieza a publicar en su propia goroutine
+// El primer intento de conexión es síncrono, como en ChatConsumer.Start.
+// Retorna la función que lo detiene publicando los eventos pendientes
+func (p *UsagePublisher) Start() (stop func(ctx context.Context), err error) {
+	client, err := p.connect(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.run(client)
+	}()
+
+	return func(ctx context.Context) {
+		close(p.quit)
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+	}, nil
+}
+
+// PublishUsage implementa domain.UsageEventPublisher
+func (p *UsagePublisher) PublishUsage(ctx context.Context, event domain.UsageEvent) {
+	select {
+	case p.events <- event:
+	default:
+		p.drop()
+	}
+}
+
+// connect abre una conexión y lanza la goroutine que la mantiene viva
+// El publicador no lee mensajes, pero sí tiene que responder a los PING del
+// servidor (o cerraría la conexión): de eso se encarga Next
+func (p *UsagePublisher) connect(ctx context.Context) (*Client, error) {
+	client, err := Dial(ctx, p.url, "groq-hexagonal-api-usage")
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		_, err := client.Next()
+		select {
+		case <-p.quit:
+			// Al parar, la conexión la cierra run
+		default:
+			log.Printf("⚠️  Conexión de los eventos de consumo con NATS cerrada: %v", err)
+		}
+		client.Close()
+	}()
+	return client, nil
+}
+
+// run publica los eventos hasta que se pide parar
+// client es nil mientras no hay conexión
+func (p *UsagePublisher) run(client *Client) {
+	var retryAt time.Time
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
+
+	for {
+		var event domain.UsageEvent
+		select {
+		case event = <-p.events:
+		case <-p.quit:
+			p.flush(client)
+			return
+		}
+
+		if client == nil {
+			if time.Now().Before(retryAt) {
+				p.drop()
+				continue
+			}
+			var err error
+			if client, err = p.connect(context.Background()); err != nil {
+				log.Printf("⚠️  No se pudo reconectar a NATS (eventos de consumo): %v", err)
+				retryAt = time.Now().Add(reconnectDelay)
+				p.drop()
+				continue
+			}
+			log.Printf("✅ Eventos de consumo reconectados a NATS")
+		}
+
+		if err := p.publish(client, event); err != nil {
+			log.Printf("⚠️  Error al publicar el evento de consumo: %v", err)
+			p.drop()
+			client.Close()
+			client = nil
+			retryAt = time.Now().Add(reconnectDelay)
+		}
+	}
+}
+
+// flush publica los eventos que quedan en el buffer
+func (p *UsagePublisher) flush(client *Client) {
+	for {
+		select {
+		case event := <-p.events:
+			if client == nil {
+				p.drop()
+				continue
+			}
+			if err := p.publish(client, event); err != nil {
+				log.Printf("⚠️  Error al publicar el evento de consumo: %v", err)
+				p.drop()
+				client = nil
+			}
+		default:
+			p.logDropped()
+			return
+		}
+	}
+}
+
+// publish serializa y publica un evento
+func (p *UsagePublisher) publish(client *Client, event domain.UsageEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return client.Publish(p.subject, data)
+}
+
+// drop cuenta un evento descartado y lo avisa en el log, agrupando los
+// descartes (uno por cada 100) para no llenarlo con NATS caído
+func (p *UsagePublisher) drop() {
+	p.mu.Lock()
+	p.dropped++
+	dropped := p.dropped
+	p.mu.Unlock()
+
+	if dropped == 1 || dropped%100 == 0 {
+		log.Printf("⚠️  Eventos de consumo descartados: %d", dropped)
+	}
+}
+
+// logDropped avisa del total de eventos descartados al parar
+func (p *UsagePublisher) logDropped() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.dropped > 0 {
+		log.Printf("⚠️  Eventos de consumo descartados en total: %d", p.dropped)
+	}
+}
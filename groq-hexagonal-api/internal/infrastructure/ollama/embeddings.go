@@ -0,0 +1,54 @@
+// Package ollama - Embeddings
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+	"time"
+)
+
+// embedEndpoint es el endpoint de embeddings de la API nativa
+const embedEndpoint = "/api/embed"
+
+// NewEmbeddingClient crea el cliente de embeddings de un servidor de Ollama
+// (lo usa la caché semántica)
+func NewEmbeddingClient(baseURL string, timeout time.Duration, opts ...ClientOption) domain.EmbeddingRepository {
+	return newClient(baseURL, timeout, opts...)
+}
+
+// embedRequest es el body de POST /api/embed
+type embedRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// embedResponse es la respuesta de POST /api/embed (un vector por texto,
+// en el mismo orden)
+type embedResponse struct {
+	Embeddings [][]float64 `json:"embeddings"`
+}
+
+// CreateEmbeddings implementa domain.EmbeddingRepository
+func (c *OllamaClient) CreateEmbeddings(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	body, err := json.Marshal(embedRequest{Model: model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar request: %w", err)
+	}
+
+	response, err := c.doRequest(ctx, http.MethodPost, embedEndpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("error en la petición HTTP: %w", err)
+	}
+
+	var parsed embedResponse
+	if err := json.Unmarshal(response, &parsed); err != nil {
+		return nil, fmt.Errorf("error al parsear los embeddings: %w", err)
+	}
+	if len(parsed.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("se esperaban %d embeddings y llegaron %d", len(texts), len(parsed.Embeddings))
+	}
+	return parsed.Embeddings, nil
+}
@@ -0,0 +1,67 @@
+// Package ollama - Traducción de los errores de Ollama
+package ollama
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"net/http"
+)
+
+// apiErrorBody es el formato de los errores de Ollama: {"error": "..."}
+// A diferencia de OpenAI, no hay código de error: solo el status HTTP
+type apiErrorBody struct {
+	Error string `json:"error"`
+}
+
+// newAPIError construye el error de una respuesta no 2xx
+func newAPIError(statusCode int, body []byte) error {
+	var parsed apiErrorBody
+	_ = json.Unmarshal(body, &parsed)
+
+	message := parsed.Error
+	if message == "" {
+		message = fmt.Sprintf("Ollama retornó status %d: %s", statusCode, string(body))
+	}
+
+	return &domain.UpstreamError{
+		Kind:       classifyStatus(statusCode),
+		StatusCode: statusCode,
+		Message:    message,
+	}
+}
+
+// classifyStatus elige la categoría del dominio según el status HTTP
+func classifyStatus(statusCode int) error {
+	switch {
+	case statusCode == http.StatusNotFound:
+		// Ollama responde 404 si el modelo no está descargado
+		return domain.ErrModelNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return domain.ErrRateLimited
+	case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+		return domain.ErrUpstreamAuth
+	case statusCode >= 500:
+		return domain.ErrUpstreamUnavailable
+	default:
+		return domain.ErrInvalidRequest
+	}
+}
+
+// newTransportError traduce los errores sin respuesta (servidor caído, timeouts...)
+func newTransportError(ctx context.Context, err error) error {
+	// Si el cliente canceló la petición, se propaga tal cual
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return fmt.Errorf("error al ejecutar request: %w", err)
+	}
+
+	kind := domain.ErrUpstreamUnavailable
+	var timeoutErr interface{ Timeout() bool }
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &timeoutErr) && timeoutErr.Timeout()) {
+		kind = domain.ErrUpstreamTimeout
+	}
+
+	return &domain.UpstreamError{Kind: kind, Message: err.Error()}
+}
@@ -0,0 +1,465 @@
+// Package ollama implementa el adaptador para modelos locales con Ollama
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ============================================================================
+// ADAPTADOR DE OLLAMA
+// ============================================================================
+//
+// Ollama ejecuta modelos en local (o en un servidor propio). Su API nativa
+// no es la de OpenAI, así que este adaptador traduce en ambos sentidos:
+//
+//   domain.ChatRequest  → POST /api/chat  {"model", "messages", "options": {...}}
+//   domain.ChatResponse ← {"message": {...}, "done_reason", "eval_count", ...}
+//
+//   ListModels          → GET /api/tags
+//
+// El streaming es JSON por líneas (NDJSON), no SSE (ver stream.go). El modo
+// proxy usa el endpoint compatible con OpenAI (/v1/chat/completions), porque
+// el body del cliente ya viene en ese formato.
+//
+// Ollama no necesita API key.
+// ============================================================================
+
+// Endpoints de la API de Ollama
+const (
+	chatEndpoint        = "/api/chat"
+	tagsEndpoint        = "/api/tags"
+	proxyEndpoint       = "/v1/chat/completions"
+	contentTypeJSON     = "application/json"
+	contentTypeNDJSON   = "application/x-ndjson"
+	ownedBy             = "ollama"
+	finishReasonToolUse = "tool_calls"
+)
+
+// OllamaClient es el adaptador HTTP que implementa domain.LLMRepository
+type OllamaClient struct {
+	// httpClient tiene el timeout de las peticiones completas
+	httpClient *http.Client
+
+	// streamClient no tiene timeout global (igual que en el cliente de Groq):
+	// sirve para el streaming y para las peticiones con plazo en el contexto
+	streamClient *http.Client
+
+	// baseURL es la URL del servidor de Ollama (ej: http://localhost:11434)
+	baseURL string
+
+	// settings aporta el timeout vigente (nil = el de httpClient)
+	settings domain.SettingsSource
+}
+
+// ClientOption configura aspectos opcionales del cliente
+type ClientOption func(*OllamaClient)
+
+// WithRuntimeSettings toma el timeout de los ajustes vigentes en cada petición
+func WithRuntimeSettings(settings domain.SettingsSource) ClientOption {
+	return func(c *OllamaClient) {
+		c.settings = settings
+	}
+}
+
+// WithTransportWrapper envuelve el transporte de los dos clientes HTTP
+// (ej: egress.Allowlist.Wrap, que limita los hosts de salida)
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *OllamaClient) {
+		transport := wrap(c.httpClient.Transport)
+		c.httpClient.Transport = transport
+		c.streamClient.Transport = transport
+	}
+}
+
+// NewOllamaClient crea el adaptador para un servidor de Ollama
+func NewOllamaClient(baseURL string, timeout time.Duration, opts ...ClientOption) domain.LLMRepository {
+	return newClient(baseURL, timeout, opts...)
+}
+
+// newClient crea el cliente (compartido con NewEmbeddingClient)
+func newClient(baseURL string, timeout time.Duration, opts ...ClientOption) *OllamaClient {
+	if baseURL == "" {
+		panic("baseURL no puede estar vacía")
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	client := &OllamaClient{
+		httpClient:   &http.Client{Timeout: timeout, Transport: transport},
+		streamClient: &http.Client{Transport: transport},
+		baseURL:      baseURL,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// ============================================================================
+// FORMATO DE LA API NATIVA
+// ============================================================================
+
+// chatRequest es el body de POST /api/chat
+type chatRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Stream   bool          `json:"stream"`
+
+	// Format "json" equivale a response_format json_object
+	Format string `json:"format,omitempty"`
+
+	Options *chatOptions  `json:"options,omitempty"`
+	Tools   []domain.Tool `json:"tools,omitempty"`
+}
+
+// chatOptions son los parámetros del modelo (en Ollama van aparte)
+// Ollama no admite "n": siempre genera una sola respuesta
+type chatOptions struct {
+	Temperature      *float64 `json:"temperature,omitempty"`
+	NumPredict       int      `json:"num_predict,omitempty"` // Equivale a max_tokens
+	Stop             []string `json:"stop,omitempty"`
+	TopP             *float64 `json:"top_p,omitempty"`
+	FrequencyPenalty *float64 `json:"frequency_penalty,omitempty"`
+	PresencePenalty  *float64 `json:"presence_penalty,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+}
+
+// chatMessage es un mensaje en el formato de Ollama
+type chatMessage struct {
+	Role      string     `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []toolCall `json:"tool_calls,omitempty"`
+
+	// Images son las imágenes en base64, sin el prefijo de la data URI
+	Images []string `json:"images,omitempty"`
+}
+
+// toolCall es una llamada a herramienta de Ollama
+// A diferencia de OpenAI, no tiene id y los argumentos son un objeto JSON
+type toolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// chatResponse es la respuesta de /api/chat (y cada línea del streaming)
+type chatResponse struct {
+	Model      string      `json:"model"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Message    chatMessage `json:"message"`
+	Done       bool        `json:"done"`
+	DoneReason string      `json:"done_reason"`
+
+	// Tokens del prompt y de la respuesta (solo cuando done es true)
+	PromptEvalCount int `json:"prompt_eval_count"`
+	EvalCount       int `json:"eval_count"`
+
+	// Error llega si Ollama falla a mitad del streaming
+	Error string `json:"error,omitempty"`
+}
+
+// tagsResponse es la respuesta de /api/tags
+type tagsResponse struct {
+	Models []struct {
+		Name       string    `json:"name"`
+		ModifiedAt time.Time `json:"modified_at"`
+	} `json:"models"`
+}
+
+// ============================================================================
+// IMPLEMENTACIÓN DE domain.LLMRepository
+// ============================================================================
+
+// CreateChatCompletion implementa la interfaz LLMRepository
+func (c *OllamaClient) CreateChatCompletion(
+	ctx context.Context,
+	request domain.ChatRequest,
+) (*domain.ChatResponse, error) {
+	jsonData, err := json.Marshal(toChatRequest(request, false))
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar request: %w", err)
+	}
+
+	body, err := c.doRequest(ctx, http.MethodPost, chatEndpoint, jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("error en la petición HTTP: %w", err)
+	}
+
+	var response chatResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error al parsear respuesta: %w", err)
+	}
+	return toDomainResponse(response), nil
+}
+
+// ListModels implementa la interfaz LLMRepository
+// Retorna los modelos descargados en el servidor de Ollama
+func (c *OllamaClient) ListModels(ctx context.Context) (*domain.ModelsResponse, error) {
+	body, err := c.doRequest(ctx, http.MethodGet, tagsEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener modelos: %w", err)
+	}
+
+	var tags tagsResponse
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("error al parsear modelos: %w", err)
+	}
+
+	models := &domain.ModelsResponse{Object: "list", Data: make([]domain.Model, len(tags.Models))}
+	for i, model := range tags.Models {
+		models.Data[i] = domain.Model{
+			ID:      model.Name,
+			Object:  "model",
+			Created: model.ModifiedAt,
+			OwnedBy: ownedBy,
+		}
+	}
+	return models, nil
+}
+
+// ProxyChatCompletion implementa la interfaz LLMRepository
+// Usa el endpoint compatible con OpenAI: el body se reenvía sin traducir
+func (c *OllamaClient) ProxyChatCompletion(
+	ctx context.Context,
+	body []byte,
+	stream bool,
+) (*domain.ProxyResponse, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, proxyEndpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.requestClient(ctx)
+	if stream {
+		client = c.streamClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, newTransportError(ctx, err)
+	}
+
+	// El body queda abierto: lo cerrará el llamador
+	return &domain.ProxyResponse{
+		StatusCode: resp.StatusCode,
+		Header:     map[string][]string{"Content-Type": resp.Header.Values("Content-Type")},
+		Body:       resp.Body,
+	}, nil
+}
+
+// ============================================================================
+// MÉTODOS PRIVADOS
+// ============================================================================
+
+// requestClient retorna el cliente de las peticiones sin streaming
+// (el plazo del contexto o el timeout vigente, ver groq.GroqClient.requestClient)
+func (c *OllamaClient) requestClient(ctx context.Context) *http.Client {
+	if _, ok := ctx.Deadline(); ok {
+		return c.streamClient
+	}
+	if c.settings == nil {
+		return c.httpClient
+	}
+	timeout := c.settings.Settings().HTTPTimeout
+	if timeout <= 0 || timeout == c.httpClient.Timeout {
+		return c.httpClient
+	}
+	return &http.Client{Timeout: timeout, Transport: c.httpClient.Transport}
+}
+
+// doRequest realiza la petición y retorna el body si el status es 2xx
+func (c *OllamaClient) doRequest(ctx context.Context, method, endpoint string, body []byte) ([]byte, error) {
+	req, err := c.newRequest(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.requestClient(ctx).Do(req)
+	if err != nil {
+		return nil, newTransportError(ctx, err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer respuesta: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, newAPIError(resp.StatusCode, responseBody)
+	}
+	return responseBody, nil
+}
+
+// newRequest crea una petición HTTP para el servidor de Ollama
+func (c *OllamaClient) newRequest(ctx context.Context, method, endpoint string, body []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("error al crear request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	return req, nil
+}
+
+// ============================================================================
+// TRADUCCIÓN DOMINIO ↔ OLLAMA
+// ============================================================================
+
+// toChatRequest traduce la petición del dominio al formato de Ollama
+// ToolChoice no tiene equivalente en Ollama y se ignora
+func toChatRequest(request domain.ChatRequest, stream bool) chatRequest {
+	result := chatRequest{
+		Model:    request.Model,
+		Messages: make([]chatMessage, len(request.Messages)),
+		Stream:   stream,
+		Tools:    request.Tools,
+	}
+
+	for i, message := range request.Messages {
+		result.Messages[i] = chatMessage{
+			Role:      message.Role,
+			Content:   message.Content,
+			ToolCalls: toToolCalls(message.ToolCalls),
+			Images:    toImages(message.Images),
+		}
+	}
+
+	if request.ResponseFormat != nil && request.ResponseFormat.Type == domain.JSONResponseFormat.Type {
+		result.Format = "json"
+	}
+
+	sampling := request.TopP != nil || request.FrequencyPenalty != nil ||
+		request.PresencePenalty != nil || request.Seed != nil
+	if request.Temperature != nil || request.MaxTokens > 0 || len(request.Stop) > 0 || sampling {
+		result.Options = &chatOptions{
+			Temperature:      request.Temperature,
+			NumPredict:       request.MaxTokens,
+			Stop:             request.Stop,
+			TopP:             request.TopP,
+			FrequencyPenalty: request.FrequencyPenalty,
+			PresencePenalty:  request.PresencePenalty,
+			Seed:             request.Seed,
+		}
+	}
+	return result
+}
+
+// toToolCalls traduce las llamadas del historial (argumentos como string JSON)
+func toToolCalls(calls []domain.ToolCall) []toolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	result := make([]toolCall, len(calls))
+	for i, call := range calls {
+		result[i].Function.Name = call.Function.Name
+		result[i].Function.Arguments = json.RawMessage(call.Function.Arguments)
+		// Ollama exige un objeto: si el modelo generó algo que no es JSON, se
+		// envía como string para no romper la petición
+		if !json.Valid(result[i].Function.Arguments) {
+			result[i].Function.Arguments, _ = json.Marshal(call.Function.Arguments)
+		}
+	}
+	return result
+}
+
+// toImages extrae el base64 de las imágenes del mensaje
+// Ollama no descarga URLs: las imágenes que no son data URI se omiten
+func toImages(images []domain.ImageURL) []string {
+	var result []string
+	for _, image := range images {
+		if data := image.Base64Data(); data != "" {
+			result = append(result, data)
+		}
+	}
+	return result
+}
+
+// toDomainResponse traduce una respuesta completa de Ollama al dominio
+func toDomainResponse(response chatResponse) *domain.ChatResponse {
+	toolCalls := toDomainToolCalls(response.Message.ToolCalls, false)
+
+	return &domain.ChatResponse{
+		ID:      responseID(response.CreatedAt),
+		Object:  "chat.completion",
+		Created: response.CreatedAt.Unix(),
+		Model:   response.Model,
+		Choices: []domain.Choice{{
+			Message: domain.ChatMessage{
+				Role:      "assistant",
+				Content:   response.Message.Content,
+				ToolCalls: toolCalls,
+			},
+			FinishReason: finishReason(response.DoneReason, len(toolCalls) > 0),
+		}},
+		Usage: toUsage(response),
+	}
+}
+
+// toDomainToolCalls traduce las llamadas a herramientas de Ollama al dominio
+// Ollama no genera ids: se numeran (el cliente los necesita para responder)
+func toDomainToolCalls(calls []toolCall, stream bool) []domain.ToolCall {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	result := make([]domain.ToolCall, len(calls))
+	for i, call := range calls {
+		result[i] = domain.ToolCall{
+			ID:   fmt.Sprintf("call_%d", i),
+			Type: "function",
+			Function: domain.ToolCallFunction{
+				Name:      call.Function.Name,
+				Arguments: string(call.Function.Arguments),
+			},
+		}
+		// En streaming, Index identifica la llamada (Ollama la envía entera)
+		if stream {
+			index := i
+			result[i].Index = &index
+		}
+	}
+	return result
+}
+
+// toUsage calcula el uso de tokens con los contadores de Ollama
+func toUsage(response chatResponse) domain.Usage {
+	return domain.Usage{
+		PromptTokens:     response.PromptEvalCount,
+		CompletionTokens: response.EvalCount,
+		TotalTokens:      response.PromptEvalCount + response.EvalCount,
+	}
+}
+
+// finishReason traduce done_reason al valor de OpenAI
+func finishReason(doneReason string, hasToolCalls bool) string {
+	if hasToolCalls {
+		return finishReasonToolUse
+	}
+	if doneReason == "" {
+		return "stop"
+	}
+	// "stop" y "length" coinciden con OpenAI; "load"/"unload" no son respuestas
+	return doneReason
+}
+
+// responseID genera un id para la respuesta (Ollama no envía ninguno)
+func responseID(createdAt time.Time) string {
+	return fmt.Sprintf("ollama-%d", createdAt.UnixNano())
+}
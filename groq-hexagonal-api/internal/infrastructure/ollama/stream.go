@@ -0,0 +1,167 @@
+// Package ollama - Soporte de streaming (JSON por líneas)
+package ollama
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"io"
+	"net/http"
+)
+
+// ============================================================================
+// STREAMING CON NDJSON
+// ============================================================================
+//
+// Con "stream": true, Ollama envía una respuesta parcial por línea:
+//
+//   {"model":"llama3.2","message":{"role":"assistant","content":"Hola"},"done":false}
+//   {"model":"llama3.2","message":{"role":"assistant","content":" mundo"},"done":false}
+//   {"model":"llama3.2","message":{...},"done":true,"done_reason":"stop","eval_count":2,...}
+//
+// La última línea ("done": true) trae la razón de fin y el uso de tokens.
+// Cada línea se traduce a un domain.ChatStreamChunk, igual que los de Groq.
+// ============================================================================
+
+// CreateChatCompletionStream implementa la interfaz LLMRepository
+func (c *OllamaClient) CreateChatCompletionStream(
+	ctx context.Context,
+	request domain.ChatRequest,
+) (domain.ChatStream, error) {
+	jsonData, err := json.Marshal(toChatRequest(request, true))
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar request: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, http.MethodPost, chatEndpoint, jsonData)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", contentTypeNDJSON)
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, newTransportError(ctx, err)
+	}
+
+	// Si Ollama rechaza la petición, el body es un único JSON de error
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		responseBody, _ := io.ReadAll(resp.Body)
+		return nil, newAPIError(resp.StatusCode, responseBody)
+	}
+
+	// El body queda abierto: lo cerrará el llamador con stream.Close()
+	return &ollamaStream{
+		body:   resp.Body,
+		reader: bufio.NewReader(resp.Body),
+	}, nil
+}
+
+// ollamaStream lee las líneas del body y las traduce a fragmentos del dominio
+type ollamaStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+
+	// id es el de la primera línea: todos los fragmentos comparten el mismo
+	id string
+
+	// done indica que ya llegó la última línea
+	done bool
+
+	// toolCalls indica que el modelo pidió herramientas en alguna línea
+	// (llegan antes de la última, que es la que lleva la razón de fin)
+	toolCalls bool
+
+	// long acumula las líneas que no caben en el buffer del reader
+	long []byte
+}
+
+// Recv lee la siguiente línea con datos
+// Retorna io.EOF tras la línea con "done": true o si se cierra la conexión
+func (s *ollamaStream) Recv() (*domain.ChatStreamChunk, error) {
+	for !s.done {
+		line, err := s.readLine()
+		if len(line) == 0 && err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("error al leer el flujo: %w", err)
+		}
+
+		// La última línea puede llegar sin '\n' justo antes del EOF
+		var event chatResponse
+		if jsonErr := json.Unmarshal(line, &event); jsonErr != nil {
+			if len(bytes.TrimSpace(line)) == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("error al parsear fragmento: %w", jsonErr)
+		}
+		if event.Error != "" {
+			return nil, fmt.Errorf("error de Ollama durante el streaming: %s", event.Error)
+		}
+
+		if s.id == "" {
+			s.id = responseID(event.CreatedAt)
+		}
+		s.done = event.Done
+		return s.toChunk(event), nil
+	}
+	return nil, io.EOF
+}
+
+// toChunk traduce una línea al fragmento del dominio
+func (s *ollamaStream) toChunk(event chatResponse) *domain.ChatStreamChunk {
+	toolCalls := toDomainToolCalls(event.Message.ToolCalls, true)
+	if len(toolCalls) > 0 {
+		s.toolCalls = true
+	}
+
+	choice := domain.StreamChoice{
+		Delta: domain.ChatMessage{
+			Role:      "assistant",
+			Content:   event.Message.Content,
+			ToolCalls: toolCalls,
+		},
+	}
+	chunk := &domain.ChatStreamChunk{
+		ID:      s.id,
+		Object:  "chat.completion.chunk",
+		Created: event.CreatedAt.Unix(),
+		Model:   event.Model,
+		Choices: []domain.StreamChoice{choice},
+	}
+
+	if event.Done {
+		usage := toUsage(event)
+		chunk.Usage = &usage
+		chunk.Choices[0].FinishReason = finishReason(event.DoneReason, s.toolCalls)
+	}
+	return chunk
+}
+
+// readLine lee la siguiente línea sin copiarla si cabe en el buffer del
+// reader; las más largas se acumulan en s.long (igual que en el adaptador de
+// Groq). La línea solo es válida hasta la siguiente llamada
+func (s *ollamaStream) readLine() ([]byte, error) {
+	line, err := s.reader.ReadSlice('\n')
+	if !errors.Is(err, bufio.ErrBufferFull) {
+		return line, err
+	}
+
+	s.long = append(s.long[:0], line...)
+	for errors.Is(err, bufio.ErrBufferFull) {
+		line, err = s.reader.ReadSlice('\n')
+		s.long = append(s.long, line...)
+	}
+	return s.long, err
+}
+
+// Close cierra la conexión con Ollama
+func (s *ollamaStream) Close() error {
+	return s.body.Close()
+}
@@ -0,0 +1,42 @@
+// Package openai implementa el adaptador para la API de OpenAI
+package openai
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"groq-hexagonal-api/internal/infrastructure/groq"
+	"time"
+)
+
+// ============================================================================
+// ADAPTADOR DE OPENAI
+// ============================================================================
+//
+// La API de Groq es compatible con la de OpenAI (mismos endpoints, mismo JSON
+// y mismo formato de errores), así que este adaptador reutiliza el cliente de
+// Groq con otra URL base. La única diferencia que importa aquí es que OpenAI
+// no envía el uso de tokens en streaming salvo que se pida con stream_options.
+//
+// También sirve para cualquier otra API compatible (Azure OpenAI, vLLM,
+// LiteLLM...) cambiando la URL base.
+// ============================================================================
+
+// DefaultBaseURL es la URL base de la API de OpenAI
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// NewOpenAIClient crea el adaptador para la API de OpenAI
+// opts admite las mismas opciones que el cliente de Groq (ej: groq.WithRateLimitObserver)
+func NewOpenAIClient(apiKey, baseURL string, timeout time.Duration, opts ...groq.ClientOption) domain.LLMRepository {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return groq.NewGroqClient(apiKey, baseURL, timeout, append(opts, groq.WithStreamUsage())...)
+}
+
+// NewEmbeddingClient crea el cliente de embeddings de la API de OpenAI
+// (lo usa la caché semántica)
+func NewEmbeddingClient(apiKey, baseURL string, timeout time.Duration, opts ...groq.ClientOption) domain.EmbeddingRepository {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return groq.NewEmbeddingClient(apiKey, baseURL, timeout, opts...)
+}
@@ -0,0 +1,184 @@
+// Package pdf genera documentos PDF sencillos de solo texto (ej: extractos)
+//
+// No hay dependencias externas: el documento usa dos de las fuentes estándar
+// que todo lector de PDF trae (Helvetica-Bold para los títulos y Courier para
+// el texto), así que no se incrustan fuentes. Courier es monoespaciada: las
+// tablas se alinean con espacios, como en un terminal.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// FORMATO
+// ============================================================================
+//
+// Un PDF es una lista de objetos numerados ("3 0 obj ... endobj") seguida de
+// la tabla xref con la posición en bytes de cada uno y un trailer que indica
+// el objeto raíz. Aquí:
+//
+//   1: catálogo → 2: árbol de páginas → una página + su contenido por hoja
+//   3 y 4: las fuentes; 5: metadatos (título, fecha)
+//
+// El contenido de cada página son operadores de texto: "BT /F2 9 Tf 50 780 Td
+// (texto) Tj ET" escribe "texto" con la fuente F2 a 9 puntos en (50, 780),
+// midiendo desde la esquina inferior izquierda.
+// ============================================================================
+
+// Medidas de la página (A4 en puntos) y del texto
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+
+	headingSize = 14
+	textSize    = 9
+	lineSpacing = 1.4
+)
+
+// MaxLineChars es cuántos caracteres de texto caben en una línea
+// (Courier mide 0,6 veces el tamaño de la fuente)
+const MaxLineChars = (pageWidth - 2*margin) * 10 / (textSize * 6)
+
+// line es una línea ya colocada en su página
+type line struct {
+	font string
+	size float64
+	y    float64
+	text string
+}
+
+// Document es un PDF en construcción
+type Document struct {
+	title string
+	pages [][]line
+
+	// y es la altura de la siguiente línea en la página actual
+	y float64
+}
+
+// NewDocument crea un documento vacío; title va en los metadatos
+func NewDocument(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+// Heading añade un título
+func (d *Document) Heading(text string) {
+	d.add("F1", headingSize, text)
+}
+
+// Text añade una línea de texto; lo que no cabe se corta
+func (d *Document) Text(text string) {
+	if runes := []rune(text); len(runes) > MaxLineChars {
+		text = string(runes[:MaxLineChars])
+	}
+	d.add("F2", textSize, text)
+}
+
+// Blank añade una línea vacía
+func (d *Document) Blank() {
+	d.y -= textSize * lineSpacing
+}
+
+// add coloca una línea, empezando página si no cabe
+func (d *Document) add(font string, size float64, text string) {
+	height := size * lineSpacing
+	if d.y-height < margin {
+		d.newPage()
+	}
+	d.y -= height
+	page := len(d.pages) - 1
+	d.pages[page] = append(d.pages[page], line{font: font, size: size, y: d.y, text: text})
+}
+
+// newPage empieza una página
+func (d *Document) newPage() {
+	d.pages = append(d.pages, nil)
+	d.y = pageHeight - margin
+}
+
+// Bytes genera el PDF
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	// offsets[i] es la posición del objeto i+1
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// La segunda línea con bytes altos indica a las herramientas que es binario
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Las páginas empiezan en el objeto 6: página 6, contenido 7, página 8...
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (groq-hexagonal-api) /CreationDate (D:%s) >>",
+		literal(d.title), time.Now().UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 7+2*i))
+
+		var content bytes.Buffer
+		for _, line := range page {
+			fmt.Fprintf(&content, "BT /%s %g Tf %d %g Td %s Tj ET\n", line.font, line.size, margin, line.y, literal(line.text))
+		}
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// literal escribe text como cadena de PDF: "(...)" en WinAnsiEncoding, con
+// \, ( y ) escapados. Los caracteres que la codificación no tiene salen como "?"
+func literal(text string) string {
+	var out strings.Builder
+	out.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			out.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			// Latin-1 coincide con WinAnsi en este rango (á, é, ñ, ü...)
+			fmt.Fprintf(&out, "\\%03o", r)
+		case winAnsi[r] != 0:
+			fmt.Fprintf(&out, "\\%03o", winAnsi[r])
+		default:
+			out.WriteByte('?')
+		}
+	}
+	out.WriteByte(')')
+	return out.String()
+}
+
+// winAnsi son los caracteres de WinAnsiEncoding fuera de Latin-1 que se usan
+var winAnsi = map[rune]byte{
+	'€': 0x80,
+	'…': 0x85,
+	'•': 0x95,
+	'–': 0x96,
+	'—': 0x97,
+}
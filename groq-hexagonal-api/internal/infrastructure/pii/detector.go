@@ -0,0 +1,162 @@
+// Package pii implementa la detección de datos personales (adaptador secundario)
+// Implementa domain.PIIDetector sin dependencias externas
+package pii
+
+import (
+	"groq-hexagonal-api/internal/domain"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// DETECCIÓN POR PATRONES
+// ============================================================================
+//
+// Cada tipo de dato tiene una expresión regular y, si el formato lo permite,
+// una validación de su dígito de control: el algoritmo de Luhn en las
+// tarjetas, el módulo 97 en los IBAN y la letra del DNI/NIE. La validación
+// descarta la mayoría de números que solo se parecen (pedidos, referencias).
+//
+// Los teléfonos no tienen dígito de control: se detectan los internacionales
+// (+34 612 345 678) y los españoles de 9 cifras que empiezan por 6, 7, 8 o 9.
+//
+// Los nombres de personas o de empresas no siguen ningún patrón y no se
+// detectan: para eso hace falta otro adaptador (un modelo de reconocimiento
+// de entidades) que implemente domain.PIIDetector.
+// ============================================================================
+
+// pattern asocia una expresión regular con su tipo y su validación
+type pattern struct {
+	entityType string
+	re         *regexp.Regexp
+
+	// valid descarta las coincidencias que no son datos reales (nil = todas valen)
+	valid func(match string) bool
+}
+
+// patterns se compilan una sola vez al cargar el paquete
+var patterns = []pattern{
+	{domain.PIIEmail, regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`), nil},
+	{domain.PIICreditCard, regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), validLuhn},
+	{domain.PIIIBAN, regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`), validIBAN},
+	{domain.PIINationalID, regexp.MustCompile(`\b[XYZ0-9]\d{7}-?[A-Z]\b`), validNationalID},
+	{domain.PIIIPAddress, regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`), validIPv4},
+	{domain.PIIPhone, regexp.MustCompile(`\+\d{1,3}(?:[ .-]?\d{2,4}){2,5}\b`), nil},
+	{domain.PIIPhone, regexp.MustCompile(`\b[6789]\d{2}(?:[ .-]?\d{3}){2}\b|\b[6789]\d{2}(?:[ .-]?\d{2}){3}\b`), nil},
+}
+
+// RegexDetector detecta datos personales con expresiones regulares
+type RegexDetector struct{}
+
+// NewRegexDetector crea el detector
+func NewRegexDetector() *RegexDetector {
+	return &RegexDetector{}
+}
+
+// Detect implementa domain.PIIDetector
+// Un mismo fragmento puede coincidir con varios patrones (ej: una tarjeta y
+// un teléfono): el RedactionService se queda con el más largo
+func (d *RegexDetector) Detect(text string) []domain.PIIEntity {
+	var entities []domain.PIIEntity
+	for _, p := range patterns {
+		for _, loc := range p.re.FindAllStringIndex(text, -1) {
+			match := text[loc[0]:loc[1]]
+			if p.valid != nil && !p.valid(match) {
+				continue
+			}
+			entities = append(entities, domain.PIIEntity{
+				Type:  p.entityType,
+				Text:  match,
+				Start: loc[0],
+				End:   loc[1],
+			})
+		}
+	}
+	return entities
+}
+
+// ============================================================================
+// VALIDACIONES
+// ============================================================================
+
+// digitsOnly elimina los separadores (espacios y guiones)
+func digitsOnly(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// validLuhn comprueba el dígito de control de una tarjeta (algoritmo de Luhn)
+func validLuhn(match string) bool {
+	digits := digitsOnly(match)
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(digits) - 1; i >= 0; i-- {
+		n := int(digits[i] - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// validIBAN comprueba los dígitos de control de un IBAN (módulo 97)
+func validIBAN(match string) bool {
+	iban := digitsOnly(match)
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+
+	// Los 4 primeros caracteres van al final y cada letra vale 10 + su posición
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, r := range rearranged {
+		var value int
+		switch {
+		case r >= '0' && r <= '9':
+			value = int(r - '0')
+			remainder = (remainder*10 + value) % 97
+		case r >= 'A' && r <= 'Z':
+			value = int(r-'A') + 10
+			remainder = (remainder*100 + value) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// nationalIDLetters es la tabla de letras de control del DNI/NIE
+const nationalIDLetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+// validNationalID comprueba la letra de control de un DNI o NIE
+func validNationalID(match string) bool {
+	id := digitsOnly(match)
+
+	// En el NIE, la letra inicial se sustituye por un dígito (X=0, Y=1, Z=2)
+	number := strings.NewReplacer("X", "0", "Y", "1", "Z", "2").Replace(id[:len(id)-1])
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return false
+	}
+	return nationalIDLetters[n%23] == id[len(id)-1]
+}
+
+// validIPv4 comprueba que cada octeto está entre 0 y 255
+func validIPv4(match string) bool {
+	for _, octet := range strings.Split(match, ".") {
+		n, err := strconv.Atoi(octet)
+		if err != nil || n > 255 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// API KEYS EN POSTGRESQL
+// ============================================================================
+//
+// Una fila por clave (api_keys) con los scopes en JSONB. La autenticación
+// busca por hash o previous_hash, los dos con índice.
+// ============================================================================
+
+// apiKeyColumns son las columnas que lee scanAPIKey, en orden
+const apiKeyColumns = `id, name, prefix, hash, scopes, tier, created_at, expires_at,
+	disabled_at, rotated_at, previous_hash, previous_expires_at, expiring_notified_at,
+	expired_notified_at, tenant`
+
+// APIKeyRepository guarda las API keys en PostgreSQL
+// Implementa domain.APIKeyRepository
+type APIKeyRepository struct {
+	db *DB
+}
+
+// NewAPIKeyRepository crea el repositorio sobre un pool ya abierto
+// El esquema debe existir (ver Migrate)
+func NewAPIKeyRepository(db *DB) *APIKeyRepository {
+	if db == nil {
+		panic("db no puede ser nil")
+	}
+
+	return &APIKeyRepository{db: db}
+}
+
+// SaveAPIKey implementa domain.APIKeyRepository
+func (r *APIKeyRepository) SaveAPIKey(ctx context.Context, key *domain.APIKey) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return fmt.Errorf("error al serializar la API key: %w", err)
+	}
+	var previousHash sql.NullString
+	if key.PreviousHash != "" {
+		previousHash = sql.NullString{String: key.PreviousHash, Valid: true}
+	}
+
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO api_keys (`+apiKeyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			prefix = EXCLUDED.prefix,
+			hash = EXCLUDED.hash,
+			scopes = EXCLUDED.scopes,
+			tier = EXCLUDED.tier,
+			expires_at = EXCLUDED.expires_at,
+			disabled_at = EXCLUDED.disabled_at,
+			rotated_at = EXCLUDED.rotated_at,
+			previous_hash = EXCLUDED.previous_hash,
+			previous_expires_at = EXCLUDED.previous_expires_at,
+			expiring_notified_at = EXCLUDED.expiring_notified_at,
+			expired_notified_at = EXCLUDED.expired_notified_at,
+			tenant = EXCLUDED.tenant`,
+		key.ID, key.Name, key.Prefix, key.Hash, scopes, key.Tier, key.CreatedAt, key.ExpiresAt,
+		key.DisabledAt, key.RotatedAt, previousHash, key.PreviousExpiresAt, key.ExpiringNotifiedAt,
+		key.ExpiredNotifiedAt, key.Tenant,
+	); err != nil {
+		return fmt.Errorf("error al guardar la API key: %w", err)
+	}
+	return nil
+}
+
+// FindAPIKey implementa domain.APIKeyRepository
+func (r *APIKeyRepository) FindAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+}
+
+// FindAPIKeyByHash implementa domain.APIKeyRepository
+func (r *APIKeyRepository) FindAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	return scanAPIKey(r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys WHERE hash = $1 OR previous_hash = $1
+		LIMIT 1`, hash))
+}
+
+// ListAPIKeys implementa domain.APIKeyRepository
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("error al listar las API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []domain.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error al listar las API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// scanAPIKey lee una fila con apiKeyColumns
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes []byte
+	var previousHash sql.NullString
+
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		&key.Hash,
+		&scopes,
+		&key.Tier,
+		&key.CreatedAt,
+		&key.ExpiresAt,
+		&key.DisabledAt,
+		&key.RotatedAt,
+		&previousHash,
+		&key.PreviousExpiresAt,
+		&key.ExpiringNotifiedAt,
+		&key.ExpiredNotifiedAt,
+		&key.Tenant,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la API key: %w", err)
+	}
+	key.PreviousHash = previousHash.String
+
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return nil, fmt.Errorf("API key corrupta %s: %w", key.ID, err)
+	}
+	return &key, nil
+}
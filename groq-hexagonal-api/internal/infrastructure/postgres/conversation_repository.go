@@ -0,0 +1,263 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"time"
+)
+
+// ============================================================================
+// REPOSITORIO DE CONVERSACIONES EN POSTGRESQL
+// ============================================================================
+//
+// Una fila por conversación (conversations) y una por mensaje
+// (conversation_messages), con los datos del turno del asistente (modelo,
+// latencia y tokens) en columnas: se pueden consultar con SQL para analítica.
+// ============================================================================
+
+// ConversationRepository guarda las conversaciones en PostgreSQL
+// Implementa domain.ConversationRepository
+type ConversationRepository struct {
+	// db es un pool de conexiones: seguro para usar desde varias goroutines
+	db *DB
+}
+
+// NewConversationRepository crea el repositorio sobre un pool ya abierto
+// El esquema debe existir (ver Migrate)
+func NewConversationRepository(db *DB) *ConversationRepository {
+	if db == nil {
+		panic("db no puede ser nil")
+	}
+
+	return &ConversationRepository{db: db}
+}
+
+// Save crea o reemplaza la conversación
+// La conversación y sus mensajes se escriben en una sola transacción
+func (r *ConversationRepository) Save(ctx context.Context, conversation *domain.Conversation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+	defer tx.Rollback()
+
+	var budget []byte
+	if conversation.Budget != nil {
+		if budget, err = json.Marshal(conversation.Budget); err != nil {
+			return fmt.Errorf("error al serializar el presupuesto: %w", err)
+		}
+	}
+	var summary []byte
+	if conversation.Summary != nil {
+		if summary, err = json.Marshal(conversation.Summary); err != nil {
+			return fmt.Errorf("error al serializar el resumen: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO conversations (id, model, temperature, system_prompt, budget, summary, created_at, updated_at, deleted_at, purge_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (id) DO UPDATE SET
+			model = EXCLUDED.model,
+			temperature = EXCLUDED.temperature,
+			system_prompt = EXCLUDED.system_prompt,
+			budget = EXCLUDED.budget,
+			summary = EXCLUDED.summary,
+			updated_at = EXCLUDED.updated_at,
+			deleted_at = EXCLUDED.deleted_at,
+			purge_at = EXCLUDED.purge_at`,
+		conversation.ID,
+		conversation.Model,
+		conversation.Temperature,
+		conversation.SystemPrompt,
+		budget,
+		summary,
+		conversation.CreatedAt,
+		conversation.UpdatedAt,
+		conversation.DeletedAt,
+		conversation.PurgeAt,
+	); err != nil {
+		return fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+
+	// El historial solo crece: basta con insertar los mensajes nuevos
+	// (ON CONFLICT ignora los que ya estaban guardados)
+	for position, message := range conversation.Messages {
+		if err := insertMessage(ctx, tx, conversation.ID, position, message); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+	return nil
+}
+
+// insertMessage guarda un mensaje del historial si no existía
+func insertMessage(ctx context.Context, tx *sql.Tx, conversationID string, position int, message domain.ConversationMessage) error {
+	var toolCalls []byte
+	if len(message.ToolCalls) > 0 {
+		var err error
+		if toolCalls, err = json.Marshal(message.ToolCalls); err != nil {
+			return fmt.Errorf("error al serializar tool_calls: %w", err)
+		}
+	}
+
+	// Columnas del turno: NULL si el mensaje no es una respuesta del asistente
+	var (
+		model                          sql.NullString
+		latencyMs                      sql.NullInt64
+		promptTokens, completionTokens sql.NullInt32
+		totalTokens                    sql.NullInt32
+		costUSD                        sql.NullFloat64
+		finishReason                   sql.NullString
+		cacheHit                       sql.NullBool
+	)
+	if turn := message.Turn; turn != nil {
+		model = sql.NullString{String: turn.Model, Valid: true}
+		latencyMs = sql.NullInt64{Int64: turn.Latency.Milliseconds(), Valid: true}
+		promptTokens = sql.NullInt32{Int32: int32(turn.Usage.PromptTokens), Valid: true}
+		completionTokens = sql.NullInt32{Int32: int32(turn.Usage.CompletionTokens), Valid: true}
+		totalTokens = sql.NullInt32{Int32: int32(turn.Usage.TotalTokens), Valid: true}
+		costUSD = sql.NullFloat64{Float64: turn.Usage.CostUSD, Valid: true}
+		finishReason = sql.NullString{String: turn.FinishReason, Valid: true}
+		cacheHit = sql.NullBool{Bool: turn.CacheHit, Valid: true}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO conversation_messages (
+			conversation_id, position, role, content, tool_calls, tool_call_id, created_at,
+			turn_model, latency_ms, prompt_tokens, completion_tokens, total_tokens, cost_usd, finish_reason, cache_hit
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (conversation_id, position) DO NOTHING`,
+		conversationID, position, message.Role, message.Content, toolCalls, message.ToolCallID, message.CreatedAt,
+		model, latencyMs, promptTokens, completionTokens, totalTokens, costUSD, finishReason, cacheHit,
+	); err != nil {
+		return fmt.Errorf("error al guardar el mensaje %d: %w", position, err)
+	}
+	return nil
+}
+
+// FindByID retorna la conversación con su historial o domain.ErrConversationNotFound
+func (r *ConversationRepository) FindByID(ctx context.Context, id string) (*domain.Conversation, error) {
+	conversation := &domain.Conversation{ID: id}
+
+	var budget, summary []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT model, temperature, system_prompt, budget, summary, created_at, updated_at, deleted_at, purge_at
+		FROM conversations WHERE id = $1`, id,
+	).Scan(
+		&conversation.Model,
+		&conversation.Temperature,
+		&conversation.SystemPrompt,
+		&budget,
+		&summary,
+		&conversation.CreatedAt,
+		&conversation.UpdatedAt,
+		&conversation.DeletedAt,
+		&conversation.PurgeAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la conversación: %w", err)
+	}
+	if len(budget) > 0 {
+		if err := json.Unmarshal(budget, &conversation.Budget); err != nil {
+			return nil, fmt.Errorf("presupuesto inválido: %w", err)
+		}
+	}
+	if len(summary) > 0 {
+		if err := json.Unmarshal(summary, &conversation.Summary); err != nil {
+			return nil, fmt.Errorf("resumen inválido: %w", err)
+		}
+	}
+
+	messages, err := r.findMessages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	conversation.Messages = messages
+	return conversation, nil
+}
+
+// findMessages lee el historial en orden cronológico
+func (r *ConversationRepository) findMessages(ctx context.Context, conversationID string) ([]domain.ConversationMessage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT role, content, tool_calls, tool_call_id, created_at,
+			turn_model, latency_ms, prompt_tokens, completion_tokens, total_tokens, cost_usd, finish_reason, cache_hit
+		FROM conversation_messages
+		WHERE conversation_id = $1
+		ORDER BY position`, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer los mensajes: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []domain.ConversationMessage{}
+	for rows.Next() {
+		var (
+			message                        domain.ConversationMessage
+			toolCalls                      []byte
+			model                          sql.NullString
+			latencyMs                      sql.NullInt64
+			promptTokens, completionTokens sql.NullInt32
+			totalTokens                    sql.NullInt32
+			costUSD                        sql.NullFloat64
+			finishReason                   sql.NullString
+			cacheHit                       sql.NullBool
+		)
+		if err := rows.Scan(
+			&message.Role, &message.Content, &toolCalls, &message.ToolCallID, &message.CreatedAt,
+			&model, &latencyMs, &promptTokens, &completionTokens, &totalTokens, &costUSD, &finishReason, &cacheHit,
+		); err != nil {
+			return nil, fmt.Errorf("error al leer los mensajes: %w", err)
+		}
+
+		if len(toolCalls) > 0 {
+			if err := json.Unmarshal(toolCalls, &message.ToolCalls); err != nil {
+				return nil, fmt.Errorf("tool_calls inválidos: %w", err)
+			}
+		}
+
+		// turn_model solo es NULL en los mensajes sin turno (usuario, tool)
+		if model.Valid {
+			message.Turn = &domain.TurnMeta{
+				Model:   model.String,
+				Latency: time.Duration(latencyMs.Int64) * time.Millisecond,
+				Usage: domain.Usage{
+					PromptTokens:     int(promptTokens.Int32),
+					CompletionTokens: int(completionTokens.Int32),
+					TotalTokens:      int(totalTokens.Int32),
+					CostUSD:          costUSD.Float64,
+				},
+				FinishReason: finishReason.String,
+				CacheHit:     cacheHit.Bool,
+			}
+		}
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+// PurgeDeleted elimina las conversaciones borradas cuyo plazo ha pasado
+// Los mensajes se eliminan en cascada (ON DELETE CASCADE)
+func (r *ConversationRepository) PurgeDeleted(ctx context.Context, now time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM conversations WHERE purge_at <= $1`, now)
+	if err != nil {
+		return 0, fmt.Errorf("error al purgar conversaciones: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(purged), nil
+}
@@ -0,0 +1,216 @@
+// Package postgres implementa repositorios sobre PostgreSQL (adaptadores secundarios)
+// Los datos sobreviven a los reinicios y se comparten entre réplicas
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// CONEXIÓN Y MIGRACIONES
+// ============================================================================
+//
+// El paquete solo usa database/sql: el driver ("pgx") se registra en
+// driver.go, que se compila con la etiqueta "postgres" (make build-postgres).
+// Así el binario por defecto no depende de ningún driver de base de datos.
+//
+// Las migraciones son ficheros .sql en migrations/, embebidos en el binario
+// con go:embed y aplicados en orden de nombre. La tabla schema_migrations
+// recuerda cuáles se aplicaron: cada una se ejecuta una sola vez.
+//
+// DB es el pool del primario (escrituras y lecturas que deben ver lo último
+// escrito) y, opcionalmente, el de una réplica de lectura (ver read.go).
+// ============================================================================
+
+// driverName es el nombre con el que se registra el driver de PostgreSQL
+const driverName = "pgx"
+
+// migrationsFS contiene los ficheros de migrations/ (se compilan en el binario)
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// Options configura los pools de conexiones (el del primario y el de la réplica)
+type Options struct {
+	// ReplicaDSN es el DSN de la réplica de lectura ("" = sin réplica)
+	ReplicaDSN string
+
+	// Tamaño de cada pool y vida de sus conexiones (0 = el de database/sql)
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// StatementTimeout cancela las sentencias que tardan más (0 = sin límite)
+	StatementTimeout time.Duration
+
+	// ReadRetries son los reintentos de una lectura si la conexión cae
+	ReadRetries int
+}
+
+// DB son los pools de PostgreSQL
+// Embebe el del primario: los repositorios escriben con r.db.ExecContext...
+type DB struct {
+	*sql.DB
+
+	// replica es el pool de la réplica de lectura (nil = se lee del primario)
+	replica *sql.DB
+
+	readRetries int
+}
+
+// Open abre los pools de conexiones y comprueba que la base de datos responde
+func Open(ctx context.Context, dsn string, opts Options) (*DB, error) {
+	primary, err := openPool(ctx, dsn, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &DB{DB: primary, readRetries: opts.ReadRetries}
+	if opts.ReplicaDSN != "" {
+		if db.replica, err = openPool(ctx, opts.ReplicaDSN, opts); err != nil {
+			primary.Close()
+			return nil, fmt.Errorf("réplica de lectura: %w", err)
+		}
+	}
+	return db, nil
+}
+
+// Close cierra los pools
+func (d *DB) Close() error {
+	if d.replica != nil {
+		d.replica.Close()
+	}
+	return d.DB.Close()
+}
+
+// HasReplica indica si las lecturas van a una réplica
+func (d *DB) HasReplica() bool {
+	return d.replica != nil
+}
+
+// openPool abre un pool de conexiones y comprueba que responde
+func openPool(ctx context.Context, dsn string, opts Options) (*sql.DB, error) {
+	if opts.StatementTimeout > 0 {
+		dsn = withRuntimeParam(dsn, "statement_timeout", strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10))
+	}
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		// sql.Open solo falla si el driver no está registrado
+		return nil, fmt.Errorf("error al abrir PostgreSQL (¿binario compilado sin -tags postgres?): %w", err)
+	}
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
+	}
+	db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+
+	// sql.Open no conecta: Ping detecta un DSN erróneo al arrancar, no en la
+	// primera petición
+	pingCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error al conectar con PostgreSQL: %w", err)
+	}
+	return db, nil
+}
+
+// withRuntimeParam añade un parámetro de sesión al DSN (pgx envía al
+// servidor los que no conoce), tanto en forma de URL como de "clave=valor"
+func withRuntimeParam(dsn, key, value string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if parsed, err := url.Parse(dsn); err == nil {
+			query := parsed.Query()
+			query.Set(key, value)
+			parsed.RawQuery = query.Encode()
+			return parsed.String()
+		}
+	}
+	return strings.TrimSpace(dsn) + " " + key + "=" + value
+}
+
+// Migrate aplica las migraciones pendientes
+// Retorna cuántas se aplicaron (0 si el esquema ya estaba al día)
+func Migrate(ctx context.Context, db *sql.DB) (int, error) {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		name       TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`); err != nil {
+		return 0, fmt.Errorf("error al crear schema_migrations: %w", err)
+	}
+
+	names, err := fs.Glob(migrationsFS, "migrations/*.sql")
+	if err != nil {
+		return 0, err
+	}
+	// El prefijo numérico (0001_, 0002_...) fija el orden
+	sort.Strings(names)
+
+	applied := 0
+	for _, name := range names {
+		done, err := applyMigration(ctx, db, name)
+		if err != nil {
+			return applied, err
+		}
+		if done {
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// applyMigration ejecuta una migración si no se aplicó antes
+// La migración y su registro van en la misma transacción: o todo o nada
+func applyMigration(ctx context.Context, db *sql.DB, name string) (bool, error) {
+	script, err := migrationsFS.ReadFile(name)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback tras un Commit correcto no hace nada
+	defer tx.Rollback()
+
+	// Las migraciones pueden tardar más que el límite de las sentencias
+	if _, err := tx.ExecContext(ctx, `SET LOCAL statement_timeout = 0`); err != nil {
+		return false, err
+	}
+
+	// El bloqueo evita que dos réplicas que arrancan a la vez migren las dos
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE schema_migrations IN EXCLUSIVE MODE`); err != nil {
+		return false, fmt.Errorf("error al bloquear schema_migrations: %w", err)
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE name = $1)`, name,
+	).Scan(&exists); err != nil {
+		return false, err
+	}
+	if exists {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+		return false, fmt.Errorf("error en la migración %s: %w", name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (name) VALUES ($1)`, name); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,7 @@
+//go:build postgres
+
+package postgres
+
+// Registra el driver "pgx" en database/sql
+// Solo se compila con -tags postgres (make build-postgres)
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// VALORACIONES EN POSTGRESQL
+// ============================================================================
+//
+// Una fila por valoración (feedback). Los resúmenes filtran por fecha y, si
+// se pide, por tenant: los dos con índice.
+// ============================================================================
+
+// FeedbackRepository guarda las valoraciones en PostgreSQL
+// Implementa domain.FeedbackRepository
+type FeedbackRepository struct {
+	db *DB
+}
+
+// NewFeedbackRepository crea el repositorio sobre un pool ya abierto
+// El esquema debe existir (ver Migrate)
+func NewFeedbackRepository(db *DB) *FeedbackRepository {
+	if db == nil {
+		panic("db no puede ser nil")
+	}
+
+	return &FeedbackRepository{db: db}
+}
+
+// SaveFeedback implementa domain.FeedbackRepository
+func (r *FeedbackRepository) SaveFeedback(ctx context.Context, feedback *domain.Feedback) error {
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO feedback (id, response_id, conversation_id, rating, comment, model, persona, tenant, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		feedback.ID, feedback.ResponseID, feedback.ConversationID, feedback.Rating, feedback.Comment,
+		feedback.Model, feedback.Persona, feedback.Tenant, feedback.CreatedAt,
+	); err != nil {
+		return fmt.Errorf("error al guardar la valoración: %w", err)
+	}
+	return nil
+}
+
+// ListFeedback implementa domain.FeedbackRepository
+// Lee de la réplica, si la hay: un resumen admite unos segundos de retraso
+func (r *FeedbackRepository) ListFeedback(ctx context.Context, filter domain.FeedbackFilter) ([]domain.Feedback, error) {
+	var feedbacks []domain.Feedback
+	err := r.db.read(ctx, func(tx *sql.Tx) error {
+		// Un reintento empieza de cero
+		feedbacks = nil
+
+		rows, err := tx.QueryContext(ctx, `
+			SELECT id, response_id, conversation_id, rating, comment, model, persona, tenant, created_at
+			FROM feedback
+			WHERE created_at >= $1 AND ($2 = '' OR tenant = $2)
+			ORDER BY created_at`, filter.Since, filter.Tenant)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var feedback domain.Feedback
+			if err := rows.Scan(
+				&feedback.ID,
+				&feedback.ResponseID,
+				&feedback.ConversationID,
+				&feedback.Rating,
+				&feedback.Comment,
+				&feedback.Model,
+				&feedback.Persona,
+				&feedback.Tenant,
+				&feedback.CreatedAt,
+			); err != nil {
+				return err
+			}
+			feedbacks = append(feedbacks, feedback)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error al leer las valoraciones: %w", err)
+	}
+	return feedbacks, nil
+}
@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// JOBS EN POSTGRESQL
+// ============================================================================
+//
+// Una fila por job (chat_jobs) con la respuesta del modelo (o la referencia a
+// ella, si se guardó aparte), el estado del callback y el progreso en
+// columnas JSONB. Cada job nuevo elimina de paso los caducados: no hace falta
+// otro proceso de limpieza y el índice de expires_at lo hace barato.
+// ============================================================================
+
+// JobRepository guarda los jobs en PostgreSQL
+// Implementa domain.JobRepository
+type JobRepository struct {
+	db *DB
+}
+
+// NewJobRepository crea el repositorio sobre un pool ya abierto
+// El esquema debe existir (ver Migrate)
+func NewJobRepository(db *DB) *JobRepository {
+	if db == nil {
+		panic("db no puede ser nil")
+	}
+
+	return &JobRepository{db: db}
+}
+
+// SaveJob implementa domain.JobRepository
+func (r *JobRepository) SaveJob(ctx context.Context, job *domain.Job) error {
+	var result []byte
+	if job.Result != nil {
+		data, err := json.Marshal(job.Result)
+		if err != nil {
+			return fmt.Errorf("error al serializar el job: %w", err)
+		}
+		result = data
+	}
+	var callback []byte
+	if job.Callback != nil {
+		data, err := json.Marshal(job.Callback)
+		if err != nil {
+			return fmt.Errorf("error al serializar el job: %w", err)
+		}
+		callback = data
+	}
+	var resultRef []byte
+	if job.ResultRef != nil {
+		data, err := json.Marshal(job.ResultRef)
+		if err != nil {
+			return fmt.Errorf("error al serializar el job: %w", err)
+		}
+		resultRef = data
+	}
+	var progress []byte
+	if job.Progress != nil {
+		data, err := json.Marshal(job.Progress)
+		if err != nil {
+			return fmt.Errorf("error al serializar el job: %w", err)
+		}
+		progress = data
+	}
+
+	if job.Status == domain.JobPending {
+		if _, err := r.db.ExecContext(ctx, `DELETE FROM chat_jobs WHERE expires_at <= now()`); err != nil {
+			return fmt.Errorf("error al eliminar los jobs caducados: %w", err)
+		}
+	}
+
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO chat_jobs (id, client, status, model, result, error, created_at, started_at, finished_at, expires_at, callback, result_ref, progress, error_type)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (id) DO UPDATE SET
+			status = EXCLUDED.status,
+			result = EXCLUDED.result,
+			error = EXCLUDED.error,
+			started_at = EXCLUDED.started_at,
+			finished_at = EXCLUDED.finished_at,
+			callback = EXCLUDED.callback,
+			result_ref = EXCLUDED.result_ref,
+			progress = EXCLUDED.progress,
+			error_type = EXCLUDED.error_type`,
+		job.ID, job.Client, string(job.Status), job.Model, result, job.Error,
+		job.CreatedAt, job.StartedAt, job.FinishedAt, job.ExpiresAt, callback, resultRef,
+		progress, job.ErrorType,
+	); err != nil {
+		return fmt.Errorf("error al guardar el job: %w", err)
+	}
+	return nil
+}
+
+// FindJob implementa domain.JobRepository
+func (r *JobRepository) FindJob(ctx context.Context, id string) (*domain.Job, error) {
+	job := &domain.Job{ID: id}
+	var status string
+	var result, callback, resultRef, progress []byte
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT client, status, model, result, error, created_at, started_at, finished_at, expires_at, callback, result_ref, progress, error_type
+		FROM chat_jobs WHERE id = $1 AND expires_at > now()`, id,
+	).Scan(
+		&job.Client,
+		&status,
+		&job.Model,
+		&result,
+		&job.Error,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+		&job.ExpiresAt,
+		&callback,
+		&resultRef,
+		&progress,
+		&job.ErrorType,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el job: %w", err)
+	}
+	job.Status = domain.JobStatus(status)
+
+	if result != nil {
+		job.Result = &domain.ChatResponse{}
+		if err := json.Unmarshal(result, job.Result); err != nil {
+			return nil, fmt.Errorf("job corrupto %s: %w", id, err)
+		}
+	}
+	if callback != nil {
+		job.Callback = &domain.JobCallback{}
+		if err := json.Unmarshal(callback, job.Callback); err != nil {
+			return nil, fmt.Errorf("job corrupto %s: %w", id, err)
+		}
+	}
+	if resultRef != nil {
+		job.ResultRef = &domain.JobResultRef{}
+		if err := json.Unmarshal(resultRef, job.ResultRef); err != nil {
+			return nil, fmt.Errorf("job corrupto %s: %w", id, err)
+		}
+	}
+	if progress != nil {
+		job.Progress = &domain.JobProgress{}
+		if err := json.Unmarshal(progress, job.Progress); err != nil {
+			return nil, fmt.Errorf("job corrupto %s: %w", id, err)
+		}
+	}
+	return job, nil
+}
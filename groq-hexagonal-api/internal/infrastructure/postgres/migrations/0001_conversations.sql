@@ -0,0 +1,38 @@
+-- Conversaciones y su historial de mensajes
+-- Los ajustes fijados (model, temperature, system_prompt) van en la conversación;
+-- los datos de cada turno del asistente (modelo, latencia, tokens) en el mensaje
+
+CREATE TABLE conversations (
+    id            TEXT PRIMARY KEY,
+    model         TEXT NOT NULL,
+    temperature   DOUBLE PRECISION,
+    system_prompt TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL,
+    updated_at    TIMESTAMPTZ NOT NULL,
+    deleted_at    TIMESTAMPTZ,
+    purge_at      TIMESTAMPTZ
+);
+
+-- La retención busca por purge_at: solo interesan las conversaciones borradas
+CREATE INDEX conversations_purge_at_idx ON conversations (purge_at) WHERE purge_at IS NOT NULL;
+
+CREATE TABLE conversation_messages (
+    conversation_id   TEXT NOT NULL REFERENCES conversations (id) ON DELETE CASCADE,
+    position          INTEGER NOT NULL,
+    role              TEXT NOT NULL,
+    content           TEXT NOT NULL,
+    tool_calls        JSONB,
+    tool_call_id      TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL,
+
+    -- Datos del turno (NULL salvo en las respuestas del asistente)
+    turn_model        TEXT,
+    latency_ms        BIGINT,
+    prompt_tokens     INTEGER,
+    completion_tokens INTEGER,
+    total_tokens      INTEGER,
+    finish_reason     TEXT,
+    cache_hit         BOOLEAN,
+
+    PRIMARY KEY (conversation_id, position)
+);
@@ -0,0 +1,12 @@
+-- Consumo de tokens por cliente y día (UTC), para las cuotas diarias
+-- No se purga: es el histórico de consumo para facturación y analítica
+
+CREATE TABLE token_usage (
+    client            TEXT NOT NULL,
+    day               DATE NOT NULL,
+    prompt_tokens     BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    total_tokens      BIGINT NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (client, day)
+);
@@ -0,0 +1,3 @@
+-- Coste estimado del consumo (USD, según los precios configurados al registrarlo)
+
+ALTER TABLE token_usage ADD COLUMN cost_usd DOUBLE PRECISION NOT NULL DEFAULT 0;
@@ -0,0 +1,18 @@
+-- Chats asíncronos (POST /api/v1/chat/async)
+-- La respuesta del modelo se guarda como JSON: solo se lee entera al sondear
+
+CREATE TABLE chat_jobs (
+    id          TEXT PRIMARY KEY,
+    client      TEXT NOT NULL,
+    status      TEXT NOT NULL,
+    model       TEXT NOT NULL DEFAULT '',
+    result      JSONB,
+    error       TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL,
+    started_at  TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    expires_at  TIMESTAMPTZ NOT NULL
+);
+
+-- La limpieza busca por expires_at
+CREATE INDEX chat_jobs_expires_at_idx ON chat_jobs (expires_at);
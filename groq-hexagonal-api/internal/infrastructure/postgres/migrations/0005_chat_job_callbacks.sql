@@ -0,0 +1,4 @@
+-- Callbacks de los chats asíncronos: URL y estado de la entrega
+-- NULL = el job no pidió callback
+
+ALTER TABLE chat_jobs ADD COLUMN callback JSONB;
@@ -0,0 +1,4 @@
+-- Respuestas de los jobs guardadas fuera de la tabla (JOB_RESULT_DIR)
+-- NULL = la respuesta está en result (o el job no ha terminado)
+
+ALTER TABLE chat_jobs ADD COLUMN result_ref JSONB;
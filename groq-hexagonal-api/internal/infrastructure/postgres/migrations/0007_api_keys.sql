@@ -0,0 +1,21 @@
+-- API keys de los clientes (POST /admin/keys)
+-- Solo se guarda el SHA-256 del secreto; previous_hash es el secreto anterior
+-- a la última rotación, válido hasta previous_expires_at
+
+CREATE TABLE api_keys (
+    id                  TEXT PRIMARY KEY,
+    name                TEXT NOT NULL,
+    prefix              TEXT NOT NULL,
+    hash                TEXT NOT NULL UNIQUE,
+    scopes              JSONB NOT NULL DEFAULT '[]',
+    tier                TEXT NOT NULL DEFAULT '',
+    created_at          TIMESTAMPTZ NOT NULL,
+    expires_at          TIMESTAMPTZ,
+    disabled_at         TIMESTAMPTZ,
+    rotated_at          TIMESTAMPTZ,
+    previous_hash       TEXT,
+    previous_expires_at TIMESTAMPTZ
+);
+
+-- Cada petición autenticada busca por el hash (el actual o el anterior)
+CREATE INDEX api_keys_previous_hash_idx ON api_keys (previous_hash) WHERE previous_hash IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Eventos de caducidad de las API keys ya emitidos (api_key.expiring y
+-- api_key.expired): NULL = todavía no se ha avisado
+
+ALTER TABLE api_keys ADD COLUMN expiring_notified_at TIMESTAMPTZ;
+ALTER TABLE api_keys ADD COLUMN expired_notified_at TIMESTAMPTZ;
@@ -0,0 +1,17 @@
+-- Consumo de cada tenant por mes (UTC), para los extractos mensuales
+-- Una fila por dimensión: kind = 'model' (name es el modelo) o 'conversation'
+-- (name es el ID de la conversación). No se purga, como token_usage
+
+CREATE TABLE tenant_usage (
+    tenant            TEXT NOT NULL,
+    month             TEXT NOT NULL,
+    kind              TEXT NOT NULL,
+    name              TEXT NOT NULL,
+    requests          BIGINT NOT NULL DEFAULT 0,
+    prompt_tokens     BIGINT NOT NULL DEFAULT 0,
+    completion_tokens BIGINT NOT NULL DEFAULT 0,
+    total_tokens      BIGINT NOT NULL DEFAULT 0,
+    cost_usd          DOUBLE PRECISION NOT NULL DEFAULT 0,
+
+    PRIMARY KEY (tenant, month, kind, name)
+);
@@ -0,0 +1,5 @@
+-- Presupuesto de las conversaciones (JSON de domain.ConversationBudget, NULL =
+-- sin límite) y coste estimado de cada turno, para sumar lo consumido
+
+ALTER TABLE conversations ADD COLUMN budget JSONB;
+ALTER TABLE conversation_messages ADD COLUMN cost_usd DOUBLE PRECISION;
@@ -0,0 +1,4 @@
+-- Resumen del historial de las conversaciones (JSON de
+-- domain.ConversationSummary, NULL = sin resumen)
+
+ALTER TABLE conversations ADD COLUMN summary JSONB;
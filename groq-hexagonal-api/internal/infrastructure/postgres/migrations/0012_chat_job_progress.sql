@@ -0,0 +1,5 @@
+-- Progreso de los jobs (JSON de domain.JobProgress, NULL = en cola) y tipo
+-- del fallo ('' = sin fallo)
+
+ALTER TABLE chat_jobs ADD COLUMN progress JSONB;
+ALTER TABLE chat_jobs ADD COLUMN error_type TEXT NOT NULL DEFAULT '';
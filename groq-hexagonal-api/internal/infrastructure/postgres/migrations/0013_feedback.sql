@@ -0,0 +1,18 @@
+-- Valoraciones de las respuestas (POST /api/v1/feedback)
+-- No se purgan, como token_usage: son los datos de la evaluación offline
+
+CREATE TABLE feedback (
+    id              TEXT PRIMARY KEY,
+    response_id     TEXT NOT NULL DEFAULT '',
+    conversation_id TEXT NOT NULL DEFAULT '',
+    rating          TEXT NOT NULL,
+    comment         TEXT NOT NULL DEFAULT '',
+    model           TEXT NOT NULL DEFAULT '',
+    persona         TEXT NOT NULL DEFAULT '',
+    tenant          TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL
+);
+
+-- Los resúmenes leen un periodo, de todos los tenants o de uno
+CREATE INDEX feedback_created_at_idx ON feedback (created_at);
+CREATE INDEX feedback_tenant_created_at_idx ON feedback (tenant, created_at);
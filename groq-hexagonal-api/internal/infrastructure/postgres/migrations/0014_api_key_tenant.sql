@@ -0,0 +1,4 @@
+-- Tenant al que está atada cada API key: con autenticación, el de todas sus
+-- peticiones ('' = el tenant por defecto)
+
+ALTER TABLE api_keys ADD COLUMN tenant TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// LECTURAS EN LA RÉPLICA Y FAILOVER
+// ============================================================================
+//
+// Las lecturas que toleran unos segundos de retraso (consumo, extractos,
+// resumen de valoraciones) van a la réplica en una transacción de solo
+// lectura. Las que preceden a una escritura (una conversación antes de
+// guardar el turno, una API key antes de rotarla) o que el cliente hace justo
+// después de escribir (el job recién creado) siguen en el primario: en la
+// réplica podrían no estar todavía.
+//
+// Si la conexión cae a mitad (failover, reinicio, la réplica se retrasa
+// demasiado y cancela la consulta), la lectura se repite en el primario
+// hasta ReadRetries veces: una transacción de solo lectura no cambia nada,
+// así que repetirla es seguro. Cualquier otro error se retorna sin más.
+// ============================================================================
+
+// readBackoff es la espera antes de cada reintento (se multiplica por el
+// número de intento): da tiempo a que el DSN apunte al nuevo primario
+const readBackoff = 100 * time.Millisecond
+
+// read ejecuta read en una transacción de solo lectura, en la réplica si la
+// hay, reintentando en el primario si la conexión falla
+func (d *DB) read(ctx context.Context, read func(tx *sql.Tx) error) error {
+	pool := d.DB
+	if d.replica != nil {
+		pool = d.replica
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := readOnly(ctx, pool, read)
+		if err == nil || attempt > d.readRetries || !isConnectionLost(err) || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * readBackoff):
+		}
+		pool = d.DB
+	}
+}
+
+// readOnly ejecuta read en una transacción de solo lectura de pool
+func readOnly(ctx context.Context, pool *sql.DB, read func(tx *sql.Tx) error) error {
+	tx, err := pool.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	// Rollback tras un Commit correcto no hace nada
+	defer tx.Rollback()
+
+	if err := read(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// isConnectionLost indica si err se debe a que el servidor dejó de estar
+// disponible (merece reintentar) y no a la consulta
+func isConnectionLost(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Los errores del servidor traen su SQLSTATE (pgconn.PgError lo expone
+	// así, sin que este fichero dependa del driver)
+	var pgErr interface{ SQLState() string }
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	code := pgErr.SQLState()
+	switch {
+	case strings.HasPrefix(code, "08"):
+		// connection_exception y sus variantes
+		return true
+	case code == "57P01", code == "57P02", code == "57P03":
+		// admin_shutdown, crash_shutdown, cannot_connect_now
+		return true
+	case code == "40001":
+		// En una réplica: consulta cancelada por un conflicto con la replicación
+		return true
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// CONSUMO DE TOKENS EN POSTGRESQL
+// ============================================================================
+//
+// Una fila por cliente y día (token_usage). Cada petición suma sus tokens con
+// un upsert atómico: las réplicas pueden escribir a la vez sin perder
+// incrementos. Las filas no caducan: son el histórico de consumo.
+//
+// El extracto de cada tenant sale de tenant_usage: una fila por mes, modelo
+// y conversación, que suman con el mismo upsert.
+// ============================================================================
+
+// UsageRepository acumula el consumo de tokens en PostgreSQL
+// Implementa domain.UsageRepository
+type UsageRepository struct {
+	db *DB
+}
+
+// NewUsageRepository crea el repositorio sobre un pool ya abierto
+// El esquema debe existir (ver Migrate)
+func NewUsageRepository(db *DB) *UsageRepository {
+	if db == nil {
+		panic("db no puede ser nil")
+	}
+
+	return &UsageRepository{db: db}
+}
+
+// AddUsage implementa domain.UsageRepository
+func (r *UsageRepository) AddUsage(ctx context.Context, client string, day string, usage domain.Usage) error {
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO token_usage (client, day, prompt_tokens, completion_tokens, total_tokens, cost_usd)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (client, day) DO UPDATE SET
+			prompt_tokens = token_usage.prompt_tokens + EXCLUDED.prompt_tokens,
+			completion_tokens = token_usage.completion_tokens + EXCLUDED.completion_tokens,
+			total_tokens = token_usage.total_tokens + EXCLUDED.total_tokens,
+			cost_usd = token_usage.cost_usd + EXCLUDED.cost_usd`,
+		client, day, usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, usage.CostUSD,
+	); err != nil {
+		return fmt.Errorf("error al guardar el consumo: %w", err)
+	}
+	return nil
+}
+
+// GetUsage implementa domain.UsageRepository
+// Lee de la réplica, si la hay: la cuota puede ir unos segundos por detrás
+func (r *UsageRepository) GetUsage(ctx context.Context, client string, day string) (domain.Usage, error) {
+	var usage domain.Usage
+	err := r.db.read(ctx, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, `
+			SELECT prompt_tokens, completion_tokens, total_tokens, cost_usd
+			FROM token_usage WHERE client = $1 AND day = $2`, client, day,
+		).Scan(&usage.PromptTokens, &usage.CompletionTokens, &usage.TotalTokens, &usage.CostUSD)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// Día sin consumo
+		return domain.Usage{}, nil
+	}
+	if err != nil {
+		return domain.Usage{}, fmt.Errorf("error al leer el consumo: %w", err)
+	}
+	return usage, nil
+}
+
+// AddTenantUsage implementa domain.UsageRepository
+// La fila del modelo y la de la conversación se suman en la misma sentencia
+func (r *UsageRepository) AddTenantUsage(ctx context.Context, tenant string, month string, record domain.TenantUsageRecord) error {
+	query := `
+		INSERT INTO tenant_usage (tenant, month, kind, name, requests, prompt_tokens, completion_tokens, total_tokens, cost_usd)
+		VALUES ($1, $2, 'model', $3, 1, $4, $5, $6, $7)`
+	args := []interface{}{
+		tenant, month, record.Model,
+		record.Usage.PromptTokens, record.Usage.CompletionTokens, record.Usage.TotalTokens, record.Usage.CostUSD,
+	}
+	if record.ConversationID != "" {
+		query += `, ($1, $2, 'conversation', $8, 1, $4, $5, $6, $7)`
+		args = append(args, record.ConversationID)
+	}
+	query += `
+		ON CONFLICT (tenant, month, kind, name) DO UPDATE SET
+			requests = tenant_usage.requests + EXCLUDED.requests,
+			prompt_tokens = tenant_usage.prompt_tokens + EXCLUDED.prompt_tokens,
+			completion_tokens = tenant_usage.completion_tokens + EXCLUDED.completion_tokens,
+			total_tokens = tenant_usage.total_tokens + EXCLUDED.total_tokens,
+			cost_usd = tenant_usage.cost_usd + EXCLUDED.cost_usd`
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("error al guardar el consumo del tenant: %w", err)
+	}
+	return nil
+}
+
+// GetTenantUsage implementa domain.UsageRepository
+// Lee de la réplica, si la hay (como GetUsage)
+func (r *UsageRepository) GetTenantUsage(ctx context.Context, tenant string, month string) (*domain.TenantUsage, error) {
+	var result *domain.TenantUsage
+	err := r.db.read(ctx, func(tx *sql.Tx) error {
+		// Un reintento empieza de cero
+		result = &domain.TenantUsage{}
+
+		rows, err := tx.QueryContext(ctx, `
+			SELECT kind, name, requests, prompt_tokens, completion_tokens, total_tokens, cost_usd
+			FROM tenant_usage WHERE tenant = $1 AND month = $2`, tenant, month)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var kind, name string
+			var requests int64
+			var usage domain.Usage
+			if err := rows.Scan(&kind, &name, &requests, &usage.PromptTokens, &usage.CompletionTokens, &usage.TotalTokens, &usage.CostUSD); err != nil {
+				return err
+			}
+
+			switch kind {
+			case "model":
+				result.Models = append(result.Models, domain.ModelUsage{Model: name, Requests: requests, Usage: usage})
+			case "conversation":
+				result.Conversations = append(result.Conversations, domain.ConversationUsage{ConversationID: name, Requests: requests, Usage: usage})
+			}
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el consumo del tenant: %w", err)
+	}
+	return result, nil
+}
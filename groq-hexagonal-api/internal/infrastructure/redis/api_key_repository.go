@@ -0,0 +1,151 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"slices"
+)
+
+// ============================================================================
+// API KEYS EN REDIS
+// ============================================================================
+//
+// Dos hashes: "<prefijo>apikeys" (ID → clave en JSON) y "<prefijo>apikeys:hash"
+// (hash del secreto → ID), el índice con el que se autentica cada petición.
+// Al rotar, el índice guarda el hash nuevo y el anterior; los de rotaciones
+// previas se eliminan. Las claves no caducan en Redis: una clave caducada o
+// desactivada se conserva para el listado.
+// ============================================================================
+
+// APIKeyRepository guarda las API keys en Redis
+// Implementa domain.APIKeyRepository
+type APIKeyRepository struct {
+	client *Client
+	prefix string
+}
+
+// NewAPIKeyRepository crea el repositorio; prefix se antepone a las claves
+func NewAPIKeyRepository(client *Client, prefix string) *APIKeyRepository {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &APIKeyRepository{client: client, prefix: prefix}
+}
+
+// SaveAPIKey implementa domain.APIKeyRepository
+func (r *APIKeyRepository) SaveAPIKey(ctx context.Context, key *domain.APIKey) error {
+	data, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("error al serializar la API key: %w", err)
+	}
+
+	// Los hashes que la versión guardada tenía y esta ya no, salen del índice
+	var stale []string
+	if previous, err := r.FindAPIKey(ctx, key.ID); err == nil {
+		for _, hash := range []string{previous.Hash, previous.PreviousHash} {
+			if hash != "" && hash != key.Hash && hash != key.PreviousHash {
+				stale = append(stale, hash)
+			}
+		}
+	}
+
+	if _, err := r.client.Do(ctx, "HSET", r.keysKey(), key.ID, string(data)); err != nil {
+		return fmt.Errorf("error al guardar la API key: %w", err)
+	}
+	args := []string{"HSET", r.indexKey(), key.Hash, key.ID}
+	if key.PreviousHash != "" {
+		args = append(args, key.PreviousHash, key.ID)
+	}
+	if _, err := r.client.Do(ctx, args...); err != nil {
+		return fmt.Errorf("error al guardar la API key: %w", err)
+	}
+	if len(stale) > 0 {
+		if _, err := r.client.Do(ctx, append([]string{"HDEL", r.indexKey()}, stale...)...); err != nil {
+			return fmt.Errorf("error al guardar la API key: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindAPIKey implementa domain.APIKeyRepository
+func (r *APIKeyRepository) FindAPIKey(ctx context.Context, id string) (*domain.APIKey, error) {
+	reply, err := r.client.Do(ctx, "HGET", r.keysKey(), id)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la API key: %w", err)
+	}
+	data, ok := reply.(string)
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return decodeAPIKey(data)
+}
+
+// FindAPIKeyByHash implementa domain.APIKeyRepository
+func (r *APIKeyRepository) FindAPIKeyByHash(ctx context.Context, hash string) (*domain.APIKey, error) {
+	reply, err := r.client.Do(ctx, "HGET", r.indexKey(), hash)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la API key: %w", err)
+	}
+	id, ok := reply.(string)
+	if !ok {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+
+	key, err := r.FindAPIKey(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// El índice puede ir por detrás de la clave (otra réplica la está guardando)
+	if key.Hash != hash && key.PreviousHash != hash {
+		return nil, domain.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// ListAPIKeys implementa domain.APIKeyRepository
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	reply, err := r.client.Do(ctx, "HVALS", r.keysKey())
+	if err != nil {
+		return nil, fmt.Errorf("error al listar las API keys: %w", err)
+	}
+	values, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("respuesta inesperada al listar las API keys: %v", reply)
+	}
+
+	keys := make([]domain.APIKey, 0, len(values))
+	for _, value := range values {
+		data, _ := value.(string)
+		key, err := decodeAPIKey(data)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	slices.SortFunc(keys, func(a, b domain.APIKey) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return keys, nil
+}
+
+// decodeAPIKey lee una clave guardada en JSON
+func decodeAPIKey(data string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := json.Unmarshal([]byte(data), &key); err != nil {
+		return nil, fmt.Errorf("API key corrupta: %w", err)
+	}
+	return &key, nil
+}
+
+// keysKey es el hash de las claves por ID
+func (r *APIKeyRepository) keysKey() string {
+	return r.prefix + "apikeys"
+}
+
+// indexKey es el hash de los IDs por hash del secreto
+func (r *APIKeyRepository) indexKey() string {
+	return r.prefix + "apikeys:hash"
+}
@@ -0,0 +1,291 @@
+// Package redis implementa adaptadores secundarios sobre Redis
+// Comparten estado entre réplicas: conversaciones y contadores de rate limit
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// CLIENTE REDIS (RESP)
+// ============================================================================
+//
+// Los adaptadores solo necesitan unos pocos comandos (GET, SET, EVAL...), así
+// que en lugar de una dependencia externa se usa un cliente mínimo del
+// protocolo RESP sobre net.Conn:
+//
+//   petición:  *3\r\n$3\r\nSET\r\n$3\r\nkey\r\n$5\r\nvalue\r\n
+//   respuesta: +OK\r\n  (simple) | :1\r\n (entero) | $5\r\nvalue\r\n (bulk)
+//              *2\r\n... (array) | -ERR ...\r\n (error) | $-1\r\n (nil)
+//
+// Las conexiones se reutilizan con un pool (un canal con capacidad fija).
+// ============================================================================
+
+const (
+	// poolSize es el máximo de conexiones inactivas que se guardan
+	poolSize = 16
+
+	// defaultTimeout limita cada comando si el contexto no tiene deadline
+	defaultTimeout = 5 * time.Second
+)
+
+// Error es un error devuelto por el servidor Redis (ej: "WRONGTYPE ...")
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+// Client es un cliente Redis seguro para usar desde varias goroutines
+type Client struct {
+	address  string
+	username string
+	password string
+	db       int
+	useTLS   bool
+
+	// pool guarda las conexiones inactivas
+	pool chan *conn
+}
+
+// conn es una conexión con su buffer de lectura
+type conn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// NewClient crea el cliente a partir de una URL:
+//
+//	redis://[usuario:contraseña@]host:puerto[/db]
+//	rediss://...  (TLS)
+//
+// No abre ninguna conexión: usa Ping para comprobar que el servidor responde
+func NewClient(rawURL string) (*Client, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("REDIS_URL inválida: %w", err)
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, fmt.Errorf("REDIS_URL inválida: el esquema debe ser redis:// o rediss://")
+	}
+
+	client := &Client{
+		address: u.Host,
+		useTLS:  u.Scheme == "rediss",
+		pool:    make(chan *conn, poolSize),
+	}
+	if u.Port() == "" {
+		client.address = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		// redis://:contraseña@host (sin usuario) usa el AUTH clásico
+		client.username = u.User.Username()
+		client.password, _ = u.User.Password()
+	}
+	if path := strings.TrimPrefix(u.Path, "/"); path != "" {
+		if client.db, err = strconv.Atoi(path); err != nil {
+			return nil, fmt.Errorf("REDIS_URL inválida: base de datos %q", path)
+		}
+	}
+	return client, nil
+}
+
+// Ping comprueba que el servidor responde
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.Do(ctx, "PING")
+	return err
+}
+
+// Do ejecuta un comando y retorna la respuesta:
+// string, int64, nil, []interface{} o un error (Error si lo envía el servidor)
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cn.SetDeadline(deadline(ctx)); err != nil {
+		cn.Close()
+		return nil, err
+	}
+
+	reply, err := cn.do(args)
+	if err != nil {
+		var redisErr Error
+		if !errors.As(err, &redisErr) {
+			// Fallo de red o de protocolo: la conexión queda en un estado
+			// desconocido y no se devuelve al pool
+			cn.Close()
+			return nil, err
+		}
+	}
+	c.put(cn)
+	return reply, err
+}
+
+// Close cierra las conexiones inactivas
+func (c *Client) Close() error {
+	for {
+		select {
+		case cn := <-c.pool:
+			cn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// get toma una conexión del pool o abre una nueva
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case cn := <-c.pool:
+		return cn, nil
+	default:
+	}
+
+	dialer := &net.Dialer{Timeout: defaultTimeout}
+	var netConn net.Conn
+	var err error
+	if c.useTLS {
+		tlsDialer := &tls.Dialer{NetDialer: dialer}
+		netConn, err = tlsDialer.DialContext(ctx, "tcp", c.address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", c.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error al conectar con Redis: %w", err)
+	}
+
+	cn := &conn{Conn: netConn, reader: bufio.NewReader(netConn)}
+	if err := c.handshake(ctx, cn); err != nil {
+		cn.Close()
+		return nil, err
+	}
+	return cn, nil
+}
+
+// handshake autentica la conexión y selecciona la base de datos
+func (c *Client) handshake(ctx context.Context, cn *conn) error {
+	if err := cn.SetDeadline(deadline(ctx)); err != nil {
+		return err
+	}
+
+	if c.password != "" {
+		args := []string{"AUTH", c.password}
+		if c.username != "" {
+			args = []string{"AUTH", c.username, c.password}
+		}
+		if _, err := cn.do(args); err != nil {
+			return fmt.Errorf("error al autenticar en Redis: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.do([]string{"SELECT", strconv.Itoa(c.db)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put devuelve la conexión al pool (o la cierra si está lleno)
+func (c *Client) put(cn *conn) {
+	select {
+	case c.pool <- cn:
+	default:
+		cn.Close()
+	}
+}
+
+// deadline es el límite de la operación: el del contexto o el por defecto
+func deadline(ctx context.Context) time.Time {
+	if d, ok := ctx.Deadline(); ok {
+		return d
+	}
+	return time.Now().Add(defaultTimeout)
+}
+
+// ============================================================================
+// PROTOCOLO RESP
+// ============================================================================
+
+// do envía un comando y lee su respuesta
+func (cn *conn) do(args []string) (interface{}, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(cn, b.String()); err != nil {
+		return nil, err
+	}
+	return cn.readReply()
+}
+
+// readReply lee una respuesta (recursivo para los arrays)
+func (cn *conn) readReply() (interface{}, error) {
+	line, err := cn.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: respuesta vacía")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if size < 0 {
+			return nil, nil
+		}
+		// El bulk string va seguido de \r\n
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(cn.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:size]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			// Un error dentro de un array (ej: en EXEC) no invalida el resto
+			item, err := cn.readReply()
+			var redisErr Error
+			if err != nil && !errors.As(err, &redisErr) {
+				return nil, err
+			}
+			if err != nil {
+				item = redisErr
+			}
+			items[i] = item
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: respuesta desconocida %q", line)
+	}
+}
@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// REPOSITORIO DE CONVERSACIONES EN REDIS
+// ============================================================================
+//
+// Cada conversación es un valor JSON en la clave "<prefijo>conversation:<id>".
+// Las borradas se apuntan además en un sorted set ordenado por PurgeAt, para
+// que la retención encuentre las caducadas sin recorrer todas las claves.
+//
+// Las escrituras usan scripts Lua (EVAL): Redis los ejecuta de forma atómica,
+// así que dos réplicas nunca ven el valor y el índice a medio actualizar.
+// ============================================================================
+
+// saveScript guarda la conversación y actualiza el índice de purga
+// (las conversaciones activas no están en él, ej: tras Restore)
+// KEYS = conversación, sorted set de purga; ARGV = JSON, id, PurgeAt en Unix ms ("" = activa)
+const saveScript = `
+redis.call('SET', KEYS[1], ARGV[1])
+if ARGV[3] == '' then
+  redis.call('ZREM', KEYS[2], ARGV[2])
+else
+  redis.call('ZADD', KEYS[2], ARGV[3], ARGV[2])
+end
+return 1`
+
+// purgeScript elimina de forma atómica las conversaciones caducadas
+// KEYS[1] = sorted set de purga, ARGV[1] = ahora (Unix ms), ARGV[2] = prefijo
+const purgeScript = `
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for _, id in ipairs(ids) do
+  redis.call('DEL', ARGV[2] .. id)
+  redis.call('ZREM', KEYS[1], id)
+end
+return #ids`
+
+// ConversationRepository guarda las conversaciones en Redis
+// Implementa domain.ConversationRepository
+type ConversationRepository struct {
+	client *Client
+
+	// prefix separa las claves de esta aplicación de las de otras
+	prefix string
+}
+
+// NewConversationRepository crea el repositorio
+// prefix se antepone a todas las claves (ej: "groq:")
+func NewConversationRepository(client *Client, prefix string) *ConversationRepository {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &ConversationRepository{client: client, prefix: prefix}
+}
+
+// Save crea o reemplaza la conversación
+// El valor y el índice de purga se actualizan juntos (saveScript)
+func (r *ConversationRepository) Save(ctx context.Context, conversation *domain.Conversation) error {
+	data, err := json.Marshal(conversation)
+	if err != nil {
+		return fmt.Errorf("error al serializar la conversación: %w", err)
+	}
+
+	purgeAt := ""
+	if conversation.PurgeAt != nil {
+		purgeAt = strconv.FormatInt(conversation.PurgeAt.UnixMilli(), 10)
+	}
+
+	_, err = r.client.Do(ctx, "EVAL", saveScript, "2",
+		r.conversationKey(conversation.ID), r.purgeKey(),
+		string(data), conversation.ID, purgeAt)
+	if err != nil {
+		return fmt.Errorf("error al guardar la conversación: %w", err)
+	}
+	return nil
+}
+
+// FindByID retorna la conversación o domain.ErrConversationNotFound
+func (r *ConversationRepository) FindByID(ctx context.Context, id string) (*domain.Conversation, error) {
+	reply, err := r.client.Do(ctx, "GET", r.conversationKey(id))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer la conversación: %w", err)
+	}
+	data, ok := reply.(string)
+	if !ok {
+		// GET de una clave inexistente responde nil
+		return nil, domain.ErrConversationNotFound
+	}
+
+	var conversation domain.Conversation
+	if err := json.Unmarshal([]byte(data), &conversation); err != nil {
+		return nil, fmt.Errorf("conversación corrupta %s: %w", id, err)
+	}
+	return &conversation, nil
+}
+
+// PurgeDeleted elimina las conversaciones borradas cuyo plazo ha pasado
+func (r *ConversationRepository) PurgeDeleted(ctx context.Context, now time.Time) (int, error) {
+	reply, err := r.client.Do(ctx, "EVAL", purgeScript, "1", r.purgeKey(),
+		strconv.FormatInt(now.UnixMilli(), 10), r.conversationKey(""))
+	if err != nil {
+		return 0, fmt.Errorf("error al purgar conversaciones: %w", err)
+	}
+	purged, _ := reply.(int64)
+	return int(purged), nil
+}
+
+// conversationKey es la clave de una conversación
+func (r *ConversationRepository) conversationKey(id string) string {
+	return r.prefix + "conversation:" + id
+}
+
+// purgeKey es el sorted set con las conversaciones pendientes de purga
+func (r *ConversationRepository) purgeKey() string {
+	return r.prefix + "conversations:purge"
+}
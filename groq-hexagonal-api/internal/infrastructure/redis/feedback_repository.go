@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strconv"
+)
+
+// ============================================================================
+// VALORACIONES EN REDIS
+// ============================================================================
+//
+// Un sorted set "<prefijo>feedback" con cada valoración en JSON y su fecha
+// (Unix ms) como puntuación: un resumen es un ZRANGEBYSCORE desde Since, y
+// el filtro por tenant se aplica al leer. Al guardar se eliminan las que ya
+// no entran en ningún resumen (MaxFeedbackSummaryDays).
+// ============================================================================
+
+// saveFeedbackScript guarda la valoración y elimina las caducadas
+// KEYS[1] = sorted set; ARGV = fecha (Unix ms), JSON, límite de caducidad (Unix ms)
+const saveFeedbackScript = `
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[3])
+return 1`
+
+// FeedbackRepository guarda las valoraciones en Redis
+// Implementa domain.FeedbackRepository
+type FeedbackRepository struct {
+	client *Client
+	prefix string
+}
+
+// NewFeedbackRepository crea el repositorio; prefix se antepone a las claves
+func NewFeedbackRepository(client *Client, prefix string) *FeedbackRepository {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &FeedbackRepository{client: client, prefix: prefix}
+}
+
+// SaveFeedback implementa domain.FeedbackRepository
+func (r *FeedbackRepository) SaveFeedback(ctx context.Context, feedback *domain.Feedback) error {
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return fmt.Errorf("error al serializar la valoración: %w", err)
+	}
+
+	cutoff := feedback.CreatedAt.AddDate(0, 0, -domain.MaxFeedbackSummaryDays)
+	if _, err := r.client.Do(ctx, "EVAL", saveFeedbackScript, "1", r.key(),
+		strconv.FormatInt(feedback.CreatedAt.UnixMilli(), 10),
+		string(data),
+		strconv.FormatInt(cutoff.UnixMilli(), 10),
+	); err != nil {
+		return fmt.Errorf("error al guardar la valoración: %w", err)
+	}
+	return nil
+}
+
+// ListFeedback implementa domain.FeedbackRepository
+func (r *FeedbackRepository) ListFeedback(ctx context.Context, filter domain.FeedbackFilter) ([]domain.Feedback, error) {
+	reply, err := r.client.Do(ctx, "ZRANGEBYSCORE", r.key(), strconv.FormatInt(filter.Since.UnixMilli(), 10), "+inf")
+	if err != nil {
+		return nil, fmt.Errorf("error al leer las valoraciones: %w", err)
+	}
+	values, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("respuesta inesperada al leer las valoraciones: %v", reply)
+	}
+
+	var feedbacks []domain.Feedback
+	for _, value := range values {
+		data, _ := value.(string)
+		var feedback domain.Feedback
+		if err := json.Unmarshal([]byte(data), &feedback); err != nil {
+			return nil, fmt.Errorf("valoración corrupta: %w", err)
+		}
+		if filter.Matches(feedback) {
+			feedbacks = append(feedbacks, feedback)
+		}
+	}
+	return feedbacks, nil
+}
+
+// key es el sorted set de las valoraciones
+func (r *FeedbackRepository) key() string {
+	return r.prefix + "feedback"
+}
@@ -0,0 +1,98 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// IDEMPOTENCIA EN REDIS
+// ============================================================================
+//
+// Cada clave es un JSON con caducidad. La reserva es un script Lua: leer la
+// clave y crearla si no existe es atómico, así que de dos réplicas que
+// reciben el mismo reintento a la vez solo una llama al modelo.
+// ============================================================================
+
+// reserveScript retorna la entrada guardada o, si no hay, guarda ARGV[1]
+// durante ARGV[2] ms y retorna false (nil en Go)
+const reserveScript = `
+local existing = redis.call('GET', KEYS[1])
+if existing then
+	return existing
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return false
+`
+
+// IdempotencyStore guarda las respuestas de las peticiones idempotentes
+// Implementa domain.IdempotencyStore
+type IdempotencyStore struct {
+	client *Client
+	prefix string
+}
+
+// NewIdempotencyStore crea el almacén; prefix se antepone a las claves
+func NewIdempotencyStore(client *Client, prefix string) *IdempotencyStore {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &IdempotencyStore{client: client, prefix: prefix}
+}
+
+// Reserve implementa domain.IdempotencyStore
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, entry domain.IdempotentResponse, ttl time.Duration) (*domain.IdempotentResponse, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("error al serializar la reserva: %w", err)
+	}
+
+	reply, err := s.client.Do(ctx, "EVAL", reserveScript, "1", s.key(key),
+		string(data), strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return nil, fmt.Errorf("error al reservar la Idempotency-Key: %w", err)
+	}
+	existing, ok := reply.(string)
+	if !ok {
+		return nil, nil
+	}
+
+	var response domain.IdempotentResponse
+	if err := json.Unmarshal([]byte(existing), &response); err != nil {
+		return nil, fmt.Errorf("respuesta idempotente corrupta: %w", err)
+	}
+	return &response, nil
+}
+
+// Complete implementa domain.IdempotencyStore
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, entry domain.IdempotentResponse, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error al serializar la respuesta: %w", err)
+	}
+
+	_, err = s.client.Do(ctx, "SET", s.key(key), string(data),
+		"PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return fmt.Errorf("error al guardar la respuesta idempotente: %w", err)
+	}
+	return nil
+}
+
+// Release implementa domain.IdempotencyStore
+func (s *IdempotencyStore) Release(ctx context.Context, key string) error {
+	if _, err := s.client.Do(ctx, "DEL", s.key(key)); err != nil {
+		return fmt.Errorf("error al liberar la Idempotency-Key: %w", err)
+	}
+	return nil
+}
+
+// key retorna la clave de Redis de una petición
+func (s *IdempotencyStore) key(key string) string {
+	return s.prefix + "idempotency:" + key
+}
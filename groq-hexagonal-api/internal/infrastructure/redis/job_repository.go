@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// JOBS EN REDIS
+// ============================================================================
+//
+// Cada job es un valor JSON en la clave "<prefijo>job:<id>" que caduca en
+// ExpiresAt (SET ... PX): cualquier réplica puede responder al sondeo y no
+// hace falta ningún proceso de limpieza.
+// ============================================================================
+
+// JobRepository guarda los jobs en Redis
+// Implementa domain.JobRepository
+type JobRepository struct {
+	client *Client
+	prefix string
+}
+
+// NewJobRepository crea el repositorio; prefix se antepone a las claves
+func NewJobRepository(client *Client, prefix string) *JobRepository {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &JobRepository{client: client, prefix: prefix}
+}
+
+// SaveJob implementa domain.JobRepository
+func (r *JobRepository) SaveJob(ctx context.Context, job *domain.Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("error al serializar el job: %w", err)
+	}
+
+	// PX exige un tiempo positivo: un job ya caducado dura 1 ms
+	ttl := max(time.Until(job.ExpiresAt).Milliseconds(), 1)
+	if _, err := r.client.Do(ctx, "SET", r.key(job.ID), string(data), "PX", strconv.FormatInt(ttl, 10)); err != nil {
+		return fmt.Errorf("error al guardar el job: %w", err)
+	}
+	return nil
+}
+
+// FindJob implementa domain.JobRepository
+func (r *JobRepository) FindJob(ctx context.Context, id string) (*domain.Job, error) {
+	reply, err := r.client.Do(ctx, "GET", r.key(id))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el job: %w", err)
+	}
+	data, ok := reply.(string)
+	if !ok {
+		// GET de una clave inexistente (o caducada) responde nil
+		return nil, domain.ErrJobNotFound
+	}
+
+	var job domain.Job
+	if err := json.Unmarshal([]byte(data), &job); err != nil {
+		return nil, fmt.Errorf("job corrupto %s: %w", id, err)
+	}
+	return &job, nil
+}
+
+// key es la clave de un job
+func (r *JobRepository) key(id string) string {
+	return r.prefix + "job:" + id
+}
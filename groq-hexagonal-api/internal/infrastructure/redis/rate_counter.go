@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// CONTADOR DE RATE LIMIT EN REDIS
+// ============================================================================
+//
+// Ventana fija: la primera petición crea el contador con caducidad = ventana;
+// las siguientes lo incrementan. Como el contador vive en Redis, todas las
+// réplicas cuentan sobre el mismo valor y repartir las peticiones entre ellas
+// no permite saltarse el límite.
+// ============================================================================
+
+// incrementScript incrementa el contador y fija su caducidad en la primera petición
+// Retorna {peticiones en la ventana, ms hasta que se reinicia}
+const incrementScript = `
+local count = redis.call('INCR', KEYS[1])
+if count == 1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return {count, redis.call('PTTL', KEYS[1])}`
+
+// RateCounter cuenta peticiones por ventana en Redis
+// Implementa domain.RateCounter
+type RateCounter struct {
+	client *Client
+	prefix string
+}
+
+// NewRateCounter crea el contador; prefix se antepone a las claves
+func NewRateCounter(client *Client, prefix string) *RateCounter {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &RateCounter{client: client, prefix: prefix}
+}
+
+// Increment implementa domain.RateCounter
+func (c *RateCounter) Increment(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	reply, err := c.client.Do(ctx, "EVAL", incrementScript, "1",
+		c.prefix+"ratelimit:"+key, strconv.FormatInt(window.Milliseconds(), 10))
+	if err != nil {
+		return 0, 0, fmt.Errorf("error en el contador de rate limit: %w", err)
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return 0, 0, fmt.Errorf("respuesta inesperada del contador de rate limit: %v", reply)
+	}
+	count, _ := values[0].(int64)
+	ttl, _ := values[1].(int64)
+	return count, time.Duration(ttl) * time.Millisecond, nil
+}
@@ -0,0 +1,154 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ DE RESPUESTAS EN REDIS
+// ============================================================================
+//
+// Cada respuesta es una clave con caducidad (SET ... PX): Redis la elimina
+// sola y todas las réplicas comparten las respuestas guardadas.
+//
+// Len y Flush recorren las claves con SCAN (no KEYS): no bloquean Redis
+// aunque haya muchas, a cambio de no ser una foto exacta del momento.
+// ============================================================================
+
+// scanBatch es el número de claves que se piden a Redis en cada SCAN
+const scanBatch = "500"
+
+// ResponseCache guarda respuestas en Redis
+// Implementa domain.ResponseCache
+type ResponseCache struct {
+	client    *Client
+	prefix    string
+	namespace string
+}
+
+// NewResponseCache crea la caché; prefix se antepone a las claves
+func NewResponseCache(client *Client, prefix string) *ResponseCache {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &ResponseCache{client: client, prefix: prefix, namespace: "response:"}
+}
+
+// Get implementa domain.ResponseCache
+func (c *ResponseCache) Get(ctx context.Context, key string) (*domain.CachedResponse, error) {
+	data, ok, err := c.get(ctx, key)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var entry domain.CachedResponse
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("respuesta cacheada corrupta: %w", err)
+	}
+	return &entry, nil
+}
+
+// Set implementa domain.ResponseCache
+func (c *ResponseCache) Set(ctx context.Context, key string, entry domain.CachedResponse, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error al serializar la respuesta: %w", err)
+	}
+	return c.set(ctx, key, data, ttl)
+}
+
+// get retorna la respuesta serializada (false si no existe o caducó)
+func (c *ResponseCache) get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.client.Do(ctx, "GET", c.key(key))
+	if err != nil {
+		return nil, false, fmt.Errorf("error al leer la respuesta cacheada: %w", err)
+	}
+	data, ok := reply.(string)
+	if !ok {
+		// GET de una clave inexistente (o caducada) responde nil
+		return nil, false, nil
+	}
+	return []byte(data), true, nil
+}
+
+// set guarda la respuesta serializada durante ttl
+func (c *ResponseCache) set(ctx context.Context, key string, data []byte, ttl time.Duration) error {
+	_, err := c.client.Do(ctx, "SET", c.key(key), string(data),
+		"PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return fmt.Errorf("error al guardar la respuesta en la caché: %w", err)
+	}
+	return nil
+}
+
+// Len implementa domain.ResponseCache
+func (c *ResponseCache) Len(ctx context.Context) (int, error) {
+	count := 0
+	err := c.scan(ctx, func(keys []string) error {
+		count += len(keys)
+		return nil
+	})
+	return count, err
+}
+
+// Flush implementa domain.ResponseCache
+func (c *ResponseCache) Flush(ctx context.Context) (int, error) {
+	removed := 0
+	err := c.scan(ctx, func(keys []string) error {
+		reply, err := c.client.Do(ctx, append([]string{"DEL"}, keys...)...)
+		if err != nil {
+			return fmt.Errorf("error al borrar las respuestas cacheadas: %w", err)
+		}
+		// Una clave puede caducar entre el SCAN y el DEL: se cuenta lo borrado
+		if deleted, ok := reply.(int64); ok {
+			removed += int(deleted)
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// key retorna la clave de Redis de una respuesta
+func (c *ResponseCache) key(key string) string {
+	return c.prefix + c.namespace + key
+}
+
+// scan llama a fn con cada lote de claves de la caché
+func (c *ResponseCache) scan(ctx context.Context, fn func(keys []string) error) error {
+	cursor := "0"
+	for {
+		reply, err := c.client.Do(ctx, "SCAN", cursor, "MATCH", c.key("*"), "COUNT", scanBatch)
+		if err != nil {
+			return fmt.Errorf("error al recorrer la caché: %w", err)
+		}
+
+		// SCAN responde [cursor, [claves...]]; el cursor "0" indica el final
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return fmt.Errorf("respuesta inesperada de SCAN: %v", reply)
+		}
+		cursor, _ = parts[0].(string)
+		items, _ := parts[1].([]interface{})
+
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			if key, ok := item.(string); ok {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"time"
+)
+
+// ============================================================================
+// CACHÉ POR RUTA EN REDIS
+// ============================================================================
+//
+// Igual que ResponseCache (una clave con caducidad por respuesta) pero bajo
+// "route:", para no mezclarse con las respuestas del chat.
+// ============================================================================
+
+// RouteCache guarda respuestas HTTP en Redis
+// Implementa domain.RouteCache
+type RouteCache struct {
+	cache *ResponseCache
+}
+
+// NewRouteCache crea la caché; prefix se antepone a las claves
+func NewRouteCache(client *Client, prefix string) *RouteCache {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &RouteCache{cache: &ResponseCache{client: client, prefix: prefix, namespace: "route:"}}
+}
+
+// Get implementa domain.RouteCache
+func (c *RouteCache) Get(ctx context.Context, key string) (*domain.CachedRoute, error) {
+	data, ok, err := c.cache.get(ctx, key)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var entry domain.CachedRoute
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("respuesta cacheada corrupta: %w", err)
+	}
+	return &entry, nil
+}
+
+// Set implementa domain.RouteCache
+func (c *RouteCache) Set(ctx context.Context, key string, entry domain.CachedRoute, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("error al serializar la respuesta: %w", err)
+	}
+	return c.cache.set(ctx, key, data, ttl)
+}
@@ -0,0 +1,218 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// CONSUMO DE TOKENS EN REDIS
+// ============================================================================
+//
+// Un hash por cliente y día (campos prompt, completion, total y cost) que se
+// incrementa con HINCRBY y HINCRBYFLOAT: las réplicas suman sobre el mismo
+// valor, así que la cuota es la misma aunque las peticiones se repartan.
+// Cada hash caduca cuando su día sale del historial consultable.
+//
+// El extracto de cada tenant es otro hash por mes, con un campo por dimensión
+// y medida: "model:<modelo>:<medida>" y "conversation:<id>:<medida>" (medidas
+// requests, prompt, completion, total y cost). Caduca con el último mes
+// consultable.
+// ============================================================================
+
+// usageRetention es lo que se guarda cada día (el historial más el día actual)
+const usageRetention = (domain.MaxUsageHistoryDays + 1) * 24 * time.Hour
+
+// addUsageScript suma los tokens y fija la caducidad en el primer incremento
+const addUsageScript = `
+redis.call('HINCRBY', KEYS[1], 'prompt', ARGV[1])
+redis.call('HINCRBY', KEYS[1], 'completion', ARGV[2])
+local total = redis.call('HINCRBY', KEYS[1], 'total', ARGV[3])
+redis.call('HINCRBYFLOAT', KEYS[1], 'cost', ARGV[4])
+if redis.call('PTTL', KEYS[1]) < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[5])
+end
+return total`
+
+// tenantUsageRetention es lo que se guarda cada mes del extracto (los meses
+// consultables más el actual, contando meses de 31 días)
+const tenantUsageRetention = (domain.MaxStatementMonths + 1) * 31 * 24 * time.Hour
+
+// addTenantUsageScript suma la petición al modelo y, si hay, a la conversación
+// ARGV: modelo, conversación (vacía = ninguna), prompt, completion, total,
+// coste y caducidad en milisegundos
+const addTenantUsageScript = `
+local dimensions = {'model:' .. ARGV[1]}
+if ARGV[2] ~= '' then
+  table.insert(dimensions, 'conversation:' .. ARGV[2])
+end
+for _, dimension in ipairs(dimensions) do
+  redis.call('HINCRBY', KEYS[1], dimension .. ':requests', 1)
+  redis.call('HINCRBY', KEYS[1], dimension .. ':prompt', ARGV[3])
+  redis.call('HINCRBY', KEYS[1], dimension .. ':completion', ARGV[4])
+  redis.call('HINCRBY', KEYS[1], dimension .. ':total', ARGV[5])
+  redis.call('HINCRBYFLOAT', KEYS[1], dimension .. ':cost', ARGV[6])
+end
+if redis.call('PTTL', KEYS[1]) < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[7])
+end
+return 1`
+
+// UsageRepository acumula el consumo de tokens en Redis
+// Implementa domain.UsageRepository
+type UsageRepository struct {
+	client *Client
+	prefix string
+}
+
+// NewUsageRepository crea el repositorio; prefix se antepone a las claves
+func NewUsageRepository(client *Client, prefix string) *UsageRepository {
+	if client == nil {
+		panic("client no puede ser nil")
+	}
+
+	return &UsageRepository{client: client, prefix: prefix}
+}
+
+// AddUsage implementa domain.UsageRepository
+func (r *UsageRepository) AddUsage(ctx context.Context, client string, day string, usage domain.Usage) error {
+	_, err := r.client.Do(ctx, "EVAL", addUsageScript, "1", r.key(client, day),
+		strconv.Itoa(usage.PromptTokens),
+		strconv.Itoa(usage.CompletionTokens),
+		strconv.Itoa(usage.TotalTokens),
+		strconv.FormatFloat(usage.CostUSD, 'f', -1, 64),
+		strconv.FormatInt(usageRetention.Milliseconds(), 10))
+	if err != nil {
+		return fmt.Errorf("error al guardar el consumo: %w", err)
+	}
+	return nil
+}
+
+// GetUsage implementa domain.UsageRepository
+func (r *UsageRepository) GetUsage(ctx context.Context, client string, day string) (domain.Usage, error) {
+	reply, err := r.client.Do(ctx, "HMGET", r.key(client, day), "prompt", "completion", "total", "cost")
+	if err != nil {
+		return domain.Usage{}, fmt.Errorf("error al leer el consumo: %w", err)
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 4 {
+		return domain.Usage{}, fmt.Errorf("respuesta inesperada al leer el consumo: %v", reply)
+	}
+	// Los campos que no existen (día sin consumo) llegan como nil y cuentan 0
+	field := func(value interface{}) int {
+		text, _ := value.(string)
+		n, _ := strconv.Atoi(text)
+		return n
+	}
+	cost, _ := values[3].(string)
+	costUSD, _ := strconv.ParseFloat(cost, 64)
+	return domain.Usage{
+		PromptTokens:     field(values[0]),
+		CompletionTokens: field(values[1]),
+		TotalTokens:      field(values[2]),
+		CostUSD:          costUSD,
+	}, nil
+}
+
+// AddTenantUsage implementa domain.UsageRepository
+func (r *UsageRepository) AddTenantUsage(ctx context.Context, tenant string, month string, record domain.TenantUsageRecord) error {
+	_, err := r.client.Do(ctx, "EVAL", addTenantUsageScript, "1", r.tenantKey(tenant, month),
+		record.Model,
+		record.ConversationID,
+		strconv.Itoa(record.Usage.PromptTokens),
+		strconv.Itoa(record.Usage.CompletionTokens),
+		strconv.Itoa(record.Usage.TotalTokens),
+		strconv.FormatFloat(record.Usage.CostUSD, 'f', -1, 64),
+		strconv.FormatInt(tenantUsageRetention.Milliseconds(), 10))
+	if err != nil {
+		return fmt.Errorf("error al guardar el consumo del tenant: %w", err)
+	}
+	return nil
+}
+
+// GetTenantUsage implementa domain.UsageRepository
+func (r *UsageRepository) GetTenantUsage(ctx context.Context, tenant string, month string) (*domain.TenantUsage, error) {
+	reply, err := r.client.Do(ctx, "HGETALL", r.tenantKey(tenant, month))
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el consumo del tenant: %w", err)
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values)%2 != 0 {
+		return nil, fmt.Errorf("respuesta inesperada al leer el consumo del tenant: %v", reply)
+	}
+
+	// HGETALL retorna campo, valor, campo, valor...
+	models := make(map[string]*domain.ModelUsage)
+	conversations := make(map[string]*domain.ConversationUsage)
+	for i := 0; i < len(values); i += 2 {
+		field, _ := values[i].(string)
+		value, _ := values[i+1].(string)
+
+		// El nombre puede llevar ":" (ej: "ft:llama:org"): la dimensión va hasta
+		// el primero y la medida desde el último
+		dimension, rest, _ := strings.Cut(field, ":")
+		sep := strings.LastIndex(rest, ":")
+		if sep < 0 {
+			continue
+		}
+		name, measure := rest[:sep], rest[sep+1:]
+
+		switch dimension {
+		case "model":
+			model, ok := models[name]
+			if !ok {
+				model = &domain.ModelUsage{Model: name}
+				models[name] = model
+			}
+			setUsageMeasure(&model.Requests, &model.Usage, measure, value)
+		case "conversation":
+			conversation, ok := conversations[name]
+			if !ok {
+				conversation = &domain.ConversationUsage{ConversationID: name}
+				conversations[name] = conversation
+			}
+			setUsageMeasure(&conversation.Requests, &conversation.Usage, measure, value)
+		}
+	}
+
+	result := &domain.TenantUsage{}
+	for _, model := range models {
+		result.Models = append(result.Models, *model)
+	}
+	for _, conversation := range conversations {
+		result.Conversations = append(result.Conversations, *conversation)
+	}
+	return result, nil
+}
+
+// setUsageMeasure guarda una medida de un campo del extracto
+func setUsageMeasure(requests *int64, usage *domain.Usage, measure, value string) {
+	n, _ := strconv.Atoi(value)
+	switch measure {
+	case "requests":
+		*requests = int64(n)
+	case "prompt":
+		usage.PromptTokens = n
+	case "completion":
+		usage.CompletionTokens = n
+	case "total":
+		usage.TotalTokens = n
+	case "cost":
+		usage.CostUSD, _ = strconv.ParseFloat(value, 64)
+	}
+}
+
+// key es la clave del consumo de un cliente en un día
+func (r *UsageRepository) key(client, day string) string {
+	return r.prefix + "usage:" + client + ":" + day
+}
+
+// tenantKey es la clave del extracto de un tenant en un mes
+func (r *UsageRepository) tenantKey(tenant, month string) string {
+	return r.prefix + "statement:" + tenant + ":" + month
+}
@@ -0,0 +1,590 @@
+// Package sqlcheck analiza sentencias SQL (adaptador secundario)
+// Implementa domain.SQLAnalyzer sin dependencias externas
+package sqlcheck
+
+import (
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// ANÁLISIS DE SENTENCIAS
+// ============================================================================
+//
+// No es un parser completo de ningún dialecto: es un analizador léxico que
+// entiende lo necesario para decidir si una sentencia es segura de ejecutar:
+//
+//   - Comillas simples, identificadores entre comillas ("", ``, []),
+//     cadenas con dólar de PostgreSQL ($$...$$) y comentarios (-- y /* */):
+//     lo que hay dentro no cuenta como palabra clave
+//   - Exactamente una sentencia (los ";" separan sentencias) y los
+//     paréntesis equilibrados
+//   - Lista blanca de lecturas: SELECT, WITH, VALUES, TABLE, SHOW, EXPLAIN y
+//     DESCRIBE. Cualquier otra sentencia, o una lectura que contenga INSERT,
+//     UPDATE, DELETE, DDL, SELECT ... INTO o FOR UPDATE en cualquier punto
+//     (ej: un CTE que borra), se considera que modifica
+//   - Lista blanca de funciones: una lectura puede modificar a través de una
+//     función (SELECT setval(...), pg_terminate_backend(...), dblink_exec(...)
+//     o cualquier función del usuario), así que llamar a una que no esté en
+//     readOnlyFunctions también cuenta como que modifica. Con esquema, solo
+//     las de pg_catalog
+//
+// Las tablas se extraen de FROM, JOIN, INTO, UPDATE y TABLE, sin contar los
+// CTE ni las funciones de tabla.
+// ============================================================================
+
+// readStatements son las sentencias que no modifican nada por sí mismas
+var readStatements = map[string]bool{
+	"SELECT": true, "WITH": true, "VALUES": true, "TABLE": true,
+	"SHOW": true, "EXPLAIN": true, "DESCRIBE": true, "DESC": true,
+}
+
+// mutatingKeywords modifican datos, esquema o permisos en cualquier posición
+var mutatingKeywords = map[string]bool{
+	"INSERT": true, "UPDATE": true, "DELETE": true, "MERGE": true, "UPSERT": true,
+	"CREATE": true, "ALTER": true, "DROP": true, "TRUNCATE": true, "RENAME": true,
+	"GRANT": true, "REVOKE": true, "COPY": true, "CALL": true, "EXEC": true, "EXECUTE": true,
+	"ATTACH": true, "DETACH": true, "VACUUM": true, "REINDEX": true, "LOCK": true,
+}
+
+// readOnlyFunctions son las funciones (en minúsculas) que no modifican nada
+// ni actúan sobre el servidor: agregados, ventanas, cadenas, números, fechas,
+// JSON, arrays y las funciones de tabla habituales. Las familias pg_*, lo_*,
+// dblink*, nextval/setval/set_config... quedan fuera a propósito
+// Incluye los nombres que van seguidos de "(" sin ser funciones: tipos con
+// precisión (numeric(10, 2)) y cláusulas (FILTER (WHERE ...), ROLLUP (...))
+var readOnlyFunctions = map[string]bool{
+	// Agregados y ventanas
+	"count": true, "sum": true, "avg": true, "min": true, "max": true,
+	"stddev": true, "stddev_pop": true, "stddev_samp": true, "variance": true,
+	"var_pop": true, "var_samp": true, "bool_and": true, "bool_or": true, "every": true,
+	"string_agg": true, "array_agg": true, "json_agg": true, "jsonb_agg": true,
+	"json_object_agg": true, "jsonb_object_agg": true, "group_concat": true,
+	"percentile_cont": true, "percentile_disc": true, "mode": true, "median": true,
+	"corr": true, "covar_pop": true, "covar_samp": true,
+	"row_number": true, "rank": true, "dense_rank": true, "percent_rank": true,
+	"cume_dist": true, "ntile": true, "lag": true, "lead": true,
+	"first_value": true, "last_value": true, "nth_value": true,
+
+	// Condicionales y conversiones
+	"coalesce": true, "nullif": true, "greatest": true, "least": true,
+	"ifnull": true, "nvl": true, "iif": true, "cast": true, "try_cast": true,
+	"convert": true, "typeof": true,
+
+	// Cadenas
+	"length": true, "char_length": true, "character_length": true, "octet_length": true,
+	"lower": true, "upper": true, "initcap": true, "trim": true, "ltrim": true, "rtrim": true,
+	"btrim": true, "substring": true, "substr": true, "left": true, "right": true,
+	"position": true, "strpos": true, "instr": true, "locate": true, "replace": true,
+	"translate": true, "overlay": true, "concat": true, "concat_ws": true, "format": true,
+	"lpad": true, "rpad": true, "repeat": true, "reverse": true, "split_part": true,
+	"regexp_replace": true, "regexp_matches": true, "regexp_match": true,
+	"regexp_split_to_array": true, "regexp_split_to_table": true, "regexp_like": true,
+	"md5": true, "to_char": true, "to_number": true, "quote_ident": true, "quote_literal": true,
+	"starts_with": true, "ascii": true, "chr": true,
+
+	// Números
+	"abs": true, "ceil": true, "ceiling": true, "floor": true, "round": true, "trunc": true,
+	"truncate": true, "mod": true, "power": true, "pow": true, "sqrt": true, "cbrt": true,
+	"exp": true, "ln": true, "log": true, "log10": true, "sign": true, "div": true,
+	"random": true, "width_bucket": true, "pi": true,
+
+	// Fechas
+	"now": true, "current_date": true, "current_time": true, "current_timestamp": true,
+	"localtime": true, "localtimestamp": true, "date_trunc": true, "date_part": true,
+	"extract": true, "age": true, "to_date": true, "to_timestamp": true, "make_date": true,
+	"make_time": true, "make_timestamp": true, "make_interval": true, "date": true,
+	"time": true, "timestamp": true, "year": true, "month": true, "day": true,
+	"hour": true, "minute": true, "second": true, "dayofweek": true, "week": true,
+	"datediff": true, "date_add": true, "date_sub": true, "date_format": true,
+	"strftime": true, "julianday": true, "datetime": true, "unixepoch": true,
+
+	// JSON y arrays
+	"json_build_object": true, "jsonb_build_object": true, "json_build_array": true,
+	"jsonb_build_array": true, "to_json": true, "to_jsonb": true, "row_to_json": true,
+	"json_extract_path": true, "jsonb_extract_path": true, "json_extract_path_text": true,
+	"jsonb_extract_path_text": true, "json_array_length": true, "jsonb_array_length": true,
+	"json_each": true, "jsonb_each": true, "json_each_text": true, "jsonb_each_text": true,
+	"json_array_elements": true, "jsonb_array_elements": true, "jsonb_path_query": true,
+	"json_object_keys": true, "jsonb_object_keys": true, "json_typeof": true, "jsonb_typeof": true,
+	"json_extract": true, "json_object": true, "json_array": true,
+	"array": true, "array_length": true, "array_position": true, "array_to_string": true,
+	"string_to_array": true, "cardinality": true, "unnest": true, "generate_series": true,
+	"row": true,
+
+	// Tipos con precisión y cláusulas con paréntesis
+	"numeric": true, "decimal": true, "varchar": true, "char": true, "character": true,
+	"varying": true, "float": true, "bit": true, "interval": true,
+	"filter": true, "within": true, "rollup": true, "cube": true, "sets": true,
+	"explain": true, "bernoulli": true,
+}
+
+// keywords son las palabras reservadas que importan para las tablas: tras
+// ellas no hay un alias, y antes de un "(" no hay una función
+var keywords = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "JOIN": true, "INNER": true, "LEFT": true,
+	"RIGHT": true, "FULL": true, "OUTER": true, "CROSS": true, "NATURAL": true, "ON": true,
+	"USING": true, "GROUP": true, "BY": true, "ORDER": true, "HAVING": true, "LIMIT": true,
+	"OFFSET": true, "FETCH": true, "UNION": true, "INTERSECT": true, "EXCEPT": true, "ALL": true,
+	"DISTINCT": true, "AS": true, "WITH": true, "RECURSIVE": true, "IN": true, "EXISTS": true,
+	"NOT": true, "AND": true, "OR": true, "IS": true, "NULL": true, "LIKE": true, "ILIKE": true,
+	"BETWEEN": true, "CASE": true, "WHEN": true, "THEN": true, "ELSE": true, "END": true,
+	"INTO": true, "VALUES": true, "SET": true, "RETURNING": true, "LATERAL": true, "WINDOW": true,
+	"OVER": true, "PARTITION": true, "FOR": true, "TABLE": true, "IF": true, "ANY": true,
+	"SOME": true, "ONLY": true, "INSERT": true, "UPDATE": true, "DELETE": true, "CREATE": true,
+	"DROP": true, "ALTER": true, "TRUNCATE": true, "MERGE": true, "MATERIALIZED": true,
+}
+
+// Analyzer analiza sentencias SQL con un analizador léxico propio
+type Analyzer struct{}
+
+// NewAnalyzer crea el analizador
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{}
+}
+
+// Analyze implementa domain.SQLAnalyzer
+func (a *Analyzer) Analyze(sql string) (*domain.SQLAnalysis, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+
+	statements := splitStatements(tokens)
+	switch {
+	case len(statements) == 0:
+		return nil, fmt.Errorf("la sentencia está vacía")
+	case len(statements) > 1:
+		return nil, fmt.Errorf("hay %d sentencias: solo se admite una", len(statements))
+	}
+	statement := statements[0]
+
+	if err := checkParens(statement); err != nil {
+		return nil, err
+	}
+
+	analysis := classify(statement)
+	analysis.Tables = collectTables(statement)
+	return analysis, nil
+}
+
+// ============================================================================
+// ANALIZADOR LÉXICO
+// ============================================================================
+
+// tokenKind es el tipo de un token
+type tokenKind int
+
+const (
+	tokenWord   tokenKind = iota // Palabra clave o identificador sin comillas
+	tokenQuoted                  // Identificador entre comillas
+	tokenString                  // Literal de cadena
+	tokenNumber                  // Literal numérico
+	tokenSymbol                  // Cualquier otro carácter
+)
+
+// token es una unidad léxica de la sentencia
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// is indica si el token es la palabra clave o el símbolo indicado
+func (t token) is(text string) bool {
+	return (t.kind == tokenWord || t.kind == tokenSymbol) && strings.EqualFold(t.text, text)
+}
+
+// isIdentifier indica si el token puede ser un nombre (tabla, alias...)
+func (t token) isIdentifier() bool {
+	return t.kind == tokenQuoted || (t.kind == tokenWord && !keywords[strings.ToUpper(t.text)])
+}
+
+// tokenize parte la sentencia en tokens, sin comentarios ni espacios
+func tokenize(sql string) ([]token, error) {
+	runes := []rune(sql)
+	var tokens []token
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '-' && next == '-':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '/' && next == '*':
+			end := indexFrom(runes, i+2, "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("comentario sin cerrar")
+			}
+			i = end + 2
+		case r == '\'':
+			end, err := closeQuote(runes, i, '\'')
+			if err != nil {
+				return nil, fmt.Errorf("cadena sin cerrar")
+			}
+			tokens = append(tokens, token{tokenString, string(runes[i+1 : end])})
+			i = end + 1
+		case r == '"' || r == '`':
+			end, err := closeQuote(runes, i, r)
+			if err != nil {
+				return nil, fmt.Errorf("identificador sin cerrar")
+			}
+			tokens = append(tokens, token{tokenQuoted, string(runes[i+1 : end])})
+			i = end + 1
+		case r == '[':
+			end := indexFrom(runes, i+1, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("identificador sin cerrar")
+			}
+			tokens = append(tokens, token{tokenQuoted, string(runes[i+1 : end])})
+			i = end + 1
+		case r == '$' && (next == '$' || unicode.IsLetter(next) || next == '_'):
+			// Cadena con dólar: $$...$$ o $etiqueta$...$etiqueta$
+			tagEnd := indexFrom(runes, i+1, "$")
+			if tagEnd < 0 {
+				tokens = append(tokens, token{tokenSymbol, "$"})
+				i++
+				continue
+			}
+			tag := string(runes[i : tagEnd+1])
+			end := indexFrom(runes, tagEnd+1, tag)
+			if end < 0 {
+				return nil, fmt.Errorf("cadena sin cerrar")
+			}
+			tokens = append(tokens, token{tokenString, string(runes[tagEnd+1 : end])})
+			i = end + len([]rune(tag))
+		case unicode.IsLetter(r) || r == '_':
+			j := i + 1
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '$') {
+				j++
+			}
+			tokens = append(tokens, token{tokenWord, string(runes[i:j])})
+			i = j
+		case unicode.IsDigit(r):
+			j := i + 1
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[i:j])})
+			i = j
+		default:
+			tokens = append(tokens, token{tokenSymbol, string(r)})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// closeQuote busca la comilla que cierra la abierta en start
+// Dos comillas seguidas son una comilla escapada, no el cierre
+func closeQuote(runes []rune, start int, quote rune) (int, error) {
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] != quote {
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == quote {
+			i++
+			continue
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("sin cerrar")
+}
+
+// indexFrom busca substr en runes a partir de start (-1 si no está)
+func indexFrom(runes []rune, start int, substr string) int {
+	if start > len(runes) {
+		return -1
+	}
+	index := strings.Index(string(runes[start:]), substr)
+	if index < 0 {
+		return -1
+	}
+	return start + len([]rune(string(runes[start:])[:index]))
+}
+
+// splitStatements separa las sentencias por ";" (las vacías se descartan)
+func splitStatements(tokens []token) [][]token {
+	var statements [][]token
+	start := 0
+	for i, t := range tokens {
+		if t.is(";") {
+			if i > start {
+				statements = append(statements, tokens[start:i])
+			}
+			start = i + 1
+		}
+	}
+	if start < len(tokens) {
+		statements = append(statements, tokens[start:])
+	}
+	return statements
+}
+
+// checkParens comprueba que los paréntesis están equilibrados
+func checkParens(tokens []token) error {
+	depth := 0
+	for _, t := range tokens {
+		switch {
+		case t.is("("):
+			depth++
+		case t.is(")"):
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("paréntesis sin abrir")
+			}
+		}
+	}
+	if depth > 0 {
+		return fmt.Errorf("paréntesis sin cerrar")
+	}
+	return nil
+}
+
+// ============================================================================
+// TIPO DE SENTENCIA
+// ============================================================================
+
+// classify decide el tipo de la sentencia y si modifica algo
+func classify(tokens []token) *domain.SQLAnalysis {
+	ctes := collectCTEs(tokens)
+
+	// La primera palabra es la operación (puede ir tras "(": (SELECT ...) UNION ...)
+	first := ""
+	for _, t := range tokens {
+		if t.kind == tokenWord {
+			first = strings.ToUpper(t.text)
+			break
+		}
+	}
+
+	analysis := &domain.SQLAnalysis{StatementType: strings.ToLower(first)}
+	if first == "WITH" {
+		analysis.StatementType = "select"
+	}
+	if !readStatements[first] {
+		// Fuera de la lista blanca (SET, BEGIN, DO, PRAGMA...): se trata como
+		// escritura aunque no tenga ninguna palabra clave de escritura
+		analysis.Mutating = true
+	}
+
+	for i, t := range tokens {
+		if isFunctionCall(tokens, i, ctes) && !readOnlyFunction(tokens, i) {
+			// Una función que no está en la lista blanca puede modificar
+			analysis.Mutating = true
+		}
+		if t.kind != tokenWord {
+			continue
+		}
+		word := strings.ToUpper(t.text)
+		switch {
+		case word == "UPDATE" && i > 0 && tokens[i-1].is("FOR"):
+			// SELECT ... FOR UPDATE bloquea filas
+			analysis.Mutating = true
+		case mutatingKeywords[word]:
+			if !analysis.Mutating || readStatements[first] {
+				analysis.StatementType = strings.ToLower(word)
+			}
+			analysis.Mutating = true
+			return analysis
+		case word == "INTO" && readStatements[first]:
+			// SELECT ... INTO crea una tabla (o un fichero en MySQL)
+			analysis.Mutating = true
+		}
+	}
+	return analysis
+}
+
+// isFunctionCall indica si el token i es el nombre de una función llamada
+// (un nombre seguido de "("), descartando los que no lo son: la tabla de
+// INSERT INTO t (...), la lista de columnas de un CTE (WITH c (a, b) AS ...)
+// y la de un alias (FROM (VALUES ...) AS v (a, b))
+func isFunctionCall(tokens []token, i int, ctes map[string]bool) bool {
+	if !tokens[i].isIdentifier() || i+1 >= len(tokens) || !tokens[i+1].is("(") {
+		return false
+	}
+	if ctes[strings.ToLower(tokens[i].text)] {
+		return false
+	}
+	if i == 0 {
+		return true
+	}
+	previous := tokens[i-1]
+	return !(previous.is("INTO") || previous.is("TABLE") || previous.is("AS") || previous.is(")"))
+}
+
+// readOnlyFunction indica si la función del token i está en la lista blanca
+// Con esquema (esquema.función) solo se admiten las de pg_catalog: una
+// función del usuario con el mismo nombre puede hacer cualquier cosa
+func readOnlyFunction(tokens []token, i int) bool {
+	if i >= 2 && tokens[i-1].is(".") && !strings.EqualFold(tokens[i-2].text, "pg_catalog") {
+		return false
+	}
+	return readOnlyFunctions[strings.ToLower(tokens[i].text)]
+}
+
+// ============================================================================
+// TABLAS
+// ============================================================================
+
+// collectTables retorna las tablas que usa la sentencia, sin repetir
+func collectTables(tokens []token) []string {
+	ctes := collectCTEs(tokens)
+	seen := make(map[string]bool)
+	tables := []string{}
+	add := func(name string) {
+		key := strings.ToLower(name)
+		if ctes[key] || seen[key] {
+			return
+		}
+		seen[key] = true
+		tables = append(tables, name)
+	}
+
+	// functionParens indica, por nivel de paréntesis, si es una llamada a
+	// función: EXTRACT(YEAR FROM fecha) no lee ninguna tabla "fecha"
+	var functionParens []bool
+	inFunction := func() bool {
+		return len(functionParens) > 0 && functionParens[len(functionParens)-1]
+	}
+
+	for i, t := range tokens {
+		switch {
+		case t.is("("):
+			functionParens = append(functionParens, i > 0 && tokens[i-1].isIdentifier())
+			continue
+		case t.is(")"):
+			if len(functionParens) > 0 {
+				functionParens = functionParens[:len(functionParens)-1]
+			}
+			continue
+		case t.kind != tokenWord || inFunction():
+			continue
+		}
+
+		switch keyword := strings.ToUpper(t.text); keyword {
+		case "FROM", "JOIN", "INTO", "UPDATE", "TABLE":
+			if keyword == "UPDATE" && i > 0 && tokens[i-1].is("FOR") {
+				continue
+			}
+			for _, name := range tablesAfter(tokens, i+1, keyword == "FROM", keyword == "FROM" || keyword == "JOIN") {
+				add(name)
+			}
+		}
+	}
+	return tables
+}
+
+// tablesAfter lee los nombres de tabla que empiezan en start
+// list admite varias separadas por comas (FROM a, b); skipFunctions descarta
+// las funciones de tabla (FROM generate_series(1, 10))
+func tablesAfter(tokens []token, start int, list bool, skipFunctions bool) []string {
+	var names []string
+	i := start
+
+	// DROP TABLE IF EXISTS t, CREATE TABLE IF NOT EXISTS t, FROM ONLY t
+	for i < len(tokens) && (tokens[i].is("IF") || tokens[i].is("NOT") || tokens[i].is("EXISTS") || tokens[i].is("ONLY")) {
+		i++
+	}
+
+	for {
+		name, next, ok := qualifiedName(tokens, i)
+		if !ok {
+			return names
+		}
+		i = next
+		if !(skipFunctions && i < len(tokens) && tokens[i].is("(")) {
+			names = append(names, name)
+		}
+		if !list {
+			return names
+		}
+
+		// Alias opcional: [AS] alias
+		if i < len(tokens) && tokens[i].is("AS") {
+			i++
+		}
+		if i < len(tokens) && tokens[i].isIdentifier() {
+			i++
+		}
+		if i >= len(tokens) || !tokens[i].is(",") {
+			return names
+		}
+		i++
+	}
+}
+
+// qualifiedName lee un nombre con esquema opcional (esquema.tabla)
+func qualifiedName(tokens []token, start int) (string, int, bool) {
+	if start >= len(tokens) || !tokens[start].isIdentifier() {
+		return "", start, false
+	}
+	parts := []string{tokens[start].text}
+	i := start + 1
+	for i+1 < len(tokens) && tokens[i].is(".") && tokens[i+1].isIdentifier() {
+		parts = append(parts, tokens[i+1].text)
+		i += 2
+	}
+	return strings.Join(parts, "."), i, true
+}
+
+// collectCTEs retorna los nombres de los CTE (WITH nombre AS (...)), en minúsculas
+func collectCTEs(tokens []token) map[string]bool {
+	ctes := make(map[string]bool)
+	for i, t := range tokens {
+		if !t.is("WITH") {
+			continue
+		}
+		j := i + 1
+		if j < len(tokens) && tokens[j].is("RECURSIVE") {
+			j++
+		}
+		for j < len(tokens) && tokens[j].isIdentifier() {
+			ctes[strings.ToLower(tokens[j].text)] = true
+			j++
+
+			// Lista de columnas opcional, AS y [NOT] MATERIALIZED
+			if j < len(tokens) && tokens[j].is("(") {
+				j = skipParens(tokens, j)
+			}
+			for j < len(tokens) && (tokens[j].is("AS") || tokens[j].is("NOT") || tokens[j].is("MATERIALIZED")) {
+				j++
+			}
+			if j >= len(tokens) || !tokens[j].is("(") {
+				break
+			}
+			j = skipParens(tokens, j)
+			if j >= len(tokens) || !tokens[j].is(",") {
+				break
+			}
+			j++
+		}
+	}
+	return ctes
+}
+
+// skipParens retorna la posición siguiente al ")" que cierra el "(" de start
+func skipParens(tokens []token, start int) int {
+	depth := 0
+	for i := start; i < len(tokens); i++ {
+		switch {
+		case tokens[i].is("("):
+			depth++
+		case tokens[i].is(")"):
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(tokens)
+}
@@ -0,0 +1,132 @@
+package sqlcheck
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeClassifiesStatements(t *testing.T) {
+	tests := []struct {
+		name          string
+		sql           string
+		statementType string
+		mutating      bool
+	}{
+		// Lecturas
+		{"select", "SELECT id, name FROM users WHERE id = 1", "select", false},
+		{"values", "VALUES (1, 'a'), (2, 'b')", "values", false},
+		{"explain", "EXPLAIN (FORMAT JSON) SELECT * FROM users", "explain", false},
+		{"unión entre paréntesis", "(SELECT id FROM a) UNION (SELECT id FROM b)", "select", false},
+
+		// Escrituras y sentencias fuera de la lista blanca
+		{"insert", "INSERT INTO users (name) VALUES ('a')", "insert", true},
+		{"delete", "DELETE FROM users", "delete", true},
+		{"drop", "DROP TABLE IF EXISTS users", "drop", true},
+		{"set", "SET search_path = public", "set", true},
+		{"select into", "SELECT * INTO backup FROM users", "select", true},
+		{"for update", "SELECT * FROM users FOR UPDATE", "select", true},
+
+		// Comillas: lo de dentro no son palabras clave
+		{"cadena", "SELECT 'DROP TABLE users' AS texto FROM logs", "select", false},
+		{"comilla escapada", "SELECT 'it''s; DELETE FROM users' FROM logs", "select", false},
+		{"identificador", `SELECT "delete", "update" FROM "insert"`, "select", false},
+		{"identificador con acentos graves", "SELECT `drop` FROM `create`", "select", false},
+		{"identificador entre corchetes", "SELECT [truncate] FROM [alter]", "select", false},
+		{"cadena con dólar", "SELECT $$DELETE FROM users$$ FROM logs", "select", false},
+		{"cadena con dólar y etiqueta", "SELECT $x$; DROP TABLE users; $x$ FROM logs", "select", false},
+
+		// Comentarios: lo de dentro no cuenta
+		{"comentario de línea", "SELECT 1 -- ; DROP TABLE users", "select", false},
+		{"comentario de bloque", "SELECT /* DELETE FROM users; */ id FROM users", "select", false},
+
+		// CTE
+		{"cte de lectura", "WITH activos AS (SELECT * FROM users WHERE active) SELECT * FROM activos", "select", false},
+		{"cte con columnas", "WITH c (a, b) AS (SELECT 1, 2) SELECT a FROM c", "select", false},
+		{"cte que borra", "WITH d AS (DELETE FROM users RETURNING *) SELECT * FROM d", "delete", true},
+		{"cte que inserta", "WITH i AS (INSERT INTO logs (msg) VALUES ('x') RETURNING id) SELECT id FROM i", "insert", true},
+
+		// Funciones de la lista blanca
+		{"agregados", "SELECT count(*), sum(total), coalesce(max(total), 0) FROM orders", "select", false},
+		{"filtro de agregado", "SELECT count(*) FILTER (WHERE paid) FROM orders", "select", false},
+		{"fechas", "SELECT date_trunc('month', created_at), extract(YEAR FROM created_at) FROM orders", "select", false},
+		{"tipo con precisión", "SELECT CAST(total AS numeric(10, 2)), total::varchar(20) FROM orders", "select", false},
+		{"función de tabla", "SELECT n FROM generate_series(1, 10) AS n", "select", false},
+		{"alias con columnas", "SELECT a FROM (VALUES (1, 2)) AS v (a, b)", "select", false},
+		{"ventana", "SELECT row_number() OVER (PARTITION BY user_id ORDER BY id) FROM orders", "select", false},
+		{"pg_catalog", "SELECT pg_catalog.lower(name) FROM users", "select", false},
+
+		// Funciones con efectos (o desconocidas): modifican
+		{"pg_terminate_backend", "SELECT pg_terminate_backend(pid) FROM pg_stat_activity", "select", true},
+		{"setval", "SELECT setval('users_id_seq', 1)", "select", true},
+		{"nextval", "SELECT nextval('users_id_seq')", "select", true},
+		{"set_config", "SELECT set_config('search_path', 'x', false)", "select", true},
+		{"lo_unlink", "SELECT lo_unlink(16400)", "select", true},
+		{"dblink_exec", "SELECT dblink_exec('dbname=x', 'DROP TABLE users')", "select", true},
+		{"pg_read_file", "SELECT pg_read_file('/etc/passwd')", "select", true},
+		{"función del usuario", "SELECT purge_old_orders() FROM orders", "select", true},
+		{"función entre comillas", `SELECT "setval"('users_id_seq', 1)`, "select", true},
+		{"función con esquema", "SELECT app.lower(name) FROM users", "select", true},
+		{"función en el where", "SELECT * FROM users WHERE pg_sleep(10) IS NULL", "select", true},
+		{"función en un cte", "WITH x AS (SELECT setval('s', 1)) SELECT * FROM x", "select", true},
+	}
+
+	analyzer := NewAnalyzer()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := analyzer.Analyze(tt.sql)
+			if err != nil {
+				t.Fatalf("Analyze(%q): %v", tt.sql, err)
+			}
+			if analysis.StatementType != tt.statementType || analysis.Mutating != tt.mutating {
+				t.Errorf("Analyze(%q) = %s, mutating=%v; se esperaba %s, mutating=%v",
+					tt.sql, analysis.StatementType, analysis.Mutating, tt.statementType, tt.mutating)
+			}
+		})
+	}
+}
+
+func TestAnalyzeCollectsTables(t *testing.T) {
+	tests := []struct {
+		sql    string
+		tables []string
+	}{
+		{"SELECT * FROM users", []string{"users"}},
+		{"SELECT * FROM users u, orders AS o", []string{"users", "orders"}},
+		{"SELECT * FROM public.users u JOIN orders o ON o.user_id = u.id", []string{"public.users", "orders"}},
+		{"SELECT extract(YEAR FROM created_at) FROM orders", []string{"orders"}},
+		{"SELECT n FROM generate_series(1, 10) AS n", []string{}},
+		{"WITH activos AS (SELECT * FROM users) SELECT * FROM activos JOIN orders ON true", []string{"users", "orders"}},
+		{"INSERT INTO logs (msg) SELECT name FROM users", []string{"logs", "users"}},
+		{`SELECT * FROM "Users"`, []string{"Users"}},
+	}
+
+	analyzer := NewAnalyzer()
+	for _, tt := range tests {
+		analysis, err := analyzer.Analyze(tt.sql)
+		if err != nil {
+			t.Fatalf("Analyze(%q): %v", tt.sql, err)
+		}
+		if !reflect.DeepEqual(analysis.Tables, tt.tables) {
+			t.Errorf("Analyze(%q).Tables = %q; se esperaba %q", tt.sql, analysis.Tables, tt.tables)
+		}
+	}
+}
+
+func TestAnalyzeRejectsMalformed(t *testing.T) {
+	tests := map[string]string{
+		"vacía":                    "  -- nada\n",
+		"varias sentencias":        "SELECT 1; DROP TABLE users",
+		"paréntesis sin cerrar":    "SELECT count(* FROM users",
+		"paréntesis sin abrir":     "SELECT 1) FROM users",
+		"cadena sin cerrar":        "SELECT 'abc FROM users",
+		"comentario sin cerrar":    "SELECT 1 /* DROP TABLE users",
+		"identificador sin cerrar": `SELECT "abc FROM users`,
+	}
+
+	analyzer := NewAnalyzer()
+	for name, sql := range tests {
+		if _, err := analyzer.Analyze(sql); err == nil {
+			t.Errorf("%s: Analyze(%q) no retornó error", name, sql)
+		}
+	}
+}
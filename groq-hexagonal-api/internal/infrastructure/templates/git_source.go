@@ -0,0 +1,155 @@
+// Package templates implementa los orígenes de personas (adaptadores secundarios)
+package templates
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ============================================================================
+// PERSONAS DESDE UN REPOSITORIO GIT
+// ============================================================================
+//
+// GitSource mantiene una copia local del repositorio de plantillas: la primera
+// vez hace "git clone" y después "git pull" en cada Load(). El repositorio
+// tiene un fichero por persona (el nombre del fichero es el de la persona):
+//
+//   personas/
+//   ├── soporte.md        → solo el system prompt (texto plano)
+//   └── tutor-go.json     → {"system_prompt": "...", "model": "...", "temperature": 0.3}
+//
+// Requiere el binario git en el PATH. Para repositorios privados, las
+// credenciales van en la URL o en la configuración de git del sistema.
+// ============================================================================
+
+// GitSource carga las personas de un repositorio Git
+type GitSource struct {
+	// url y branch identifican el repositorio
+	url    string
+	branch string
+
+	// dir es el subdirectorio con las plantillas ("" = raíz del repositorio)
+	dir string
+
+	// checkout es la ruta local del clon
+	checkout string
+}
+
+// NewGitSource crea el origen de personas
+func NewGitSource(url, branch, dir, checkout string) *GitSource {
+	if url == "" {
+		panic("url no puede estar vacía")
+	}
+	if checkout == "" {
+		panic("checkout no puede estar vacío")
+	}
+	if branch == "" {
+		branch = "main"
+	}
+
+	return &GitSource{
+		url:      url,
+		branch:   branch,
+		dir:      dir,
+		checkout: checkout,
+	}
+}
+
+// Load implementa domain.PersonaSource
+func (s *GitSource) Load(ctx context.Context) (map[string]domain.Persona, error) {
+	if err := s.sync(ctx); err != nil {
+		return nil, err
+	}
+	return readPersonas(filepath.Join(s.checkout, s.dir))
+}
+
+// sync clona el repositorio o lo actualiza si ya existe
+func (s *GitSource) sync(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(s.checkout, ".git")); errors.Is(err, os.ErrNotExist) {
+		return runGit(ctx, "", "clone", "--depth", "1", "--branch", s.branch, s.url, s.checkout)
+	}
+
+	// fetch + reset en lugar de pull: la copia local nunca tiene cambios
+	// propios, y así un force-push en el repositorio no la deja bloqueada
+	if err := runGit(ctx, s.checkout, "fetch", "--depth", "1", "origin", s.branch); err != nil {
+		return err
+	}
+	return runGit(ctx, s.checkout, "reset", "--hard", "FETCH_HEAD")
+}
+
+// runGit ejecuta un comando git e incluye su salida en el error
+func runGit(ctx context.Context, dir string, args ...string) error {
+	// exec.CommandContext mata el proceso si el contexto se cancela
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// readPersonas lee un fichero por persona del directorio
+func readPersonas(dir string) (map[string]domain.Persona, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer las plantillas: %w", err)
+	}
+
+	personas := make(map[string]domain.Persona)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		extension := filepath.Ext(entry.Name())
+		name := strings.TrimSuffix(entry.Name(), extension)
+
+		var persona domain.Persona
+		switch extension {
+		case ".json", ".md", ".txt":
+			persona, err = readPersona(filepath.Join(dir, entry.Name()), extension)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			// README, LICENSE... no son plantillas
+			continue
+		}
+
+		persona.Name = name
+		personas[name] = persona
+	}
+	return personas, nil
+}
+
+// readPersona lee una plantilla (JSON completo o texto con el system prompt)
+func readPersona(path, extension string) (domain.Persona, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.Persona{}, fmt.Errorf("error al leer %s: %w", path, err)
+	}
+
+	if extension != ".json" {
+		return domain.Persona{SystemPrompt: strings.TrimSpace(string(data))}, nil
+	}
+
+	var persona domain.Persona
+	if err := json.Unmarshal(data, &persona); err != nil {
+		return domain.Persona{}, fmt.Errorf("plantilla inválida %s: %w", path, err)
+	}
+	if persona.SystemPrompt == "" {
+		return domain.Persona{}, fmt.Errorf("plantilla inválida %s: system_prompt es obligatorio", path)
+	}
+	return persona, nil
+}
@@ -0,0 +1,30 @@
+// Package usage implementa el registro del uso de tokens (adaptadores secundarios)
+package usage
+
+import (
+	"context"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+)
+
+// LogUsageRecorder escribe el uso de tokens en el log con formato clave=valor
+// (ej: sumar total_tokens de las líneas con event=usage por tenant)
+type LogUsageRecorder struct{}
+
+// NewLogUsageRecorder crea el recorder
+func NewLogUsageRecorder() *LogUsageRecorder {
+	return &LogUsageRecorder{}
+}
+
+// RecordUsage implementa domain.UsageRecorder
+func (r *LogUsageRecorder) RecordUsage(ctx context.Context, record domain.UsageRecord) {
+	log.Printf(
+		"event=usage source=%s tenant=%s model=%s prompt_tokens=%d completion_tokens=%d total_tokens=%d",
+		record.Source,
+		domain.TenantFromContext(ctx),
+		record.Model,
+		record.Usage.PromptTokens,
+		record.Usage.CompletionTokens,
+		record.Usage.TotalTokens,
+	)
+}
@@ -0,0 +1,94 @@
+// Package wasm carga hooks de peticiones y respuestas compilados a WebAssembly
+//
+// Los plugins se ejecutan con wazero (sin cgo) y solo se compilan con la
+// etiqueta "wasmplugins" (make build-wasm); sin ella, Load retorna ErrNotCompiled.
+package wasm
+
+import (
+	"context"
+	"errors"
+	"groq-hexagonal-api/internal/domain"
+)
+
+// ============================================================================
+// ABI DE LOS PLUGINS
+// ============================================================================
+//
+// Un plugin es un fichero .wasm en PLUGINS_DIR (módulo WASI, ej: compilado con
+// TinyGo o Rust) que exporta:
+//
+//   memory                       la memoria lineal del módulo
+//   alloc(size i32) i32          reserva size bytes y retorna su dirección
+//   on_request(ptr, len i32) i64   (opcional) hook de petición
+//   on_response(ptr, len i32) i64  (opcional) hook de respuesta
+//
+// El host escribe un JSON de entrada (requestInput o responseInput) en la
+// memoria reservada con alloc y llama al hook. El hook retorna la dirección y
+// la longitud de su JSON de salida empaquetadas en un i64 (ptr << 32 | len),
+// o 0 si no cambia nada.
+//
+// Cada llamada usa una instancia nueva del módulo: los plugins no guardan
+// estado entre peticiones y se pueden ejecutar en paralelo.
+// ============================================================================
+
+// ErrNotCompiled indica que el binario se compiló sin soporte WASM
+var ErrNotCompiled = errors.New("binario compilado sin soporte de plugins WASM (usa make build-wasm)")
+
+// Nombres de las funciones exportadas por los plugins
+const (
+	exportAlloc      = "alloc"
+	exportOnRequest  = "on_request"
+	exportOnResponse = "on_response"
+)
+
+// requestInput es la entrada de on_request
+type requestInput struct {
+	Request domain.ChatRequest    `json:"request"`
+	Tenant  string                `json:"tenant"`
+	Headers domain.RequestHeaders `json:"headers,omitempty"`
+}
+
+// requestOutput es la salida de on_request (los campos vacíos no cambian nada)
+type requestOutput struct {
+	// Request reemplaza a la petición (ej: con otro modelo)
+	Request *domain.ChatRequest `json:"request,omitempty"`
+
+	// Reject rechaza la petición con este mensaje (403 request_rejected)
+	Reject string `json:"reject,omitempty"`
+}
+
+// responseInput es la entrada de on_response
+type responseInput struct {
+	Request  domain.ChatRequest    `json:"request"`
+	Response domain.ChatResponse   `json:"response"`
+	Tenant   string                `json:"tenant"`
+	Headers  domain.RequestHeaders `json:"headers,omitempty"`
+}
+
+// responseOutput es la salida de on_response
+type responseOutput struct {
+	// Response reemplaza a la respuesta (sin efecto en streaming: el texto
+	// ya se ha enviado al cliente)
+	Response *domain.ChatResponse `json:"response,omitempty"`
+}
+
+// Plugins son los hooks cargados de PLUGINS_DIR
+type Plugins struct {
+	// Names son los ficheros cargados, en orden
+	Names []string
+
+	// RequestHooks y ResponseHooks se registran en application.HookRegistry
+	RequestHooks  []domain.RequestHook
+	ResponseHooks []domain.ResponseHook
+
+	// close libera el runtime de WebAssembly
+	close func(ctx context.Context) error
+}
+
+// Close libera los recursos de los plugins (llamar al apagar el servidor)
+func (p *Plugins) Close(ctx context.Context) error {
+	if p == nil || p.close == nil {
+		return nil
+	}
+	return p.close(ctx)
+}
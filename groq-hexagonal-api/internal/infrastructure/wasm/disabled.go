@@ -0,0 +1,14 @@
+//go:build !wasmplugins
+
+// Package wasm carga hooks de peticiones y respuestas compilados a WebAssembly
+//
+// Este fichero se compila cuando el binario NO incluye soporte WASM (la opción
+// por defecto, para no arrastrar wazero). Ver loader.go y `make build-wasm`.
+package wasm
+
+import "context"
+
+// Load no carga nada: sin la etiqueta de compilación "wasmplugins" no hay runtime
+func Load(ctx context.Context, dir string) (*Plugins, error) {
+	return nil, ErrNotCompiled
+}
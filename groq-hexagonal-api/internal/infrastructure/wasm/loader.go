@@ -0,0 +1,215 @@
+//go:build wasmplugins
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"groq-hexagonal-api/internal/domain"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// ============================================================================
+// CARGA Y EJECUCIÓN DE LOS PLUGINS
+// ============================================================================
+//
+// Los módulos se compilan una vez al arrancar; cada llamada instancia el
+// módulo compilado (barato) y lo cierra al terminar.
+//
+// Límites: cada llamada tiene callTimeout y cada instancia como mucho
+// memoryLimitPages de memoria. Un plugin que los supera o falla:
+//   - en on_request, hace fallar la petición (500)
+//   - en on_response, se registra en el log y la respuesta sigue sin cambios
+// ============================================================================
+
+const (
+	// callTimeout es el tiempo máximo de una llamada a un hook
+	callTimeout = 2 * time.Second
+
+	// memoryLimitPages limita la memoria de cada instancia (64 KiB por página)
+	memoryLimitPages = 256 // 16 MiB
+)
+
+// Load compila los plugins (*.wasm) del directorio, en orden de nombre
+func Load(ctx context.Context, dir string) (*Plugins, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.wasm"))
+	if err != nil {
+		return nil, fmt.Errorf("error al buscar plugins en %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	// WithCloseOnContextDone detiene un plugin colgado al vencer callTimeout
+	runtime := wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(memoryLimitPages))
+	wasi_snapshot_preview1.MustInstantiate(ctx, runtime)
+
+	plugins := &Plugins{close: runtime.Close}
+	for _, path := range paths {
+		plugin, err := compile(ctx, runtime, path)
+		if err != nil {
+			runtime.Close(ctx)
+			return nil, err
+		}
+
+		plugins.Names = append(plugins.Names, plugin.name)
+		if plugin.has(exportOnRequest) {
+			plugins.RequestHooks = append(plugins.RequestHooks, requestHook{plugin})
+		}
+		if plugin.has(exportOnResponse) {
+			plugins.ResponseHooks = append(plugins.ResponseHooks, responseHook{plugin})
+		}
+	}
+	return plugins, nil
+}
+
+// plugin es un módulo compilado
+type plugin struct {
+	name     string
+	runtime  wazero.Runtime
+	compiled wazero.CompiledModule
+}
+
+// compile compila un fichero .wasm y comprueba que exporta lo necesario
+func compile(ctx context.Context, runtime wazero.Runtime, path string) (*plugin, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer el plugin %s: %w", path, err)
+	}
+
+	compiled, err := runtime.CompileModule(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("error al compilar el plugin %s: %w", path, err)
+	}
+
+	p := &plugin{name: filepath.Base(path), runtime: runtime, compiled: compiled}
+	if !p.has(exportAlloc) {
+		return nil, fmt.Errorf("el plugin %s no exporta %q", p.name, exportAlloc)
+	}
+	if !p.has(exportOnRequest) && !p.has(exportOnResponse) {
+		return nil, fmt.Errorf("el plugin %s no exporta %q ni %q", p.name, exportOnRequest, exportOnResponse)
+	}
+	return p, nil
+}
+
+// has indica si el módulo exporta la función
+func (p *plugin) has(name string) bool {
+	_, ok := p.compiled.ExportedFunctions()[name]
+	return ok
+}
+
+// call ejecuta un hook con una instancia nueva del módulo
+// input y output se serializan como JSON; output no cambia si el hook retorna 0
+func (p *plugin) call(ctx context.Context, function string, input, output interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, callTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("error al serializar la entrada: %w", err)
+	}
+
+	// Nombre vacío: permite varias instancias del mismo módulo a la vez
+	// _initialize prepara los módulos "reactor" (ej: TinyGo); si no existe, se omite
+	module, err := p.runtime.InstantiateModule(ctx, p.compiled,
+		wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize"))
+	if err != nil {
+		return fmt.Errorf("error al instanciar: %w", err)
+	}
+	defer module.Close(ctx)
+
+	results, err := module.ExportedFunction(exportAlloc).Call(ctx, uint64(len(data)))
+	if err != nil {
+		return fmt.Errorf("error en %s: %w", exportAlloc, err)
+	}
+	ptr := uint32(results[0])
+	if !module.Memory().Write(ptr, data) {
+		return fmt.Errorf("%s retornó una dirección fuera de la memoria", exportAlloc)
+	}
+
+	results, err = module.ExportedFunction(function).Call(ctx, uint64(ptr), uint64(len(data)))
+	if err != nil {
+		return fmt.Errorf("error en %s: %w", function, err)
+	}
+	if results[0] == 0 {
+		return nil
+	}
+
+	// Resultado empaquetado: dirección en los 32 bits altos, longitud en los bajos
+	outPtr, outLen := uint32(results[0]>>32), uint32(results[0])
+	out, ok := module.Memory().Read(outPtr, outLen)
+	if !ok {
+		return fmt.Errorf("%s retornó un resultado fuera de la memoria", function)
+	}
+	if err := json.Unmarshal(out, output); err != nil {
+		return fmt.Errorf("%s retornó un JSON inválido: %w", function, err)
+	}
+	return nil
+}
+
+// ============================================================================
+// ADAPTADORES A LOS HOOKS DEL DOMINIO
+// ============================================================================
+
+// requestHook implementa domain.RequestHook con on_request
+type requestHook struct {
+	plugin *plugin
+}
+
+// BeforeRequest implementa domain.RequestHook
+func (h requestHook) BeforeRequest(ctx context.Context, request *domain.ChatRequest) error {
+	input := requestInput{
+		Request: *request,
+		Tenant:  domain.TenantFromContext(ctx),
+		Headers: domain.RequestHeadersFromContext(ctx),
+	}
+
+	var output requestOutput
+	if err := h.plugin.call(ctx, exportOnRequest, input, &output); err != nil {
+		return fmt.Errorf("plugin %s: %w", h.plugin.name, err)
+	}
+
+	if output.Reject != "" {
+		return fmt.Errorf("%w: %s", domain.ErrRequestRejected, output.Reject)
+	}
+	if output.Request != nil {
+		*request = *output.Request
+	}
+	return nil
+}
+
+// responseHook implementa domain.ResponseHook con on_response
+type responseHook struct {
+	plugin *plugin
+}
+
+// AfterResponse implementa domain.ResponseHook
+func (h responseHook) AfterResponse(ctx context.Context, request domain.ChatRequest, response *domain.ChatResponse) {
+	input := responseInput{
+		Request:  request,
+		Response: *response,
+		Tenant:   domain.TenantFromContext(ctx),
+		Headers:  domain.RequestHeadersFromContext(ctx),
+	}
+
+	var output responseOutput
+	if err := h.plugin.call(ctx, exportOnResponse, input, &output); err != nil {
+		log.Printf("⚠️  Plugin %s: %v", h.plugin.name, err)
+		return
+	}
+
+	// Meta no viaja en el JSON (la añade la aplicación): se conserva
+	if output.Response != nil {
+		meta := response.Meta
+		*response = *output.Response
+		response.Meta = meta
+	}
+}
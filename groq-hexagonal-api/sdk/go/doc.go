@@ -0,0 +1,18 @@
+// Package groqclient es el SDK de Go de la API, generado desde api/openapi.yaml
+//
+// El cliente (client.gen.go) no se edita a mano: se regenera con
+//
+//	make sdk-go
+//
+// que ejecuta el go:generate de abajo con la configuración de oapi-codegen.yaml.
+//
+// Uso:
+//
+//	client, err := groqclient.NewClientWithResponses("http://localhost:8080")
+//	message := "Explica qué es Go"
+//	resp, err := client.ChatWithResponse(ctx, nil, groqclient.ChatJSONRequestBody{Message: &message})
+//	fmt.Println(resp.JSON200.Message)
+package groqclient
+
+// La versión de oapi-codegen va fijada para que el código generado sea reproducible
+//go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen@v2.4.1 -config oapi-codegen.yaml ../../api/openapi.yaml
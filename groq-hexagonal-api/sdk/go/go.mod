@@ -0,0 +1,5 @@
+// Módulo independiente del SDK de Go: se publica por separado de la API
+// Sus dependencias (oapi-codegen/runtime) las añade `make sdk-go` con go mod tidy
+module github.com/jamatrain/go-groq-hexagonal/groq-hexagonal-api/sdk/go
+
+go 1.22